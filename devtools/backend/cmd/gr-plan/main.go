@@ -0,0 +1,141 @@
+// Package main はgr-plan CLIのエントリーポイントです。
+// 計画書（*_plan.md）の構造化結果の表示と、確認事項の記法の検査を行います。
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"ghostrunner/backend/internal/plandoc"
+)
+
+const usage = `使い方:
+  gr-plan lint [--json] <plan.md | ディレクトリ>...
+  gr-plan show <plan.md>
+
+サブコマンド:
+  lint  確認事項の記法の問題を「ファイル:行: 種別: メッセージ」で報告する（問題があれば終了コード1）。
+        ディレクトリを渡すと直下の *.md を検査する
+  show  計画書の構造化結果（メタデータ・見出し・確認事項・チェック項目・受け入れ条件）を JSON で出力する
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "lint":
+		os.Exit(runLint(os.Args[2:]))
+	case "show":
+		runShow(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// fileIssues は1ファイルの検査結果です（--json の出力単位）
+type fileIssues struct {
+	Path   string          `json:"path"`
+	Issues []plandoc.Issue `json:"issues"`
+}
+
+// runLint は lint サブコマンドを実行し、終了コードを返します
+func runLint(args []string) int {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "結果を JSON で出力する")
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	_ = fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	paths, err := expandPaths(fs.Args())
+	if err != nil {
+		log.Printf("[gr-plan] %v", err)
+		return 2
+	}
+
+	results := []fileIssues{}
+	found := 0
+	for _, path := range paths {
+		doc, err := plandoc.ParseFile(path)
+		if err != nil {
+			log.Printf("[gr-plan] %v", err)
+			return 2
+		}
+		issues := plandoc.Lint(doc)
+		found += len(issues)
+		results = append(results, fileIssues{Path: path, Issues: issues})
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			log.Printf("[gr-plan] 書き出しに失敗: %v", err)
+			return 2
+		}
+	} else {
+		for _, r := range results {
+			for _, is := range r.Issues {
+				fmt.Printf("%s:%s\n", r.Path, is)
+			}
+		}
+	}
+
+	if found > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runShow は show サブコマンドを実行します
+func runShow(args []string) {
+	if len(args) != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	doc, err := plandoc.ParseFile(args[0])
+	if err != nil {
+		log.Fatalf("[gr-plan] %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		log.Fatalf("[gr-plan] 書き出しに失敗: %v", err)
+	}
+}
+
+// expandPaths はディレクトリを直下の *.md（名前順）に展開します
+func expandPaths(args []string) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", arg, err)
+		}
+		if !info.IsDir() {
+			paths = append(paths, arg)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(arg, "*.md"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to glob %s: %w", arg, err)
+		}
+		sort.Strings(matches)
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
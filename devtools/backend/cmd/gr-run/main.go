@@ -7,17 +7,24 @@ import (
 	"flag"
 	"log"
 	"os"
+	"os/exec"
 	"path/filepath"
 
+	"ghostrunner/backend/internal/config"
 	"ghostrunner/backend/internal/grrun"
-	"ghostrunner/backend/internal/service"
+	"ghostrunner/backend/internal/notify"
 )
 
 func main() {
 	var (
-		project  = flag.String("project", "", "対象プロジェクトの絶対パス（必須）")
-		task     = flag.String("task", "", "タスクファイル名（必須）")
-		locksDir = flag.String("locks-dir", "", "ロックファイルの格納ディレクトリ（デフォルト: ~/.ghostrunner/locks）")
+		project      = flag.String("project", "", "対象プロジェクトの絶対パス（必須）")
+		task         = flag.String("task", "", "タスクファイル名（必須）")
+		stateDir     = flag.String("state-dir", "", "状態の格納先（デフォルト: サーバーと同じ。GHOSTRUNNER_STATE_DIR・設定ファイルの stateDir・~/.ghostrunner の順）")
+		configFile   = flag.String("config", "", "サーバーの設定ファイル（デフォルト: GHOSTRUNNER_CONFIG または <state-dir>/config.yaml）")
+		locksDir     = flag.String("locks-dir", "", "ロックファイルの格納ディレクトリ（デフォルト: <state-dir>/locks）")
+		runsDir      = flag.String("runs-dir", "", "実行履歴の格納ディレクトリ（デフォルト: <state-dir>/runs）")
+		notifyConfig = flag.String("notify-config", "", "通知設定ファイル（デフォルト: <state-dir>/notify.yaml。無ければ NTFY_TOPIC 等の環境変数）")
+		resume       = flag.Bool("resume", false, "実行中のタスクを前回のClaudeセッションで再開する（確認事項への回答後）")
 	)
 	flag.Parse()
 
@@ -25,33 +32,64 @@ func main() {
 		log.Fatal("[gr-run] --project と --task は必須です")
 	}
 
-	// ロックディレクトリのデフォルト値を解決
-	if *locksDir == "" {
+	// ロック・実行履歴・通知設定のデフォルト値をサーバーと同じ状態ディレクトリから解決
+	if *locksDir == "" || *runsDir == "" || *notifyConfig == "" {
 		home, err := os.UserHomeDir()
 		if err != nil {
 			log.Fatalf("[gr-run] ホームディレクトリの取得に失敗: %v", err)
 		}
-		*locksDir = filepath.Join(home, ".ghostrunner", "locks")
+		wd, err := os.Getwd()
+		if err != nil {
+			log.Fatalf("[gr-run] 作業ディレクトリの取得に失敗: %v", err)
+		}
+		dir, err := config.ResolveStateDir(*stateDir, *configFile, os.Getenv, home, wd)
+		if err != nil {
+			log.Fatalf("[gr-run] 状態ディレクトリの解決に失敗: %v", err)
+		}
+		if *locksDir == "" {
+			*locksDir = filepath.Join(dir, "locks")
+		}
+		if *runsDir == "" {
+			*runsDir = filepath.Join(dir, "runs")
+		}
+		if *notifyConfig == "" {
+			*notifyConfig = filepath.Join(dir, notify.DefaultConfigFile)
+		}
 	}
 
 	cfg := grrun.Config{
 		ProjectPath: *project,
 		TaskFile:    *task,
 		LocksDir:    *locksDir,
+		RunsDir:     *runsDir,
 	}
 
-	// 通知サービスの初期化（NTFY_TOPIC未設定時はnil）
+	// 通知の初期化（チャネル未設定時はnil）。終了前に Stop で未配送の通知を送り切る
+	nc, err := notify.LoadConfig(*notifyConfig, os.Getenv, exec.LookPath)
+	if err != nil {
+		log.Fatalf("[gr-run] 通知設定が不正です: %v", err)
+	}
+	registry, err := notify.NewRegistry(nc)
+	if err != nil {
+		log.Fatalf("[gr-run] 通知の初期化に失敗: %v", err)
+	}
 	var notifier grrun.Notifier
-	ntfySvc := service.NewNtfyService()
-	if ntfySvc != nil {
-		notifier = ntfySvc
+	if registry.Len() > 0 {
+		notifier = registry
 	}
+	registry.Start(context.Background())
 
 	executor := grrun.DefaultExecutor()
 	runner := grrun.NewRunner(cfg, notifier, executor)
-	result := runner.Run(context.Background())
+	var result grrun.RunResult
+	if *resume {
+		result = runner.Resume(context.Background())
+	} else {
+		result = runner.Run(context.Background())
+	}
 
 	log.Printf("[gr-run] result: outcome=%s, message=%s", result.Outcome, result.Message)
+	registry.Stop()
 
 	switch result.Outcome {
 	case grrun.OutcomeAbnormal:
@@ -0,0 +1,89 @@
+// Package main はgr-transcript CLIのエントリーポイントです。
+// Claude Code の会話ログを共有用の Markdown / HTML / JSON に書き出します。
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"ghostrunner/backend/internal/export"
+	"ghostrunner/backend/internal/projects"
+)
+
+const usage = `使い方:
+  gr-transcript export [--format md|html|json] [--out FILE] [--projects FILE] <session-id | path/to/session.jsonl>
+
+サブコマンド:
+  export  会話ログ1セッションを秘匿値を伏せて書き出す（--out 省略時は標準出力）
+`
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "export" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	var (
+		format       = fs.String("format", "md", "出力形式（md / html / json）")
+		out          = fs.String("out", "", "出力先ファイル（省略時は標準出力）")
+		projectsFile = fs.String("projects", "", "帰属判定に使う patrol_projects.json（省略時は会話ログの cwd をそのまま使う）")
+	)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	_ = fs.Parse(os.Args[2:])
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	f, err := export.ParseFormat(*format)
+	if err != nil {
+		log.Fatalf("[gr-transcript] %v", err)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		log.Fatalf("[gr-transcript] ホームディレクトリの取得に失敗: %v", err)
+	}
+
+	var provider func() ([]projects.Project, error)
+	if *projectsFile != "" {
+		provider = func() ([]projects.Project, error) {
+			return projects.LoadProjects(*projectsFile)
+		}
+	}
+
+	// CLI はローカル利用のため未登録プロジェクトでもタスクファイルを埋め込む
+	exporter := export.NewExporter(home, provider, time.Now, export.EnvSecrets(), true)
+	t, err := exporter.Build(fs.Arg(0))
+	if err != nil {
+		log.Fatalf("[gr-transcript] エクスポートに失敗: %v", err)
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("[gr-transcript] 出力先の作成に失敗: %v", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	bw := bufio.NewWriter(w)
+	if err := export.Render(bw, f, t); err != nil {
+		log.Fatalf("[gr-transcript] 書き出しに失敗: %v", err)
+	}
+	if err := bw.Flush(); err != nil {
+		log.Fatalf("[gr-transcript] 書き出しに失敗: %v", err)
+	}
+
+	if *out != "" {
+		log.Printf("[gr-transcript] exported: session=%s, format=%s, out=%s, messages=%d", t.SessionID, f, *out, len(t.Messages))
+	}
+}
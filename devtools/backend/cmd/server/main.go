@@ -5,15 +5,31 @@ import (
 	"context"
 	"log"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"time"
 
+	"ghostrunner/backend/internal/analytics"
+	"ghostrunner/backend/internal/auth"
+	"ghostrunner/backend/internal/autoanswer"
+	"ghostrunner/backend/internal/callback"
+	"ghostrunner/backend/internal/config"
 	"ghostrunner/backend/internal/dashboard"
+	"ghostrunner/backend/internal/events"
+	"ghostrunner/backend/internal/export"
+	"ghostrunner/backend/internal/gitinfo"
+	"ghostrunner/backend/internal/grrun"
 	"ghostrunner/backend/internal/handler"
+	"ghostrunner/backend/internal/history"
 	"ghostrunner/backend/internal/idle"
+	"ghostrunner/backend/internal/metrics"
+	"ghostrunner/backend/internal/notify"
 	"ghostrunner/backend/internal/projects"
+	"ghostrunner/backend/internal/report"
+	"ghostrunner/backend/internal/search"
 	"ghostrunner/backend/internal/service"
+	"ghostrunner/backend/internal/tasks"
 	"ghostrunner/backend/internal/transcript"
 	"ghostrunner/backend/internal/tts"
 
@@ -25,36 +41,111 @@ import (
 func main() {
 	log.Println("[Server] Starting Ghostrunner API server...")
 
+	// ホームディレクトリ解決（会話ログ・要約キャッシュの場所と設定の "~" 展開で共用）
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		log.Fatalf("[Server] Failed to get home directory: %v", err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		log.Fatalf("[Server] Failed to get working directory: %v", err)
+	}
+
+	// サーバー設定（設定ファイル < 環境変数 < 引数。起動時に検証し GET /api/config で公開）
+	cfg, err := config.Load(os.Args[1:], os.Getenv, homeDir, wd)
+	if err != nil {
+		log.Fatalf("[Server] Invalid configuration: %v", err)
+	}
+	log.Printf("[Server] Config loaded: root=%s, listen=%s, projectBaseDir=%s, stateDir=%s, file=%s",
+		cfg.Root, cfg.Listen, cfg.ProjectBaseDir, cfg.StateDir, cfg.File)
+	configHandler := handler.NewConfigHandler(cfg)
+
 	// 依存性の組み立て
-	ntfyService := service.NewNtfyService() // nil の場合がある（NTFY_TOPIC 未設定時）
+	// 通知（<stateDir>/notify.yaml のチャネルとルート。無ければ NTFY_TOPIC 等の環境変数から組み立てる）
+	notifyConfig, err := notify.LoadConfig(cfg.StatePath(notify.DefaultConfigFile), os.Getenv, exec.LookPath)
+	if err != nil {
+		log.Fatalf("[Server] Invalid notify config: %v", err)
+	}
+	notifyRegistry, err := notify.NewRegistry(notifyConfig)
+	if err != nil {
+		log.Fatalf("[Server] Failed to create notifier: %v", err)
+	}
+	// 通知ルール（静音時間・重複抑制・送信数の上限。rules がある場合のみ発生元と配送の間に挟む）
+	var notifyRules *notify.RuleEngine
+	var ntfyService service.NtfyService // nil の場合がある（通知チャネル未設定時）
+	if notifyRegistry.Len() > 0 {
+		ntfyService = notifyRegistry
+		if notifyConfig.Rules.Enabled() {
+			notifyRules = notify.NewRuleEngine(notifyConfig.Rules, notifyRegistry, time.Now)
+			ntfyService = notifyRules
+		}
+	} else {
+		log.Println("[Server] No notification channels configured (notify.yaml / NTFY_TOPIC)")
+	}
 	claudeService := service.NewClaudeService(ntfyService)
 	geminiService := service.NewGeminiService() // nil の場合がある（API キー未設定時）
 	openaiService := service.NewOpenAIService() // nil の場合がある（API キー未設定時）
-	// Ghostrunnerリポジトリルートを取得（devtools/backend/cmd/server/main.go から4階層上）
-	_, thisFile, _, _ := runtime.Caller(0)
-	ghostrunnerRoot := filepath.Join(filepath.Dir(thisFile), "..", "..", "..", "..")
+	// Ghostrunnerリポジトリルート（テンプレート・コマンド定義・自プロジェクトの判定に使用）
+	ghostrunnerRoot := cfg.Root
 
 	// patrol_projects.json パス（複数ハンドラ/サービスで共用）
-	patrolConfigPath := filepath.Join(ghostrunnerRoot, "devtools", "backend", "patrol_projects.json")
+	patrolConfigPath := cfg.PatrolProjects
 
-	planHandler := handler.NewPlanHandler(claudeService)
-	commandHandler := handler.NewCommandHandler(claudeService, ghostrunnerRoot)
+	// サーバー内イベントバス（実行・巡回・ダッシュボード・タスク・TTS のイベントを /api/bus/events へ集約）
+	eventBus := events.NewBus(time.Now)
+	eventsHandler := handler.NewEventsHandler(eventBus, cfg.AllowOrigin)
+
+	planHandler := handler.NewPlanHandler(claudeService, eventBus)
+	commandHandler := handler.NewCommandHandler(claudeService, ghostrunnerRoot, eventBus)
 	geminiHandler := handler.NewGeminiHandler(geminiService)
 	openaiHandler := handler.NewOpenAIHandler(openaiService)
 	filesHandler := handler.NewFilesHandler()
-	projectsHandler := handler.NewProjectsHandler(patrolConfigPath)
+	projectsHandler := handler.NewProjectsHandler(patrolConfigPath, cfg.ProjectBaseDir)
 	healthHandler := handler.NewHealthHandler()
 
-	// 巡回サービスの依存性組み立て
-	patrolService := service.NewPatrolService(claudeService, ntfyService, patrolConfigPath)
-	patrolHandler := handler.NewPatrolHandler(patrolService)
+	// 端末認証（ペアリングとスコープ付きトークン。既定は無効で、features.auth=true / AUTH_DISABLED=false で有効）
+	authConfig := cfg.AuthConfig()
+	authService, err := auth.NewService(authConfig.DevicesPath, time.Now)
+	if err != nil {
+		log.Fatalf("[Server] Failed to load devices: %v", err)
+	}
+	authHandler := handler.NewAuthHandler(authService, authConfig)
+	if authConfig.Disabled {
+		log.Println("[Server] Auth disabled (features.auth=false)")
+		if !cfg.ListensOnLoopback() {
+			// 認証なしで外部から届くアドレスに出すと、コマンド実行やプロジェクト削除を誰でも呼べる
+			log.Printf("[Server] WARNING: auth is disabled but listening on non-loopback address %s; "+
+				"anyone who can reach it can run commands and destroy projects. "+
+				"Enable features.auth (AUTH_DISABLED=false) or listen on 127.0.0.1", cfg.Listen)
+		}
+	}
 
-	// ホームディレクトリ解決（質問待ちマーカー・プロジェクト生成で共用）
-	homeDir, err := os.UserHomeDir()
+	// 質問の自動回答（プロジェクトの .ghostrunner/auto_answer.yaml のルールで巡回の質問に回答し、
+	// 自動回答・エスカレーションを <stateDir>/auto_answers.jsonl に記録する）
+	var autoAnswerNotifier autoanswer.Notifier
+	if ntfyService != nil {
+		autoAnswerNotifier = ntfyService
+	}
+	autoAnswerAudit := autoanswer.NewAuditLog(cfg.StatePath("auto_answers.jsonl"))
+	autoAnswerEngine := autoanswer.NewEngine(autoAnswerAudit, autoAnswerNotifier, time.Now)
+	autoAnswerHandler := handler.NewAutoAnswerHandler(autoAnswerAudit)
+
+	// 通知の回答ボタン（publicURL がある場合のみ。選択肢ごとに署名付き・単回使用のコールバック URL を付ける）
+	callbackService, err := callback.NewService(cfg.PublicURL, nil, callback.DefaultTTL, time.Now)
 	if err != nil {
-		log.Fatalf("[Server] Failed to get home directory: %v", err)
+		log.Fatalf("[Server] Failed to create callback service: %v", err)
+	}
+	patrolOpts := []service.PatrolOption{service.WithAutoAnswer(autoAnswerEngine), service.WithEventBus(eventBus)}
+	if cfg.PublicURL != "" {
+		patrolOpts = append(patrolOpts, service.WithAnswerActions(callbackService))
+		log.Printf("[Server] Notification answer buttons enabled: publicURL=%s", cfg.PublicURL)
 	}
 
+	// 巡回サービスの依存性組み立て
+	patrolService := service.NewPatrolService(claudeService, ntfyService, patrolConfigPath, patrolOpts...)
+	patrolHandler := handler.NewPatrolHandler(patrolService)
+	callbackHandler := handler.NewCallbackHandler(callbackService, patrolService)
+
 	// 要約キャッシュの格納先（~/.claude/gr-idle-summaries）。transcript 方式のセッションには
 	// .idle マーカーが無いため、要約は独立キャッシュに保存し reader の List が読み戻す。
 	summaryCacheDir := filepath.Join(homeDir, ".claude", "gr-idle-summaries")
@@ -65,140 +156,320 @@ func main() {
 	// ダッシュボードサービスの依存性組み立て（質問待ちを会話ログ直読みで検出）。
 	// フックのマーカー方式は環境（VS Code 拡張）で AskUserQuestion を取りこぼすため、
 	// 各セッションの会話ログ JSONL を直読みする transcriptReader を idle.Reader として注入する。
-	idleReader := transcript.NewReader(homeDir, func() ([]projects.Project, error) {
+	projectsProvider := func() ([]projects.Project, error) {
 		return projects.LoadProjects(patrolConfigPath)
-	}, time.Now, summaryCacheDir)
-	dashboardService := dashboard.NewService(patrolConfigPath, ghostrunnerRoot, idleReader)
+	}
+	idleReader := transcript.NewReader(homeDir, projectsProvider, time.Now, summaryCacheDir)
+	// 停滞監視（動作中セッションを追跡し、会話ログが伸びない・同じツール呼び出しの連続失敗を検出して通知）
+	watchdog := dashboard.NewWatchdog(idleReader, ntfyService, dashboard.WatchdogConfig{
+		NoProgressWindow: cfg.Watchdog.StuckWindow(),
+		RepeatThreshold:  cfg.Watchdog.RepeatThreshold,
+	}, time.Now)
+	// 運用アラート（各プロジェクトの 運用/ops.yaml の条件を評価し、発火・解除の遷移を通知）
+	opsMonitor := dashboard.NewOpsMonitor(projectsProvider, ntfyService, time.Now)
+	// 未回答の質問の再通知（rules.escalation がある場合のみ。質問待ちが after を超えたら優先度を上げて1回再通知）
+	var escalator *dashboard.Escalator
+	if after := notifyConfig.Rules.EscalateAfter(); after > 0 && ntfyService != nil {
+		escalator = dashboard.NewEscalator(idleReader, ntfyService, after, time.Now)
+	}
+	// 要約プロバイダーは summarizer.providers の順に試し、エラーなら次へフォールバックする（既定 claude → extractive）。
+	summarizeProviders := newSummarizeProviders(cfg.Summarizer)
+	// git 状態（.git/index 等の mtime でキャッシュし、2秒間隔のストリームスキャンでも git を毎回起動しない）
+	gitInspector := gitinfo.NewInspector(time.Now)
+	dashboardOpts := []dashboard.Option{
+		dashboard.WithWatchdog(watchdog), dashboard.WithGitInspector(gitInspector),
+		dashboard.WithRunsDir(cfg.StatePath("runs")), dashboard.WithAutoAnswer(autoAnswerEngine),
+	}
+	// 動作中セッションの進捗要約（summarizer.progressInterval ごとに会話ログの差分を要約。0 なら行わない）。
+	// 要約は <要約キャッシュ>/progress に sessionID と会話ログの位置を key に保存し、再起動後も続きから再開する。
+	var progressSummarizer *dashboard.ProgressSummarizer
+	if every := cfg.Summarizer.ProgressEvery(); every > 0 {
+		progressSummarizer = dashboard.NewProgressSummarizer(idleReader, summarizeProviders,
+			filepath.Join(summaryCacheDir, "progress"), every, time.Now, dashboard.WithProgressNotifier(ntfyService))
+		dashboardOpts = append(dashboardOpts, dashboard.WithProgress(progressSummarizer))
+	}
+	dashboardService := dashboard.NewService(patrolConfigPath, ghostrunnerRoot, idleReader, dashboardOpts...)
+
+	// ダッシュボード状態の時系列（スキャンごとの状態を <stateDir>/history に日次 JSONL で記録）
+	historyStore := history.NewStore(cfg.StatePath("history"), time.Now)
+	dashboardHistoryHandler := handler.NewDashboardHistoryHandler(historyStore)
 
 	// ダッシュボード状態のSSE配信サービス
-	dashboardStream := dashboard.NewStreamService(dashboardService)
+	dashboardStream := dashboard.NewStreamService(dashboardService, dashboard.WithRecorder(historyStore), dashboard.WithEventBus(eventBus))
 	dashboardHandler := handler.NewDashboardHandler(dashboardService, dashboardStream)
 
-	// 質問待ちの要約ジョブ（滞留セッションを haiku で1行要約し要約キャッシュへ書き戻す）。
+	// 質問待ちの要約ジョブ（滞留セッションを要約プロバイダーで1行要約し要約キャッシュへ書き戻す）。
 	// W3 解除: Phase B で要約キャッシュ writer（summaryCacheWriter）を注入して起動する。
 	// reader は transcriptReader（idleReader と同一構成）を共有し、Summarizer は
 	// m.Timestamp（entry-time）を expectedTimestamp として WriteSummary する既存挙動のまま。
 	// key の timestamp=entry-time（C1）で CAS が担保され、待機が変われば別 key となり churn しない。
-	summarizeService := service.NewSummarizeService()
 	summaryWriter := idle.NewSummaryCacheWriter(summaryCacheDir)
-	summarizer := dashboard.NewSummarizer(idleReader, summaryWriter, summarizeService, time.Now)
+	summarizer := dashboard.NewSummarizer(idleReader, summaryWriter, summarizeProviders[0], time.Now,
+		dashboard.WithFallbacks(summarizeProviders[1:]...))
 
 	// バックグラウンドジョブを起動（サーバー稼働中は動き続ける）。
 	bgCtx := context.Background()
+	notifyRegistry.Start(bgCtx)
+	if notifyRules != nil {
+		notifyRules.Start(bgCtx)
+	}
+	if escalator != nil {
+		escalator.Start(bgCtx)
+	}
 	summarizer.Start(bgCtx)
+	if progressSummarizer != nil {
+		progressSummarizer.Start(bgCtx)
+	}
 	dashboardStream.Start(bgCtx)
+	watchdog.Start(bgCtx)
+	opsMonitor.Start(bgCtx)
+
+	// セッション分析（会話ログ全読みによるトークン・ツール・レイテンシ集計）
+	analyticsService := analytics.NewService(homeDir, projectsProvider, time.Now)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService)
+
+	// 全文検索（会話ログ + 開発ドキュメントの転置索引。<stateDir>/index に永続化）
+	searchIndexDir := cfg.StatePath("index")
+	searchService := search.NewService(searchIndexDir, homeDir, projectsProvider, handler.DevFolders, time.Now)
+	searchHandler := handler.NewSearchHandler(searchService)
+	searchService.Start(bgCtx)
+
+	// 会話ログのエクスポート（.env 値とサーバー環境変数の秘匿値を伏せる）
+	exporter := export.NewExporter(homeDir, projectsProvider, time.Now, export.EnvSecrets(), false)
+	sessionsHandler := handler.NewSessionsHandler(exporter)
+
+	// カンバンのタスク操作（開発/実装 配下の .md を登録済みプロジェクトに限って作成・更新・移動）
+	tasksService := tasks.NewService(projectsProvider, time.Now, tasks.WithPublisher(eventBus))
+	tasksHandler := handler.NewTasksHandler(tasksService)
+
+	// 回答待ちタスクの自動再開（features.autoResume / AUTO_RESUME=true で有効。gr-run が waiting_answer で終えたタスクの
+	// 確認事項がすべて回答されたら、記録したセッションを --resume で再開する）
+	var resumeNotifier grrun.Notifier
+	if ntfyService != nil {
+		resumeNotifier = ntfyService
+	}
+	resumerConfig := grrun.ResumerConfig{
+		Enabled:     cfg.Features.AutoResume,
+		SettleDelay: cfg.Resume.SettleDelay(),
+		MaxAttempts: cfg.Resume.MaxAttempts,
+		LocksDir:    cfg.StatePath("locks"),
+		RunsDir:     cfg.StatePath("runs"),
+	}
+	resumer := grrun.NewResumer(resumerConfig, projectsProvider,
+		grrun.DefaultLauncher(resumeNotifier), time.Now)
+	resumer.Start(bgCtx)
 
-	// TTS (VOICEVOX) の依存性組み立て
-	ttsService := tts.NewService()
-	ttsHandler := tts.NewHandler(ttsService)
+	// 活動ダイジェスト（完了タスク・要確認の実行・質問と回答・トークン使用量・停滞した運用を日次・週次でまとめる）。
+	// digest.daily / digest.weekly の時刻に通知し、digest.writeDocs なら各プロジェクトの 開発/資料 にも書き出す
+	digestGenerator := report.NewGenerator(projectsProvider, resumerConfig.RunsDir, historyStore, analyticsService, time.Now)
+	reportHandler := handler.NewReportHandler(digestGenerator)
+	var digestSchedules []report.Schedule
+	if cfg.Digest.Daily != "" {
+		digestSchedules = append(digestSchedules, report.Schedule{Period: report.PeriodDay, Clock: cfg.Digest.Daily})
+	}
+	if day, clock, ok := config.ParseWeekly(cfg.Digest.Weekly); ok {
+		digestSchedules = append(digestSchedules, report.Schedule{Period: report.PeriodWeek, Clock: clock, Weekly: true, Weekday: day})
+	}
+	if len(digestSchedules) > 0 {
+		var digestOpts []report.SchedulerOption
+		if cfg.Digest.WriteDocs {
+			digestOpts = append(digestOpts, report.WithWriteDocs())
+		}
+		report.NewScheduler(digestGenerator, ntfyService, digestSchedules, time.Now, digestOpts...).Start(bgCtx)
+	}
+
+	// TTS (VOICEVOX) の依存性組み立て（メモリ LRU の下に <stateDir>/tts-cache のディスクキャッシュを重ね、再起動後も再合成しない）
+	ttsService := tts.NewService(tts.WithPublisher(eventBus), tts.WithDiskCache(cfg.StatePath("tts-cache")))
+	// 端末ごとの声質プリセットは <stateDir>/tts_presets.json に保存する
+	ttsPresets, err := tts.NewPresetStore(cfg.StatePath("tts_presets.json"))
+	if err != nil {
+		log.Fatalf("[Server] Failed to load tts presets: %v", err)
+	}
+	ttsHandler := tts.NewHandler(ttsService, tts.WithPresets(ttsPresets, handler.DeviceID))
 
 	// プロジェクト生成関連の依存性組み立て
 	templateService := service.NewTemplateService(ghostrunnerRoot)
-	createService := service.NewCreateService(templateService, homeDir)
+	createService := service.NewCreateService(templateService, cfg.ProjectBaseDir)
 	createHandler := handler.NewCreateHandler(createService)
 
 	// Ginエンジン初期化
 	r := gin.Default()
 
 	// pprof エンドポイント (メモリリーク調査用)
-	// ENABLE_PPROF=1 のときだけ有効化。本番事故防止のため明示 opt-in。
+	// features.pprof（ENABLE_PPROF=1 / --pprof）のときだけ有効化。本番事故防止のため明示 opt-in。
 	// 取得例:
 	//   go tool pprof http://localhost:8888/debug/pprof/heap
 	//   curl -s http://localhost:8888/debug/pprof/heap > heap.pprof
-	if os.Getenv("ENABLE_PPROF") == "1" {
+	if cfg.Features.Pprof {
 		ginpprof.Register(r)
 		log.Println("[Server] pprof enabled at /debug/pprof/")
 	}
 
+	// 運用メトリクス（Prometheus テキスト形式。ローカルの Prometheus / Grafana からスクレイプする）
+	startedAt := time.Now()
+	metrics.Default.GaugeFunc("ghostrunner_goroutines", "Number of goroutines.", func() float64 {
+		return float64(runtime.NumGoroutine())
+	})
+	metrics.Default.GaugeFunc("ghostrunner_uptime_seconds", "Seconds since the server started.", func() float64 {
+		return time.Since(startedAt).Seconds()
+	})
+	metricsHandler := handler.NewMetricsHandler(metrics.Default)
+
 	// CORS設定（ローカル開発時およびTailscale経由のアクセスを許可）
 	r.Use(cors.New(cors.Config{
-		AllowOriginFunc: func(origin string) bool {
-			// localhost を許可（3000: プロジェクト用, 3333: devtools用）
-			if origin == "http://localhost:3000" || origin == "http://localhost:3333" {
-				return true
-			}
-			// Tailscale IP (100.x.x.x) を許可
-			if len(origin) > 11 && origin[:11] == "http://100." {
-				return true
-			}
-			// Tailscale Funnel ドメイン (*.ts.net) を許可
-			if len(origin) > 7 && origin[len(origin)-7:] == ".ts.net" {
-				return true
-			}
-			return false
-		},
+		AllowOriginFunc:  cfg.AllowOrigin,
 		AllowMethods:     []string{"GET", "POST", "OPTIONS"},
-		AllowHeaders:     []string{"Content-Type"},
+		AllowHeaders:     []string{"Content-Type", "If-Match", "Authorization"},
+		ExposeHeaders:    []string{"ETag"},
 		AllowCredentials: true,
 	}))
 
-	// APIルーティング
-	api := r.Group("/api")
+	// 認証不要のAPI（ヘルスチェック、ペアリングの成立、署名付きトークンで認可する通知のコールバック）
+	public := r.Group("/api")
 	{
-		// ヘルスチェックAPI
-		api.GET("/health", healthHandler.Handle)
+		public.GET("/health", healthHandler.Handle)
+		public.POST("/auth/pair", authHandler.HandlePair)
+		public.POST("/callbacks/:token", callbackHandler.Handle)
+	}
+
+	// APIルーティング（既定は read スコープ。実行・変更は execute、削除と端末管理は destroy）
+	execute := authHandler.Require(auth.ScopeExecute)
+	destroy := authHandler.Require(auth.ScopeDestroy)
+	// 運用メトリクスは設定や実行状況が読み取れるため /api と同じく read スコープを要求する（ホスト本人のスクレイプはトークン不要）
+	r.GET("/metrics", authHandler.Require(auth.ScopeRead), metricsHandler.Handle)
+	api := r.Group("/api", authHandler.Require(auth.ScopeRead))
+	{
+		// サーバー設定API
+		api.GET("/config", configHandler.Handle)
+
+		// 端末認証API
+		api.POST("/auth/pairing", authHandler.HandleStartPairing)
+		api.GET("/auth/me", authHandler.HandleMe)
+		api.GET("/auth/devices", destroy, authHandler.HandleDevices)
+		api.POST("/auth/devices/revoke", destroy, authHandler.HandleRevoke)
 
 		// ファイル一覧API
 		api.GET("/files", filesHandler.Handle)
 
 		// プロジェクト一覧API
 		api.GET("/projects", projectsHandler.Handle)
-		api.POST("/projects/destroy", projectsHandler.HandleDestroy)
+		api.POST("/projects/destroy", destroy, projectsHandler.HandleDestroy)
 
 		// 汎用コマンドAPI（推奨）
-		api.POST("/command", commandHandler.Handle)
-		api.POST("/command/stream", commandHandler.HandleStream)
-		api.POST("/command/continue", commandHandler.HandleContinue)
-		api.POST("/command/continue/stream", commandHandler.HandleContinueStream)
+		api.POST("/command", execute, commandHandler.Handle)
+		api.POST("/command/stream", execute, commandHandler.HandleStream)
+		api.POST("/command/continue", execute, commandHandler.HandleContinue)
+		api.POST("/command/continue/stream", execute, commandHandler.HandleContinueStream)
 
 		// 旧API（互換性維持）
-		api.POST("/plan", planHandler.Handle)
-		api.POST("/plan/stream", planHandler.HandleStream)
-		api.POST("/plan/continue", planHandler.HandleContinue)
-		api.POST("/plan/continue/stream", planHandler.HandleContinueStream)
+		api.POST("/plan", execute, planHandler.Handle)
+		api.POST("/plan/stream", execute, planHandler.HandleStream)
+		api.POST("/plan/continue", execute, planHandler.HandleContinue)
+		api.POST("/plan/continue/stream", execute, planHandler.HandleContinueStream)
 
 		// Gemini API
-		api.POST("/gemini/token", geminiHandler.HandleToken)
+		api.POST("/gemini/token", execute, geminiHandler.HandleToken)
 
 		// OpenAI Realtime API
-		api.POST("/openai/realtime/session", openaiHandler.HandleSession)
+		api.POST("/openai/realtime/session", execute, openaiHandler.HandleSession)
 
 		// プロジェクト生成API
 		api.GET("/projects/validate", createHandler.HandleValidate)
-		api.POST("/projects/create/stream", createHandler.HandleCreateStream)
-		api.POST("/projects/open", createHandler.HandleOpen)
+		api.POST("/projects/create/stream", execute, createHandler.HandleCreateStream)
+		api.POST("/projects/open", execute, createHandler.HandleOpen)
 
 		// TTS API (VOICEVOX)
-		api.POST("/tts", ttsHandler.HandleSynthesize)
+		api.POST("/tts", execute, ttsHandler.HandleSynthesize)
+		api.GET("/tts/cache", ttsHandler.HandleCacheStats)
+		api.GET("/tts/speakers", ttsHandler.HandleSpeakers)
+		api.GET("/tts/preset", ttsHandler.HandleGetPreset)
+		api.POST("/tts/preset", execute, ttsHandler.HandleSetPreset)
 
 		// ダッシュボードAPI
 		dashGroup := api.Group("/dashboard")
 		{
 			dashGroup.GET("/state", dashboardHandler.HandleState)
-			dashGroup.POST("/answer", dashboardHandler.HandleAnswer)
+			dashGroup.POST("/answer", execute, dashboardHandler.HandleAnswer)
+			dashGroup.POST("/answers", execute, dashboardHandler.HandleAnswers)
+			dashGroup.GET("/answers/history", dashboardHandler.HandleAnswerHistory)
 			dashGroup.GET("/stream", dashboardHandler.HandleStream)
+			dashGroup.GET("/history", dashboardHistoryHandler.Handle)
 		}
 
+		// タスク管理API（カンバン）
+		api.GET("/tasks", tasksHandler.HandleList)
+		api.GET("/tasks/templates", tasksHandler.HandleTemplates)
+		api.GET("/tasks/content", tasksHandler.HandleGet)
+		api.POST("/tasks/create", execute, tasksHandler.HandleCreate)
+		api.POST("/tasks/update", execute, tasksHandler.HandleUpdate)
+		api.POST("/tasks/move", execute, tasksHandler.HandleMove)
+		api.POST("/tasks/reorder", execute, tasksHandler.HandleReorder)
+		api.POST("/tasks/delete", execute, tasksHandler.HandleDelete)
+
+		// セッション分析API
+		api.GET("/analytics", analyticsHandler.Handle)
+
+		// 活動ダイジェストAPI
+		api.GET("/reports/digest", reportHandler.HandleDigest)
+
+		// 全文検索API
+		api.GET("/search", searchHandler.Handle)
+
+		// セッションエクスポートAPI
+		api.GET("/exports/sessions/:id", sessionsHandler.HandleExport)
+
+		// 自動回答の監査API
+		api.GET("/auto-answers", autoAnswerHandler.Handle)
+
+		// イベント購読API（SSE / WebSocket）
+		api.GET("/bus/events", eventsHandler.HandleStream)
+		api.GET("/bus/events/ws", eventsHandler.HandleWebSocket)
+
 		// 巡回API
 		patrol := api.Group("/patrol")
 		{
-			patrol.POST("/projects", patrolHandler.HandleRegister)
-			patrol.POST("/projects/remove", patrolHandler.HandleRemove)
+			patrol.POST("/projects", execute, patrolHandler.HandleRegister)
+			patrol.POST("/projects/remove", execute, patrolHandler.HandleRemove)
 			patrol.GET("/projects", patrolHandler.HandleListProjects)
 			patrol.GET("/scan", patrolHandler.HandleScan)
-			patrol.POST("/start", patrolHandler.HandleStart)
-			patrol.POST("/stop", patrolHandler.HandleStop)
-			patrol.POST("/resume", patrolHandler.HandleResume)
+			patrol.POST("/start", execute, patrolHandler.HandleStart)
+			patrol.POST("/stop", execute, patrolHandler.HandleStop)
+			patrol.POST("/resume", execute, patrolHandler.HandleResume)
 			patrol.GET("/states", patrolHandler.HandleStates)
 			patrol.GET("/stream", patrolHandler.HandleStream)
-			patrol.POST("/polling/start", patrolHandler.HandlePollingStart)
-			patrol.POST("/polling/stop", patrolHandler.HandlePollingStop)
+			patrol.POST("/polling/start", execute, patrolHandler.HandlePollingStart)
+			patrol.POST("/polling/stop", execute, patrolHandler.HandlePollingStop)
 		}
 	}
 
-	// サーバー起動（0.0.0.0で全インターフェースからアクセス可能に）
-	log.Println("[Server] Listening on 0.0.0.0:8888")
-	if err := r.Run("0.0.0.0:8888"); err != nil {
+	// サーバー起動（既定は 0.0.0.0:8888 で全インターフェースからアクセス可能）
+	log.Printf("[Server] Listening on %s", cfg.Listen)
+	if err := r.Run(cfg.Listen); err != nil {
 		log.Fatalf("[Server] Failed to start server: %v", err)
 	}
 }
+
+// newSummarizeProviders は設定の順に要約プロバイダーを生成します。
+// 生成できないもの（API キー未設定など）は警告して除き、1つも残らなければ抽出要約を使います
+func newSummarizeProviders(cfg config.Summarizer) []service.SummarizeService {
+	endpoints := map[string]config.SummarizerEndpoint{
+		service.SummarizeProviderOpenAI: cfg.OpenAI,
+		service.SummarizeProviderGemini: cfg.Gemini,
+		service.SummarizeProviderLocal:  cfg.Local,
+	}
+	var providers []service.SummarizeService
+	for _, name := range cfg.Providers {
+		ep := endpoints[name]
+		p, err := service.NewSummarizeProvider(name, service.SummarizeEndpoint{BaseURL: ep.BaseURL, Model: ep.Model})
+		if err != nil {
+			log.Printf("[Server] summarizer provider skipped: %v", err)
+			continue
+		}
+		providers = append(providers, p)
+	}
+	if len(providers) == 0 {
+		providers = append(providers, service.NewExtractiveSummarizer())
+	}
+	log.Printf("[Server] summarizer providers: %v", cfg.Providers)
+	return providers
+}
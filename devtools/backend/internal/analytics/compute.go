@@ -0,0 +1,160 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"ghostrunner/backend/internal/transcript"
+)
+
+// ComputeSession は会話ログのエントリ列から1セッションの集計値を算出します。
+//
+// 集計規則:
+//   - トークン: assistant message.usage を合算する。Claude Code は1メッセージを content 要素ごとに
+//     複数行へ分割し同じ usage を重複記録するため、message.id で重複排除する
+//   - ツール: tool_use を名前別に数え、対応する tool_result（tool_use_id）の is_error をエラーとする
+//   - ターン: 人間のプロンプト（tool_result 返送・isMeta 以外の user）1件を1ターンとし、
+//     プロンプトから最初の assistant エントリまでをレイテンシとする
+//   - 経過時間: timestamp を持つ最初と最後のエントリの差
+//
+// サイドチェーン（サブエージェント）のエントリもトークン・ツールに計上しますが、ターンには数えません。
+func ComputeSession(sessionID string, entries []transcript.Entry) SessionStats {
+	st := SessionStats{SessionID: sessionID, Stats: newStats()}
+	st.Sessions = 1
+
+	seenMsg := make(map[string]struct{})
+	toolNames := make(map[string]string)
+	models := make(map[string]struct{})
+
+	var pendingPrompt time.Time
+	for _, e := range entries {
+		if !e.Timestamp.IsZero() {
+			if st.StartedAt.IsZero() || e.Timestamp.Before(st.StartedAt) {
+				st.StartedAt = e.Timestamp
+			}
+			if e.Timestamp.After(st.EndedAt) {
+				st.EndedAt = e.Timestamp
+			}
+		}
+		if e.Message == nil {
+			continue
+		}
+
+		switch e.Type {
+		case "assistant":
+			if !e.IsSidechain && !pendingPrompt.IsZero() && !e.Timestamp.IsZero() {
+				ms := e.Timestamp.Sub(pendingPrompt).Milliseconds()
+				if ms >= 0 {
+					st.latencySumMs += ms
+					st.latencyCount++
+					if ms > st.MaxTurnLatencyMs {
+						st.MaxTurnLatencyMs = ms
+					}
+				}
+				pendingPrompt = time.Time{}
+			}
+			if e.Message.Model != "" && e.Message.Model != "<synthetic>" {
+				models[e.Message.Model] = struct{}{}
+			}
+			if u := e.Message.Usage; u != nil && firstSeen(seenMsg, e.Message.ID) {
+				st.Tokens.add(TokenUsage{
+					Input:         u.InputTokens,
+					Output:        u.OutputTokens,
+					CacheCreation: u.CacheCreationInputTokens,
+					CacheRead:     u.CacheReadInputTokens,
+				})
+			}
+			for _, b := range e.Message.Content {
+				if b.Type != "tool_use" || b.Name == "" {
+					continue
+				}
+				ts := st.Tools[b.Name]
+				ts.Calls++
+				st.Tools[b.Name] = ts
+				st.ToolCalls++
+				if b.ID != "" {
+					toolNames[b.ID] = b.Name
+				}
+			}
+		case "user":
+			if e.IsPrompt() {
+				if !e.IsSidechain {
+					st.Turns++
+					pendingPrompt = e.Timestamp
+				}
+				continue
+			}
+			for _, b := range e.Message.Content {
+				if b.Type != "tool_result" || !b.IsError {
+					continue
+				}
+				name, ok := toolNames[b.ToolUseID]
+				if !ok {
+					name = "unknown"
+				}
+				ts := st.Tools[name]
+				ts.Errors++
+				st.Tools[name] = ts
+				st.ToolErrors++
+			}
+		}
+	}
+
+	if !st.StartedAt.IsZero() {
+		st.WallTimeSec = int64(st.EndedAt.Sub(st.StartedAt).Seconds())
+	}
+	for m := range models {
+		st.Models = append(st.Models, m)
+	}
+	sort.Strings(st.Models)
+	st.finalize()
+	return st
+}
+
+// firstSeen は id が初出なら記録して true を返します。id が空の場合は常に true です。
+func firstSeen(seen map[string]struct{}, id string) bool {
+	if id == "" {
+		return true
+	}
+	if _, ok := seen[id]; ok {
+		return false
+	}
+	seen[id] = struct{}{}
+	return true
+}
+
+// bucketStart は t を含むバケットの開始時刻（t のロケーション基準）を返します。
+func bucketStart(t time.Time, b Bucket) time.Time {
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	if b != BucketWeek {
+		return day
+	}
+	// 週頭は月曜（ISO 8601）
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// buildBuckets はセッション群を開始時刻でバケットに振り分け、開始時刻昇順で返します。
+func buildBuckets(sessions []SessionStats, b Bucket, loc *time.Location) []BucketStats {
+	byStart := make(map[time.Time]*BucketStats)
+	for _, s := range sessions {
+		if s.StartedAt.IsZero() {
+			continue
+		}
+		start := bucketStart(s.StartedAt.In(loc), b)
+		bs, ok := byStart[start]
+		if !ok {
+			bs = &BucketStats{Start: start, Label: start.Format("2006-01-02"), Stats: newStats()}
+			byStart[start] = bs
+		}
+		bs.merge(s.Stats)
+	}
+
+	out := make([]BucketStats, 0, len(byStart))
+	for _, bs := range byStart {
+		out = append(out, *bs)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	return out
+}
@@ -0,0 +1,88 @@
+package analytics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/transcript"
+)
+
+func decode(t *testing.T, lines ...string) []transcript.Entry {
+	t.Helper()
+	entries, err := transcript.DecodeEntries(strings.NewReader(strings.Join(lines, "\n")))
+	if err != nil {
+		t.Fatalf("DecodeEntries error: %v", err)
+	}
+	return entries
+}
+
+func TestComputeSession(t *testing.T) {
+	entries := decode(t,
+		`{"type":"user","cwd":"/p","timestamp":"2026-07-01T10:00:00Z","message":{"role":"user","content":"実装して"}}`,
+		// 1メッセージが2行に分割され同じ usage が重複記録される
+		`{"type":"assistant","timestamp":"2026-07-01T10:00:03Z","message":{"id":"m1","role":"assistant","model":"claude-a","content":[{"type":"text","text":"了解"}],"usage":{"input_tokens":100,"output_tokens":20,"cache_creation_input_tokens":5,"cache_read_input_tokens":50}}}`,
+		`{"type":"assistant","timestamp":"2026-07-01T10:00:04Z","message":{"id":"m1","role":"assistant","model":"claude-a","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{}}],"usage":{"input_tokens":100,"output_tokens":20,"cache_creation_input_tokens":5,"cache_read_input_tokens":50}}}`,
+		`{"type":"user","timestamp":"2026-07-01T10:00:05Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","is_error":true,"content":"exit 1"}]}}`,
+		`{"type":"assistant","timestamp":"2026-07-01T10:00:06Z","message":{"id":"m2","role":"assistant","content":[{"type":"tool_use","id":"t2","name":"Bash","input":{}},{"type":"tool_use","id":"t3","name":"Read","input":{}}],"usage":{"input_tokens":10,"output_tokens":2}}}`,
+		`{"type":"user","timestamp":"2026-07-01T10:00:07Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t2","content":"ok"},{"type":"tool_result","tool_use_id":"t3","content":"ok"}]}}`,
+		`{"type":"user","timestamp":"2026-07-01T10:01:00Z","isMeta":true,"message":{"role":"user","content":"<command>"}}`,
+		`{"type":"user","timestamp":"2026-07-01T10:05:00Z","message":{"role":"user","content":"次"}}`,
+		`{"type":"assistant","timestamp":"2026-07-01T10:05:09Z","message":{"id":"m3","role":"assistant","content":[{"type":"text","text":"done"}]}}`,
+	)
+
+	st := ComputeSession("s1", entries)
+
+	if st.Turns != 2 {
+		t.Errorf("Turns = %d, want 2（isMeta と tool_result 返送は数えない）", st.Turns)
+	}
+	want := TokenUsage{Input: 110, Output: 22, CacheCreation: 5, CacheRead: 50}
+	if st.Tokens != want {
+		t.Errorf("Tokens = %+v, want %+v（message.id で重複排除）", st.Tokens, want)
+	}
+	if st.ToolCalls != 3 || st.ToolErrors != 1 {
+		t.Errorf("ToolCalls=%d ToolErrors=%d, want 3/1", st.ToolCalls, st.ToolErrors)
+	}
+	if got := st.Tools["Bash"]; got.Calls != 2 || got.Errors != 1 {
+		t.Errorf("Tools[Bash] = %+v", got)
+	}
+	if got := st.ToolErrorRate; got < 0.333 || got > 0.334 {
+		t.Errorf("ToolErrorRate = %v", got)
+	}
+	if st.AvgTurnLatencyMs != 6000 || st.MaxTurnLatencyMs != 9000 {
+		t.Errorf("latency avg=%d max=%d, want 6000/9000", st.AvgTurnLatencyMs, st.MaxTurnLatencyMs)
+	}
+	if st.WallTimeSec != 309 {
+		t.Errorf("WallTimeSec = %d, want 309", st.WallTimeSec)
+	}
+	if len(st.Models) != 1 || st.Models[0] != "claude-a" {
+		t.Errorf("Models = %v", st.Models)
+	}
+}
+
+func TestBucketStart(t *testing.T) {
+	loc := time.FixedZone("JST", 9*3600)
+	// 2026-07-01 は水曜日
+	ts := time.Date(2026, 7, 1, 15, 30, 0, 0, loc)
+
+	tests := []struct {
+		name   string
+		bucket Bucket
+		want   time.Time
+	}{
+		{"日次は当日0時", BucketDay, time.Date(2026, 7, 1, 0, 0, 0, 0, loc)},
+		{"週次は月曜0時", BucketWeek, time.Date(2026, 6, 29, 0, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bucketStart(ts, tt.bucket); !got.Equal(tt.want) {
+				t.Errorf("bucketStart = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	sunday := time.Date(2026, 7, 5, 23, 0, 0, 0, loc)
+	if got := bucketStart(sunday, BucketWeek); !got.Equal(time.Date(2026, 6, 29, 0, 0, 0, 0, loc)) {
+		t.Errorf("日曜は前の月曜に属する: got %v", got)
+	}
+}
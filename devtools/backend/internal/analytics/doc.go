@@ -0,0 +1,24 @@
+// Package analytics は Claude Code の会話ログからセッション分析（トークン使用量・ツール利用・
+// ツールエラー率・ターンレイテンシ・経過時間）を集計する。
+//
+// # 概要
+//
+// transcript パッケージの待機判定は会話ログ末尾しか読まず、usage やツール結果を捨てている。
+// 本パッケージは会話ログ JSONL を全読み（transcript.ReadEntries）し、セッション単位の集計値を
+// 算出したうえでプロジェクト別・日次/週次バケット別に合算する。GET /api/analytics の実体。
+//
+// # 主要な型・関数
+//
+//   - Service / NewService: 登録プロジェクトの会話ログを列挙・集計する（mtime + size キャッシュ付き）
+//   - ComputeSession: エントリ列から1セッションの集計値を算出する純粋関数
+//   - Query: プロジェクト・バケット粒度・開始時刻範囲の集計条件
+//   - Report / ProjectStats / BucketStats / SessionStats: 集計結果
+//
+// # 設計方針
+//
+//   - 帰属は transcript と同じく実 cwd + idle.MatchProject（C2）。lossy な project-id は列挙の絞り込み専用
+//   - 会話ログは公式非サポート形式のため best-effort。解釈できない行・読めないファイルは skip する
+//   - usage は message.id で重複排除する（1メッセージが content 要素ごとに複数行へ分割記録されるため）
+//   - セッションはバケット・期間フィルタとも開始時刻（最初のエントリの timestamp）で振り分ける
+//   - 再集計コストを抑えるため、会話ログの mtime + size が不変なら前回の集計結果を再利用する
+package analytics
@@ -0,0 +1,228 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"ghostrunner/backend/internal/idle"
+	"ghostrunner/backend/internal/projects"
+	"ghostrunner/backend/internal/transcript"
+)
+
+// ErrValidation は集計条件が不正な場合のエラーです。
+var ErrValidation = errors.New("validation error")
+
+// Service は会話ログからセッション分析を集計するインターフェースです。
+type Service interface {
+	// Report は条件に一致するセッションをプロジェクト別・バケット別に集計して返します。
+	Report(ctx context.Context, q Query) (*Report, error)
+}
+
+// cachedSession は会話ログ1件の集計結果キャッシュです（mtime + size 不変なら再パースしない）。
+type cachedSession struct {
+	modTime time.Time
+	size    int64
+	stats   SessionStats
+	// cwd は会話ログ内で最初に現れた実 cwd です（帰属判定用）。
+	cwd string
+}
+
+// serviceImpl は Service の実装です。
+type serviceImpl struct {
+	homeDir          string
+	projectsProvider func() ([]projects.Project, error)
+	now              func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedSession
+}
+
+// NewService は新しい分析サービスを生成します。
+// projectsProvider は登録プロジェクト一覧の取得関数（transcript.NewReader と同じ注入形）です。
+func NewService(homeDir string, projectsProvider func() ([]projects.Project, error), now func() time.Time) Service {
+	return &serviceImpl{
+		homeDir:          homeDir,
+		projectsProvider: projectsProvider,
+		now:              now,
+		cache:            make(map[string]cachedSession),
+	}
+}
+
+// Report は条件に一致するセッションを集計します。
+func (s *serviceImpl) Report(ctx context.Context, q Query) (*Report, error) {
+	if q.Bucket == "" {
+		q.Bucket = BucketDay
+	}
+	if q.Bucket != BucketDay && q.Bucket != BucketWeek {
+		return nil, fmt.Errorf("%w: bucket must be day or week: %s", ErrValidation, q.Bucket)
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrValidation)
+	}
+
+	projs, err := s.projectsProvider()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load projects: %w", err)
+	}
+	targets := projs
+	if q.ProjectPath != "" {
+		targets = nil
+		clean := filepath.Clean(q.ProjectPath)
+		for _, p := range projs {
+			if filepath.Clean(p.Path) == clean {
+				targets = append(targets, p)
+			}
+		}
+		if len(targets) == 0 {
+			return nil, fmt.Errorf("%w: project is not registered: %s", ErrValidation, q.ProjectPath)
+		}
+	}
+
+	sessions, err := transcript.ListSessions(s.homeDir, targets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	log.Printf("[AnalyticsService] Report started: project=%s, bucket=%s, sessions=%d", q.ProjectPath, q.Bucket, len(sessions))
+
+	byProject := make(map[string][]SessionStats)
+	alive := make(map[string]struct{}, len(sessions))
+	for _, sess := range sessions {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		alive[sess.Path] = struct{}{}
+
+		cs, ok := s.load(sess)
+		if !ok {
+			continue
+		}
+		// C2: 帰属は実 cwd + MatchProject（全登録プロジェクトで最長一致させ兄弟誤帰属を防ぐ）
+		projPath, matched := idle.MatchProject(cs.cwd, projs)
+		if !matched || !containsProject(targets, projPath) {
+			continue
+		}
+		if !inRange(cs.stats.StartedAt, q.From, q.To) {
+			continue
+		}
+		st := cs.stats
+		st.ProjectPath = projPath
+		byProject[projPath] = append(byProject[projPath], st)
+	}
+	s.prune(alive)
+
+	loc := s.now().Location()
+	report := &Report{
+		Bucket:      q.Bucket,
+		Total:       newStats(),
+		Projects:    make([]ProjectStats, 0, len(targets)),
+		GeneratedAt: s.now(),
+	}
+	if !q.From.IsZero() {
+		from := q.From
+		report.From = &from
+	}
+	if !q.To.IsZero() {
+		to := q.To
+		report.To = &to
+	}
+
+	for _, p := range targets {
+		list := byProject[filepath.Clean(p.Path)]
+		sort.Slice(list, func(i, j int) bool { return list[i].StartedAt.After(list[j].StartedAt) })
+
+		ps := ProjectStats{
+			Name:     p.Name,
+			Path:     p.Path,
+			Total:    newStats(),
+			Buckets:  buildBuckets(list, q.Bucket, loc),
+			Sessions: list,
+		}
+		if ps.Sessions == nil {
+			ps.Sessions = []SessionStats{}
+		}
+		for _, st := range list {
+			ps.Total.merge(st.Stats)
+		}
+		report.Total.merge(ps.Total)
+		report.Projects = append(report.Projects, ps)
+	}
+
+	log.Printf("[AnalyticsService] Report completed: projects=%d, sessions=%d", len(report.Projects), report.Total.Sessions)
+	return report, nil
+}
+
+// load はキャッシュ済みの集計結果を返し、無効ならば会話ログを全読みして再集計します。
+// 読み取りに失敗した会話ログは ok=false で skip させます（他セッションの集計を妨げない）。
+func (s *serviceImpl) load(sess transcript.Session) (cachedSession, bool) {
+	s.mu.Lock()
+	cs, hit := s.cache[sess.Path]
+	s.mu.Unlock()
+	if hit && cs.modTime.Equal(sess.ModTime) && cs.size == sess.Size {
+		return cs, true
+	}
+
+	entries, err := transcript.ReadEntries(sess.Path)
+	if err != nil {
+		log.Printf("[AnalyticsService] failed to read session, skipping: path=%s, error=%v", sess.Path, err)
+		return cachedSession{}, false
+	}
+
+	cs = cachedSession{
+		modTime: sess.ModTime,
+		size:    sess.Size,
+		stats:   ComputeSession(sess.ID, entries),
+	}
+	for _, e := range entries {
+		if e.Cwd != "" {
+			cs.cwd = e.Cwd
+			break
+		}
+	}
+
+	s.mu.Lock()
+	s.cache[sess.Path] = cs
+	s.mu.Unlock()
+	return cs, true
+}
+
+// prune は列挙されなくなった会話ログのキャッシュを削除します。
+func (s *serviceImpl) prune(alive map[string]struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for path := range s.cache {
+		if _, ok := alive[path]; !ok {
+			delete(s.cache, path)
+		}
+	}
+}
+
+// containsProject は projs に path（Clean 済み）が含まれるかを返します。
+func containsProject(projs []projects.Project, path string) bool {
+	for _, p := range projs {
+		if filepath.Clean(p.Path) == path {
+			return true
+		}
+	}
+	return false
+}
+
+// inRange は t が [from, to) に含まれるかを返します。ゼロ値の境界は無制限です。
+func inRange(t, from, to time.Time) bool {
+	if t.IsZero() {
+		return from.IsZero() && to.IsZero()
+	}
+	if !from.IsZero() && t.Before(from) {
+		return false
+	}
+	if !to.IsZero() && !t.Before(to) {
+		return false
+	}
+	return true
+}
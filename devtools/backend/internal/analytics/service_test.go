@@ -0,0 +1,99 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/projects"
+)
+
+func writeSession(t *testing.T, home, projID, sessionID string, lines ...string) {
+	t.Helper()
+	dir := filepath.Join(home, ".claude", "projects", projID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, sessionID+".jsonl"), []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func prompt(cwd, ts string) string {
+	return `{"type":"user","cwd":"` + cwd + `","timestamp":"` + ts + `","message":{"role":"user","content":"go"}}`
+}
+
+func reply(ts, id string, out int) string {
+	return `{"type":"assistant","timestamp":"` + ts + `","message":{"id":"` + id + `","role":"assistant","content":[{"type":"text","text":"ok"}],"usage":{"input_tokens":1,"output_tokens":` + strconv.Itoa(out) + `}}}`
+}
+
+func TestServiceReport(t *testing.T) {
+	home := t.TempDir()
+	projs := []projects.Project{
+		{Name: "a", Path: "/work/a"},
+		{Name: "ab", Path: "/work/ab"},
+	}
+	writeSession(t, home, "-work-a", "s1", prompt("/work/a", "2026-06-29T01:00:00Z"), reply("2026-06-29T01:00:02Z", "m1", 10))
+	writeSession(t, home, "-work-a", "s2", prompt("/work/a/sub", "2026-07-02T01:00:00Z"), reply("2026-07-02T01:00:04Z", "m2", 20))
+	// 兄弟ディレクトリ（glob 前方一致で拾われるが MatchProject で ab に帰属する）
+	writeSession(t, home, "-work-ab", "s3", prompt("/work/ab", "2026-07-02T02:00:00Z"), reply("2026-07-02T02:00:01Z", "m3", 40))
+
+	now := func() time.Time { return time.Date(2026, 7, 3, 0, 0, 0, 0, time.UTC) }
+	svc := NewService(home, func() ([]projects.Project, error) { return projs, nil }, now)
+
+	t.Run("プロジェクト別・日次", func(t *testing.T) {
+		r, err := svc.Report(context.Background(), Query{ProjectPath: "/work/a"})
+		if err != nil {
+			t.Fatalf("Report error: %v", err)
+		}
+		if len(r.Projects) != 1 || r.Projects[0].Total.Sessions != 2 {
+			t.Fatalf("projects = %+v", r.Projects)
+		}
+		if r.Total.Tokens.Output != 30 {
+			t.Errorf("Output = %d, want 30（兄弟 ab は含まない）", r.Total.Tokens.Output)
+		}
+		if len(r.Projects[0].Buckets) != 2 {
+			t.Errorf("day buckets = %d, want 2", len(r.Projects[0].Buckets))
+		}
+		if r.Projects[0].Sessions[0].SessionID != "s2" {
+			t.Errorf("sessions は開始時刻降順: got %s", r.Projects[0].Sessions[0].SessionID)
+		}
+	})
+
+	t.Run("週次は同一週に集約", func(t *testing.T) {
+		r, err := svc.Report(context.Background(), Query{ProjectPath: "/work/a", Bucket: BucketWeek})
+		if err != nil {
+			t.Fatalf("Report error: %v", err)
+		}
+		if b := r.Projects[0].Buckets; len(b) != 1 || b[0].Sessions != 2 || b[0].Label != "2026-06-29" {
+			t.Errorf("week buckets = %+v", b)
+		}
+	})
+
+	t.Run("期間フィルタ", func(t *testing.T) {
+		r, err := svc.Report(context.Background(), Query{From: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)})
+		if err != nil {
+			t.Fatalf("Report error: %v", err)
+		}
+		if r.Total.Sessions != 2 || r.Total.Tokens.Output != 60 {
+			t.Errorf("total = %+v", r.Total)
+		}
+	})
+
+	t.Run("バリデーション", func(t *testing.T) {
+		for _, q := range []Query{
+			{Bucket: "month"},
+			{ProjectPath: "/work/none"},
+			{From: now(), To: now().Add(-time.Hour)},
+		} {
+			if _, err := svc.Report(context.Background(), q); !errors.Is(err, ErrValidation) {
+				t.Errorf("Report(%+v) err = %v, want ErrValidation", q, err)
+			}
+		}
+	})
+}
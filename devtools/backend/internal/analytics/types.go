@@ -0,0 +1,148 @@
+package analytics
+
+import "time"
+
+// Bucket は時系列集計の粒度です。
+type Bucket string
+
+const (
+	// BucketDay は日次（ローカル時刻の0時区切り）集計です。
+	BucketDay Bucket = "day"
+	// BucketWeek は週次（ローカル時刻の月曜0時区切り）集計です。
+	BucketWeek Bucket = "week"
+)
+
+// TokenUsage はトークン使用量の合計です。
+type TokenUsage struct {
+	Input         int64 `json:"input"`
+	Output        int64 `json:"output"`
+	CacheCreation int64 `json:"cacheCreation"`
+	CacheRead     int64 `json:"cacheRead"`
+}
+
+// add は u に o を加算します。
+func (u *TokenUsage) add(o TokenUsage) {
+	u.Input += o.Input
+	u.Output += o.Output
+	u.CacheCreation += o.CacheCreation
+	u.CacheRead += o.CacheRead
+}
+
+// ToolStats はツール1種類の呼び出し回数とエラー回数です。
+type ToolStats struct {
+	Calls  int `json:"calls"`
+	Errors int `json:"errors"`
+}
+
+// Stats はセッション・プロジェクト・バケットで共通の集計値です。
+type Stats struct {
+	Sessions int                  `json:"sessions"`
+	Turns    int                  `json:"turns"`
+	Tokens   TokenUsage           `json:"tokens"`
+	Tools    map[string]ToolStats `json:"tools"`
+	// ToolCalls / ToolErrors は Tools の全ツール合計です。
+	ToolCalls  int `json:"toolCalls"`
+	ToolErrors int `json:"toolErrors"`
+	// ToolErrorRate は ToolErrors / ToolCalls（呼び出し0件なら0）です。
+	ToolErrorRate float64 `json:"toolErrorRate"`
+	// AvgTurnLatencyMs はプロンプトから最初の assistant 応答までの平均ミリ秒です。
+	AvgTurnLatencyMs int64 `json:"avgTurnLatencyMs"`
+	// MaxTurnLatencyMs はプロンプトから最初の assistant 応答までの最大ミリ秒です。
+	MaxTurnLatencyMs int64 `json:"maxTurnLatencyMs"`
+	// WallTimeSec は最初のエントリから最後のエントリまでの経過秒の合計です。
+	WallTimeSec int64 `json:"wallTimeSec"`
+
+	// latencySumMs / latencyCount は平均算出用の内部累積値です。
+	latencySumMs int64
+	latencyCount int
+}
+
+// newStats は Tools を初期化した Stats を返します。
+func newStats() Stats {
+	return Stats{Tools: make(map[string]ToolStats)}
+}
+
+// merge は s に o を合算し派生値を再計算します。
+func (s *Stats) merge(o Stats) {
+	s.Sessions += o.Sessions
+	s.Turns += o.Turns
+	s.Tokens.add(o.Tokens)
+	for name, ts := range o.Tools {
+		cur := s.Tools[name]
+		cur.Calls += ts.Calls
+		cur.Errors += ts.Errors
+		s.Tools[name] = cur
+	}
+	s.ToolCalls += o.ToolCalls
+	s.ToolErrors += o.ToolErrors
+	s.WallTimeSec += o.WallTimeSec
+	s.latencySumMs += o.latencySumMs
+	s.latencyCount += o.latencyCount
+	if o.MaxTurnLatencyMs > s.MaxTurnLatencyMs {
+		s.MaxTurnLatencyMs = o.MaxTurnLatencyMs
+	}
+	s.finalize()
+}
+
+// finalize は累積値からエラー率と平均レイテンシを算出します。
+func (s *Stats) finalize() {
+	if s.ToolCalls > 0 {
+		s.ToolErrorRate = float64(s.ToolErrors) / float64(s.ToolCalls)
+	} else {
+		s.ToolErrorRate = 0
+	}
+	if s.latencyCount > 0 {
+		s.AvgTurnLatencyMs = s.latencySumMs / int64(s.latencyCount)
+	} else {
+		s.AvgTurnLatencyMs = 0
+	}
+}
+
+// SessionStats は会話ログ1件の集計結果です。
+type SessionStats struct {
+	SessionID   string    `json:"sessionId"`
+	ProjectPath string    `json:"projectPath"`
+	StartedAt   time.Time `json:"startedAt"`
+	EndedAt     time.Time `json:"endedAt"`
+	Models      []string  `json:"models,omitempty"`
+	Stats
+}
+
+// BucketStats は時系列バケット1件の集計結果です。
+type BucketStats struct {
+	// Start はバケットの開始時刻（ローカル時刻）です。
+	Start time.Time `json:"start"`
+	// Label は表示用のバケット名（day: 2006-01-02 / week: 週頭の日付）です。
+	Label string `json:"label"`
+	Stats
+}
+
+// ProjectStats はプロジェクト1件の集計結果です。
+type ProjectStats struct {
+	Name     string         `json:"name"`
+	Path     string         `json:"path"`
+	Total    Stats          `json:"total"`
+	Buckets  []BucketStats  `json:"buckets"`
+	Sessions []SessionStats `json:"sessions"`
+}
+
+// Query は集計条件です。ゼロ値のフィールドは無条件を表します。
+type Query struct {
+	// ProjectPath は対象プロジェクトの絶対パスです（空なら全登録プロジェクト）。
+	ProjectPath string
+	// Bucket は時系列集計の粒度です（空なら BucketDay）。
+	Bucket Bucket
+	// From / To はセッション開始時刻の範囲 [From, To) です。
+	From time.Time
+	To   time.Time
+}
+
+// Report は /api/analytics のレスポンス本体です。
+type Report struct {
+	Bucket      Bucket         `json:"bucket"`
+	From        *time.Time     `json:"from,omitempty"`
+	To          *time.Time     `json:"to,omitempty"`
+	Total       Stats          `json:"total"`
+	Projects    []ProjectStats `json:"projects"`
+	GeneratedAt time.Time      `json:"generatedAt"`
+}
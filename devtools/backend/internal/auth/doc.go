@@ -0,0 +1,36 @@
+// Package auth は devtools API の端末認証（ペアリングとスコープ付きトークン）を提供する。
+//
+// # 概要
+//
+// サーバーは 0.0.0.0:8888 で待ち受け、Tailscale（100.x / *.ts.net）からのアクセスを許可している。
+// Claude を bypassPermissions で実行する /api/command やプロジェクトを削除する /api/projects/destroy を
+// 保護するため、端末ごとに長期トークンを発行し、スコープで許可する操作を分ける。
+//
+// ペアリングの流れ:
+//
+//  1. ホスト（サーバーを動かしているマシン）で POST /api/auth/pairing を呼び、1回限りのコード
+//     （例: "K7QM-3XPA"、5分有効）を発行する。ホストの画面はコードと QR を表示する
+//  2. 端末はコードと端末名を POST /api/auth/pair に送り、長期トークン（grt_...）を受け取る
+//  3. 端末は以降 Authorization: Bearer <token>（SSE / WebSocket の購読ルートに限り ?access_token=）で API を呼ぶ
+//  4. 不要になった端末は POST /api/auth/devices/revoke で失効させる
+//
+// # 主要な型・関数
+//
+//   - Scope / Scopes / ParseScope: read（閲覧）< execute（実行・回答・タスク操作）< destroy（削除・端末管理）
+//   - Service: StartPairing / Pair / Authenticate / Devices / Revoke
+//   - Device / Pairing / PairResult: 登録端末、発行したコード、ペアリング結果（トークンを含む）
+//   - Config: 認証の有無・ループバックの扱い・端末の保存先（config.Config.AuthConfig がサーバー設定から組み立てる）
+//
+// # 設計方針
+//
+//   - トークンは SHA-256 のハッシュのみを <stateDir>/devices.json（0600）に保存し、平文は発行時に1回だけ返す
+//   - ペアリングコードはメモリのみに保持する。誤りは接続元ごとに数え、maxPairFailures 回誤った接続元だけを
+//     pairLockout の間拒否する（他の端末のペアリングや発行済みのコードは妨げない）
+//   - 最終利用時刻はリクエストごとに書き込まず、1分以上進んだときだけ保存する
+//   - スコープは上位が下位を含む順序付きの3段階とし、ルートごとに必要なスコープを宣言する
+//     （ミドルウェアは handler パッケージの AuthHandler.Require）
+//   - プロキシを経由しないループバックからの要求はホスト本人として扱う（AUTH_TRUST_LOOPBACK=false で無効）。
+//     Tailscale Serve / Funnel 経由の要求は X-Forwarded-For 等が付くためホスト扱いにならない
+//   - 認証は既定で無効（features.auth）。同梱のフロントエンドは Next.js の rewrite 経由で X-Forwarded-For が付き、
+//     トークンも送らないため、有効にするとホスト上の UI からの要求も 401 になる
+package auth
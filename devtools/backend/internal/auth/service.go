@@ -0,0 +1,347 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// tokenPrefix は端末トークンの接頭辞です（ログや設定ファイルに紛れた時に見分けるため）
+	tokenPrefix = "grt_"
+	// pairingTTL はペアリングコードの有効期間です
+	pairingTTL = 5 * time.Minute
+	// maxPairFailures は同じ接続元がこの回数コードを誤ると pairLockout の間ペアリングを拒否する回数です（総当たり対策）
+	maxPairFailures = 5
+	// pairLockout は誤りが続いた接続元のペアリングを拒否する期間です（コードの有効期間と同じ）
+	pairLockout = pairingTTL
+	// lastUsedPersistInterval は最終利用時刻をファイルへ書き戻す最小間隔です（リクエストごとに書かないため）
+	lastUsedPersistInterval = time.Minute
+	// maxDeviceNameLen は端末名の最大文字数です
+	maxDeviceNameLen = 64
+	// pairingAlphabet はペアリングコードの文字（読み違えやすい 0/O/1/I を除く）
+	pairingAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// Config は認証の設定です
+type Config struct {
+	// Disabled は認証を行わないか（設定の features.auth の否定。既定は認証なし）
+	Disabled bool
+	// TrustLoopback はプロキシを経由しないループバックからの要求をホスト本人として扱うか
+	// （設定の features.trustLoopback。既定は true）
+	TrustLoopback bool
+	// DevicesPath は端末の登録先（<stateDir>/devices.json）
+	DevicesPath string
+}
+
+// deviceRecord は devices.json の1端末です
+type deviceRecord struct {
+	Device
+	TokenHash string `json:"tokenHash"`
+}
+
+// devicesFile は devices.json の内容です
+type devicesFile struct {
+	Devices []deviceRecord `json:"devices"`
+}
+
+// Service は端末のペアリング・トークン検証・失効を行います。
+// 登録端末は DevicesPath に保存し、ペアリングコードはメモリのみに保持します（再起動で無効）。
+type Service struct {
+	path string
+	now  func() time.Time
+
+	mu        sync.Mutex
+	devices   []deviceRecord
+	persisted map[string]time.Time   // key: 端末ID。ファイルに書いた最終利用時刻
+	pending   map[string]Pairing     // key: 正規化したコード
+	failures  map[string]pairFailure // key: 接続元
+}
+
+// pairFailure は接続元ごとのペアリングの失敗状況です
+type pairFailure struct {
+	count       int
+	last        time.Time
+	lockedUntil time.Time
+}
+
+// NewService は端末の登録を path から読み込んで Service を生成します。ファイルが無い場合は端末なしで始めます。
+// now が nil の場合は time.Now を使います。
+func NewService(path string, now func() time.Time) (*Service, error) {
+	if now == nil {
+		now = time.Now
+	}
+	s := &Service{
+		path:      path,
+		now:       now,
+		persisted: make(map[string]time.Time),
+		pending:   make(map[string]Pairing),
+		failures:  make(map[string]pairFailure),
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read devices: %w", err)
+	}
+	var f devicesFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse devices %s: %w", path, err)
+	}
+	s.devices = f.Devices
+	for _, d := range s.devices {
+		s.persisted[d.ID] = d.LastUsedAt
+	}
+	return s, nil
+}
+
+// StartPairing はホストに表示するペアリングコードを発行します。コードは pairingTTL の間1回だけ使えます
+func (s *Service) StartPairing(scope Scope) (Pairing, error) {
+	if !slices.Contains(Scopes, scope) {
+		return Pairing{}, fmt.Errorf("%w: unknown scope: %s", ErrValidation, scope)
+	}
+	code, err := newPairingCode()
+	if err != nil {
+		return Pairing{}, err
+	}
+	p := Pairing{Code: code, Scope: scope, ExpiresAt: s.now().Add(pairingTTL)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expirePairingsLocked()
+	s.pending[normalizeCode(code)] = p
+	return p, nil
+}
+
+// Pair はペアリングコードを端末トークンと交換します。トークンは戻り値でのみ返し、ハッシュを保存します。
+// client は接続元（IP アドレス）で、誤りの回数を接続元ごとに数えます。
+// maxPairFailures 回誤った接続元は pairLockout の間 ErrTooManyAttempts を返し、他の接続元や発行済みのコードには影響しません。
+func (s *Service) Pair(code, name, client string) (PairResult, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxDeviceNameLen {
+		return PairResult{}, fmt.Errorf("%w: name must be 1-%d characters", ErrValidation, maxDeviceNameLen)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expirePairingsLocked()
+	now := s.now()
+	f := s.failures[client]
+	if now.Before(f.lockedUntil) {
+		return PairResult{}, ErrTooManyAttempts
+	}
+
+	key := normalizeCode(code)
+	p, ok := s.pending[key]
+	if !ok {
+		f.count++
+		f.last = now
+		if f.count >= maxPairFailures {
+			log.Printf("[Auth] too many pairing failures, locking out client: client=%s, until=%s", client, now.Add(pairLockout).Format("15:04:05"))
+			f.count = 0
+			f.lockedUntil = now.Add(pairLockout)
+		}
+		s.failures[client] = f
+		return PairResult{}, ErrInvalidCode
+	}
+	delete(s.pending, key)
+	delete(s.failures, client)
+
+	token, err := newToken()
+	if err != nil {
+		return PairResult{}, err
+	}
+	id, err := randomHex(8)
+	if err != nil {
+		return PairResult{}, err
+	}
+	rec := deviceRecord{
+		Device:    Device{ID: id, Name: name, Scope: p.Scope, CreatedAt: now, LastUsedAt: now},
+		TokenHash: hashToken(token),
+	}
+	devices := append(append([]deviceRecord(nil), s.devices...), rec)
+	if err := s.saveLocked(devices); err != nil {
+		return PairResult{}, err
+	}
+	s.devices = devices
+	s.persisted[id] = now
+	log.Printf("[Auth] device paired: id=%s, name=%s, scope=%s", id, name, p.Scope)
+	return PairResult{Token: token, Device: rec.Device}, nil
+}
+
+// Authenticate はトークンを検証して端末を返し、最終利用時刻を更新します
+func (s *Service) Authenticate(token string) (Device, error) {
+	if !strings.HasPrefix(token, tokenPrefix) {
+		return Device{}, ErrInvalidToken
+	}
+	hash := hashToken(token)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.devices {
+		if subtle.ConstantTimeCompare([]byte(s.devices[i].TokenHash), []byte(hash)) != 1 {
+			continue
+		}
+		now := s.now()
+		s.devices[i].LastUsedAt = now
+		if now.Sub(s.persisted[s.devices[i].ID]) >= lastUsedPersistInterval {
+			if err := s.saveLocked(s.devices); err != nil {
+				// 最終利用時刻は参考情報のため、保存に失敗しても認証は通す
+				log.Printf("[Auth] failed to persist last used time: id=%s, error=%v", s.devices[i].ID, err)
+			} else {
+				s.persisted[s.devices[i].ID] = now
+			}
+		}
+		return s.devices[i].Device, nil
+	}
+	return Device{}, ErrInvalidToken
+}
+
+// Devices は登録端末をペアリングの古い順に返します
+func (s *Service) Devices() []Device {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Device, 0, len(s.devices))
+	for _, d := range s.devices {
+		out = append(out, d.Device)
+	}
+	return out
+}
+
+// Revoke は端末を削除し、そのトークンを以降無効にします
+func (s *Service) Revoke(id string) error {
+	if id == "" {
+		return fmt.Errorf("%w: id is required", ErrValidation)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	devices := make([]deviceRecord, 0, len(s.devices))
+	var found *deviceRecord
+	for i := range s.devices {
+		if s.devices[i].ID == id {
+			found = &s.devices[i]
+			continue
+		}
+		devices = append(devices, s.devices[i])
+	}
+	if found == nil {
+		return ErrNotFound
+	}
+	if err := s.saveLocked(devices); err != nil {
+		return err
+	}
+	log.Printf("[Auth] device revoked: id=%s, name=%s", found.ID, found.Name)
+	s.devices = devices
+	delete(s.persisted, id)
+	return nil
+}
+
+// expirePairingsLocked は期限切れのペアリングコードと、拒否期間を過ぎた接続元の失敗記録を捨てます（s.mu 保持下で呼ぶ）
+func (s *Service) expirePairingsLocked() {
+	now := s.now()
+	for k, p := range s.pending {
+		if !now.Before(p.ExpiresAt) {
+			delete(s.pending, k)
+		}
+	}
+	for k, f := range s.failures {
+		if !now.Before(f.lockedUntil) && now.Sub(f.last) >= pairLockout {
+			delete(s.failures, k)
+		}
+	}
+}
+
+// saveLocked は端末一覧を所有者のみ読み書きできるファイルへアトミックに書き込みます（s.mu 保持下で呼ぶ）
+func (s *Service) saveLocked(devices []deviceRecord) error {
+	data, err := json.MarshalIndent(devicesFile{Devices: devices}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal devices: %w", err)
+	}
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create devices dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".devices-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write devices: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename devices file: %w", err)
+	}
+	return nil
+}
+
+// newPairingCode は "ABCD-EFGH" 形式のペアリングコードを返します
+func newPairingCode() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate pairing code: %w", err)
+	}
+	code := make([]byte, 0, 9)
+	for i, v := range b {
+		if i == 4 {
+			code = append(code, '-')
+		}
+		code = append(code, pairingAlphabet[int(v)%len(pairingAlphabet)])
+	}
+	return string(code), nil
+}
+
+// normalizeCode は入力されたコードの大文字小文字・区切りの違いを吸収します
+func normalizeCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, code)
+}
+
+// newToken は端末トークン（grt_ + 256bit の base64url）を返します
+func newToken() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return tokenPrefix + base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+// randomHex は n バイトの乱数の16進文字列を返します
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashToken はトークンの保存用ハッシュ（SHA-256 の16進）を返します
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
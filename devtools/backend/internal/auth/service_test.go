@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestService(t *testing.T, now *time.Time) (*Service, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "devices.json")
+	s, err := NewService(path, func() time.Time { return *now })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, path
+}
+
+func TestScope_Allows(t *testing.T) {
+	tests := []struct {
+		have, need Scope
+		want       bool
+	}{
+		{ScopeRead, ScopeRead, true},
+		{ScopeRead, ScopeExecute, false},
+		{ScopeExecute, ScopeRead, true},
+		{ScopeExecute, ScopeDestroy, false},
+		{ScopeDestroy, ScopeExecute, true},
+		{Scope("admin"), ScopeRead, false},
+	}
+	for _, tt := range tests {
+		if got := tt.have.Allows(tt.need); got != tt.want {
+			t.Errorf("%s.Allows(%s) = %v, want %v", tt.have, tt.need, got, tt.want)
+		}
+	}
+	if _, err := ParseScope("admin"); !errors.Is(err, ErrValidation) {
+		t.Errorf("ParseScope(admin) err = %v, want ErrValidation", err)
+	}
+	if s, err := ParseScope(""); err != nil || s != ScopeRead {
+		t.Errorf("ParseScope(\"\") = %s, %v", s, err)
+	}
+}
+
+func TestService_PairAndAuthenticate(t *testing.T) {
+	now := time.Date(2026, 7, 20, 10, 0, 0, 0, time.UTC)
+	s, path := newTestService(t, &now)
+
+	p, err := s.StartPairing(ScopeExecute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Code) != 9 || p.Code[4] != '-' {
+		t.Errorf("code = %q, want XXXX-XXXX", p.Code)
+	}
+
+	// 小文字・区切りなしでも受け付ける
+	res, err := s.Pair(strings.ToLower(strings.ReplaceAll(p.Code, "-", "")), "iPhone", "100.64.0.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(res.Token, tokenPrefix) || res.Device.Scope != ScopeExecute || res.Device.Name != "iPhone" {
+		t.Errorf("pair result = %+v", res)
+	}
+
+	// コードは1回限り
+	if _, err := s.Pair(p.Code, "iPad", "100.64.0.2"); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("reuse err = %v, want ErrInvalidCode", err)
+	}
+
+	d, err := s.Authenticate(res.Token)
+	if err != nil || d.ID != res.Device.ID {
+		t.Errorf("Authenticate = %+v, %v", d, err)
+	}
+	if _, err := s.Authenticate(res.Token + "x"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("wrong token err = %v, want ErrInvalidToken", err)
+	}
+
+	// 平文のトークンは保存しない
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), res.Token) || !strings.Contains(string(data), hashToken(res.Token)) {
+		t.Errorf("devices.json should contain only the token hash: %s", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("devices.json perm = %o, want 600", info.Mode().Perm())
+	}
+
+	// 再読み込みしても同じトークンで認証できる
+	reloaded, err := NewService(path, func() time.Time { return now })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reloaded.Authenticate(res.Token); err != nil {
+		t.Errorf("Authenticate after reload: %v", err)
+	}
+}
+
+func TestService_PairingExpiresAndFailures(t *testing.T) {
+	now := time.Date(2026, 7, 20, 10, 0, 0, 0, time.UTC)
+	s, _ := newTestService(t, &now)
+
+	expired, _ := s.StartPairing(ScopeRead)
+	now = now.Add(pairingTTL)
+	if _, err := s.Pair(expired.Code, "phone", "100.64.0.2"); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("expired code err = %v, want ErrInvalidCode", err)
+	}
+
+	// maxPairFailures 回誤った接続元は pairLockout の間拒否されるが、他の接続元と発行済みのコードは影響を受けない
+	p, _ := s.StartPairing(ScopeRead)
+	for i := 0; i < maxPairFailures; i++ {
+		if _, err := s.Pair("ZZZZ-ZZZZ", "attacker", "100.64.0.9"); !errors.Is(err, ErrInvalidCode) {
+			t.Fatalf("failure %d err = %v, want ErrInvalidCode", i, err)
+		}
+	}
+	if _, err := s.Pair(p.Code, "attacker", "100.64.0.9"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("locked out client err = %v, want ErrTooManyAttempts", err)
+	}
+	if _, err := s.Pair(p.Code, "phone", "100.64.0.2"); err != nil {
+		t.Errorf("other client after failures: %v", err)
+	}
+	now = now.Add(pairLockout)
+	p2, _ := s.StartPairing(ScopeRead)
+	if _, err := s.Pair(p2.Code, "tablet", "100.64.0.9"); err != nil {
+		t.Errorf("client after lockout: %v", err)
+	}
+
+	if _, err := s.Pair("ABCD-EFGH", " ", "100.64.0.2"); !errors.Is(err, ErrValidation) {
+		t.Errorf("empty name err = %v, want ErrValidation", err)
+	}
+}
+
+func TestService_Revoke(t *testing.T) {
+	now := time.Date(2026, 7, 20, 10, 0, 0, 0, time.UTC)
+	s, path := newTestService(t, &now)
+
+	p, _ := s.StartPairing(ScopeRead)
+	res, err := s.Pair(p.Code, "phone", "100.64.0.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Devices(); len(got) != 1 {
+		t.Fatalf("devices = %+v", got)
+	}
+
+	if err := s.Revoke(res.Device.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Authenticate(res.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("revoked token err = %v, want ErrInvalidToken", err)
+	}
+	if err := s.Revoke(res.Device.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("revoke twice err = %v, want ErrNotFound", err)
+	}
+
+	reloaded, err := NewService(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.Devices(); len(got) != 0 {
+		t.Errorf("devices after reload = %+v, want none", got)
+	}
+}
+
+func TestService_LastUsedPersistedAtMostEveryMinute(t *testing.T) {
+	now := time.Date(2026, 7, 20, 10, 0, 0, 0, time.UTC)
+	s, path := newTestService(t, &now)
+	p, _ := s.StartPairing(ScopeRead)
+	res, _ := s.Pair(p.Code, "phone", "100.64.0.2")
+
+	load := func() time.Time {
+		r, err := NewService(path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r.Devices()[0].LastUsedAt
+	}
+
+	now = now.Add(30 * time.Second)
+	s.Authenticate(res.Token)
+	if got := load(); !got.Equal(res.Device.CreatedAt) {
+		t.Errorf("lastUsedAt persisted too early: %v", got)
+	}
+	if got := s.Devices()[0].LastUsedAt; !got.Equal(now) {
+		t.Errorf("in-memory lastUsedAt = %v, want %v", got, now)
+	}
+
+	now = now.Add(time.Minute)
+	s.Authenticate(res.Token)
+	if got := load(); !got.Equal(now) {
+		t.Errorf("lastUsedAt = %v, want %v", got, now)
+	}
+}
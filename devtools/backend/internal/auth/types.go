@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var (
+	// ErrValidation は入力（スコープ・端末名・ID）が不正であることを示します
+	ErrValidation = errors.New("validation error")
+	// ErrInvalidToken はトークンが無い・不明・失効済みであることを示します
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrInvalidCode はペアリングコードが不明・期限切れ・使用済みであることを示します
+	ErrInvalidCode = errors.New("invalid pairing code")
+	// ErrTooManyAttempts は接続元がペアリングコードを誤り続けたため一時的に拒否していることを示します
+	ErrTooManyAttempts = errors.New("too many pairing attempts")
+	// ErrNotFound は端末が登録されていないことを示します
+	ErrNotFound = errors.New("device not found")
+)
+
+// Scope は端末トークンに許可する操作の範囲です。read < execute < destroy の順に上位が下位を含みます
+type Scope string
+
+const (
+	// ScopeRead はダッシュボード・一覧・検索・イベント購読などの読み取りのみです
+	ScopeRead Scope = "read"
+	// ScopeExecute は読み取りに加え、Claude の実行・回答・タスク操作・巡回の開始停止などを許可します
+	ScopeExecute Scope = "execute"
+	// ScopeDestroy は実行に加え、プロジェクトの削除と端末の管理を許可します
+	ScopeDestroy Scope = "destroy"
+)
+
+// Scopes は指定できるスコープです（下位から順）
+var Scopes = []Scope{ScopeRead, ScopeExecute, ScopeDestroy}
+
+// Allows は s が required の操作を含むかを返します
+func (s Scope) Allows(required Scope) bool {
+	have := slices.Index(Scopes, s)
+	need := slices.Index(Scopes, required)
+	return have >= 0 && need >= 0 && have >= need
+}
+
+// ParseScope はスコープ指定を検証して返します。空文字は ScopeRead です
+func ParseScope(raw string) (Scope, error) {
+	if raw == "" {
+		return ScopeRead, nil
+	}
+	s := Scope(raw)
+	if !slices.Contains(Scopes, s) {
+		return "", fmt.Errorf("%w: unknown scope: %s", ErrValidation, raw)
+	}
+	return s, nil
+}
+
+// Device はペアリング済みの端末です。トークンはハッシュのみ保存し、発行時に1回だけ返します
+type Device struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Scope      Scope     `json:"scope"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"` // ペアリング直後は CreatedAt
+}
+
+// Pairing はホストに表示する1回限りのペアリングコードです
+type Pairing struct {
+	Code      string    `json:"code"`
+	Scope     Scope     `json:"scope"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// PairResult はペアリング成立時に端末へ返す結果です
+type PairResult struct {
+	Token  string `json:"token"`
+	Device Device `json:"device"`
+}
@@ -0,0 +1,116 @@
+package autoanswer
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+const (
+	// defaultQueryLimit は監査ログの既定の件数です
+	defaultQueryLimit = 100
+	// maxQueryLimit は監査ログの件数の上限です
+	maxQueryLimit = 1000
+)
+
+// AuditLog は自動回答とエスカレーションを1ファイルの JSONL に記録します
+type AuditLog struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewAuditLog は path に追記する AuditLog を生成します
+func NewAuditLog(path string) *AuditLog {
+	return &AuditLog{path: path}
+}
+
+// Append は1件追記します
+func (a *AuditLog) Append(rec Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(a.path), 0755); err != nil {
+		return fmt.Errorf("failed to create audit dir: %w", err)
+	}
+	f, err := os.OpenFile(a.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log %s: %w", a.path, err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write audit log %s: %w", a.path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log %s: %w", a.path, err)
+	}
+	return nil
+}
+
+// Query は条件に一致する記録を新しい順に返します。解析できない行は読み飛ばします。
+func (a *AuditLog) Query(q Query) (*Result, error) {
+	if !q.From.IsZero() && !q.To.IsZero() && q.From.After(q.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrValidation)
+	}
+	if q.Limit < 0 || q.Limit > maxQueryLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrValidation, maxQueryLimit)
+	}
+	if q.Limit == 0 {
+		q.Limit = defaultQueryLimit
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	records := []Record{}
+	f, err := os.Open(a.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &Result{Records: records}, nil
+		}
+		return nil, fmt.Errorf("failed to open audit log %s: %w", a.path, err)
+	}
+	defer f.Close()
+
+	project := ""
+	if q.Project != "" {
+		project = filepath.Clean(q.Project)
+	}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		if project != "" && filepath.Clean(rec.Project) != project {
+			continue
+		}
+		if (!q.From.IsZero() && rec.At.Before(q.From)) || (!q.To.IsZero() && rec.At.After(q.To)) {
+			continue
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log %s: %w", a.path, err)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].At.After(records[j].At)
+	})
+	result := &Result{Records: records, Total: len(records)}
+	if len(records) > q.Limit {
+		result.Records = records[:q.Limit]
+	}
+	return result, nil
+}
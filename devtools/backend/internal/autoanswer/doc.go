@@ -0,0 +1,45 @@
+// Package autoanswer はエージェントの定型的な質問（AskUserQuestion）への対応を、プロジェクトごとの
+// ルールファイルで自動化する。
+//
+// # 概要
+//
+// 巡回中の「推奨案で進めますか？」のような質問は毎回同じ答えになる。本パッケージはプロジェクトの
+// .ghostrunner/auto_answer.yaml のルールを質問の見出し・質問文・選択肢に照合し、一致したルールの対応
+// （即時回答 / 待ち時間後に回答 / 通知で人手に回す）を決める。自動回答とエスカレーションはすべて監査ログ
+// （~/.ghostrunner/auto_answers.jsonl）に記録し、GET /api/auto-answers で参照できる。
+//
+// ルールファイルの例:
+//
+//	rules:
+//	  - name: recommended
+//	    match:
+//	      option: "(Recommended|推奨)"
+//	    action: answer          # 一致した選択肢で即時回答
+//	  - name: naming
+//	    match:
+//	      header: "^命名$"
+//	    action: timeout         # 10分待って回答が無ければ先頭の選択肢で回答
+//	    timeout: 10m
+//	  - name: destructive
+//	    match:
+//	      question: "(?i)(削除|drop|force push)"
+//	    action: escalate        # 回答せず優先度の高い通知
+//
+// # 主要な型・関数
+//
+//   - Policy / Rule / ParsePolicy / LoadPolicy: ルールファイルの解析と検証（正規表現・対応・待ち時間）
+//   - Question / Decision: 照合する質問と、一致したルールの対応・回答・待ち時間
+//   - Engine: ルールファイルの読み込み（mtime キャッシュ）、Decide、Record、待機ごとに1回の Escalate
+//   - AuditLog / Record / Query: 監査ログの追記と照会（新しい順）
+//
+// # 設計方針
+//
+//   - ルールは先頭から照合し最初に一致したものを使う。match の header / question / option は指定した
+//     ものすべてに一致する必要があり、option はいずれかの選択肢に一致すればよい
+//   - 回答は answer 指定値、無ければ option に一致した選択肢、それも無ければ先頭の選択肢。回答を決められない
+//     （選択肢が無い）質問には answer / timeout のルールを適用しない
+//   - ルールファイルが不正な場合はログに出してそのプロジェクトの自動回答を無効にする（誤回答より安全側）
+//   - 回答の実行は呼び出し側が行う。巡回は Claude のセッション継続で回答し、ダッシュボードの質問待ち
+//     （外部で起動したセッション）はサーバーから回答できないため、回答する対応は提案として表示し、
+//     escalate のみ通知する
+package autoanswer
@@ -0,0 +1,146 @@
+package autoanswer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"ghostrunner/backend/internal/notify"
+)
+
+// escalationTTL はエスカレーション済みキーを覚えておく時間です（同じ待機を繰り返し通知しないため）
+const escalationTTL = 24 * time.Hour
+
+// Notifier はエスカレーションの通知先です（notify.Notifier。service.NtfyService と同一）
+type Notifier = notify.Notifier
+
+// cachedPolicy はルールファイルの読み込み結果を mtime・サイズと共に保持します
+type cachedPolicy struct {
+	modTime time.Time
+	size    int64
+	policy  *Policy
+}
+
+// Engine はプロジェクトごとのルールで質問への対応を決め、自動回答とエスカレーションを監査ログに記録します
+type Engine struct {
+	audit    *AuditLog
+	notifier Notifier
+	now      func() time.Time
+
+	mu        sync.Mutex
+	policies  map[string]cachedPolicy // key: プロジェクトパス
+	escalated map[string]time.Time    // key: 呼び出し元が決める待機のキー
+}
+
+// NewEngine は新しいEngineを生成します。
+// audit と notifier は nil 許容で、nil の場合はそれぞれ記録・通知をしません。now が nil の場合は time.Now を使います。
+func NewEngine(audit *AuditLog, notifier Notifier, now func() time.Time) *Engine {
+	if now == nil {
+		now = time.Now
+	}
+	return &Engine{
+		audit:     audit,
+		notifier:  notifier,
+		now:       now,
+		policies:  make(map[string]cachedPolicy),
+		escalated: make(map[string]time.Time),
+	}
+}
+
+// Decide はプロジェクトのルールファイルで質問への対応を決めます。
+// ルールファイルが無い・不正な場合は一致なしです（不正はログに出します）。
+func (e *Engine) Decide(projectPath string, q Question) (Decision, bool) {
+	p := e.policy(projectPath)
+	if p == nil {
+		return Decision{}, false
+	}
+	return p.Decide(q)
+}
+
+// policy はルールファイルを mtime・サイズが変わったときだけ読み直して返します
+func (e *Engine) policy(projectPath string) *Policy {
+	path := filepath.Join(projectPath, RulesFile)
+	info, err := os.Stat(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("[AutoAnswer] failed to stat rules file: path=%s, err=%v", path, err)
+		}
+		e.mu.Lock()
+		delete(e.policies, projectPath)
+		e.mu.Unlock()
+		return nil
+	}
+
+	e.mu.Lock()
+	cached, ok := e.policies[projectPath]
+	e.mu.Unlock()
+	if ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.policy
+	}
+
+	p, err := LoadPolicy(path)
+	if err != nil {
+		log.Printf("[AutoAnswer] invalid rules file, ignored: path=%s, err=%v", path, err)
+		p = nil
+	}
+	e.mu.Lock()
+	e.policies[projectPath] = cachedPolicy{modTime: info.ModTime(), size: info.Size(), policy: p}
+	e.mu.Unlock()
+	return p
+}
+
+// Record は自動回答を監査ログに記録します。At が未設定なら現在時刻を設定します。失敗はログのみです。
+func (e *Engine) Record(rec Record) {
+	if rec.At.IsZero() {
+		rec.At = e.now()
+	}
+	log.Printf("[AutoAnswer] %s: project=%s, source=%s, rule=%s, answer=%q", rec.Action, rec.Project, rec.Source, rec.Rule, rec.Answer)
+	if e.audit == nil {
+		return
+	}
+	if err := e.audit.Append(rec); err != nil {
+		log.Printf("[AutoAnswer] failed to append audit record: %v", err)
+	}
+}
+
+// Escalate は待機 key ごとに1回だけ優先度の高い通知を送り、監査ログに記録します。
+// 通知した場合は true、同じ key を既に通知済みなら false を返します。
+func (e *Engine) Escalate(key string, rec Record) bool {
+	now := e.now()
+	e.mu.Lock()
+	for k, at := range e.escalated {
+		if now.Sub(at) > escalationTTL {
+			delete(e.escalated, k)
+		}
+	}
+	if _, done := e.escalated[key]; done {
+		e.mu.Unlock()
+		return false
+	}
+	e.escalated[key] = now
+	e.mu.Unlock()
+
+	rec.Action = ActionEscalate
+	rec.Answer = ""
+	if e.notifier != nil {
+		notify.Send(e.notifier, notify.Message{
+			Event:    notify.EventAutoAnswerEscalate,
+			Project:  rec.Project,
+			Title:    fmt.Sprintf("要対応: %s", filepath.Base(rec.Project)),
+			Body:     fmt.Sprintf("[%s] %s", rec.Rule, rec.Question),
+			Priority: notify.PriorityHigh,
+		})
+	}
+	e.Record(rec)
+	return true
+}
+
+// Audit は監査ログを返します（未設定なら nil）
+func (e *Engine) Audit() *AuditLog {
+	return e.audit
+}
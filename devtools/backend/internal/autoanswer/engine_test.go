@@ -0,0 +1,122 @@
+package autoanswer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type mockNotifier struct {
+	mu     sync.Mutex
+	errors []string
+}
+
+func (m *mockNotifier) Notify(title, message string) {}
+
+func (m *mockNotifier) NotifyError(title, message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors = append(m.errors, message)
+}
+
+func writeRules(t *testing.T, projectPath, content string) {
+	t.Helper()
+	path := filepath.Join(projectPath, RulesFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEngine_Decide(t *testing.T) {
+	proj := t.TempDir()
+	e := NewEngine(nil, nil, nil)
+	q := Question{Text: "続けますか"}
+
+	if _, ok := e.Decide(proj, q); ok {
+		t.Error("expected no match without rules file")
+	}
+
+	writeRules(t, proj, "rules:\n  - name: fixed\n    match: {question: 続けますか}\n    action: answer\n    answer: はい\n")
+	d, ok := e.Decide(proj, q)
+	if !ok || d.Answer != "はい" {
+		t.Errorf("decision = %+v, ok=%v", d, ok)
+	}
+
+	// 不正なルールファイルは無効（一致なし）
+	writeRules(t, proj, "rules:\n  - name: broken\n    match: {question: \"(\"}\n    action: answer\n")
+	if _, ok := e.Decide(proj, q); ok {
+		t.Error("expected no match with invalid rules file")
+	}
+}
+
+func TestEngine_EscalateOnce(t *testing.T) {
+	dir := t.TempDir()
+	audit := NewAuditLog(filepath.Join(dir, "auto_answers.jsonl"))
+	notif := &mockNotifier{}
+	now := time.Date(2026, 7, 20, 10, 0, 0, 0, time.UTC)
+	e := NewEngine(audit, notif, func() time.Time { return now })
+
+	rec := Record{Project: "/p", SessionID: "s1", Source: SourcePatrol, Rule: "destructive", Question: "DROP?"}
+	if !e.Escalate("k1", rec) {
+		t.Error("first escalation should notify")
+	}
+	if e.Escalate("k1", rec) {
+		t.Error("second escalation of the same key should not notify")
+	}
+	if len(notif.errors) != 1 {
+		t.Errorf("NotifyError called %d times, want 1", len(notif.errors))
+	}
+
+	result, err := audit.Query(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 1 || result.Records[0].Action != ActionEscalate || !result.Records[0].At.Equal(now) {
+		t.Errorf("audit = %+v", result)
+	}
+}
+
+func TestAuditLog_Query(t *testing.T) {
+	audit := NewAuditLog(filepath.Join(t.TempDir(), "audit", "auto_answers.jsonl"))
+	base := time.Date(2026, 7, 20, 10, 0, 0, 0, time.UTC)
+
+	empty, err := audit.Query(Query{})
+	if err != nil || empty.Total != 0 {
+		t.Fatalf("empty: %+v, %v", empty, err)
+	}
+
+	for i, proj := range []string{"/a", "/b", "/a"} {
+		rec := Record{At: base.Add(time.Duration(i) * time.Hour), Project: proj, Rule: "r", Action: ActionAnswer, Answer: "x"}
+		if err := audit.Append(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := audit.Query(Query{Project: "/a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 2 || !result.Records[0].At.Equal(base.Add(2*time.Hour)) {
+		t.Errorf("project filter: %+v", result)
+	}
+
+	result, err = audit.Query(Query{From: base.Add(30 * time.Minute), Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 2 || len(result.Records) != 1 {
+		t.Errorf("from/limit: %+v", result)
+	}
+
+	for _, q := range []Query{{From: base, To: base.Add(-time.Hour)}, {Limit: 1001}, {Limit: -1}} {
+		if _, err := audit.Query(q); !errors.Is(err, ErrValidation) {
+			t.Errorf("query %+v: expected ErrValidation, got %v", q, err)
+		}
+	}
+}
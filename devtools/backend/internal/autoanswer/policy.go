@@ -0,0 +1,156 @@
+package autoanswer
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rule はルールファイルの1ルールです
+type Rule struct {
+	Name  string `yaml:"name"`
+	Match struct {
+		// Header / Question / Option は正規表現。指定したものすべてに一致したときルールが適用されます。
+		// Option はいずれかの選択肢ラベルに一致すれば一致とみなします。
+		Header   string `yaml:"header"`
+		Question string `yaml:"question"`
+		Option   string `yaml:"option"`
+	} `yaml:"match"`
+	Action Action `yaml:"action"`
+	// Answer は回答する文字列。省略時は Option に一致した最初の選択肢、Option も無ければ先頭の選択肢です
+	Answer string `yaml:"answer"`
+	// Timeout は ActionTimeout で回答までに待つ時間（"10m" 等）
+	Timeout string `yaml:"timeout"`
+
+	header, question, option *regexp.Regexp
+	timeout                  time.Duration
+}
+
+// Policy はプロジェクトのルール一覧です。先頭から順に照合し、最初に一致したルールを適用します
+type Policy struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// LoadPolicy はルールファイルを読み込み、検証します
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file %s: %w", path, err)
+	}
+	return ParsePolicy(data)
+}
+
+// ParsePolicy はルールファイルの内容を解析し、正規表現・対応・待ち時間を検証します
+func ParsePolicy(data []byte) (*Policy, error) {
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("%w: invalid yaml: %v", ErrValidation, err)
+	}
+	for i := range p.Rules {
+		if err := p.Rules[i].compile(); err != nil {
+			name := p.Rules[i].Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			return nil, fmt.Errorf("%w: rule %s: %v", ErrValidation, name, err)
+		}
+	}
+	return &p, nil
+}
+
+// compile はルールの正規表現と待ち時間を解釈します
+func (r *Rule) compile() error {
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	var err error
+	if r.header, err = compileOptional(r.Match.Header); err != nil {
+		return fmt.Errorf("match.header: %v", err)
+	}
+	if r.question, err = compileOptional(r.Match.Question); err != nil {
+		return fmt.Errorf("match.question: %v", err)
+	}
+	if r.option, err = compileOptional(r.Match.Option); err != nil {
+		return fmt.Errorf("match.option: %v", err)
+	}
+	if r.header == nil && r.question == nil && r.option == nil {
+		return fmt.Errorf("match requires at least one of header, question, option")
+	}
+
+	switch r.Action {
+	case ActionAnswer, ActionEscalate:
+	case ActionTimeout:
+		r.timeout, err = time.ParseDuration(r.Timeout)
+		if err != nil || r.timeout <= 0 {
+			return fmt.Errorf("timeout must be a positive duration: %q", r.Timeout)
+		}
+	default:
+		return fmt.Errorf("unknown action: %q", r.Action)
+	}
+	return nil
+}
+
+// compileOptional は空でなければ正規表現をコンパイルします
+func compileOptional(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	return regexp.Compile(expr)
+}
+
+// Decide は質問に最初に一致したルールの対応を返します。
+// 回答する対応（answer / timeout）で回答を決められない（選択肢が無く answer も無い）ルールは読み飛ばします。
+func (p *Policy) Decide(q Question) (Decision, bool) {
+	for _, r := range p.Rules {
+		matched, answer := r.matchQuestion(q)
+		if !matched {
+			continue
+		}
+		d := Decision{Rule: r.Name, Action: r.Action}
+		if r.Action != ActionEscalate {
+			if answer == "" {
+				continue
+			}
+			d.Answer = answer
+			d.Timeout = r.timeout
+		}
+		return d, true
+	}
+	return Decision{}, false
+}
+
+// matchQuestion はルールが質問に一致するかと、一致した場合の回答を返します
+func (r *Rule) matchQuestion(q Question) (bool, string) {
+	if r.header != nil && !r.header.MatchString(q.Header) {
+		return false, ""
+	}
+	if r.question != nil && !r.question.MatchString(q.Text) {
+		return false, ""
+	}
+
+	matchedOption := ""
+	if r.option != nil {
+		for _, o := range q.Options {
+			if r.option.MatchString(o) {
+				matchedOption = o
+				break
+			}
+		}
+		if matchedOption == "" {
+			return false, ""
+		}
+	}
+
+	switch {
+	case r.Answer != "":
+		return true, r.Answer
+	case matchedOption != "":
+		return true, matchedOption
+	case len(q.Options) > 0:
+		return true, q.Options[0]
+	}
+	return true, ""
+}
@@ -0,0 +1,113 @@
+package autoanswer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+const testRules = `
+rules:
+  - name: recommended
+    match:
+      option: "推奨"
+    action: answer
+  - name: naming
+    match:
+      header: "^命名$"
+    action: timeout
+    timeout: 10m
+  - name: destructive
+    match:
+      question: "(?i)(削除|drop)"
+    action: escalate
+  - name: fixed
+    match:
+      question: "続けますか"
+    action: answer
+    answer: "はい"
+`
+
+func TestPolicy_Decide(t *testing.T) {
+	p, err := ParsePolicy([]byte(testRules))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		q    Question
+		want Decision
+		ok   bool
+	}{
+		{
+			name: "option に一致した選択肢で回答",
+			q:    Question{Text: "どちらで進めますか", Options: []string{"A案", "B案（推奨）"}},
+			want: Decision{Rule: "recommended", Action: ActionAnswer, Answer: "B案（推奨）"},
+			ok:   true,
+		},
+		{
+			name: "timeout は先頭の選択肢",
+			q:    Question{Header: "命名", Text: "名前は？", Options: []string{"foo", "bar"}},
+			want: Decision{Rule: "naming", Action: ActionTimeout, Answer: "foo", Timeout: 10 * time.Minute},
+			ok:   true,
+		},
+		{
+			name: "escalate は回答なし",
+			q:    Question{Text: "テーブルを DROP しますか", Options: []string{"はい"}},
+			want: Decision{Rule: "destructive", Action: ActionEscalate},
+			ok:   true,
+		},
+		{
+			name: "answer 指定値で回答（選択肢なしでも可）",
+			q:    Question{Text: "続けますか"},
+			want: Decision{Rule: "fixed", Action: ActionAnswer, Answer: "はい"},
+			ok:   true,
+		},
+		{
+			name: "回答を決められないルールは読み飛ばす",
+			q:    Question{Header: "命名", Text: "名前は？"},
+			ok:   false,
+		},
+		{
+			name: "一致なし",
+			q:    Question{Text: "どうしますか", Options: []string{"A", "B"}},
+			ok:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := p.Decide(tt.q)
+			if ok != tt.ok {
+				t.Fatalf("matched = %v, want %v", ok, tt.ok)
+			}
+			if got != tt.want {
+				t.Errorf("decision = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePolicy_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+	}{
+		{"名前なし", "rules:\n  - match: {question: x}\n    action: answer\n"},
+		{"条件なし", "rules:\n  - name: a\n    action: answer\n"},
+		{"正規表現不正", "rules:\n  - name: a\n    match: {question: \"(\"}\n    action: answer\n"},
+		{"未知の対応", "rules:\n  - name: a\n    match: {question: x}\n    action: skip\n"},
+		{"timeout なし", "rules:\n  - name: a\n    match: {question: x}\n    action: timeout\n"},
+		{"yaml 不正", "rules: [\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePolicy([]byte(tt.rules))
+			if !errors.Is(err, ErrValidation) {
+				t.Errorf("expected ErrValidation, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package autoanswer
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrValidation はルールファイルや監査ログの照会条件が不正であることを示します
+var ErrValidation = errors.New("validation error")
+
+// RulesFile はプロジェクトルートからのルールファイルの相対パスです
+const RulesFile = ".ghostrunner/auto_answer.yaml"
+
+// Action はルールに一致した質問への対応です
+type Action string
+
+const (
+	// ActionAnswer は即座に回答します
+	ActionAnswer Action = "answer"
+	// ActionTimeout は Timeout の間人手の回答を待ち、回答が無ければ回答します
+	ActionTimeout Action = "timeout"
+	// ActionEscalate は回答せず、優先度の高い通知で人手に回します
+	ActionEscalate Action = "escalate"
+)
+
+// Source は質問を検出した経路です
+type Source string
+
+const (
+	// SourcePatrol は巡回（PatrolService）が実行した Claude の AskUserQuestion です
+	SourcePatrol Source = "patrol"
+	// SourceDashboard はダッシュボードが会話ログから検出した質問待ちセッションです
+	SourceDashboard Source = "dashboard"
+)
+
+// Question は照合する質問です。ダッシュボードの質問待ちセッションでは、会話ログの AskUserQuestion の
+// 見出しを改行連結した Header と、全質問の選択肢ラベルを並べた Options になります。
+type Question struct {
+	Header  string
+	Text    string
+	Options []string
+}
+
+// Decision はルールに一致した質問への対応です
+type Decision struct {
+	Rule   string
+	Action Action
+	// Answer は回答する文字列（ActionEscalate では空）
+	Answer string
+	// Timeout は ActionTimeout で回答までに待つ時間
+	Timeout time.Duration
+}
+
+// Record は監査ログの1件（自動回答またはエスカレーション）です
+type Record struct {
+	At        time.Time `json:"at"`
+	Project   string    `json:"project"`
+	SessionID string    `json:"sessionId,omitempty"`
+	Source    Source    `json:"source"`
+	Rule      string    `json:"rule"`
+	Action    Action    `json:"action"`
+	Header    string    `json:"header,omitempty"`
+	Question  string    `json:"question"`
+	Options   []string  `json:"options,omitempty"`
+	// Answer は自動回答した文字列（エスカレーションでは空）
+	Answer string `json:"answer,omitempty"`
+}
+
+// Query は監査ログの照会条件です
+type Query struct {
+	// Project はプロジェクトパス（空なら全プロジェクト）
+	Project string
+	// From / To は記録時刻の範囲（ゼロ値は無制限）
+	From time.Time
+	To   time.Time
+	// Limit は件数（0 は既定の100件、上限1000件）
+	Limit int
+}
+
+// Result は監査ログの照会結果です
+type Result struct {
+	// Records は新しい順
+	Records []Record `json:"records"`
+	// Total は Limit で切り詰める前の件数
+	Total int `json:"total"`
+}
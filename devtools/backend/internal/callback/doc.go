@@ -0,0 +1,24 @@
+// Package callback は通知のボタンから呼ぶ署名付き・単回使用のコールバック URL を提供する。
+//
+// # 概要
+//
+// 巡回中のプロジェクトが承認待ちになると、質問の選択肢ごとに <publicURL>/api/callbacks/<token> を発行し、
+// ntfy のボタン（押すと POST）として通知に付ける。ボタンを押すとサーバーがトークンを検証し、
+// その選択肢のラベルで PatrolService.ResumeProject を呼ぶ。UI を開かずにロック画面から回答できる。
+//
+// # 主要な型・関数
+//
+//   - Service / NewService: トークンの発行（Sign・AnswerActions）と検証（Verify・Consume）
+//   - Claim: トークンに埋め込む種類・プロジェクト・セッション・質問の要約値・回答・有効期限・単回使用の ID
+//   - Question / Question.Digest: ボタンを発行する質問と、その見出し・本文・選択肢の要約値
+//   - ErrInvalidToken / ErrExpired / ErrUsed: 検証の失敗理由
+//
+// # 設計方針
+//
+//   - トークンは base64url(JSON) と HMAC-SHA256 の署名で、サーバーに状態を持たずに内容を検証する。
+//     端末トークン（auth）を持たない通知アプリから呼ぶため、署名そのものを認可とする
+//   - 同じ質問のボタンは ID を共有し、どれか1つを使うと残りは ErrUsed になる（二重回答の防止）
+//   - 署名鍵と使用済み ID はメモリのみに保持する。再起動で巡回状態も失われるため、発行済み URL は無効になってよい
+//   - トークンの Project・SessionID・質問の要約値が現在の承認待ちと一致するかは呼び出し側（CallbackHandler）で確認する。
+//     巡回は --resume で同じセッションを続けるため、セッションが同じでも質問が変わっていれば古いボタンとして拒否する
+package callback
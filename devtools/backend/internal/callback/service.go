@@ -0,0 +1,207 @@
+package callback
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"ghostrunner/backend/internal/notify"
+)
+
+const (
+	// DefaultTTL はコールバック URL の既定の有効期間です
+	DefaultTTL = 24 * time.Hour
+	// PathPrefix はコールバックのエンドポイントのパスです（トークンを続けます）
+	PathPrefix = "/api/callbacks/"
+	// KindAnswer は承認待ちプロジェクトへ回答して再開するコールバックです
+	KindAnswer = "answer"
+	// maxAnswerActions は1通知に付ける回答ボタンの上限です（ntfy の上限）
+	maxAnswerActions = 3
+)
+
+var (
+	// ErrInvalidToken は形式・署名が不正なトークンです
+	ErrInvalidToken = errors.New("invalid callback token")
+	// ErrExpired は有効期限切れのトークンです
+	ErrExpired = errors.New("callback token expired")
+	// ErrUsed は使用済みのトークンです（同じ質問の他のボタンも含む）
+	ErrUsed = errors.New("callback token already used")
+)
+
+// Question はボタンを発行する質問です（AskUserQuestion の見出し・本文・選択肢のラベル）
+type Question struct {
+	Header  string
+	Text    string
+	Options []string
+}
+
+// Digest は質問の見出し・本文・全選択肢から作る短い要約値です。
+// --resume で再開したセッションは ID が変わらないため、セッション ID だけでは前の質問のボタンを見分けられません
+func (q Question) Digest() string {
+	h := sha256.New()
+	h.Write([]byte(q.Header))
+	h.Write([]byte{0})
+	h.Write([]byte(q.Text))
+	for _, o := range q.Options {
+		h.Write([]byte{0})
+		h.Write([]byte(o))
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// Claim はトークンに署名して埋め込む内容です
+type Claim struct {
+	// ID は単回使用の単位です。同じ質問のボタンは ID を共有し、どれか1つを使うと他も無効になります
+	ID        string `json:"id"`
+	Kind      string `json:"k"`
+	Project   string `json:"p"`
+	SessionID string `json:"s"`
+	// Question は発行時の質問の Question.Digest です（同じセッションで次の質問に進んだ後のボタンを拒否するため）
+	Question string `json:"q"`
+	Answer   string `json:"a"`
+	// ExpiresAt は有効期限（Unix 秒）です
+	ExpiresAt int64 `json:"e"`
+}
+
+// Service は署名付き・単回使用のコールバック URL を発行・検証します。
+// 署名鍵と使用済み ID はメモリのみに保持するため、再起動前に発行した URL は無効になります
+type Service struct {
+	key     []byte
+	baseURL string
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu   sync.Mutex
+	used map[string]time.Time // key: Claim.ID、value: 有効期限（期限後に削除）
+}
+
+// NewService は新しい Service を生成します。baseURL は端末から到達できるサーバーのベース URL、
+// key が空の場合はランダムな鍵を生成し、ttl が 0 以下なら DefaultTTL、now が nil なら time.Now を使います
+func NewService(baseURL string, key []byte, ttl time.Duration, now func() time.Time) (*Service, error) {
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate key: %w", err)
+		}
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &Service{
+		key:     key,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		ttl:     ttl,
+		now:     now,
+		used:    make(map[string]time.Time),
+	}, nil
+}
+
+// AnswerActions は承認待ちの質問の選択肢ごとに、押すとその選択肢で回答して再開する通知のボタンを返します。
+// ボタンは1つの ID を共有するため、どれか1つを押すと残りは使えません。先頭 3 つまでです
+func (s *Service) AnswerActions(projectPath, sessionID string, q Question) []notify.Action {
+	labels := q.Options
+	if len(labels) == 0 || sessionID == "" {
+		return nil
+	}
+	id, err := randomID()
+	if err != nil {
+		return nil
+	}
+	exp := s.now().Add(s.ttl).Unix()
+	digest := q.Digest()
+	var actions []notify.Action
+	for _, label := range labels[:min(len(labels), maxAnswerActions)] {
+		token := s.Sign(Claim{ID: id, Kind: KindAnswer, Project: projectPath, SessionID: sessionID, Question: digest, Answer: label, ExpiresAt: exp})
+		actions = append(actions, notify.Action{Label: label, URL: s.baseURL + PathPrefix + token})
+	}
+	return actions
+}
+
+// Sign は Claim に署名したトークン（base64url(JSON) + "." + base64url(HMAC-SHA256)）を返します
+func (s *Service) Sign(c Claim) string {
+	payload, _ := json.Marshal(c)
+	body := base64.RawURLEncoding.EncodeToString(payload)
+	return body + "." + base64.RawURLEncoding.EncodeToString(s.mac(body))
+}
+
+// Verify はトークンの署名・有効期限・未使用を検証し、Claim を返します。使用済みにはしません
+func (s *Service) Verify(token string) (Claim, error) {
+	body, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return Claim{}, ErrInvalidToken
+	}
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, s.mac(body)) {
+		return Claim{}, ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return Claim{}, ErrInvalidToken
+	}
+	var c Claim
+	if err := json.Unmarshal(payload, &c); err != nil || c.ID == "" {
+		return Claim{}, ErrInvalidToken
+	}
+	now := s.now()
+	if now.Unix() >= c.ExpiresAt {
+		return Claim{}, ErrExpired
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pruneLocked(now)
+	if _, used := s.used[c.ID]; used {
+		return Claim{}, ErrUsed
+	}
+	return c, nil
+}
+
+// Consume はトークンを検証して使用済みにします。同じ ID のトークンが同時に使われた場合は1つだけが成功します
+func (s *Service) Consume(token string) (Claim, error) {
+	c, err := s.Verify(token)
+	if err != nil {
+		return Claim{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, used := s.used[c.ID]; used {
+		return Claim{}, ErrUsed
+	}
+	s.used[c.ID] = time.Unix(c.ExpiresAt, 0)
+	return c, nil
+}
+
+// mac は body の HMAC-SHA256 を返します
+func (s *Service) mac(body string) []byte {
+	h := hmac.New(sha256.New, s.key)
+	h.Write([]byte(body))
+	return h.Sum(nil)
+}
+
+// pruneLocked は有効期限を過ぎた使用済み ID を削除します（期限切れのトークンは ErrExpired になるため覚えておく必要がない）
+func (s *Service) pruneLocked(now time.Time) {
+	for id, exp := range s.used {
+		if !now.Before(exp) {
+			delete(s.used, id)
+		}
+	}
+}
+
+// randomID は 16 バイトのランダムな ID を返します
+func randomID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
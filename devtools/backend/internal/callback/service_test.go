@@ -0,0 +1,104 @@
+package callback
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestService(t *testing.T, now *time.Time) *Service {
+	t.Helper()
+	s, err := NewService("https://mac.example.ts.net:8888/", []byte("test-key"), time.Hour, func() time.Time { return *now })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func tokenOf(t *testing.T, url string) string {
+	t.Helper()
+	token, ok := strings.CutPrefix(url, "https://mac.example.ts.net:8888"+PathPrefix)
+	if !ok {
+		t.Fatalf("unexpected url: %s", url)
+	}
+	return token
+}
+
+func TestService_AnswerActions(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	s := newTestService(t, &now)
+
+	q := Question{Header: "方式", Text: "どれにしますか？", Options: []string{"A案", "B案", "C案", "D案"}}
+	actions := s.AnswerActions("/home/u/shop", "sess-1", q)
+	if len(actions) != 3 {
+		t.Fatalf("len(actions) = %d, want 3", len(actions))
+	}
+	if actions[1].Label != "B案" {
+		t.Errorf("label = %q, want B案", actions[1].Label)
+	}
+	c, err := s.Verify(tokenOf(t, actions[1].URL))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if c.Kind != KindAnswer || c.Project != "/home/u/shop" || c.SessionID != "sess-1" || c.Answer != "B案" || c.Question != q.Digest() {
+		t.Errorf("claim = %+v", c)
+	}
+
+	if q.Digest() == (Question{Header: q.Header, Text: q.Text, Options: q.Options[:3]}).Digest() {
+		t.Error("digest should cover every option")
+	}
+
+	if got := s.AnswerActions("/home/u/shop", "", Question{Options: []string{"A"}}); got != nil {
+		t.Errorf("actions without session = %v, want nil", got)
+	}
+}
+
+func TestService_ConsumeIsSingleUse(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	s := newTestService(t, &now)
+	actions := s.AnswerActions("/p", "sess", Question{Options: []string{"yes", "no"}})
+
+	if _, err := s.Consume(tokenOf(t, actions[0].URL)); err != nil {
+		t.Fatalf("first Consume: %v", err)
+	}
+	if _, err := s.Consume(tokenOf(t, actions[0].URL)); !errors.Is(err, ErrUsed) {
+		t.Errorf("second Consume err = %v, want ErrUsed", err)
+	}
+	if _, err := s.Verify(tokenOf(t, actions[1].URL)); !errors.Is(err, ErrUsed) {
+		t.Errorf("sibling button err = %v, want ErrUsed", err)
+	}
+}
+
+func TestService_VerifyRejects(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	s := newTestService(t, &now)
+	token := s.Sign(Claim{ID: "x", Kind: KindAnswer, Answer: "yes", ExpiresAt: now.Add(time.Minute).Unix()})
+
+	other, _ := NewService("", []byte("other-key"), time.Hour, func() time.Time { return now })
+	body, _, _ := strings.Cut(token, ".")
+	forged := other.Sign(Claim{ID: "x", Kind: KindAnswer, Answer: "no", ExpiresAt: now.Add(time.Minute).Unix()})
+	_, forgedSig, _ := strings.Cut(forged, ".")
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"no signature", body, ErrInvalidToken},
+		{"signed by another key", body + "." + forgedSig, ErrInvalidToken},
+		{"garbage", "abc.def", ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Verify(tt.token); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := s.Verify(token); !errors.Is(err, ErrExpired) {
+		t.Errorf("expired err = %v, want ErrExpired", err)
+	}
+}
@@ -0,0 +1,652 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"ghostrunner/backend/internal/auth"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// defaultListen は既定の待ち受けアドレスです
+	defaultListen = "0.0.0.0:8888"
+	// defaultConfigFile は StateDir 配下の既定の設定ファイル名です
+	defaultConfigFile = "config.yaml"
+	// rootMarker は Root を自動検出するときの目印です（リポジトリルートからの相対パス）
+	rootMarker = "devtools/backend/go.mod"
+)
+
+// defaultAllowedOrigins は既定で許可する Origin です（localhost の開発サーバーと Tailscale）
+var defaultAllowedOrigins = []string{"http://localhost:3000", "http://localhost:3333", "http://100.*", "*.ts.net"}
+
+// defaultSummarizers は既定の要約プロバイダーの順です（オフライン・クォータ切れでも抽出要約で埋める）
+var defaultSummarizers = []string{"claude", "extractive"}
+
+// defaultProgressInterval は動作中セッションの進捗を再要約する最小間隔の既定値です
+const defaultProgressInterval = "5m"
+
+const (
+	// defaultResumeSettleSeconds は計画書の最終更新から自動再開までに待つ秒数の既定値です
+	defaultResumeSettleSeconds = 60
+	// defaultResumeMaxAttempts は1タスクあたりの実行回数の上限の既定値です（初回実行を含む）
+	defaultResumeMaxAttempts = 5
+	// defaultStuckWindowMinutes は会話ログが伸びないまま停滞とみなすまでの分数の既定値です（idle.RunningMaxAge と揃える）
+	defaultStuckWindowMinutes = 10
+	// defaultStuckRepeatThreshold は同一ツール呼び出しの連続失敗を停滞とみなす回数の既定値です
+	defaultStuckRepeatThreshold = 3
+)
+
+// minProgressInterval は summarizer.progressInterval に指定できる最小値です（0 は無効化）
+const minProgressInterval = time.Minute
+
+// knownSummarizers は summarizer.providers に指定できるプロバイダー名です（service.SummarizeProvider* と同じ）
+var knownSummarizers = []string{"claude", "openai", "gemini", "local", "extractive"}
+
+// fileSummarizerEndpoint は設定ファイルの要約プロバイダーの接続先です
+type fileSummarizerEndpoint struct {
+	BaseURL *string `yaml:"baseURL"`
+	Model   *string `yaml:"model"`
+}
+
+// fileConfig は設定ファイルの内容です。指定の有無を区別するためポインタで受けます
+type fileConfig struct {
+	Root           *string  `yaml:"root"`
+	Listen         *string  `yaml:"listen"`
+	ProjectBaseDir *string  `yaml:"projectBaseDir"`
+	StateDir       *string  `yaml:"stateDir"`
+	PatrolProjects *string  `yaml:"patrolProjects"`
+	AllowedOrigins []string `yaml:"allowedOrigins"`
+	PublicURL      *string  `yaml:"publicURL"`
+	Features       struct {
+		Auth          *bool `yaml:"auth"`
+		TrustLoopback *bool `yaml:"trustLoopback"`
+		AutoResume    *bool `yaml:"autoResume"`
+		Pprof         *bool `yaml:"pprof"`
+	} `yaml:"features"`
+	Digest struct {
+		Daily     *string `yaml:"daily"`
+		Weekly    *string `yaml:"weekly"`
+		WriteDocs *bool   `yaml:"writeDocs"`
+	} `yaml:"digest"`
+	Summarizer struct {
+		Providers        []string               `yaml:"providers"`
+		ProgressInterval *string                `yaml:"progressInterval"`
+		OpenAI           fileSummarizerEndpoint `yaml:"openai"`
+		Gemini           fileSummarizerEndpoint `yaml:"gemini"`
+		Local            fileSummarizerEndpoint `yaml:"local"`
+	} `yaml:"summarizer"`
+	Resume struct {
+		SettleSeconds *int `yaml:"settleSeconds"`
+		MaxAttempts   *int `yaml:"maxAttempts"`
+	} `yaml:"resume"`
+	Watchdog struct {
+		StuckWindowMinutes *int `yaml:"stuckWindowMinutes"`
+		RepeatThreshold    *int `yaml:"repeatThreshold"`
+	} `yaml:"watchdog"`
+}
+
+// Load はコマンドライン引数・環境変数・設定ファイルから設定を組み立てて検証し、StateDir を作成します。
+// getenv は環境変数の参照（通常 os.Getenv）、homeDir は "~" の展開先、wd は相対パスの基準と Root の自動検出の起点です。
+// 設定ファイルは --config / GHOSTRUNNER_CONFIG、無ければ <StateDir>/config.yaml（無くてもよい）です。
+func Load(args []string, getenv func(string) string, homeDir, wd string) (*Config, error) {
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	flagConfig := fs.String("config", "", "設定ファイル（既定: <state-dir>/config.yaml）")
+	flagRoot := fs.String("root", "", "Ghostrunner リポジトリのルート")
+	flagListen := fs.String("listen", "", "待ち受けアドレス（既定: "+defaultListen+"）")
+	flagBase := fs.String("project-base-dir", "", "プロジェクトのベースディレクトリ（既定: ホームディレクトリ）")
+	flagState := fs.String("state-dir", "", "状態の格納先（既定: ~/.ghostrunner）")
+	flagPatrol := fs.String("patrol-projects", "", "登録プロジェクトの一覧ファイル（既定: <root>/devtools/backend/patrol_projects.json）")
+	flagOrigins := fs.String("allowed-origins", "", "許可する Origin（カンマ区切り）")
+	flagPublicURL := fs.String("public-url", "", "端末から到達できるこのサーバーのベース URL（通知のアクションボタンに使用）")
+	flagAuth := fs.Bool("auth", false, "端末認証を行う（フロントエンドはトークンを送らないため既定は無効）")
+	flagTrust := fs.Bool("trust-loopback", true, "ループバックをホスト本人として扱う")
+	flagResume := fs.Bool("auto-resume", false, "回答待ちタスクを自動再開する")
+	flagPprof := fs.Bool("pprof", false, "/debug/pprof を公開する")
+	flagDigestDaily := fs.String("digest-daily", "", "日次ダイジェストを通知する時刻（HH:MM）")
+	flagDigestWeekly := fs.String("digest-weekly", "", "週次ダイジェストを通知する曜日と時刻（mon 09:00）")
+	flagDigestDocs := fs.Bool("digest-write-docs", false, "定期配信したダイジェストを 開発/資料 に書き出す")
+	flagSummarizer := fs.String("summarizer", "", "質問待ちの要約プロバイダー（試す順、カンマ区切り。既定: claude,extractive）")
+	flagLocalURL := fs.String("summarizer-local-url", "", "要約に使う OpenAI 互換のローカルエンドポイント（例: http://localhost:11434/v1）")
+	flagLocalModel := fs.String("summarizer-local-model", "", "ローカルエンドポイントの要約モデル")
+	flagProgress := fs.String("summarizer-progress-interval", "", "動作中セッションの進捗を再要約する最小間隔（既定: "+defaultProgressInterval+"、0 で無効）")
+	if err := fs.Parse(args); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+	setFlags := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { setFlags[f.Name] = true })
+
+	cfg := &Config{
+		Listen:         defaultListen,
+		ProjectBaseDir: homeDir,
+		StateDir:       filepath.Join(homeDir, ".ghostrunner"),
+		AllowedOrigins: defaultAllowedOrigins,
+		Features:       Features{TrustLoopback: true},
+		Summarizer:     Summarizer{Providers: defaultSummarizers, ProgressInterval: defaultProgressInterval},
+		Resume:         Resume{SettleSeconds: defaultResumeSettleSeconds, MaxAttempts: defaultResumeMaxAttempts},
+		Watchdog:       Watchdog{StuckWindowMinutes: defaultStuckWindowMinutes, RepeatThreshold: defaultStuckRepeatThreshold},
+		Sources:        make(map[string]Source),
+	}
+	for _, key := range []string{"root", "listen", "projectBaseDir", "stateDir", "patrolProjects", "allowedOrigins", "publicURL",
+		"features.auth", "features.trustLoopback", "features.autoResume", "features.pprof",
+		"digest.daily", "digest.weekly", "digest.writeDocs",
+		"summarizer.providers", "summarizer.openai.baseURL", "summarizer.openai.model", "summarizer.gemini.baseURL", "summarizer.gemini.model",
+		"summarizer.local.baseURL", "summarizer.local.model", "summarizer.progressInterval",
+		"resume.settleSeconds", "resume.maxAttempts", "watchdog.stuckWindowMinutes", "watchdog.repeatThreshold"} {
+		cfg.Sources[key] = SourceDefault
+	}
+
+	// 設定ファイルの場所は StateDir に依存するため、StateDir だけ先に環境変数・引数を見る
+	stateDir := cfg.StateDir
+	if v := getenv("GHOSTRUNNER_STATE_DIR"); v != "" {
+		stateDir = v
+	}
+	if setFlags["state-dir"] {
+		stateDir = *flagState
+	}
+	configFlag := ""
+	if setFlags["config"] {
+		configFlag = *flagConfig
+	}
+	path, explicit := configFile(stateDir, configFlag, getenv, homeDir, wd)
+	if err := cfg.applyFile(path, explicit); err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	envString := func(name, key string, dst *string) {
+		if v := getenv(name); v != "" {
+			*dst = v
+			cfg.Sources[key] = SourceEnv
+		}
+	}
+	envBool := func(name, key string, dst *bool, invert bool) {
+		v := getenv(name)
+		if v == "" {
+			return
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: %s must be a boolean: %q", ErrValidation, name, v))
+			return
+		}
+		*dst = b != invert
+		cfg.Sources[key] = SourceEnv
+	}
+	envInt := func(name, key string, dst *int) {
+		v := getenv(name)
+		if v == "" {
+			return
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: %s must be an integer: %q", ErrValidation, name, v))
+			return
+		}
+		*dst = n
+		cfg.Sources[key] = SourceEnv
+	}
+	envString("GHOSTRUNNER_ROOT", "root", &cfg.Root)
+	envString("GHOSTRUNNER_LISTEN", "listen", &cfg.Listen)
+	envString("GHOSTRUNNER_PROJECT_BASE_DIR", "projectBaseDir", &cfg.ProjectBaseDir)
+	envString("GHOSTRUNNER_STATE_DIR", "stateDir", &cfg.StateDir)
+	envString("GHOSTRUNNER_PATROL_PROJECTS", "patrolProjects", &cfg.PatrolProjects)
+	envString("GHOSTRUNNER_PUBLIC_URL", "publicURL", &cfg.PublicURL)
+	if v := getenv("GHOSTRUNNER_ALLOWED_ORIGINS"); v != "" {
+		cfg.AllowedOrigins = splitList(v)
+		cfg.Sources["allowedOrigins"] = SourceEnv
+	}
+	envBool("AUTH_DISABLED", "features.auth", &cfg.Features.Auth, true)
+	envBool("AUTH_TRUST_LOOPBACK", "features.trustLoopback", &cfg.Features.TrustLoopback, false)
+	envBool("AUTO_RESUME", "features.autoResume", &cfg.Features.AutoResume, false)
+	envBool("ENABLE_PPROF", "features.pprof", &cfg.Features.Pprof, false)
+	envString("GHOSTRUNNER_DIGEST_DAILY", "digest.daily", &cfg.Digest.Daily)
+	envString("GHOSTRUNNER_DIGEST_WEEKLY", "digest.weekly", &cfg.Digest.Weekly)
+	envBool("GHOSTRUNNER_DIGEST_WRITE_DOCS", "digest.writeDocs", &cfg.Digest.WriteDocs, false)
+	if v := getenv("GHOSTRUNNER_SUMMARIZER"); v != "" {
+		cfg.Summarizer.Providers = splitList(v)
+		cfg.Sources["summarizer.providers"] = SourceEnv
+	}
+	envString("GHOSTRUNNER_SUMMARIZER_LOCAL_URL", "summarizer.local.baseURL", &cfg.Summarizer.Local.BaseURL)
+	envString("GHOSTRUNNER_SUMMARIZER_LOCAL_MODEL", "summarizer.local.model", &cfg.Summarizer.Local.Model)
+	envString("GHOSTRUNNER_SUMMARIZER_PROGRESS_INTERVAL", "summarizer.progressInterval", &cfg.Summarizer.ProgressInterval)
+	envInt("AUTO_RESUME_SETTLE_SECONDS", "resume.settleSeconds", &cfg.Resume.SettleSeconds)
+	envInt("AUTO_RESUME_MAX_ATTEMPTS", "resume.maxAttempts", &cfg.Resume.MaxAttempts)
+	envInt("STUCK_WINDOW_MINUTES", "watchdog.stuckWindowMinutes", &cfg.Watchdog.StuckWindowMinutes)
+	envInt("STUCK_REPEAT_THRESHOLD", "watchdog.repeatThreshold", &cfg.Watchdog.RepeatThreshold)
+
+	flagString := func(name, key string, dst *string, v string) {
+		if setFlags[name] {
+			*dst = v
+			cfg.Sources[key] = SourceFlag
+		}
+	}
+	flagBool := func(name, key string, dst *bool, v bool) {
+		if setFlags[name] {
+			*dst = v
+			cfg.Sources[key] = SourceFlag
+		}
+	}
+	flagString("root", "root", &cfg.Root, *flagRoot)
+	flagString("listen", "listen", &cfg.Listen, *flagListen)
+	flagString("project-base-dir", "projectBaseDir", &cfg.ProjectBaseDir, *flagBase)
+	flagString("state-dir", "stateDir", &cfg.StateDir, *flagState)
+	flagString("patrol-projects", "patrolProjects", &cfg.PatrolProjects, *flagPatrol)
+	flagString("public-url", "publicURL", &cfg.PublicURL, *flagPublicURL)
+	if setFlags["allowed-origins"] {
+		cfg.AllowedOrigins = splitList(*flagOrigins)
+		cfg.Sources["allowedOrigins"] = SourceFlag
+	}
+	flagBool("auth", "features.auth", &cfg.Features.Auth, *flagAuth)
+	flagBool("trust-loopback", "features.trustLoopback", &cfg.Features.TrustLoopback, *flagTrust)
+	flagBool("auto-resume", "features.autoResume", &cfg.Features.AutoResume, *flagResume)
+	flagBool("pprof", "features.pprof", &cfg.Features.Pprof, *flagPprof)
+	flagString("digest-daily", "digest.daily", &cfg.Digest.Daily, *flagDigestDaily)
+	flagString("digest-weekly", "digest.weekly", &cfg.Digest.Weekly, *flagDigestWeekly)
+	flagBool("digest-write-docs", "digest.writeDocs", &cfg.Digest.WriteDocs, *flagDigestDocs)
+	if setFlags["summarizer"] {
+		cfg.Summarizer.Providers = splitList(*flagSummarizer)
+		cfg.Sources["summarizer.providers"] = SourceFlag
+	}
+	flagString("summarizer-local-url", "summarizer.local.baseURL", &cfg.Summarizer.Local.BaseURL, *flagLocalURL)
+	flagString("summarizer-local-model", "summarizer.local.model", &cfg.Summarizer.Local.Model, *flagLocalModel)
+	flagString("summarizer-progress-interval", "summarizer.progressInterval", &cfg.Summarizer.ProgressInterval, *flagProgress)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	cfg.resolvePaths(homeDir, wd)
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(cfg.StateDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create state dir: %w", err)
+	}
+	return cfg, nil
+}
+
+// ResolveStateDir はサーバーと同じ優先順位（引数 > 環境変数 GHOSTRUNNER_STATE_DIR > 設定ファイルの stateDir > ~/.ghostrunner）で
+// 状態の格納先を返します。設定全体を必要としない gr-run が、サーバーと同じロック・実行履歴・通知設定を使うためのものです。
+// stateDirFlag / configFlag は --state-dir / --config の値で、空なら未指定として扱います。
+func ResolveStateDir(stateDirFlag, configFlag string, getenv func(string) string, homeDir, wd string) (string, error) {
+	c := &Config{StateDir: filepath.Join(homeDir, ".ghostrunner"), Sources: make(map[string]Source)}
+	stateDir := c.StateDir
+	if v := getenv("GHOSTRUNNER_STATE_DIR"); v != "" {
+		stateDir = v
+	}
+	if stateDirFlag != "" {
+		stateDir = stateDirFlag
+	}
+	path, explicit := configFile(stateDir, configFlag, getenv, homeDir, wd)
+	if err := c.applyFile(path, explicit); err != nil {
+		return "", err
+	}
+	if v := getenv("GHOSTRUNNER_STATE_DIR"); v != "" {
+		c.StateDir = v
+	}
+	if stateDirFlag != "" {
+		c.StateDir = stateDirFlag
+	}
+	return expandPath(c.StateDir, homeDir, wd), nil
+}
+
+// configFile は設定ファイルの場所（--config > GHOSTRUNNER_CONFIG > <stateDir>/config.yaml）と、明示指定かを返します
+func configFile(stateDir, configFlag string, getenv func(string) string, homeDir, wd string) (string, bool) {
+	path, explicit := filepath.Join(expandPath(stateDir, homeDir, wd), defaultConfigFile), false
+	if v := getenv("GHOSTRUNNER_CONFIG"); v != "" {
+		path, explicit = v, true
+	}
+	if configFlag != "" {
+		path, explicit = configFlag, true
+	}
+	return expandPath(path, homeDir, wd), explicit
+}
+
+// applyFile は設定ファイルの値を反映します。explicit でない（既定の場所の）ファイルは無くてもかまいません
+func (c *Config) applyFile(path string, explicit bool) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	var f fileConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: invalid config file %s: %v", ErrValidation, path, err)
+	}
+	c.File = path
+
+	str := func(key string, dst *string, v *string) {
+		if v != nil {
+			*dst = *v
+			c.Sources[key] = SourceFile
+		}
+	}
+	bl := func(key string, dst *bool, v *bool) {
+		if v != nil {
+			*dst = *v
+			c.Sources[key] = SourceFile
+		}
+	}
+	num := func(key string, dst *int, v *int) {
+		if v != nil {
+			*dst = *v
+			c.Sources[key] = SourceFile
+		}
+	}
+	str("root", &c.Root, f.Root)
+	str("listen", &c.Listen, f.Listen)
+	str("projectBaseDir", &c.ProjectBaseDir, f.ProjectBaseDir)
+	str("stateDir", &c.StateDir, f.StateDir)
+	str("patrolProjects", &c.PatrolProjects, f.PatrolProjects)
+	if f.AllowedOrigins != nil {
+		c.AllowedOrigins = f.AllowedOrigins
+		c.Sources["allowedOrigins"] = SourceFile
+	}
+	str("publicURL", &c.PublicURL, f.PublicURL)
+	bl("features.auth", &c.Features.Auth, f.Features.Auth)
+	bl("features.trustLoopback", &c.Features.TrustLoopback, f.Features.TrustLoopback)
+	bl("features.autoResume", &c.Features.AutoResume, f.Features.AutoResume)
+	bl("features.pprof", &c.Features.Pprof, f.Features.Pprof)
+	str("digest.daily", &c.Digest.Daily, f.Digest.Daily)
+	str("digest.weekly", &c.Digest.Weekly, f.Digest.Weekly)
+	bl("digest.writeDocs", &c.Digest.WriteDocs, f.Digest.WriteDocs)
+	if f.Summarizer.Providers != nil {
+		c.Summarizer.Providers = f.Summarizer.Providers
+		c.Sources["summarizer.providers"] = SourceFile
+	}
+	str("summarizer.openai.baseURL", &c.Summarizer.OpenAI.BaseURL, f.Summarizer.OpenAI.BaseURL)
+	str("summarizer.openai.model", &c.Summarizer.OpenAI.Model, f.Summarizer.OpenAI.Model)
+	str("summarizer.gemini.baseURL", &c.Summarizer.Gemini.BaseURL, f.Summarizer.Gemini.BaseURL)
+	str("summarizer.gemini.model", &c.Summarizer.Gemini.Model, f.Summarizer.Gemini.Model)
+	str("summarizer.local.baseURL", &c.Summarizer.Local.BaseURL, f.Summarizer.Local.BaseURL)
+	str("summarizer.local.model", &c.Summarizer.Local.Model, f.Summarizer.Local.Model)
+	str("summarizer.progressInterval", &c.Summarizer.ProgressInterval, f.Summarizer.ProgressInterval)
+	num("resume.settleSeconds", &c.Resume.SettleSeconds, f.Resume.SettleSeconds)
+	num("resume.maxAttempts", &c.Resume.MaxAttempts, f.Resume.MaxAttempts)
+	num("watchdog.stuckWindowMinutes", &c.Watchdog.StuckWindowMinutes, f.Watchdog.StuckWindowMinutes)
+	num("watchdog.repeatThreshold", &c.Watchdog.RepeatThreshold, f.Watchdog.RepeatThreshold)
+	return nil
+}
+
+// resolvePaths は "~" と相対パスを絶対パスにし、未指定の Root を検出、PatrolProjects の既定値を決めます
+func (c *Config) resolvePaths(homeDir, wd string) {
+	if c.Root == "" {
+		if root, ok := detectRoot(wd); ok {
+			c.Root = root
+			c.Sources["root"] = SourceDetected
+		}
+	} else {
+		c.Root = expandPath(c.Root, homeDir, wd)
+	}
+	c.ProjectBaseDir = expandPath(c.ProjectBaseDir, homeDir, wd)
+	c.StateDir = expandPath(c.StateDir, homeDir, wd)
+	if c.PatrolProjects == "" && c.Root != "" {
+		c.PatrolProjects = filepath.Join(c.Root, "devtools", "backend", "patrol_projects.json")
+	} else {
+		c.PatrolProjects = expandPath(c.PatrolProjects, homeDir, wd)
+	}
+}
+
+// Validate は設定値を検証します。問題はすべてまとめて返します
+func (c *Config) Validate() error {
+	var errs []error
+	requireDir := func(key, path string) {
+		if path == "" {
+			errs = append(errs, fmt.Errorf("%w: %s is required", ErrValidation, key))
+			return
+		}
+		info, err := os.Stat(path)
+		if err != nil || !info.IsDir() {
+			errs = append(errs, fmt.Errorf("%w: %s must be an existing directory: %s", ErrValidation, key, path))
+		}
+	}
+	if c.Root == "" {
+		errs = append(errs, fmt.Errorf("%w: root is required (set --root, GHOSTRUNNER_ROOT or root in the config file)", ErrValidation))
+	} else {
+		requireDir("root", c.Root)
+	}
+	requireDir("projectBaseDir", c.ProjectBaseDir)
+	if c.PatrolProjects != "" {
+		requireDir("patrolProjects directory", filepath.Dir(c.PatrolProjects))
+	}
+	if info, err := os.Stat(c.StateDir); err == nil && !info.IsDir() {
+		errs = append(errs, fmt.Errorf("%w: stateDir is not a directory: %s", ErrValidation, c.StateDir))
+	}
+
+	if _, port, err := net.SplitHostPort(c.Listen); err != nil {
+		errs = append(errs, fmt.Errorf("%w: listen must be host:port: %q", ErrValidation, c.Listen))
+	} else if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		errs = append(errs, fmt.Errorf("%w: listen port must be 1-65535: %q", ErrValidation, c.Listen))
+	}
+
+	for _, o := range c.AllowedOrigins {
+		if err := validateOrigin(o); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if c.PublicURL != "" {
+		u, err := url.Parse(c.PublicURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+			errs = append(errs, fmt.Errorf("%w: publicURL must be an http(s) base URL without path: %q", ErrValidation, c.PublicURL))
+		}
+		c.PublicURL = strings.TrimSuffix(c.PublicURL, "/")
+	}
+
+	if c.Digest.Daily != "" {
+		if _, err := time.Parse("15:04", c.Digest.Daily); err != nil {
+			errs = append(errs, fmt.Errorf("%w: digest.daily must be HH:MM: %q", ErrValidation, c.Digest.Daily))
+		}
+	}
+	if c.Digest.Weekly != "" {
+		if _, _, ok := ParseWeekly(c.Digest.Weekly); !ok {
+			errs = append(errs, fmt.Errorf("%w: digest.weekly must be \"<weekday> HH:MM\" (e.g. \"mon 09:00\"): %q", ErrValidation, c.Digest.Weekly))
+		}
+	}
+	errs = append(errs, c.Summarizer.validate()...)
+	if c.Resume.SettleSeconds < 0 {
+		errs = append(errs, fmt.Errorf("%w: resume.settleSeconds must be 0 or greater: %d", ErrValidation, c.Resume.SettleSeconds))
+	}
+	if c.Resume.MaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("%w: resume.maxAttempts must be at least 1: %d", ErrValidation, c.Resume.MaxAttempts))
+	}
+	if c.Watchdog.StuckWindowMinutes < 1 {
+		errs = append(errs, fmt.Errorf("%w: watchdog.stuckWindowMinutes must be at least 1: %d", ErrValidation, c.Watchdog.StuckWindowMinutes))
+	}
+	if c.Watchdog.RepeatThreshold < 1 {
+		errs = append(errs, fmt.Errorf("%w: watchdog.repeatThreshold must be at least 1: %d", ErrValidation, c.Watchdog.RepeatThreshold))
+	}
+	return errors.Join(errs...)
+}
+
+// validate は要約プロバイダーの指定を検証します（未知・重複の名前、local の接続先、URL の形式、進捗要約の間隔）
+func (s Summarizer) validate() []error {
+	var errs []error
+	if len(s.Providers) == 0 {
+		errs = append(errs, fmt.Errorf("%w: summarizer.providers must not be empty", ErrValidation))
+	}
+	seen := make(map[string]bool)
+	for _, p := range s.Providers {
+		switch {
+		case !slices.Contains(knownSummarizers, p):
+			errs = append(errs, fmt.Errorf("%w: summarizer.providers: unknown provider %q (want one of %s)", ErrValidation, p, strings.Join(knownSummarizers, ", ")))
+		case seen[p]:
+			errs = append(errs, fmt.Errorf("%w: summarizer.providers: duplicate provider %q", ErrValidation, p))
+		}
+		seen[p] = true
+	}
+	if seen["local"] && s.Local.BaseURL == "" {
+		errs = append(errs, fmt.Errorf("%w: summarizer.local.baseURL is required when providers include local", ErrValidation))
+	}
+	checkURL := func(key, raw string) {
+		if raw == "" {
+			return
+		}
+		if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("%w: summarizer.%s.baseURL must be an http(s) URL: %q", ErrValidation, key, raw))
+		}
+	}
+	checkURL("openai", s.OpenAI.BaseURL)
+	checkURL("gemini", s.Gemini.BaseURL)
+	checkURL("local", s.Local.BaseURL)
+	if d, err := time.ParseDuration(s.ProgressInterval); err != nil || (d != 0 && d < minProgressInterval) {
+		errs = append(errs, fmt.Errorf("%w: summarizer.progressInterval must be 0 or a duration of at least %s: %q", ErrValidation, minProgressInterval, s.ProgressInterval))
+	}
+	return errs
+}
+
+// ProgressEvery は進捗要約の最小間隔を返します（0 は進捗要約を行わない。検証済みの値を前提とします）
+func (s Summarizer) ProgressEvery() time.Duration {
+	d, err := time.ParseDuration(s.ProgressInterval)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// SettleDelay は計画書の最終更新から自動再開までに待つ時間を返します
+func (r Resume) SettleDelay() time.Duration {
+	return time.Duration(r.SettleSeconds) * time.Second
+}
+
+// StuckWindow は会話ログが伸びないまま停滞とみなすまでの時間を返します
+func (w Watchdog) StuckWindow() time.Duration {
+	return time.Duration(w.StuckWindowMinutes) * time.Minute
+}
+
+// ParseWeekly は "mon 09:00" 形式の曜日と時刻を解析します（曜日は英語の3文字または完全な名前、大文字小文字を問いません）
+func ParseWeekly(s string) (day time.Weekday, clock string, ok bool) {
+	name, clock, found := strings.Cut(strings.TrimSpace(s), " ")
+	if !found {
+		return 0, "", false
+	}
+	clock = strings.TrimSpace(clock)
+	if _, err := time.Parse("15:04", clock); err != nil {
+		return 0, "", false
+	}
+	name = strings.ToLower(name)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		full := strings.ToLower(d.String())
+		if name == full || name == full[:3] {
+			return d, clock, true
+		}
+	}
+	return 0, "", false
+}
+
+// AllowOrigin は origin が AllowedOrigins のいずれかに一致するかを返します
+func (c *Config) AllowOrigin(origin string) bool {
+	for _, pattern := range c.AllowedOrigins {
+		switch {
+		case strings.HasPrefix(pattern, "*"):
+			if strings.HasSuffix(origin, pattern[1:]) {
+				return true
+			}
+		case strings.HasSuffix(pattern, "*"):
+			if strings.HasPrefix(origin, pattern[:len(pattern)-1]) {
+				return true
+			}
+		case origin == pattern:
+			return true
+		}
+	}
+	return false
+}
+
+// ListensOnLoopback は Listen がループバックのみで待ち受けるかを返します（ホスト省略・0.0.0.0 は全インターフェース）
+func (c *Config) ListensOnLoopback() bool {
+	host, _, err := net.SplitHostPort(c.Listen)
+	if err != nil || host == "" {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// AuthConfig は端末認証の設定を返します（features.auth の否定が Disabled、端末の登録先は <stateDir>/devices.json）
+func (c *Config) AuthConfig() auth.Config {
+	return auth.Config{
+		Disabled:      !c.Features.Auth,
+		TrustLoopback: c.Features.TrustLoopback,
+		DevicesPath:   c.StatePath("devices.json"),
+	}
+}
+
+// StatePath は StateDir 配下のパスを返します
+func (c *Config) StatePath(elem ...string) string {
+	return filepath.Join(append([]string{c.StateDir}, elem...)...)
+}
+
+// validateOrigin は Origin のパターンを検証します。"*" は先頭か末尾に1つだけ置けます（全許可の "*" は不可）
+func validateOrigin(pattern string) error {
+	n := strings.Count(pattern, "*")
+	switch {
+	case pattern == "" || pattern == "*":
+		return fmt.Errorf("%w: allowedOrigins must not be empty or \"*\": %q", ErrValidation, pattern)
+	case n > 1, n == 1 && !strings.HasPrefix(pattern, "*") && !strings.HasSuffix(pattern, "*"):
+		return fmt.Errorf("%w: allowedOrigins wildcard must be a single leading or trailing \"*\": %q", ErrValidation, pattern)
+	}
+	return nil
+}
+
+// detectRoot は dir から親へ辿り、rootMarker を持つディレクトリを探します
+func detectRoot(dir string) (string, bool) {
+	for dir != "" {
+		if _, err := os.Stat(filepath.Join(dir, rootMarker)); err == nil {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return "", false
+}
+
+// expandPath は先頭の "~" をホームディレクトリに、相対パスを wd 基準の絶対パスにします
+func expandPath(path, homeDir, wd string) string {
+	if path == "" {
+		return ""
+	}
+	if path == "~" {
+		path = homeDir
+	} else if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		path = filepath.Join(homeDir, rest)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(wd, path)
+	}
+	return filepath.Clean(path)
+}
+
+// splitList はカンマ区切りの値を空要素を除いて分割します
+func splitList(raw string) []string {
+	var out []string
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,256 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// newTree は Root として検出されるリポジトリとホームディレクトリを作ります
+func newTree(t *testing.T) (root, home string) {
+	t.Helper()
+	base := t.TempDir()
+	root = filepath.Join(base, "ghostrunner")
+	home = filepath.Join(base, "home")
+	for _, dir := range []string{filepath.Join(root, "devtools", "backend"), home} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, rootMarker), []byte("module x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return root, home
+}
+
+func envOf(m map[string]string) func(string) string {
+	return func(k string) string { return m[k] }
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	root, home := newTree(t)
+	cfg, err := Load(nil, envOf(nil), home, filepath.Join(root, "devtools", "backend"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Root != root || cfg.Sources["root"] != SourceDetected {
+		t.Errorf("root = %s (%s), want %s detected", cfg.Root, cfg.Sources["root"], root)
+	}
+	if cfg.Listen != defaultListen || cfg.ProjectBaseDir != home {
+		t.Errorf("listen = %s, projectBaseDir = %s", cfg.Listen, cfg.ProjectBaseDir)
+	}
+	if cfg.StateDir != filepath.Join(home, ".ghostrunner") {
+		t.Errorf("stateDir = %s", cfg.StateDir)
+	}
+	if info, err := os.Stat(cfg.StateDir); err != nil || !info.IsDir() {
+		t.Errorf("stateDir should be created: %v", err)
+	}
+	if cfg.PatrolProjects != filepath.Join(root, "devtools", "backend", "patrol_projects.json") {
+		t.Errorf("patrolProjects = %s", cfg.PatrolProjects)
+	}
+	if cfg.Features.Auth || !cfg.Features.TrustLoopback || cfg.Features.AutoResume || cfg.Features.Pprof {
+		t.Errorf("features = %+v", cfg.Features)
+	}
+	if cfg.File != "" {
+		t.Errorf("file = %s, want none", cfg.File)
+	}
+	if !slices.Equal(cfg.Summarizer.Providers, []string{"claude", "extractive"}) {
+		t.Errorf("summarizer.providers = %v", cfg.Summarizer.Providers)
+	}
+	if cfg.Summarizer.ProgressEvery() != 5*time.Minute {
+		t.Errorf("summarizer.progressInterval = %s", cfg.Summarizer.ProgressInterval)
+	}
+	if cfg.Resume.SettleDelay() != time.Minute || cfg.Resume.MaxAttempts != 5 {
+		t.Errorf("resume = %+v", cfg.Resume)
+	}
+	if cfg.Watchdog.StuckWindow() != 10*time.Minute || cfg.Watchdog.RepeatThreshold != 3 {
+		t.Errorf("watchdog = %+v", cfg.Watchdog)
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	root, home := newTree(t)
+	state := filepath.Join(home, "state")
+	if err := os.MkdirAll(state, 0755); err != nil {
+		t.Fatal(err)
+	}
+	file := "listen: 127.0.0.1:9000\nprojectBaseDir: ~/\nallowedOrigins: [\"https://*\"]\nfeatures:\n  auth: true\n  autoResume: true\n  pprof: true\n" +
+		"summarizer:\n  providers: [local, extractive]\n  local:\n    baseURL: http://localhost:11434/v1\n    model: qwen\n" +
+		"resume:\n  settleSeconds: 0\n  maxAttempts: 2\nwatchdog:\n  stuckWindowMinutes: 20\n"
+	if err := os.WriteFile(filepath.Join(state, defaultConfigFile), []byte(file), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	env := envOf(map[string]string{
+		"GHOSTRUNNER_STATE_DIR":              state,
+		"GHOSTRUNNER_ROOT":                   root,
+		"GHOSTRUNNER_LISTEN":                 "127.0.0.1:9100",
+		"AUTH_DISABLED":                      "true",
+		"GHOSTRUNNER_SUMMARIZER_LOCAL_MODEL": "llama",
+		"AUTO_RESUME_MAX_ATTEMPTS":           "3",
+	})
+	cfg, err := Load([]string{"--listen", "127.0.0.1:9200", "--pprof=false"}, env, home, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.File != filepath.Join(state, defaultConfigFile) {
+		t.Errorf("file = %s", cfg.File)
+	}
+	checks := []struct {
+		key  string
+		got  any
+		want any
+		src  Source
+	}{
+		{"listen", cfg.Listen, "127.0.0.1:9200", SourceFlag},
+		{"root", cfg.Root, root, SourceEnv},
+		{"projectBaseDir", cfg.ProjectBaseDir, home, SourceFile},
+		{"features.auth", cfg.Features.Auth, false, SourceEnv},
+		{"features.autoResume", cfg.Features.AutoResume, true, SourceFile},
+		{"features.pprof", cfg.Features.Pprof, false, SourceFlag},
+		{"features.trustLoopback", cfg.Features.TrustLoopback, true, SourceDefault},
+		{"summarizer.local.baseURL", cfg.Summarizer.Local.BaseURL, "http://localhost:11434/v1", SourceFile},
+		{"summarizer.local.model", cfg.Summarizer.Local.Model, "llama", SourceEnv},
+		{"resume.settleSeconds", cfg.Resume.SettleSeconds, 0, SourceFile},
+		{"resume.maxAttempts", cfg.Resume.MaxAttempts, 3, SourceEnv},
+		{"watchdog.stuckWindowMinutes", cfg.Watchdog.StuckWindowMinutes, 20, SourceFile},
+		{"watchdog.repeatThreshold", cfg.Watchdog.RepeatThreshold, 3, SourceDefault},
+	}
+	for _, c := range checks {
+		if c.got != c.want || cfg.Sources[c.key] != c.src {
+			t.Errorf("%s = %v (%s), want %v (%s)", c.key, c.got, cfg.Sources[c.key], c.want, c.src)
+		}
+	}
+	if !slices.Equal(cfg.AllowedOrigins, []string{"https://*"}) {
+		t.Errorf("allowedOrigins = %v", cfg.AllowedOrigins)
+	}
+	if !slices.Equal(cfg.Summarizer.Providers, []string{"local", "extractive"}) || cfg.Sources["summarizer.providers"] != SourceFile {
+		t.Errorf("summarizer.providers = %v (%s)", cfg.Summarizer.Providers, cfg.Sources["summarizer.providers"])
+	}
+}
+
+func TestLoad_Validation(t *testing.T) {
+	root, home := newTree(t)
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+	}{
+		{name: "root not detected", args: nil},
+		{name: "root missing", args: []string{"--root", filepath.Join(root, "nope")}},
+		{name: "listen without port", args: []string{"--root", root, "--listen", "0.0.0.0"}},
+		{name: "listen port out of range", args: []string{"--root", root, "--listen", ":70000"}},
+		{name: "project base dir missing", args: []string{"--root", root, "--project-base-dir", "/nonexistent/dir"}},
+		{name: "wildcard origin", args: []string{"--root", root, "--allowed-origins", "*"}},
+		{name: "wildcard in the middle", args: []string{"--root", root, "--allowed-origins", "http://*.example.com:3000"}},
+		{name: "invalid boolean env", args: []string{"--root", root}, env: map[string]string{"AUTO_RESUME": "yes please"}},
+		{name: "unknown flag", args: []string{"--port", "80"}},
+		{name: "public url with path", args: []string{"--root", root, "--public-url", "https://mac.example.ts.net/api"}},
+		{name: "digest weekly without weekday", args: []string{"--root", root, "--digest-weekly", "09:00"}},
+		{name: "digest daily out of range", args: []string{"--root", root}, env: map[string]string{"GHOSTRUNNER_DIGEST_DAILY": "24:30"}},
+		{name: "unknown summarizer", args: []string{"--root", root, "--summarizer", "claude,llama"}},
+		{name: "local summarizer without url", args: []string{"--root", root}, env: map[string]string{"GHOSTRUNNER_SUMMARIZER": "local,extractive"}},
+		{name: "progress interval too short", args: []string{"--root", root, "--summarizer-progress-interval", "30s"}},
+		{name: "invalid integer env", args: []string{"--root", root}, env: map[string]string{"AUTO_RESUME_MAX_ATTEMPTS": "abc"}},
+		{name: "negative settle seconds", args: []string{"--root", root}, env: map[string]string{"AUTO_RESUME_SETTLE_SECONDS": "-1"}},
+		{name: "zero stuck window", args: []string{"--root", root}, env: map[string]string{"STUCK_WINDOW_MINUTES": "0"}},
+		{name: "explicit config file missing", args: []string{"--root", root, "--config", filepath.Join(home, "missing.yaml")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.args, envOf(tt.env), home, t.TempDir())
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if tt.name != "explicit config file missing" && !errors.Is(err, ErrValidation) {
+				t.Errorf("err = %v, want ErrValidation", err)
+			}
+		})
+	}
+}
+
+func TestLoad_UnknownFileKey(t *testing.T) {
+	root, home := newTree(t)
+	path := filepath.Join(home, "config.yaml")
+	if err := os.WriteFile(path, []byte("lisen: :9000\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load([]string{"--root", root, "--config", path}, envOf(nil), home, home); !errors.Is(err, ErrValidation) {
+		t.Errorf("err = %v, want ErrValidation for unknown key", err)
+	}
+}
+
+func TestResolveStateDir(t *testing.T) {
+	home := t.TempDir()
+	path := filepath.Join(home, "gr.yaml")
+	if err := os.WriteFile(path, []byte("stateDir: ~/from-file\nlisten: :9000\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		flag    string
+		env     map[string]string
+		want    string
+		wantErr bool
+	}{
+		{name: "既定", want: filepath.Join(home, ".ghostrunner")},
+		{name: "設定ファイル", env: map[string]string{"GHOSTRUNNER_CONFIG": path}, want: filepath.Join(home, "from-file")},
+		{name: "環境変数が設定ファイルより優先", env: map[string]string{"GHOSTRUNNER_CONFIG": path, "GHOSTRUNNER_STATE_DIR": "~/from-env"}, want: filepath.Join(home, "from-env")},
+		{name: "引数が最優先", flag: "/srv/state", env: map[string]string{"GHOSTRUNNER_STATE_DIR": "~/from-env"}, want: "/srv/state"},
+		{name: "明示した設定ファイルが無い", env: map[string]string{"GHOSTRUNNER_CONFIG": filepath.Join(home, "missing.yaml")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveStateDir(tt.flag, "", envOf(tt.env), home, home)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveStateDir = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_AllowOrigin(t *testing.T) {
+	cfg := &Config{AllowedOrigins: defaultAllowedOrigins}
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"http://localhost:3333", true},
+		{"http://localhost:4000", false},
+		{"http://100.64.0.1:3333", true},
+		{"https://box.tail1234.ts.net", true},
+		{"https://evil.example.com", false},
+	}
+	for _, tt := range tests {
+		if got := cfg.AllowOrigin(tt.origin); got != tt.want {
+			t.Errorf("AllowOrigin(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
+
+func TestConfig_ListensOnLoopback(t *testing.T) {
+	tests := []struct {
+		listen string
+		want   bool
+	}{
+		{defaultListen, false},
+		{":8888", false},
+		{"100.64.0.1:8888", false},
+		{"127.0.0.1:8888", true},
+		{"[::1]:8888", true},
+		{"localhost:8888", true},
+	}
+	for _, tt := range tests {
+		cfg := &Config{Listen: tt.listen}
+		if got := cfg.ListensOnLoopback(); got != tt.want {
+			t.Errorf("ListensOnLoopback(%q) = %v, want %v", tt.listen, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Package config はAPIサーバーの設定（設定ファイル・環境変数・コマンドライン引数）を提供する。
+//
+// # 概要
+//
+// リポジトリのルート、待ち受けアドレス、プロジェクトのベースディレクトリ、状態の格納先、許可する Origin、
+// 機能の有効・無効を1つの Config にまとめる。ソースツリーの外にインストールしたバイナリでも動くよう、
+// ルートは明示指定か起動ディレクトリからの検出で決め、ソースファイルの位置（runtime.Caller）には依存しない。
+// 起動時に検証し、実効値と各値の出どころは GET /api/config で確認できる。
+//
+// 設定ファイルの例（~/.ghostrunner/config.yaml）:
+//
+//	root: /opt/ghostrunner
+//	listen: 0.0.0.0:8888
+//	projectBaseDir: ~/work
+//	stateDir: ~/.ghostrunner
+//	allowedOrigins:
+//	  - http://localhost:3333
+//	  - http://100.*
+//	  - "*.ts.net"
+//	digest:
+//	  daily: "18:00"
+//	  weekly: mon 09:00
+//	features:
+//	  auth: true
+//	  autoResume: false
+//	resume:
+//	  settleSeconds: 60
+//	  maxAttempts: 5
+//	watchdog:
+//	  stuckWindowMinutes: 10
+//	  repeatThreshold: 3
+//
+// # 主要な型・関数
+//
+//   - Config / Features / Digest / Summarizer / Resume / Watchdog: 設定値（Digest は活動ダイジェストの定期配信、Summarizer は質問待ち・進捗要約のプロバイダーと進捗要約の間隔、
+//     Resume は自動再開の待ち時間と試行回数の上限、Watchdog は停滞検出のしきい値）。Sources に各値の出どころ（default / detected / file / env / flag）を持つ
+//   - Load: 既定値 < 設定ファイル < 環境変数 < 引数 の順に組み立て、パスを絶対化して検証し、StateDir を作成する
+//   - Config.Validate: ディレクトリの存在、listen の host:port、Origin のパターン、ダイジェストの時刻、要約プロバイダーの名前と接続先、自動再開・停滞検出の数値の範囲を検証する（問題はまとめて返す）
+//   - ParseWeekly: digest.weekly（"mon 09:00"）を曜日と時刻に分解する
+//   - Config.AllowOrigin / Config.StatePath: CORS・WebSocket の Origin 判定と、StateDir 配下のパス
+//
+// # 設計方針
+//
+//   - 既存の環境変数（AUTH_DISABLED / AUTH_TRUST_LOOPBACK / AUTO_RESUME / ENABLE_PPROF、AUTO_RESUME_SETTLE_SECONDS / AUTO_RESUME_MAX_ATTEMPTS、
+//     STUCK_WINDOW_MINUTES / STUCK_REPEAT_THRESHOLD）はそのまま使える。読むのはこのパッケージだけで、grrun・dashboard は Config から組み立てた値を受け取る
+//   - 真偽値・整数として解釈できない値や範囲外の値は既定値へ黙って戻さず、起動エラーにする
+//   - 設定ファイルは未知のキーをエラーにする（綴り間違いで既定値のまま動くのを防ぐ）。既定の場所のファイルは無くてもよいが、
+//     --config / GHOSTRUNNER_CONFIG で指定したファイルが無い場合はエラー
+//   - Origin の "*" は先頭（後方一致）か末尾（前方一致）に1つだけ。認証情報付きの CORS を許すため全許可の "*" は受け付けない
+//   - API キー等の秘匿値は従来どおり各サービスが環境変数から読み、Config には含めない（/api/config で露出しない）
+package config
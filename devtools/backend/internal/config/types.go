@@ -0,0 +1,120 @@
+package config
+
+import "errors"
+
+// ErrValidation は設定値が不正であることを示します
+var ErrValidation = errors.New("validation error")
+
+// Source は設定値の出どころです
+type Source string
+
+const (
+	// SourceDefault は既定値です
+	SourceDefault Source = "default"
+	// SourceDetected は起動ディレクトリから検出した値です（Root のみ）
+	SourceDetected Source = "detected"
+	// SourceFile は設定ファイル（config.yaml）です
+	SourceFile Source = "file"
+	// SourceEnv は環境変数です
+	SourceEnv Source = "env"
+	// SourceFlag はコマンドライン引数です
+	SourceFlag Source = "flag"
+)
+
+// Config はサーバーの設定です。既定値 < 設定ファイル < 環境変数 < コマンドライン引数 の順に上書きします
+type Config struct {
+	// Root は Ghostrunner リポジトリのルート（.claude/commands、テンプレート、自プロジェクトの判定に使用）
+	Root string `yaml:"root" json:"root"`
+	// Listen は待ち受けアドレス（host:port）
+	Listen string `yaml:"listen" json:"listen"`
+	// ProjectBaseDir はプロジェクトの一覧・生成・削除の対象ディレクトリ
+	ProjectBaseDir string `yaml:"projectBaseDir" json:"projectBaseDir"`
+	// StateDir はサーバーの状態（監査ログ・履歴・索引・端末・gr-run のロックと実行履歴）の格納先
+	StateDir string `yaml:"stateDir" json:"stateDir"`
+	// PatrolProjects は登録プロジェクトの一覧ファイル（patrol_projects.json）
+	PatrolProjects string `yaml:"patrolProjects" json:"patrolProjects"`
+	// AllowedOrigins は CORS と WebSocket で許可する Origin。
+	// 完全一致、"http://100.*"（前方一致）、"*.ts.net"（後方一致）を指定できます
+	AllowedOrigins []string `yaml:"allowedOrigins" json:"allowedOrigins"`
+	// PublicURL は端末（スマートフォン等）から到達できるこのサーバーのベース URL（例: https://mac.tailnet.ts.net:8888）。
+	// 通知のアクションボタンのコールバック URL に使い、空の場合はアクションボタンを付けません
+	PublicURL string `yaml:"publicURL" json:"publicURL,omitempty"`
+	// Features は機能の有効・無効です
+	Features Features `yaml:"features" json:"features"`
+	// Digest は活動ダイジェストの定期配信です
+	Digest Digest `yaml:"digest" json:"digest"`
+	// Summarizer は質問待ち・動作中の進捗の要約に使うプロバイダーです
+	Summarizer Summarizer `yaml:"summarizer" json:"summarizer"`
+	// Resume は回答待ちタスクの自動再開の調整です（有効・無効は features.autoResume）
+	Resume Resume `yaml:"resume" json:"resume"`
+	// Watchdog は動作中セッションの停滞検出のしきい値です
+	Watchdog Watchdog `yaml:"watchdog" json:"watchdog"`
+
+	// File は読み込んだ設定ファイルのパスです（無ければ空）
+	File string `yaml:"-" json:"file,omitempty"`
+	// Sources は各設定値の出どころです（キーは "listen"、"features.auth" 等）
+	Sources map[string]Source `yaml:"-" json:"sources"`
+}
+
+// Features は機能の有効・無効です
+type Features struct {
+	// Auth は端末認証を行うか（既定 false。同梱のフロントエンドは Next.js の rewrite 経由でトークンを送らないため、
+	// 有効にすると UI の要求がすべて 401 になる。features.auth: true / AUTH_DISABLED=false で有効）
+	Auth bool `yaml:"auth" json:"auth"`
+	// TrustLoopback はプロキシを経由しないループバックをホスト本人として扱うか（既定 true）
+	TrustLoopback bool `yaml:"trustLoopback" json:"trustLoopback"`
+	// AutoResume は回答待ちタスクの自動再開を行うか（既定 false）
+	AutoResume bool `yaml:"autoResume" json:"autoResume"`
+	// Pprof は /debug/pprof を公開するか（既定 false）
+	Pprof bool `yaml:"pprof" json:"pprof"`
+}
+
+// Digest は活動ダイジェスト（完了タスク・要確認の実行・質問・トークン使用量・停滞した運用）の定期配信です
+type Digest struct {
+	// Daily は直近24時間のダイジェストを毎日通知する時刻（"HH:MM"。空なら送らない）
+	Daily string `yaml:"daily" json:"daily,omitempty"`
+	// Weekly は直近7日のダイジェストを毎週通知する曜日と時刻（"mon 09:00"。空なら送らない）
+	Weekly string `yaml:"weekly" json:"weekly,omitempty"`
+	// WriteDocs は定期配信時に各プロジェクトの 開発/資料 へ Markdown を書き出すか（既定 false）
+	WriteDocs bool `yaml:"writeDocs" json:"writeDocs"`
+}
+
+// Summarizer は質問待ち・動作中の進捗の要約に使うプロバイダーの選択です。API キーは環境変数から読み、ここには持ちません
+type Summarizer struct {
+	// Providers は試す順のプロバイダー（claude / openai / gemini / local / extractive）。
+	// 前のプロバイダーがエラーになると次を試します（既定 [claude, extractive]）
+	Providers []string `yaml:"providers" json:"providers"`
+	// OpenAI は OpenAI の接続先（空ならプロバイダーの既定値）
+	OpenAI SummarizerEndpoint `yaml:"openai" json:"openai"`
+	// Gemini は Gemini の接続先（空ならプロバイダーの既定値）
+	Gemini SummarizerEndpoint `yaml:"gemini" json:"gemini"`
+	// Local は OpenAI 互換のローカルエンドポイント（Ollama・LM Studio 等。providers に local を含む場合 BaseURL は必須）
+	Local SummarizerEndpoint `yaml:"local" json:"local"`
+	// ProgressInterval は動作中セッションの進捗（いま何をしているか／済んだこと）を再要約する最小間隔です
+	// （"5m" 等の duration。既定 "5m"、"0" で進捗要約を行いません。1分未満は指定できません）
+	ProgressInterval string `yaml:"progressInterval" json:"progressInterval"`
+}
+
+// Resume は回答待ちタスクの自動再開の調整です
+type Resume struct {
+	// SettleSeconds は計画書の最終更新から再開までに待つ秒数です（回答途中での再開を避ける。既定 60、0 以上）
+	SettleSeconds int `yaml:"settleSeconds" json:"settleSeconds"`
+	// MaxAttempts は1タスクあたりの実行回数の上限です（初回実行を含む。到達したタスクは再開しない。既定 5、1 以上）
+	MaxAttempts int `yaml:"maxAttempts" json:"maxAttempts"`
+}
+
+// Watchdog は動作中セッションの停滞検出のしきい値です
+type Watchdog struct {
+	// StuckWindowMinutes は生成途中のまま会話ログが伸びないセッションを停滞とみなすまでの分数です（既定 10、1 以上）
+	StuckWindowMinutes int `yaml:"stuckWindowMinutes" json:"stuckWindowMinutes"`
+	// RepeatThreshold は同じツール呼び出しの連続失敗を停滞とみなす回数です（既定 3、1 以上）
+	RepeatThreshold int `yaml:"repeatThreshold" json:"repeatThreshold"`
+}
+
+// SummarizerEndpoint は HTTP の要約プロバイダーの接続先です
+type SummarizerEndpoint struct {
+	// BaseURL は API のベース URL（例: http://localhost:11434/v1）
+	BaseURL string `yaml:"baseURL" json:"baseURL,omitempty"`
+	// Model はモデル名
+	Model string `yaml:"model" json:"model,omitempty"`
+}
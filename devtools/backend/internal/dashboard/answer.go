@@ -6,7 +6,10 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
+	"ghostrunner/backend/internal/plandoc"
 	"ghostrunner/backend/internal/projects"
 )
 
@@ -16,18 +19,107 @@ var ErrAlreadyAnswered = errors.New("already answered or line shifted")
 // ErrValidation はバリデーションエラーです
 var ErrValidation = errors.New("validation error")
 
+// AnswerSource は回答の入力元です。計画書の「**回答元**:」に記録します。
+type AnswerSource string
+
+const (
+	// AnswerSourceDashboard はダッシュボードの回答フォームです（省略時の既定）
+	AnswerSourceDashboard AnswerSource = "dashboard"
+	// AnswerSourceVoice は音声入力（統括GUIの音声対話）です
+	AnswerSourceVoice AnswerSource = "voice"
+	// AnswerSourceNtfy は ntfy 通知のアクションです
+	AnswerSourceNtfy AnswerSource = "ntfy"
+)
+
+// MaxBatchAnswers は1回の一括回答で指定できる回答数の上限です
+const MaxBatchAnswers = 50
+
 // AnswerRequest は確認事項への回答リクエストを表します
 type AnswerRequest struct {
 	ProjectPath string `json:"projectPath"`
 	PlanPath    string `json:"planPath"`
-	LineStart   int    `json:"lineStart"`
-	Answer      string `json:"answer"`
+	// QuestionID は確認事項の ID（例: Q1）。指定時は LineStart より優先し、LineStart は省略可
+	QuestionID string       `json:"questionId,omitempty"`
+	LineStart  int          `json:"lineStart"`
+	Answer     string       `json:"answer"`
+	Source     AnswerSource `json:"source,omitempty"`
+}
+
+// BatchAnswerRequest は1つの計画書への複数の回答リクエストを表します
+type BatchAnswerRequest struct {
+	ProjectPath string       `json:"projectPath"`
+	PlanPath    string       `json:"planPath"`
+	Source      AnswerSource `json:"source,omitempty"`
+	Answers     []AnswerItem `json:"answers"`
+}
+
+// AnswerItem は一括回答の1件です（確認事項は QuestionID または LineStart で指定）
+type AnswerItem struct {
+	QuestionID string `json:"questionId,omitempty"`
+	LineStart  int    `json:"lineStart,omitempty"`
+	Answer     string `json:"answer"`
+}
+
+// planLocks は計画書ごとの書き込みロックです（キーは計画書の絶対パス）。
+// ダッシュボード・音声・ntfy・自動回答から同じ計画書へ同時に回答しても、読み込みから書き戻しまでを直列化して
+// 後から書いた側が先の回答を消さないようにします。使用中の間だけ保持し、参照が無くなったら削除します。
+var planLocks = struct {
+	mu sync.Mutex
+	m  map[string]*planLock
+}{m: make(map[string]*planLock)}
+
+// planLock は1つの計画書のロックと、待機中を含む参照数です
+type planLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// lockPlan は計画書 path のロックを取得し、解放する関数を返します
+func lockPlan(path string) (unlock func()) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	planLocks.mu.Lock()
+	l, ok := planLocks.m[path]
+	if !ok {
+		l = &planLock{}
+		planLocks.m[path] = l
+	}
+	l.refs++
+	planLocks.mu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		planLocks.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(planLocks.m, path)
+		}
+		planLocks.mu.Unlock()
+	}
+}
+
+// AnswerQuestion は確認事項に回答を書き戻します。now は「**回答日時**:」に記録する時刻です。
+func AnswerQuestion(req AnswerRequest, allowedProjects []projects.Project, now time.Time) error {
+	return AnswerQuestions(BatchAnswerRequest{
+		ProjectPath: req.ProjectPath,
+		PlanPath:    req.PlanPath,
+		Source:      req.Source,
+		Answers: []AnswerItem{{
+			QuestionID: req.QuestionID,
+			LineStart:  req.LineStart,
+			Answer:     req.Answer,
+		}},
+	}, allowedProjects, now)
 }
 
-// AnswerQuestion は確認事項に回答を書き戻します
-func AnswerQuestion(req AnswerRequest, allowedProjects []projects.Project) error {
+// AnswerQuestions は1つの計画書の複数の確認事項に回答を書き戻します。
+// 全件を1回のアトミック書き込みで反映し、1件でも対象が見つからなければ何も書き込みません。
+// 同じ計画書への回答は読み込みから書き戻しまでを直列化します。
+func AnswerQuestions(req BatchAnswerRequest, allowedProjects []projects.Project, now time.Time) error {
 	// バリデーション
-	if err := validateAnswerRequest(req, allowedProjects); err != nil {
+	if err := validateBatchAnswerRequest(req, allowedProjects); err != nil {
 		return err
 	}
 
@@ -39,76 +131,44 @@ func AnswerQuestion(req AnswerRequest, allowedProjects []projects.Project) error
 		return fmt.Errorf("%w: path traversal detected", ErrValidation)
 	}
 
+	// 読み込みから書き戻しまでの間に他の回答が割り込まないよう、計画書ごとに直列化する
+	unlock := lockPlan(absPath)
+	defer unlock()
+
 	// ファイル読み込み
 	data, err := os.ReadFile(absPath)
 	if err != nil {
 		return fmt.Errorf("failed to read plan file: %w", err)
 	}
 
-	lines := strings.Split(string(data), "\n")
-
-	// 検索ウィンドウ内で未回答行を探す(LineStart-2 ~ LineStart+2、0-indexed)
-	targetIdx := req.LineStart - 1 // 0-based
-	windowStart := targetIdx - 2
-	if windowStart < 0 {
-		windowStart = 0
-	}
-	windowEnd := targetIdx + 2
-	if windowEnd >= len(lines) {
-		windowEnd = len(lines) - 1
-	}
-
-	// ウィンドウ内の未回答行を検索
-	matchIdx := -1
-	bestDist := len(lines)
-	for i := windowStart; i <= windowEnd; i++ {
-		if unansweredRe.MatchString(lines[i]) {
-			dist := i - targetIdx
-			if dist < 0 {
-				dist = -dist
-			}
-			if dist < bestDist {
-				bestDist = dist
-				matchIdx = i
-			}
-		}
+	source := req.Source
+	if source == "" {
+		source = AnswerSourceDashboard
 	}
-
-	if matchIdx < 0 {
-		return ErrAlreadyAnswered
+	answers := make([]plandoc.Answer, 0, len(req.Answers))
+	for _, a := range req.Answers {
+		answers = append(answers, plandoc.Answer{
+			QuestionID: a.QuestionID,
+			Line:       a.LineStart,
+			Text:       a.Answer,
+			AnsweredAt: now,
+			Source:     string(source),
+		})
 	}
 
-	// ステータスを回答済みに変更
-	lines[matchIdx] = strings.Replace(lines[matchIdx], "未回答", "回答済", 1)
-
-	// 回答行の挿入・置換
-	answerLine := fmt.Sprintf("**回答**: %s", strings.TrimSpace(req.Answer))
-	nextIdx := matchIdx + 1
-
-	if nextIdx >= len(lines) {
-		// ファイル末尾の場合は追加
-		lines = append(lines, answerLine)
-	} else if strings.TrimSpace(lines[nextIdx]) == "" {
-		// 空行の場合は回答行を挿入
-		newLines := make([]string, 0, len(lines)+1)
-		newLines = append(newLines, lines[:nextIdx]...)
-		newLines = append(newLines, answerLine)
-		newLines = append(newLines, lines[nextIdx:]...)
-		lines = newLines
-	} else if strings.HasPrefix(strings.TrimSpace(lines[nextIdx]), "**回答**:") {
-		// 既存の回答行を置換
-		lines[nextIdx] = answerLine
-	} else {
-		// その他（見出し等）の場合は手前に挿入
-		newLines := make([]string, 0, len(lines)+1)
-		newLines = append(newLines, lines[:nextIdx]...)
-		newLines = append(newLines, answerLine)
-		newLines = append(newLines, lines[nextIdx:]...)
-		lines = newLines
+	// QuestionID 指定は ID で、それ以外は LineStart の前後 plandoc.AnswerWindow 行以内で未回答を特定する
+	updated, err := plandoc.ApplyAnswers(data, answers)
+	if err != nil {
+		if errors.Is(err, plandoc.ErrNoUnanswered) {
+			return fmt.Errorf("%w: %v", ErrAlreadyAnswered, err)
+		}
+		if errors.Is(err, plandoc.ErrDuplicateAnswer) {
+			return fmt.Errorf("%w: %v", ErrValidation, err)
+		}
+		return fmt.Errorf("failed to apply answers: %w", err)
 	}
 
 	// アトミック書き込み（同一ディレクトリにtmpファイルを作成してrename）
-	content := strings.Join(lines, "\n")
 	dir := filepath.Dir(absPath)
 	tmpFile, err := os.CreateTemp(dir, ".plan.tmp.*")
 	if err != nil {
@@ -116,7 +176,7 @@ func AnswerQuestion(req AnswerRequest, allowedProjects []projects.Project) error
 	}
 	tmpPath := tmpFile.Name()
 
-	if _, err := tmpFile.WriteString(content); err != nil {
+	if _, err := tmpFile.Write(updated); err != nil {
 		tmpFile.Close()
 		os.Remove(tmpPath)
 		return fmt.Errorf("failed to write temp file: %w", err)
@@ -134,8 +194,8 @@ func AnswerQuestion(req AnswerRequest, allowedProjects []projects.Project) error
 	return nil
 }
 
-// validateAnswerRequest はリクエストのバリデーションを行います
-func validateAnswerRequest(req AnswerRequest, allowedProjects []projects.Project) error {
+// validateBatchAnswerRequest はリクエストのバリデーションを行います
+func validateBatchAnswerRequest(req BatchAnswerRequest, allowedProjects []projects.Project) error {
 	// projectPathが許可リストに含まれるか
 	allowed := false
 	cleanReqPath := filepath.Clean(req.ProjectPath)
@@ -171,14 +231,31 @@ func validateAnswerRequest(req AnswerRequest, allowedProjects []projects.Project
 		return fmt.Errorf("%w: plan path must be .md file: %s", ErrValidation, req.PlanPath)
 	}
 
-	// LineStartチェック
-	if req.LineStart < 1 {
-		return fmt.Errorf("%w: lineStart must be >= 1", ErrValidation)
+	// Sourceチェック
+	switch req.Source {
+	case "", AnswerSourceDashboard, AnswerSourceVoice, AnswerSourceNtfy:
+	default:
+		return fmt.Errorf("%w: unknown source: %s", ErrValidation, req.Source)
+	}
+
+	// 件数チェック
+	if len(req.Answers) == 0 {
+		return fmt.Errorf("%w: answers must not be empty", ErrValidation)
 	}
+	if len(req.Answers) > MaxBatchAnswers {
+		return fmt.Errorf("%w: too many answers: %d (max %d)", ErrValidation, len(req.Answers), MaxBatchAnswers)
+	}
+
+	for i, a := range req.Answers {
+		// LineStartチェック（QuestionID 指定時は不要）
+		if a.QuestionID == "" && a.LineStart < 1 {
+			return fmt.Errorf("%w: answers[%d]: lineStart must be >= 1", ErrValidation, i)
+		}
 
-	// Answerチェック
-	if strings.TrimSpace(req.Answer) == "" {
-		return fmt.Errorf("%w: answer must not be empty", ErrValidation)
+		// Answerチェック
+		if strings.TrimSpace(a.Answer) == "" {
+			return fmt.Errorf("%w: answers[%d]: answer must not be empty", ErrValidation, i)
+		}
 	}
 
 	return nil
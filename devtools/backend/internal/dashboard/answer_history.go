@@ -0,0 +1,157 @@
+package dashboard
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"ghostrunner/backend/internal/plandoc"
+	"ghostrunner/backend/internal/projects"
+)
+
+const (
+	// defaultAnswerHistoryLimit は回答履歴の既定の件数です
+	defaultAnswerHistoryLimit = 100
+	// maxAnswerHistoryLimit は回答履歴の件数の上限です
+	maxAnswerHistoryLimit = 1000
+)
+
+// answerHistoryDirs は回答履歴を探す計画書のディレクトリです（回答後に完了・アーカイブへ移った計画書も含む）
+var answerHistoryDirs = []string{
+	filepath.Join("開発", "実装", "レビュー"),
+	filepath.Join("開発", "実装", "実装待ち"),
+	filepath.Join("開発", "実装", "実行中"),
+	filepath.Join("開発", "実装", "完了"),
+	filepath.Join("開発", "実装", "完了", "アーカイブ"),
+}
+
+// AnswerHistoryQuery は回答履歴の検索条件です
+type AnswerHistoryQuery struct {
+	// Project はプロジェクトパス（空なら全登録プロジェクト）
+	Project string
+	// From / To は回答日時の範囲（ゼロ値は無制限）
+	From time.Time
+	To   time.Time
+	// Limit は件数（0 は既定の100件、上限1000件）
+	Limit int
+}
+
+// AnsweredQuestion は回答履歴の1件（回答日時が記録された回答済の確認事項）です
+type AnsweredQuestion struct {
+	ProjectName  string       `json:"projectName"`
+	ProjectPath  string       `json:"projectPath"`
+	PlanPath     string       `json:"planPath"`
+	QuestionID   string       `json:"questionId,omitempty"`
+	Heading      string       `json:"heading"`
+	QuestionText string       `json:"questionText"`
+	Answer       string       `json:"answer"`
+	AnsweredAt   time.Time    `json:"answeredAt"`
+	Source       AnswerSource `json:"source,omitempty"`
+	// Line はステータス行の行番号（1始まり）
+	Line int `json:"line"`
+}
+
+// AnswerHistory は回答履歴の検索結果です
+type AnswerHistory struct {
+	// Answers は回答日時の新しい順
+	Answers []AnsweredQuestion `json:"answers"`
+	// Total は Limit で切り詰める前の件数
+	Total int `json:"total"`
+}
+
+// CollectAnswerHistory は登録プロジェクトの計画書から回答日時が記録された回答を集め、新しい順に返します。
+// 回答日時の無い回答（本機能以前の回答・手作業の回答）は含めません。読み取りに失敗したファイルは
+// warnings に記録して読み飛ばします。
+func CollectAnswerHistory(q AnswerHistoryQuery, allowedProjects []projects.Project, warnings *[]string) (*AnswerHistory, error) {
+	if !q.From.IsZero() && !q.To.IsZero() && q.From.After(q.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrValidation)
+	}
+	if q.Limit < 0 || q.Limit > maxAnswerHistoryLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrValidation, maxAnswerHistoryLimit)
+	}
+	if q.Limit == 0 {
+		q.Limit = defaultAnswerHistoryLimit
+	}
+
+	targets := allowedProjects
+	if q.Project != "" {
+		targets = nil
+		for _, p := range allowedProjects {
+			if filepath.Clean(p.Path) == filepath.Clean(q.Project) {
+				targets = append(targets, p)
+			}
+		}
+		if len(targets) == 0 {
+			return nil, fmt.Errorf("%w: project not in allowed list: %s", ErrValidation, q.Project)
+		}
+	}
+
+	answers := []AnsweredQuestion{}
+	for _, p := range targets {
+		for _, dir := range answerHistoryDirs {
+			matches, err := filepath.Glob(filepath.Join(p.Path, dir, "*.md"))
+			if err != nil {
+				*warnings = append(*warnings, fmt.Sprintf("failed to glob %s: %v", dir, err))
+				continue
+			}
+			for _, path := range matches {
+				doc, err := plandoc.ParseFile(path)
+				if err != nil {
+					*warnings = append(*warnings, fmt.Sprintf("failed to read %s: %v", path, err))
+					continue
+				}
+				rel, err := filepath.Rel(p.Path, path)
+				if err != nil {
+					rel = path
+				}
+				answers = appendAnswered(answers, p, rel, doc, q)
+			}
+		}
+	}
+
+	sort.SliceStable(answers, func(i, j int) bool {
+		if !answers[i].AnsweredAt.Equal(answers[j].AnsweredAt) {
+			return answers[i].AnsweredAt.After(answers[j].AnsweredAt)
+		}
+		if answers[i].PlanPath != answers[j].PlanPath {
+			return answers[i].PlanPath < answers[j].PlanPath
+		}
+		return answers[i].Line < answers[j].Line
+	})
+
+	result := &AnswerHistory{Answers: answers, Total: len(answers)}
+	if len(answers) > q.Limit {
+		result.Answers = answers[:q.Limit]
+	}
+	return result, nil
+}
+
+// appendAnswered は計画書の回答済の確認事項のうち、回答日時が範囲内のものを追加します
+func appendAnswered(dst []AnsweredQuestion, p projects.Project, relPath string, doc *plandoc.Document, q AnswerHistoryQuery) []AnsweredQuestion {
+	for _, question := range doc.Questions {
+		if question.Status != plandoc.StatusAnswered || question.AnsweredAt == "" {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, question.AnsweredAt)
+		if err != nil {
+			continue
+		}
+		if (!q.From.IsZero() && at.Before(q.From)) || (!q.To.IsZero() && at.After(q.To)) {
+			continue
+		}
+		dst = append(dst, AnsweredQuestion{
+			ProjectName:  p.Name,
+			ProjectPath:  p.Path,
+			PlanPath:     relPath,
+			QuestionID:   question.ID,
+			Heading:      question.Heading,
+			QuestionText: question.Text,
+			Answer:       question.Answer,
+			AnsweredAt:   at,
+			Source:       AnswerSource(question.Source),
+			Line:         question.StatusLine,
+		})
+	}
+	return dst
+}
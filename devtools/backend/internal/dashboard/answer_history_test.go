@@ -0,0 +1,123 @@
+package dashboard
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/projects"
+)
+
+func TestCollectAnswerHistory(t *testing.T) {
+	projA := t.TempDir()
+	projB := t.TempDir()
+
+	writeFile(t, projA, filepath.Join("開発", "実装", "完了", "a_plan.md"), `# A
+### Q1: 古い回答
+**ステータス**: 回答済
+**回答**: はい
+**回答日時**: 2026-07-01T10:00:00Z
+**回答元**: dashboard
+
+### Q2: 回答日時なし
+**ステータス**: 回答済
+**回答**: 手作業
+
+### Q3: 未回答
+**ステータス**: 未回答
+`)
+	writeFile(t, projB, filepath.Join("開発", "実装", "実行中", "b_plan.md"), `# B
+### Q1: 新しい回答
+**ステータス**: 回答済
+**回答**: いいえ
+**回答日時**: 2026-07-02T10:00:00Z
+**回答元**: voice
+`)
+
+	allowed := []projects.Project{{Path: projA, Name: "a"}, {Path: projB, Name: "b"}}
+
+	tests := []struct {
+		name      string
+		q         AnswerHistoryQuery
+		wantIDs   []string
+		wantTotal int
+	}{
+		{
+			name:      "全プロジェクトを新しい順",
+			q:         AnswerHistoryQuery{},
+			wantIDs:   []string{"b:Q1", "a:Q1"},
+			wantTotal: 2,
+		},
+		{
+			name:      "プロジェクト指定",
+			q:         AnswerHistoryQuery{Project: projA},
+			wantIDs:   []string{"a:Q1"},
+			wantTotal: 1,
+		},
+		{
+			name:      "期間指定",
+			q:         AnswerHistoryQuery{From: time.Date(2026, 7, 2, 0, 0, 0, 0, time.UTC)},
+			wantIDs:   []string{"b:Q1"},
+			wantTotal: 1,
+		},
+		{
+			name:      "件数指定",
+			q:         AnswerHistoryQuery{Limit: 1},
+			wantIDs:   []string{"b:Q1"},
+			wantTotal: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var warnings []string
+			got, err := CollectAnswerHistory(tt.q, allowed, &warnings)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var ids []string
+			for _, a := range got.Answers {
+				ids = append(ids, a.ProjectName+":"+a.QuestionID)
+			}
+			if len(ids) != len(tt.wantIDs) {
+				t.Fatalf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIDs[i] {
+					t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+				}
+			}
+			if got.Total != tt.wantTotal {
+				t.Errorf("total = %d, want %d", got.Total, tt.wantTotal)
+			}
+		})
+	}
+
+	first, _ := CollectAnswerHistory(AnswerHistoryQuery{}, allowed, new([]string))
+	if a := first.Answers[0]; a.Source != AnswerSourceVoice || a.Answer != "いいえ" || a.PlanPath != filepath.Join("開発", "実装", "実行中", "b_plan.md") {
+		t.Errorf("unexpected entry: %+v", a)
+	}
+}
+
+func TestCollectAnswerHistory_Validation(t *testing.T) {
+	allowed := []projects.Project{{Path: t.TempDir(), Name: "a"}}
+
+	tests := []struct {
+		name string
+		q    AnswerHistoryQuery
+	}{
+		{name: "未登録プロジェクト", q: AnswerHistoryQuery{Project: "/not/registered"}},
+		{name: "from > to", q: AnswerHistoryQuery{From: time.Now(), To: time.Now().Add(-time.Hour)}},
+		{name: "limit 上限超過", q: AnswerHistoryQuery{Limit: maxAnswerHistoryLimit + 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CollectAnswerHistory(tt.q, allowed, new([]string))
+			if !errors.Is(err, ErrValidation) {
+				t.Errorf("expected ErrValidation, got %v", err)
+			}
+		})
+	}
+}
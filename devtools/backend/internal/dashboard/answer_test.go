@@ -2,10 +2,13 @@ package dashboard
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"ghostrunner/backend/internal/projects"
 )
@@ -91,7 +94,7 @@ func TestAnswerQuestion_Validation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := AnswerQuestion(tt.req, allowedProjects)
+			err := AnswerQuestion(tt.req, allowedProjects, time.Now())
 			if tt.wantErr {
 				if err == nil {
 					t.Error("expected error, got nil")
@@ -136,7 +139,7 @@ func TestAnswerQuestion_WriteBack(t *testing.T) {
 		Answer:      "正規化した設計で進めます",
 	}
 
-	if err := AnswerQuestion(req, allowed); err != nil {
+	if err := AnswerQuestion(req, allowed, time.Now()); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
@@ -192,7 +195,7 @@ func TestAnswerQuestion_AlreadyAnswered(t *testing.T) {
 		Answer:      "新しい回答",
 	}
 
-	err := AnswerQuestion(req, allowed)
+	err := AnswerQuestion(req, allowed, time.Now())
 	if !errors.Is(err, ErrAlreadyAnswered) {
 		t.Errorf("expected ErrAlreadyAnswered, got %v", err)
 	}
@@ -229,7 +232,7 @@ func TestAnswerQuestion_ExistingAnswerLine(t *testing.T) {
 		Answer:      "A案で",
 	}
 
-	if err := AnswerQuestion(req, allowed); err != nil {
+	if err := AnswerQuestion(req, allowed, time.Now()); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
@@ -243,3 +246,193 @@ func TestAnswerQuestion_ExistingAnswerLine(t *testing.T) {
 		t.Error("expected answer to replace existing answer line")
 	}
 }
+
+func TestAnswerQuestions_Batch(t *testing.T) {
+	dir := t.TempDir()
+	planDir := filepath.Join(dir, "開発", "実装", "実装待ち")
+	if err := os.MkdirAll(planDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := `# 計画書
+
+### Q1: DBについて
+**ステータス**: 未回答
+
+### Q2: APIについて
+**ステータス**: 未回答
+`
+	planFile := filepath.Join(planDir, "plan.md")
+	if err := os.WriteFile(planFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	allowed := []projects.Project{{Path: dir, Name: "test"}}
+	now := time.Date(2026, 7, 20, 10, 0, 0, 0, time.UTC)
+
+	req := BatchAnswerRequest{
+		ProjectPath: dir,
+		PlanPath:    filepath.Join("開発", "実装", "実装待ち", "plan.md"),
+		Source:      AnswerSourceVoice,
+		Answers: []AnswerItem{
+			{QuestionID: "Q2", Answer: "RESTで"},
+			{LineStart: 4, Answer: "A案で"},
+		},
+	}
+
+	if err := AnswerQuestions(req, allowed, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := os.ReadFile(planFile)
+	if err != nil {
+		t.Fatalf("failed to read result: %v", err)
+	}
+
+	want := `# 計画書
+
+### Q1: DBについて
+**ステータス**: 回答済
+**回答**: A案で
+**回答日時**: 2026-07-20T10:00:00Z
+**回答元**: voice
+
+### Q2: APIについて
+**ステータス**: 回答済
+**回答**: RESTで
+**回答日時**: 2026-07-20T10:00:00Z
+**回答元**: voice
+`
+	if string(result) != want {
+		t.Errorf("unexpected content:\n%s", result)
+	}
+}
+
+func TestAnswerQuestions_AllOrNothing(t *testing.T) {
+	dir := t.TempDir()
+	planDir := filepath.Join(dir, "開発", "実装", "実装待ち")
+	if err := os.MkdirAll(planDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "### Q1: A\n**ステータス**: 未回答\n### Q2: B\n**ステータス**: 回答済\n**回答**: 済み\n"
+	planFile := filepath.Join(planDir, "plan.md")
+	if err := os.WriteFile(planFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	allowed := []projects.Project{{Path: dir, Name: "test"}}
+	base := BatchAnswerRequest{
+		ProjectPath: dir,
+		PlanPath:    filepath.Join("開発", "実装", "実装待ち", "plan.md"),
+	}
+
+	tests := []struct {
+		name    string
+		answers []AnswerItem
+		source  AnswerSource
+		errIs   error
+	}{
+		{
+			name:    "異常系: 回答済を含む",
+			answers: []AnswerItem{{QuestionID: "Q1", Answer: "x"}, {QuestionID: "Q2", Answer: "y"}},
+			errIs:   ErrAlreadyAnswered,
+		},
+		{
+			name:    "異常系: 同じ確認事項を2回指定",
+			answers: []AnswerItem{{QuestionID: "Q1", Answer: "x"}, {LineStart: 2, Answer: "y"}},
+			errIs:   ErrValidation,
+		},
+		{
+			name:    "異常系: 回答なし",
+			answers: nil,
+			errIs:   ErrValidation,
+		},
+		{
+			name:    "異常系: 不明な回答元",
+			answers: []AnswerItem{{QuestionID: "Q1", Answer: "x"}},
+			source:  "email",
+			errIs:   ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := base
+			req.Answers = tt.answers
+			req.Source = tt.source
+			err := AnswerQuestions(req, allowed, time.Now())
+			if !errors.Is(err, tt.errIs) {
+				t.Errorf("expected error wrapping %v, got %v", tt.errIs, err)
+			}
+
+			result, err := os.ReadFile(planFile)
+			if err != nil {
+				t.Fatalf("failed to read result: %v", err)
+			}
+			if string(result) != content {
+				t.Error("expected plan file to be unchanged")
+			}
+		})
+	}
+}
+
+func TestAnswerQuestions_ConcurrentSubmits(t *testing.T) {
+	dir := t.TempDir()
+	planDir := filepath.Join(dir, "開発", "実装", "実行中")
+	if err := os.MkdirAll(planDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 20
+	var b strings.Builder
+	b.WriteString("# 計画書\n")
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "\n### Q%d: 質問%d\n**ステータス**: 未回答\n", i, i)
+	}
+	planFile := filepath.Join(planDir, "plan.md")
+	if err := os.WriteFile(planFile, []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	allowed := []projects.Project{{Path: dir, Name: "test"}}
+	now := time.Date(2026, 7, 20, 10, 0, 0, 0, time.UTC)
+
+	// 別々の確認事項への回答を同時に送る（ダッシュボードと ntfy から同時に回答した場合）
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 1; i <= n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- AnswerQuestions(BatchAnswerRequest{
+				ProjectPath: dir,
+				PlanPath:    filepath.Join("開発", "実装", "実行中", "plan.md"),
+				Answers:     []AnswerItem{{QuestionID: fmt.Sprintf("Q%d", i), Answer: fmt.Sprintf("回答%d", i)}},
+			}, allowed, now)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	result, err := os.ReadFile(planFile)
+	if err != nil {
+		t.Fatalf("failed to read result: %v", err)
+	}
+	if got := strings.Count(string(result), "**ステータス**: 回答済"); got != n {
+		t.Errorf("answered = %d, want %d:\n%s", got, n, result)
+	}
+	for i := 1; i <= n; i++ {
+		if !strings.Contains(string(result), fmt.Sprintf("**回答**: 回答%d\n", i)) {
+			t.Errorf("answer to Q%d was lost", i)
+		}
+	}
+	if len(planLocks.m) != 0 {
+		t.Errorf("plan locks not released: %d", len(planLocks.m))
+	}
+}
@@ -0,0 +1,57 @@
+package dashboard
+
+import (
+	"time"
+
+	"ghostrunner/backend/internal/autoanswer"
+	"ghostrunner/backend/internal/idle"
+)
+
+// attachAutoAnswer は質問への回答待ち（WaitQuestion）のプロジェクトについて、代表セッションの
+// AskUserQuestion（見出し・質問文・選択肢）をプロジェクトの自動回答ルールに照合し、一致したルールを
+// IdleState.AutoAnswer に付与します。
+// escalate に一致した待機は、待機（セッションID + 待機開始時刻）ごとに1回だけ通知して記録します。
+func attachAutoAnswer(states []ProjectState, markers []idle.Marker, engine *autoanswer.Engine) {
+	bySession := make(map[string]idle.Marker, len(markers))
+	for _, m := range markers {
+		bySession[m.SessionID] = m
+	}
+
+	for i := range states {
+		st := states[i].Idle
+		if st == nil || st.Reason != string(idle.WaitQuestion) {
+			continue
+		}
+		m, ok := bySession[st.SessionID]
+		if !ok || m.RawTail.LastAssistant == "" {
+			continue
+		}
+
+		q := autoanswer.Question{
+			Header:  m.RawTail.QuestionHeader,
+			Text:    m.RawTail.LastAssistant,
+			Options: m.RawTail.QuestionOptions,
+		}
+		d, matched := engine.Decide(states[i].Path, q)
+		if !matched {
+			continue
+		}
+
+		st.AutoAnswer = &AutoAnswerState{Rule: d.Rule, Action: string(d.Action), Answer: d.Answer}
+		since := time.Unix(m.Timestamp, 0)
+		switch d.Action {
+		case autoanswer.ActionTimeout:
+			st.AutoAnswer.DueAt = since.Add(d.Timeout).Format(time.RFC3339)
+		case autoanswer.ActionEscalate:
+			engine.Escalate(m.SessionID+"\x00"+st.Timestamp, autoanswer.Record{
+				Project:   states[i].Path,
+				SessionID: m.SessionID,
+				Source:    autoanswer.SourceDashboard,
+				Rule:      d.Rule,
+				Header:    q.Header,
+				Question:  q.Text,
+				Options:   q.Options,
+			})
+		}
+	}
+}
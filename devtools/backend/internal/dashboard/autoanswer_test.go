@@ -0,0 +1,138 @@
+package dashboard
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/autoanswer"
+	"ghostrunner/backend/internal/idle"
+)
+
+type recordingNotifier struct {
+	errors int
+}
+
+func (n *recordingNotifier) Notify(title, message string) {}
+
+func (n *recordingNotifier) NotifyError(title, message string) { n.errors++ }
+
+func TestGetState_AutoAnswerAttached(t *testing.T) {
+	dir := t.TempDir()
+	projA := mkProjectDir(t, dir, "project-a")
+	projB := mkProjectDir(t, dir, "project-b")
+	configPath := makeConfig(t, dir, []map[string]string{
+		{"path": projA, "name": "project-a"},
+		{"path": projB, "name": "project-b"},
+	})
+
+	rules := `
+rules:
+  - name: continue
+    match: {question: "進めてよいですか"}
+    action: timeout
+    timeout: 10m
+    answer: はい
+  - name: destructive
+    match: {question: "削除"}
+    action: escalate
+`
+	for _, p := range []string{projA, projB} {
+		if err := os.MkdirAll(filepath.Join(p, ".ghostrunner"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(p, autoanswer.RulesFile), []byte(rules), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reader := &fakeIdleReader{markers: []idle.Marker{
+		{Cwd: projA, SessionID: "s1", Timestamp: epochAgo(3 * time.Minute), Status: idle.StatusWaiting, WaitReason: idle.WaitQuestion,
+			SessionCount: 1, RawTail: idle.RawTail{LastAssistant: "この方針で進めてよいですか"}},
+		{Cwd: projB, SessionID: "s2", Timestamp: epochAgo(3 * time.Minute), Status: idle.StatusWaiting, WaitReason: idle.WaitQuestion,
+			SessionCount: 1, RawTail: idle.RawTail{LastAssistant: "古いブランチを削除しますか"}},
+	}}
+
+	audit := autoanswer.NewAuditLog(filepath.Join(dir, "auto_answers.jsonl"))
+	notif := &recordingNotifier{}
+	engine := autoanswer.NewEngine(audit, notif, func() time.Time { return fixedNow })
+	svc := NewServiceWithClock(configPath, "/other", reader, func() time.Time { return fixedNow }, WithAutoAnswer(engine))
+
+	for i := 0; i < 2; i++ {
+		state, err := svc.GetState(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		a := findProject(t, state, "project-a")
+		if a.Idle == nil || a.Idle.AutoAnswer == nil {
+			t.Fatal("expected AutoAnswer on project-a")
+		}
+		wantDue := fixedNow.Add(-3 * time.Minute).Add(10 * time.Minute).Format(time.RFC3339)
+		if got := *a.Idle.AutoAnswer; got.Rule != "continue" || got.Action != "timeout" || got.Answer != "はい" || got.DueAt != wantDue {
+			t.Errorf("project-a AutoAnswer = %+v", got)
+		}
+
+		b := findProject(t, state, "project-b")
+		if b.Idle == nil || b.Idle.AutoAnswer == nil || b.Idle.AutoAnswer.Action != "escalate" {
+			t.Fatalf("expected escalate on project-b, got %+v", b.Idle)
+		}
+	}
+
+	// escalate は同じ待機で1回だけ通知・記録する。提案のみの timeout は記録しない
+	if notif.errors != 1 {
+		t.Errorf("NotifyError called %d times, want 1", notif.errors)
+	}
+	result, err := audit.Query(autoanswer.Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 1 || result.Records[0].Source != autoanswer.SourceDashboard || result.Records[0].SessionID != "s2" {
+		t.Errorf("audit = %+v", result)
+	}
+}
+
+func TestGetState_AutoAnswerMatchesHeaderAndOptions(t *testing.T) {
+	dir := t.TempDir()
+	projA := mkProjectDir(t, dir, "project-a")
+	configPath := makeConfig(t, dir, []map[string]string{{"path": projA, "name": "project-a"}})
+
+	rules := `
+rules:
+  - name: lint-choice
+    match: {header: "^Lint$", option: "自動修正"}
+    action: answer
+`
+	if err := os.MkdirAll(filepath.Join(projA, ".ghostrunner"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projA, autoanswer.RulesFile), []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := &fakeIdleReader{markers: []idle.Marker{
+		{Cwd: projA, SessionID: "s1", Timestamp: epochAgo(3 * time.Minute), Status: idle.StatusWaiting, WaitReason: idle.WaitQuestion,
+			SessionCount: 1, RawTail: idle.RawTail{
+				LastAssistant:   "lint の指摘をどう扱いますか",
+				QuestionHeader:  "Lint",
+				QuestionOptions: []string{"無視する", "自動修正する"},
+			}},
+	}}
+	engine := autoanswer.NewEngine(autoanswer.NewAuditLog(filepath.Join(dir, "auto_answers.jsonl")), &recordingNotifier{},
+		func() time.Time { return fixedNow })
+	svc := NewServiceWithClock(configPath, "/other", reader, func() time.Time { return fixedNow }, WithAutoAnswer(engine))
+
+	state, err := svc.GetState(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	a := findProject(t, state, "project-a")
+	if a.Idle == nil || a.Idle.AutoAnswer == nil {
+		t.Fatal("expected AutoAnswer from the header/option rule")
+	}
+	if got := *a.Idle.AutoAnswer; got.Rule != "lint-choice" || got.Action != "answer" || got.Answer != "自動修正する" {
+		t.Errorf("AutoAnswer = %+v", got)
+	}
+}
@@ -4,7 +4,7 @@
 //
 // 登録済みプロジェクト群のカンバン状態、未回答確認事項、運用状態を
 // ファイルシステムから読み取り専用で集約する。
-// 唯一の書き込み操作は確認事項への回答書き戻し(AnswerQuestion)のみ。
+// 唯一の書き込み操作は確認事項への回答書き戻し(AnswerQuestion / AnswerQuestions)のみ。
 //
 // # 主要な型
 //
@@ -13,27 +13,56 @@
 //   - Attention: プロジェクトの注目度（required / progress / watching）
 //   - KanbanCounts: カンバン各レーン（レビュー/実装待ち/実行中/完了）の.mdファイル数
 //   - UnansweredQuestion: 計画書内の未回答確認事項（ファイルパス、行番号、質問文）
-//   - OpsEntry: 運用/状態/ 配下のJSONから読み取った1エントリ（stale検知付き）
+//   - OpsEntry: 運用/状態/ 配下のJSONから読み取った1エントリ（stale検知、ops.yaml の期待フィールド・アラート評価付き）
+//   - OpsAlert / OpsSeverity: 運用/ops.yaml のアラート条件1つの評価結果と重要度（critical / warning）
 //   - IdleState: 質問待ち状態（会話ログ由来の代表マーカー。キー存在＝質問待ち）
 //   - RunningState: 動作中状態（会話ログ上で Claude が処理中の代表セッション。キー存在＝動作中。
 //     kanban.running 件数・ops status="running" とは別概念のランタイム動作中）
-//   - AnswerRequest: 回答書き戻しリクエスト（プロジェクトパス、計画書パス、行番号、回答文）
+//   - gitinfo.Info: git リポジトリ状態（ProjectState.Git。gitinfo パッケージの共有インスペクタで取得）
+//   - StuckState: 停滞状態（Watchdog が検出した、生成途中のまま進まない/同じツール呼び出しが失敗し続けるセッション）
+//   - AnswerRequest: 回答書き戻しリクエスト（プロジェクトパス、計画書パス、行番号または確認事項ID、回答文、回答元）
+//   - BatchAnswerRequest / AnswerItem: 1つの計画書への一括回答リクエスト
+//   - AnswerSource: 回答元（dashboard / voice / ntfy）。計画書の「**回答元**:」に記録する
+//   - AnswerHistoryQuery / AnswerHistory / AnsweredQuestion: 計画書に記録された回答の履歴
 //
 // # 主要な関数・インターフェース
 //
-//   - Service: GetState（全プロジェクト集約）とAnswer（回答書き戻し）を提供するインターフェース
+//   - Service: GetState（全プロジェクト集約）、Answer / AnswerBatch（回答書き戻し）、AnswerHistory（回答履歴）を
+//     提供するインターフェース
 //   - NewService: Serviceの本番用コンストラクタ
 //   - NewServiceWithClock: clock注入付きコンストラクタ（テスト用）
 //   - ScanProject: 1プロジェクトのカンバン/未回答/運用を読み取り専用で収集する
-//   - AnswerQuestion: 計画書の未回答行を「回答済」に更新し回答文を挿入する（アトミック書き込み）
+//   - AnswerQuestion / AnswerQuestions: 計画書の未回答行を「回答済」に更新し、回答文・回答日時・回答元を
+//     書き込む（アトミック書き込み。一括時は全件適用か無変更）
+//   - CollectAnswerHistory: 登録プロジェクトの全レーンの計画書から回答日時付きの回答を新しい順に集める
 //   - StreamService: ダッシュボード状態のSSE配信（変化時のみStateスナップショットをbroadcast）
+//   - WithRecorder: StreamServiceにhistory.Recorderを設定するStreamOption（スキャンごとの状態を
+//     プロジェクト別サンプルへ変換して時系列に記録。間引きはRecorder側）
 //   - Summarizer: 滞留した質問待ちマーカーを検出しSummarizeServiceで要約してマーカーへ書き戻す
+//   - WithFallbacks: 要約がエラーになったときに順に試すSummarizeServiceを追加するSummarizerOption
+//   - ProgressSummarizer: 動作中セッションの会話ログを前回位置からの差分で読み、interval（既定5分）ごとに
+//     「いま何をしているか／ここまでに済んだこと」を要約する。要約は sessionID と会話ログの位置を key に
+//     キャッシュし、更新時は session.progress を low で通知する
+//   - WithProgress: Serviceに進捗要約を設定するOption（設定時のみRunningState.Progressを付与）
+//   - Watchdog / WatchdogConfig: 動作中セッションを追跡して停滞を検出し、遷移時にNtfyServiceで通知する
+//     （しきい値はサーバー設定の watchdog から組み立てて渡す）
+//   - OpsMonitor: 登録プロジェクトの運用状態を定期評価し、ops.yaml のアラートが発火・解除へ遷移した時に
+//     NtfyServiceで通知する
+//   - Escalator: 質問待ちが待機開始（Marker.Timestamp）から一定時間を超えたセッションを、1待機につき1回
+//     high の question.escalate で再通知する（notify.yaml の rules.escalation 設定時のみ起動）
+//   - WithWatchdog: Serviceに停滞監視を設定するOption（設定時のみProjectState.Stuckを付与）
+//   - WithGitInspector: Serviceにgit状態の取得を設定するOption（設定時のみProjectState.Gitを付与。
+//     .git/indexのmtimeキャッシュで2秒間隔のスキャンでもgitを毎回起動しない。タスク開始は gr-run が記録した実行中へのクレーム時刻）
+//   - WithAutoAnswer: Serviceに質問の自動回答ルールを設定するOption（設定時のみ質問待ちの質問文をルールに照合し
+//     IdleState.AutoAnswerを付与。外部セッションには回答できないため提案のみで、escalateは待機ごとに1回通知する）
 //
 // # 質問待ち要約とSSE配信（Phase 1b）
 //
 // Summarizerはtickerで滞留（約2分以上）かつ未要約のマーカーを抽出し、会話末尾を
-// service.SummarizeService（claude -p --model haiku）で日本語1行に要約してidle.Writerで
-// マーカーへ書き戻す。書き戻しはフックによる削除/更新を rename 直前に再確認し、解消済みの
+// service.SummarizeService（既定は claude -p --model haiku）で日本語1行に要約してidle.Writerで
+// マーカーへ書き戻す。要約がエラーになった場合は WithFallbacks のプロバイダー（OpenAI・Gemini・
+// OpenAI互換のローカルエンドポイント・抽出要約）を順に試し、最初に成功した要約を使う。
+// どのプロバイダーを使ったかは ghostrunner_summarizer_provider_total に記録する。書き戻しはフックによる削除/更新を rename 直前に再確認し、解消済みの
 // 質問待ちを復活させない（compare-and-swap）。並列数は小さく抑えCLIコストを制御する。
 //
 // StreamServiceは短間隔でGetStateをスキャンし、前回と実変化があった場合のみStateスナップ
@@ -44,10 +73,13 @@
 // # 設計方針
 //
 //   - ファイルシステムを唯一の真実源(source of truth)とする
-//   - 未回答検出の正規表現パターンはgrrunパッケージのSSOTを共有
+//   - 未回答の検出と回答の書き戻しはplandocパッケージの計画書モデルを通す（grrunのClassifyResultと共有）
 //   - テスト用にclock注入(NewServiceWithClock)をサポート
 //   - ScanProjectは各プロジェクトの状態を独立に収集し、エラーはwarningsに蓄積
-//   - AnswerQuestionはwrite-to-temp + renameパターンで安全にファイルを更新
+//   - AnswerQuestionはwrite-to-temp + renameパターンで安全にファイルを更新し、同じ計画書（絶対パス）への
+//     同時回答は読み込みから書き戻しまでをロックで直列化する（後の書き込みが先の回答を消さない）
+//   - 回答履歴は計画書自体（回答日時・回答元の行）を記録先とし、別のログファイルを持たない。
+//     回答日時の無い過去の回答は履歴に含めない
 //   - 回答対象の計画書は開発/実装/実装待ち/ または 開発/実装/実行中/ 配下の.mdのみ許可
 //   - プロジェクトパスはpatrol_projects.jsonの登録済みリストで検証
 //
@@ -64,6 +96,8 @@
 //   - マーカーのcwdはidle.MatchProjectで登録済みプロジェクトへパス前方一致で紐付ける
 //   - Marker.Statusで分岐: waiting→IdleState、running→RunningStateを付与。SessionCountは
 //     rep.SessionCount(reader集計の同一status数)をそのまま採用する
+//   - Marker.WaitReasonはIdleState.Reason/ReasonLabelへ引き継ぎ、質問・計画承認・実行許可・返信待ちを
+//     フロントが区別できるようにする
 //   - idleMinAgeゲート(応答直後ノイズ抑制)はwaitingのみに適用し、runningには適用しない(fresh runningを
 //     落とすと動作中が一切表示されないため・C-1)
 //   - 付与したプロジェクトはAttentionを再評価する(determineAttention・C1)。質問待ちはrequired、
@@ -71,4 +105,28 @@
 //   - プロジェクトのソートは質問待ち(Idle!=nil)を第1キー、動作中(Running!=nil)を第2キーとし、
 //     未回答由来のrequiredより優先する。以降はattention優先度、質問待ちの経過時間(内部計算・非露出)、
 //     isSelf、名前の順で安定ソートする
+//
+// # 運用状態の設定(運用/ops.yaml)
+//
+// scanOpsは 運用/状態/*.json の既知フィールド(opsJSON)を読み、プロジェクトの 運用/ops.yaml で
+// 宣言された kind ごとの設定を適用する。ファイルが無い・解析できない場合は既定値で判定する。
+//
+//   - fields: 状態ファイルに存在すべきパス。欠けていれば OpsEntry.MissingFields と warnings に出す
+//   - staleAfter: status=running のまま更新が無いとき停滞とみなす時間(既定3時間)
+//   - errorThreshold: consecutiveErrors を要対応とみなす回数(既定3回)
+//   - alerts: "<項> <演算子> <項>" の比較式と評価開始時刻(by)。評価結果は OpsEntry.Alerts に載せ、
+//     critical の発火中は required とする。defaults の設定に kind 側を重ね、alerts は連結する
+//
+// 発火・解除の通知は OpsMonitor が担い、GetState(読み取り)は通知しない。
+//
+// # 停滞(Stuck)の検出
+//
+// readerは生成途中のまま idle.RunningMaxAge を超えたセッションを静観(none)へ落とすため、固まった
+// ツール呼び出しはダッシュボードから黙って消える。Watchdogは一度runningとして観測したセッションを
+// 会話ログのパス(Marker.TranscriptPath)で追跡し続け、以下を停滞として検出する。
+//
+//   - no_progress: 生成途中のまま会話ログが NoProgressWindow(既定=RunningMaxAge)以上伸びていない
+//   - repeated_failure: 末尾で同一ツール・同一入力の呼び出しが RepeatThreshold 回以上連続で失敗している
+//   - 実行許可待ち以外のwaitingへ移った、ターンを終えた、会話ログが消えた/TTL超過のセッションは追跡を外す
+//   - 通知は停滞への遷移（種別の変化を含む）時のみ1回。同じ種別が続く間はSinceを保持し差分配信を揺らさない
 package dashboard
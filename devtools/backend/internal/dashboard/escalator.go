@@ -0,0 +1,136 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"ghostrunner/backend/internal/idle"
+	"ghostrunner/backend/internal/notify"
+	"ghostrunner/backend/internal/service"
+)
+
+// escalatorInterval は未回答の質問の確認間隔です
+const escalatorInterval = time.Minute
+
+// Escalator は質問待ちのまま一定時間を超えたセッションを、優先度を上げて1回だけ再通知するバックグラウンドジョブです。
+// 待機の開始時刻は会話ログの reader が返す待機開始 entry-time（Marker.Timestamp）を使うため、
+// サーバーの再起動をまたいでも経過時間は変わりません。返信待ち（end_of_turn）は質問ではないため対象外です。
+type Escalator struct {
+	reader   idle.Reader
+	notifier service.NtfyService
+	after    time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	notified map[string]bool // key: idle.CacheKey（セッションID + 待機開始時刻）
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewEscalator は新しいEscalatorを生成します。after は質問待ちの開始から再通知までの時間です。
+// now が nil の場合は time.Now を使います。
+func NewEscalator(reader idle.Reader, notifier service.NtfyService, after time.Duration, now func() time.Time) *Escalator {
+	if now == nil {
+		now = time.Now
+	}
+	return &Escalator{
+		reader:   reader,
+		notifier: notifier,
+		after:    after,
+		now:      now,
+		notified: make(map[string]bool),
+	}
+}
+
+// Start は未回答の質問の監視を開始します。ctx のキャンセルまたは Stop で終了します。
+func (e *Escalator) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	e.cancel = cancel
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		ticker := time.NewTicker(escalatorInterval)
+		defer ticker.Stop()
+
+		log.Printf("[Escalator] started: interval=%s, after=%s", escalatorInterval, e.after)
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("[Escalator] stopped")
+				return
+			case <-ticker.C:
+				e.Check(ctx)
+			}
+		}
+	}()
+}
+
+// Stop は監視を停止し、実行中のtickが終わるまで待機します
+func (e *Escalator) Stop() {
+	if e.cancel != nil {
+		e.cancel()
+	}
+	e.wg.Wait()
+}
+
+// Check はマーカーを読み取り、1回分の再通知判定を行います
+func (e *Escalator) Check(ctx context.Context) {
+	markers, err := e.reader.List(ctx)
+	if err != nil {
+		log.Printf("[Escalator] list markers failed: %v", err)
+		return
+	}
+	e.Observe(markers, e.now())
+}
+
+// Observe は質問待ちのマーカーのうち、待機開始から after 以上経ったものを1待機につき1回だけ再通知します。
+// 解消した（マーカーから消えた）待機は記録から外し、同じセッションの次の質問は改めて判定します。
+func (e *Escalator) Observe(markers []idle.Marker, now time.Time) {
+	var due []idle.Marker
+	seen := make(map[string]bool, len(markers))
+
+	e.mu.Lock()
+	for _, m := range markers {
+		if m.Status != idle.StatusWaiting || m.WaitReason == idle.WaitEndOfTurn || m.Timestamp == 0 {
+			continue
+		}
+		key := idle.CacheKey(m.SessionID, m.Timestamp)
+		seen[key] = true
+		if e.notified[key] || now.Sub(time.Unix(m.Timestamp, 0)) < e.after || idle.IsExpired(m, now, idle.TTL) {
+			continue
+		}
+		e.notified[key] = true
+		due = append(due, m)
+	}
+	for key := range e.notified {
+		if !seen[key] {
+			delete(e.notified, key)
+		}
+	}
+	e.mu.Unlock()
+
+	for _, m := range due {
+		waited := now.Sub(time.Unix(m.Timestamp, 0)).Truncate(time.Minute)
+		log.Printf("[Escalator] question unanswered: session=%s, cwd=%s, reason=%s, waited=%s", m.SessionID, m.Cwd, m.WaitReason, waited)
+		if e.notifier == nil {
+			continue
+		}
+		body := m.Summary
+		if body == "" {
+			body = m.WaitReason.Label()
+		}
+		notify.Send(e.notifier, notify.Message{
+			Event:    notify.EventQuestionEscalate,
+			Project:  m.Cwd,
+			Title:    fmt.Sprintf("未回答が%d分続いています: %s", int(waited.Minutes()), filepath.Base(m.Cwd)),
+			Body:     body,
+			Priority: notify.PriorityHigh,
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package dashboard
+
+import (
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/idle"
+)
+
+func waitingMarker(cwd, sessionID string, reason idle.WaitReason, since time.Time) idle.Marker {
+	return idle.Marker{Cwd: cwd, SessionID: sessionID, Status: idle.StatusWaiting, WaitReason: reason, Timestamp: since.Unix()}
+}
+
+// TestEscalator_NotifiesOncePerWait は待機開始から after を超えた質問待ちを1待機につき1回だけ再通知し、
+// 返信待ち・after 未満は対象外で、同じセッションの次の待機は改めて判定されることを検証します。
+func TestEscalator_NotifiesOncePerWait(t *testing.T) {
+	notifier := &fakeNotifier{}
+	e := NewEscalator(&fakeIdleReader{}, notifier, 15*time.Minute, nil)
+
+	markers := []idle.Marker{
+		waitingMarker("/home/u/shop", "s1", idle.WaitQuestion, fixedNow.Add(-20*time.Minute)),
+		waitingMarker("/home/u/blog", "s2", idle.WaitEndOfTurn, fixedNow.Add(-time.Hour)),
+		waitingMarker("/home/u/api", "s3", idle.WaitPlanApproval, fixedNow.Add(-10*time.Minute)),
+		runningMarker("/home/u/cli", "s4", "/logs/s4.jsonl"),
+	}
+	e.Observe(markers, fixedNow)
+	if notifier.count() != 1 || notifier.titles[0] != "未回答が20分続いています: shop" {
+		t.Fatalf("titles = %v, want only shop", notifier.titles)
+	}
+
+	e.Observe(markers, fixedNow.Add(5*time.Minute))
+	if notifier.count() != 2 || notifier.titles[1] != "未回答が15分続いています: api" {
+		t.Fatalf("titles = %v, want shop once and api after 15 minutes", notifier.titles)
+	}
+
+	// s1 が回答されて次の質問で待機し直した場合は新しい待機として扱う
+	next := waitingMarker("/home/u/shop", "s1", idle.WaitQuestion, fixedNow.Add(10*time.Minute))
+	e.Observe([]idle.Marker{next}, fixedNow.Add(20*time.Minute))
+	if notifier.count() != 2 {
+		t.Fatalf("new wait escalated before after elapsed")
+	}
+	e.Observe([]idle.Marker{next}, fixedNow.Add(25*time.Minute))
+	if notifier.count() != 3 {
+		t.Errorf("titles = %v, want the new wait escalated", notifier.titles)
+	}
+}
@@ -0,0 +1,33 @@
+package dashboard
+
+import (
+	"time"
+
+	"ghostrunner/backend/internal/metrics"
+)
+
+var (
+	// scanDurationSeconds はストリームの1回分のスキャン（GetState）の所要時間です（result=ok / error）
+	scanDurationSeconds = metrics.NewHistogramVec("ghostrunner_dashboard_scan_duration_seconds",
+		"Duration of one dashboard stream scan (GetState) by result (ok, error).", nil, "result")
+	// summarizerAttemptsTotal は質問待ち要約の試行数です
+	// （result=summarized / empty / summarize_error / write_error）
+	summarizerAttemptsTotal = metrics.NewCounterVec("ghostrunner_summarizer_attempts_total",
+		"Idle summarizer attempts by result (summarized, empty, summarize_error, write_error).", "result")
+	// summarizerProviderTotal は要約プロバイダーごとの呼び出し結果です（質問待ち・進捗の両方。result=ok / error。error は次のプロバイダーへ）
+	summarizerProviderTotal = metrics.NewCounterVec("ghostrunner_summarizer_provider_total",
+		"Summarizer provider calls (idle and progress) by provider and result (ok, error).", "provider", "result")
+	// progressSummariesTotal は動作中セッションの進捗要約の結果です
+	// （result=summarized / cached / empty / summarize_error / write_error / read_error）
+	progressSummariesTotal = metrics.NewCounterVec("ghostrunner_progress_summaries_total",
+		"Running session progress summaries by result (summarized, cached, empty, summarize_error, write_error, read_error).", "result")
+)
+
+// observeScan は started からのスキャン所要時間を記録します
+func observeScan(started time.Time, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	scanDurationSeconds.Observe(time.Since(started).Seconds(), result)
+}
@@ -0,0 +1,325 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// opsConfigFile は運用状態のスキーマ・しきい値・アラート条件を宣言するファイル（運用/ 配下）です
+	opsConfigFile = "ops.yaml"
+	// defaultOpsStaleAfter は status=running のまま状態ファイルが更新されず停滞とみなすまでの既定時間です
+	defaultOpsStaleAfter = 3 * time.Hour
+	// defaultOpsErrorThreshold は連続エラーを要対応とみなす既定回数です
+	defaultOpsErrorThreshold = 3
+)
+
+// OpsSeverity は運用アラートの重要度です。
+type OpsSeverity string
+
+const (
+	// OpsSeverityCritical は発火中にプロジェクトを要対応（required）にする重要度です（既定）
+	OpsSeverityCritical OpsSeverity = "critical"
+	// OpsSeverityWarning は発火を表示・通知するのみで注目度を変えない重要度です
+	OpsSeverityWarning OpsSeverity = "warning"
+)
+
+// opsConfig は 運用/ops.yaml の内容です。defaults は全 kind に、kinds は kind 別に適用されます
+type opsConfig struct {
+	Defaults opsKindConfig            `yaml:"defaults"`
+	Kinds    map[string]opsKindConfig `yaml:"kinds"`
+}
+
+// opsKindConfig は1つの kind の期待フィールド・停滞判定・アラート条件です
+type opsKindConfig struct {
+	// Fields は状態ファイルに存在すべきフィールド（"today.count" のようなドット区切りのパス）
+	Fields []string `yaml:"fields"`
+	// StaleAfter は status=running のまま更新が無いとき停滞とみなす時間（"3h" 等）
+	StaleAfter string `yaml:"staleAfter"`
+	// ErrorThreshold は consecutiveErrors がこの回数以上で要対応とみなす回数
+	ErrorThreshold int            `yaml:"errorThreshold"`
+	Alerts         []opsAlertRule `yaml:"alerts"`
+
+	staleAfter time.Duration
+}
+
+// opsAlertRule は1つのアラート条件です
+type opsAlertRule struct {
+	Name string `yaml:"name"`
+	// When は "<パス> <演算子> <パスまたは値>" 形式の条件（例: "today.count < today.target"）
+	When string `yaml:"when"`
+	// By は "HH:MM"。指定時はその時刻以降にのみ評価します（「18:00 までに達成」の表現）
+	By       string      `yaml:"by"`
+	Severity OpsSeverity `yaml:"severity"`
+	// Message は発火時の説明。省略時は条件式と評価値から組み立てます
+	Message string `yaml:"message"`
+
+	cond     opsCondition
+	byMinute int // 0時からの分。By 未指定時は -1
+}
+
+// opsCondition は解析済みの比較式です
+type opsCondition struct {
+	left, op, right string
+}
+
+// opsOperators は比較演算子です（2文字の演算子を先に照合します）
+var opsOperators = []string{"<=", ">=", "==", "!=", "<", ">"}
+
+// loadOpsConfig はプロジェクトの 運用/ops.yaml を読み込みます。ファイルが無い場合は既定値を返します
+func loadOpsConfig(projectPath string) (*opsConfig, error) {
+	path := filepath.Join(projectPath, "運用", opsConfigFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return parseOpsConfig(nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	cfg, err := parseOpsConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// parseOpsConfig は ops.yaml の内容を解析し、停滞時間・アラート条件・時刻を検証します
+func parseOpsConfig(data []byte) (*opsConfig, error) {
+	var cfg opsConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid yaml: %v", err)
+	}
+	if err := cfg.Defaults.compile(); err != nil {
+		return nil, fmt.Errorf("defaults: %v", err)
+	}
+	for kind, kc := range cfg.Kinds {
+		if err := kc.compile(); err != nil {
+			return nil, fmt.Errorf("kinds.%s: %v", kind, err)
+		}
+		cfg.Kinds[kind] = kc
+	}
+	return &cfg, nil
+}
+
+// compile は停滞時間とアラート条件を解釈します
+func (k *opsKindConfig) compile() error {
+	if k.StaleAfter != "" {
+		d, err := time.ParseDuration(k.StaleAfter)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("staleAfter must be a positive duration: %q", k.StaleAfter)
+		}
+		k.staleAfter = d
+	}
+	if k.ErrorThreshold < 0 {
+		return fmt.Errorf("errorThreshold must not be negative: %d", k.ErrorThreshold)
+	}
+	for i := range k.Alerts {
+		if err := k.Alerts[i].compile(); err != nil {
+			name := k.Alerts[i].Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			return fmt.Errorf("alert %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// compile は条件式・時刻・重要度を解釈します
+func (r *opsAlertRule) compile() error {
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	cond, err := parseOpsCondition(r.When)
+	if err != nil {
+		return fmt.Errorf("when: %v", err)
+	}
+	r.cond = cond
+	r.byMinute = -1
+	if r.By != "" {
+		t, err := time.Parse("15:04", r.By)
+		if err != nil {
+			return fmt.Errorf("by must be HH:MM: %q", r.By)
+		}
+		r.byMinute = t.Hour()*60 + t.Minute()
+	}
+	switch r.Severity {
+	case "":
+		r.Severity = OpsSeverityCritical
+	case OpsSeverityCritical, OpsSeverityWarning:
+	default:
+		return fmt.Errorf("unknown severity: %s", r.Severity)
+	}
+	return nil
+}
+
+// parseOpsCondition は "<左辺> <演算子> <右辺>" 形式の比較式を解析します
+func parseOpsCondition(expr string) (opsCondition, error) {
+	for _, op := range opsOperators {
+		if i := strings.Index(expr, op); i >= 0 {
+			left := strings.TrimSpace(expr[:i])
+			right := strings.TrimSpace(expr[i+len(op):])
+			if left == "" || right == "" {
+				return opsCondition{}, fmt.Errorf("operand is missing: %q", expr)
+			}
+			return opsCondition{left: left, op: op, right: right}, nil
+		}
+	}
+	return opsCondition{}, fmt.Errorf("comparison operator is required: %q", expr)
+}
+
+// resolved は kind に適用する設定を defaults と合成して返します。
+// fields / staleAfter / errorThreshold は kind 側の指定を優先し、alerts は defaults の後に kind 側を連結します
+func (c *opsConfig) resolved(kind string) opsKindConfig {
+	out := c.Defaults
+	out.Alerts = append([]opsAlertRule(nil), c.Defaults.Alerts...)
+	if kc, ok := c.Kinds[kind]; ok {
+		if len(kc.Fields) > 0 {
+			out.Fields = kc.Fields
+		}
+		if kc.staleAfter > 0 {
+			out.staleAfter = kc.staleAfter
+		}
+		if kc.ErrorThreshold > 0 {
+			out.ErrorThreshold = kc.ErrorThreshold
+		}
+		out.Alerts = append(out.Alerts, kc.Alerts...)
+	}
+	if out.staleAfter <= 0 {
+		out.staleAfter = defaultOpsStaleAfter
+	}
+	if out.ErrorThreshold <= 0 {
+		out.ErrorThreshold = defaultOpsErrorThreshold
+	}
+	return out
+}
+
+// missingFields は doc に存在しない期待フィールドを返します
+func missingFields(doc map[string]any, fields []string) []string {
+	var missing []string
+	for _, f := range fields {
+		if _, ok := lookupOpsPath(doc, f); !ok {
+			missing = append(missing, f)
+		}
+	}
+	return missing
+}
+
+// evaluate はアラート条件を状態ファイルの内容に対して評価します。
+// By の時刻より前は評価せず Skipped とし、参照先が無い・型が比較できない場合は Error に理由を入れます
+func (r *opsAlertRule) evaluate(doc map[string]any, now time.Time) OpsAlert {
+	alert := OpsAlert{
+		Rule:     r.Name,
+		Severity: r.Severity,
+		When:     r.When,
+		By:       r.By,
+	}
+	if r.byMinute >= 0 && now.Hour()*60+now.Minute() < r.byMinute {
+		alert.Skipped = true
+		return alert
+	}
+	left, err := opsOperand(doc, r.cond.left)
+	if err != nil {
+		alert.Error = err.Error()
+		return alert
+	}
+	right, err := opsOperand(doc, r.cond.right)
+	if err != nil {
+		alert.Error = err.Error()
+		return alert
+	}
+	firing, err := compareOps(left, r.cond.op, right)
+	if err != nil {
+		alert.Error = err.Error()
+		return alert
+	}
+	alert.Firing = firing
+	if firing {
+		alert.Message = r.Message
+		if alert.Message == "" {
+			alert.Message = fmt.Sprintf("%s (%v %s %v)", r.When, left, r.cond.op, right)
+		}
+	}
+	return alert
+}
+
+// opsOperand は比較式の項を値に解決します。数値・true/false・引用符付き文字列はリテラル、それ以外はパスです
+func opsOperand(doc map[string]any, term string) (any, error) {
+	if n, err := strconv.ParseFloat(term, 64); err == nil {
+		return n, nil
+	}
+	switch term {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if len(term) >= 2 && (term[0] == '"' || term[0] == '\'') && term[len(term)-1] == term[0] {
+		return term[1 : len(term)-1], nil
+	}
+	v, ok := lookupOpsPath(doc, term)
+	if !ok {
+		return nil, fmt.Errorf("field not found: %s", term)
+	}
+	return v, nil
+}
+
+// lookupOpsPath はドット区切りのパスで JSON オブジェクトを辿ります
+func lookupOpsPath(doc map[string]any, path string) (any, bool) {
+	var cur any = doc
+	for _, key := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// compareOps は2つの値を比較します。数値は全演算子、文字列・真偽値は == と != のみ対応します
+func compareOps(left any, op string, right any) (bool, error) {
+	if l, ok := left.(float64); ok {
+		r, ok := right.(float64)
+		if !ok {
+			return false, fmt.Errorf("cannot compare number with %T", right)
+		}
+		switch op {
+		case "<":
+			return l < r, nil
+		case "<=":
+			return l <= r, nil
+		case ">":
+			return l > r, nil
+		case ">=":
+			return l >= r, nil
+		case "==":
+			return l == r, nil
+		case "!=":
+			return l != r, nil
+		}
+	}
+	switch left.(type) {
+	case string, bool:
+		if fmt.Sprintf("%T", left) != fmt.Sprintf("%T", right) {
+			return false, fmt.Errorf("cannot compare %T with %T", left, right)
+		}
+		switch op {
+		case "==":
+			return left == right, nil
+		case "!=":
+			return left != right, nil
+		}
+		return false, fmt.Errorf("operator %s is not supported for %T", op, left)
+	}
+	return false, fmt.Errorf("cannot compare %T", left)
+}
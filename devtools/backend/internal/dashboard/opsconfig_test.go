@@ -0,0 +1,153 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseOpsConfig_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{name: "空ファイルは既定値", yaml: ""},
+		{
+			name: "正常",
+			yaml: `
+defaults:
+  staleAfter: 2h
+kinds:
+  follower:
+    fields: [today.count, today.target]
+    errorThreshold: 5
+    alerts:
+      - name: daily-target
+        when: today.count < today.target
+        by: "18:00"
+`,
+		},
+		{name: "staleAfterが不正", yaml: "defaults:\n  staleAfter: abc\n", wantErr: "staleAfter"},
+		{name: "errorThresholdが負", yaml: "defaults:\n  errorThreshold: -1\n", wantErr: "errorThreshold"},
+		{name: "ルール名なし", yaml: "defaults:\n  alerts:\n    - when: a < 1\n", wantErr: "name is required"},
+		{name: "演算子なし", yaml: "defaults:\n  alerts:\n    - name: r\n      when: today.count\n", wantErr: "operator"},
+		{name: "byが不正", yaml: "defaults:\n  alerts:\n    - name: r\n      when: a < 1\n      by: 25:00\n", wantErr: "HH:MM"},
+		{name: "未知のseverity", yaml: "defaults:\n  alerts:\n    - name: r\n      when: a < 1\n      severity: fatal\n", wantErr: "severity"},
+		{name: "yaml不正", yaml: "kinds: [", wantErr: "invalid yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOpsConfig([]byte(tt.yaml))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestOpsConfig_Resolved(t *testing.T) {
+	cfg, err := parseOpsConfig([]byte(`
+defaults:
+  fields: [status]
+  staleAfter: 2h
+  alerts:
+    - name: base
+      when: consecutiveErrors > 0
+kinds:
+  follower:
+    fields: [today.count]
+    staleAfter: 30m
+    errorThreshold: 5
+    alerts:
+      - name: daily-target
+        when: today.count < today.target
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	kc := cfg.resolved("follower")
+	if kc.staleAfter != 30*time.Minute || kc.ErrorThreshold != 5 {
+		t.Errorf("kind override not applied: staleAfter=%s, errorThreshold=%d", kc.staleAfter, kc.ErrorThreshold)
+	}
+	if len(kc.Fields) != 1 || kc.Fields[0] != "today.count" {
+		t.Errorf("fields = %v, want [today.count]", kc.Fields)
+	}
+	if len(kc.Alerts) != 2 || kc.Alerts[0].Name != "base" || kc.Alerts[1].Name != "daily-target" {
+		t.Errorf("alerts should be defaults followed by kind: %+v", kc.Alerts)
+	}
+
+	other := cfg.resolved("unknown")
+	if other.staleAfter != 2*time.Hour || other.ErrorThreshold != defaultOpsErrorThreshold || len(other.Alerts) != 1 {
+		t.Errorf("defaults not applied: staleAfter=%s, errorThreshold=%d, alerts=%d", other.staleAfter, other.ErrorThreshold, len(other.Alerts))
+	}
+
+	empty, _ := parseOpsConfig(nil)
+	if kc := empty.resolved("x"); kc.staleAfter != defaultOpsStaleAfter || kc.ErrorThreshold != defaultOpsErrorThreshold {
+		t.Errorf("built-in defaults not applied: staleAfter=%s, errorThreshold=%d", kc.staleAfter, kc.ErrorThreshold)
+	}
+}
+
+func TestOpsAlertRule_Evaluate(t *testing.T) {
+	doc := map[string]any{
+		"status": "running",
+		"paused": false,
+		"today":  map[string]any{"count": float64(12), "target": float64(30)},
+	}
+	at := func(hh, mm int) time.Time { return time.Date(2026, 5, 26, hh, mm, 0, 0, time.Local) }
+
+	tests := []struct {
+		name       string
+		when       string
+		by         string
+		message    string
+		now        time.Time
+		wantFiring bool
+		wantSkip   bool
+		wantErr    bool
+		wantMsg    string
+	}{
+		{name: "数値比較で発火", when: "today.count < today.target", now: at(10, 0), wantFiring: true, wantMsg: "today.count < today.target (12 < 30)"},
+		{name: "数値リテラルとの比較", when: "today.count >= 30", now: at(10, 0)},
+		{name: "by前は評価しない", when: "today.count < today.target", by: "18:00", now: at(17, 59), wantSkip: true},
+		{name: "by以降は評価する", when: "today.count < today.target", by: "18:00", now: at(18, 0), wantFiring: true},
+		{name: "文字列の一致", when: `status == "running"`, message: "稼働中", now: at(10, 0), wantFiring: true, wantMsg: "稼働中"},
+		{name: "真偽値の不一致", when: "paused != true", now: at(10, 0), wantFiring: true},
+		{name: "参照先が無い", when: "today.missing < 1", now: at(10, 0), wantErr: true},
+		{name: "型が違う", when: "status < 1", now: at(10, 0), wantErr: true},
+		{name: "文字列に大小比較", when: "status < 'a'", now: at(10, 0), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := opsAlertRule{Name: "r", When: tt.when, By: tt.by, Message: tt.message}
+			if err := r.compile(); err != nil {
+				t.Fatalf("compile: %v", err)
+			}
+			got := r.evaluate(doc, tt.now)
+			if got.Firing != tt.wantFiring {
+				t.Errorf("firing = %v, want %v (error=%q)", got.Firing, tt.wantFiring, got.Error)
+			}
+			if got.Skipped != tt.wantSkip {
+				t.Errorf("skipped = %v, want %v", got.Skipped, tt.wantSkip)
+			}
+			if (got.Error != "") != tt.wantErr {
+				t.Errorf("error = %q, wantErr %v", got.Error, tt.wantErr)
+			}
+			if tt.wantMsg != "" && got.Message != tt.wantMsg {
+				t.Errorf("message = %q, want %q", got.Message, tt.wantMsg)
+			}
+			if got.Severity != OpsSeverityCritical {
+				t.Errorf("severity = %q, want critical by default", got.Severity)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"ghostrunner/backend/internal/notify"
+	"ghostrunner/backend/internal/projects"
+	"ghostrunner/backend/internal/service"
+)
+
+// opsMonitorInterval は運用アラートの評価間隔です
+const opsMonitorInterval = time.Minute
+
+// firingAlert は発火中として通知済みのアラートです
+type firingAlert struct {
+	path    string
+	project string
+	label   string
+	alert   OpsAlert
+}
+
+// OpsMonitor は登録プロジェクトの運用状態を定期的に評価し、運用/ops.yaml のアラート条件が
+// 発火へ遷移した時と解除された時に通知するバックグラウンドジョブです。
+// 発火状態はメモリにのみ保持するため、再起動後に発火中のアラートは再度1回通知されます。
+type OpsMonitor struct {
+	projectsProvider func() ([]projects.Project, error)
+	notifier         service.NtfyService
+	now              func() time.Time
+
+	mu     sync.Mutex
+	firing map[string]firingAlert // key: プロジェクトパス + 状態ファイル + ルール名
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewOpsMonitor は新しいOpsMonitorを生成します。
+// notifier は nil 許容で、nil の場合は通知せず遷移のログのみ出します。now が nil の場合は time.Now を使います。
+func NewOpsMonitor(projectsProvider func() ([]projects.Project, error), notifier service.NtfyService, now func() time.Time) *OpsMonitor {
+	if now == nil {
+		now = time.Now
+	}
+	return &OpsMonitor{
+		projectsProvider: projectsProvider,
+		notifier:         notifier,
+		now:              now,
+		firing:           make(map[string]firingAlert),
+	}
+}
+
+// Start は運用アラートの評価を開始します。ctx のキャンセルまたは Stop で終了します。
+func (m *OpsMonitor) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	m.cancel = cancel
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(opsMonitorInterval)
+		defer ticker.Stop()
+
+		log.Printf("[OpsMonitor] started: interval=%s", opsMonitorInterval)
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("[OpsMonitor] stopped")
+				return
+			case <-ticker.C:
+				m.Check(ctx)
+			}
+		}
+	}()
+}
+
+// Stop は運用アラートの評価を停止し、実行中のtickが終わるまで待機します
+func (m *OpsMonitor) Stop() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.wg.Wait()
+}
+
+// Check は登録プロジェクトの運用状態を1回評価し、発火・解除の遷移を通知します。
+// 運用/ ディレクトリの無いプロジェクトは対象外です。状態ファイルやルールが消えたアラートは通知せず追跡を外します。
+func (m *OpsMonitor) Check(ctx context.Context) {
+	projs, err := m.projectsProvider()
+	if err != nil {
+		log.Printf("[OpsMonitor] load projects failed: %v", err)
+		return
+	}
+
+	now := m.now()
+	seen := make(map[string]bool)
+	for _, p := range projs {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		if info, err := os.Stat(filepath.Join(p.Path, "運用")); err != nil || !info.IsDir() {
+			continue
+		}
+		var warnings []string
+		for _, op := range scanOps(p.Path, now, &warnings) {
+			for _, a := range op.Alerts {
+				key := p.Path + "\x00" + op.SourceFile + "\x00" + a.Rule
+				seen[key] = true
+				m.update(key, firingAlert{path: p.Path, project: projectName(p), label: opsLabel(op), alert: a})
+			}
+		}
+	}
+
+	m.mu.Lock()
+	for key, fa := range m.firing {
+		if !seen[key] {
+			log.Printf("[OpsMonitor] alert untracked: project=%s, entry=%s, rule=%s", fa.project, fa.label, fa.alert.Rule)
+			delete(m.firing, key)
+		}
+	}
+	m.mu.Unlock()
+}
+
+// update は1アラートの発火状態を更新し、発火への遷移と解除の時のみ通知します。
+// 評価できなかった（Error あり）場合と By の時刻より前で評価していない（Skipped）場合は
+// 直前の状態を保持し、解除とはみなしません（日付が変わるたびに解除を通知しないため）。
+func (m *OpsMonitor) update(key string, fa firingAlert) {
+	if fa.alert.Error != "" || fa.alert.Skipped {
+		return
+	}
+	m.mu.Lock()
+	prev, wasFiring := m.firing[key]
+	if fa.alert.Firing {
+		m.firing[key] = fa
+	} else {
+		delete(m.firing, key)
+	}
+	m.mu.Unlock()
+
+	switch {
+	case fa.alert.Firing && !wasFiring:
+		log.Printf("[OpsMonitor] alert firing: project=%s, entry=%s, rule=%s, severity=%s", fa.project, fa.label, fa.alert.Rule, fa.alert.Severity)
+		if m.notifier == nil {
+			return
+		}
+		msg := notify.Message{
+			Event:    notify.EventOpsAlert,
+			Project:  fa.path,
+			Title:    fmt.Sprintf("運用アラート: %s", fa.project),
+			Body:     fmt.Sprintf("[%s] %s: %s", fa.alert.Rule, fa.label, fa.alert.Message),
+			Priority: notify.PriorityDefault,
+		}
+		if fa.alert.Severity == OpsSeverityCritical {
+			msg.Priority = notify.PriorityHigh
+		}
+		notify.Send(m.notifier, msg)
+	case !fa.alert.Firing && wasFiring:
+		log.Printf("[OpsMonitor] alert cleared: project=%s, entry=%s, rule=%s", fa.project, fa.label, fa.alert.Rule)
+		notify.Send(m.notifier, notify.Message{
+			Event:    notify.EventOpsCleared,
+			Project:  fa.path,
+			Title:    fmt.Sprintf("運用アラート解除: %s", fa.project),
+			Body:     fmt.Sprintf("[%s] %s: 条件が解消しました（%s）", prev.alert.Rule, fa.label, prev.alert.Message),
+			Priority: notify.PriorityDefault,
+		})
+	}
+}
+
+// projectName はプロジェクトの表示名を返します（名前未設定時はディレクトリ名）
+func projectName(p projects.Project) string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return filepath.Base(p.Path)
+}
+
+// opsLabel は通知に使う運用状態の識別名を返します（account/kind、無ければ状態ファイル名）
+func opsLabel(op OpsEntry) string {
+	switch {
+	case op.Account != "" && op.Kind != "":
+		return op.Account + "/" + op.Kind
+	case op.Account != "":
+		return op.Account
+	case op.Kind != "":
+		return op.Kind
+	}
+	return filepath.Base(op.SourceFile)
+}
@@ -0,0 +1,149 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/projects"
+)
+
+// opsNotifier は通常通知とエラー通知を区別して記録するテスト用スタブです
+type opsNotifier struct {
+	mu     sync.Mutex
+	infos  []string
+	errors []string
+}
+
+func (n *opsNotifier) Notify(title, message string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.infos = append(n.infos, title+": "+message)
+}
+
+func (n *opsNotifier) NotifyError(title, message string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.errors = append(n.errors, title+": "+message)
+}
+
+// writeOpsState は 運用/状態/<name>.json を書き込みます
+func writeOpsState(t *testing.T, projectPath, name string, v map[string]any) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, projectPath, filepath.Join("運用", "状態", name+".json"), string(data))
+}
+
+func TestOpsMonitor_NotifiesOnFiringAndClear(t *testing.T) {
+	dir := t.TempDir()
+	proj := filepath.Join(dir, "proj")
+	writeFile(t, proj, filepath.Join("運用", "ops.yaml"), `
+kinds:
+  follower:
+    alerts:
+      - name: daily-target
+        when: today.count < today.target
+        by: "18:00"
+        message: 本日の目標に未達です
+`)
+	writeOpsState(t, proj, "acc", map[string]any{
+		"account": "acc", "kind": "follower", "status": "running",
+		"today": map[string]any{"count": 5, "target": 10},
+	})
+
+	now := time.Date(2026, 5, 26, 17, 0, 0, 0, time.Local)
+	notifier := &opsNotifier{}
+	m := NewOpsMonitor(func() ([]projects.Project, error) {
+		return []projects.Project{{Path: proj, Name: "proj"}}, nil
+	}, notifier, func() time.Time { return now })
+	ctx := context.Background()
+
+	// by 前は発火しない
+	m.Check(ctx)
+	if len(notifier.errors) != 0 || len(notifier.infos) != 0 {
+		t.Fatalf("expected no notification before 18:00, got errors=%v infos=%v", notifier.errors, notifier.infos)
+	}
+
+	// 18:00 以降に発火 → 1回だけ通知
+	now = now.Add(2 * time.Hour)
+	m.Check(ctx)
+	m.Check(ctx)
+	if len(notifier.errors) != 1 {
+		t.Fatalf("expected 1 firing notification, got %v", notifier.errors)
+	}
+	if want := "運用アラート: proj: [daily-target] acc/follower: 本日の目標に未達です"; notifier.errors[0] != want {
+		t.Errorf("notification = %q, want %q", notifier.errors[0], want)
+	}
+
+	// 日付が変わって by 前に戻っても解除とはみなさない
+	now = now.Add(6 * time.Hour)
+	m.Check(ctx)
+	if len(notifier.infos) != 0 {
+		t.Fatalf("expected no clear notification after midnight, got %v", notifier.infos)
+	}
+	now = now.Add(18 * time.Hour)
+
+	// 達成 → 解除を1回通知
+	writeOpsState(t, proj, "acc", map[string]any{
+		"account": "acc", "kind": "follower", "status": "running",
+		"today": map[string]any{"count": 10, "target": 10},
+	})
+	m.Check(ctx)
+	m.Check(ctx)
+	if len(notifier.infos) != 1 {
+		t.Fatalf("expected 1 clear notification, got %v", notifier.infos)
+	}
+	if len(notifier.errors) != 1 {
+		t.Errorf("expected no further firing notification, got %v", notifier.errors)
+	}
+}
+
+func TestOpsMonitor_KeepsStateOnEvaluationError(t *testing.T) {
+	dir := t.TempDir()
+	proj := filepath.Join(dir, "proj")
+	writeFile(t, proj, filepath.Join("運用", "ops.yaml"), `
+defaults:
+  alerts:
+    - name: errors
+      when: consecutiveErrors > 0
+      severity: warning
+`)
+	writeOpsState(t, proj, "acc", map[string]any{"status": "running", "consecutiveErrors": 2})
+
+	notifier := &opsNotifier{}
+	m := NewOpsMonitor(func() ([]projects.Project, error) {
+		return []projects.Project{{Path: proj}}, nil
+	}, notifier, nil)
+	ctx := context.Background()
+
+	m.Check(ctx)
+	if len(notifier.infos) != 1 || len(notifier.errors) != 0 {
+		t.Fatalf("warning should be sent as normal notification: infos=%v errors=%v", notifier.infos, notifier.errors)
+	}
+
+	// フィールドが消えて評価できない間は解除とみなさない
+	writeOpsState(t, proj, "acc", map[string]any{"status": "running"})
+	m.Check(ctx)
+	if len(notifier.infos) != 1 {
+		t.Errorf("evaluation error should not clear alert: infos=%v", notifier.infos)
+	}
+
+	// 状態ファイルが消えたアラートは通知せず追跡を外す
+	if err := os.Remove(filepath.Join(proj, "運用", "状態", "acc.json")); err != nil {
+		t.Fatal(err)
+	}
+	m.Check(ctx)
+	if len(notifier.infos) != 1 {
+		t.Errorf("removed entry should not notify: infos=%v", notifier.infos)
+	}
+	if len(m.firing) != 0 {
+		t.Errorf("expected no tracked alerts, got %d", len(m.firing))
+	}
+}
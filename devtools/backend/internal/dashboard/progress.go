@@ -0,0 +1,386 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"ghostrunner/backend/internal/idle"
+	"ghostrunner/backend/internal/notify"
+	"ghostrunner/backend/internal/service"
+	"ghostrunner/backend/internal/transcript"
+)
+
+const (
+	// progressTickInterval は進捗要約ジョブの実行間隔です（要約するかは ProgressSummarizer の interval で判定）
+	progressTickInterval = 30 * time.Second
+	// DefaultProgressInterval は同一セッションの進捗を再要約する最小間隔の既定値です
+	DefaultProgressInterval = 5 * time.Minute
+	// maxProgressEditedFiles は進捗に残す編集ファイルの最大数です（古いものから落とします）
+	maxProgressEditedFiles = 20
+)
+
+// ProgressSummarizerOption は ProgressSummarizer の任意設定です
+type ProgressSummarizerOption func(*ProgressSummarizer)
+
+// WithProgressNotifier は進捗要約を更新したときに session.progress を低い優先度で通知します
+func WithProgressNotifier(n service.NtfyService) ProgressSummarizerOption {
+	return func(p *ProgressSummarizer) {
+		p.notifier = n
+	}
+}
+
+// progressSession は進捗を追跡中の動作中セッションです。
+type progressSession struct {
+	cwd         string
+	path        string
+	offset      int64     // 要約済みの会話ログの末尾位置
+	lastAttempt time.Time // 要約の試行時刻（結果によらず記録）
+	summary     string
+	at          string // 要約時刻（RFC3339）
+	editedFiles []string
+}
+
+// ProgressSummarizer は動作中セッションの会話ログを前回位置からの差分（直近のツール呼び出し・編集ファイル）で
+// 読み、「いま何をしているか／ここまでに済んだこと」を interval ごとに要約するバックグラウンドジョブです。
+// 要約は CacheKey(sessionID, 会話ログの位置) で cacheDir へ保存し、同じ位置までは再要約しません。
+// 要約がエラーになった場合は次の SummarizeService を順に試します（Summarizer と同じ並び）。
+type ProgressSummarizer struct {
+	reader    idle.Reader
+	svcs      []service.ProgressSummarizeService
+	cacheDir  string
+	interval  time.Duration
+	notifier  service.NtfyService
+	now       func() time.Time
+	readDelta func(path string, offset int64) (transcript.Delta, error)
+
+	mu       sync.Mutex
+	sessions map[string]*progressSession // key: sessionID
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewProgressSummarizer は新しいProgressSummarizerを生成します。
+// svcs のうち ProgressSummarizeService を実装するものを順に使います。
+// interval が0以下の場合は DefaultProgressInterval、now が nil の場合は time.Now を使います。
+func NewProgressSummarizer(reader idle.Reader, svcs []service.SummarizeService, cacheDir string, interval time.Duration, now func() time.Time, opts ...ProgressSummarizerOption) *ProgressSummarizer {
+	if now == nil {
+		now = time.Now
+	}
+	if interval <= 0 {
+		interval = DefaultProgressInterval
+	}
+	p := &ProgressSummarizer{
+		reader:    reader,
+		cacheDir:  cacheDir,
+		interval:  interval,
+		now:       now,
+		readDelta: transcript.ReadDelta,
+		sessions:  make(map[string]*progressSession),
+	}
+	for _, svc := range svcs {
+		if ps, ok := svc.(service.ProgressSummarizeService); ok {
+			p.svcs = append(p.svcs, ps)
+		}
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Start は進捗要約ジョブを開始します。ctx のキャンセルまたは Stop で終了します。
+func (p *ProgressSummarizer) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	p.cancel = cancel
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(progressTickInterval)
+		defer ticker.Stop()
+
+		log.Printf("[ProgressSummarizer] started: interval=%s, providers=%d", p.interval, len(p.svcs))
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("[ProgressSummarizer] stopped")
+				return
+			case <-ticker.C:
+				p.Check(ctx)
+			}
+		}
+	}()
+}
+
+// Stop は進捗要約ジョブを停止し、実行中のtickが終わるまで待機します
+func (p *ProgressSummarizer) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+}
+
+// Check はマーカーを読み取り、1回分の進捗要約を行います
+func (p *ProgressSummarizer) Check(ctx context.Context) {
+	markers, err := p.reader.List(ctx)
+	if err != nil {
+		log.Printf("[ProgressSummarizer] list markers failed: %v", err)
+		return
+	}
+	p.Observe(ctx, markers, p.now())
+}
+
+// Observe はマーカー群から動作中セッションを追跡し、前回の要約から interval 以上経ったセッションの差分を要約します。
+//   - 初めて見る動作中セッションはキャッシュの最新の要約と位置から再開する（サーバー再起動をまたぐ）
+//   - 動作中でなくなったセッションは追跡から外し、マーカーから消えたセッションのキャッシュは削除する
+//   - 差分にツール呼び出し・発言が無ければ要約しない
+func (p *ProgressSummarizer) Observe(ctx context.Context, markers []idle.Marker, now time.Time) {
+	alive := make(map[string]bool, len(markers))
+	running := make(map[string]idle.Marker, len(markers))
+	for _, m := range markers {
+		alive[m.SessionID] = true
+		if m.Status == idle.StatusRunning && m.TranscriptPath != "" {
+			running[m.SessionID] = m
+		}
+	}
+
+	p.mu.Lock()
+	for id := range p.sessions {
+		if _, ok := running[id]; !ok {
+			delete(p.sessions, id)
+		}
+	}
+	var due []string
+	for id, m := range running {
+		ps, ok := p.sessions[id]
+		if !ok {
+			ps = p.restore(m)
+			p.sessions[id] = ps
+		}
+		if now.Sub(ps.lastAttempt) >= p.interval {
+			due = append(due, id)
+		}
+	}
+	p.mu.Unlock()
+
+	if p.cacheDir != "" {
+		if err := idle.PruneProgressCache(p.cacheDir, alive); err != nil {
+			log.Printf("[ProgressSummarizer] prune progress cache failed: %v", err)
+		}
+	}
+
+	// 会話ログの読み取り・要約（IO）はロック外で行う
+	slices.Sort(due)
+	for _, id := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		p.summarizeOne(ctx, id, now)
+	}
+}
+
+// restore はキャッシュの最新の要約から追跡状態を復元します（無ければ会話ログの先頭から）。
+func (p *ProgressSummarizer) restore(m idle.Marker) *progressSession {
+	ps := &progressSession{cwd: m.Cwd, path: m.TranscriptPath}
+	if p.cacheDir == "" {
+		return ps
+	}
+	entry, ok := idle.LatestProgressCache(p.cacheDir, m.SessionID)
+	if !ok {
+		return ps
+	}
+	ps.offset = entry.Offset
+	ps.summary = entry.Summary
+	ps.at = entry.SummarizedAt
+	ps.editedFiles = entry.EditedFiles
+	if t, err := time.Parse(time.RFC3339, entry.SummarizedAt); err == nil {
+		ps.lastAttempt = t
+	}
+	return ps
+}
+
+// summarizeOne は1セッションの差分を読み、要約（またはキャッシュ）で進捗を更新します。
+func (p *ProgressSummarizer) summarizeOne(ctx context.Context, sessionID string, now time.Time) {
+	p.mu.Lock()
+	ps, ok := p.sessions[sessionID]
+	if !ok {
+		p.mu.Unlock()
+		return
+	}
+	snapshot := *ps
+	p.mu.Unlock()
+
+	delta, err := p.readDelta(snapshot.path, snapshot.offset)
+	if err != nil {
+		log.Printf("[ProgressSummarizer] read delta failed: session=%s, error=%v", sessionID, err)
+		progressSummariesTotal.Inc("read_error")
+		return
+	}
+	if delta.Empty() {
+		// 活動が無いまま位置だけ進んだ（帳簿型エントリ等）。次回の差分の起点だけ進める
+		p.commit(sessionID, delta.Offset, nil)
+		return
+	}
+
+	editedFiles := mergeEditedFiles(snapshot.editedFiles, delta.EditedFiles, delta.Reset)
+	var entry idle.ProgressCacheEntry
+	cached := false
+	if p.cacheDir != "" {
+		entry, cached = idle.ReadProgressCache(p.cacheDir, sessionID, delta.Offset)
+	}
+	if cached {
+		progressSummariesTotal.Inc("cached")
+	} else {
+		p.markAttempt(sessionID, now)
+		previous := snapshot.summary
+		if delta.Reset {
+			previous = ""
+		}
+		summary, err := p.summarize(ctx, sessionID, service.ProgressInput{
+			ToolCalls:     formatToolCalls(delta.ToolCalls),
+			EditedFiles:   editedFiles,
+			LastAssistant: delta.LastAssistant,
+			Previous:      previous,
+		})
+		if err != nil {
+			log.Printf("[ProgressSummarizer] summarize failed: session=%s, error=%v", sessionID, err)
+			progressSummariesTotal.Inc("summarize_error")
+			return
+		}
+		if summary == "" {
+			progressSummariesTotal.Inc("empty")
+			return
+		}
+		entry = idle.ProgressCacheEntry{
+			SessionID:    sessionID,
+			Offset:       delta.Offset,
+			Summary:      summary,
+			EditedFiles:  editedFiles,
+			SummarizedAt: p.now().Format(time.RFC3339),
+		}
+		if p.cacheDir != "" {
+			if err := idle.WriteProgressCache(p.cacheDir, entry); err != nil {
+				// 表示は更新する（再起動後に同じ差分を要約し直すだけ）
+				log.Printf("[ProgressSummarizer] write progress cache failed: session=%s, error=%v", sessionID, err)
+				progressSummariesTotal.Inc("write_error")
+			}
+		}
+		progressSummariesTotal.Inc("summarized")
+	}
+
+	if !p.commit(sessionID, delta.Offset, &entry) {
+		return
+	}
+	log.Printf("[ProgressSummarizer] progress updated: session=%s, offset=%d, cached=%t", sessionID, delta.Offset, cached)
+	if p.notifier != nil && !cached {
+		notify.Send(p.notifier, notify.Message{
+			Event:    notify.EventSessionProgress,
+			Project:  snapshot.cwd,
+			Title:    fmt.Sprintf("進捗: %s", filepath.Base(snapshot.cwd)),
+			Body:     entry.Summary,
+			Priority: notify.PriorityLow,
+		})
+	}
+}
+
+// summarize は ProgressSummarizeService を先頭から順に試し、最初に成功した要約を返します。
+// すべてエラーの場合は最後のエラーを返します
+func (p *ProgressSummarizer) summarize(ctx context.Context, sessionID string, in service.ProgressInput) (string, error) {
+	lastErr := fmt.Errorf("no progress summarizer configured")
+	for _, svc := range p.svcs {
+		name := providerName(svc)
+		summary, err := svc.SummarizeProgress(ctx, in)
+		if err == nil {
+			summarizerProviderTotal.Inc(name, "ok")
+			return summary, nil
+		}
+		summarizerProviderTotal.Inc(name, "error")
+		lastErr = err
+		if ctx.Err() != nil {
+			break
+		}
+		log.Printf("[ProgressSummarizer] provider failed, falling back: session=%s, provider=%s, error=%v", sessionID, name, err)
+	}
+	return "", lastErr
+}
+
+// markAttempt は要約の試行時刻を記録します（失敗しても interval の間は再試行しない）。
+func (p *ProgressSummarizer) markAttempt(sessionID string, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if ps, ok := p.sessions[sessionID]; ok {
+		ps.lastAttempt = now
+	}
+}
+
+// commit は要約済みの位置を offset へ進め、entry があれば要約を更新します。
+// 要約中にセッションが追跡から外れていれば false を返します。
+func (p *ProgressSummarizer) commit(sessionID string, offset int64, entry *idle.ProgressCacheEntry) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ps, ok := p.sessions[sessionID]
+	if !ok {
+		return false
+	}
+	ps.offset = offset
+	if entry != nil {
+		ps.summary = entry.Summary
+		ps.at = entry.SummarizedAt
+		ps.editedFiles = entry.EditedFiles
+	}
+	return true
+}
+
+// progressBySession は要約済みの進捗を sessionID ごとに返します。
+func (p *ProgressSummarizer) progressBySession() map[string]progressSession {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make(map[string]progressSession, len(p.sessions))
+	for id, ps := range p.sessions {
+		if ps.summary != "" {
+			out[id] = *ps
+		}
+	}
+	return out
+}
+
+// mergeEditedFiles は前回までの編集ファイルへ差分の編集ファイルを重複なく足し、直近 maxProgressEditedFiles 件を返します。
+// reset（会話ログの書き直し）の場合は前回までの分を捨てます。
+func mergeEditedFiles(prev, added []string, reset bool) []string {
+	var out []string
+	if !reset {
+		out = append(out, prev...)
+	}
+	for _, f := range added {
+		if i := slices.Index(out, f); i >= 0 {
+			out = slices.Delete(out, i, i+1)
+		}
+		out = append(out, f)
+	}
+	if len(out) > maxProgressEditedFiles {
+		out = out[len(out)-maxProgressEditedFiles:]
+	}
+	return out
+}
+
+// formatToolCalls はツール呼び出しを要約プロンプト用の "Name: target" 形式にします（失敗は " (error)" を付けます）。
+func formatToolCalls(calls []transcript.ToolCall) []string {
+	out := make([]string, 0, len(calls))
+	for _, c := range calls {
+		s := c.Name
+		if c.Target != "" {
+			s += ": " + c.Target
+		}
+		if c.IsError {
+			s += " (error)"
+		}
+		out = append(out, s)
+	}
+	return out
+}
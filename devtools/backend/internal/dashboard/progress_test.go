@@ -0,0 +1,149 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/idle"
+	"ghostrunner/backend/internal/service"
+	"ghostrunner/backend/internal/transcript"
+)
+
+// fakeProgressService は service.SummarizeService と service.ProgressSummarizeService を満たすテスト用スタブです
+type fakeProgressService struct {
+	fakeSummarizeService
+	mu     sync.Mutex
+	inputs []service.ProgressInput
+	result string
+	err    error
+}
+
+func (s *fakeProgressService) SummarizeProgress(ctx context.Context, in service.ProgressInput) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inputs = append(s.inputs, in)
+	return s.result, s.err
+}
+
+func (s *fakeProgressService) progressCalls() []service.ProgressInput {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.inputs)
+}
+
+// newTestProgressSummarizer は readDelta を offset → Delta の表で差し替えた ProgressSummarizer を返します
+func newTestProgressSummarizer(t *testing.T, cacheDir string, deltas map[int64]transcript.Delta, svcs ...service.SummarizeService) *ProgressSummarizer {
+	t.Helper()
+	p := NewProgressSummarizer(nil, svcs, cacheDir, 5*time.Minute, func() time.Time { return time.Unix(10000, 0).UTC() })
+	p.readDelta = func(path string, offset int64) (transcript.Delta, error) {
+		d, ok := deltas[offset]
+		if !ok {
+			return transcript.Delta{From: offset, Offset: offset}, nil
+		}
+		return d, nil
+	}
+	return p
+}
+
+func TestProgressSummarizer_Observe(t *testing.T) {
+	now := time.Unix(10000, 0).UTC()
+	cacheDir := t.TempDir()
+	deltas := map[int64]transcript.Delta{
+		0: {Offset: 100, ToolCalls: []transcript.ToolCall{{Name: "Edit", Target: "/app/a.go"}}, EditedFiles: []string{"/app/a.go"}},
+		100: {From: 100, Offset: 250, ToolCalls: []transcript.ToolCall{{Name: "Bash", Target: "go test ./...", IsError: true}},
+			EditedFiles: []string{"/app/b.go"}},
+	}
+	svc := &fakeProgressService{result: "いま: 実装中"}
+	p := newTestProgressSummarizer(t, cacheDir, deltas, svc)
+	markers := []idle.Marker{
+		{SessionID: "run", Cwd: "/app", Status: idle.StatusRunning, TranscriptPath: "/t/run.jsonl"},
+		{SessionID: "wait", Cwd: "/other", Status: idle.StatusWaiting, TranscriptPath: "/t/wait.jsonl"},
+	}
+
+	p.Observe(context.Background(), markers, now)
+	got := p.progressBySession()
+	if len(got) != 1 || got["run"].summary != "いま: 実装中" || got["run"].offset != 100 {
+		t.Fatalf("progress after first observe = %+v", got)
+	}
+
+	// interval 内は再要約しない
+	p.Observe(context.Background(), markers, now.Add(time.Minute))
+	if n := len(svc.progressCalls()); n != 1 {
+		t.Fatalf("summarize calls within interval = %d, want 1", n)
+	}
+
+	// interval 経過後は前回位置からの差分と前回の要約を渡す
+	svc.result = "いま: テスト修正／済: a.go, b.go"
+	p.Observe(context.Background(), markers, now.Add(6*time.Minute))
+	calls := svc.progressCalls()
+	if len(calls) != 2 {
+		t.Fatalf("summarize calls = %d, want 2", len(calls))
+	}
+	in := calls[1]
+	if in.Previous != "いま: 実装中" || !slices.Equal(in.ToolCalls, []string{"Bash: go test ./... (error)"}) ||
+		!slices.Equal(in.EditedFiles, []string{"/app/a.go", "/app/b.go"}) {
+		t.Errorf("second input = %+v", in)
+	}
+
+	// 再起動相当: 新しい ProgressSummarizer はキャッシュの最新位置から再開し、同じ位置は再要約しない
+	restarted := newTestProgressSummarizer(t, cacheDir, deltas, svc)
+	restarted.Observe(context.Background(), markers, now.Add(7*time.Minute))
+	if n := len(svc.progressCalls()); n != 2 {
+		t.Errorf("summarize calls after restart = %d, want 2 (resume from cache)", n)
+	}
+	if ps := restarted.progressBySession()["run"]; ps.offset != 250 || ps.summary != svc.result {
+		t.Errorf("restored progress = %+v", ps)
+	}
+
+	// 動作中でなくなったセッションは追跡から外し、マーカーから消えたセッションのキャッシュは削除する
+	restarted.Observe(context.Background(), markers[1:], now.Add(20*time.Minute))
+	if len(restarted.progressBySession()) != 0 {
+		t.Errorf("progress should be dropped when the session stops running")
+	}
+	if _, ok := idle.LatestProgressCache(cacheDir, "run"); ok {
+		t.Errorf("progress cache of a vanished session should be pruned")
+	}
+}
+
+func TestProgressSummarizer_FallbackAndEmpty(t *testing.T) {
+	now := time.Unix(10000, 0).UTC()
+	deltas := map[int64]transcript.Delta{
+		0: {Offset: 100, LastAssistant: "調べています"},
+	}
+	failing := &fakeProgressService{err: errors.New("quota exceeded")}
+	fallback := &fakeProgressService{result: "いま: 調査中"}
+	p := newTestProgressSummarizer(t, "", deltas, failing, fallback)
+	markers := []idle.Marker{{SessionID: "run", Cwd: "/app", Status: idle.StatusRunning, TranscriptPath: "/t/run.jsonl"}}
+
+	p.Observe(context.Background(), markers, now)
+	if ps := p.progressBySession()["run"]; ps.summary != "いま: 調査中" {
+		t.Errorf("summary = %q, want fallback summary", ps.summary)
+	}
+
+	// 活動の無い差分は要約せず、位置だけ進める
+	p.Observe(context.Background(), markers, now.Add(10*time.Minute))
+	if n := len(fallback.progressCalls()); n != 1 {
+		t.Errorf("summarize calls for empty delta = %d, want 1", n)
+	}
+}
+
+func TestAttachProgress(t *testing.T) {
+	states := []ProjectState{
+		{Path: "/a", Running: &RunningState{SessionID: "s1"}},
+		{Path: "/b", Running: &RunningState{SessionID: "s2"}},
+		{Path: "/c"},
+	}
+	attachProgress(states, map[string]progressSession{
+		"s1": {summary: "いま: 実装中", at: "2026-07-20T10:00:00Z", editedFiles: []string{"/a/x.go"}},
+	})
+	if r := states[0].Running; r.Progress != "いま: 実装中" || r.ProgressAt != "2026-07-20T10:00:00Z" || len(r.EditedFiles) != 1 {
+		t.Errorf("running /a = %+v", r)
+	}
+	if r := states[1].Running; r.Progress != "" {
+		t.Errorf("running /b should have no progress, got %+v", r)
+	}
+}
@@ -5,19 +5,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"time"
 
-	"ghostrunner/backend/internal/grrun"
+	"ghostrunner/backend/internal/plandoc"
 )
 
-var unansweredPattern = grrun.UnansweredPattern
-
-var unansweredRe = regexp.MustCompile(unansweredPattern)
-
-// headingRe は確認事項の見出し行を検出する正規表現です
-var headingRe = regexp.MustCompile(`^### Q\d+:`)
+var unansweredPattern = plandoc.UnansweredPattern
 
 // GetPatternForTest はSSOT検証用にパターン文字列を返します
 func GetPatternForTest() string {
@@ -95,51 +89,26 @@ func scanUnanswered(projectPath, dir string, warnings *[]string) []UnansweredQue
 	}
 
 	for _, filePath := range matches {
-		data, err := os.ReadFile(filePath)
+		doc, err := plandoc.ParseFile(filePath)
 		if err != nil {
 			*warnings = append(*warnings, fmt.Sprintf("failed to read %s: %v", filePath, err))
 			continue
 		}
 
-		lines := strings.Split(string(data), "\n")
-		for i, line := range lines {
-			if !unansweredRe.MatchString(line) {
-				continue
-			}
-
-			lineNum := i + 1 // 1-based
-
-			// 見出しを後方検索
-			heading := ""
-			questionText := ""
-			for j := i - 1; j >= 0; j-- {
-				if headingRe.MatchString(lines[j]) {
-					heading = strings.TrimSpace(lines[j])
-					// 見出しとステータス行の間のテキストを質問文とする
-					var textParts []string
-					for k := j + 1; k < i; k++ {
-						trimmed := strings.TrimSpace(lines[k])
-						if trimmed != "" && !strings.HasPrefix(trimmed, "**") {
-							textParts = append(textParts, trimmed)
-						}
-					}
-					questionText = strings.Join(textParts, " ")
-					break
-				}
-			}
-
-			// プロジェクトパスからの相対パスを計算
-			relPath, err := filepath.Rel(projectPath, filePath)
-			if err != nil {
-				relPath = filePath
-			}
+		// プロジェクトパスからの相対パスを計算
+		relPath, err := filepath.Rel(projectPath, filePath)
+		if err != nil {
+			relPath = filePath
+		}
 
+		for _, q := range doc.Unanswered() {
 			questions = append(questions, UnansweredQuestion{
 				PlanPath:     relPath,
-				LineStart:    lineNum,
-				LineEnd:      lineNum,
-				QuestionText: questionText,
-				Heading:      heading,
+				QuestionID:   q.ID,
+				LineStart:    q.StatusLine,
+				LineEnd:      q.StatusLine,
+				QuestionText: q.Text,
+				Heading:      q.Heading,
 			})
 		}
 	}
@@ -159,11 +128,22 @@ type opsJSON struct {
 	UpdatedAt         string       `json:"updatedAt"`
 }
 
-// scanOps は運用/状態ディレクトリからOpsEntryを収集します
+// opsKnownKeys は opsJSON で解釈済みのため rawExtra から除外するキーです
+var opsKnownKeys = []string{"account", "kind", "status", "progress", "today", "stats", "consecutiveErrors", "updatedAt"}
+
+// scanOps は運用/状態ディレクトリからOpsEntryを収集します。
+// 運用/ops.yaml があれば kind ごとの期待フィールド・停滞時間・連続エラーしきい値・アラート条件を適用し、
+// 無い・壊れている場合は既定値（停滞3時間・連続エラー3回・アラートなし）で判定します
 func scanOps(projectPath string, now time.Time, warnings *[]string) []OpsEntry {
 	var entries []OpsEntry
 	stateDir := filepath.Join(projectPath, "運用", "状態")
 
+	cfg, err := loadOpsConfig(projectPath)
+	if err != nil {
+		*warnings = append(*warnings, err.Error())
+		cfg, _ = parseOpsConfig(nil)
+	}
+
 	matches, err := filepath.Glob(filepath.Join(stateDir, "*.json"))
 	if err != nil {
 		*warnings = append(*warnings, fmt.Sprintf("failed to glob ops state: %v", err))
@@ -188,16 +168,42 @@ func scanOps(projectPath string, now time.Time, warnings *[]string) []OpsEntry {
 			*warnings = append(*warnings, fmt.Sprintf("failed to parse %s: %v", filePath, err))
 			continue
 		}
+		// 期待フィールドとアラート条件はJSON全体に対して評価する
+		var doc map[string]any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			doc = nil
+		}
 
-		// staleness判定: ファイル更新から3時間以上かつstatus=running
-		staleHours := int(now.Sub(info.ModTime()).Hours())
-		stale := now.Sub(info.ModTime()).Hours() >= 3.0 && raw.Status == "running"
+		relPath, err := filepath.Rel(projectPath, filePath)
+		if err != nil {
+			relPath = filePath
+		}
 
-		// rawExtraとしてJSON全体を保持
+		kc := cfg.resolved(raw.Kind)
+
+		// staleness判定: ファイル更新から staleAfter（既定3時間）以上かつstatus=running
+		age := now.Sub(info.ModTime())
+		staleHours := int(age.Hours())
+		stale := age >= kc.staleAfter && raw.Status == "running"
+
+		missing := missingFields(doc, kc.Fields)
+		if len(missing) > 0 {
+			*warnings = append(*warnings, fmt.Sprintf("%s: missing fields: %s", relPath, strings.Join(missing, ", ")))
+		}
+
+		var alerts []OpsAlert
+		for i := range kc.Alerts {
+			alerts = append(alerts, kc.Alerts[i].evaluate(doc, now))
+		}
+
+		// rawExtraとして既知フィールド以外を保持
 		var rawExtra map[string]any
-		if err := json.Unmarshal(data, &rawExtra); err == nil {
-			// 既知フィールドを除外
-			for _, key := range []string{"account", "kind", "status", "progress", "today", "stats", "consecutiveErrors", "updatedAt"} {
+		if doc != nil {
+			rawExtra = make(map[string]any, len(doc))
+			for k, v := range doc {
+				rawExtra[k] = v
+			}
+			for _, key := range opsKnownKeys {
 				delete(rawExtra, key)
 			}
 			if len(rawExtra) == 0 {
@@ -205,11 +211,6 @@ func scanOps(projectPath string, now time.Time, warnings *[]string) []OpsEntry {
 			}
 		}
 
-		relPath, err := filepath.Rel(projectPath, filePath)
-		if err != nil {
-			relPath = filePath
-		}
-
 		entries = append(entries, OpsEntry{
 			Account:           raw.Account,
 			Kind:              raw.Kind,
@@ -223,24 +224,49 @@ func scanOps(projectPath string, now time.Time, warnings *[]string) []OpsEntry {
 			StaleHours:        staleHours,
 			SourceFile:        relPath,
 			RawExtra:          rawExtra,
+			ErrorThreshold:    kc.ErrorThreshold,
+			MissingFields:     missing,
+			Alerts:            alerts,
 		})
 	}
 
 	return entries
 }
 
+// needsAttention は運用状態1件が要対応かを判定します。
+// blocked・停滞・連続エラーがしきい値以上（未設定時は既定3回）・critical アラートの発火中のいずれかで要対応です
+func (op OpsEntry) needsAttention() bool {
+	threshold := op.ErrorThreshold
+	if threshold <= 0 {
+		threshold = defaultOpsErrorThreshold
+	}
+	if op.Status == "blocked" || op.Stale || op.ConsecutiveErrors >= threshold {
+		return true
+	}
+	for _, a := range op.Alerts {
+		if a.Firing && a.Severity == OpsSeverityCritical {
+			return true
+		}
+	}
+	return false
+}
+
 // determineAttention はプロジェクトの注目度を判定します
 func determineAttention(state ProjectState) Attention {
-	// required: 質問待ち（Idle付与済み）、未回答あり、またはops異常
+	// required: 質問待ち（Idle付与済み）、停滞（Stuck付与済み）、未回答あり、またはops異常
 	// Idleはservice.goのattachIdleStateで付与された後に再評価される（C1）
 	if state.Idle != nil {
 		return AttentionRequired
 	}
+	// 停滞セッション（Watchdog 検出）は人の介入が要るため質問待ちと同じく required
+	if state.Stuck != nil {
+		return AttentionRequired
+	}
 	if len(state.Unanswered) > 0 {
 		return AttentionRequired
 	}
 	for _, op := range state.Ops {
-		if op.Status == "blocked" || op.Stale || op.ConsecutiveErrors >= 3 {
+		if op.needsAttention() {
 			return AttentionRequired
 		}
 	}
@@ -136,6 +136,30 @@ func TestScanProject_Attention(t *testing.T) {
 			},
 			expected: AttentionRequired,
 		},
+		{
+			name: "progress: 連続エラーがops.yamlのしきい値未満",
+			state: ProjectState{
+				Unanswered: []UnansweredQuestion{},
+				Ops:        []OpsEntry{{Status: "running", ConsecutiveErrors: 4, ErrorThreshold: 5}},
+			},
+			expected: AttentionProgress,
+		},
+		{
+			name: "required: criticalアラート発火中",
+			state: ProjectState{
+				Unanswered: []UnansweredQuestion{},
+				Ops:        []OpsEntry{{Status: "running", Alerts: []OpsAlert{{Rule: "r", Severity: OpsSeverityCritical, Firing: true}}}},
+			},
+			expected: AttentionRequired,
+		},
+		{
+			name: "progress: warningアラートは注目度を変えない",
+			state: ProjectState{
+				Unanswered: []UnansweredQuestion{},
+				Ops:        []OpsEntry{{Status: "running", Alerts: []OpsAlert{{Rule: "r", Severity: OpsSeverityWarning, Firing: true}}}},
+			},
+			expected: AttentionProgress,
+		},
 		{
 			name: "progress: running > 0",
 			state: ProjectState{
@@ -320,6 +344,84 @@ func TestScanProject_OpsStale(t *testing.T) {
 	}
 }
 
+func TestScanProject_OpsConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, filepath.Join("運用", "ops.yaml"), `
+kinds:
+  follower:
+    fields: [today.count, stats.error]
+    staleAfter: 30m
+    errorThreshold: 5
+    alerts:
+      - name: daily-target
+        when: today.count < today.target
+`)
+	writeOpsState(t, dir, "acc", map[string]any{
+		"account":           "acc",
+		"kind":              "follower",
+		"status":            "running",
+		"consecutiveErrors": 4,
+		"today":             map[string]any{"count": 3, "target": 10},
+		"note":              "extra",
+	})
+	opsFile := filepath.Join(dir, "運用", "状態", "acc.json")
+	oldTime := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(opsFile, oldTime, oldTime); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := ScanProject(dir, "/other", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(state.Ops) != 1 {
+		t.Fatalf("expected 1 ops entry, got %d", len(state.Ops))
+	}
+	op := state.Ops[0]
+	if !op.Stale {
+		t.Error("expected stale=true with staleAfter=30m")
+	}
+	if op.ErrorThreshold != 5 {
+		t.Errorf("errorThreshold = %d, want 5", op.ErrorThreshold)
+	}
+	if len(op.MissingFields) != 1 || op.MissingFields[0] != "stats.error" {
+		t.Errorf("missingFields = %v, want [stats.error]", op.MissingFields)
+	}
+	if len(state.Warnings) != 1 {
+		t.Errorf("expected 1 warning for missing fields, got %v", state.Warnings)
+	}
+	if len(op.Alerts) != 1 || !op.Alerts[0].Firing {
+		t.Errorf("expected daily-target firing, got %+v", op.Alerts)
+	}
+	if op.RawExtra["note"] != "extra" || len(op.RawExtra) != 1 {
+		t.Errorf("rawExtra = %v, want only note", op.RawExtra)
+	}
+	if state.Attention != AttentionRequired {
+		t.Errorf("attention = %s, want required", state.Attention)
+	}
+}
+
+func TestScanProject_OpsConfigInvalid(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, filepath.Join("運用", "ops.yaml"), "defaults:\n  staleAfter: soon\n")
+	writeOpsState(t, dir, "acc", map[string]any{"status": "running", "consecutiveErrors": 3})
+
+	state, err := ScanProject(dir, "/other", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(state.Warnings) != 1 {
+		t.Errorf("expected 1 warning for invalid ops.yaml, got %v", state.Warnings)
+	}
+	// 既定値（連続エラー3回）で判定される
+	if len(state.Ops) != 1 || state.Ops[0].ErrorThreshold != defaultOpsErrorThreshold {
+		t.Fatalf("expected default threshold, got %+v", state.Ops)
+	}
+	if state.Attention != AttentionRequired {
+		t.Errorf("attention = %s, want required", state.Attention)
+	}
+}
+
 func writeFile(t *testing.T, base, rel, content string) {
 	t.Helper()
 	path := filepath.Join(base, rel)
@@ -2,12 +2,18 @@ package dashboard
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
+	"ghostrunner/backend/internal/autoanswer"
+	"ghostrunner/backend/internal/gitinfo"
+	"ghostrunner/backend/internal/grrun"
 	"ghostrunner/backend/internal/idle"
 	"ghostrunner/backend/internal/projects"
 )
@@ -21,35 +27,84 @@ type Service interface {
 	GetState(ctx context.Context) (State, error)
 	// Answer は確認事項に回答を書き戻します
 	Answer(ctx context.Context, req AnswerRequest) error
+	// AnswerBatch は1つの計画書の複数の確認事項に一括で回答を書き戻します（全件適用か無変更）
+	AnswerBatch(ctx context.Context, req BatchAnswerRequest) error
+	// AnswerHistory は登録プロジェクトの計画書から回答履歴を新しい順に返します
+	AnswerHistory(ctx context.Context, q AnswerHistoryQuery) (*AnswerHistory, error)
 }
 
 type serviceImpl struct {
 	configPath      string
 	ghostrunnerRoot string
 	idleReader      idle.Reader
+	watchdog        *Watchdog
+	progress        *ProgressSummarizer
+	gitInspector    gitinfo.Inspector
+	runsDir         string
+	autoAnswer      *autoanswer.Engine
 	now             func() time.Time
 }
 
+// Option は Service の任意の依存を設定します。
+type Option func(*serviceImpl)
+
+// WithWatchdog は停滞監視を設定します。設定時は GetState が停滞セッションを ProjectState.Stuck に付与します。
+func WithWatchdog(w *Watchdog) Option {
+	return func(s *serviceImpl) {
+		s.watchdog = w
+	}
+}
+
+// WithProgress は動作中セッションの進捗要約を設定します。設定時は GetState が要約済みの進捗を RunningState.Progress に付与します。
+func WithProgress(p *ProgressSummarizer) Option {
+	return func(s *serviceImpl) {
+		s.progress = p
+	}
+}
+
+// WithGitInspector は git 状態の取得を設定します。設定時は GetState が ProjectState.Git を付与します。
+func WithGitInspector(insp gitinfo.Inspector) Option {
+	return func(s *serviceImpl) {
+		s.gitInspector = insp
+	}
+}
+
+// WithRunsDir は gr-run の実行履歴ディレクトリ（<stateDir>/runs）を設定します。設定時は git 状態の
+// 「タスク開始以降のコミット」の起点に、実行中タスクを 実行中 へ移動した時刻（grrun.TaskClaimedAt）を使います。
+func WithRunsDir(dir string) Option {
+	return func(s *serviceImpl) {
+		s.runsDir = dir
+	}
+}
+
+// WithAutoAnswer は質問の自動回答ルールを設定します。設定時は GetState が質問待ち（AskUserQuestion）の
+// セッションをプロジェクトのルールに照合し、一致したルールを IdleState.AutoAnswer に付与します。
+// 外部で起動したセッションにはサーバーから回答できないため、escalate ルールのみ通知を送ります。
+func WithAutoAnswer(e *autoanswer.Engine) Option {
+	return func(s *serviceImpl) {
+		s.autoAnswer = e
+	}
+}
+
 // NewService は新しいServiceを生成します。
 // idleReader は nil 許容で、nil の場合は質問待ちの付与をスキップします。
-func NewService(configPath, ghostrunnerRoot string, idleReader idle.Reader) Service {
-	return &serviceImpl{
-		configPath:      configPath,
-		ghostrunnerRoot: ghostrunnerRoot,
-		idleReader:      idleReader,
-		now:             time.Now,
-	}
+func NewService(configPath, ghostrunnerRoot string, idleReader idle.Reader, opts ...Option) Service {
+	return NewServiceWithClock(configPath, ghostrunnerRoot, idleReader, time.Now, opts...)
 }
 
 // NewServiceWithClock はclock注入付きのServiceを生成します（テスト用）。
 // idleReader は nil 許容で、nil の場合は質問待ちの付与をスキップします。
-func NewServiceWithClock(configPath, ghostrunnerRoot string, idleReader idle.Reader, now func() time.Time) Service {
-	return &serviceImpl{
+func NewServiceWithClock(configPath, ghostrunnerRoot string, idleReader idle.Reader, now func() time.Time, opts ...Option) Service {
+	s := &serviceImpl{
 		configPath:      configPath,
 		ghostrunnerRoot: ghostrunnerRoot,
 		idleReader:      idleReader,
 		now:             now,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // GetState は全プロジェクトの集約状態を返します
@@ -93,6 +148,11 @@ func (s *serviceImpl) GetState(ctx context.Context) (State, error) {
 		states = append(states, ps)
 	}
 
+	// git 状態を各プロジェクトへ付与（gitInspector 未設定時はスキップ）
+	if s.gitInspector != nil {
+		attachGitInfo(ctx, states, s.gitInspector, s.runsDir)
+	}
+
 	// 質問待ちマーカーを各プロジェクトへ付与（idleReaderがnilの時はスキップ）
 	now := s.now()
 	if s.idleReader != nil {
@@ -101,14 +161,27 @@ func (s *serviceImpl) GetState(ctx context.Context) (State, error) {
 			log.Printf("[DashboardService] idle marker list failed: %v", err)
 		} else {
 			attachIdleState(states, markers, now)
+			if s.autoAnswer != nil {
+				attachAutoAnswer(states, markers, s.autoAnswer)
+			}
 		}
 	}
 
+	// 動作中セッションの進捗要約を付与（progress 未設定時はスキップ）
+	if s.progress != nil {
+		attachProgress(states, s.progress.progressBySession())
+	}
+
+	// 停滞セッションを各プロジェクトへ付与（watchdog 未設定時はスキップ）
+	if s.watchdog != nil {
+		attachStuckState(states, s.watchdog.stuckByCwd())
+	}
+
 	// ソート: Idle存在DESC, attention優先度ASC, 経過時間DESC, isSelf ASC, name ASC（安定ソート）
 	sort.SliceStable(states, func(i, j int) bool {
-		// 第1キー: 質問待ち(Idle!=nil)を最優先（未回答由来requiredと分離・C2）
-		ii := states[i].Idle != nil
-		ij := states[j].Idle != nil
+		// 第1キー: 質問待ち(Idle!=nil)・停滞(Stuck!=nil)を最優先（未回答由来requiredと分離・C2）
+		ii := states[i].Idle != nil || states[i].Stuck != nil
+		ij := states[j].Idle != nil || states[j].Stuck != nil
 		if ii != ij {
 			return ii // Idleありが先
 		}
@@ -149,18 +222,14 @@ func (s *serviceImpl) GetState(ctx context.Context) (State, error) {
 
 // Answer は確認事項に回答を書き戻します
 func (s *serviceImpl) Answer(ctx context.Context, req AnswerRequest) error {
-	log.Printf("[DashboardService] Answer started: project=%s, plan=%s, line=%d", req.ProjectPath, req.PlanPath, req.LineStart)
+	log.Printf("[DashboardService] Answer started: project=%s, plan=%s, line=%d, question=%s", req.ProjectPath, req.PlanPath, req.LineStart, req.QuestionID)
 
-	projs, err := projects.LoadProjects(s.configPath)
+	projs, err := s.loadAnswerProjects()
 	if err != nil {
-		return fmt.Errorf("failed to load projects: %w", err)
-	}
-
-	if projs == nil {
-		return fmt.Errorf("%w: no projects configured", ErrValidation)
+		return err
 	}
 
-	if err := AnswerQuestion(req, projs); err != nil {
+	if err := AnswerQuestion(req, projs, s.now()); err != nil {
 		log.Printf("[DashboardService] Answer failed: error=%v", err)
 		return err
 	}
@@ -169,6 +238,60 @@ func (s *serviceImpl) Answer(ctx context.Context, req AnswerRequest) error {
 	return nil
 }
 
+// AnswerBatch は1つの計画書の複数の確認事項に一括で回答を書き戻します
+func (s *serviceImpl) AnswerBatch(ctx context.Context, req BatchAnswerRequest) error {
+	log.Printf("[DashboardService] AnswerBatch started: project=%s, plan=%s, answers=%d, source=%s", req.ProjectPath, req.PlanPath, len(req.Answers), req.Source)
+
+	projs, err := s.loadAnswerProjects()
+	if err != nil {
+		return err
+	}
+
+	if err := AnswerQuestions(req, projs, s.now()); err != nil {
+		log.Printf("[DashboardService] AnswerBatch failed: error=%v", err)
+		return err
+	}
+
+	log.Printf("[DashboardService] AnswerBatch completed: project=%s, plan=%s, answers=%d", req.ProjectPath, req.PlanPath, len(req.Answers))
+	return nil
+}
+
+// AnswerHistory は登録プロジェクトの計画書から回答履歴を新しい順に返します
+func (s *serviceImpl) AnswerHistory(ctx context.Context, q AnswerHistoryQuery) (*AnswerHistory, error) {
+	log.Printf("[DashboardService] AnswerHistory started: project=%s, limit=%d", q.Project, q.Limit)
+
+	projs, err := projects.LoadProjects(s.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load projects: %w", err)
+	}
+
+	var warnings []string
+	result, err := CollectAnswerHistory(q, projs, &warnings)
+	if err != nil {
+		log.Printf("[DashboardService] AnswerHistory failed: error=%v", err)
+		return nil, err
+	}
+	for _, w := range warnings {
+		log.Printf("[DashboardService] AnswerHistory warning: %s", w)
+	}
+
+	log.Printf("[DashboardService] AnswerHistory completed: answers=%d, total=%d", len(result.Answers), result.Total)
+	return result, nil
+}
+
+// loadAnswerProjects は回答書き戻しの許可リスト（登録済みプロジェクト）を読み込みます
+func (s *serviceImpl) loadAnswerProjects() ([]projects.Project, error) {
+	projs, err := projects.LoadProjects(s.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load projects: %w", err)
+	}
+
+	if projs == nil {
+		return nil, fmt.Errorf("%w: no projects configured", ErrValidation)
+	}
+	return projs, nil
+}
+
 // attachIdleState は reader が返す代表マーカー（プロジェクト毎1件・Status/SessionCount 込み）を
 // 各プロジェクトへ付与します。reader が既に per-project 代表へ collapse 済みのため、ここでは
 // MatchProject で割り当て → Marker.Status で Idle(waiting)/Running(running) へディスパッチするのみです（C-1）。
@@ -212,15 +335,21 @@ func attachIdleState(states []ProjectState, markers []idle.Marker, now time.Time
 				continue
 			}
 			states[i].Idle = &IdleState{
+				SessionID:    m.SessionID,
 				Timestamp:    time.Unix(m.Timestamp, 0).Format(time.RFC3339),
 				Preview:      truncateRunes(m.RawTail.LastAssistant, 80),
 				SessionCount: m.SessionCount,
 				Summary:      m.Summary,
 				SummarizedAt: m.SummarizedAt,
 			}
+			if m.WaitReason != "" {
+				states[i].Idle.Reason = string(m.WaitReason)
+				states[i].Idle.ReasonLabel = m.WaitReason.Label()
+			}
 		case idle.StatusRunning:
 			// running は idleMinAge ゲートを通さない（fresh running を落とさない・C-1）
 			states[i].Running = &RunningState{
+				SessionID:    m.SessionID,
 				Preview:      truncateRunes(m.RawTail.LastAssistant, 80),
 				SessionCount: m.SessionCount,
 			}
@@ -233,6 +362,99 @@ func attachIdleState(states []ProjectState, markers []idle.Marker, now time.Time
 	}
 }
 
+// attachProgress は ProgressSummarizer が要約した進捗を、代表セッションが一致する RunningState へ付与します。
+func attachProgress(states []ProjectState, progress map[string]progressSession) {
+	for i := range states {
+		r := states[i].Running
+		if r == nil {
+			continue
+		}
+		ps, ok := progress[r.SessionID]
+		if !ok {
+			continue
+		}
+		r.Progress = ps.summary
+		r.ProgressAt = ps.at
+		r.EditedFiles = ps.editedFiles
+	}
+}
+
+// attachGitInfo は各プロジェクトの git 状態を付与します。リポジトリでないプロジェクトは付与せず、
+// 取得失敗はログのみで全体を失敗させません。タスク開始時刻は taskStartTime を参照してください。
+func attachGitInfo(ctx context.Context, states []ProjectState, insp gitinfo.Inspector, runsDir string) {
+	for i := range states {
+		var taskStart time.Time
+		if states[i].Kanban.Running > 0 && runsDir != "" {
+			taskStart = taskStartTime(states[i].Path, runsDir)
+		}
+		info, err := insp.Inspect(ctx, states[i].Path, taskStart)
+		if err != nil {
+			if !errors.Is(err, gitinfo.ErrNotRepository) {
+				log.Printf("[DashboardService] git inspect failed: path=%s, error=%v", states[i].Path, err)
+			}
+			continue
+		}
+		states[i].Git = info
+	}
+}
+
+// taskStartTime は 実行中 レーンのタスクのうち最も早く 実行中 へ移動した時刻を返します（gr-run の記録）。
+// レーンのディレクトリの mtime は計画書の書き戻し等でも変わるため使いません。記録が無ければゼロ値です。
+func taskStartTime(projectPath, runsDir string) time.Time {
+	entries, err := os.ReadDir(filepath.Join(projectPath, grrun.RelRunning))
+	if err != nil {
+		return time.Time{}
+	}
+	var start time.Time
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".md") {
+			continue
+		}
+		at, ok, err := grrun.TaskClaimedAt(runsDir, projectPath, e.Name())
+		if err != nil {
+			log.Printf("[DashboardService] task claim lookup failed: path=%s, task=%s, error=%v", projectPath, e.Name(), err)
+			continue
+		}
+		if ok && (start.IsZero() || at.Before(start)) {
+			start = at
+		}
+	}
+	return start
+}
+
+// attachStuckState は Watchdog が検出した停滞セッション（cwd ごと）を各プロジェクトへ付与し、
+// Attention を再評価します。帰属は attachIdleState と同じく idle.MatchProject で判定します。
+func attachStuckState(states []ProjectState, stuck map[string]StuckState) {
+	if len(stuck) == 0 {
+		return
+	}
+	projs := make([]projects.Project, len(states))
+	for i, s := range states {
+		projs[i] = projects.Project{Path: s.Path, Name: s.Name}
+	}
+
+	matched := make(map[string]StuckState)
+	for cwd, st := range stuck {
+		proj, ok := idle.MatchProject(cwd, projs)
+		if !ok {
+			continue
+		}
+		if cur, exists := matched[proj]; exists && cur.Since <= st.Since {
+			continue
+		}
+		matched[proj] = st
+	}
+
+	for i := range states {
+		st, ok := matched[filepath.Clean(states[i].Path)]
+		if !ok {
+			continue
+		}
+		states[i].Stuck = &st
+		states[i].Attention = determineAttention(states[i])
+	}
+}
+
 // idleElapsed は質問待ちの経過時間を返します（Idleなしは0）。ソートの内部計算専用で外部には露出しません。
 func idleElapsed(s ProjectState, now time.Time) time.Duration {
 	if s.Idle == nil {
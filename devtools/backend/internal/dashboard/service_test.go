@@ -9,6 +9,8 @@ import (
 	"testing"
 	"time"
 
+	"ghostrunner/backend/internal/gitinfo"
+	"ghostrunner/backend/internal/grrun"
 	"ghostrunner/backend/internal/idle"
 )
 
@@ -358,6 +360,52 @@ func TestGetState_IdleUsesRepSessionCount(t *testing.T) {
 	}
 }
 
+// TestGetState_IdleWaitReason は、Marker.WaitReason が IdleState の Reason / ReasonLabel に
+// 引き継がれ、理由なしのマーカーではキーごと空になることを検証します。
+func TestGetState_IdleWaitReason(t *testing.T) {
+	tests := []struct {
+		name      string
+		reason    idle.WaitReason
+		wantLabel string
+	}{
+		{"計画承認待ち", idle.WaitPlanApproval, "計画の承認待ち"},
+		{"質問待ち", idle.WaitQuestion, "質問への回答待ち"},
+		{"実行許可待ち", idle.WaitPermission, "実行許可待ち"},
+		{"返信待ち", idle.WaitEndOfTurn, "返信待ち"},
+		{"理由なし", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			projA := mkProjectDir(t, dir, "project-a")
+			configPath := makeConfig(t, dir, []map[string]string{
+				{"path": projA, "name": "project-a"},
+			})
+			reader := &fakeIdleReader{markers: []idle.Marker{
+				{Cwd: projA, SessionID: "s1", Timestamp: epochAgo(5 * time.Minute), Status: idle.StatusWaiting, WaitReason: tt.reason, SessionCount: 1},
+			}}
+
+			svc := NewServiceWithClock(configPath, "/other", reader, func() time.Time { return fixedNow })
+			state, err := svc.GetState(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			p := findProject(t, state, "project-a")
+			if p.Idle == nil {
+				t.Fatal("expected Idle attached")
+			}
+			if p.Idle.Reason != string(tt.reason) {
+				t.Errorf("Reason = %q, want %q", p.Idle.Reason, tt.reason)
+			}
+			if p.Idle.ReasonLabel != tt.wantLabel {
+				t.Errorf("ReasonLabel = %q, want %q", p.Idle.ReasonLabel, tt.wantLabel)
+			}
+		})
+	}
+}
+
 // TestGetState_IdleExpiredExcluded は、TTL(6h)を超えた失効マーカーがIdle付与されない
 // （除外・削除しない）ことを検証します。
 func TestGetState_IdleExpiredExcluded(t *testing.T) {
@@ -605,3 +653,72 @@ func TestGetState_IdleOmitemptyInJSON(t *testing.T) {
 		t.Errorf("expected no idle key for non-waiting project, got: %s", data)
 	}
 }
+
+// fakeGitInspector は gitinfo.Inspector を満たすテスト用スタブです
+type fakeGitInspector struct {
+	infos      map[string]*gitinfo.Info
+	taskStarts map[string]time.Time
+}
+
+func (f *fakeGitInspector) Inspect(ctx context.Context, dir string, taskStart time.Time) (*gitinfo.Info, error) {
+	f.taskStarts[dir] = taskStart
+	info, ok := f.infos[dir]
+	if !ok {
+		return nil, gitinfo.ErrNotRepository
+	}
+	return info, nil
+}
+
+// TestGetState_GitInfoAttached は WithGitInspector 設定時に git 状態が付与され、
+// リポジトリでないプロジェクトは付与されず、実行中タスクがあれば gr-run が記録したクレーム時刻が
+// タスク開始として渡ることを検証します（レーンの mtime は計画書の書き戻し等で変わるため使わない）。
+func TestGetState_GitInfoAttached(t *testing.T) {
+	dir := t.TempDir()
+	repo := mkProjectDir(t, dir, "repo")
+	plain := mkProjectDir(t, dir, "plain")
+	runningDir := filepath.Join(repo, "開発", "実装", "実行中")
+	if err := os.MkdirAll(runningDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(runningDir, "task.md"), []byte("# task\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runsDir := t.TempDir()
+	claimed := fixedNow.Add(-2 * time.Hour)
+	if err := grrun.RecordClaim(runsDir, repo, "task.md", claimed); err != nil {
+		t.Fatal(err)
+	}
+	// レーンへの一時ファイル書き込み（ディレクトリの mtime が進む）はタスク開始に影響しない
+	if err := os.WriteFile(filepath.Join(runningDir, ".order"), []byte("task.md\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	configPath := makeConfig(t, dir, []map[string]string{
+		{"path": repo, "name": "repo"},
+		{"path": plain, "name": "plain"},
+	})
+
+	insp := &fakeGitInspector{
+		infos:      map[string]*gitinfo.Info{repo: {Branch: "main", Dirty: 2}},
+		taskStarts: map[string]time.Time{},
+	}
+	svc := NewServiceWithClock(configPath, "/other", nil, func() time.Time { return fixedNow },
+		WithGitInspector(insp), WithRunsDir(runsDir))
+	state, err := svc.GetState(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := findProject(t, state, "repo")
+	if r.Git == nil || r.Git.Branch != "main" || r.Git.Dirty != 2 {
+		t.Errorf("Git = %+v, want main/dirty=2", r.Git)
+	}
+	if !insp.taskStarts[repo].Equal(claimed) {
+		t.Errorf("taskStart = %v, want claim time %v", insp.taskStarts[repo], claimed)
+	}
+	if p := findProject(t, state, "plain"); p.Git != nil {
+		t.Errorf("Git = %+v, want nil for non-repository", p.Git)
+	}
+	if !insp.taskStarts[plain].IsZero() {
+		t.Errorf("taskStart = %v, want zero without running task", insp.taskStarts[plain])
+	}
+}
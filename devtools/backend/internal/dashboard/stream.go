@@ -6,6 +6,9 @@ import (
 	"reflect"
 	"sync"
 	"time"
+
+	"ghostrunner/backend/internal/events"
+	"ghostrunner/backend/internal/history"
 )
 
 const (
@@ -17,62 +20,74 @@ const (
 
 // StreamService はダッシュボード状態のSSE配信を提供します。
 // 内部tickerで短間隔スキャンし、前回Stateと実変化があった場合のみ
-// State スナップショット全体をsubscriberへbroadcastします。
+// State スナップショット全体をイベントバスの dashboard.state へ発行します。
 type StreamService interface {
 	// Subscribe は状態更新を受け取るチャネルと購読解除関数を返します
 	Subscribe() (<-chan State, func())
 	// Start は差分検出のバックグラウンドスキャンを開始します
 	Start(ctx context.Context)
-	// Stop はスキャンを停止し全subscriberチャネルを閉じます
+	// Stop はスキャンを停止します。専用バスの場合は全subscriberチャネルも閉じます
 	Stop()
 }
 
 type streamServiceImpl struct {
-	svc Service
+	svc      Service
+	recorder history.Recorder
+
+	bus     *events.Bus
+	ownsBus bool // 専用バス（WithEventBus 未設定）なら Stop で閉じる
 
-	mu          sync.Mutex
-	subscribers map[int]chan State
-	nextID      int
-	lastState   *State
+	mu        sync.Mutex
+	lastState *State
 
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
 }
 
-// NewStreamService は新しいStreamServiceを生成します
-func NewStreamService(svc Service) StreamService {
-	return &streamServiceImpl{
-		svc:         svc,
-		subscribers: make(map[int]chan State),
+// StreamOption はStreamServiceの任意設定です
+type StreamOption func(*streamServiceImpl)
+
+// WithRecorder はスキャンごとの状態を時系列として記録するRecorderを設定します。
+// 変化の間引き（状態が同じなら心拍間隔まで記録しない）はRecorder側が行います。
+func WithRecorder(r history.Recorder) StreamOption {
+	return func(s *streamServiceImpl) {
+		s.recorder = r
 	}
 }
 
-// Subscribe は状態更新チャネルと購読解除関数を返します。
-// 購読直後に最新Stateが存在すれば初期値として1件送ります。
-func (s *streamServiceImpl) Subscribe() (<-chan State, func()) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	ch := make(chan State, streamBufferSize)
-	id := s.nextID
-	s.nextID++
-	s.subscribers[id] = ch
-
-	// 購読直後に現状のStateを反映（バッファに空きがあるため非ブロッキング）
-	if s.lastState != nil {
-		ch <- *s.lastState
+// WithEventBus は状態を発行するイベントバスを設定します。
+// 未設定時はサービス専用のバスを使い、Subscribe の購読者にのみ配信します。
+func WithEventBus(b *events.Bus) StreamOption {
+	return func(s *streamServiceImpl) {
+		s.bus = b
 	}
+}
 
-	unsubscribe := func() {
-		s.mu.Lock()
-		defer s.mu.Unlock()
-		if _, ok := s.subscribers[id]; ok {
-			delete(s.subscribers, id)
-			close(ch)
-		}
+// NewStreamService は新しいStreamServiceを生成します
+func NewStreamService(svc Service, opts ...StreamOption) StreamService {
+	s := &streamServiceImpl{
+		svc: svc,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.bus == nil {
+		s.bus = events.NewBus(nil)
+		s.ownsBus = true
 	}
+	return s
+}
 
-	return ch, unsubscribe
+// Subscribe は状態更新チャネルと購読解除関数を返します。
+// 購読直後に最新Stateが存在すれば初期値として1件送ります（バスの保持値）。
+// バッファは1件で、満杯時は古い値を捨てて最新を入れます（coalesce）。
+func (s *streamServiceImpl) Subscribe() (<-chan State, func()) {
+	return events.SubscribeData[State](s.bus, events.Options{
+		Topics: []string{events.TopicDashboardState},
+		Buffer: streamBufferSize,
+		Policy: events.PolicyDropOldest,
+		Name:   "dashboard",
+	})
 }
 
 // Start は差分検出のバックグラウンドスキャンを開始します
@@ -99,29 +114,37 @@ func (s *streamServiceImpl) Start(ctx context.Context) {
 	}()
 }
 
-// Stop はスキャンを停止し全subscriberチャネルを閉じます
+// Stop はスキャンを停止します。専用バスの場合は全subscriberチャネルも閉じます
+// （共有バスのクローズはバスの所有者が行います）
 func (s *streamServiceImpl) Stop() {
 	if s.cancel != nil {
 		s.cancel()
 	}
 	s.wg.Wait()
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	for id, ch := range s.subscribers {
-		delete(s.subscribers, id)
-		close(ch)
+	if s.ownsBus {
+		s.bus.Close()
 	}
 }
 
-// scanAndBroadcast は状態を取得し、前回と実変化があればbroadcastします
+// scanAndBroadcast は状態を取得し、前回と実変化があれば dashboard.state へ発行します。
+// 変化が無い場合も保持値は差し替え、新しい購読者には最新の generatedAt を届けます。
 func (s *streamServiceImpl) scanAndBroadcast(ctx context.Context) {
+	started := time.Now()
 	state, err := s.svc.GetState(ctx)
+	observeScan(started, err)
 	if err != nil {
 		log.Printf("[DashboardStream] GetState failed: %v", err)
 		return
 	}
 
+	// 時系列の記録（ファイルIO）はsubscriberロックの外で行う
+	if s.recorder != nil {
+		if err := s.recorder.Record(toSamples(state)); err != nil {
+			log.Printf("[DashboardStream] record history failed: %v", err)
+		}
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -129,29 +152,10 @@ func (s *streamServiceImpl) scanAndBroadcast(ctx context.Context) {
 	stateCopy := state
 	s.lastState = &stateCopy
 	if !changed {
+		s.bus.Retain(events.TopicDashboardState, state)
 		return
 	}
-
-	for _, ch := range s.subscribers {
-		sendCoalesce(ch, state)
-	}
-}
-
-// sendCoalesce はsubscriberチャネルへ最新Stateを送ります。
-// バッファ満杯時は古い値を捨てて最新を入れます（coalesce）。
-// 送信元はscanAndBroadcastのみ（mu保持下）のため2回以内に収束します。
-func sendCoalesce(ch chan State, state State) {
-	for {
-		select {
-		case ch <- state:
-			return
-		default:
-			select {
-			case <-ch:
-			default:
-			}
-		}
-	}
+	s.bus.PublishRetained(events.TopicDashboardState, state)
 }
 
 // statesDiffer は2つのStateに表示上の実変化があるかを判定します。
@@ -182,3 +186,28 @@ func normalizeForDiff(projects []ProjectState) []ProjectState {
 	}
 	return out
 }
+
+// toSamples はStateを時系列記録用のプロジェクト別サンプルへ変換します（時刻はRecorderが付与）。
+func toSamples(state State) []history.Sample {
+	out := make([]history.Sample, 0, len(state.Projects))
+	for _, p := range state.Projects {
+		sm := history.Sample{
+			Project: p.Path,
+			Kanban: history.Kanban{
+				Reviewing: p.Kanban.Reviewing,
+				Waiting:   p.Kanban.Waiting,
+				Running:   p.Kanban.Running,
+				Done:      p.Kanban.Done,
+			},
+			Idle:       p.Idle != nil,
+			Running:    p.Running != nil,
+			Stuck:      p.Stuck != nil,
+			Unanswered: len(p.Unanswered),
+		}
+		for _, o := range p.Ops {
+			sm.Ops = append(sm.Ops, history.OpsStatus{Account: o.Account, Kind: o.Kind, Status: o.Status, Stale: o.Stale})
+		}
+		out = append(out, sm)
+	}
+	return out
+}
@@ -2,8 +2,11 @@ package dashboard
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
+
+	"ghostrunner/backend/internal/history"
 )
 
 // fakeDashboardService は Service を満たすテスト用スタブです
@@ -20,6 +23,14 @@ func (f *fakeDashboardService) Answer(ctx context.Context, req AnswerRequest) er
 	return nil
 }
 
+func (f *fakeDashboardService) AnswerBatch(ctx context.Context, req BatchAnswerRequest) error {
+	return nil
+}
+
+func (f *fakeDashboardService) AnswerHistory(ctx context.Context, q AnswerHistoryQuery) (*AnswerHistory, error) {
+	return &AnswerHistory{}, nil
+}
+
 func TestStatesDiffer(t *testing.T) {
 	baseProjects := []ProjectState{
 		{
@@ -169,24 +180,31 @@ func TestStatesDiffer_元Stateを破壊しない(t *testing.T) {
 	}
 }
 
-// sendCoalesce は満杯チャネルで古い値を捨てて最新を入れ、ブロックしないこと。
-func TestSendCoalesce_満杯チャネルで最新に置き換わる(t *testing.T) {
-	ch := make(chan State, streamBufferSize) // streamBufferSize=1
-
-	old := State{GeneratedAt: "old"}
-	newer := State{GeneratedAt: "new"}
+// 受信していない購読者（バッファ満杯）へも配信はブロックせず、古い値を捨てて最新を入れること（coalesce）。
+func TestStream_満杯の購読者には最新に置き換わる(t *testing.T) {
+	svc := &fakeDashboardService{state: State{
+		Projects:    []ProjectState{{Name: "p", Path: "/a", Attention: AttentionWatching}},
+		GeneratedAt: "old",
+	}}
+	s := NewStreamService(svc).(*streamServiceImpl)
+	ch, unsub := s.Subscribe()
+	defer unsub()
 
-	ch <- old // バッファを満杯にする
+	s.scanAndBroadcast(context.Background()) // バッファを満杯にする
 
+	svc.state = State{
+		Projects:    []ProjectState{{Name: "p", Path: "/a", Attention: AttentionRequired}},
+		GeneratedAt: "new",
+	}
 	done := make(chan struct{})
 	go func() {
-		sendCoalesce(ch, newer) // ブロックしてはいけない
+		s.scanAndBroadcast(context.Background()) // ブロックしてはいけない
 		close(done)
 	}()
 	select {
 	case <-done:
 	case <-time.After(time.Second):
-		t.Fatalf("sendCoalesce blocked on full channel")
+		t.Fatalf("broadcast blocked on full channel")
 	}
 
 	got := <-ch
@@ -205,7 +223,7 @@ func TestStream_SubscribeBroadcastAndUnsubscribe(t *testing.T) {
 		GeneratedAt: "2026-07-20T12:00:00Z",
 	}
 	svc := &fakeDashboardService{state: stateA}
-	s := &streamServiceImpl{svc: svc, subscribers: make(map[int]chan State)}
+	s := NewStreamService(svc).(*streamServiceImpl)
 
 	// 1人目購読（lastState nil のため初期値は届かない）
 	ch1, unsub1 := s.Subscribe()
@@ -242,3 +260,70 @@ func TestStream_SubscribeBroadcastAndUnsubscribe(t *testing.T) {
 		t.Errorf("ch1 should be closed after unsubscribe")
 	}
 }
+
+// fakeRecorder は history.Recorder を満たすテスト用スタブです
+type fakeRecorder struct {
+	calls [][]history.Sample
+	err   error
+}
+
+func (f *fakeRecorder) Record(samples []history.Sample) error {
+	f.calls = append(f.calls, samples)
+	return f.err
+}
+
+// スキャンごとに State がプロジェクト別サンプルへ変換されて記録される。
+// 記録の失敗は配信を止めない。
+func TestStream_RecordsHistory(t *testing.T) {
+	state := State{
+		Projects: []ProjectState{
+			{
+				Name: "a", Path: "/a", Attention: AttentionRequired,
+				Kanban:     KanbanCounts{Reviewing: 1, Waiting: 2, Running: 1, Done: 5},
+				Unanswered: []UnansweredQuestion{{PlanPath: "x.md"}, {PlanPath: "y.md"}},
+				Ops:        []OpsEntry{{Account: "acc", Kind: "follow", Status: "error"}},
+				Idle:       &IdleState{Timestamp: "2026-07-20T11:50:00Z"},
+			},
+			{
+				Name: "b", Path: "/b", Attention: AttentionProgress,
+				Running: &RunningState{SessionCount: 1},
+				Stuck:   &StuckState{Reason: StuckNoProgress},
+			},
+		},
+		GeneratedAt: "2026-07-20T12:00:00Z",
+	}
+	rec := &fakeRecorder{err: errors.New("disk full")}
+	s := NewStreamService(&fakeDashboardService{state: state}, WithRecorder(rec)).(*streamServiceImpl)
+
+	ch, unsub := s.Subscribe()
+	defer unsub()
+	s.scanAndBroadcast(context.Background())
+	if got := <-ch; got.GeneratedAt != state.GeneratedAt {
+		t.Errorf("broadcast should continue on record error: got %q", got.GeneratedAt)
+	}
+
+	if len(rec.calls) != 1 {
+		t.Fatalf("Record calls: got %d, want 1", len(rec.calls))
+	}
+	want := []history.Sample{
+		{
+			Project:    "/a",
+			Kanban:     history.Kanban{Reviewing: 1, Waiting: 2, Running: 1, Done: 5},
+			Idle:       true,
+			Unanswered: 2,
+			Ops:        []history.OpsStatus{{Account: "acc", Kind: "follow", Status: "error"}},
+		},
+		{Project: "/b", Running: true, Stuck: true},
+	}
+	got := rec.calls[0]
+	if len(got) != len(want) {
+		t.Fatalf("samples: got %d, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Project != want[i].Project || got[i].Kanban != want[i].Kanban || got[i].Idle != want[i].Idle ||
+			got[i].Running != want[i].Running || got[i].Stuck != want[i].Stuck || got[i].Unanswered != want[i].Unanswered ||
+			len(got[i].Ops) != len(want[i].Ops) {
+			t.Errorf("sample[%d]: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
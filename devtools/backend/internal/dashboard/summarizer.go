@@ -25,12 +25,23 @@ const (
 	summarizeCooldown = 5 * time.Minute
 )
 
+// SummarizerOption は Summarizer の任意設定です
+type SummarizerOption func(*Summarizer)
+
+// WithFallbacks は要約がエラーになったときに順に試す SummarizeService を追加します
+func WithFallbacks(svcs ...service.SummarizeService) SummarizerOption {
+	return func(s *Summarizer) {
+		s.svcs = append(s.svcs, svcs...)
+	}
+}
+
 // Summarizer は滞留した質問待ちマーカーを検出し、SummarizeServiceで要約して
 // マーカーへ書き戻すバックグラウンドジョブです。
+// 要約がエラーになった場合は WithFallbacks で渡した SummarizeService を順に試します。
 type Summarizer struct {
 	reader idle.Reader
 	writer idle.Writer
-	svc    service.SummarizeService
+	svcs   []service.SummarizeService // 先頭から順に試す
 	now    func() time.Time
 
 	mu          sync.Mutex
@@ -41,17 +52,21 @@ type Summarizer struct {
 }
 
 // NewSummarizer は新しいSummarizerを生成します。now が nil の場合は time.Now を使います。
-func NewSummarizer(reader idle.Reader, writer idle.Writer, svc service.SummarizeService, now func() time.Time) *Summarizer {
+func NewSummarizer(reader idle.Reader, writer idle.Writer, svc service.SummarizeService, now func() time.Time, opts ...SummarizerOption) *Summarizer {
 	if now == nil {
 		now = time.Now
 	}
-	return &Summarizer{
+	s := &Summarizer{
 		reader:      reader,
 		writer:      writer,
-		svc:         svc,
+		svcs:        []service.SummarizeService{svc},
 		now:         now,
 		lastAttempt: make(map[string]time.Time),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // Start は要約ジョブを開始します。ctx のキャンセルまたは Stop で終了します。
@@ -134,19 +149,53 @@ func (s *Summarizer) runOnce(ctx context.Context) {
 
 // summarizeOne は1つのマーカーを要約して書き戻します
 func (s *Summarizer) summarizeOne(ctx context.Context, m idle.Marker) {
-	summary, err := s.svc.SummarizeIdle(ctx, m.RawTail.LastAssistant, m.RawTail.LastPrompt)
+	summary, err := s.summarize(ctx, m)
 	if err != nil {
 		log.Printf("[Summarizer] summarize failed: session=%s, error=%v", m.SessionID, err)
+		summarizerAttemptsTotal.Inc("summarize_error")
 		return
 	}
 	if summary == "" {
+		summarizerAttemptsTotal.Inc("empty")
 		return
 	}
 	// 基準は List 時点（T0）の timestamp。要約中にマーカーが削除/再生成されても
 	// 新マーカーへ旧要約を上書きしない（C3）。
 	if err := s.writer.WriteSummary(m.SessionID, m.Timestamp, summary, s.now()); err != nil {
 		log.Printf("[Summarizer] write summary failed: session=%s, error=%v", m.SessionID, err)
+		summarizerAttemptsTotal.Inc("write_error")
+		return
+	}
+	summarizerAttemptsTotal.Inc("summarized")
+}
+
+// summarize は SummarizeService を先頭から順に試し、最初に成功した要約を返します。
+// すべてエラーの場合は最後のエラーを返します
+func (s *Summarizer) summarize(ctx context.Context, m idle.Marker) (string, error) {
+	var lastErr error
+	for _, svc := range s.svcs {
+		name := providerName(svc)
+		summary, err := svc.SummarizeIdle(ctx, m.RawTail.LastAssistant, m.RawTail.LastPrompt)
+		if err == nil {
+			summarizerProviderTotal.Inc(name, "ok")
+			return summary, nil
+		}
+		summarizerProviderTotal.Inc(name, "error")
+		lastErr = err
+		if ctx.Err() != nil {
+			break
+		}
+		log.Printf("[Summarizer] provider failed, falling back: session=%s, provider=%s, error=%v", m.SessionID, name, err)
+	}
+	return "", lastErr
+}
+
+// providerName は SummarizeService / ProgressSummarizeService のプロバイダー名を返します（名前を持たない場合は "default"）
+func providerName(svc any) string {
+	if p, ok := svc.(service.SummarizeProvider); ok {
+		return p.Name()
 	}
+	return "default"
 }
 
 // claimAttempts はクールダウン内でない候補のみを返し、返した候補の試行時刻を記録します。
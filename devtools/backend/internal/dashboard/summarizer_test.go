@@ -2,6 +2,7 @@ package dashboard
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -213,3 +214,29 @@ func TestSummarizer_要約成功でtimestampを基準に書き戻す(t *testing.
 		t.Errorf("summary: got %q", call.summary)
 	}
 }
+
+// 先頭の SummarizeService がエラーなら WithFallbacks の順に試し、最初の成功を書き戻す。
+func TestSummarizer_エラー時はフォールバックへ切り替える(t *testing.T) {
+	base := time.Unix(40000, 0).UTC()
+	now := func() time.Time { return base }
+
+	reader := &fakeIdleReader{markers: []idle.Marker{
+		{SessionID: "s1", Timestamp: base.Add(-3 * time.Minute).Unix(), Status: idle.StatusWaiting, RawTail: idle.RawTail{LastAssistant: "選んで"}},
+	}}
+	writer := &fakeIdleWriter{}
+	primary := &fakeSummarizeService{err: errors.New("offline")}
+	second := &fakeSummarizeService{err: errors.New("quota")}
+	last := &fakeSummarizeService{result: "選択待ち"}
+	unused := &fakeSummarizeService{result: "使われない"}
+
+	s := NewSummarizer(reader, writer, primary, now, WithFallbacks(second, last, unused))
+	s.runOnce(context.Background())
+
+	if primary.callCount() != 1 || second.callCount() != 1 || last.callCount() != 1 || unused.callCount() != 0 {
+		t.Errorf("calls: primary=%d second=%d last=%d unused=%d, want 1/1/1/0",
+			primary.callCount(), second.callCount(), last.callCount(), unused.callCount())
+	}
+	if writer.callCount() != 1 || writer.calls[0].summary != "選択待ち" {
+		t.Fatalf("writer calls: %+v, want one write of the fallback summary", writer.calls)
+	}
+}
@@ -1,5 +1,7 @@
 package dashboard
 
+import "ghostrunner/backend/internal/gitinfo"
+
 // Attention はプロジェクトの注目度を表します
 type Attention string
 
@@ -22,7 +24,9 @@ type KanbanCounts struct {
 
 // UnansweredQuestion は未回答の確認事項を表します
 type UnansweredQuestion struct {
-	PlanPath     string `json:"planPath"`
+	PlanPath string `json:"planPath"`
+	// QuestionID は見出しの ID（例: Q1）。見出しの無いステータス行では省略
+	QuestionID   string `json:"questionId,omitempty"`
 	LineStart    int    `json:"lineStart"`
 	LineEnd      int    `json:"lineEnd"`
 	QuestionText string `json:"questionText"`
@@ -63,18 +67,53 @@ type OpsEntry struct {
 	StaleHours        int            `json:"staleHours"`
 	SourceFile        string         `json:"sourceFile"`
 	RawExtra          map[string]any `json:"rawExtra,omitempty"`
+	// ErrorThreshold は consecutiveErrors を要対応とみなす回数（運用/ops.yaml の errorThreshold。既定3）
+	ErrorThreshold int `json:"errorThreshold"`
+	// MissingFields は ops.yaml の fields に宣言されたのに状態ファイルに無いフィールド
+	MissingFields []string `json:"missingFields,omitempty"`
+	// Alerts は ops.yaml のアラート条件の評価結果（宣言順。発火していないものも含む）
+	Alerts []OpsAlert `json:"alerts,omitempty"`
+}
+
+// OpsAlert は運用状態1件に対するアラート条件1つの評価結果です。
+// 評価できなかった（参照先が無い・型が比較できない）場合は Firing=false で Error に理由が入ります。
+type OpsAlert struct {
+	Rule     string      `json:"rule"`
+	Severity OpsSeverity `json:"severity"`     // critical（発火中は required）/ warning
+	When     string      `json:"when"`         // 条件式（例: "today.count < today.target"）
+	By       string      `json:"by,omitempty"` // "HH:MM"。この時刻より前は評価しない
+	Firing   bool        `json:"firing"`
+	Skipped  bool        `json:"skipped,omitempty"` // By の時刻より前で評価していない
+	Message  string      `json:"message,omitempty"` // 発火時の説明
+	Error    string      `json:"error,omitempty"`   // 評価できなかった理由
 }
 
 // IdleState は1プロジェクトの質問待ち状態を表します。
 // このフィールドが存在すること自体が「質問待ち」を意味します
 // （非待機時はキーごと欠落し、waiting bool は持ちません）。
 // 経過分はサーバーに載せず、Timestamp からフロントが算出します。
+// Reason は何を待っているか（回答・計画承認・実行許可・返信）でフロントが導線を分けるための種別です。
 type IdleState struct {
-	Timestamp    string `json:"timestamp"`    // RFC3339。バッジの「N分」はフロントが now - timestamp で算出
-	Preview      string `json:"preview"`      // rawTail.lastAssistant 先頭80字（要約前の暫定）
-	SessionCount int    `json:"sessionCount"` // 同プロジェクトの質問待ちセッション数（代表1件＋件数）
-	Summary      string `json:"summary"`      // 「何を待っているか」の日本語1行要約（Phase 1a では空）
-	SummarizedAt string `json:"summarizedAt"` // 要約生成時刻（RFC3339・Phase 1a では空）
+	SessionID    string `json:"sessionId,omitempty"`   // 代表セッションのID
+	Timestamp    string `json:"timestamp"`             // RFC3339。バッジの「N分」はフロントが now - timestamp で算出
+	Preview      string `json:"preview"`               // rawTail.lastAssistant 先頭80字（要約前の暫定）
+	SessionCount int    `json:"sessionCount"`          // 同プロジェクトの質問待ちセッション数（代表1件＋件数）
+	Summary      string `json:"summary"`               // 「何を待っているか」の日本語1行要約（Phase 1a では空）
+	SummarizedAt string `json:"summarizedAt"`          // 要約生成時刻（RFC3339・Phase 1a では空）
+	Reason       string `json:"reason,omitempty"`      // 待機理由（question / plan_approval / permission / end_of_turn）
+	ReasonLabel  string `json:"reasonLabel,omitempty"` // 待機理由の表示名（「計画の承認待ち」等）
+	// AutoAnswer は質問に一致した自動回答ルール（WithAutoAnswer 設定時・一致時のみ）
+	AutoAnswer *AutoAnswerState `json:"autoAnswer,omitempty"`
+}
+
+// AutoAnswerState は質問待ちセッションに一致した自動回答ルールを表します。
+// 外部で起動したセッションにはサーバーから回答できないため、answer / timeout は回答の提案として表示し、
+// escalate は通知済みであることを示します。
+type AutoAnswerState struct {
+	Rule   string `json:"rule"`
+	Action string `json:"action"`           // answer / timeout / escalate
+	Answer string `json:"answer,omitempty"` // 提案する回答（escalate では空）
+	DueAt  string `json:"dueAt,omitempty"`  // timeout の回答予定時刻（RFC3339。待機開始 + timeout）
 }
 
 // RunningState は1プロジェクトの動作中（ランタイム）セッション状態を表します。
@@ -85,10 +124,27 @@ type IdleState struct {
 //   - OpsEntry.Status == "running": 運用ジョブが稼働中
 //   - ProjectState.Running（本型）: 会話ログ上で Claude が今まさに処理中の代表セッション
 //
-// 動作中は内容が刻々変わるため要約せず、生 preview のみ保持します（Summary/Timestamp は持たない・W-6）。
+// 動作中は内容が刻々変わるため質問待ちの要約（Summary）は持たず、生 preview を保持します（W-6）。
+// 長い作業の「いま何をしているか／ここまでに済んだこと」は ProgressSummarizer が会話ログの差分から
+// interval ごとに要約し、Progress に付与します（未要約・未設定時は空）。
 type RunningState struct {
-	Preview      string `json:"preview"`      // rawTail.lastAssistant 先頭80字（要約前の生text）
-	SessionCount int    `json:"sessionCount"` // 同プロジェクトの動作中セッション数（代表1件＋件数）
+	SessionID    string   `json:"sessionId"`
+	Preview      string   `json:"preview"`               // rawTail.lastAssistant 先頭80字（要約前の生text）
+	SessionCount int      `json:"sessionCount"`          // 同プロジェクトの動作中セッション数（代表1件＋件数）
+	Progress     string   `json:"progress,omitempty"`    // 進捗要約（「いま: …／済: …」）
+	ProgressAt   string   `json:"progressAt,omitempty"`  // 進捗を要約した時刻（RFC3339）
+	EditedFiles  []string `json:"editedFiles,omitempty"` // これまでに編集・作成したファイル（直近20件）
+}
+
+// StuckState は1プロジェクトの停滞セッション状態を表します（Watchdog が検出）。
+// このフィールドが存在すること自体が「停滞（要対応）」を意味します。生成途中のまま会話ログが
+// 伸びないセッションは reader が RunningMaxAge 超で静観へ落とすため、Running とは独立に付与されます。
+type StuckState struct {
+	SessionID string      `json:"sessionId"`
+	Reason    StuckReason `json:"reason"`             // no_progress / repeated_failure
+	Since     string      `json:"since"`              // RFC3339。no_progress は最終更新時刻、repeated_failure は検出時刻
+	Detail    string      `json:"detail"`             // 日本語の説明（通知本文と同じ）
+	ToolName  string      `json:"toolName,omitempty"` // repeated_failure で失敗し続けているツール名
 }
 
 // ProjectState は1つのプロジェクトの集約状態を表します
@@ -104,6 +160,8 @@ type ProjectState struct {
 	Warnings   []string             `json:"warnings"`
 	Idle       *IdleState           `json:"idle,omitempty"`
 	Running    *RunningState        `json:"running,omitempty"`
+	Stuck      *StuckState          `json:"stuck,omitempty"`
+	Git        *gitinfo.Info        `json:"git,omitempty"`
 }
 
 // State はダッシュボード全体の状態を表します
@@ -0,0 +1,268 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"ghostrunner/backend/internal/idle"
+	"ghostrunner/backend/internal/notify"
+	"ghostrunner/backend/internal/service"
+	"ghostrunner/backend/internal/transcript"
+)
+
+const (
+	// watchdogInterval は停滞監視の実行間隔です
+	watchdogInterval = 30 * time.Second
+	// defaultStuckWindow は会話ログが伸びないまま停滞とみなすまでの既定時間です。
+	// reader が固まった midTurn を none へ落とす idle.RunningMaxAge と揃え、動作中表示が消えた時点で
+	// 停滞表示へ引き継ぎます（ダッシュボードから黙って消えるのを防ぐ）。
+	defaultStuckWindow = idle.RunningMaxAge
+	// defaultStuckRepeatThreshold は同一ツール呼び出しの連続失敗を停滞とみなす既定回数です
+	defaultStuckRepeatThreshold = 3
+)
+
+// StuckReason は停滞の種別です。
+type StuckReason string
+
+const (
+	// StuckNoProgress は生成途中のまま会話ログが一定時間伸びていない状態です。
+	StuckNoProgress StuckReason = "no_progress"
+	// StuckRepeatedFailure は同じツール呼び出し（同一ツール・同一入力）が連続して失敗している状態です。
+	StuckRepeatedFailure StuckReason = "repeated_failure"
+)
+
+// WatchdogConfig は停滞監視のしきい値です。
+type WatchdogConfig struct {
+	// NoProgressWindow はこの時間以上会話ログが伸びない生成途中セッションを停滞とみなす窓です
+	NoProgressWindow time.Duration
+	// RepeatThreshold は同一ツール呼び出しがこの回数以上連続失敗したら停滞とみなす回数です
+	RepeatThreshold int
+}
+
+// trackedSession は動作中として一度でも観測されたセッションの監視状態です。
+type trackedSession struct {
+	cwd   string
+	path  string
+	stuck *StuckState
+}
+
+// Watchdog は動作中セッションを時間をまたいで追跡し、停滞（会話ログが伸びない・同じツール呼び出しの
+// 連続失敗）を検出するバックグラウンドジョブです。停滞へ遷移した時点で1回だけ通知します。
+type Watchdog struct {
+	reader   idle.Reader
+	notifier service.NtfyService
+	cfg      WatchdogConfig
+	now      func() time.Time
+	inspect  func(path string) (transcript.Progress, error)
+
+	mu      sync.Mutex
+	tracked map[string]*trackedSession // key: sessionID
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewWatchdog は新しいWatchdogを生成します。
+// notifier は nil 許容で、nil の場合は通知せず状態の検出のみ行います。now が nil の場合は time.Now を使います。
+func NewWatchdog(reader idle.Reader, notifier service.NtfyService, cfg WatchdogConfig, now func() time.Time) *Watchdog {
+	if now == nil {
+		now = time.Now
+	}
+	if cfg.NoProgressWindow <= 0 {
+		cfg.NoProgressWindow = defaultStuckWindow
+	}
+	if cfg.RepeatThreshold <= 0 {
+		cfg.RepeatThreshold = defaultStuckRepeatThreshold
+	}
+	return &Watchdog{
+		reader:   reader,
+		notifier: notifier,
+		cfg:      cfg,
+		now:      now,
+		inspect:  transcript.InspectProgress,
+		tracked:  make(map[string]*trackedSession),
+	}
+}
+
+// Start は停滞監視を開始します。ctx のキャンセルまたは Stop で終了します。
+func (w *Watchdog) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	w.cancel = cancel
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(watchdogInterval)
+		defer ticker.Stop()
+
+		log.Printf("[Watchdog] started: interval=%s, window=%s, repeatThreshold=%d", watchdogInterval, w.cfg.NoProgressWindow, w.cfg.RepeatThreshold)
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("[Watchdog] stopped")
+				return
+			case <-ticker.C:
+				w.Check(ctx)
+			}
+		}
+	}()
+}
+
+// Stop は停滞監視を停止し、実行中のtickが終わるまで待機します
+func (w *Watchdog) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+}
+
+// Check はマーカーを読み取り、1回分の停滞判定を行います
+func (w *Watchdog) Check(ctx context.Context) {
+	markers, err := w.reader.List(ctx)
+	if err != nil {
+		log.Printf("[Watchdog] list markers failed: %v", err)
+		return
+	}
+	w.Observe(markers, w.now())
+}
+
+// Observe はマーカー群を観測して追跡対象を更新し、停滞判定と遷移時の通知を行います。
+//   - running マーカーのセッションを追跡対象に加える（マーカーが消えても追跡は続ける）
+//   - 実行許可待ち以外の waiting に移ったセッション、ターンを終えた（生成途中でない）セッション、
+//     会話ログが消えた・TTL を超えたセッションは追跡から外す
+//   - 末尾で同一ツール呼び出しが RepeatThreshold 回以上連続失敗していれば repeated_failure
+//   - 生成途中のまま会話ログが NoProgressWindow 以上伸びていなければ no_progress
+func (w *Watchdog) Observe(markers []idle.Marker, now time.Time) {
+	running := make(map[string]bool, len(markers))
+	waiting := make(map[string]idle.WaitReason, len(markers))
+
+	w.mu.Lock()
+	for _, m := range markers {
+		switch m.Status {
+		case idle.StatusRunning:
+			running[m.SessionID] = true
+			if _, ok := w.tracked[m.SessionID]; !ok && m.TranscriptPath != "" {
+				w.tracked[m.SessionID] = &trackedSession{cwd: m.Cwd, path: m.TranscriptPath}
+			}
+		case idle.StatusWaiting:
+			waiting[m.SessionID] = m.WaitReason
+		}
+	}
+	sessions := make(map[string]*trackedSession, len(w.tracked))
+	for id, ts := range w.tracked {
+		sessions[id] = ts
+	}
+	w.mu.Unlock()
+
+	// 会話ログの読み取り（IO）はロック外で行う
+	for id, ts := range sessions {
+		if reason, ok := waiting[id]; ok && reason != idle.WaitPermission {
+			w.untrack(id, "waiting")
+			continue
+		}
+		p, err := w.inspect(ts.path)
+		if err != nil {
+			w.untrack(id, "unreadable")
+			continue
+		}
+		if now.Sub(p.ModTime) > idle.TTL {
+			w.untrack(id, "expired")
+			continue
+		}
+		if !running[id] && !p.MidTurn && p.RepeatedFailures < w.cfg.RepeatThreshold {
+			w.untrack(id, "settled")
+			continue
+		}
+		w.update(id, ts, detectStuck(id, p, w.cfg, now))
+	}
+}
+
+// detectStuck は1セッションの進捗から停滞状態を判定します（停滞でなければ nil）。
+func detectStuck(sessionID string, p transcript.Progress, cfg WatchdogConfig, now time.Time) *StuckState {
+	if p.RepeatedFailures >= cfg.RepeatThreshold {
+		return &StuckState{
+			SessionID: sessionID,
+			Reason:    StuckRepeatedFailure,
+			Since:     now.Format(time.RFC3339),
+			Detail:    fmt.Sprintf("%s が同じ入力で%d回連続して失敗しています", p.FailingTool, p.RepeatedFailures),
+			ToolName:  p.FailingTool,
+		}
+	}
+	if p.MidTurn && now.Sub(p.ModTime) >= cfg.NoProgressWindow {
+		return &StuckState{
+			SessionID: sessionID,
+			Reason:    StuckNoProgress,
+			Since:     p.ModTime.Format(time.RFC3339),
+			Detail:    "生成途中のまま会話ログが更新されていません（固まったツール呼び出しまたは許可待ち）",
+		}
+	}
+	return nil
+}
+
+// update は追跡中セッションの停滞状態を更新し、停滞への遷移（種別の変化を含む）時のみ通知します。
+// 同じ種別が続く間は検出時刻（Since）を保持し、ダッシュボードの差分配信を揺らしません。
+func (w *Watchdog) update(sessionID string, ts *trackedSession, st *StuckState) {
+	w.mu.Lock()
+	prev := ts.stuck
+	switch {
+	case st == nil:
+		ts.stuck = nil
+	case prev != nil && prev.Reason == st.Reason:
+		st.Since = prev.Since
+		ts.stuck = st
+	default:
+		ts.stuck = st
+	}
+	w.mu.Unlock()
+
+	if st == nil {
+		if prev != nil {
+			log.Printf("[Watchdog] session recovered: session=%s, cwd=%s", sessionID, ts.cwd)
+		}
+		return
+	}
+	if prev != nil && prev.Reason == st.Reason {
+		return
+	}
+	log.Printf("[Watchdog] session stuck: session=%s, cwd=%s, reason=%s", sessionID, ts.cwd, st.Reason)
+	if w.notifier != nil {
+		notify.Send(w.notifier, notify.Message{
+			Event:    notify.EventSessionStuck,
+			Project:  ts.cwd,
+			Title:    fmt.Sprintf("セッション停滞: %s", filepath.Base(ts.cwd)),
+			Body:     st.Detail,
+			Priority: notify.PriorityHigh,
+		})
+	}
+}
+
+// untrack はセッションを追跡対象から外します。
+func (w *Watchdog) untrack(sessionID, why string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if ts, ok := w.tracked[sessionID]; ok && ts.stuck != nil {
+		log.Printf("[Watchdog] stuck session cleared: session=%s, reason=%s", sessionID, why)
+	}
+	delete(w.tracked, sessionID)
+}
+
+// stuckByCwd は停滞中セッションを cwd ごとに返します（同一 cwd に複数あれば検出の早いものを優先）。
+func (w *Watchdog) stuckByCwd() map[string]StuckState {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	out := make(map[string]StuckState)
+	for _, ts := range w.tracked {
+		if ts.stuck == nil {
+			continue
+		}
+		if cur, ok := out[ts.cwd]; ok && cur.Since <= ts.stuck.Since {
+			continue
+		}
+		out[ts.cwd] = *ts.stuck
+	}
+	return out
+}
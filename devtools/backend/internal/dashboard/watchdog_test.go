@@ -0,0 +1,191 @@
+package dashboard
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/idle"
+	"ghostrunner/backend/internal/transcript"
+)
+
+// fakeNotifier は service.NtfyService を満たすテスト用スタブです
+type fakeNotifier struct {
+	mu     sync.Mutex
+	titles []string
+}
+
+func (n *fakeNotifier) Notify(title, message string) {}
+
+func (n *fakeNotifier) NotifyError(title, message string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.titles = append(n.titles, title)
+}
+
+func (n *fakeNotifier) count() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.titles)
+}
+
+// newTestWatchdog は inspect を progress マップで差し替えた Watchdog を生成します
+func newTestWatchdog(notifier *fakeNotifier, progress map[string]transcript.Progress) *Watchdog {
+	w := NewWatchdog(&fakeIdleReader{}, notifier, WatchdogConfig{NoProgressWindow: 10 * time.Minute, RepeatThreshold: 3}, nil)
+	w.inspect = func(path string) (transcript.Progress, error) {
+		p, ok := progress[path]
+		if !ok {
+			return transcript.Progress{}, os.ErrNotExist
+		}
+		return p, nil
+	}
+	return w
+}
+
+func runningMarker(cwd, sessionID, path string) idle.Marker {
+	return idle.Marker{Cwd: cwd, SessionID: sessionID, Status: idle.StatusRunning, TranscriptPath: path, SessionCount: 1}
+}
+
+// TestWatchdog_NoProgressAfterRunningDisappears は running として観測したセッションが
+// マーカーから消えた後も追跡され、生成途中のまま窓を超えたら no_progress になり1回だけ通知されることを検証します。
+func TestWatchdog_NoProgressAfterRunningDisappears(t *testing.T) {
+	notifier := &fakeNotifier{}
+	progress := map[string]transcript.Progress{
+		"/logs/s1.jsonl": {ModTime: fixedNow.Add(-1 * time.Minute), MidTurn: true},
+	}
+	w := newTestWatchdog(notifier, progress)
+
+	// 1回目: running として観測（まだ停滞ではない）
+	w.Observe([]idle.Marker{runningMarker("/proj/app", "s1", "/logs/s1.jsonl")}, fixedNow)
+	if got := w.stuckByCwd(); len(got) != 0 {
+		t.Fatalf("stuck = %v, want none", got)
+	}
+
+	// 2回目: reader が none へ落としマーカーが消えても、生成途中のまま11分伸びていなければ停滞
+	later := fixedNow.Add(10 * time.Minute)
+	w.Observe(nil, later)
+	got := w.stuckByCwd()
+	st, ok := got["/proj/app"]
+	if !ok {
+		t.Fatalf("stuck = %v, want /proj/app", got)
+	}
+	if st.Reason != StuckNoProgress || st.SessionID != "s1" {
+		t.Errorf("stuck = %+v, want no_progress/s1", st)
+	}
+	if st.Since != fixedNow.Add(-1*time.Minute).Format(time.RFC3339) {
+		t.Errorf("Since = %q, want last mtime", st.Since)
+	}
+
+	// 3回目: 同じ種別が続く間は再通知しない
+	w.Observe(nil, later.Add(time.Minute))
+	if notifier.count() != 1 {
+		t.Errorf("notifications = %d, want 1", notifier.count())
+	}
+}
+
+// TestWatchdog_RepeatedFailure は同一ツール呼び出しの連続失敗がしきい値以上で repeated_failure になることを検証します。
+func TestWatchdog_RepeatedFailure(t *testing.T) {
+	notifier := &fakeNotifier{}
+	progress := map[string]transcript.Progress{
+		"/logs/s1.jsonl": {ModTime: fixedNow, MidTurn: true, RepeatedFailures: 3, FailingTool: "Bash"},
+	}
+	w := newTestWatchdog(notifier, progress)
+
+	w.Observe([]idle.Marker{runningMarker("/proj/app", "s1", "/logs/s1.jsonl")}, fixedNow)
+	st, ok := w.stuckByCwd()["/proj/app"]
+	if !ok {
+		t.Fatal("expected stuck")
+	}
+	if st.Reason != StuckRepeatedFailure || st.ToolName != "Bash" {
+		t.Errorf("stuck = %+v, want repeated_failure/Bash", st)
+	}
+	if notifier.count() != 1 {
+		t.Errorf("notifications = %d, want 1", notifier.count())
+	}
+}
+
+// TestWatchdog_Untrack は停滞状態が解消・追跡解除される条件を検証します。
+func TestWatchdog_Untrack(t *testing.T) {
+	tests := []struct {
+		name     string
+		markers  []idle.Marker
+		progress transcript.Progress
+		present  bool
+	}{
+		{
+			name:     "返信待ちへ移ったら追跡解除",
+			markers:  []idle.Marker{{Cwd: "/proj/app", SessionID: "s1", Status: idle.StatusWaiting, WaitReason: idle.WaitEndOfTurn}},
+			progress: transcript.Progress{ModTime: fixedNow.Add(-20 * time.Minute), MidTurn: true},
+		},
+		{
+			name:     "実行許可待ちは追跡を続け停滞扱い",
+			markers:  []idle.Marker{{Cwd: "/proj/app", SessionID: "s1", Status: idle.StatusWaiting, WaitReason: idle.WaitPermission}},
+			progress: transcript.Progress{ModTime: fixedNow.Add(-20 * time.Minute), MidTurn: true},
+			present:  true,
+		},
+		{
+			name:     "生成途中でなくなったら追跡解除",
+			progress: transcript.Progress{ModTime: fixedNow.Add(-20 * time.Minute)},
+		},
+		{
+			name:     "TTL超過で追跡解除",
+			progress: transcript.Progress{ModTime: fixedNow.Add(-7 * time.Hour), MidTurn: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := map[string]transcript.Progress{"/logs/s1.jsonl": {ModTime: fixedNow.Add(-30 * time.Minute), MidTurn: true}}
+			w := newTestWatchdog(&fakeNotifier{}, progress)
+			w.Observe([]idle.Marker{runningMarker("/proj/app", "s1", "/logs/s1.jsonl")}, fixedNow.Add(-10*time.Minute))
+
+			progress["/logs/s1.jsonl"] = tt.progress
+			w.Observe(tt.markers, fixedNow)
+
+			_, ok := w.stuckByCwd()["/proj/app"]
+			if ok != tt.present {
+				t.Errorf("stuck present = %v, want %v", ok, tt.present)
+			}
+		})
+	}
+}
+
+// TestGetState_StuckAttached は WithWatchdog 設定時に停滞セッションが ProjectState.Stuck に付与され、
+// attention=required・ソート最優先になることを検証します。
+func TestGetState_StuckAttached(t *testing.T) {
+	dir := t.TempDir()
+	projA := mkProjectDir(t, dir, "aaa")
+	projB := mkProjectDir(t, dir, "zzz-stuck")
+	configPath := makeConfig(t, dir, []map[string]string{
+		{"path": projA, "name": "aaa"},
+		{"path": projB, "name": "zzz-stuck"},
+	})
+
+	progress := map[string]transcript.Progress{
+		"/logs/s1.jsonl": {ModTime: fixedNow.Add(-30 * time.Minute), MidTurn: true},
+	}
+	w := newTestWatchdog(&fakeNotifier{}, progress)
+	w.Observe([]idle.Marker{runningMarker(projB, "s1", "/logs/s1.jsonl")}, fixedNow)
+
+	svc := NewServiceWithClock(configPath, "/other", nil, func() time.Time { return fixedNow }, WithWatchdog(w))
+	state, err := svc.GetState(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if state.Projects[0].Name != "zzz-stuck" {
+		t.Fatalf("expected stuck project first, got %s", state.Projects[0].Name)
+	}
+	p := state.Projects[0]
+	if p.Stuck == nil || p.Stuck.SessionID != "s1" {
+		t.Fatalf("Stuck = %+v, want s1", p.Stuck)
+	}
+	if p.Attention != AttentionRequired {
+		t.Errorf("Attention = %q, want required", p.Attention)
+	}
+	if findProject(t, state, "aaa").Stuck != nil {
+		t.Error("expected no stuck on unrelated project")
+	}
+}
@@ -0,0 +1,380 @@
+package events
+
+import (
+	"cmp"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// dropLogInterval は PolicyDropNewest で捨てたイベントのログを購読者ごとにまとめる間隔です
+const dropLogInterval = time.Minute
+
+// topicPattern は購読パターンの書式です（"*"、"dashboard.state"、"run.*" 等）
+var topicPattern = regexp.MustCompile(`^(\*|[a-z][a-z0-9_]*(\.([a-z0-9_]+|\*))?)$`)
+
+// Bus はプロセス内のイベントバスです。発行はノンブロッキング（PolicyBlock の購読者を除く）で、
+// 購読者ごとのバッファと背圧ポリシーで遅い購読者が他の購読者や発行側を止めないようにします。
+type Bus struct {
+	now func() time.Time
+
+	mu       sync.RWMutex
+	subs     map[uint64]sink
+	nextSub  uint64
+	retained map[string]Event
+	closed   bool
+
+	nextID atomic.Uint64
+}
+
+// sink は1購読者の配信先です。型付きチャネルごとに subscription[T] が実装します
+type sink interface {
+	// accepts はイベントが購読条件（トピック・Filter）に一致するかを返します
+	accepts(ev Event) bool
+	// offer はポリシーに従って配信し、購読を切断すべき場合 false を返します
+	offer(ev Event) bool
+	// shutdown はチャネルを閉じます（複数回呼んでも安全）
+	shutdown()
+	name() string
+}
+
+// NewBus は新しいBusを生成します。now が nil の場合は time.Now を使います。
+func NewBus(now func() time.Time) *Bus {
+	if now == nil {
+		now = time.Now
+	}
+	return &Bus{
+		now:      now,
+		subs:     make(map[uint64]sink),
+		retained: make(map[string]Event),
+	}
+}
+
+// Publish はイベントを一致する全購読者へ配信します
+func (b *Bus) Publish(topic string, data any) {
+	b.publish(topic, data, false, true)
+}
+
+// PublishRetained はイベントを配信し、トピックの最新値として保持します。
+// 保持した値は以降に購読を始めた一致する購読者へ最初に1件届きます（dashboard.state の初期値）。
+func (b *Bus) PublishRetained(topic string, data any) {
+	b.publish(topic, data, true, true)
+}
+
+// Retain はトピックの最新値を配信せずに差し替えます（内容が変わらない再スキャンで時刻だけ更新する用途）
+func (b *Bus) Retain(topic string, data any) {
+	b.publish(topic, data, true, false)
+}
+
+func (b *Bus) publish(topic string, data any, retain, deliver bool) {
+	ev := Event{ID: b.nextID.Add(1), Topic: topic, Time: b.now(), Data: data}
+
+	var targets []sink
+	var ids []uint64
+	if retain {
+		b.mu.Lock()
+	} else {
+		b.mu.RLock()
+	}
+	if retain {
+		b.retained[topic] = ev
+	}
+	if deliver && !b.closed {
+		for id, s := range b.subs {
+			if s.accepts(ev) {
+				targets = append(targets, s)
+				ids = append(ids, id)
+			}
+		}
+	}
+	if retain {
+		b.mu.Unlock()
+	} else {
+		b.mu.RUnlock()
+	}
+	if !deliver {
+		return
+	}
+	publishedTotal.Inc(family(topic))
+
+	// 配信はバスのロック外で行い、PolicyBlock の購読者が購読・解除を止めないようにする
+	for i, s := range targets {
+		if !s.offer(ev) {
+			log.Printf("[EventBus] subscriber disconnected (buffer full): subscriber=%s, topic=%s", s.name(), topic)
+			droppedTotal.Inc(s.name(), "disconnect")
+			b.remove(ids[i])
+		}
+	}
+}
+
+// Subscribe は一致するイベントを受け取るチャネルと購読解除関数を返します。
+// 保持中の最新値（PublishRetained）のうち一致するものは購読直後に届きます。
+func (b *Bus) Subscribe(opts Options) (<-chan Event, func()) {
+	return subscribe(b, opts, func(ev Event) (Event, bool) { return ev, true })
+}
+
+// SubscribeData は Event.Data が T のイベントだけを T のチャネルで受け取ります。
+// 既存のエンドポイントが従来の型のまま配信するためのアダプタで、バッファとポリシーは Subscribe と同じです。
+func SubscribeData[T any](b *Bus, opts Options) (<-chan T, func()) {
+	return subscribe(b, opts, func(ev Event) (T, bool) {
+		v, ok := ev.Data.(T)
+		return v, ok
+	})
+}
+
+func subscribe[T any](b *Bus, opts Options, convert func(Event) (T, bool)) (<-chan T, func()) {
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultBuffer
+	}
+	if !slices.Contains(Policies, opts.Policy) {
+		opts.Policy = PolicyDropNewest
+	}
+	if opts.BlockTimeout <= 0 {
+		opts.BlockTimeout = DefaultBlockTimeout
+	}
+	if opts.Name == "" {
+		opts.Name = "anonymous"
+	}
+	s := &subscription[T]{
+		opts:    opts,
+		now:     b.now,
+		convert: convert,
+		ch:      make(chan T, opts.Buffer),
+		done:    make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		s.shutdown()
+		return s.ch, func() {}
+	}
+	id := b.nextSub
+	b.nextSub++
+	b.subs[id] = s
+	// 保持値はロック内で配信し、以降の発行より必ず先に届くようにする（空きが無ければ捨てる）
+	for _, ev := range b.retainedFor(s) {
+		s.tryOffer(ev)
+	}
+	b.mu.Unlock()
+
+	return s.ch, func() { b.remove(id) }
+}
+
+// retainedFor は購読者に一致する保持値をID順に返します（b.mu 保持下で呼ぶ）
+func (b *Bus) retainedFor(s sink) []Event {
+	var out []Event
+	for _, ev := range b.retained {
+		if s.accepts(ev) {
+			out = append(out, ev)
+		}
+	}
+	slices.SortFunc(out, func(a, b Event) int { return cmp.Compare(a.ID, b.ID) })
+	return out
+}
+
+// remove は購読を外してチャネルを閉じます（複数回呼んでも安全）
+func (b *Bus) remove(id uint64) {
+	b.mu.Lock()
+	s, ok := b.subs[id]
+	delete(b.subs, id)
+	b.mu.Unlock()
+	if ok {
+		s.shutdown()
+	}
+}
+
+// Close は全購読を閉じ、以降の発行を配信しません（サーバー停止時に SSE / WebSocket を終わらせる）
+func (b *Bus) Close() {
+	b.mu.Lock()
+	b.closed = true
+	subs := b.subs
+	b.subs = make(map[uint64]sink)
+	b.mu.Unlock()
+	for _, s := range subs {
+		s.shutdown()
+	}
+}
+
+// Subscribers は現在の購読者数を返します
+func (b *Bus) Subscribers() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
+
+// subscription は型付きチャネルへの配信先です
+type subscription[T any] struct {
+	opts    Options
+	now     func() time.Time
+	convert func(Event) (T, bool)
+
+	// mu は送信とチャネルのクローズを直列化します（閉じたチャネルへ送らない）
+	mu       sync.Mutex
+	ch       chan T
+	closed   bool
+	done     chan struct{}
+	doneOnce sync.Once
+
+	// dropped / droppedSince は最後にログへ出してから PolicyDropNewest で捨てた件数とその起点です（mu 保持下で更新）
+	dropped      int
+	droppedSince time.Time
+}
+
+func (s *subscription[T]) name() string { return s.opts.Name }
+
+func (s *subscription[T]) accepts(ev Event) bool {
+	if !Match(s.opts.Topics, ev.Topic) {
+		return false
+	}
+	if _, ok := s.convert(ev); !ok {
+		return false
+	}
+	return s.opts.Filter == nil || s.opts.Filter(ev)
+}
+
+// tryOffer は空きがあるときだけ送ります（保持値の初期配信用）
+func (s *subscription[T]) tryOffer(ev Event) {
+	v, _ := s.convert(ev)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.ch <- v:
+	default:
+	}
+}
+
+func (s *subscription[T]) offer(ev Event) bool {
+	v, _ := s.convert(ev)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return true
+	}
+
+	select {
+	case s.ch <- v:
+		return true
+	default:
+	}
+
+	switch s.opts.Policy {
+	case PolicyDropOldest:
+		// 受信側と競合しても、送信は本購読の mu 保持下のみのため数回で収束する
+		for {
+			select {
+			case <-s.ch:
+				droppedTotal.Inc(s.opts.Name, string(PolicyDropOldest))
+			default:
+			}
+			select {
+			case s.ch <- v:
+				return true
+			default:
+			}
+		}
+	case PolicyBlock:
+		timer := time.NewTimer(s.opts.BlockTimeout)
+		defer timer.Stop()
+		select {
+		case s.ch <- v:
+			return true
+		case <-s.done:
+			return true
+		case <-timer.C:
+			return false
+		}
+	case PolicyDisconnect:
+		return false
+	default:
+		droppedTotal.Inc(s.opts.Name, string(PolicyDropNewest))
+		s.logDropLocked(ev.Topic)
+		return true
+	}
+}
+
+// logDropLocked は捨てたイベントをログに出します。満杯が続く購読者でログが溢れないよう、
+// 最初の1件と、以降は dropLogInterval ごとにその間の件数を1行にまとめて出します（s.mu 保持下で呼ぶ）
+func (s *subscription[T]) logDropLocked(topic string) {
+	now := s.now()
+	if s.droppedSince.IsZero() {
+		log.Printf("[EventBus] subscriber buffer full, skipping events: subscriber=%s, topic=%s", s.opts.Name, topic)
+		s.droppedSince = now
+		return
+	}
+	s.dropped++
+	if now.Sub(s.droppedSince) < dropLogInterval {
+		return
+	}
+	log.Printf("[EventBus] subscriber buffer still full, skipped events: subscriber=%s, count=%d, since=%s",
+		s.opts.Name, s.dropped, s.droppedSince.Format(time.RFC3339))
+	s.dropped = 0
+	s.droppedSince = now
+}
+
+func (s *subscription[T]) shutdown() {
+	// 先に done を閉じ、PolicyBlock で待っている送信を解放してから mu を取る
+	s.doneOnce.Do(func() { close(s.done) })
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+// Match はトピックがパターンのいずれかに一致するかを返します。パターンが空なら常に一致します
+func Match(patterns []string, topic string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		switch {
+		case p == "*", p == topic:
+			return true
+		case strings.HasSuffix(p, ".*") && strings.HasPrefix(topic, p[:len(p)-1]):
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTopics はクエリ等のカンマ区切りのトピック指定を検証して返します。空文字は全件（nil）です。
+// 書式が不正なもの、系統（Families）が未知のものは ErrValidation です。
+func ParseTopics(raw string) ([]string, error) {
+	var out []string
+	for _, p := range strings.Split(raw, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if !topicPattern.MatchString(p) {
+			return nil, fmt.Errorf("%w: invalid topic pattern: %s", ErrValidation, p)
+		}
+		if p != "*" && !slices.Contains(Families, family(p)) {
+			return nil, fmt.Errorf("%w: unknown topic family: %s (known: %s)", ErrValidation, p, strings.Join(Families, ", "))
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// ParsePolicy は背圧ポリシーの指定を検証して返します。空文字は def です
+func ParsePolicy(raw string, def Policy) (Policy, error) {
+	if raw == "" {
+		return def, nil
+	}
+	p := Policy(raw)
+	if !slices.Contains(Policies, p) {
+		return "", fmt.Errorf("%w: unknown policy: %s", ErrValidation, raw)
+	}
+	return p, nil
+}
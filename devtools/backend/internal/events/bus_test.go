@@ -0,0 +1,300 @@
+package events
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recv はチャネルから1件受け取ります（タイムアウトで失敗）
+func recv[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+	select {
+	case v, ok := <-ch:
+		if !ok {
+			t.Fatal("channel closed unexpectedly")
+		}
+		return v
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for event")
+	}
+	var zero T
+	return zero
+}
+
+// assertEmpty はチャネルに何も届いていないことを確認します
+func assertEmpty[T any](t *testing.T, ch <-chan T) {
+	t.Helper()
+	select {
+	case v := <-ch:
+		t.Fatalf("unexpected event: %+v", v)
+	default:
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		topic    string
+		want     bool
+	}{
+		{name: "パターンなしは全件", patterns: nil, topic: "run.init", want: true},
+		{name: "全件", patterns: []string{"*"}, topic: "tts.failed", want: true},
+		{name: "完全一致", patterns: []string{"dashboard.state"}, topic: "dashboard.state", want: true},
+		{name: "前方一致", patterns: []string{"run.*"}, topic: "run.complete", want: true},
+		{name: "系統違い", patterns: []string{"run.*"}, topic: "patrol.scan_completed", want: false},
+		{name: "系統名だけの前方一致はしない", patterns: []string{"run.*"}, topic: "runner.x", want: false},
+		{name: "いずれかに一致", patterns: []string{"task.*", "tts.synthesized"}, topic: "tts.synthesized", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Match(tt.patterns, tt.topic); got != tt.want {
+				t.Errorf("Match(%v, %q) = %v, want %v", tt.patterns, tt.topic, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTopics(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    int
+		wantErr bool
+	}{
+		{name: "空は全件", raw: "", want: 0},
+		{name: "複数", raw: "run.*, dashboard.state,task.created", want: 3},
+		{name: "全件指定", raw: "*", want: 1},
+		{name: "未知の系統", raw: "foo.*", wantErr: true},
+		{name: "不正な書式", raw: "run.**", wantErr: true},
+		{name: "大文字", raw: "Run.init", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTopics(tt.raw)
+			if tt.wantErr {
+				if !errors.Is(err, ErrValidation) {
+					t.Errorf("expected ErrValidation, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("got %v, want %d patterns", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBus_PublishFiltersByTopic(t *testing.T) {
+	b := NewBus(nil)
+	runCh, unsubRun := b.Subscribe(Options{Topics: []string{"run.*"}})
+	defer unsubRun()
+	allCh, unsubAll := b.Subscribe(Options{})
+	defer unsubAll()
+
+	b.Publish("patrol.scan_completed", "p")
+	b.Publish("run.init", "r")
+
+	if ev := recv(t, runCh); ev.Topic != "run.init" || ev.Data != "r" {
+		t.Errorf("run subscriber got %+v", ev)
+	}
+	assertEmpty(t, runCh)
+
+	first, second := recv(t, allCh), recv(t, allCh)
+	if first.Topic != "patrol.scan_completed" || second.Topic != "run.init" {
+		t.Errorf("all subscriber order: %s, %s", first.Topic, second.Topic)
+	}
+	if first.ID >= second.ID {
+		t.Errorf("event IDs should increase: %d, %d", first.ID, second.ID)
+	}
+}
+
+func TestBus_FilterAndSubscribeData(t *testing.T) {
+	type payload struct{ RunID string }
+	b := NewBus(nil)
+	ch, unsub := SubscribeData[payload](b, Options{
+		Topics: []string{"run.*"},
+		Filter: func(ev Event) bool { return ev.Data.(payload).RunID == "a" },
+	})
+	defer unsub()
+
+	b.Publish("run.text", "not a payload")
+	b.Publish("run.text", payload{RunID: "b"})
+	b.Publish("run.text", payload{RunID: "a"})
+
+	if got := recv(t, ch); got.RunID != "a" {
+		t.Errorf("got %+v, want run a", got)
+	}
+	assertEmpty(t, ch)
+}
+
+func TestBus_Policies(t *testing.T) {
+	t.Run("drop_newest は満杯時に新しいものを捨てる", func(t *testing.T) {
+		b := NewBus(nil)
+		ch, unsub := b.Subscribe(Options{Buffer: 2, Policy: PolicyDropNewest})
+		defer unsub()
+		for i := 1; i <= 3; i++ {
+			b.Publish("task.created", i)
+		}
+		if a, c := recv(t, ch).Data, recv(t, ch).Data; a != 1 || c != 2 {
+			t.Errorf("got %v, %v, want 1, 2", a, c)
+		}
+		assertEmpty(t, ch)
+	})
+
+	t.Run("drop_newest のログは最初の1件と間隔ごとのまとめだけ", func(t *testing.T) {
+		var buf bytes.Buffer
+		log.SetOutput(&buf)
+		defer log.SetOutput(os.Stderr)
+
+		now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+		b := NewBus(func() time.Time { return now })
+		_, unsub := b.Subscribe(Options{Name: "slow", Buffer: 1, Policy: PolicyDropNewest})
+		defer unsub()
+		for i := 0; i < 10; i++ {
+			b.Publish("task.created", i)
+		}
+		if got := strings.Count(buf.String(), "[EventBus]"); got != 1 {
+			t.Fatalf("log lines = %d, want 1 for the first drop:\n%s", got, buf.String())
+		}
+		now = now.Add(dropLogInterval)
+		b.Publish("task.created", 10)
+		if got := strings.Count(buf.String(), "[EventBus]"); got != 2 {
+			t.Fatalf("log lines = %d, want 2 after interval:\n%s", got, buf.String())
+		}
+		if !strings.Contains(buf.String(), "subscriber=slow, count=9") {
+			t.Errorf("summary should count drops since the first log:\n%s", buf.String())
+		}
+	})
+
+	t.Run("drop_oldest は満杯時に古いものを捨てる", func(t *testing.T) {
+		b := NewBus(nil)
+		ch, unsub := b.Subscribe(Options{Buffer: 1, Policy: PolicyDropOldest})
+		defer unsub()
+		for i := 1; i <= 3; i++ {
+			b.Publish("dashboard.state", i)
+		}
+		if got := recv(t, ch).Data; got != 3 {
+			t.Errorf("got %v, want latest 3", got)
+		}
+	})
+
+	t.Run("disconnect は満杯時に購読を閉じる", func(t *testing.T) {
+		b := NewBus(nil)
+		ch, unsub := b.Subscribe(Options{Buffer: 1, Policy: PolicyDisconnect})
+		defer unsub()
+		b.Publish("tts.failed", 1)
+		b.Publish("tts.failed", 2)
+		if got := recv(t, ch).Data; got != 1 {
+			t.Errorf("got %v, want 1", got)
+		}
+		if _, ok := <-ch; ok {
+			t.Error("channel should be closed after disconnect")
+		}
+		if b.Subscribers() != 0 {
+			t.Errorf("subscribers = %d, want 0", b.Subscribers())
+		}
+	})
+
+	t.Run("block は受信を待ち、取りこぼさない", func(t *testing.T) {
+		b := NewBus(nil)
+		ch, unsub := b.Subscribe(Options{Buffer: 1, Policy: PolicyBlock})
+		defer unsub()
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 1; i <= 5; i++ {
+				b.Publish("run.text", i)
+			}
+		}()
+		for i := 1; i <= 5; i++ {
+			if got := recv(t, ch).Data; got != i {
+				t.Errorf("event %d: got %v", i, got)
+			}
+		}
+		wg.Wait()
+	})
+
+	t.Run("block は待ち時間を超えると切断する", func(t *testing.T) {
+		b := NewBus(nil)
+		ch, unsub := b.Subscribe(Options{Buffer: 1, Policy: PolicyBlock, BlockTimeout: 10 * time.Millisecond})
+		defer unsub()
+		b.Publish("run.text", 1)
+		b.Publish("run.text", 2)
+		recv(t, ch)
+		if _, ok := <-ch; ok {
+			t.Error("channel should be closed after block timeout")
+		}
+	})
+
+	t.Run("block 中の購読解除で発行側が解放される", func(t *testing.T) {
+		b := NewBus(nil)
+		ch, unsub := b.Subscribe(Options{Buffer: 1, Policy: PolicyBlock, BlockTimeout: time.Minute})
+		b.Publish("run.text", 1)
+
+		done := make(chan struct{})
+		go func() {
+			b.Publish("run.text", 2)
+			close(done)
+		}()
+		time.Sleep(10 * time.Millisecond)
+		unsub()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("publisher still blocked after unsubscribe")
+		}
+		for range ch {
+		}
+	})
+}
+
+func TestBus_Retained(t *testing.T) {
+	b := NewBus(nil)
+	b.PublishRetained("dashboard.state", "v1")
+	b.Retain("dashboard.state", "v2")
+	b.Publish("run.init", "not retained")
+
+	ch, unsub := b.Subscribe(Options{})
+	defer unsub()
+	if ev := recv(t, ch); ev.Data != "v2" {
+		t.Errorf("retained = %v, want v2", ev.Data)
+	}
+	assertEmpty(t, ch)
+
+	other, unsubOther := b.Subscribe(Options{Topics: []string{"run.*"}})
+	defer unsubOther()
+	assertEmpty(t, other)
+}
+
+func TestBus_UnsubscribeAndClose(t *testing.T) {
+	b := NewBus(nil)
+	ch, unsub := b.Subscribe(Options{})
+	unsub()
+	unsub() // 二重解除も安全
+	if _, ok := <-ch; ok {
+		t.Error("channel should be closed after unsubscribe")
+	}
+	b.Publish("run.init", nil) // 解除後の発行で panic しない
+
+	ch2, _ := b.Subscribe(Options{})
+	b.Close()
+	if _, ok := <-ch2; ok {
+		t.Error("channel should be closed after Close")
+	}
+	ch3, _ := b.Subscribe(Options{})
+	if _, ok := <-ch3; ok {
+		t.Error("subscribe after Close should return closed channel")
+	}
+}
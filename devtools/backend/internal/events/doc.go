@@ -0,0 +1,39 @@
+// Package events はサーバー内のイベントバスを提供する。
+//
+// # 概要
+//
+// 巡回（patrol）、ダッシュボード状態、コマンド・計画の実行、カンバンのタスク操作、音声合成の各イベントを
+// 1つのバスに型付きトピックで流す。各機能の既存 SSE エンドポイント（/api/patrol/stream、
+// /api/dashboard/stream、/api/command/stream 等）はバスの購読を従来の形式で書き出す薄いアダプタで、
+// GET /api/bus/events（SSE）と GET /api/bus/events/ws（WebSocket）はトピック指定で複数系統をまとめて購読できる。
+//
+// トピック:
+//
+//	run.<type> / run.finished      コマンド・計画の実行イベント（type は init / tool_use / complete 等）
+//	patrol.<type>                  巡回イベント（PatrolEvent.Type）
+//	dashboard.state                ダッシュボード状態のスナップショット（実変化時のみ。最新値を保持）
+//	task.created / updated / moved / reordered / deleted
+//	tts.synthesized / tts.failed
+//
+// # 主要な型・関数
+//
+//   - Bus / NewBus: バス本体。Publish / PublishRetained / Retain / Subscribe / Close
+//   - SubscribeData: Event.Data を従来の型のままチャネルで受け取る購読（既存エンドポイントのアダプタ用）
+//   - Event: ID（プロセス内で単調増加）・トピック・時刻・データ
+//   - Options / Policy: 購読のトピック・追加条件・バッファ・背圧ポリシー
+//   - Publisher: 発行側が依存するインターフェース（tasks・tts はこれだけを受け取る）
+//   - ParseTopics / ParsePolicy / Match: クエリのトピック指定・ポリシーの検証と照合
+//
+// # 設計方針
+//
+//   - 配信はバスのロック外で購読者ごとに行い、遅い購読者が他の購読者・発行側・購読の追加解除を止めない
+//   - 背圧ポリシーは購読者ごとに選ぶ。drop_newest（既定・巡回の従来挙動）、drop_oldest（最新値だけが
+//     意味を持つ状態配信）、block（取りこぼせない実行イベント。待ち時間を超えたら切断）、disconnect。
+//     block は発行側を待たせるためプロセス内の購読者に限り、API の購読者には ExternalPolicies だけを許す
+//   - 送信とチャネルのクローズは購読者ごとの mutex で直列化し、閉じたチャネルへ送らない。block で待っている
+//     送信は購読解除で即座に解放する
+//   - dashboard.state は PublishRetained で最新値を保持し、購読直後に1件届ける（従来の初期スナップショット）
+//   - 取りこぼし・切断は ghostrunner_events_dropped_total、発行数は ghostrunner_events_published_total に記録する。
+//     drop_newest で捨てたイベントのログは購読者ごとに最初の1件と1分ごとの件数のまとめだけを出す
+//   - イベントはメモリのみで永続化しない。再接続時の取りこぼし再送（Last-Event-ID）は行わない
+package events
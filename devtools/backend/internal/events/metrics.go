@@ -0,0 +1,25 @@
+package events
+
+import (
+	"strings"
+
+	"ghostrunner/backend/internal/metrics"
+)
+
+var (
+	// publishedTotal は発行されたイベント数です（family=run / patrol / dashboard / task / tts）
+	publishedTotal = metrics.NewCounterVec("ghostrunner_events_published_total",
+		"Events published on the in-process bus by topic family.", "family")
+	// droppedTotal は購読者へ届かなかったイベント数です
+	// （reason=drop_newest / drop_oldest / disconnect）
+	droppedTotal = metrics.NewCounterVec("ghostrunner_events_dropped_total",
+		"Events not delivered to a subscriber by subscriber name and reason (drop_newest, drop_oldest, disconnect).", "subscriber", "reason")
+)
+
+// family はトピックの系統（最初のドットより前）を返します
+func family(topic string) string {
+	if i := strings.IndexByte(topic, '.'); i >= 0 {
+		return topic[:i]
+	}
+	return topic
+}
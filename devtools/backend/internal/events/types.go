@@ -0,0 +1,101 @@
+package events
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrValidation はトピック指定・購読設定が不正な場合のエラーです
+var ErrValidation = errors.New("validation error")
+
+// トピックは「<系統>.<種別>」のドット区切りです。購読はトピック名の完全一致、「<系統>.*」の前方一致、
+// 「*」（全件）で指定します。
+const (
+	// TopicRunPrefix はコマンド・計画の実行イベントの系統です（run.<StreamEvent.Type> と run.finished）
+	TopicRunPrefix = "run."
+	// TopicRunFinished は実行のイベント列が終わったことを示します（最後の1件）
+	TopicRunFinished = "run.finished"
+	// TopicPatrolPrefix は巡回イベントの系統です（patrol.<PatrolEvent.Type>）
+	TopicPatrolPrefix = "patrol."
+	// TopicDashboardState はダッシュボード状態のスナップショットです（実変化時のみ。最新値を保持）
+	TopicDashboardState = "dashboard.state"
+	// TopicTaskPrefix はカンバンのタスク操作の系統です
+	TopicTaskPrefix = "task."
+	// TopicTaskCreated はタスクの作成です
+	TopicTaskCreated = "task.created"
+	// TopicTaskUpdated はタスク本文の更新です
+	TopicTaskUpdated = "task.updated"
+	// TopicTaskMoved はタスクのレーン移動（同一レーン内の位置変更を含む）です
+	TopicTaskMoved = "task.moved"
+	// TopicTaskReordered はレーン内の並び替えです
+	TopicTaskReordered = "task.reordered"
+	// TopicTaskDeleted はタスクの削除です
+	TopicTaskDeleted = "task.deleted"
+	// TopicTTSPrefix は音声合成の系統です
+	TopicTTSPrefix = "tts."
+	// TopicTTSSynthesized は音声合成の完了（キャッシュヒットを含む）です
+	TopicTTSSynthesized = "tts.synthesized"
+	// TopicTTSFailed は音声合成の失敗です
+	TopicTTSFailed = "tts.failed"
+)
+
+// Families は購読で指定できるトピックの系統です
+var Families = []string{"run", "patrol", "dashboard", "task", "tts"}
+
+// Event はバスを流れる1件のイベントです。ID はプロセス内で単調増加します
+type Event struct {
+	ID    uint64    `json:"id"`
+	Topic string    `json:"topic"`
+	Time  time.Time `json:"time"`
+	Data  any       `json:"data"`
+}
+
+// Policy は購読者のバッファが満杯のときの扱いです
+type Policy string
+
+const (
+	// PolicyDropNewest は満杯時に新しいイベントを捨てます（既定。巡回イベントの従来挙動）
+	PolicyDropNewest Policy = "drop_newest"
+	// PolicyDropOldest は満杯時に最も古いイベントを捨てて新しいものを入れます（最新値だけが意味を持つ状態配信向け）
+	PolicyDropOldest Policy = "drop_oldest"
+	// PolicyBlock は空きが出るまで BlockTimeout だけ待ち、それでも満杯なら購読を切断します（取りこぼせない実行イベント向け）
+	PolicyBlock Policy = "block"
+	// PolicyDisconnect は満杯時に購読を切断します（遅い購読者に再接続させる）
+	PolicyDisconnect Policy = "disconnect"
+)
+
+// Policies は指定できる背圧ポリシーです
+var Policies = []Policy{PolicyDropNewest, PolicyDropOldest, PolicyBlock, PolicyDisconnect}
+
+// ExternalPolicies は外部（API 経由）の購読者に許す背圧ポリシーです。
+// PolicyBlock は遅い購読者がすべての発行側を待たせるため、プロセス内の購読者（streamRun 等）に限ります。
+var ExternalPolicies = []Policy{PolicyDropNewest, PolicyDropOldest, PolicyDisconnect}
+
+const (
+	// DefaultBuffer は購読チャネルの既定バッファサイズです
+	DefaultBuffer = 64
+	// DefaultBlockTimeout は PolicyBlock の既定待ち時間です
+	DefaultBlockTimeout = 5 * time.Second
+)
+
+// Options は購読の設定です
+type Options struct {
+	// Topics は購読するトピックのパターン（完全一致 / "<系統>.*" / "*"）。空は全件です
+	Topics []string
+	// Filter はトピック一致後に追加で絞り込む条件です（nil は絞り込みなし）
+	Filter func(Event) bool
+	// Buffer はチャネルのバッファサイズです（0 以下は DefaultBuffer）
+	Buffer int
+	// Policy は満杯時の扱いです（空は PolicyDropNewest）
+	Policy Policy
+	// BlockTimeout は PolicyBlock の待ち時間です（0 以下は DefaultBlockTimeout）
+	BlockTimeout time.Duration
+	// Name はログとメトリクスに使う購読者名です（例: "dashboard", "events"）
+	Name string
+}
+
+// Publisher はイベントの発行口です。発行側のパッケージはバス本体ではなくこのインターフェースに依存します
+type Publisher interface {
+	// Publish はイベントを一致する全購読者へ配信します
+	Publish(topic string, data any)
+}
@@ -0,0 +1,30 @@
+// Package export は Claude Code の会話ログ1セッションを共有用の Markdown / 単一 HTML / JSON に書き出す。
+//
+// # 概要
+//
+// エージェントがタスクで何をしたかを、マシンにアクセスできないチームメンバーと共有するための
+// エクスポート機能。GET /api/exports/sessions/:id と gr-transcript export CLI の共通実体。
+// 会話を発言単位に整形し、ツール呼び出しは入力と結果を折りたたみ（<details>）で表示する。
+// 会話中で参照された 開発/実装 配下のタスクファイル（計画書）を現在の所在から探して埋め込む。
+//
+// # 主要な型・関数
+//
+//   - Exporter / NewExporter: Build は session-id または .jsonl パスから（CLI 用）、BuildSession は session-id の形の値だけから
+//     （API 用）Transcript を組み立てる
+//   - ErrNotFound / ErrInvalidID: セッションが無い・未登録プロジェクト（API）/ session-id の形でない指定
+//   - Transcript / Message / Block / ToolCall / TaskFile: エクスポート用の整形済み会話
+//   - Render / Format: md / html / json への書き出し
+//   - Redactor / ProjectSecrets / EnvSecrets: 秘匿値の伏せ字化
+//
+// # 設計方針
+//
+//   - 伏せ字は2段構え: プロジェクトの .env 系ファイルとサーバー環境変数（*_KEY / *_TOKEN 等）の値を
+//     完全一致で伏せ、加えて API キーらしい文字列（sk-… / AKIA… / ghp_… / 秘密鍵 PEM 等）と
+//     秘匿らしい代入（api_key=… 等）をパターンで伏せる。短い値（8文字未満）や自明な値は対象外
+//   - thinking と isMeta（コマンド展開等の自動挿入）は共有物に不要なため載せない
+//   - ツールの入出力は1件あたり maxToolOutputRunes 文字で切り詰める（Read の全文等で読めなくなるのを防ぐ）
+//   - タスクファイルはレーン間を移動するため、参照時のレーンに無ければ同名ファイルを全レーンから探す
+//   - API 経由では登録プロジェクトに帰属するセッションだけをエクスポートし、それ以外は見つからない扱いにする（CLI は制限なし）。
+//     API は英数字・ハイフン・アンダースコアの session-id だけを受け付け、パス指定や glob のメタ文字でファイルを探させない
+//   - HTML は外部アセットを持たない単一ファイルで、本文は html/template で自動エスケープする
+package export
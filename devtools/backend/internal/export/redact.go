@@ -0,0 +1,162 @@
+package export
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// redactedMark は秘匿値の置換文字列です。
+const redactedMark = "[REDACTED]"
+
+// minSecretLen は .env / 環境変数から秘匿値として扱う最小文字数です。
+// "true" や "3000" 等の短い値まで伏せると本文が読めなくなるため、短い値は対象外にします。
+const minSecretLen = 8
+
+// secretPatterns は API キーらしい文字列のパターンです（値そのものを伏せる）。
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+	regexp.MustCompile(`\bsk-(?:ant-|proj-)?[A-Za-z0-9_\-]{20,}`),
+	regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`),
+	regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`),
+	regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`),
+	regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{40,}\b`),
+	regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9\-]{10,}`),
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]{10,}\.[A-Za-z0-9_\-]{10,}\.[A-Za-z0-9_\-]{10,}`),
+}
+
+// assignmentPattern は KEY=value / "key": "value" 形式の秘匿らしい代入です（値部分だけを伏せる）。
+var assignmentPattern = regexp.MustCompile(`(?i)((?:api[_-]?key|secret|token|password|passwd|private[_-]?key)["']?\s*[:=]\s*["']?)([A-Za-z0-9_\-./+=]{12,})`)
+
+// secretKeyPattern は環境変数名が秘匿値を表すかの判定パターンです。
+var secretKeyPattern = regexp.MustCompile(`(?i)(KEY|TOKEN|SECRET|PASSWORD|PASSWD|TOPIC|DSN|CREDENTIALS?)$`)
+
+// Redactor はエクスポート本文から秘匿値を伏せます。
+type Redactor struct {
+	// values は完全一致で伏せる既知の秘匿値です（長い順。部分一致の取りこぼしを防ぐ）。
+	values []string
+}
+
+// NewRedactor は既知の秘匿値 values を伏せる Redactor を生成します。
+// パターン（API キーらしい文字列・秘匿らしい代入）は values の有無に関わらず常に適用します。
+func NewRedactor(values []string) *Redactor {
+	seen := make(map[string]struct{})
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if len(v) < minSecretLen {
+			continue
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	sort.Slice(out, func(i, j int) bool { return len(out[i]) > len(out[j]) })
+	return &Redactor{values: out}
+}
+
+// Redact は s から既知の秘匿値と API キーらしい文字列を伏せた文字列を返します。
+func (r *Redactor) Redact(s string) string {
+	if s == "" {
+		return s
+	}
+	if r != nil {
+		for _, v := range r.values {
+			s = strings.ReplaceAll(s, v, redactedMark)
+		}
+	}
+	for _, re := range secretPatterns {
+		s = re.ReplaceAllString(s, redactedMark)
+	}
+	return assignmentPattern.ReplaceAllString(s, "${1}"+redactedMark)
+}
+
+// ProjectSecrets はプロジェクトの .env 系ファイル（ルートと1階層下。.env.example 等の雛形は除く）の値を返します。
+func ProjectSecrets(projectPath string) []string {
+	if projectPath == "" {
+		return nil
+	}
+	patterns := []string{
+		filepath.Join(projectPath, ".env*"),
+		filepath.Join(projectPath, "*", ".env*"),
+	}
+	values := make([]string, 0)
+	for _, pattern := range patterns {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			continue
+		}
+		for _, p := range paths {
+			base := filepath.Base(p)
+			if strings.HasSuffix(base, ".example") || strings.HasSuffix(base, ".sample") || strings.HasSuffix(base, ".template") {
+				continue
+			}
+			values = append(values, envFileValues(p)...)
+		}
+	}
+	return values
+}
+
+// EnvSecrets は現在のプロセス環境変数のうち、名前が秘匿値を表すもの（*_KEY / *_TOKEN 等）の値を返します。
+func EnvSecrets() []string {
+	values := make([]string, 0)
+	for _, kv := range os.Environ() {
+		k, v, ok := strings.Cut(kv, "=")
+		if ok && secretKeyPattern.MatchString(k) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// envFileValues は .env 形式ファイルの値一覧を返します。読めないファイルは空を返します。
+func envFileValues(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	values := make([]string, 0)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		_, v, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		v = strings.TrimSpace(v)
+		if unq, err := strconv.Unquote(v); err == nil {
+			v = unq
+		} else {
+			v = strings.Trim(v, `'`)
+		}
+		if isPlainValue(v) {
+			continue
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+// isPlainValue は秘匿値として扱わない自明な値（真偽値・数値・localhost URL 等）かを返します。
+func isPlainValue(v string) bool {
+	switch strings.ToLower(v) {
+	case "", "true", "false", "development", "production", "test":
+		return true
+	}
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return true
+	}
+	return strings.HasPrefix(v, "http://localhost") || strings.HasPrefix(v, "http://127.0.0.1")
+}
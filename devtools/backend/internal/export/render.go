@@ -0,0 +1,218 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+)
+
+// Format はエクスポート形式です。
+type Format string
+
+const (
+	// FormatMarkdown は GitHub 等でそのまま読める Markdown（ツール呼び出しは <details> で折りたたみ）です。
+	FormatMarkdown Format = "md"
+	// FormatHTML は外部アセットに依存しない単一 HTML ファイルです。
+	FormatHTML Format = "html"
+	// FormatJSON は Transcript をそのまま JSON にしたものです。
+	FormatJSON Format = "json"
+)
+
+// ParseFormat は文字列をエクスポート形式に変換します。空文字は FormatMarkdown です。
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatMarkdown:
+		return FormatMarkdown, nil
+	case FormatHTML, FormatJSON:
+		return Format(s), nil
+	}
+	return "", fmt.Errorf("unsupported format: %s", s)
+}
+
+// ContentType は形式に対応する Content-Type を返します。
+func (f Format) ContentType() string {
+	switch f {
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	case FormatJSON:
+		return "application/json; charset=utf-8"
+	}
+	return "text/markdown; charset=utf-8"
+}
+
+// Render は Transcript を指定形式で w に書き出します。
+func Render(w io.Writer, f Format, t *Transcript) error {
+	switch f {
+	case FormatHTML:
+		return renderHTML(w, t)
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(t)
+	case FormatMarkdown:
+		return renderMarkdown(w, t)
+	}
+	return fmt.Errorf("unsupported format: %s", f)
+}
+
+// roleLabel は発言者の表示名を返します。
+func roleLabel(m Message) string {
+	label := "ユーザー"
+	if m.Role == "assistant" {
+		label = "Claude"
+	}
+	if m.Sidechain {
+		label += "（サブエージェント）"
+	}
+	return label
+}
+
+// toolSummary はツール呼び出しの折りたたみ見出しを返します。
+func toolSummary(tc *ToolCall) string {
+	status := "成功"
+	if tc.IsError {
+		status = "エラー"
+	} else if tc.Result == "" {
+		status = "結果なし"
+	}
+	return fmt.Sprintf("ツール: %s（%s）", tc.Name, status)
+}
+
+// formatTime は表示用の時刻文字列を返します（ゼロ値は空）。
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// fence は content 内のバッククォート連続より長いコードフェンスを返します。
+func fence(content string) string {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// renderMarkdown は Markdown 形式で書き出します。
+func renderMarkdown(w io.Writer, t *Transcript) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# セッション %s\n\n", t.SessionID)
+	if t.ProjectPath != "" {
+		fmt.Fprintf(&b, "- プロジェクト: `%s`\n", t.ProjectPath)
+	}
+	fmt.Fprintf(&b, "- 期間: %s 〜 %s\n", formatTime(t.StartedAt), formatTime(t.EndedAt))
+	fmt.Fprintf(&b, "- エクスポート: %s\n\n", formatTime(t.ExportedAt))
+
+	if t.TaskFile != nil {
+		f := fence(t.TaskFile.Content)
+		fmt.Fprintf(&b, "## タスクファイル: %s\n\n<details>\n<summary>%s</summary>\n\n%smarkdown\n%s\n%s\n\n</details>\n\n",
+			t.TaskFile.Path, t.TaskFile.Path, f, strings.TrimRight(t.TaskFile.Content, "\n"), f)
+	}
+
+	b.WriteString("## 会話\n\n")
+	for _, m := range t.Messages {
+		fmt.Fprintf(&b, "### %s", roleLabel(m))
+		if ts := formatTime(m.Timestamp); ts != "" {
+			fmt.Fprintf(&b, " — %s", ts)
+		}
+		b.WriteString("\n\n")
+		for _, blk := range m.Blocks {
+			if blk.Tool == nil {
+				b.WriteString(strings.TrimRight(blk.Text, "\n"))
+				b.WriteString("\n\n")
+				continue
+			}
+			tc := blk.Tool
+			fmt.Fprintf(&b, "<details>\n<summary>%s</summary>\n\n", template.HTMLEscapeString(toolSummary(tc)))
+			if tc.Input != "" {
+				f := fence(tc.Input)
+				fmt.Fprintf(&b, "入力:\n\n%sjson\n%s\n%s\n\n", f, tc.Input, f)
+			}
+			if tc.Result != "" {
+				f := fence(tc.Result)
+				fmt.Fprintf(&b, "結果:\n\n%s\n%s\n%s\n\n", f, strings.TrimRight(tc.Result, "\n"), f)
+			}
+			b.WriteString("</details>\n\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// htmlTemplate は単一ファイルで完結する HTML エクスポートのテンプレートです。
+var htmlTemplate = template.Must(template.New("export").Funcs(template.FuncMap{
+	"roleLabel":   roleLabel,
+	"toolSummary": toolSummary,
+	"formatTime":  formatTime,
+}).Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>セッション {{.SessionID}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Hiragino Sans", "Noto Sans JP", sans-serif; max-width: 960px; margin: 2rem auto; padding: 0 1rem; color: #1f2328; line-height: 1.6; }
+header { border-bottom: 1px solid #d0d7de; margin-bottom: 1.5rem; }
+dl.meta { display: grid; grid-template-columns: max-content 1fr; gap: .25rem 1rem; font-size: .9rem; }
+dl.meta dt { color: #656d76; }
+.msg { border: 1px solid #d0d7de; border-radius: 8px; margin: 1rem 0; padding: .75rem 1rem; }
+.msg.user { background: #f6f8fa; }
+.msg.sidechain { margin-left: 2rem; border-style: dashed; }
+.role { font-weight: 600; font-size: .9rem; }
+.time { color: #656d76; font-size: .8rem; margin-left: .5rem; }
+.text { white-space: pre-wrap; word-break: break-word; }
+details { margin: .5rem 0; border: 1px solid #d0d7de; border-radius: 6px; padding: .25rem .75rem; background: #fff; }
+details.error summary { color: #cf222e; }
+summary { cursor: pointer; font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: .85rem; }
+pre { background: #f6f8fa; padding: .75rem; overflow-x: auto; font-size: .8rem; white-space: pre-wrap; word-break: break-all; }
+</style>
+</head>
+<body>
+<header>
+<h1>セッション {{.SessionID}}</h1>
+<dl class="meta">
+{{if .ProjectPath}}<dt>プロジェクト</dt><dd>{{.ProjectPath}}</dd>{{end}}
+<dt>期間</dt><dd>{{formatTime .StartedAt}} 〜 {{formatTime .EndedAt}}</dd>
+<dt>エクスポート</dt><dd>{{formatTime .ExportedAt}}</dd>
+</dl>
+</header>
+{{with .TaskFile}}
+<section>
+<h2>タスクファイル</h2>
+<details><summary>{{.Path}}</summary><pre>{{.Content}}</pre></details>
+</section>
+{{end}}
+<section>
+<h2>会話</h2>
+{{range .Messages}}
+<div class="msg {{.Role}}{{if .Sidechain}} sidechain{{end}}">
+<div><span class="role">{{roleLabel .}}</span><span class="time">{{formatTime .Timestamp}}</span></div>
+{{range .Blocks}}{{if .Tool}}<details{{if .Tool.IsError}} class="error"{{end}}><summary>{{toolSummary .Tool}}</summary>
+{{if .Tool.Input}}<div>入力</div><pre>{{.Tool.Input}}</pre>{{end}}
+{{if .Tool.Result}}<div>結果</div><pre>{{.Tool.Result}}</pre>{{end}}
+</details>{{else}}<div class="text">{{.Text}}</div>{{end}}
+{{end}}
+</div>
+{{end}}
+</section>
+</body>
+</html>
+`))
+
+// renderHTML は HTML 形式で書き出します。本文は html/template が自動エスケープします。
+func renderHTML(w io.Writer, t *Transcript) error {
+	return htmlTemplate.Execute(w, t)
+}
@@ -0,0 +1,318 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"ghostrunner/backend/internal/idle"
+	"ghostrunner/backend/internal/projects"
+	"ghostrunner/backend/internal/transcript"
+)
+
+// maxToolOutputRunes はツール入出力1件をエクスポートに載せる最大文字数です。
+// Read の全文や長大なビルドログで共有物が読めなくなるのを防ぎます。
+const maxToolOutputRunes = 20000
+
+// ErrNotFound はセッションが見つからない場合のエラーです。
+var ErrNotFound = errors.New("session not found")
+
+// ErrInvalidID は session-id の形をしていない指定のエラーです（API 経由の BuildSession のみ）。
+var ErrInvalidID = errors.New("invalid session id")
+
+// sessionIDPattern は API で受け付ける session-id の形です（英数字・ハイフン・アンダースコア）。
+// パス区切り・glob のメタ文字・.jsonl 拡張子を含む指定でファイルを探させないため、許可する文字を限定します。
+var sessionIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,127}$`)
+
+// taskLanes は紐づくタスクファイルを探す 開発/実装 配下のレーンです（移動済みでも見つけられるよう全レーン）。
+var taskLanes = []string{"レビュー", "実装待ち", "実行中", "完了", filepath.Join("完了", "アーカイブ")}
+
+// taskRefPattern は会話本文中の 開発/実装/<レーン>/<ファイル>.md 参照です（/coding @開発/実装/実行中/x.md 等）。
+var taskRefPattern = regexp.MustCompile(`開発/実装/[^\s"'` + "`" + `)）\]]+?\.md`)
+
+// Transcript はエクスポート用に整形した会話1件です。
+type Transcript struct {
+	SessionID   string    `json:"sessionId"`
+	ProjectPath string    `json:"projectPath,omitempty"`
+	Cwd         string    `json:"cwd,omitempty"`
+	StartedAt   time.Time `json:"startedAt"`
+	EndedAt     time.Time `json:"endedAt"`
+	Messages    []Message `json:"messages"`
+	TaskFile    *TaskFile `json:"taskFile,omitempty"`
+	ExportedAt  time.Time `json:"exportedAt"`
+}
+
+// Message は会話の1発言（user / assistant）です。
+type Message struct {
+	Role      string    `json:"role"`
+	Timestamp time.Time `json:"timestamp"`
+	// Sidechain はサブエージェント（Task ツール）内の発言かを表します。
+	Sidechain bool    `json:"sidechain,omitempty"`
+	Blocks    []Block `json:"blocks"`
+}
+
+// Block は発言内の要素です。Text と Tool のどちらか一方を持ちます。
+type Block struct {
+	Text string    `json:"text,omitempty"`
+	Tool *ToolCall `json:"tool,omitempty"`
+}
+
+// ToolCall はツール呼び出しとその結果です。
+type ToolCall struct {
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name"`
+	Input   string `json:"input"`
+	Result  string `json:"result,omitempty"`
+	IsError bool   `json:"isError,omitempty"`
+}
+
+// TaskFile は会話に紐づくタスクファイル（開発/実装 配下の計画書）です。
+type TaskFile struct {
+	// Path はプロジェクトルートからの相対パス（現在の所在）です。
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// Exporter は session-id から共有用の Transcript を組み立てます。
+type Exporter struct {
+	homeDir          string
+	projectsProvider func() ([]projects.Project, error)
+	now              func() time.Time
+	// extraSecrets はプロジェクトの .env 以外に伏せる既知の秘匿値です。
+	extraSecrets []string
+	// allowUnregistered は未登録プロジェクトのセッションのエクスポートを許可します（CLI 用）。
+	allowUnregistered bool
+}
+
+// NewExporter は Exporter を生成します。
+// extraSecrets はプロジェクトの .env 値に加えて伏せる秘匿値（サーバーの環境変数等）です。
+// allowUnregistered が false の場合、登録プロジェクトに帰属しないセッションは ErrNotFound になります。
+func NewExporter(homeDir string, projectsProvider func() ([]projects.Project, error), now func() time.Time, extraSecrets []string, allowUnregistered bool) *Exporter {
+	return &Exporter{
+		homeDir:           homeDir,
+		projectsProvider:  projectsProvider,
+		now:               now,
+		extraSecrets:      extraSecrets,
+		allowUnregistered: allowUnregistered,
+	}
+}
+
+// Build は session-id の会話ログを読み、秘匿値を伏せた Transcript を返します。
+// sessionIDOrPath が .jsonl のパスの場合はそのファイルを直接読みます（CLI 用。API からは BuildSession を使う）。
+func (e *Exporter) Build(sessionIDOrPath string) (*Transcript, error) {
+	path, sessionID, err := e.resolve(sessionIDOrPath)
+	if err != nil {
+		return nil, err
+	}
+	return e.build(path, sessionID)
+}
+
+// BuildSession は session-id だけで会話ログを探して Transcript を返します（API 用）。
+// session-id の形でない指定（パス・glob のメタ文字・.jsonl 付き）は ErrInvalidID です。
+func (e *Exporter) BuildSession(sessionID string) (*Transcript, error) {
+	if !sessionIDPattern.MatchString(sessionID) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidID, sessionID)
+	}
+	sess, err := transcript.FindSession(e.homeDir, sessionID)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, sessionID)
+		}
+		return nil, err
+	}
+	return e.build(sess.Path, sess.ID)
+}
+
+// build は会話ログのファイルを読み、帰属プロジェクトの判定・タスクファイルの埋め込み・伏せ字化を行います
+func (e *Exporter) build(path, sessionID string) (*Transcript, error) {
+	entries, err := transcript.ReadEntries(path)
+	if err != nil {
+		return nil, err
+	}
+
+	t := buildTranscript(sessionID, entries)
+	t.ExportedAt = e.now()
+
+	projectPath, registered := e.attribute(t.Cwd)
+	if !registered && !e.allowUnregistered {
+		// 未登録プロジェクトの会話ログは存在も含めて API から見せない
+		return nil, fmt.Errorf("%w: %s (not a registered project)", ErrNotFound, sessionID)
+	}
+	t.ProjectPath = projectPath
+	t.TaskFile = findTaskFile(projectPath, entries)
+
+	secrets := append(ProjectSecrets(projectPath), e.extraSecrets...)
+	redactTranscript(t, NewRedactor(secrets))
+	return t, nil
+}
+
+// resolve は session-id またはパスから会話ログのパスと session-id を返します。
+func (e *Exporter) resolve(sessionIDOrPath string) (string, string, error) {
+	if strings.HasSuffix(sessionIDOrPath, ".jsonl") {
+		if _, err := os.Stat(sessionIDOrPath); err != nil {
+			return "", "", fmt.Errorf("%w: %s", ErrNotFound, sessionIDOrPath)
+		}
+		base := filepath.Base(sessionIDOrPath)
+		return sessionIDOrPath, strings.TrimSuffix(base, ".jsonl"), nil
+	}
+	sess, err := transcript.FindSession(e.homeDir, sessionIDOrPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", "", fmt.Errorf("%w: %s", ErrNotFound, sessionIDOrPath)
+		}
+		return "", "", err
+	}
+	return sess.Path, sess.ID, nil
+}
+
+// attribute は cwd の帰属プロジェクトを返します。未登録なら cwd 自体と registered=false を返します。
+func (e *Exporter) attribute(cwd string) (string, bool) {
+	if cwd == "" || e.projectsProvider == nil {
+		return cwd, false
+	}
+	projs, err := e.projectsProvider()
+	if err != nil {
+		return cwd, false
+	}
+	if p, ok := idle.MatchProject(cwd, projs); ok {
+		return p, true
+	}
+	return cwd, false
+}
+
+// buildTranscript はエントリ列を発言単位に整形し、tool_use と tool_result を突き合わせます。
+// isMeta（コマンド展開等の自動挿入）と thinking は共有物に不要なため載せません。
+func buildTranscript(sessionID string, entries []transcript.Entry) *Transcript {
+	t := &Transcript{SessionID: sessionID, Messages: make([]Message, 0)}
+	tools := make(map[string]*ToolCall)
+
+	for _, en := range entries {
+		if !en.Timestamp.IsZero() {
+			if t.StartedAt.IsZero() {
+				t.StartedAt = en.Timestamp
+			}
+			t.EndedAt = en.Timestamp
+		}
+		if t.Cwd == "" && en.Cwd != "" {
+			t.Cwd = en.Cwd
+		}
+		if en.Message == nil || en.IsMeta || (en.Type != "user" && en.Type != "assistant") {
+			continue
+		}
+
+		msg := Message{Role: en.Type, Timestamp: en.Timestamp, Sidechain: en.IsSidechain}
+		for _, b := range en.Message.Content {
+			switch b.Type {
+			case "text":
+				if strings.TrimSpace(b.Text) != "" {
+					msg.Blocks = append(msg.Blocks, Block{Text: b.Text})
+				}
+			case "tool_use":
+				tc := &ToolCall{ID: b.ID, Name: b.Name, Input: truncate(prettyJSON(b.Input))}
+				if b.ID != "" {
+					tools[b.ID] = tc
+				}
+				msg.Blocks = append(msg.Blocks, Block{Tool: tc})
+			case "tool_result":
+				// 結果は対応する tool_use のブロックに畳み込む（単独の発言にはしない）
+				if tc, ok := tools[b.ToolUseID]; ok {
+					tc.Result = truncate(b.ResultText())
+					tc.IsError = b.IsError
+				}
+			}
+		}
+		if len(msg.Blocks) == 0 {
+			continue
+		}
+
+		// 1メッセージが content 要素ごとに複数行へ分割記録されるため、同一ロールの連続は結合する
+		if n := len(t.Messages); n > 0 && t.Messages[n-1].Role == msg.Role && t.Messages[n-1].Sidechain == msg.Sidechain && msg.Role == "assistant" {
+			t.Messages[n-1].Blocks = append(t.Messages[n-1].Blocks, msg.Blocks...)
+			continue
+		}
+		t.Messages = append(t.Messages, msg)
+	}
+	return t
+}
+
+// findTaskFile は会話本文中の 開発/実装 配下のタスクファイル参照を探し、現在の所在から本文を読み込みます。
+// タスクファイルはレーン間を移動するため、参照時のレーンに無ければ同名ファイルを全レーンから探します。
+func findTaskFile(projectPath string, entries []transcript.Entry) *TaskFile {
+	if projectPath == "" {
+		return nil
+	}
+	for _, en := range entries {
+		if !en.IsPrompt() {
+			continue
+		}
+		for _, ref := range taskRefPattern.FindAllString(en.Text(), -1) {
+			name := filepath.Base(ref)
+			candidates := append([]string{filepath.FromSlash(ref)}, laneCandidates(name)...)
+			for _, rel := range candidates {
+				abs := filepath.Join(projectPath, rel)
+				if !strings.HasPrefix(abs, filepath.Join(projectPath, "開発", "実装")+string(os.PathSeparator)) {
+					continue
+				}
+				data, err := os.ReadFile(abs)
+				if err != nil {
+					continue
+				}
+				return &TaskFile{Path: rel, Content: string(data)}
+			}
+		}
+	}
+	return nil
+}
+
+// laneCandidates は name が各レーンにあった場合の相対パス一覧を返します。
+func laneCandidates(name string) []string {
+	out := make([]string, 0, len(taskLanes))
+	for _, lane := range taskLanes {
+		out = append(out, filepath.Join("開発", "実装", lane, name))
+	}
+	return out
+}
+
+// redactTranscript は Transcript の全テキストから秘匿値を伏せます。
+func redactTranscript(t *Transcript, r *Redactor) {
+	for i := range t.Messages {
+		for j := range t.Messages[i].Blocks {
+			b := &t.Messages[i].Blocks[j]
+			b.Text = r.Redact(b.Text)
+			if b.Tool != nil {
+				b.Tool.Input = r.Redact(b.Tool.Input)
+				b.Tool.Result = r.Redact(b.Tool.Result)
+			}
+		}
+	}
+	if t.TaskFile != nil {
+		t.TaskFile.Content = r.Redact(t.TaskFile.Content)
+	}
+}
+
+// prettyJSON は tool_use の input を読みやすく整形します。整形できなければそのまま返します。
+func prettyJSON(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, raw, "", "  "); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
+
+// truncate は s を maxToolOutputRunes 文字に切り詰めます。
+func truncate(s string) string {
+	r := []rune(s)
+	if len(r) <= maxToolOutputRunes {
+		return s
+	}
+	return string(r[:maxToolOutputRunes]) + fmt.Sprintf("\n… (%d文字省略)", len(r)-maxToolOutputRunes)
+}
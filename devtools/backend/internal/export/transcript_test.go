@@ -0,0 +1,156 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/projects"
+)
+
+func writeFixture(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func setupExport(t *testing.T) (*Exporter, string) {
+	t.Helper()
+	home := t.TempDir()
+	proj := filepath.Join(t.TempDir(), "shop")
+	writeFixture(t, filepath.Join(proj, ".env"), "STRIPE_SECRET=whsec_live_1234567890\n")
+	// 参照時は 実行中 だったタスクファイルが 完了 へ移動済み
+	writeFixture(t, filepath.Join(proj, "開発", "実装", "完了", "決済_plan.md"), "# 決済\n\n鍵は whsec_live_1234567890\n")
+
+	lines := []string{
+		`{"type":"user","cwd":"` + proj + `","timestamp":"2026-07-01T10:00:00Z","message":{"role":"user","content":"/coding @開発/実装/実行中/決済_plan.md"}}`,
+		`{"type":"user","isMeta":true,"timestamp":"2026-07-01T10:00:00Z","message":{"role":"user","content":"<command-expansion>"}}`,
+		`{"type":"assistant","timestamp":"2026-07-01T10:00:05Z","message":{"id":"m1","role":"assistant","content":[{"type":"thinking","thinking":"内緒"},{"type":"text","text":"確認します"}]}}`,
+		`{"type":"assistant","timestamp":"2026-07-01T10:00:06Z","message":{"id":"m1","role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"cat .env"}}]}}`,
+		`{"type":"user","timestamp":"2026-07-01T10:00:07Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","is_error":true,"content":"STRIPE_SECRET=whsec_live_1234567890 <script>"}]}}`,
+		`{"type":"assistant","timestamp":"2026-07-01T10:00:09Z","message":{"id":"m2","role":"assistant","content":[{"type":"text","text":"完了しました"}]}}`,
+	}
+	writeFixture(t, filepath.Join(home, ".claude", "projects", "-shop", "sess-1.jsonl"), strings.Join(lines, "\n")+"\n")
+
+	provider := func() ([]projects.Project, error) { return []projects.Project{{Name: "shop", Path: proj}}, nil }
+	now := func() time.Time { return time.Date(2026, 7, 2, 0, 0, 0, 0, time.UTC) }
+	return NewExporter(home, provider, now, nil, false), proj
+}
+
+func TestExporter_Build(t *testing.T) {
+	ex, proj := setupExport(t)
+
+	tr, err := ex.Build("sess-1")
+	if err != nil {
+		t.Fatalf("Build error: %v", err)
+	}
+	if tr.ProjectPath != proj || tr.SessionID != "sess-1" {
+		t.Errorf("project=%s session=%s", tr.ProjectPath, tr.SessionID)
+	}
+	// prompt / assistant（分割行を結合）/ assistant（tool_result は tool_use に畳み込み）
+	if len(tr.Messages) != 2 {
+		t.Fatalf("messages = %d, want 2: %+v", len(tr.Messages), tr.Messages)
+	}
+	asst := tr.Messages[1]
+	if len(asst.Blocks) != 3 || asst.Blocks[1].Tool == nil || !asst.Blocks[1].Tool.IsError {
+		t.Fatalf("assistant blocks = %+v", asst.Blocks)
+	}
+	if strings.Contains(asst.Blocks[1].Tool.Result, "whsec_live") {
+		t.Errorf(".env の値が伏せられていない: %q", asst.Blocks[1].Tool.Result)
+	}
+	if tr.TaskFile == nil || tr.TaskFile.Path != filepath.Join("開発", "実装", "完了", "決済_plan.md") {
+		t.Fatalf("TaskFile = %+v", tr.TaskFile)
+	}
+	if strings.Contains(tr.TaskFile.Content, "whsec_live") {
+		t.Error("タスクファイルの秘匿値が伏せられていない")
+	}
+
+	if _, err := ex.Build("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Build(missing) err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestExporter_Unregistered(t *testing.T) {
+	ex, _ := setupExport(t)
+	ex.projectsProvider = func() ([]projects.Project, error) { return nil, nil }
+
+	// API（allowUnregistered=false）では未登録プロジェクトのセッションは見つからない扱い
+	if _, err := ex.BuildSession("sess-1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("BuildSession(unregistered) err = %v, want ErrNotFound", err)
+	}
+
+	// CLI（allowUnregistered=true）はタスクファイルも含めてエクスポートする
+	ex.allowUnregistered = true
+	tr, err := ex.Build("sess-1")
+	if err != nil {
+		t.Fatalf("Build error: %v", err)
+	}
+	if tr.TaskFile == nil {
+		t.Error("CLI では未登録プロジェクトでもタスクファイルを埋め込む")
+	}
+}
+
+func TestExporter_BuildSession_RejectsNonIDs(t *testing.T) {
+	ex, _ := setupExport(t)
+	if _, err := ex.BuildSession("sess-1"); err != nil {
+		t.Fatalf("BuildSession error: %v", err)
+	}
+	for _, id := range []string{"*", "sess-?", "[s]ess-1", "sess-1.jsonl", "../sess-1", ".claude", ""} {
+		if _, err := ex.BuildSession(id); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("BuildSession(%q) err = %v, want ErrInvalidID", id, err)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	ex, _ := setupExport(t)
+	tr, err := ex.Build("sess-1")
+	if err != nil {
+		t.Fatalf("Build error: %v", err)
+	}
+
+	tests := []struct {
+		format   Format
+		contains []string
+		absent   []string
+	}{
+		{FormatMarkdown, []string{"# セッション sess-1", "<details>", "ツール: Bash（エラー）", "## タスクファイル"}, []string{"内緒", "whsec_live"}},
+		{FormatHTML, []string{"<!DOCTYPE html>", "<details class=\"error\">", "&lt;script&gt;"}, []string{"<script>", "内緒"}},
+		{FormatJSON, []string{`"sessionId": "sess-1"`}, []string{"whsec_live"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&buf, tt.format, tr); err != nil {
+				t.Fatalf("Render error: %v", err)
+			}
+			out := buf.String()
+			for _, s := range tt.contains {
+				if !strings.Contains(out, s) {
+					t.Errorf("出力に %q が含まれない", s)
+				}
+			}
+			for _, s := range tt.absent {
+				if strings.Contains(out, s) {
+					t.Errorf("出力に %q が含まれる", s)
+				}
+			}
+			if tt.format == FormatJSON && !json.Valid(buf.Bytes()) {
+				t.Error("JSON が不正")
+			}
+		})
+	}
+
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Error("ParseFormat(pdf) should fail")
+	}
+}
@@ -0,0 +1,28 @@
+// Package gitinfo はプロジェクトの git リポジトリ状態（ブランチ・未コミット変更・upstream との差分・
+// 最終コミット）を取得する共有インスペクタを提供する。
+//
+// # 概要
+//
+// ダッシュボード（dashboard.ProjectState.Git）と巡回（PatrolService の git log）が git を個別に
+// shell out していたのを本パッケージに集約する。ダッシュボードは2秒間隔でスキャンされるため、
+// Inspector は .git/index・HEAD・FETCH_HEAD の mtime をキーに結果をキャッシュし、変化が無い間は
+// git を起動しない。
+//
+// # 主要な型・関数
+//
+//   - Info: ブランチ / upstream / ahead・behind / 未コミット変更数 / 最終コミット / タスク開始以降の変更数
+//   - Inspector / NewInspector: キャッシュ付きの状態取得（Inspect）
+//   - RecentLog: git log --oneline -n の取得（巡回のスキャン結果用）
+//   - Arrivals / FileArrival: 期間内のコミットで指定パス配下に追加・移動してきたファイル（ダイジェストの完了タスク用）
+//   - ErrNotRepository: 対象ディレクトリが git リポジトリでない
+//
+// # 設計方針
+//
+//   - 状態取得は git --no-optional-locks status --porcelain=v2 --branch の1回で済ませ、
+//     ユーザーの git 操作と index.lock を取り合わない（optional lock を取らない）
+//   - キャッシュキーは .git/index・HEAD・FETCH_HEAD の mtime と「タスク開始時刻」。
+//     git add せずに編集したファイルは index を変えないため、cacheTTL で定期的に取り直す
+//   - 「タスク開始以降の変更数」は未コミット変更のうち mtime がタスク開始以降のファイル数。
+//     タスク開始時刻の決め方（gr-run が記録したクレーム時刻等）は呼び出し側が渡す
+//   - git が無い・リポジトリでない・コミットが無い場合も全体を失敗させず、取れた範囲で返す
+package gitinfo
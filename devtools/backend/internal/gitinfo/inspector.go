@@ -0,0 +1,254 @@
+package gitinfo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheTTL は .git 配下の mtime が変わらなくても結果を取り直す間隔です。
+// git add していない作業ツリーの編集は index を変えないため、未コミット変更数を追従させる目的です。
+const cacheTTL = 30 * time.Second
+
+// commandTimeout は git コマンド1回あたりのタイムアウトです。
+const commandTimeout = 5 * time.Second
+
+// ErrNotRepository は対象ディレクトリが git リポジトリでない場合のエラーです。
+var ErrNotRepository = errors.New("not a git repository")
+
+// Info は1リポジトリの git 状態です。
+type Info struct {
+	Branch string `json:"branch"`
+	// Detached は HEAD がブランチを指していない（detached HEAD）かを表します。
+	Detached bool   `json:"detached,omitempty"`
+	Upstream string `json:"upstream,omitempty"`
+	Ahead    int    `json:"ahead"`
+	Behind   int    `json:"behind"`
+	// Dirty は未コミット変更（staged / unstaged / untracked / conflict）のファイル数です。
+	Dirty             int    `json:"dirty"`
+	LastCommitAt      string `json:"lastCommitAt,omitempty"` // RFC3339
+	LastCommitAuthor  string `json:"lastCommitAuthor,omitempty"`
+	LastCommitSubject string `json:"lastCommitSubject,omitempty"`
+	// TaskStartedAt は呼び出し側が渡したタスク開始時刻です（RFC3339。タスクが無ければ空）。
+	TaskStartedAt string `json:"taskStartedAt,omitempty"`
+	// ChangedSinceTaskStart は未コミット変更のうち mtime が TaskStartedAt 以降のファイル数です。
+	ChangedSinceTaskStart int `json:"changedSinceTaskStart,omitempty"`
+}
+
+// Inspector は git 状態をキャッシュ付きで取得します。
+type Inspector interface {
+	// Inspect は dir の git 状態を返します。taskStart がゼロ値でなければ、それ以降に変更された
+	// 未コミットファイル数も数えます。dir がリポジトリでない場合は ErrNotRepository を返します。
+	Inspect(ctx context.Context, dir string, taskStart time.Time) (*Info, error)
+}
+
+// cacheKey はキャッシュの有効性を判定する .git 配下の mtime の組です。
+type cacheKey struct {
+	index     time.Time
+	head      time.Time
+	fetchHead time.Time
+	taskStart time.Time
+}
+
+type cacheEntry struct {
+	key       cacheKey
+	info      Info
+	fetchedAt time.Time
+}
+
+type inspectorImpl struct {
+	now func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry // key: filepath.Clean(dir)
+}
+
+// NewInspector は Inspector を生成します。now が nil の場合は time.Now を使います。
+func NewInspector(now func() time.Time) Inspector {
+	if now == nil {
+		now = time.Now
+	}
+	return &inspectorImpl{now: now, cache: make(map[string]cacheEntry)}
+}
+
+// Inspect は dir の git 状態を返します。.git 配下の mtime と taskStart が前回と同じで、
+// 取得から cacheTTL 以内であれば git を起動せずキャッシュを返します。
+func (s *inspectorImpl) Inspect(ctx context.Context, dir string, taskStart time.Time) (*Info, error) {
+	dir = filepath.Clean(dir)
+	gitDir, err := resolveGitDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	key := cacheKey{
+		index:     modTime(filepath.Join(gitDir, "index")),
+		head:      modTime(filepath.Join(gitDir, "HEAD")),
+		fetchHead: modTime(filepath.Join(gitDir, "FETCH_HEAD")),
+		taskStart: taskStart,
+	}
+	now := s.now()
+
+	s.mu.Lock()
+	if e, ok := s.cache[dir]; ok && e.key == key && now.Sub(e.fetchedAt) < cacheTTL {
+		s.mu.Unlock()
+		info := e.info
+		return &info, nil
+	}
+	s.mu.Unlock()
+
+	// git の起動（IO）はロック外で行う
+	info, err := inspect(ctx, dir, taskStart)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[dir] = cacheEntry{key: key, info: *info, fetchedAt: now}
+	s.mu.Unlock()
+	return info, nil
+}
+
+// inspect は git を実行して Info を組み立てます。
+func inspect(ctx context.Context, dir string, taskStart time.Time) (*Info, error) {
+	// 日本語などの非 ASCII パスを C 形式で引用させない（引用されると os.Stat で見つからず数え漏れる）
+	out, err := runGit(ctx, dir, "--no-optional-locks", "-c", "core.quotePath=false", "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return nil, err
+	}
+	info, paths := parseStatus(out)
+
+	if !taskStart.IsZero() {
+		info.TaskStartedAt = taskStart.Format(time.RFC3339)
+		for _, p := range paths {
+			if fi, err := os.Stat(filepath.Join(dir, p)); err == nil && !fi.ModTime().Before(taskStart) {
+				info.ChangedSinceTaskStart++
+			}
+		}
+	}
+
+	// コミットが1つも無いリポジトリでは失敗するため、最終コミット情報は取れた場合のみ載せる
+	if logOut, err := runGit(ctx, dir, "log", "-1", "--format=%cI%x00%an%x00%s"); err == nil {
+		parts := strings.SplitN(strings.TrimRight(string(logOut), "\n"), "\x00", 3)
+		if len(parts) == 3 {
+			info.LastCommitAt, info.LastCommitAuthor, info.LastCommitSubject = parts[0], parts[1], parts[2]
+		}
+	}
+	return &info, nil
+}
+
+// parseStatus は git status --porcelain=v2 --branch の出力を解釈し、Info と変更ファイルのパス一覧を返します。
+func parseStatus(out []byte) (Info, []string) {
+	var info Info
+	var paths []string
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "# branch.head "):
+			info.Branch = strings.TrimPrefix(line, "# branch.head ")
+			if info.Branch == "(detached)" {
+				info.Detached = true
+			}
+		case strings.HasPrefix(line, "# branch.upstream "):
+			info.Upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			fields := strings.Fields(strings.TrimPrefix(line, "# branch.ab "))
+			if len(fields) == 2 {
+				info.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[0], "+"))
+				info.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[1], "-"))
+			}
+		case strings.HasPrefix(line, "1 "):
+			// 1 XY sub mH mI mW hH hI path
+			if f := strings.SplitN(line, " ", 9); len(f) == 9 {
+				info.Dirty++
+				paths = append(paths, f[8])
+			}
+		case strings.HasPrefix(line, "2 "):
+			// 2 XY sub mH mI mW hH hI Xscore path<TAB>origPath
+			if f := strings.SplitN(line, " ", 10); len(f) == 10 {
+				info.Dirty++
+				path, _, _ := strings.Cut(f[9], "\t")
+				paths = append(paths, path)
+			}
+		case strings.HasPrefix(line, "u "):
+			// u XY sub m1 m2 m3 mW h1 h2 h3 path
+			if f := strings.SplitN(line, " ", 11); len(f) == 11 {
+				info.Dirty++
+				paths = append(paths, f[10])
+			}
+		case strings.HasPrefix(line, "? "):
+			info.Dirty++
+			paths = append(paths, strings.TrimPrefix(line, "? "))
+		}
+	}
+	return info, paths
+}
+
+// RecentLog は dir の git log --oneline -n を返します。
+func RecentLog(ctx context.Context, dir string, n int) (string, error) {
+	out, err := runGit(ctx, dir, "log", "--oneline", fmt.Sprintf("-%d", n))
+	if err != nil {
+		return "", fmt.Errorf("failed to get git log: %w", err)
+	}
+	return string(out), nil
+}
+
+// runGit は dir で git を実行し標準出力を返します。
+func runGit(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if strings.Contains(stderr.String(), "not a git repository") {
+			return nil, fmt.Errorf("%w: %s", ErrNotRepository, dir)
+		}
+		return nil, fmt.Errorf("failed to run git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// resolveGitDir は dir 直下の .git（ディレクトリ、または worktree の "gitdir: <path>" ファイル）を解決します。
+// dir 直下に .git が無い場合は ErrNotRepository を返します（サブディレクトリからの親探索はしません）。
+func resolveGitDir(dir string) (string, error) {
+	p := filepath.Join(dir, ".git")
+	fi, err := os.Stat(p)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrNotRepository, dir)
+	}
+	if fi.IsDir() {
+		return p, nil
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", p, err)
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotRepository, dir)
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+	return gitDir, nil
+}
+
+// modTime は path の mtime を返します（存在しなければゼロ値）。
+func modTime(path string) time.Time {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return fi.ModTime()
+}
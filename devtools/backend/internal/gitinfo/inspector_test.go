@@ -0,0 +1,166 @@
+package gitinfo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseStatus(t *testing.T) {
+	out := []byte(`# branch.oid 0123456789abcdef
+# branch.head feature/x
+# branch.upstream origin/feature/x
+# branch.ab +2 -1
+1 .M N... 100644 100644 100644 aaa bbb src/main.go
+1 A. N... 000000 100644 100644 000 ccc docs/new file.md
+2 R. N... 100644 100644 100644 ddd ddd R100 renamed.go	old.go
+u UU N... 100644 100644 100644 100644 e f g conflict.go
+? untracked.txt
+`)
+	info, paths := parseStatus(out)
+
+	if info.Branch != "feature/x" || info.Detached {
+		t.Errorf("Branch = %q (detached=%v), want feature/x", info.Branch, info.Detached)
+	}
+	if info.Upstream != "origin/feature/x" || info.Ahead != 2 || info.Behind != 1 {
+		t.Errorf("upstream = %q +%d -%d, want origin/feature/x +2 -1", info.Upstream, info.Ahead, info.Behind)
+	}
+	if info.Dirty != 5 {
+		t.Errorf("Dirty = %d, want 5", info.Dirty)
+	}
+	want := []string{"src/main.go", "docs/new file.md", "renamed.go", "conflict.go", "untracked.txt"}
+	if len(paths) != len(want) {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("paths[%d] = %q, want %q", i, paths[i], want[i])
+		}
+	}
+}
+
+func TestParseStatus_Detached(t *testing.T) {
+	info, _ := parseStatus([]byte("# branch.oid abc\n# branch.head (detached)\n"))
+	if !info.Detached {
+		t.Error("expected detached")
+	}
+	if info.Upstream != "" || info.Ahead != 0 {
+		t.Errorf("unexpected upstream info: %+v", info)
+	}
+}
+
+// initRepo は1コミットを持つ git リポジトリを作成します（git が無ければ skip）。
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=tester", "-c", "user.email=t@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	run("init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", "a.txt")
+	run("commit", "-q", "-m", "first commit")
+	return dir
+}
+
+func TestInspect_RepositoryAndCache(t *testing.T) {
+	dir := initRepo(t)
+	now := time.Now()
+	clock := now
+	insp := NewInspector(func() time.Time { return clock })
+
+	taskStart := now.Add(-time.Hour)
+	info, err := insp.Inspect(context.Background(), dir, taskStart)
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if info.Branch != "main" || info.Dirty != 0 {
+		t.Errorf("info = %+v, want clean main", info)
+	}
+	if info.LastCommitSubject != "first commit" || info.LastCommitAuthor != "tester" || info.LastCommitAt == "" {
+		t.Errorf("last commit = %q/%q/%q", info.LastCommitSubject, info.LastCommitAuthor, info.LastCommitAt)
+	}
+
+	// index を変えない編集（untracked 追加）は TTL 内ならキャッシュを返す
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cached, err := insp.Inspect(context.Background(), dir, taskStart)
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if cached.Dirty != 0 {
+		t.Errorf("Dirty = %d, want cached 0", cached.Dirty)
+	}
+
+	// TTL 経過後は取り直し、タスク開始以降の変更も数える
+	clock = now.Add(cacheTTL)
+	fresh, err := insp.Inspect(context.Background(), dir, taskStart)
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if fresh.Dirty != 1 || fresh.ChangedSinceTaskStart != 1 {
+		t.Errorf("Dirty/ChangedSinceTaskStart = %d/%d, want 1/1", fresh.Dirty, fresh.ChangedSinceTaskStart)
+	}
+
+	// タスク開始がファイル更新より後なら数えない（taskStart が変わればキャッシュキーも変わる）
+	later, err := insp.Inspect(context.Background(), dir, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if later.ChangedSinceTaskStart != 0 {
+		t.Errorf("ChangedSinceTaskStart = %d, want 0", later.ChangedSinceTaskStart)
+	}
+}
+
+// TestInspect_NonASCIIPath は日本語のパスもタスク開始以降の変更として数えることを確認します
+func TestInspect_NonASCIIPath(t *testing.T) {
+	dir := initRepo(t)
+	if err := os.WriteFile(filepath.Join(dir, "議事録.md"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("変更"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := NewInspector(nil).Inspect(context.Background(), dir, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if info.Dirty != 2 || info.ChangedSinceTaskStart != 2 {
+		t.Errorf("Dirty/ChangedSinceTaskStart = %d/%d, want 2/2", info.Dirty, info.ChangedSinceTaskStart)
+	}
+}
+
+func TestInspect_NotRepository(t *testing.T) {
+	insp := NewInspector(nil)
+	_, err := insp.Inspect(context.Background(), t.TempDir(), time.Time{})
+	if !errors.Is(err, ErrNotRepository) {
+		t.Errorf("err = %v, want ErrNotRepository", err)
+	}
+}
+
+func TestRecentLog(t *testing.T) {
+	dir := initRepo(t)
+	out, err := RecentLog(context.Background(), dir, 5)
+	if err != nil {
+		t.Fatalf("RecentLog: %v", err)
+	}
+	if !strings.Contains(out, "first commit") {
+		t.Errorf("log = %q, want first commit", out)
+	}
+}
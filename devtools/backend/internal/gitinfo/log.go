@@ -0,0 +1,72 @@
+package gitinfo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FileArrival は期間内のコミットで追加された、または移動してきたファイル1件です。
+type FileArrival struct {
+	// Path はリポジトリルートからの相対パスです。
+	Path string `json:"path"`
+	// From は移動元のパスです（新規追加なら空）。
+	From string `json:"from,omitempty"`
+	// Commit はコミットのハッシュです。
+	Commit string `json:"commit"`
+	// At はコミット日時です。
+	At time.Time `json:"at"`
+}
+
+// Arrivals は [from, to) のコミットで pathspec 配下に追加・移動（rename 検出）されたファイルを新しい順に返します。
+// 同じパスが複数回現れた場合は最新のものだけを返します。dir がリポジトリでない場合は ErrNotRepository を返します。
+func Arrivals(ctx context.Context, dir, pathspec string, from, to time.Time) ([]FileArrival, error) {
+	out, err := runGit(ctx, dir, "-c", "core.quotePath=false", "log",
+		"--since="+from.Format(time.RFC3339), "--until="+to.Format(time.RFC3339),
+		"--diff-filter=AR", "-M", "--name-status", "--format=%x1e%H%x1f%cI", "--", pathspec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git log: %w", err)
+	}
+	return parseArrivals(string(out), from, to), nil
+}
+
+// parseArrivals は git log --name-status（%x1e%H%x1f%cI 形式）の出力を解析します。
+// --since / --until はコミット日時の粗い絞り込みのため、[from, to) の外のコミットはここで除外します。
+func parseArrivals(out string, from, to time.Time) []FileArrival {
+	var arrivals []FileArrival
+	seen := make(map[string]bool)
+	for _, rec := range strings.Split(out, "\x1e") {
+		header, body, ok := strings.Cut(rec, "\n")
+		if !ok {
+			continue
+		}
+		hash, date, ok := strings.Cut(strings.TrimSpace(header), "\x1f")
+		if !ok {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, date)
+		if err != nil || at.Before(from) || !at.Before(to) {
+			continue
+		}
+		for _, line := range strings.Split(body, "\n") {
+			fields := strings.Split(line, "\t")
+			var a FileArrival
+			switch {
+			case len(fields) == 2 && fields[0] == "A":
+				a = FileArrival{Path: fields[1]}
+			case len(fields) == 3 && strings.HasPrefix(fields[0], "R"):
+				a = FileArrival{Path: fields[2], From: fields[1]}
+			default:
+				continue
+			}
+			if seen[a.Path] {
+				continue
+			}
+			seen[a.Path] = true
+			a.Commit, a.At = hash, at
+			arrivals = append(arrivals, a)
+		}
+	}
+	return arrivals
+}
@@ -0,0 +1,27 @@
+package gitinfo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseArrivals(t *testing.T) {
+	from := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	out := "\x1ec3\x1f2026-10-18T20:00:00Z\n\n" +
+		"R092\t開発/実装/実行中/b.md\t開発/実装/完了/b.md\n" +
+		"\x1ec2\x1f2026-10-18T10:00:00+09:00\n\n" +
+		"A\t開発/実装/完了/a.md\nR100\t開発/実装/実行中/b.md\t開発/実装/完了/b.md\n" +
+		"\x1ec1\x1f2026-10-17T23:00:00Z\n\nA\t開発/実装/完了/old.md\n"
+
+	got := parseArrivals(out, from, to)
+	if len(got) != 2 {
+		t.Fatalf("got %+v, want b.md (latest) and a.md", got)
+	}
+	if got[0].Path != "開発/実装/完了/b.md" || got[0].From != "開発/実装/実行中/b.md" || got[0].Commit != "c3" {
+		t.Errorf("got[0] = %+v", got[0])
+	}
+	if got[1].Path != "開発/実装/完了/a.md" || got[1].From != "" || !got[1].At.Equal(time.Date(2026, 10, 18, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("got[1] = %+v", got[1])
+	}
+}
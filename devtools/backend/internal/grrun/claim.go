@@ -1,15 +1,15 @@
 package grrun
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
-	"regexp"
-)
 
-// unansweredRe は確認事項の未回答パターンを検出する正規表現です
-var unansweredRe = regexp.MustCompile(UnansweredPattern)
+	"ghostrunner/backend/internal/plandoc"
+)
 
 // ClaimTask はタスクファイルを実装待ちから実行中へ移動します。
 // gitコミットは行いません（/coding ブランチとの干渉を避けるため）。
@@ -60,14 +60,14 @@ func ClassifyResult(projectPath, taskFile string, exitCode int) Outcome {
 // hasUnansweredQuestion はプランファイルに未回答の確認事項があるかを判定します。
 // ファイルが存在しない場合はfalseを返します（前方互換性のため）。
 func hasUnansweredQuestion(planPath string) bool {
-	data, err := os.ReadFile(planPath)
+	doc, err := plandoc.ParseFile(planPath)
 	if err != nil {
-		if !os.IsNotExist(err) {
+		if !errors.Is(err, fs.ErrNotExist) {
 			log.Printf("[gr-run] failed to read plan file %s: %v", planPath, err)
 		}
 		return false
 	}
-	return unansweredRe.Match(data)
+	return doc.HasUnanswered()
 }
 
 // fileExists はファイルが存在するかを返します
@@ -9,11 +9,31 @@
 // send a notification. Each gr-run process handles exactly one task
 // and then exits, making it safe to launch multiple instances in parallel.
 //
+// Every Claude invocation is started with an explicit session ID and
+// appended to a per-project run history. When a run ends in
+// waiting_answer, the task stays in the running directory; once all of
+// its questions are answered, [Runner.Resume] (gr-run --resume) or the
+// server-side [Resumer] continues the recorded session with --resume.
+//
 // # Key Components
 //
 //   - [Config]: holds runtime parameters (project path, task file name,
-//     locks directory).
-//   - [Runner]: orchestrates the full pipeline via [Runner.Run].
+//     locks directory, runs directory, trigger).
+//   - [Runner]: orchestrates the full pipeline via [Runner.Run], and
+//     continues an answered waiting_answer task via [Runner.Resume]
+//     (returns not_resumable when the preconditions do not hold).
+//   - [RunRecord] / [AppendRunRecord] / [LoadRunRecords] / [LastRunForTask]:
+//     run history as JSONL, one file per project under RunsDir, named
+//     like the lock file with a .jsonl extension.
+//   - [RecordClaim] / [TaskClaimedAt]: when each task was moved to the
+//     running directory, kept in a .claims.json index next to the run
+//     history. The dashboard uses it as the task start time, because the
+//     running directory's own mtime changes whenever a file in it does.
+//   - [Resumer]: opt-in background job (features.autoResume in the
+//     server config, which also supplies [ResumerConfig]) that scans the
+//     running directory of registered projects and launches
+//     [Runner.Resume] for tasks whose last run was waiting_answer and
+//     whose plan no longer has unanswered questions.
 //   - [AcquireLock]: obtains a per-project exclusive lock using flock(2)
 //     with LOCK_NB so that concurrent invocations on the same project
 //     fail fast instead of blocking.
@@ -21,7 +41,8 @@
 //     running directory using os.Rename for atomic claim.
 //   - [ClassifyResult]: inspects the working tree after Claude finishes
 //     and returns an [Outcome] value (completed, waiting_answer,
-//     abnormal, needs_check, or lock_busy).
+//     abnormal, needs_check, or lock_busy). Unanswered questions in the
+//     plan are detected through the plandoc package's plan model.
 //   - [CommandExecutor]: function type that abstracts Claude CLI
 //     invocation, allowing test doubles to be injected.
 //
@@ -35,6 +56,17 @@
 //   - [CommandExecutor] is a function type rather than an interface to
 //     keep the abstraction lightweight; tests supply a closure that
 //     records calls and returns a predetermined exit code.
-//   - [Notifier] mirrors service.NtfyService signatures without importing
-//     the service package, keeping the dependency graph shallow.
+//   - The session ID is chosen by gr-run (--session-id) rather than read
+//     back from Claude output, so it is known before the run starts and
+//     can be recorded even when the process crashes.
+//   - [Resumer] only resumes tasks that gr-run itself left in
+//     waiting_answer, and only after the plan was modified after that run
+//     and has been quiet for SettleDelay, so that answering several
+//     questions one by one does not trigger a resume per answer.
+//     MaxAttempts bounds the number of runs per task. It does not check
+//     the lock itself; a concurrent run makes Resume return lock_busy.
+//   - [Notifier] is an alias of notify.Notifier (the same interface as
+//     service.NtfyService), so grrun depends only on the leaf notify
+//     package. Notifications carry the project path and the run.finished /
+//     run.error event for per-project routing.
 package grrun
@@ -0,0 +1,199 @@
+package grrun
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RunRecord は実行履歴の1件（gr-run の1回の Claude 実行）です
+type RunRecord struct {
+	Project  string `json:"project"`
+	TaskFile string `json:"taskFile"`
+	// SessionID は Claude の会話セッションID（再開時は元のセッションIDを引き継ぐ）
+	SessionID string `json:"sessionId,omitempty"`
+	// Resumed は確認事項への回答後に元のセッションを再開した実行かを示します
+	Resumed bool `json:"resumed"`
+	// Attempt は同じタスクの何回目の実行か（初回は1、再開ごとに加算）
+	Attempt   int       `json:"attempt"`
+	Trigger   string    `json:"trigger"`
+	StartedAt time.Time `json:"startedAt"`
+	EndedAt   time.Time `json:"endedAt"`
+	ExitCode  int       `json:"exitCode"`
+	Outcome   Outcome   `json:"outcome"`
+}
+
+// runsFile はプロジェクトの実行履歴ファイルのパスを返します（ロックファイルと同じ命名で拡張子のみ .jsonl）
+func runsFile(runsDir, projectPath string) string {
+	return filepath.Join(runsDir, strings.TrimSuffix(lockKey(projectPath), ".lock")+".jsonl")
+}
+
+// claimsFile はプロジェクトのクレーム時刻の索引ファイルのパスを返します（実行履歴と同じ命名で拡張子のみ .claims.json）
+func claimsFile(runsDir, projectPath string) string {
+	return filepath.Join(runsDir, strings.TrimSuffix(lockKey(projectPath), ".lock")+".claims.json")
+}
+
+// RecordClaim はタスクを 実行中 へ移動した時刻を索引に記録します（タスクファイル名 → 時刻）。
+// 実行中 に残っていないタスクの記録は同時に削除します。索引は temp + rename で書き換えるため、
+// 読み手（ダッシュボード）は書き込み途中を見ません。呼び出し元がプロジェクトのロックを保持している前提です。
+func RecordClaim(runsDir, projectPath, taskFile string, at time.Time) error {
+	if err := os.MkdirAll(runsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create runs directory %s: %w", runsDir, err)
+	}
+	claims, err := loadClaims(runsDir, projectPath)
+	if err != nil {
+		return err
+	}
+	for name := range claims {
+		if !fileExists(filepath.Join(projectPath, RelRunning, name)) {
+			delete(claims, name)
+		}
+	}
+	claims[taskFile] = at
+
+	data, err := json.MarshalIndent(claims, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal task claims: %w", err)
+	}
+	path := claimsFile(runsDir, projectPath)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write task claims %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to rename task claims %s: %w", path, err)
+	}
+	return nil
+}
+
+// TaskClaimedAt はタスクを 実行中 へ移動した時刻を返します。
+// 索引に無い場合（索引導入前にクレームしたタスク）は、実行履歴の最後の初回実行（Attempt=1）の開始時刻で代用します。
+// どちらにも無ければ ok=false です。
+func TaskClaimedAt(runsDir, projectPath, taskFile string) (at time.Time, ok bool, err error) {
+	claims, err := loadClaims(runsDir, projectPath)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if at, ok := claims[taskFile]; ok {
+		return at, true, nil
+	}
+	records, err := LoadRunRecords(runsDir, projectPath)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].TaskFile == taskFile && records[i].Attempt == 1 {
+			return records[i].StartedAt, true, nil
+		}
+	}
+	return time.Time{}, false, nil
+}
+
+// loadClaims はクレーム時刻の索引を読みます。索引が無い場合は空を返します
+func loadClaims(runsDir, projectPath string) (map[string]time.Time, error) {
+	path := claimsFile(runsDir, projectPath)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return make(map[string]time.Time), nil
+		}
+		return nil, fmt.Errorf("failed to read task claims %s: %w", path, err)
+	}
+	claims := make(map[string]time.Time)
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return nil, fmt.Errorf("failed to parse task claims %s: %w", path, err)
+	}
+	return claims, nil
+}
+
+// AppendRunRecord は実行履歴に1件追記します。
+// 呼び出し元がプロジェクトのロックを保持している前提のため、ファイルロックは取りません。
+func AppendRunRecord(runsDir string, rec RunRecord) error {
+	if err := os.MkdirAll(runsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create runs directory %s: %w", runsDir, err)
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal run record: %w", err)
+	}
+
+	path := runsFile(runsDir, rec.Project)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open run history %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write run history %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadRunRecords はプロジェクトの実行履歴を古い順に返します。
+// 履歴ファイルが無い場合は空を返し、解析できない行は読み飛ばします。
+func LoadRunRecords(runsDir, projectPath string) ([]RunRecord, error) {
+	path := runsFile(runsDir, projectPath)
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open run history %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var records []RunRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var rec RunRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			continue
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read run history %s: %w", path, err)
+	}
+	return records, nil
+}
+
+// LastRunForTask はタスクの最後の実行記録を返します（記録が無ければ ok=false）
+func LastRunForTask(runsDir, projectPath, taskFile string) (rec RunRecord, ok bool, err error) {
+	records, err := LoadRunRecords(runsDir, projectPath)
+	if err != nil {
+		return RunRecord{}, false, err
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].TaskFile == taskFile {
+			return records[i], true, nil
+		}
+	}
+	return RunRecord{}, false, nil
+}
+
+// newSessionID は Claude の --session-id に渡す UUID（v4）を生成します
+func newSessionID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand は Linux/macOS では失敗しない。万一の場合はセッションIDなしで実行する
+		return ""
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
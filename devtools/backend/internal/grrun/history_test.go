@@ -0,0 +1,141 @@
+package grrun
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestRunHistory_AppendAndLoad(t *testing.T) {
+	runsDir := t.TempDir()
+	proj := "/tmp/some/project"
+	started := time.Date(2026, 7, 20, 10, 0, 0, 0, time.UTC)
+
+	records := []RunRecord{
+		{Project: proj, TaskFile: "A.md", SessionID: "s-a", Attempt: 1, Outcome: OutcomeWaitingAnswer, StartedAt: started},
+		{Project: proj, TaskFile: "B.md", SessionID: "s-b", Attempt: 1, Outcome: OutcomeCompleted, StartedAt: started},
+		{Project: proj, TaskFile: "A.md", SessionID: "s-a", Resumed: true, Attempt: 2, Outcome: OutcomeCompleted, StartedAt: started},
+	}
+	for _, rec := range records {
+		if err := AppendRunRecord(runsDir, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 他プロジェクトの履歴は別ファイル
+	if err := AppendRunRecord(runsDir, RunRecord{Project: "/tmp/other/project", TaskFile: "A.md", Attempt: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadRunRecords(runsDir, proj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 3 {
+		t.Fatalf("loaded %d records, want 3", len(loaded))
+	}
+	if !loaded[0].StartedAt.Equal(started) {
+		t.Errorf("StartedAt = %v, want %v", loaded[0].StartedAt, started)
+	}
+
+	last, ok, err := LastRunForTask(runsDir, proj, "A.md")
+	if err != nil || !ok {
+		t.Fatalf("LastRunForTask: ok=%v, err=%v", ok, err)
+	}
+	if !last.Resumed || last.Attempt != 2 {
+		t.Errorf("last = %+v, want resumed attempt 2", last)
+	}
+
+	if _, ok, _ := LastRunForTask(runsDir, proj, "C.md"); ok {
+		t.Error("expected no record for C.md")
+	}
+}
+
+func TestLoadRunRecords_MissingAndBrokenLines(t *testing.T) {
+	runsDir := t.TempDir()
+	proj := "/tmp/some/project"
+
+	records, err := LoadRunRecords(runsDir, proj)
+	if err != nil || len(records) != 0 {
+		t.Fatalf("missing file: records=%v, err=%v", records, err)
+	}
+
+	content := "{broken\n\n" + `{"project":"/tmp/some/project","taskFile":"A.md","attempt":1,"outcome":"completed"}` + "\n"
+	if err := os.WriteFile(runsFile(runsDir, proj), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	records, err = LoadRunRecords(runsDir, proj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Outcome != OutcomeCompleted {
+		t.Errorf("records = %+v, want 1 completed record", records)
+	}
+}
+
+func TestTaskClaimedAt(t *testing.T) {
+	runsDir := t.TempDir()
+	proj := t.TempDir()
+	runningDir := filepath.Join(proj, RelRunning)
+	if err := os.MkdirAll(runningDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"A.md", "B.md"} {
+		if err := os.WriteFile(filepath.Join(runningDir, name), []byte("# task\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	claimedA := time.Date(2026, 7, 20, 9, 0, 0, 0, time.UTC)
+	if err := RecordClaim(runsDir, proj, "A.md", claimedA); err != nil {
+		t.Fatal(err)
+	}
+
+	at, ok, err := TaskClaimedAt(runsDir, proj, "A.md")
+	if err != nil || !ok || !at.Equal(claimedA) {
+		t.Errorf("A.md claimed at %v (ok=%v, err=%v), want %v", at, ok, err, claimedA)
+	}
+
+	// 索引に無いタスクは最後の初回実行の開始時刻で代用する
+	started := time.Date(2026, 7, 19, 8, 0, 0, 0, time.UTC)
+	for _, rec := range []RunRecord{
+		{Project: proj, TaskFile: "B.md", Attempt: 1, StartedAt: started},
+		{Project: proj, TaskFile: "B.md", Attempt: 2, Resumed: true, StartedAt: started.Add(time.Hour)},
+	} {
+		if err := AppendRunRecord(runsDir, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if at, ok, err := TaskClaimedAt(runsDir, proj, "B.md"); err != nil || !ok || !at.Equal(started) {
+		t.Errorf("B.md claimed at %v (ok=%v, err=%v), want first attempt %v", at, ok, err, started)
+	}
+	if _, ok, err := TaskClaimedAt(runsDir, proj, "C.md"); err != nil || ok {
+		t.Errorf("C.md: ok=%v, err=%v, want not found", ok, err)
+	}
+
+	// 実行中 から出たタスクの記録は次のクレームで削除する
+	if err := os.Remove(filepath.Join(runningDir, "A.md")); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordClaim(runsDir, proj, "B.md", claimedA.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	claims, err := loadClaims(runsDir, proj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := claims["A.md"]; ok || len(claims) != 1 {
+		t.Errorf("claims = %v, want only B.md", claims)
+	}
+}
+
+func TestNewSessionID(t *testing.T) {
+	uuidRe := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	a, b := newSessionID(), newSessionID()
+	if !uuidRe.MatchString(a) {
+		t.Errorf("session id %q is not a v4 UUID", a)
+	}
+	if a == b {
+		t.Error("session ids should differ")
+	}
+}
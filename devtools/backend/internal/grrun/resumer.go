@@ -0,0 +1,209 @@
+package grrun
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"ghostrunner/backend/internal/projects"
+)
+
+const (
+	// defaultResumeInterval は自動再開の走査間隔です
+	defaultResumeInterval = time.Minute
+	// defaultResumeMaxAttempts は1タスクあたりの実行回数の上限です（初回実行を含む）
+	defaultResumeMaxAttempts = 5
+)
+
+// ResumerConfig は自動再開の設定です
+type ResumerConfig struct {
+	// Enabled は自動再開を行うか（サーバー設定の features.autoResume。既定は無効）
+	Enabled bool
+	// Interval は 実行中 の走査間隔
+	Interval time.Duration
+	// SettleDelay は計画書の最終更新から再開までに待つ時間（0 は待たない）
+	SettleDelay time.Duration
+	// MaxAttempts は1タスクあたりの実行回数の上限（初回実行を含む）。到達したタスクは再開しない
+	MaxAttempts int
+	// LocksDir / RunsDir は gr-run と共有するロック・実行履歴のディレクトリ
+	LocksDir string
+	RunsDir  string
+}
+
+// Launcher は再開実行を起動する関数型です。テスト時に差し替え可能にするために型定義しています。
+type Launcher func(ctx context.Context, cfg Config) RunResult
+
+// DefaultLauncher は Runner.Resume で Claude CLI を実行する Launcher を返します。notifier は nil 許容です。
+func DefaultLauncher(notifier Notifier) Launcher {
+	return func(ctx context.Context, cfg Config) RunResult {
+		return NewRunner(cfg, notifier, DefaultExecutor()).Resume(ctx)
+	}
+}
+
+// Resumer は 実行中 のタスクのうち、前回の gr-run が回答待ち（waiting_answer）で終わり、
+// その後すべての確認事項に回答されたものを検出して再開するバックグラウンドジョブです。
+type Resumer struct {
+	cfg      ResumerConfig
+	projects func() ([]projects.Project, error)
+	launch   Launcher
+	now      func() time.Time
+
+	mu       sync.Mutex
+	inFlight map[string]bool // key: プロジェクトパス + タスク名
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewResumer は新しいResumerを生成します。now が nil の場合は time.Now を使います。
+func NewResumer(cfg ResumerConfig, projectsProvider func() ([]projects.Project, error), launch Launcher, now func() time.Time) *Resumer {
+	if now == nil {
+		now = time.Now
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultResumeInterval
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultResumeMaxAttempts
+	}
+	return &Resumer{
+		cfg:      cfg,
+		projects: projectsProvider,
+		launch:   launch,
+		now:      now,
+		inFlight: make(map[string]bool),
+	}
+}
+
+// Start は自動再開の走査を開始します。Enabled でない場合は何もしません。
+// ctx のキャンセルまたは Stop で終了します。
+func (r *Resumer) Start(ctx context.Context) {
+	if !r.cfg.Enabled {
+		log.Printf("[Resumer] disabled (features.autoResume is off)")
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.cfg.Interval)
+		defer ticker.Stop()
+
+		log.Printf("[Resumer] started: interval=%s, settle=%s, maxAttempts=%d", r.cfg.Interval, r.cfg.SettleDelay, r.cfg.MaxAttempts)
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("[Resumer] stopped")
+				return
+			case <-ticker.C:
+				r.Check(ctx)
+			}
+		}
+	}()
+}
+
+// Stop は走査を停止し、起動済みの再開実行が終わるまで待機します（ctx のキャンセルで Claude も終了します）
+func (r *Resumer) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+}
+
+// Check は登録プロジェクトの 実行中 を1回走査し、再開できるタスクの実行を起動します。
+// 起動した実行は非同期で進み、同じタスクは実行が終わるまで再度起動しません。
+func (r *Resumer) Check(ctx context.Context) {
+	list, err := r.projects()
+	if err != nil {
+		log.Printf("[Resumer] load projects failed: %v", err)
+		return
+	}
+	for _, p := range list {
+		matches, err := filepath.Glob(filepath.Join(p.Path, RelRunning, "*.md"))
+		if err != nil {
+			continue
+		}
+		for _, path := range matches {
+			taskFile := filepath.Base(path)
+			if !r.resumable(p.Path, taskFile) {
+				continue
+			}
+			r.start(ctx, p.Path, taskFile)
+		}
+	}
+}
+
+// resumable はタスクが自動再開の条件を満たすかを判定します。
+//   - 前回の実行記録が waiting_answer で、実行回数が MaxAttempts 未満
+//   - 計画書が前回の実行終了後に更新され、最終更新から SettleDelay 以上経過している
+//   - 未回答の確認事項が残っていない
+//
+// ロックは判定せず、実行中なら Runner.Resume が lock_busy を返す。
+func (r *Resumer) resumable(projectPath, taskFile string) bool {
+	if r.isInFlight(projectPath, taskFile) {
+		return false
+	}
+	last, ok, err := LastRunForTask(r.cfg.RunsDir, projectPath, taskFile)
+	if err != nil {
+		log.Printf("[Resumer] load run history failed: project=%s, err=%v", projectPath, err)
+		return false
+	}
+	if !ok || last.Outcome != OutcomeWaitingAnswer || last.Attempt >= r.cfg.MaxAttempts {
+		return false
+	}
+
+	planPath := filepath.Join(projectPath, RelRunning, taskFile)
+	info, err := os.Stat(planPath)
+	if err != nil {
+		return false
+	}
+	if !info.ModTime().After(last.EndedAt) || r.now().Sub(info.ModTime()) < r.cfg.SettleDelay {
+		return false
+	}
+	return !hasUnansweredQuestion(planPath)
+}
+
+// start は再開実行を非同期で起動します
+func (r *Resumer) start(ctx context.Context, projectPath, taskFile string) {
+	key := projectPath + "\x00" + taskFile
+	r.mu.Lock()
+	if r.inFlight[key] {
+		r.mu.Unlock()
+		return
+	}
+	r.inFlight[key] = true
+	r.mu.Unlock()
+
+	log.Printf("[Resumer] resuming: project=%s, task=%s", projectPath, taskFile)
+	cfg := Config{
+		ProjectPath: projectPath,
+		TaskFile:    taskFile,
+		LocksDir:    r.cfg.LocksDir,
+		RunsDir:     r.cfg.RunsDir,
+		Trigger:     TriggerAutoResume,
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer func() {
+			r.mu.Lock()
+			delete(r.inFlight, key)
+			r.mu.Unlock()
+		}()
+		result := r.launch(ctx, cfg)
+		log.Printf("[Resumer] finished: project=%s, task=%s, outcome=%s", projectPath, taskFile, result.Outcome)
+	}()
+}
+
+// isInFlight はタスクの再開実行が進行中かを返します
+func (r *Resumer) isInFlight(projectPath, taskFile string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.inFlight[projectPath+"\x00"+taskFile]
+}
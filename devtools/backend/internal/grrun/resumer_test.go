@@ -0,0 +1,160 @@
+package grrun
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/projects"
+)
+
+func TestResumer_Check(t *testing.T) {
+	taskFile := "T.md"
+	answered := "### Q1: DB\n**ステータス**: 回答済\n**回答**: A案\n"
+	now := time.Now()
+	ranAt := now.Add(-time.Hour)
+	answeredAt := now.Add(-10 * time.Minute)
+
+	tests := []struct {
+		name       string
+		content    string
+		last       *RunRecord
+		modTime    time.Time
+		wantLaunch bool
+	}{
+		{
+			name:       "launches when answered after waiting_answer",
+			content:    answered,
+			last:       &RunRecord{Outcome: OutcomeWaitingAnswer, Attempt: 1, EndedAt: ranAt},
+			modTime:    answeredAt,
+			wantLaunch: true,
+		},
+		{
+			name:    "skips when unanswered questions remain",
+			content: "### Q1: DB\n**ステータス**: 未回答\n",
+			last:    &RunRecord{Outcome: OutcomeWaitingAnswer, Attempt: 1, EndedAt: ranAt},
+			modTime: answeredAt,
+		},
+		{
+			name:    "skips without run history",
+			content: answered,
+			modTime: answeredAt,
+		},
+		{
+			name:    "skips when last run was not waiting_answer",
+			content: answered,
+			last:    &RunRecord{Outcome: OutcomeNeedsCheck, Attempt: 1, EndedAt: ranAt},
+			modTime: answeredAt,
+		},
+		{
+			name:    "skips when plan was not updated after the run",
+			content: answered,
+			last:    &RunRecord{Outcome: OutcomeWaitingAnswer, Attempt: 1, EndedAt: ranAt},
+			modTime: ranAt.Add(-time.Minute),
+		},
+		{
+			name:    "skips while the plan is still being edited",
+			content: answered,
+			last:    &RunRecord{Outcome: OutcomeWaitingAnswer, Attempt: 1, EndedAt: ranAt},
+			modTime: now.Add(-10 * time.Second),
+		},
+		{
+			name:    "skips when attempts are exhausted",
+			content: answered,
+			last:    &RunRecord{Outcome: OutcomeWaitingAnswer, Attempt: 3, EndedAt: ranAt},
+			modTime: answeredAt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projDir := setupRunningTask(t, taskFile, tt.content)
+			planPath := filepath.Join(projDir, RelRunning, taskFile)
+			if err := os.Chtimes(planPath, tt.modTime, tt.modTime); err != nil {
+				t.Fatal(err)
+			}
+			runsDir := t.TempDir()
+			if tt.last != nil {
+				rec := *tt.last
+				rec.Project = projDir
+				rec.TaskFile = taskFile
+				if err := AppendRunRecord(runsDir, rec); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var mu sync.Mutex
+			var launched []Config
+			launch := func(ctx context.Context, cfg Config) RunResult {
+				mu.Lock()
+				defer mu.Unlock()
+				launched = append(launched, cfg)
+				return RunResult{Outcome: OutcomeCompleted}
+			}
+
+			r := NewResumer(ResumerConfig{
+				Enabled:     true,
+				SettleDelay: time.Minute,
+				MaxAttempts: 3,
+				LocksDir:    t.TempDir(),
+				RunsDir:     runsDir,
+			}, func() ([]projects.Project, error) {
+				return []projects.Project{{Name: "p", Path: projDir}}, nil
+			}, launch, func() time.Time { return now })
+
+			r.Check(context.Background())
+			r.Stop()
+
+			if got := len(launched) == 1; got != tt.wantLaunch {
+				t.Fatalf("launched = %+v, want launch=%v", launched, tt.wantLaunch)
+			}
+			if tt.wantLaunch {
+				cfg := launched[0]
+				if cfg.ProjectPath != projDir || cfg.TaskFile != taskFile || cfg.Trigger != TriggerAutoResume || cfg.RunsDir != runsDir {
+					t.Errorf("launch config = %+v", cfg)
+				}
+			}
+		})
+	}
+}
+
+func TestResumer_SkipsInFlight(t *testing.T) {
+	taskFile := "T.md"
+	projDir := setupRunningTask(t, taskFile, "### Q1: DB\n**ステータス**: 回答済\n**回答**: A案\n")
+	now := time.Now()
+	planPath := filepath.Join(projDir, RelRunning, taskFile)
+	if err := os.Chtimes(planPath, now.Add(-10*time.Minute), now.Add(-10*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	runsDir := t.TempDir()
+	if err := AppendRunRecord(runsDir, RunRecord{Project: projDir, TaskFile: taskFile, Outcome: OutcomeWaitingAnswer, Attempt: 1, EndedAt: now.Add(-time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	release := make(chan struct{})
+	var mu sync.Mutex
+	calls := 0
+	launch := func(ctx context.Context, cfg Config) RunResult {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		<-release
+		return RunResult{Outcome: OutcomeCompleted}
+	}
+
+	r := NewResumer(ResumerConfig{Enabled: true, SettleDelay: time.Minute, RunsDir: runsDir, LocksDir: t.TempDir()},
+		func() ([]projects.Project, error) { return []projects.Project{{Name: "p", Path: projDir}}, nil },
+		launch, func() time.Time { return now })
+
+	r.Check(context.Background())
+	r.Check(context.Background())
+	close(release)
+	r.Stop()
+
+	if calls != 1 {
+		t.Errorf("launch called %d times, want 1", calls)
+	}
+}
@@ -7,18 +7,28 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"time"
+
+	"ghostrunner/backend/internal/notify"
 )
 
-// Notifier は通知送信のインターフェースです。
-// service.NtfyService と同じシグネチャだが、直接依存を避けるために再定義しています。
-type Notifier interface {
-	Notify(title, message string)
-	NotifyError(title, message string)
+// Notifier は通知送信のインターフェースです（notify.Notifier。service.NtfyService と同一）
+type Notifier = notify.Notifier
+
+// ExecRequest はClaude CLIの1回の実行内容です
+type ExecRequest struct {
+	ProjectPath string
+	TaskFile    string
+	// SessionID は会話のセッションID。Resume=false なら新規セッションのIDとして --session-id に、
+	// Resume=true なら再開するセッションとして --resume に渡します（空なら指定しない）
+	SessionID string
+	// Resume は確認事項への回答後に元のセッションを再開する実行かを示します
+	Resume bool
 }
 
 // CommandExecutor はClaude CLIの実行を抽象化する関数型です。
 // テスト時に差し替え可能にするために型定義しています。
-type CommandExecutor func(ctx context.Context, projectPath, taskFile string) (exitCode int, err error)
+type CommandExecutor func(ctx context.Context, req ExecRequest) (exitCode int, err error)
 
 // Runner はgr-runのメイン実行ロジックを保持します
 type Runner struct {
@@ -26,6 +36,7 @@ type Runner struct {
 	notifier Notifier
 	executor CommandExecutor
 	lockFile *os.File // flockのfdをGC防止のため保持
+	now      func() time.Time
 }
 
 // NewRunner は新しいRunnerを生成します。
@@ -35,20 +46,27 @@ func NewRunner(cfg Config, notifier Notifier, executor CommandExecutor) *Runner
 		cfg:      cfg,
 		notifier: notifier,
 		executor: executor,
+		now:      time.Now,
 	}
 }
 
 // DefaultExecutor はClaude CLIを実行するデフォルトのCommandExecutorを返します
 func DefaultExecutor() CommandExecutor {
-	return func(ctx context.Context, projectPath, taskFile string) (int, error) {
-		prompt := fmt.Sprintf("/coding @%s", filepath.Join(RelRunning, taskFile))
+	return func(ctx context.Context, req ExecRequest) (int, error) {
 		args := []string{
-			"-p", prompt,
+			"-p", buildPrompt(req),
 			"--permission-mode", "bypassPermissions",
 		}
+		if req.SessionID != "" {
+			if req.Resume {
+				args = append(args, "--resume", req.SessionID)
+			} else {
+				args = append(args, "--session-id", req.SessionID)
+			}
+		}
 
 		cmd := exec.CommandContext(ctx, "claude", args...)
-		cmd.Dir = projectPath
+		cmd.Dir = req.ProjectPath
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
 
@@ -67,6 +85,16 @@ func DefaultExecutor() CommandExecutor {
 	}
 }
 
+// buildPrompt はClaude CLIに渡すプロンプトを組み立てます。
+// 再開時は回答済みの確認事項を読み直して続きから実装するよう指示します。
+func buildPrompt(req ExecRequest) string {
+	prompt := fmt.Sprintf("/coding @%s", filepath.Join(RelRunning, req.TaskFile))
+	if req.Resume {
+		prompt += "\n\n確認事項はすべて回答済みです。計画書の回答を読み直し、中断したところから実装を続けてください。"
+	}
+	return prompt
+}
+
 // Run はgr-runのメイン処理を実行します。
 // ロック取得 -> タスククレーム -> Claude実行 -> 結果分類 -> 通知 の順に処理します。
 func (r *Runner) Run(ctx context.Context) RunResult {
@@ -79,56 +107,186 @@ func (r *Runner) Run(ctx context.Context) RunResult {
 	log.Printf("[gr-run] started: project=%s, task=%s", projectPath, taskFile)
 
 	// ロック取得
+	release, busy := r.acquireLock(projectPath)
+	if busy != nil {
+		return *busy
+	}
+	defer release()
+
+	// タスクをクレーム（実装待ち -> 実行中）
+	if err := ClaimTask(projectPath, taskFile); err != nil {
+		msg := fmt.Sprintf("タスクの移動に失敗: %v", err)
+		log.Printf("[gr-run] claim failed: %v", err)
+		r.notifyError("gr-run: タスク移動失敗", msg)
+		return RunResult{Outcome: OutcomeAbnormal, Message: msg}
+	}
+	log.Printf("[gr-run] task claimed: %s -> %s", RelWaiting, RelRunning)
+	if r.cfg.RunsDir != "" {
+		// タスク開始時刻（ダッシュボードの「開始以降のコミット」）の起点。失敗は実行に影響させずログのみ
+		if err := RecordClaim(r.cfg.RunsDir, projectPath, taskFile, r.now()); err != nil {
+			log.Printf("[gr-run] failed to record claim: %v", err)
+		}
+	}
+
+	return r.execute(ctx, RunRecord{
+		Project:   projectPath,
+		TaskFile:  taskFile,
+		SessionID: newSessionID(),
+		Attempt:   1,
+		Trigger:   r.trigger(),
+	})
+}
+
+// Resume は確認事項に回答済みで 実行中 に残っているタスクを、前回の Claude セッションを再開して実行します。
+// 前回の実行が waiting_answer で終わっており、未回答の確認事項が残っていないことを確認してから実行します。
+// 前回の記録にセッションIDが無い場合（履歴導入前の実行など）は新しいセッションで /coding をやり直します。
+func (r *Runner) Resume(ctx context.Context) RunResult {
+	ctx, cancel := context.WithTimeout(ctx, ClaudeTimeout)
+	defer cancel()
+
+	projectPath := r.cfg.ProjectPath
+	taskFile := r.cfg.TaskFile
+
+	log.Printf("[gr-run] resume started: project=%s, task=%s", projectPath, taskFile)
+
+	// ロック取得
+	release, busy := r.acquireLock(projectPath)
+	if busy != nil {
+		return *busy
+	}
+	defer release()
+
+	rec, msg := r.resumeRecord(projectPath, taskFile)
+	if msg != "" {
+		log.Printf("[gr-run] not resumable: %s", msg)
+		return RunResult{Outcome: OutcomeNotResumable, Message: msg}
+	}
+	log.Printf("[gr-run] resuming: session=%s, attempt=%d", rec.SessionID, rec.Attempt)
+
+	return r.execute(ctx, rec)
+}
+
+// resumeRecord は再開実行の記録（実行前の項目）を組み立てます。再開できない場合は理由を返します。
+func (r *Runner) resumeRecord(projectPath, taskFile string) (RunRecord, string) {
+	runningPath := filepath.Join(projectPath, RelRunning, taskFile)
+	if !fileExists(runningPath) {
+		return RunRecord{}, fmt.Sprintf("実行中にタスクがありません: %s", taskFile)
+	}
+	if hasUnansweredQuestion(runningPath) {
+		return RunRecord{}, fmt.Sprintf("未回答の確認事項が残っています: %s", taskFile)
+	}
+
+	rec := RunRecord{
+		Project:  projectPath,
+		TaskFile: taskFile,
+		Attempt:  1,
+		Trigger:  r.trigger(),
+	}
+	if r.cfg.RunsDir != "" {
+		last, ok, err := LastRunForTask(r.cfg.RunsDir, projectPath, taskFile)
+		if err != nil {
+			log.Printf("[gr-run] failed to load run history: %v", err)
+		}
+		if ok {
+			if last.Outcome != OutcomeWaitingAnswer {
+				return RunRecord{}, fmt.Sprintf("前回の実行が回答待ちではありません（%s）: %s", last.Outcome, taskFile)
+			}
+			rec.SessionID = last.SessionID
+			rec.Attempt = last.Attempt + 1
+		}
+	}
+	if rec.SessionID != "" {
+		rec.Resumed = true
+	} else {
+		rec.SessionID = newSessionID()
+	}
+	return rec, ""
+}
+
+// acquireLock はプロジェクトのロックを取得し、解放関数を返します。
+// 取得できなかった場合は呼び出し元がそのまま返す RunResult を返します。
+func (r *Runner) acquireLock(projectPath string) (release func(), failed *RunResult) {
 	lockFile, acquired, err := AcquireLock(r.cfg.LocksDir, projectPath)
 	if err != nil {
 		msg := fmt.Sprintf("ロック取得に失敗: %v", err)
 		log.Printf("[gr-run] lock error: %v", err)
 		r.notifyError("gr-run: ロック失敗", msg)
-		return RunResult{Outcome: OutcomeAbnormal, Message: msg}
+		return nil, &RunResult{Outcome: OutcomeAbnormal, Message: msg}
 	}
 	if !acquired {
 		msg := fmt.Sprintf("他のプロセスが実行中です: %s", projectPath)
 		log.Printf("[gr-run] lock busy: %s", projectPath)
-		return RunResult{Outcome: OutcomeLockBusy, Message: msg}
+		return nil, &RunResult{Outcome: OutcomeLockBusy, Message: msg}
 	}
 	r.lockFile = lockFile
-	defer func() {
+	return func() {
 		r.lockFile.Close()
 		r.lockFile = nil
-	}()
+	}, nil
+}
 
-	// タスクをクレーム（実装待ち -> 実行中）
-	if err := ClaimTask(projectPath, taskFile); err != nil {
-		msg := fmt.Sprintf("タスクの移動に失敗: %v", err)
-		log.Printf("[gr-run] claim failed: %v", err)
-		r.notifyError("gr-run: タスク移動失敗", msg)
-		return RunResult{Outcome: OutcomeAbnormal, Message: msg}
-	}
-	log.Printf("[gr-run] task claimed: %s -> %s", RelWaiting, RelRunning)
+// execute はClaudeを実行して結果を分類し、実行履歴への記録と通知を行います（ロック取得済みで呼び出すこと）。
+// rec には実行前に決まる項目（プロジェクト・タスク・セッションID・再開か・試行回数・契機）を設定して渡します。
+func (r *Runner) execute(ctx context.Context, rec RunRecord) RunResult {
+	rec.StartedAt = r.now()
 
 	// Claude実行
-	exitCode, err := r.executor(ctx, projectPath, taskFile)
+	exitCode, err := r.executor(ctx, ExecRequest{
+		ProjectPath: rec.Project,
+		TaskFile:    rec.TaskFile,
+		SessionID:   rec.SessionID,
+		Resume:      rec.Resumed,
+	})
 	if err != nil {
 		log.Printf("[gr-run] executor error: %v (exitCode=%d)", err, exitCode)
 		if exitCode == -1 {
 			msg := fmt.Sprintf("Claude起動に失敗: %v", err)
 			r.notifyError("gr-run: Claude起動失敗", msg)
+			rec.ExitCode = exitCode
+			rec.Outcome = OutcomeAbnormal
+			r.record(rec)
 			return RunResult{Outcome: OutcomeAbnormal, Message: msg}
 		}
 	}
-	log.Printf("[gr-run] claude finished: exitCode=%d", exitCode)
+	log.Printf("[gr-run] claude finished: exitCode=%d, session=%s, resumed=%t", exitCode, rec.SessionID, rec.Resumed)
 
 	// 結果分類
-	outcome := ClassifyResult(projectPath, taskFile, exitCode)
-	result := r.buildResult(outcome, taskFile)
+	outcome := ClassifyResult(rec.Project, rec.TaskFile, exitCode)
+	result := r.buildResult(outcome, rec.TaskFile)
+	if rec.Resumed {
+		result.Message = "[再開] " + result.Message
+	}
+
+	rec.ExitCode = exitCode
+	rec.Outcome = outcome
+	r.record(rec)
 
 	// 通知
-	r.sendNotification(outcome, taskFile, result.Message)
+	r.sendNotification(outcome, rec.TaskFile, result.Message)
 
-	log.Printf("[gr-run] completed: outcome=%s, task=%s", outcome, taskFile)
+	log.Printf("[gr-run] completed: outcome=%s, task=%s", outcome, rec.TaskFile)
 	return result
 }
 
+// trigger は実行の契機（Config.Trigger。未設定なら手動）を返します
+func (r *Runner) trigger() string {
+	if r.cfg.Trigger == "" {
+		return TriggerManual
+	}
+	return r.cfg.Trigger
+}
+
+// record は実行履歴に1件追記します（RunsDir 未設定時は記録しない）。失敗は実行結果に影響させずログのみ。
+func (r *Runner) record(rec RunRecord) {
+	if r.cfg.RunsDir == "" {
+		return
+	}
+	rec.EndedAt = r.now()
+	if err := AppendRunRecord(r.cfg.RunsDir, rec); err != nil {
+		log.Printf("[gr-run] failed to record run history: %v", err)
+	}
+}
+
 // buildResult はOutcomeからRunResultを構築します
 func (r *Runner) buildResult(outcome Outcome, taskFile string) RunResult {
 	switch outcome {
@@ -166,15 +324,21 @@ func (r *Runner) sendNotification(outcome Outcome, taskFile, message string) {
 		return
 	}
 
-	title := fmt.Sprintf("gr-run: %s", taskFile)
+	msg := notify.Message{
+		Event:    notify.EventRunFinished,
+		Project:  r.cfg.ProjectPath,
+		Title:    fmt.Sprintf("gr-run: %s", taskFile),
+		Body:     message,
+		Priority: notify.PriorityDefault,
+	}
 	switch outcome {
 	case OutcomeAbnormal:
-		r.notifier.NotifyError(title, message)
-	case OutcomeLockBusy:
+		msg.Event, msg.Priority = notify.EventRunError, notify.PriorityHigh
+	case OutcomeLockBusy, OutcomeNotResumable:
 		// 通知しない
-	default:
-		r.notifier.Notify(title, message)
+		return
 	}
+	notify.Send(r.notifier, msg)
 }
 
 // notifyError はエラー通知を送信するヘルパーです
@@ -182,5 +346,11 @@ func (r *Runner) notifyError(title, message string) {
 	if r.notifier == nil {
 		return
 	}
-	r.notifier.NotifyError(title, message)
+	notify.Send(r.notifier, notify.Message{
+		Event:    notify.EventRunError,
+		Project:  r.cfg.ProjectPath,
+		Title:    title,
+		Body:     message,
+		Priority: notify.PriorityHigh,
+	})
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -62,9 +63,9 @@ func setupProject(t *testing.T, taskFile string) string {
 // makeExecutor creates a CommandExecutor stub that returns the given exitCode/err
 // and runs an optional side-effect function to simulate Claude behavior (file moves etc.)
 func makeExecutor(exitCode int, err error, sideEffect func(projectPath, taskFile string)) CommandExecutor {
-	return func(ctx context.Context, projectPath, taskFile string) (int, error) {
+	return func(ctx context.Context, req ExecRequest) (int, error) {
 		if sideEffect != nil {
-			sideEffect(projectPath, taskFile)
+			sideEffect(req.ProjectPath, req.TaskFile)
 		}
 		return exitCode, err
 	}
@@ -219,7 +220,7 @@ func TestRunner_Run(t *testing.T) {
 		defer lockFile.Close()
 
 		executorCalled := false
-		executor := func(ctx context.Context, projectPath, tf string) (int, error) {
+		executor := func(ctx context.Context, req ExecRequest) (int, error) {
 			executorCalled = true
 			return 0, nil
 		}
@@ -273,3 +274,185 @@ func TestRunner_Run(t *testing.T) {
 		}
 	})
 }
+
+// setupRunningTask creates a temp project with the task file already in running
+func setupRunningTask(t *testing.T, taskFile, content string) string {
+	t.Helper()
+	projDir := t.TempDir()
+	runDir := filepath.Join(projDir, RelRunning)
+	if err := os.MkdirAll(runDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(runDir, taskFile), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return projDir
+}
+
+func TestRunner_Run_RecordsHistory(t *testing.T) {
+	taskFile := "T.md"
+	projDir := setupProject(t, taskFile)
+	runsDir := t.TempDir()
+
+	var got ExecRequest
+	executor := func(ctx context.Context, req ExecRequest) (int, error) {
+		got = req
+		os.WriteFile(filepath.Join(req.ProjectPath, RelRunning, req.TaskFile), []byte("**ステータス**: 未回答"), 0644)
+		return 0, nil
+	}
+
+	runner := NewRunner(Config{
+		ProjectPath: projDir,
+		TaskFile:    taskFile,
+		LocksDir:    t.TempDir(),
+		RunsDir:     runsDir,
+	}, nil, executor)
+	runner.Run(context.Background())
+
+	if got.SessionID == "" || got.Resume {
+		t.Errorf("exec request = %+v, want new session", got)
+	}
+	last, ok, err := LastRunForTask(runsDir, projDir, taskFile)
+	if err != nil || !ok {
+		t.Fatalf("LastRunForTask: ok=%v, err=%v", ok, err)
+	}
+	if last.SessionID != got.SessionID || last.Outcome != OutcomeWaitingAnswer || last.Attempt != 1 || last.Trigger != TriggerManual {
+		t.Errorf("record = %+v", last)
+	}
+	claimed, ok, err := TaskClaimedAt(runsDir, projDir, taskFile)
+	if err != nil || !ok || claimed.IsZero() || claimed.After(last.StartedAt) {
+		t.Errorf("TaskClaimedAt = %v (ok=%v, err=%v), want claim time before %v", claimed, ok, err, last.StartedAt)
+	}
+}
+
+func TestRunner_Resume(t *testing.T) {
+	taskFile := "T.md"
+	answered := "### Q1: DB\n**ステータス**: 回答済\n**回答**: A案\n"
+
+	t.Run("resumes the recorded session after all questions are answered", func(t *testing.T) {
+		projDir := setupRunningTask(t, taskFile, answered)
+		runsDir := t.TempDir()
+		if err := AppendRunRecord(runsDir, RunRecord{Project: projDir, TaskFile: taskFile, SessionID: "sess-1", Attempt: 1, Outcome: OutcomeWaitingAnswer}); err != nil {
+			t.Fatal(err)
+		}
+		notif := &mockNotifier{}
+
+		var got ExecRequest
+		executor := func(ctx context.Context, req ExecRequest) (int, error) {
+			got = req
+			doneDir := filepath.Join(req.ProjectPath, RelDone)
+			os.MkdirAll(doneDir, 0755)
+			os.Rename(filepath.Join(req.ProjectPath, RelRunning, req.TaskFile), filepath.Join(doneDir, req.TaskFile))
+			return 0, nil
+		}
+
+		runner := NewRunner(Config{
+			ProjectPath: projDir,
+			TaskFile:    taskFile,
+			LocksDir:    t.TempDir(),
+			RunsDir:     runsDir,
+			Trigger:     TriggerAutoResume,
+		}, notif, executor)
+		result := runner.Resume(context.Background())
+
+		if result.Outcome != OutcomeCompleted {
+			t.Errorf("outcome = %q, want %q", result.Outcome, OutcomeCompleted)
+		}
+		if got.SessionID != "sess-1" || !got.Resume {
+			t.Errorf("exec request = %+v, want resume of sess-1", got)
+		}
+		last, _, _ := LastRunForTask(runsDir, projDir, taskFile)
+		if !last.Resumed || last.Attempt != 2 || last.Trigger != TriggerAutoResume || last.Outcome != OutcomeCompleted {
+			t.Errorf("record = %+v", last)
+		}
+		if notif.notifyCount() != 1 {
+			t.Errorf("Notify called %d times, want 1", notif.notifyCount())
+		}
+	})
+
+	t.Run("falls back to a new session without run history", func(t *testing.T) {
+		projDir := setupRunningTask(t, taskFile, answered)
+
+		var got ExecRequest
+		executor := func(ctx context.Context, req ExecRequest) (int, error) {
+			got = req
+			return 0, nil
+		}
+
+		runner := NewRunner(Config{
+			ProjectPath: projDir,
+			TaskFile:    taskFile,
+			LocksDir:    t.TempDir(),
+			RunsDir:     t.TempDir(),
+		}, nil, executor)
+		result := runner.Resume(context.Background())
+
+		if result.Outcome != OutcomeNeedsCheck {
+			t.Errorf("outcome = %q, want %q", result.Outcome, OutcomeNeedsCheck)
+		}
+		if got.SessionID == "" || got.Resume {
+			t.Errorf("exec request = %+v, want new session", got)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		content string
+		inDone  bool
+		last    Outcome
+	}{
+		{name: "not resumable: unanswered questions remain", content: "**ステータス**: 未回答", last: OutcomeWaitingAnswer},
+		{name: "not resumable: task not in running", content: answered, inDone: true, last: OutcomeWaitingAnswer},
+		{name: "not resumable: last run was not waiting_answer", content: answered, last: OutcomeAbnormal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projDir := setupRunningTask(t, taskFile, tt.content)
+			if tt.inDone {
+				doneDir := filepath.Join(projDir, RelDone)
+				os.MkdirAll(doneDir, 0755)
+				os.Rename(filepath.Join(projDir, RelRunning, taskFile), filepath.Join(doneDir, taskFile))
+			}
+			runsDir := t.TempDir()
+			if err := AppendRunRecord(runsDir, RunRecord{Project: projDir, TaskFile: taskFile, SessionID: "sess-1", Attempt: 1, Outcome: tt.last}); err != nil {
+				t.Fatal(err)
+			}
+			notif := &mockNotifier{}
+
+			executorCalled := false
+			executor := func(ctx context.Context, req ExecRequest) (int, error) {
+				executorCalled = true
+				return 0, nil
+			}
+
+			runner := NewRunner(Config{
+				ProjectPath: projDir,
+				TaskFile:    taskFile,
+				LocksDir:    t.TempDir(),
+				RunsDir:     runsDir,
+			}, notif, executor)
+			result := runner.Resume(context.Background())
+
+			if result.Outcome != OutcomeNotResumable {
+				t.Errorf("outcome = %q, want %q", result.Outcome, OutcomeNotResumable)
+			}
+			if executorCalled {
+				t.Error("executor should not have been called")
+			}
+			if notif.notifyCount()+notif.errorCount() != 0 {
+				t.Error("not_resumable should not notify")
+			}
+		})
+	}
+}
+
+func TestBuildPrompt(t *testing.T) {
+	fresh := buildPrompt(ExecRequest{TaskFile: "T.md"})
+	if fresh != "/coding @開発/実装/実行中/T.md" {
+		t.Errorf("fresh prompt = %q", fresh)
+	}
+	resumed := buildPrompt(ExecRequest{TaskFile: "T.md", SessionID: "s", Resume: true})
+	if !strings.HasPrefix(resumed, fresh+"\n") {
+		t.Errorf("resumed prompt = %q, want continuation of %q", resumed, fresh)
+	}
+}
@@ -1,6 +1,10 @@
 package grrun
 
-import "time"
+import (
+	"time"
+
+	"ghostrunner/backend/internal/plandoc"
+)
 
 // Config はgr-runの実行設定を保持します
 type Config struct {
@@ -10,8 +14,21 @@ type Config struct {
 	TaskFile string
 	// LocksDir はflockファイルの格納ディレクトリ（デフォルト: ~/.ghostrunner/locks）
 	LocksDir string
+	// RunsDir は実行履歴（プロジェクトごとのJSONL）の格納ディレクトリ（デフォルト: ~/.ghostrunner/runs）。
+	// 空の場合は記録せず、Resume もできません
+	RunsDir string
+	// Trigger は実行の契機（TriggerManual / TriggerAutoResume）。空は TriggerManual
+	Trigger string
 }
 
+// 実行の契機（RunRecord.Trigger）
+const (
+	// TriggerManual は gr-run CLI の手動実行・外部からの起動です
+	TriggerManual = "manual"
+	// TriggerAutoResume は Resumer による確認事項回答後の自動再開です
+	TriggerAutoResume = "auto_resume"
+)
+
 // Outcome はClaude実行後の結果分類を表します
 type Outcome string
 
@@ -26,6 +43,8 @@ const (
 	OutcomeNeedsCheck Outcome = "needs_check"
 	// OutcomeLockBusy は既に他のプロセスが実行中であることを示します
 	OutcomeLockBusy Outcome = "lock_busy"
+	// OutcomeNotResumable は再開の条件（実行中にあり未回答なし・前回が回答待ち）を満たさず実行しなかったことを示します
+	OutcomeNotResumable Outcome = "not_resumable"
 )
 
 // RunResult はgr-run実行の結果を保持します
@@ -44,8 +63,8 @@ const (
 )
 
 // UnansweredPattern は確認事項の未回答を検出する正規表現パターンです。
-// chief-director.md の SSOT パターンと一致させること。
-const UnansweredPattern = `\*\*ステータス\*\*:\s*未回答`
+// 実体は plandoc.UnansweredPattern（chief-director.md の SSOT パターンと一致させること）。
+const UnansweredPattern = plandoc.UnansweredPattern
 
 // ClaudeTimeout はClaude実行のタイムアウト時間です
 const ClaudeTimeout = 60 * time.Minute
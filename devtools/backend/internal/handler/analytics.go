@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"ghostrunner/backend/internal/analytics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnalyticsHandler はセッション分析関連のHTTPハンドラを提供します
+type AnalyticsHandler struct {
+	svc analytics.Service
+}
+
+// NewAnalyticsHandler は新しいAnalyticsHandlerを生成します
+func NewAnalyticsHandler(svc analytics.Service) *AnalyticsHandler {
+	return &AnalyticsHandler{svc: svc}
+}
+
+// Handle は会話ログのセッション分析を集計して返します。
+// GET /api/analytics?project=&bucket=day|week&from=&to=
+//
+// from / to は RFC3339 または YYYY-MM-DD（ローカル時刻0時）で指定します。
+//
+// レスポンス:
+//   - 200: 成功（analytics.Report）
+//   - 400: パラメータ不正（bucket 不正、日付形式不正、未登録プロジェクト）
+//   - 500: 集計失敗
+func (h *AnalyticsHandler) Handle(c *gin.Context) {
+	q := analytics.Query{
+		ProjectPath: c.Query("project"),
+		Bucket:      analytics.Bucket(c.Query("bucket")),
+	}
+
+	var err error
+	if q.From, err = parseTimeParam(c.Query("from")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "from の形式が不正です（RFC3339 または YYYY-MM-DD）",
+		})
+		return
+	}
+	if q.To, err = parseTimeParam(c.Query("to")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "to の形式が不正です（RFC3339 または YYYY-MM-DD）",
+		})
+		return
+	}
+
+	log.Printf("[AnalyticsHandler] Handle started: project=%s, bucket=%s", q.ProjectPath, q.Bucket)
+
+	report, err := h.svc.Report(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("[AnalyticsHandler] Handle failed: error=%v", err)
+		if errors.Is(err, analytics.ErrValidation) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "セッション分析の集計に失敗しました",
+		})
+		return
+	}
+
+	log.Printf("[AnalyticsHandler] Handle completed: projects=%d, sessions=%d", len(report.Projects), report.Total.Sessions)
+	c.JSON(http.StatusOK, report)
+}
+
+// parseTimeParam はクエリパラメータの日時を RFC3339 または YYYY-MM-DD（ローカル時刻）で解釈します。
+// 空文字はゼロ値（無制限）を返します。
+func parseTimeParam(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", s, time.Local)
+}
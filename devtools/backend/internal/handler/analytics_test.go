@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/analytics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockAnalyticsService はテスト用のanalytics.Serviceモックです
+type mockAnalyticsService struct {
+	reportFunc func(ctx context.Context, q analytics.Query) (*analytics.Report, error)
+}
+
+func (m *mockAnalyticsService) Report(ctx context.Context, q analytics.Query) (*analytics.Report, error) {
+	return m.reportFunc(ctx, q)
+}
+
+func setupAnalyticsRouter(svc analytics.Service) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/analytics", NewAnalyticsHandler(svc).Handle)
+	return r
+}
+
+func TestAnalyticsHandler_Handle(t *testing.T) {
+	var got analytics.Query
+	mock := &mockAnalyticsService{
+		reportFunc: func(ctx context.Context, q analytics.Query) (*analytics.Report, error) {
+			got = q
+			if q.ProjectPath == "/unknown" {
+				return nil, fmt.Errorf("%w: project is not registered", analytics.ErrValidation)
+			}
+			if q.ProjectPath == "/broken" {
+				return nil, fmt.Errorf("boom")
+			}
+			return &analytics.Report{Bucket: q.Bucket, Projects: []analytics.ProjectStats{}}, nil
+		},
+	}
+	r := setupAnalyticsRouter(mock)
+
+	tests := []struct {
+		name     string
+		url      string
+		wantCode int
+	}{
+		{"正常系", "/api/analytics?project=/p&bucket=week&from=2026-07-01&to=2026-07-08T00:00:00Z", http.StatusOK},
+		{"from不正", "/api/analytics?from=yesterday", http.StatusBadRequest},
+		{"to不正", "/api/analytics?to=2026/07/01", http.StatusBadRequest},
+		{"未登録プロジェクト", "/api/analytics?project=/unknown", http.StatusBadRequest},
+		{"集計失敗", "/api/analytics?project=/broken", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+
+	// 正常系のクエリ解釈を再確認
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/analytics?project=/p&bucket=week&from=2026-07-01", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "/p", got.ProjectPath)
+	assert.Equal(t, analytics.BucketWeek, got.Bucket)
+	assert.True(t, got.From.Equal(time.Date(2026, 7, 1, 0, 0, 0, 0, time.Local)))
+	assert.True(t, got.To.IsZero())
+}
@@ -0,0 +1,303 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"ghostrunner/backend/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// authDeviceKey は認証済み端末を gin.Context に格納するキーです
+const authDeviceKey = "auth.device"
+
+// queryTokenRoutes は access_token クエリでトークンを受け付けるルートです。
+// ヘッダーを付けられない EventSource / WebSocket の購読に限り、それ以外はクエリのトークンを無視します
+// （クエリはアクセスログやブラウザの履歴に残るため、長期間有効な端末トークンを載せる経路を最小にする）。
+var queryTokenRoutes = map[string]bool{
+	"/api/bus/events":       true,
+	"/api/bus/events/ws":    true,
+	"/api/dashboard/stream": true,
+	"/api/patrol/stream":    true,
+}
+
+// hostDevice はループバックから来たホスト本人の要求に割り当てる端末です
+var hostDevice = auth.Device{ID: "host", Name: "host", Scope: auth.ScopeDestroy}
+
+// AuthHandler は端末のペアリング・管理APIと、ルートごとのスコープを検査するミドルウェアを提供します
+type AuthHandler struct {
+	svc *auth.Service
+	cfg auth.Config
+}
+
+// NewAuthHandler は新しいAuthHandlerを生成します
+func NewAuthHandler(svc *auth.Service, cfg auth.Config) *AuthHandler {
+	return &AuthHandler{svc: svc, cfg: cfg}
+}
+
+// PairingRequest はペアリングコード発行のリクエストです
+type PairingRequest struct {
+	Scope string `json:"scope"` // read / execute / destroy（省略時は read）
+}
+
+// PairRequest はペアリングコードとトークンの交換リクエストです
+type PairRequest struct {
+	Code string `json:"code"`
+	Name string `json:"name"` // 端末名（一覧と失効に使う表示名）
+}
+
+// RevokeRequest は端末の失効リクエストです
+type RevokeRequest struct {
+	ID string `json:"id"`
+}
+
+// Require は required 以上のスコープを持つ端末のみ通すミドルウェアを返します。
+// トークンは Authorization: Bearer で受け取ります。EventSource / WebSocket の購読ルート（queryTokenRoutes）に限り
+// access_token クエリも受け付けます。
+// 認証無効時とホスト本人（プロキシを経由しないループバック）の要求は常に通します。
+func (h *AuthHandler) Require(required auth.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		device, ok := h.authenticate(c)
+		if !ok {
+			return
+		}
+		if !device.Scope.Allows(required) {
+			authRejected.Inc("insufficient_scope")
+			log.Printf("[AuthHandler] insufficient scope: device=%s, scope=%s, required=%s, path=%s",
+				device.ID, device.Scope, required, c.FullPath())
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "この操作には " + string(required) + " スコープが必要です",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// authenticate は要求元の端末を特定します。失敗時は 401 を書いて false を返します。
+// 同じ要求で複数のミドルウェアが呼ばれても、検証は最初の1回だけ行います。
+func (h *AuthHandler) authenticate(c *gin.Context) (auth.Device, bool) {
+	if v, ok := c.Get(authDeviceKey); ok {
+		return v.(auth.Device), true
+	}
+	if h.cfg.Disabled || (h.cfg.TrustLoopback && isHostRequest(c.Request)) {
+		c.Set(authDeviceKey, hostDevice)
+		return hostDevice, true
+	}
+
+	token := bearerToken(c)
+	if token == "" {
+		authRejected.Inc("missing_token")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "認証が必要です",
+		})
+		return auth.Device{}, false
+	}
+	device, err := h.svc.Authenticate(token)
+	if err != nil {
+		authRejected.Inc("invalid_token")
+		log.Printf("[AuthHandler] invalid token: path=%s, remote=%s", c.FullPath(), c.Request.RemoteAddr)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "トークンが無効です",
+		})
+		return auth.Device{}, false
+	}
+	c.Set(authDeviceKey, device)
+	return device, true
+}
+
+// DeviceID は Require を通過した要求の端末ID（ホスト本人は host）を返します。未認証の場合は空文字です
+func DeviceID(c *gin.Context) string {
+	if v, ok := c.Get(authDeviceKey); ok {
+		if device, ok := v.(auth.Device); ok {
+			return device.ID
+		}
+	}
+	return ""
+}
+
+// bearerToken は Authorization ヘッダー、無ければ購読ルートに限り access_token クエリのトークンを返します
+func bearerToken(c *gin.Context) string {
+	if h := c.GetHeader("Authorization"); h != "" {
+		if token, ok := strings.CutPrefix(h, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+		return ""
+	}
+	if !queryTokenRoutes[c.FullPath()] {
+		return ""
+	}
+	return c.Query("access_token")
+}
+
+// isHostRequest はプロキシを経由しないループバックからの要求かを返します。
+// Tailscale Serve / Funnel はループバックへ転送するため、転送ヘッダーが付いた要求はホスト扱いにしません。
+// gin の ClientIP は転送ヘッダーを信頼するため使わず、接続元アドレスを直接見ます。
+func isHostRequest(r *http.Request) bool {
+	for _, h := range []string{"X-Forwarded-For", "Forwarded", "X-Real-Ip", "Tailscale-User-Login", "Tailscale-Funnel-Request"} {
+		if r.Header.Get(h) != "" {
+			return false
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// pairClient はペアリングの失敗を数える接続元を返します。
+// Tailscale Serve などループバックのプロキシ経由の要求は、プロキシが末尾に付けた X-Forwarded-For の値を使います
+// （ループバック以外からの X-Forwarded-For は偽装できるため見ません）。
+func pairClient(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			parts := strings.Split(xff, ",")
+			if last := strings.TrimSpace(parts[len(parts)-1]); last != "" {
+				return last
+			}
+		}
+	}
+	return host
+}
+
+// requireHost はホスト本人の要求のみ通します。失敗時は 403 を書いて false を返します
+func (h *AuthHandler) requireHost(c *gin.Context) bool {
+	if h.cfg.Disabled || (h.cfg.TrustLoopback && isHostRequest(c.Request)) {
+		return true
+	}
+	authRejected.Inc("not_host")
+	c.JSON(http.StatusForbidden, gin.H{
+		"success": false,
+		"error":   "ペアリングコードはホストでのみ発行できます",
+	})
+	return false
+}
+
+// HandleStartPairing はホストに表示するペアリングコードを発行します。
+// POST /api/auth/pairing
+func (h *AuthHandler) HandleStartPairing(c *gin.Context) {
+	if !h.requireHost(c) {
+		return
+	}
+	var req PairingRequest
+	// 本文なし（スコープ省略）も受け付ける
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "リクエストが不正です",
+		})
+		return
+	}
+	scope, err := auth.ParseScope(req.Scope)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	p, err := h.svc.StartPairing(scope)
+	if err != nil {
+		log.Printf("[AuthHandler] HandleStartPairing failed: error=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "ペアリングコードの発行に失敗しました",
+		})
+		return
+	}
+	log.Printf("[AuthHandler] pairing code issued: code=%s, scope=%s, expiresAt=%s", p.Code, p.Scope, p.ExpiresAt.Format("15:04:05"))
+	c.JSON(http.StatusOK, p)
+}
+
+// HandlePair はペアリングコードを端末トークンと交換します（認証不要）。
+// POST /api/auth/pair
+func (h *AuthHandler) HandlePair(c *gin.Context) {
+	var req PairRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "リクエストが不正です",
+		})
+		return
+	}
+	res, err := h.svc.Pair(req.Code, req.Name, pairClient(c.Request))
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrValidation):
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		case errors.Is(err, auth.ErrInvalidCode):
+			authRejected.Inc("invalid_code")
+			log.Printf("[AuthHandler] invalid pairing code: remote=%s", c.Request.RemoteAddr)
+			c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "ペアリングコードが無効です"})
+		case errors.Is(err, auth.ErrTooManyAttempts):
+			authRejected.Inc("pair_locked")
+			log.Printf("[AuthHandler] pairing locked out: remote=%s", c.Request.RemoteAddr)
+			c.JSON(http.StatusTooManyRequests, gin.H{"success": false, "error": "ペアリングコードの誤りが続いたため、しばらくしてから再試行してください"})
+		default:
+			log.Printf("[AuthHandler] HandlePair failed: error=%v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "ペアリングに失敗しました"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// HandleMe は要求元の端末（ホスト本人は id=host）を返します。
+// GET /api/auth/me
+func (h *AuthHandler) HandleMe(c *gin.Context) {
+	device, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, device)
+}
+
+// HandleDevices は登録端末の一覧を返します（destroy スコープ）。
+// GET /api/auth/devices
+func (h *AuthHandler) HandleDevices(c *gin.Context) {
+	devices := h.svc.Devices()
+	c.JSON(http.StatusOK, gin.H{
+		"devices": devices,
+		"total":   len(devices),
+	})
+}
+
+// HandleRevoke は端末を失効させます（destroy スコープ）。
+// POST /api/auth/devices/revoke
+func (h *AuthHandler) HandleRevoke(c *gin.Context) {
+	var req RevokeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "リクエストが不正です",
+		})
+		return
+	}
+	if err := h.svc.Revoke(req.ID); err != nil {
+		switch {
+		case errors.Is(err, auth.ErrValidation):
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		case errors.Is(err, auth.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"success": false, "error": "端末が見つかりません"})
+		default:
+			log.Printf("[AuthHandler] HandleRevoke failed: error=%v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "端末の失効に失敗しました"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
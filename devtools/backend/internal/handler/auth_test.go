@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"ghostrunner/backend/internal/auth"
+	"ghostrunner/backend/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAuthRouter は read / execute / destroy の各スコープのルートを持つテスト用ルーターを返します
+func newAuthRouter(t *testing.T, cfg auth.Config) (*gin.Engine, *auth.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	svc, err := auth.NewService(filepath.Join(t.TempDir(), "devices.json"), nil)
+	require.NoError(t, err)
+	h := NewAuthHandler(svc, cfg)
+
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"success": true}) }
+	r := gin.New()
+	r.POST("/api/auth/pair", h.HandlePair)
+	r.GET("/metrics", h.Require(auth.ScopeRead), ok)
+	api := r.Group("/api", h.Require(auth.ScopeRead))
+	api.POST("/auth/pairing", h.HandleStartPairing)
+	api.GET("/auth/me", h.HandleMe)
+	api.GET("/auth/devices", h.Require(auth.ScopeDestroy), h.HandleDevices)
+	api.POST("/auth/devices/revoke", h.Require(auth.ScopeDestroy), h.HandleRevoke)
+	api.GET("/state", ok)
+	api.GET("/dashboard/stream", ok)
+	api.POST("/command", h.Require(auth.ScopeExecute), ok)
+	api.POST("/projects/destroy", h.Require(auth.ScopeDestroy), ok)
+	return r, svc
+}
+
+// doAuthRequest は remote から要求を送ります（"127.0.0.1:1234" でホスト本人）
+func doAuthRequest(r *gin.Engine, method, target, remote string, header map[string]string, body any) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		_ = json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, target, &buf)
+	req.RemoteAddr = remote
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// pairDevice はホストでコードを発行し、リモート端末としてトークンと交換します
+func pairDevice(t *testing.T, r *gin.Engine, scope auth.Scope) auth.PairResult {
+	t.Helper()
+	w := doAuthRequest(r, http.MethodPost, "/api/auth/pairing", "127.0.0.1:1234", nil, PairingRequest{Scope: string(scope)})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var p auth.Pairing
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+
+	w = doAuthRequest(r, http.MethodPost, "/api/auth/pair", "100.64.0.2:5555", nil, PairRequest{Code: p.Code, Name: "phone"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var res auth.PairResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	return res
+}
+
+func TestAuthHandler_Require(t *testing.T) {
+	r, _ := newAuthRouter(t, auth.Config{TrustLoopback: true})
+	readToken := pairDevice(t, r, auth.ScopeRead).Token
+	execToken := pairDevice(t, r, auth.ScopeExecute).Token
+	remote := "100.64.0.2:5555"
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		remote     string
+		header     map[string]string
+		wantStatus int
+	}{
+		{name: "token missing", method: http.MethodGet, target: "/api/state", remote: remote, wantStatus: http.StatusUnauthorized},
+		{name: "invalid token", method: http.MethodGet, target: "/api/state", remote: remote, header: map[string]string{"Authorization": "Bearer grt_nope"}, wantStatus: http.StatusUnauthorized},
+		{name: "read token reads", method: http.MethodGet, target: "/api/state", remote: remote, header: map[string]string{"Authorization": "Bearer " + readToken}, wantStatus: http.StatusOK},
+		{name: "query token for EventSource", method: http.MethodGet, target: "/api/dashboard/stream?access_token=" + readToken, remote: remote, wantStatus: http.StatusOK},
+		{name: "query token ignored outside streams", method: http.MethodGet, target: "/api/state?access_token=" + readToken, remote: remote, wantStatus: http.StatusUnauthorized},
+		{name: "read token cannot execute", method: http.MethodPost, target: "/api/command", remote: remote, header: map[string]string{"Authorization": "Bearer " + readToken}, wantStatus: http.StatusForbidden},
+		{name: "execute token executes", method: http.MethodPost, target: "/api/command", remote: remote, header: map[string]string{"Authorization": "Bearer " + execToken}, wantStatus: http.StatusOK},
+		{name: "execute token cannot destroy", method: http.MethodPost, target: "/api/projects/destroy", remote: remote, header: map[string]string{"Authorization": "Bearer " + execToken}, wantStatus: http.StatusForbidden},
+		{name: "host is trusted", method: http.MethodPost, target: "/api/projects/destroy", remote: "127.0.0.1:1234", wantStatus: http.StatusOK},
+		{name: "proxied loopback is not host", method: http.MethodGet, target: "/api/state", remote: "127.0.0.1:1234", header: map[string]string{"X-Forwarded-For": "203.0.113.9"}, wantStatus: http.StatusUnauthorized},
+		{name: "metrics needs token", method: http.MethodGet, target: "/metrics", remote: remote, wantStatus: http.StatusUnauthorized},
+		{name: "read token scrapes metrics", method: http.MethodGet, target: "/metrics", remote: remote, header: map[string]string{"Authorization": "Bearer " + readToken}, wantStatus: http.StatusOK},
+		{name: "host scrapes metrics", method: http.MethodGet, target: "/metrics", remote: "127.0.0.1:1234", wantStatus: http.StatusOK},
+		{name: "remote cannot issue pairing code", method: http.MethodPost, target: "/api/auth/pairing", remote: remote, header: map[string]string{"Authorization": "Bearer " + execToken}, wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doAuthRequest(r, tt.method, tt.target, tt.remote, tt.header, nil)
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestAuthHandler_Disabled(t *testing.T) {
+	r, _ := newAuthRouter(t, auth.Config{Disabled: true})
+	w := doAuthRequest(r, http.MethodPost, "/api/projects/destroy", "100.64.0.2:5555", nil, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthHandler_DevicesAndRevoke(t *testing.T) {
+	r, _ := newAuthRouter(t, auth.Config{TrustLoopback: true})
+	res := pairDevice(t, r, auth.ScopeRead)
+	bearer := map[string]string{"Authorization": "Bearer " + res.Token}
+
+	w := doAuthRequest(r, http.MethodGet, "/api/auth/me", "100.64.0.2:5555", bearer, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var me auth.Device
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &me))
+	assert.Equal(t, res.Device.ID, me.ID)
+
+	// 端末の管理は destroy スコープ（ホスト本人を含む）
+	w = doAuthRequest(r, http.MethodGet, "/api/auth/devices", "100.64.0.2:5555", bearer, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = doAuthRequest(r, http.MethodGet, "/api/auth/devices", "127.0.0.1:1234", nil, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"total":1`)
+	assert.NotContains(t, w.Body.String(), "tokenHash")
+
+	w = doAuthRequest(r, http.MethodPost, "/api/auth/devices/revoke", "127.0.0.1:1234", nil, RevokeRequest{ID: res.Device.ID})
+	require.Equal(t, http.StatusOK, w.Code)
+	w = doAuthRequest(r, http.MethodGet, "/api/state", "100.64.0.2:5555", bearer, nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = doAuthRequest(r, http.MethodPost, "/api/auth/devices/revoke", "127.0.0.1:1234", nil, RevokeRequest{ID: res.Device.ID})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAuthHandler_HandlePair_InvalidCode(t *testing.T) {
+	r, _ := newAuthRouter(t, auth.Config{TrustLoopback: true})
+	w := doAuthRequest(r, http.MethodPost, "/api/auth/pair", "100.64.0.2:5555", nil, PairRequest{Code: "ABCD-EFGH", Name: "phone"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = doAuthRequest(r, http.MethodPost, "/api/auth/pair", "100.64.0.2:5555", nil, PairRequest{Code: "ABCD-EFGH"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestAuthHandler_HandlePair_LockoutPerClient は誤りが続いた接続元だけが拒否され、他の端末はペアリングできることを確認します
+func TestAuthHandler_HandlePair_LockoutPerClient(t *testing.T) {
+	r, _ := newAuthRouter(t, auth.Config{TrustLoopback: true})
+	for i := 0; i < 5; i++ {
+		w := doAuthRequest(r, http.MethodPost, "/api/auth/pair", "100.64.0.9:4444", nil, PairRequest{Code: "ZZZZ-ZZZZ", Name: "attacker"})
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+	w := doAuthRequest(r, http.MethodPost, "/api/auth/pair", "100.64.0.9:4445", nil, PairRequest{Code: "ZZZZ-ZZZZ", Name: "attacker"})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	// 別の接続元は引き続きペアリングできる
+	pairDevice(t, r, auth.ScopeRead)
+}
+
+// TestAuthHandler_DefaultConfig_AcceptsFrontendProxy は同梱のフロントエンドの要求が既定の設定で通ることを確認します。
+// Next.js の rewrite はループバックから X-Forwarded-* を付けて転送し、Authorization は送らない。
+func TestAuthHandler_DefaultConfig_AcceptsFrontendProxy(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	cfg, err := config.Load(nil, func(string) string { return "" }, t.TempDir(), wd)
+	require.NoError(t, err)
+	r, _ := newAuthRouter(t, cfg.AuthConfig())
+
+	proxied := map[string]string{
+		"X-Forwarded-For":   "100.64.0.2",
+		"X-Forwarded-Host":  "mac.tailnet.ts.net:3000",
+		"X-Forwarded-Proto": "https",
+	}
+	for _, tt := range []struct {
+		method string
+		target string
+	}{
+		{http.MethodGet, "/api/state"},
+		{http.MethodPost, "/api/command"},
+		{http.MethodPost, "/api/projects/destroy"},
+	} {
+		w := doAuthRequest(r, tt.method, tt.target, "[::1]:50123", proxied, nil)
+		assert.Equal(t, http.StatusOK, w.Code, "%s %s: %s", tt.method, tt.target, w.Body.String())
+	}
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"ghostrunner/backend/internal/autoanswer"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AutoAnswerHandler は質問の自動回答の監査ログを返すHTTPハンドラを提供します
+type AutoAnswerHandler struct {
+	audit *autoanswer.AuditLog
+}
+
+// NewAutoAnswerHandler は新しいAutoAnswerHandlerを生成します
+func NewAutoAnswerHandler(audit *autoanswer.AuditLog) *AutoAnswerHandler {
+	return &AutoAnswerHandler{audit: audit}
+}
+
+// Handle は自動回答・エスカレーションの記録を新しい順に返します。
+// GET /api/auto-answers?project=&from=&to=&limit=
+//
+// from / to は RFC3339 または YYYY-MM-DD（ローカル時刻0時）で指定します。limit 省略時は100件（上限1000件）です。
+//
+// レスポンス:
+//   - 200: 成功（autoanswer.Result）
+//   - 400: パラメータ不正（日付形式不正、from > to、limit 範囲外）
+//   - 500: 読み取り失敗
+func (h *AutoAnswerHandler) Handle(c *gin.Context) {
+	q := autoanswer.Query{Project: c.Query("project")}
+
+	var err error
+	if q.From, err = parseTimeParam(c.Query("from")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "from の形式が不正です（RFC3339 または YYYY-MM-DD）",
+		})
+		return
+	}
+	if q.To, err = parseTimeParam(c.Query("to")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "to の形式が不正です（RFC3339 または YYYY-MM-DD）",
+		})
+		return
+	}
+	if v := c.Query("limit"); v != "" {
+		if q.Limit, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "limit は整数で指定してください",
+			})
+			return
+		}
+	}
+
+	log.Printf("[AutoAnswerHandler] Handle started: project=%s", q.Project)
+
+	result, err := h.audit.Query(q)
+	if err != nil {
+		log.Printf("[AutoAnswerHandler] Handle failed: error=%v", err)
+		if errors.Is(err, autoanswer.ErrValidation) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "自動回答の記録の読み取りに失敗しました",
+		})
+		return
+	}
+
+	log.Printf("[AutoAnswerHandler] Handle completed: records=%d, total=%d", len(result.Records), result.Total)
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/autoanswer"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoAnswerHandler_Handle(t *testing.T) {
+	audit := autoanswer.NewAuditLog(filepath.Join(t.TempDir(), "auto_answers.jsonl"))
+	at := time.Date(2026, 7, 20, 10, 0, 0, 0, time.UTC)
+	assert.NoError(t, audit.Append(autoanswer.Record{At: at, Project: "/p", Source: autoanswer.SourcePatrol, Rule: "r", Action: autoanswer.ActionAnswer, Answer: "A案"}))
+	assert.NoError(t, audit.Append(autoanswer.Record{At: at.Add(time.Hour), Project: "/q", Source: autoanswer.SourceDashboard, Rule: "e", Action: autoanswer.ActionEscalate}))
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/auto-answers", NewAutoAnswerHandler(audit).Handle)
+
+	tests := []struct {
+		name      string
+		url       string
+		wantCode  int
+		wantTotal int
+	}{
+		{"全件", "/api/auto-answers", http.StatusOK, 2},
+		{"プロジェクト指定", "/api/auto-answers?project=/p", http.StatusOK, 1},
+		{"期間指定", "/api/auto-answers?from=2026-07-20T10:30:00Z", http.StatusOK, 1},
+		{"from不正", "/api/auto-answers?from=yesterday", http.StatusBadRequest, 0},
+		{"limit不正", "/api/auto-answers?limit=abc", http.StatusBadRequest, 0},
+		{"limit範囲外", "/api/auto-answers?limit=5000", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var resp autoanswer.Result
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantTotal, resp.Total)
+		})
+	}
+}
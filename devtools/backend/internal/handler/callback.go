@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"path/filepath"
+
+	"ghostrunner/backend/internal/callback"
+	"ghostrunner/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CallbackHandler は通知のボタンから呼ばれる署名付き・単回使用のコールバックを処理します
+type CallbackHandler struct {
+	callbacks     *callback.Service
+	patrolService service.PatrolService
+}
+
+// NewCallbackHandler は新しいCallbackHandlerを生成します
+func NewCallbackHandler(callbacks *callback.Service, patrolService service.PatrolService) *CallbackHandler {
+	return &CallbackHandler{callbacks: callbacks, patrolService: patrolService}
+}
+
+// CallbackResponse はコールバックのレスポンスです
+type CallbackResponse struct {
+	Success bool   `json:"success"`
+	Project string `json:"project,omitempty"`
+	Answer  string `json:"answer,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Handle はトークンを検証し、承認待ちのプロジェクトをトークンの回答で再開します。
+// トークン自体を認可とするため端末認証の外に置きます。トークンのセッションと質問（見出し・本文・選択肢の
+// 要約値）が現在の承認待ちと一致しない場合（UI で回答済み・同じセッションで別の質問に進んだ等）は
+// 使用済みにせず 409 を返します。
+//
+// レスポンス:
+//   - 200: 再開した
+//   - 401: トークンが不正
+//   - 409: プロジェクトがトークンの質問で承認待ちでない
+//   - 410: トークンの期限切れ・使用済み（同じ質問の他のボタンを含む）
+//
+// POST /api/callbacks/:token
+func (h *CallbackHandler) Handle(c *gin.Context) {
+	claim, err := h.callbacks.Verify(c.Param("token"))
+	if err != nil {
+		h.reject(c, err)
+		return
+	}
+	if claim.Kind != callback.KindAnswer {
+		h.reject(c, callback.ErrInvalidToken)
+		return
+	}
+
+	state, ok := h.patrolService.GetStates()[filepath.Clean(claim.Project)]
+	if !ok || state.Status != service.StatusWaitingApproval || state.SessionID != claim.SessionID ||
+		state.Question == nil || state.Question.CallbackQuestion().Digest() != claim.Question {
+		callbackResults.Inc("stale")
+		log.Printf("[CallbackHandler] Handle rejected: project is no longer waiting for this question, project=%s", claim.Project)
+		c.JSON(http.StatusConflict, CallbackResponse{Success: false, Error: "この質問は既に回答済みか、承認待ちではありません"})
+		return
+	}
+
+	if _, err := h.callbacks.Consume(c.Param("token")); err != nil {
+		h.reject(c, err)
+		return
+	}
+	if err := h.patrolService.ResumeProject(claim.Project, claim.Answer); err != nil {
+		callbackResults.Inc("failed")
+		log.Printf("[CallbackHandler] Handle failed: project=%s, error=%v", claim.Project, err)
+		c.JSON(http.StatusConflict, CallbackResponse{Success: false, Error: err.Error()})
+		return
+	}
+
+	callbackResults.Inc("resumed")
+	log.Printf("[CallbackHandler] Handle completed: project=%s, answer=%s", claim.Project, claim.Answer)
+	c.JSON(http.StatusOK, CallbackResponse{Success: true, Project: claim.Project, Answer: claim.Answer})
+}
+
+// reject は検証エラーに応じたステータスで拒否します
+func (h *CallbackHandler) reject(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, callback.ErrExpired):
+		callbackResults.Inc("expired")
+		c.JSON(http.StatusGone, CallbackResponse{Success: false, Error: "このボタンは期限切れです"})
+	case errors.Is(err, callback.ErrUsed):
+		callbackResults.Inc("used")
+		c.JSON(http.StatusGone, CallbackResponse{Success: false, Error: "この質問には既に回答しました"})
+	default:
+		callbackResults.Inc("invalid")
+		c.JSON(http.StatusUnauthorized, CallbackResponse{Success: false, Error: "トークンが不正です"})
+	}
+	log.Printf("[CallbackHandler] Handle rejected: error=%v", err)
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/callback"
+	"ghostrunner/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testQuestion はテスト用の承認待ちの質問です
+var testQuestion = &service.Question{
+	Header:   "確認",
+	Question: "続けますか？",
+	Options:  []service.Option{{Label: "yes"}, {Label: "no"}},
+}
+
+// newCallbackRouter は質問 current で承認待ちのプロジェクト /p（セッション sess-1）を持つテスト用ルーターを返します
+func newCallbackRouter(t *testing.T, now *time.Time, current *service.Question) (*gin.Engine, *callback.Service, *[]string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	svc, err := callback.NewService("https://mac.example.ts.net:8888", []byte("k"), time.Hour, func() time.Time { return *now })
+	require.NoError(t, err)
+
+	var resumed []string
+	patrol := &mockPatrolService{
+		getStatesFunc: func() map[string]*service.ProjectState {
+			return map[string]*service.ProjectState{
+				"/p": {Status: service.StatusWaitingApproval, SessionID: "sess-1", Question: current},
+			}
+		},
+		resumeProjectFunc: func(projectPath, answer string) error {
+			resumed = append(resumed, projectPath+":"+answer)
+			return nil
+		},
+	}
+	r := gin.New()
+	r.POST("/api/callbacks/:token", NewCallbackHandler(svc, patrol).Handle)
+	return r, svc, &resumed
+}
+
+// postCallback はボタンの URL へ POST してステータスとレスポンスを返します
+func postCallback(t *testing.T, r *gin.Engine, url string) (int, CallbackResponse) {
+	t.Helper()
+	path := url[strings.Index(url, callback.PathPrefix):]
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+	var resp CallbackResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return w.Code, resp
+}
+
+func TestCallbackHandler_Handle(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	t.Run("ボタンの選択肢で再開し、同じ質問の他のボタンは使えない", func(t *testing.T) {
+		r, svc, resumed := newCallbackRouter(t, &now, testQuestion)
+		actions := svc.AnswerActions("/p", "sess-1", testQuestion.CallbackQuestion())
+
+		code, resp := postCallback(t, r, actions[1].URL)
+		assert.Equal(t, http.StatusOK, code)
+		assert.True(t, resp.Success)
+		assert.Equal(t, "no", resp.Answer)
+		assert.Equal(t, []string{"/p:no"}, *resumed)
+
+		code, _ = postCallback(t, r, actions[0].URL)
+		assert.Equal(t, http.StatusGone, code)
+		assert.Len(t, *resumed, 1)
+	})
+
+	t.Run("別のセッションの質問は使用済みにせず409", func(t *testing.T) {
+		r, svc, resumed := newCallbackRouter(t, &now, testQuestion)
+		actions := svc.AnswerActions("/p", "sess-old", testQuestion.CallbackQuestion())
+
+		code, resp := postCallback(t, r, actions[0].URL)
+		assert.Equal(t, http.StatusConflict, code)
+		assert.False(t, resp.Success)
+		assert.Empty(t, *resumed)
+		_, err := svc.Verify(actions[0].URL[strings.LastIndex(actions[0].URL, "/")+1:])
+		assert.NoError(t, err)
+	})
+
+	t.Run("同じセッションで次の質問に進んだ後の前の質問のボタンは409", func(t *testing.T) {
+		// Q1 の通知のボタンを発行した後、Q1 に回答して --resume で同じセッションが Q2 を尋ねている
+		q1 := &service.Question{Header: "DB", Question: "どちらにしますか？", Options: []service.Option{{Label: "A案"}, {Label: "B案"}}}
+		q2 := &service.Question{Header: "API", Question: "どちらにしますか？", Options: []service.Option{{Label: "A案"}, {Label: "B案"}}}
+		r, svc, resumed := newCallbackRouter(t, &now, q2)
+		old := svc.AnswerActions("/p", "sess-1", q1.CallbackQuestion())
+
+		code, resp := postCallback(t, r, old[0].URL)
+		assert.Equal(t, http.StatusConflict, code)
+		assert.False(t, resp.Success)
+		assert.Empty(t, *resumed)
+
+		current := svc.AnswerActions("/p", "sess-1", q2.CallbackQuestion())
+		code, _ = postCallback(t, r, current[1].URL)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"/p:B案"}, *resumed)
+	})
+
+	t.Run("不正・期限切れのトークン", func(t *testing.T) {
+		clock := now
+		r, svc, resumed := newCallbackRouter(t, &clock, testQuestion)
+		actions := svc.AnswerActions("/p", "sess-1", testQuestion.CallbackQuestion())
+
+		code, _ := postCallback(t, r, callback.PathPrefix+"abc.def")
+		assert.Equal(t, http.StatusUnauthorized, code)
+
+		clock = clock.Add(2 * time.Hour)
+		code, _ = postCallback(t, r, actions[0].URL)
+		assert.Equal(t, http.StatusGone, code)
+		assert.Empty(t, *resumed)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 
+	"ghostrunner/backend/internal/events"
 	"ghostrunner/backend/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -48,14 +49,20 @@ type CommandResponse struct {
 // CommandHandler はCommand関連のHTTPハンドラを提供します
 type CommandHandler struct {
 	claudeService   service.ClaudeService
-	ghostrunnerRoot string // initコマンドでproject未指定時に使用
+	ghostrunnerRoot string      // initコマンドでproject未指定時に使用
+	bus             *events.Bus // ストリーム実行のイベントを run.* として発行するバス
 }
 
-// NewCommandHandler は新しいCommandHandlerを生成します
-func NewCommandHandler(claudeService service.ClaudeService, ghostrunnerRoot string) *CommandHandler {
+// NewCommandHandler は新しいCommandHandlerを生成します。
+// bus が nil の場合はハンドラ専用のバスを使います（/api/bus/events へは流れません）。
+func NewCommandHandler(claudeService service.ClaudeService, ghostrunnerRoot string, bus *events.Bus) *CommandHandler {
+	if bus == nil {
+		bus = events.NewBus(nil)
+	}
 	return &CommandHandler{
 		claudeService:   claudeService,
 		ghostrunnerRoot: ghostrunnerRoot,
+		bus:             bus,
 	}
 }
 
@@ -224,22 +231,13 @@ func (h *CommandHandler) HandleStream(c *gin.Context) {
 	// SSEヘッダー設定
 	setSSEHeaders(c)
 
-	// イベントチャンネル作成
-	eventCh := make(chan service.StreamEvent, 100)
-
 	// 画像データを変換
 	serviceImages := toServiceImages(req.Images)
 
-	// ストリーミング実行を開始
-	go func() {
-		err := h.claudeService.ExecuteCommandStream(c.Request.Context(), req.Project, req.Command, req.Args, serviceImages, eventCh)
-		if err != nil {
-			log.Printf("[CommandHandler] HandleStream error: %v", err)
-		}
-	}()
-
-	// イベントをSSEとして送信（selectベースのループで確実にコンテキストキャンセルを検出）
-	writeSSEEvents(c, eventCh, "CommandHandler")
+	// ストリーミング実行を開始し、バス経由でSSEとして送信（selectベースのループで確実にコンテキストキャンセルを検出）
+	streamRun(c, h.bus, RunEvent{Kind: "command", Project: req.Project}, "CommandHandler", func(eventCh chan<- service.StreamEvent) error {
+		return h.claudeService.ExecuteCommandStream(c.Request.Context(), req.Project, req.Command, req.Args, serviceImages, eventCh)
+	})
 
 	log.Printf("[CommandHandler] HandleStream completed: project=%s, command=%s", req.Project, req.Command)
 }
@@ -371,19 +369,10 @@ func (h *CommandHandler) HandleContinueStream(c *gin.Context) {
 	// SSEヘッダー設定
 	setSSEHeaders(c)
 
-	// イベントチャンネル作成
-	eventCh := make(chan service.StreamEvent, 100)
-
-	// ストリーミング実行を開始
-	go func() {
-		err := h.claudeService.ContinueSessionStream(c.Request.Context(), req.Project, req.SessionID, req.Answer, eventCh)
-		if err != nil {
-			log.Printf("[CommandHandler] HandleContinueStream error: %v", err)
-		}
-	}()
-
-	// イベントをSSEとして送信（selectベースのループで確実にコンテキストキャンセルを検出）
-	writeSSEEvents(c, eventCh, "CommandHandler")
+	// ストリーミング実行を開始し、バス経由でSSEとして送信（selectベースのループで確実にコンテキストキャンセルを検出）
+	streamRun(c, h.bus, RunEvent{Kind: "command_continue", Project: req.Project}, "CommandHandler", func(eventCh chan<- service.StreamEvent) error {
+		return h.claudeService.ContinueSessionStream(c.Request.Context(), req.Project, req.SessionID, req.Answer, eventCh)
+	})
 
 	log.Printf("[CommandHandler] HandleContinueStream completed: project=%s, sessionID=%s", req.Project, req.SessionID)
 }
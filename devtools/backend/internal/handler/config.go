@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"ghostrunner/backend/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConfigHandler は起動時に読み込んだサーバー設定を返すHTTPハンドラを提供します
+type ConfigHandler struct {
+	cfg *config.Config
+}
+
+// NewConfigHandler は新しいConfigHandlerを生成します
+func NewConfigHandler(cfg *config.Config) *ConfigHandler {
+	return &ConfigHandler{cfg: cfg}
+}
+
+// Handle は実効設定と各値の出どころを返します。
+//
+// レスポンス:
+//   - 200: 成功 Config（root, listen, projectBaseDir, stateDir, patrolProjects, allowedOrigins, features, file, sources）
+//
+// GET /api/config
+func (h *ConfigHandler) Handle(c *gin.Context) {
+	c.JSON(http.StatusOK, h.cfg)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ghostrunner/backend/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigHandler_Handle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{
+		Root:           "/opt/ghostrunner",
+		Listen:         "127.0.0.1:8888",
+		AllowedOrigins: []string{"http://localhost:3333"},
+		Features:       config.Features{Auth: true},
+		Sources:        map[string]config.Source{"listen": config.SourceFlag},
+	}
+	h := NewConfigHandler(cfg)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/config", nil)
+	h.Handle(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "127.0.0.1:8888", resp["listen"])
+	assert.Equal(t, map[string]any{"listen": "flag"}, resp["sources"])
+	assert.Equal(t, true, resp["features"].(map[string]any)["auth"])
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"time"
 
 	"ghostrunner/backend/internal/dashboard"
@@ -60,34 +61,127 @@ func (h *DashboardHandler) HandleAnswer(c *gin.Context) {
 
 	if err := h.svc.Answer(c.Request.Context(), req); err != nil {
 		log.Printf("[DashboardHandler] HandleAnswer failed: error=%v", err)
+		respondAnswerError(c, err)
+		return
+	}
 
-		if errors.Is(err, dashboard.ErrValidation) {
+	log.Println("[DashboardHandler] HandleAnswer completed")
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// HandleAnswers は1つの計画書の複数の確認事項への回答を一括で処理します。
+// 全件を1回のアトミック書き込みで反映し、1件でも対象が見つからなければ何も書き込みません。
+// POST /api/dashboard/answers
+func (h *DashboardHandler) HandleAnswers(c *gin.Context) {
+	log.Println("[DashboardHandler] HandleAnswers started")
+
+	var req dashboard.BatchAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "リクエストが不正です",
+		})
+		return
+	}
+
+	if err := h.svc.AnswerBatch(c.Request.Context(), req); err != nil {
+		log.Printf("[DashboardHandler] HandleAnswers failed: error=%v", err)
+		respondAnswerError(c, err)
+		return
+	}
+
+	log.Printf("[DashboardHandler] HandleAnswers completed: answers=%d", len(req.Answers))
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"answered": len(req.Answers),
+	})
+}
+
+// respondAnswerError は回答書き戻しのエラーをHTTPステータスに変換して返します
+func respondAnswerError(c *gin.Context, err error) {
+	if errors.Is(err, dashboard.ErrValidation) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if errors.Is(err, dashboard.ErrAlreadyAnswered) {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   "既に回答済みか、行がずれています",
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"success": false,
+		"error":   "回答の書き戻しに失敗しました",
+	})
+}
+
+// HandleAnswerHistory は登録プロジェクトの計画書に記録された回答履歴を新しい順に返します。
+// GET /api/dashboard/answers/history?project=&from=&to=&limit=
+//
+// from / to は回答日時の範囲で、RFC3339 または YYYY-MM-DD（ローカル時刻0時）で指定します。
+// limit は既定100件・上限1000件です。回答日時が記録されていない回答は含みません。
+//
+// レスポンス:
+//   - 200: 成功（dashboard.AnswerHistory）
+//   - 400: パラメータ不正（日付形式不正、from > to、limit 不正、未登録プロジェクト）
+//   - 500: プロジェクト設定の読み込み失敗
+func (h *DashboardHandler) HandleAnswerHistory(c *gin.Context) {
+	q := dashboard.AnswerHistoryQuery{Project: c.Query("project")}
+
+	var err error
+	if q.From, err = parseTimeParam(c.Query("from")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "from の形式が不正です（RFC3339 または YYYY-MM-DD）",
+		})
+		return
+	}
+	if q.To, err = parseTimeParam(c.Query("to")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "to の形式が不正です（RFC3339 または YYYY-MM-DD）",
+		})
+		return
+	}
+	if v := c.Query("limit"); v != "" {
+		if q.Limit, err = strconv.Atoi(v); err != nil || q.Limit < 1 {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"success": false,
-				"error":   err.Error(),
+				"error":   "limit は1以上の整数で指定してください",
 			})
 			return
 		}
+	}
+
+	log.Printf("[DashboardHandler] HandleAnswerHistory started: project=%s, limit=%d", q.Project, q.Limit)
 
-		if errors.Is(err, dashboard.ErrAlreadyAnswered) {
-			c.JSON(http.StatusConflict, gin.H{
+	result, err := h.svc.AnswerHistory(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("[DashboardHandler] HandleAnswerHistory failed: error=%v", err)
+		if errors.Is(err, dashboard.ErrValidation) {
+			c.JSON(http.StatusBadRequest, gin.H{
 				"success": false,
-				"error":   "既に回答済みか、行がずれています",
+				"error":   err.Error(),
 			})
 			return
 		}
-
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
-			"error":   "回答の書き戻しに失敗しました",
+			"error":   "回答履歴の取得に失敗しました",
 		})
 		return
 	}
 
-	log.Println("[DashboardHandler] HandleAnswer completed")
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-	})
+	log.Printf("[DashboardHandler] HandleAnswerHistory completed: answers=%d, total=%d", len(result.Answers), result.Total)
+	c.JSON(http.StatusOK, result)
 }
 
 // HandleStream はダッシュボード状態のSSEストリーミングを提供します。
@@ -109,6 +203,7 @@ func (h *DashboardHandler) HandleStream(c *gin.Context) {
 
 	ch, unsubscribe := h.streamSvc.Subscribe()
 	defer unsubscribe()
+	defer trackSSESubscriber("dashboard")()
 
 	writeDashboardSSEEvents(c, ch)
 
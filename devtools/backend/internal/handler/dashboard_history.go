@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"ghostrunner/backend/internal/history"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DashboardHistoryHandler はダッシュボード状態の時系列を返すHTTPハンドラを提供します
+type DashboardHistoryHandler struct {
+	store history.Store
+}
+
+// NewDashboardHistoryHandler は新しいDashboardHistoryHandlerを生成します
+func NewDashboardHistoryHandler(store history.Store) *DashboardHistoryHandler {
+	return &DashboardHistoryHandler{store: store}
+}
+
+// Handle はダッシュボード状態の時系列と期間集計を返します。
+// GET /api/dashboard/history?project=&from=&to=
+//
+// from / to は RFC3339 または YYYY-MM-DD（ローカル時刻0時）で指定します。
+// to 省略時は現在時刻、from 省略時は to の24時間前です。project 省略時は全プロジェクトです。
+//
+// レスポンス:
+//   - 200: 成功（history.Result）
+//   - 400: パラメータ不正（日付形式不正、from > to、期間31日超）
+//   - 500: 読み取り失敗
+func (h *DashboardHistoryHandler) Handle(c *gin.Context) {
+	q := history.Query{Project: c.Query("project")}
+
+	var err error
+	if q.From, err = parseTimeParam(c.Query("from")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "from の形式が不正です（RFC3339 または YYYY-MM-DD）",
+		})
+		return
+	}
+	if q.To, err = parseTimeParam(c.Query("to")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "to の形式が不正です（RFC3339 または YYYY-MM-DD）",
+		})
+		return
+	}
+
+	log.Printf("[DashboardHistoryHandler] Handle started: project=%s", q.Project)
+
+	result, err := h.store.Query(q)
+	if err != nil {
+		log.Printf("[DashboardHistoryHandler] Handle failed: error=%v", err)
+		if errors.Is(err, history.ErrValidation) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "ダッシュボード履歴の読み取りに失敗しました",
+		})
+		return
+	}
+
+	log.Printf("[DashboardHistoryHandler] Handle completed: samples=%d, projects=%d", len(result.Samples), len(result.Summaries))
+	c.JSON(http.StatusOK, result)
+}
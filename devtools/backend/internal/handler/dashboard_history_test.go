@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/history"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockHistoryStore はテスト用のhistory.Storeモックです
+type mockHistoryStore struct {
+	queryFunc func(q history.Query) (*history.Result, error)
+}
+
+func (m *mockHistoryStore) Record(samples []history.Sample) error {
+	return nil
+}
+
+func (m *mockHistoryStore) Query(q history.Query) (*history.Result, error) {
+	return m.queryFunc(q)
+}
+
+func TestDashboardHistoryHandler_Handle(t *testing.T) {
+	var got history.Query
+	mock := &mockHistoryStore{
+		queryFunc: func(q history.Query) (*history.Result, error) {
+			got = q
+			if q.Project == "/broken" {
+				return nil, fmt.Errorf("boom")
+			}
+			if !q.From.IsZero() && !q.To.IsZero() && q.From.After(q.To) {
+				return nil, fmt.Errorf("%w: from must be before to", history.ErrValidation)
+			}
+			return &history.Result{Samples: []history.Sample{}, Summaries: []history.Summary{}}, nil
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/dashboard/history", NewDashboardHistoryHandler(mock).Handle)
+
+	tests := []struct {
+		name     string
+		url      string
+		wantCode int
+	}{
+		{"正常系", "/api/dashboard/history?project=/p&from=2026-07-01&to=2026-07-02T00:00:00Z", http.StatusOK},
+		{"期間省略", "/api/dashboard/history", http.StatusOK},
+		{"from不正", "/api/dashboard/history?from=yesterday", http.StatusBadRequest},
+		{"to不正", "/api/dashboard/history?to=2026/07/01", http.StatusBadRequest},
+		{"期間逆転", "/api/dashboard/history?from=2026-07-02&to=2026-07-01", http.StatusBadRequest},
+		{"読み取り失敗", "/api/dashboard/history?project=/broken", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+
+	// クエリ解釈を再確認
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/dashboard/history?project=/p&from=2026-07-01", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "/p", got.Project)
+	assert.True(t, got.From.Equal(time.Date(2026, 7, 1, 0, 0, 0, 0, time.Local)))
+	assert.True(t, got.To.IsZero())
+}
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"ghostrunner/backend/internal/dashboard"
 
@@ -19,6 +22,8 @@ import (
 type mockDashboardService struct {
 	getStateFunc func(ctx context.Context) (dashboard.State, error)
 	answerFunc   func(ctx context.Context, req dashboard.AnswerRequest) error
+	batchFunc    func(ctx context.Context, req dashboard.BatchAnswerRequest) error
+	historyFunc  func(ctx context.Context, q dashboard.AnswerHistoryQuery) (*dashboard.AnswerHistory, error)
 }
 
 func (m *mockDashboardService) GetState(ctx context.Context) (dashboard.State, error) {
@@ -35,12 +40,28 @@ func (m *mockDashboardService) Answer(ctx context.Context, req dashboard.AnswerR
 	return nil
 }
 
+func (m *mockDashboardService) AnswerBatch(ctx context.Context, req dashboard.BatchAnswerRequest) error {
+	if m.batchFunc != nil {
+		return m.batchFunc(ctx, req)
+	}
+	return nil
+}
+
+func (m *mockDashboardService) AnswerHistory(ctx context.Context, q dashboard.AnswerHistoryQuery) (*dashboard.AnswerHistory, error) {
+	if m.historyFunc != nil {
+		return m.historyFunc(ctx, q)
+	}
+	return &dashboard.AnswerHistory{Answers: []dashboard.AnsweredQuestion{}}, nil
+}
+
 func setupDashboardRouter(svc dashboard.Service) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
 	h := NewDashboardHandler(svc, nil)
 	r.GET("/api/dashboard/state", h.HandleState)
 	r.POST("/api/dashboard/answer", h.HandleAnswer)
+	r.POST("/api/dashboard/answers", h.HandleAnswers)
+	r.GET("/api/dashboard/answers/history", h.HandleAnswerHistory)
 	return r
 }
 
@@ -197,3 +218,102 @@ func TestDashboardHandler_HandleAnswer_400_InvalidJSON(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, false, resp["success"])
 }
+
+func TestDashboardHandler_HandleAnswers(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "200", wantStatus: http.StatusOK},
+		{name: "400: validation", err: fmt.Errorf("%w: duplicate", dashboard.ErrValidation), wantStatus: http.StatusBadRequest},
+		{name: "409: already answered", err: fmt.Errorf("%w: Q2", dashboard.ErrAlreadyAnswered), wantStatus: http.StatusConflict},
+		{name: "500: write failure", err: errors.New("disk full"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got dashboard.BatchAnswerRequest
+			mock := &mockDashboardService{
+				batchFunc: func(ctx context.Context, req dashboard.BatchAnswerRequest) error {
+					got = req
+					return tt.err
+				},
+			}
+
+			body := `{"projectPath":"/p","planPath":"開発/実装/実装待ち/plan.md","source":"voice",` +
+				`"answers":[{"questionId":"Q1","answer":"A案"},{"lineStart":12,"answer":"B案"}]}`
+
+			r := setupDashboardRouter(mock)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/api/dashboard/answers", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, dashboard.AnswerSourceVoice, got.Source)
+			assert.Equal(t, []dashboard.AnswerItem{{QuestionID: "Q1", Answer: "A案"}, {LineStart: 12, Answer: "B案"}}, got.Answers)
+
+			var resp map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.err == nil, resp["success"])
+		})
+	}
+}
+
+func TestDashboardHandler_HandleAnswerHistory(t *testing.T) {
+	var got dashboard.AnswerHistoryQuery
+	mock := &mockDashboardService{
+		historyFunc: func(ctx context.Context, q dashboard.AnswerHistoryQuery) (*dashboard.AnswerHistory, error) {
+			got = q
+			return &dashboard.AnswerHistory{
+				Answers: []dashboard.AnsweredQuestion{{ProjectName: "p", QuestionID: "Q1", Answer: "A案", Source: dashboard.AnswerSourceNtfy}},
+				Total:   1,
+			}, nil
+		},
+	}
+
+	r := setupDashboardRouter(mock)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/dashboard/answers/history?project=/p&from=2026-07-01&limit=10", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/p", got.Project)
+	assert.Equal(t, 10, got.Limit)
+	assert.Equal(t, time.Date(2026, 7, 1, 0, 0, 0, 0, time.Local), got.From)
+
+	var resp dashboard.AnswerHistory
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Total)
+	assert.Equal(t, "Q1", resp.Answers[0].QuestionID)
+}
+
+func TestDashboardHandler_HandleAnswerHistory_400(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		err   error
+	}{
+		{name: "limit 不正", query: "?limit=abc"},
+		{name: "from 不正", query: "?from=yesterday"},
+		{name: "service validation", query: "?project=/unknown", err: fmt.Errorf("%w: project not in allowed list", dashboard.ErrValidation)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockDashboardService{
+				historyFunc: func(ctx context.Context, q dashboard.AnswerHistoryQuery) (*dashboard.AnswerHistory, error) {
+					return nil, tt.err
+				},
+			}
+
+			r := setupDashboardRouter(mock)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/api/dashboard/answers/history"+tt.query, nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
@@ -17,7 +17,19 @@
 //   - CreateHandler: /api/projects/validate, /api/projects/create/stream, /api/projects/open を処理（プロジェクト生成）
 //   - PatrolHandler: /api/patrol 関連のエンドポイントを処理（複数プロジェクト自動巡回）
 //   - DashboardHandler: /api/dashboard 関連のエンドポイントを処理（統括GUIダッシュボード状態集約・回答書き戻し）
+//   - DashboardHistoryHandler: /api/dashboard/history エンドポイントを処理（ダッシュボード状態の時系列）
+//   - TasksHandler: /api/tasks 関連のエンドポイントを処理（カンバンのタスク作成・更新・移動・並び替え）
+//   - MetricsHandler: /metrics エンドポイントを処理（Prometheus テキスト形式の運用メトリクス）
 //   - TTSHandler: /api/tts エンドポイントを処理（VOICEVOXによるテキスト音声合成）
+//   - AnalyticsHandler: /api/analytics エンドポイントを処理（会話ログのセッション分析）
+//   - ReportHandler: /api/reports/digest エンドポイントを処理（日次・週次の活動ダイジェスト）
+//   - SearchHandler: /api/search エンドポイントを処理（会話ログと開発ドキュメントの全文検索）
+//   - SessionsHandler: /api/sessions 関連のエンドポイントを処理（会話ログのエクスポート）
+//   - AutoAnswerHandler: /api/auto-answers エンドポイントを処理（質問の自動回答の監査ログ）
+//   - EventsHandler: /api/bus/events, /api/bus/events/ws エンドポイントを処理（サーバー内イベントの購読）
+//   - AuthHandler: /api/auth 関連のエンドポイントとスコープ検査のミドルウェア（端末のペアリング・失効）
+//   - ConfigHandler: /api/config エンドポイントを処理（起動時に読み込んだサーバー設定）
+//   - CallbackHandler: /api/callbacks/:token エンドポイントを処理（通知の回答ボタン）
 //
 // ClaudeServiceへの依存性注入によりテスタビリティを確保する。
 //
@@ -54,7 +66,8 @@
 //
 // # ProjectsHandler
 //
-// /Users/user/ 直下のディレクトリ一覧を取得するエンドポイントと、
+// プロジェクトのベースディレクトリ（サーバー設定の projectBaseDir / GHOSTRUNNER_PROJECT_BASE_DIR、
+// 既定はホームディレクトリ）直下のディレクトリ一覧を取得するエンドポイントと、
 // プロジェクトディレクトリを削除するエンドポイントを提供する。
 // フロントエンドのProjectPath選択ドロップダウンの候補を提供する。
 // 外部サービスへの依存がなく、ローカルファイルシステムのみを参照する。
@@ -107,14 +120,41 @@
 //
 // 統括GUIダッシュボードのエンドポイント群を処理するハンドラー。
 // dashboardパッケージのServiceインターフェース（状態集約・回答書き戻し）と
-// Streamインターフェース（SSE配信）に依存し、状態集約・回答書き戻し・SSEストリーミングの
-// 3エンドポイントを提供する。
+// Streamインターフェース（SSE配信）に依存し、状態集約・回答書き戻し（単発・一括）・回答履歴・
+// SSEストリーミングのエンドポイントを提供する。
 //
 // エンドポイント:
 //   - GET /api/dashboard/state: 全プロジェクトの集約状態取得
 //   - POST /api/dashboard/answer: 確認事項への回答書き戻し
+//   - POST /api/dashboard/answers: 1つの計画書の複数の確認事項への一括回答（全件適用か無変更）
+//   - GET /api/dashboard/answers/history: 計画書に記録された回答（回答日時・回答元付き）の履歴
 //   - GET /api/dashboard/stream: ダッシュボード状態のSSEストリーミング（Stateスナップショット配信）
 //
+// # DashboardHistoryHandler
+//
+// StreamServiceが記録したダッシュボード状態の時系列を返すハンドラー。
+// historyパッケージのStoreインターフェースに依存する。
+//
+// エンドポイント:
+//   - GET /api/dashboard/history: プロジェクトごとのサンプル列と期間集計（完了数・質問待ち/未回答の継続時間）
+//
+// # TasksHandler
+//
+// カンバン（開発/実装 配下のレーン）のタスクファイルを操作するハンドラー。
+// tasksパッケージのServiceインターフェースに依存し、ErrValidation / ErrNotFound / ErrConflict を
+// 400 / 404 / 409 に変換する。etag はボディの etag または If-Match ヘッダーで受け取り、
+// 取得・作成・更新の応答では ETag ヘッダーにも載せる。
+//
+// エンドポイント:
+//   - GET /api/tasks: 全レーンのタスク一覧
+//   - GET /api/tasks/templates: 作成に使えるテンプレート一覧
+//   - GET /api/tasks/content: タスク本文の取得
+//   - POST /api/tasks/create: テンプレートからタスク作成
+//   - POST /api/tasks/update: タスク本文の更新（etag 必須）
+//   - POST /api/tasks/move: レーン間の移動
+//   - POST /api/tasks/reorder: レーン内の並び替え
+//   - POST /api/tasks/delete: タスク削除（etag 必須）
+//
 // # TTSHandler
 //
 // VOICEVOXエンジンを使ったテキスト音声合成のエンドポイントを処理するハンドラー。
@@ -122,12 +162,94 @@
 //
 // エンドポイント:
 //   - POST /api/tts: テキストを音声合成しWAVバイナリを返却
+//   - GET /api/tts/speakers: VOICEVOX の話者・スタイル一覧
+//   - GET/POST /api/tts/preset: 要求元端末の声質プリセットの取得・保存
 //
 // HandleSynthesize がリクエストのバリデーション（テキスト空チェック等）を行い、
 // サービス層のエラーをHTTPステータスコード（400/429/502/504）にマッピングして返却する。
 // 成功時はContent-Type: audio/wav でWAVバイナリをレスポンスボディに書き込む。
 // X-TTS-Cache ヘッダーでキャッシュヒット有無を通知する。
 //
+// # AnalyticsHandler
+//
+// 会話ログ（~/.claude/projects/ 配下の JSONL）から集計したセッション分析を返すハンドラー。
+// analyticsパッケージのServiceインターフェースに依存する。
+//
+// エンドポイント:
+//   - GET /api/analytics: トークン使用量・ツール利用/エラー率・ターンレイテンシ・経過時間の集計
+//
+// # ReportHandler
+//
+// 完了タスク・要確認の実行・質問と回答・トークン使用量・停滞した運用をまとめた活動ダイジェストを返すハンドラー。
+// reportパッケージのGeneratorに依存する。定期配信（report.Scheduler）とは同じGeneratorを共有する。
+//
+// エンドポイント:
+//   - GET /api/reports/digest: 日次・週次のダイジェストを JSON / Markdown / HTML で返却
+//
+// # SearchHandler
+//
+// 会話ログと各プロジェクトの 開発/ 配下の Markdown を横断する全文検索のハンドラー。
+// searchパッケージのServiceインターフェースに依存する。索引の対象フォルダは DevFolders と同じ。
+//
+// エンドポイント:
+//   - GET /api/search: 検索語で索引を検索しスニペットとリンク付きで返却
+//
+// # SessionsHandler
+//
+// 会話ログ1セッションを共有用に書き出すハンドラー。exportパッケージのExporterに依存し、
+// gr-transcript export CLI と同じ整形・伏せ字処理を行う。
+//
+// エンドポイント:
+//   - GET /api/exports/sessions/:id: Markdown / 単一HTML / JSON でエクスポート（秘匿値は伏せ字）
+//
+// # AutoAnswerHandler
+//
+// 質問の自動回答（autoanswer パッケージ）の監査ログを返すハンドラー。ルールの照合と回答は
+// PatrolService と dashboard.Service が行い、本ハンドラーは記録の照会のみを担当する。
+//
+// エンドポイント:
+//   - GET /api/auto-answers: 自動回答・エスカレーションの記録を新しい順に返却
+//
+// # EventsHandler
+//
+// events.Bus に集約したサーバー内イベント（run.* / patrol.* / dashboard.state / task.* / tts.*）を
+// トピックで絞り込んで配信するハンドラー。購読者ごとのバッファと背圧ポリシー（policy）をクエリで指定できる。
+// コマンド・計画のストリーム実行（streamRun）もバスへ run.* を発行し、自分の実行のイベントだけを
+// 購読して従来形式の SSE を返すアダプタになっている。
+//
+// エンドポイント:
+//   - GET /api/bus/events: SSE（event: にトピック、data: に Event JSON）
+//   - GET /api/bus/events/ws: WebSocket（Event JSON のテキストフレーム）
+//
+// /api/events はフロントエンドの rewrite で even-terminal へ転送されるため、/api/bus 配下に置く。
+//
+// # AuthHandler
+//
+// auth パッケージの端末トークンでAPIを保護するハンドラー。Require(scope) をルートまたはグループの
+// ミドルウェアとして付け、read（GET 全般）/ execute（実行・変更）/ destroy（削除・端末管理）を検査する。
+// プロキシを経由しないループバックからの要求はホスト本人として全スコープを許可し、ペアリングコードの発行はホストのみ行える。
+// DeviceID は Require を通過した要求の端末IDを返し、端末ごとの設定（TTS の声質プリセット等）のキーに使う。
+//
+// エンドポイント:
+//   - POST /api/auth/pairing: ペアリングコードの発行（ホストのみ）
+//   - POST /api/auth/pair: コードと端末トークンの交換（認証不要）
+//   - GET /api/auth/me: 要求元の端末
+//   - GET /api/auth/devices, POST /api/auth/devices/revoke: 端末の一覧・失効（destroy）
+//
+// # ConfigHandler
+//
+// config パッケージが起動時に読み込み・検証したサーバー設定をそのまま返すハンドラー。
+// 値の変更は受け付けず、反映には再起動が必要。
+//
+// # CallbackHandler
+//
+// 承認待ちの通知に付けた回答ボタンから呼ばれるハンドラー。callback パッケージの署名付きトークンを
+// 認可として端末認証の外に置き、トークンのセッションが現在の承認待ちと一致する場合だけ
+// トークンを使用済みにしてその選択肢で PatrolService.ResumeProject を呼ぶ。
+//
+// エンドポイント:
+//   - POST /api/callbacks/:token: 回答して再開（401 不正、409 承認待ちでない、410 期限切れ・使用済み）
+//
 // # PlanHandler
 //
 // Claude CLIの /plan コマンドを実行するエンドポイント群。
@@ -146,6 +268,12 @@
 // 外部サービスへの依存がなく、サーバープロセスが起動していれば常に成功を返す。
 // Cloud Runやロードバランサーのヘルスチェックに使用する。
 //
+// # MetricsHandler
+//
+// metricsパッケージのRegistryに登録された運用メトリクスをPrometheusテキスト形式で返すハンドラー。
+// Claude実行数・巡回スロット・ダッシュボードのスキャン時間・キャッシュヒット・SSE接続数などを公開する。
+// SSE接続数（ghostrunner_sse_subscribers）は本パッケージの各HandleStream / writeSSEEventsで計測する。
+//
 // # エンドポイント一覧
 //
 // ## Health API (ヘルスチェック)
@@ -158,6 +286,32 @@
 //	    "status": "ok"
 //	}
 //
+// ## Auth API (端末認証)
+//
+// POST /api/auth/pairing {"scope": "execute"} - ホストでペアリングコードを発行（{"code": "K7QM-3XPA", "scope": "execute", "expiresAt": "..."}）
+// POST /api/auth/pair {"code": "K7QM-3XPA", "name": "iPhone"} - コードをトークンと交換（{"token": "grt_...", "device": {...}}）
+// GET /api/auth/me - 要求元の端末
+// GET /api/auth/devices - 登録端末の一覧
+// POST /api/auth/devices/revoke {"id": "..."} - 端末の失効
+//
+// トークンは Authorization: Bearer <token>（SSE / WebSocket の購読ルートに限り ?access_token=。他のルートではクエリのトークンを無視する）で送る。
+// トークンが無い・無効は 401、スコープ不足とホスト以外からのコード発行は 403。
+//
+// ## Config API (サーバー設定)
+//
+// GET /api/config - 実効設定と各値の出どころ（{"listen": "0.0.0.0:8888", ..., "sources": {"listen": "default"}}）
+//
+// ## Callbacks API (通知の回答ボタン)
+//
+// POST /api/callbacks/:token - ボタンの選択肢で回答して巡回を再開（{"success": true, "project": "...", "answer": "B案"}）
+//
+// ## Metrics API (運用メトリクス)
+//
+// GET /metrics - Prometheus テキスト形式（text/plain; version=0.0.4）の運用メトリクス
+//
+// /api 配下ではなくルート直下に置く（Prometheus の既定のスクレイプパス）。
+// 端末認証を有効にした場合は /api と同じく read スコープのトークンが必要（プロキシを経由しないループバックは不要）。
+//
 // ## Files API (ファイル一覧取得)
 //
 // GET /api/files - 開発フォルダ内のmdファイル一覧取得
@@ -349,21 +503,189 @@
 //	    "projectPath": "/path/to/project",
 //	    "planPath": "開発/実装/実行中/plan.md",
 //	    "lineStart": 42,
-//	    "answer": "A案で進めてください"
+//	    "answer": "A案で進めてください",
+//	    "source": "dashboard"             // 任意: dashboard（既定）/ voice / ntfy
 //	}
 //
+// questionId（例: "Q1"）を指定すると lineStart より優先して ID で確認事項を特定する。
+// 回答行の直後に「**回答日時**: <RFC3339>」「**回答元**: <source>」を書き込む。
+//
 // レスポンス:
 //
 //	{
 //	    "success": true
 //	}
 //
+// POST /api/dashboard/answers - 1つの計画書の複数の確認事項への一括回答
+//
+// 全件を1回のアトミック書き込みで反映する。1件でも対象が見つからない（回答済・行ずれ）場合は409、
+// 同じ確認事項を重複指定した場合は400で、いずれも計画書は変更しない。回答は最大50件。
+//
+// リクエスト:
+//
+//	{
+//	    "projectPath": "/path/to/project",
+//	    "planPath": "開発/実装/実装待ち/plan.md",
+//	    "source": "voice",
+//	    "answers": [
+//	        {"questionId": "Q1", "answer": "A案で"},
+//	        {"lineStart": 42, "answer": "RESTで"}
+//	    ]
+//	}
+//
+// レスポンス:
+//
+//	{
+//	    "success": true,
+//	    "answered": 2
+//	}
+//
+// GET /api/dashboard/answers/history?project=&from=&to=&limit= - 回答履歴
+//
+// 登録プロジェクトの 開発/実装 配下（レビュー・実装待ち・実行中・完了・アーカイブ）の計画書から、
+// 回答日時が記録された回答済の確認事項を新しい順に返す。limit は既定100件・上限1000件。
+//
+// レスポンス:
+//
+//	{
+//	    "answers": [{"projectName": "shop", "projectPath": "/path/to/shop", "planPath": "開発/実装/完了/plan.md",
+//	                 "questionId": "Q1", "heading": "### Q1: DB", "questionText": "...", "answer": "A案で",
+//	                 "answeredAt": "2026-07-20T10:00:00+09:00", "source": "voice", "line": 12}],
+//	    "total": 1
+//	}
+//
+// ## Events API (サーバー内イベントの購読)
+//
+// GET /api/bus/events?topics=run.*,task.*&policy=drop_newest&buffer=64 - トピックで絞り込んだイベントのSSE
+// GET /api/bus/events/ws?topics=... - 同じイベントの WebSocket 配信
+//
+// SSE フレーム:
+//
+//	id: 42
+//	event: task.moved
+//	data: {"id": 42, "topic": "task.moved", "time": "...", "data": {"projectPath": "/p", "lane": "reviewing", "name": "a.md", "from": "waiting"}}
+//
+// topics の系統が未知・書式不正、policy が未知または block（プロセス内の購読者専用）、buffer が 1〜1024 の範囲外の場合は 400。
+//
 // GET /api/dashboard/stream - ダッシュボード状態のSSEストリーミング
 //
 // 状態に実変化があるたびに State スナップショット全体（/api/dashboard/state と同一構造）を
 // text/event-stream で配信する。generatedAt や経過時間は差分判定に含めず、projects の実変化のみを
 // トリガーとする。15秒ごとにキープアライブコメントを送る。
 //
+// GET /api/dashboard/history?project=&from=&to= - ダッシュボード状態の時系列
+//
+// project 省略時は全プロジェクト。from / to は RFC3339 または YYYY-MM-DD で、to 省略時は現在、
+// from 省略時は to の24時間前。期間は最大31日（超過・from > to は400）。
+//
+// レスポンス:
+//
+//	{
+//	    "from": "2026-07-19T12:00:00+09:00",
+//	    "to": "2026-07-20T12:00:00+09:00",
+//	    "samples": [{"t": "...", "project": "/path/to/project", "kanban": {...}, "idle": true, "unanswered": 1}],
+//	    "summaries": [{"project": "/path/to/project", "samples": 12, "idleSeconds": 600, "completed": 3, ...}]
+//	}
+//
+// ## Tasks API (カンバンのタスク管理)
+//
+// GET /api/tasks?project= - 全レーン（reviewing / waiting / running / done / archived）のタスク一覧
+//
+// GET /api/tasks/content?project=&lane=&name= - タスク本文の取得（ETag ヘッダー付き）
+//
+// POST /api/tasks/create - テンプレートからタスク作成
+//
+// リクエスト:
+//
+//	{
+//	    "projectPath": "/path/to/project",
+//	    "lane": "waiting",
+//	    "title": "質問待ち検知",
+//	    "body": "概要",
+//	    "template": "plan"
+//	}
+//
+// POST /api/tasks/update - タスク本文の更新（etag 不一致は409）
+//
+//	{"projectPath": "...", "lane": "waiting", "name": "2026-07-20_質問待ち検知_plan.md", "content": "...", "etag": "3f2a..."}
+//
+// POST /api/tasks/move - レーン間の移動（移動先に同名があれば409）
+//
+//	{"projectPath": "...", "lane": "waiting", "name": "...", "to": "running", "position": 0}
+//
+// POST /api/tasks/reorder - レーン内の並び替え
+//
+//	{"projectPath": "...", "lane": "waiting", "names": ["b.md", "a.md"]}
+//
+// POST /api/tasks/delete - タスク削除（etag 必須）
+//
+// ## Analytics API (セッション分析)
+//
+// GET /api/analytics?project=&bucket=day|week&from=&to= - セッション分析の集計
+//
+// project 省略時は全登録プロジェクト、bucket 省略時は day。from / to は RFC3339 または
+// YYYY-MM-DD で、セッション開始時刻で絞り込む。
+//
+// レスポンス:
+//
+//	{
+//	    "bucket": "day",
+//	    "total": {"sessions": 3, "turns": 12, "tokens": {...}, "tools": {...}, "toolErrorRate": 0.05, ...},
+//	    "projects": [{"name": "...", "path": "...", "total": {...}, "buckets": [...], "sessions": [...]}],
+//	    "generatedAt": "2026-07-03T12:00:00+09:00"
+//	}
+//
+// ## Reports API (活動ダイジェスト)
+//
+// GET /api/reports/digest?period=day|week&project=&to=&format=json|md|html - 活動ダイジェスト
+//
+// period 省略時は day（直近24時間）、week は直近7日。to（期間の終わり）は RFC3339 または YYYY-MM-DD で、
+// 省略時は現在時刻。内容の無いプロジェクトは含めない。
+//
+// レスポンス（format=json）:
+//
+//	{
+//	    "period": "day",
+//	    "from": "2026-10-18T18:00:00+09:00",
+//	    "to": "2026-10-19T18:00:00+09:00",
+//	    "totals": {"completed": 2, "problems": 1, "questionsAsked": 3, "questionsAnswered": 2, "runs": 4, "staleOps": 1, ...},
+//	    "projects": [{"name": "...", "path": "...", "completed": [...], "problems": [...], "answered": [...], "runs": {...}, "usage": {...}, "staleOps": [...]}],
+//	    "generatedAt": "2026-10-19T18:00:00+09:00"
+//	}
+//
+// ## Search API (全文検索)
+//
+// GET /api/search?q=&project=&kind=session|doc&limit= - 会話ログと開発ドキュメントの全文検索
+//
+// レスポンス:
+//
+//	{
+//	    "query": "webhook",
+//	    "total": 3,
+//	    "hits": [{"kind": "session", "projectPath": "...", "sessionId": "...", "title": "...", "snippet": "…webhook…", "score": 2.1, "link": "..."}],
+//	    "indexed": 412,
+//	    "indexedAt": "2026-07-03T12:00:00+09:00"
+//	}
+//
+// ## Sessions API (会話ログのエクスポート)
+//
+// GET /api/exports/sessions/:id?format=md|html|json&download=1 - 会話ログのエクスポート
+//
+// format 省略時は md。ツール呼び出しは <details> で折りたたみ、会話中で参照された 開発/実装 配下の
+// タスクファイルを埋め込む。.env 値・API キーらしい文字列は [REDACTED] に置換する。
+// download=1 で Content-Disposition: attachment を付与する。
+//
+// ## Auto Answer API (質問の自動回答)
+//
+// GET /api/auto-answers?project=&from=&to=&limit= - 自動回答・エスカレーションの監査ログ
+//
+// レスポンス:
+//
+//	{
+//	    "records": [{"at": "...", "project": "/p", "source": "patrol", "rule": "recommended", "action": "answer", "question": "...", "answer": "B案"}],
+//	    "total": 1
+//	}
+//
 // ## TTS API (テキスト音声合成)
 //
 // POST /api/tts - テキストをVOICEVOXで音声合成
@@ -371,9 +693,13 @@
 // リクエスト:
 //
 //	{
-//	    "text": "こんにちは、世界"
+//	    "text": "こんにちは、世界",
+//	    "speakerId": 8,
+//	    "voice": {"speedScale": 1.2}
 //	}
 //
+// speakerId と voice は省略可。省略した項目は要求元端末の声質プリセットで補う。
+//
 // レスポンス: Content-Type: audio/wav でWAVバイナリを返却
 // ヘッダー: X-TTS-Cache: hit|miss
 //
@@ -439,6 +765,8 @@
 //
 //   - 200 OK: 正常完了
 //   - 400 Bad Request: リクエスト不正、バリデーションエラー、許可されていないコマンド
+//   - 401 Unauthorized: 端末トークンが無い・無効
+//   - 403 Forbidden: 端末トークンのスコープ不足
 //   - 404 Not Found: リソースが存在しない（/api/filesで開発ディレクトリが存在しない場合、/api/projects/destroyで対象ディレクトリが存在しない場合）
 //   - 500 Internal Server Error: Claude CLI実行エラー、ファイルシステムエラー、API呼び出しエラー
 //   - 503 Service Unavailable: サービス利用不可（OpenAI API キー未設定など）
@@ -454,19 +782,48 @@
 //
 // ハンドラーの初期化とルーティング:
 //
-//	ntfyService := service.NewNtfyService()
-//	claudeService := service.NewClaudeService(ntfyService)
+//	notifyConfig, _ := notify.LoadConfig(cfg.StatePath(notify.DefaultConfigFile), os.Getenv, exec.LookPath)
+//	notifyRegistry, _ := notify.NewRegistry(notifyConfig)
+//	claudeService := service.NewClaudeService(notifyRegistry)
+//
+//	// AuthHandler（/api/health と /api/auth/pair 以外は read 以上、変更は execute、削除は destroy）
+//	cfg, _ := config.Load(os.Args[1:], os.Getenv, homeDir, wd)
+//	authConfig := auth.Config{
+//	    Disabled:      !cfg.Features.Auth,
+//	    TrustLoopback: cfg.Features.TrustLoopback,
+//	    DevicesPath:   cfg.StatePath("devices.json"),
+//	}
+//	authService, _ := auth.NewService(authConfig.DevicesPath, time.Now)
+//	authHandler := handler.NewAuthHandler(authService, authConfig)
+//	execute := authHandler.Require(auth.ScopeExecute)
+//	destroy := authHandler.Require(auth.ScopeDestroy)
+//	public := router.Group("/api")
+//	public.POST("/auth/pair", authHandler.HandlePair)
+//	api := router.Group("/api", authHandler.Require(auth.ScopeRead))
+//	api.POST("/auth/pairing", authHandler.HandleStartPairing)
+//	api.GET("/auth/devices", destroy, authHandler.HandleDevices)
+//
+//	// ConfigHandler
+//	configHandler := handler.NewConfigHandler(cfg)
+//	api.GET("/config", configHandler.Handle)
+//
+//	// CallbackHandler（publicURL がある場合だけ巡回の通知にボタンを付ける）
+//	callbackService, _ := callback.NewService(cfg.PublicURL, nil, callback.DefaultTTL, time.Now)
+//	patrolService := service.NewPatrolService(claudeService, notifyRegistry, patrolConfigPath,
+//	    service.WithAnswerActions(callbackService))
+//	callbackHandler := handler.NewCallbackHandler(callbackService, patrolService)
+//	public.POST("/callbacks/:token", callbackHandler.Handle)
 //
 //	// CommandHandler
-//	commandHandler := handler.NewCommandHandler(claudeService)
-//	api := router.Group("/api")
-//	api.POST("/command", commandHandler.Handle)
-//	api.POST("/command/stream", commandHandler.HandleStream)
-//	api.POST("/command/continue", commandHandler.HandleContinue)
-//	api.POST("/command/continue/stream", commandHandler.HandleContinueStream)
+//	eventBus := events.NewBus(time.Now)
+//	commandHandler := handler.NewCommandHandler(claudeService, ghostrunnerRoot, eventBus)
+//	api.POST("/command", execute, commandHandler.Handle)
+//	api.POST("/command/stream", execute, commandHandler.HandleStream)
+//	api.POST("/command/continue", execute, commandHandler.HandleContinue)
+//	api.POST("/command/continue/stream", execute, commandHandler.HandleContinueStream)
 //
 //	// PlanHandler (後方互換性)
-//	planHandler := handler.NewPlanHandler(claudeService)
+//	planHandler := handler.NewPlanHandler(claudeService, eventBus)
 //	api.POST("/plan", planHandler.Handle)
 //	api.POST("/plan/stream", planHandler.HandleStream)
 //	api.POST("/plan/continue", planHandler.HandleContinue)
@@ -477,9 +834,9 @@
 //	api.GET("/files", filesHandler.Handle)
 //
 //	// ProjectsHandler
-//	projectsHandler := handler.NewProjectsHandler()
+//	projectsHandler := handler.NewProjectsHandler(cfg.PatrolProjects, cfg.ProjectBaseDir)
 //	api.GET("/projects", projectsHandler.Handle)
-//	api.POST("/projects/destroy", projectsHandler.HandleDestroy)
+//	api.POST("/projects/destroy", destroy, projectsHandler.HandleDestroy)
 //
 //	// OpenAIHandler
 //	openaiService := service.NewOpenAIService()
@@ -515,14 +872,33 @@
 //
 //	// DashboardHandler
 //	dashboardService := dashboard.NewService(patrolConfigPath, ghostrunnerRoot, idleReader)
-//	dashboardStream := dashboard.NewStreamService(dashboardService)
+//	historyStore := history.NewStore(historyDir, time.Now)
+//	dashboardStream := dashboard.NewStreamService(dashboardService, dashboard.WithRecorder(historyStore))
 //	dashboardHandler := handler.NewDashboardHandler(dashboardService, dashboardStream)
+//	dashboardHistoryHandler := handler.NewDashboardHistoryHandler(historyStore)
 //	dash := api.Group("/dashboard")
 //	dash.GET("/state", dashboardHandler.HandleState)
 //	dash.POST("/answer", dashboardHandler.HandleAnswer)
+//	dash.POST("/answers", dashboardHandler.HandleAnswers)
+//	dash.GET("/answers/history", dashboardHandler.HandleAnswerHistory)
 //	dash.GET("/stream", dashboardHandler.HandleStream)
+//	dash.GET("/history", dashboardHistoryHandler.Handle)
+//
+//	// AutoAnswerHandler
+//	autoAnswerAudit := autoanswer.NewAuditLog(auditPath)
+//	autoAnswerHandler := handler.NewAutoAnswerHandler(autoAnswerAudit)
+//	api.GET("/auto-answers", autoAnswerHandler.Handle)
+//
+//	// EventsHandler（allowedOrigin は CORS と同じ接続元判定）
+//	eventsHandler := handler.NewEventsHandler(eventBus, cfg.AllowOrigin)
+//	api.GET("/bus/events", eventsHandler.HandleStream)
+//	api.GET("/bus/events/ws", eventsHandler.HandleWebSocket)
 //
 //	// HealthHandler
 //	healthHandler := handler.NewHealthHandler()
 //	api.GET("/health", healthHandler.Handle)
+//
+//	// MetricsHandler（/api 配下ではなくルート直下。CORS の後に登録し、read スコープを要求）
+//	metricsHandler := handler.NewMetricsHandler(metrics.Default)
+//	r.GET("/metrics", authHandler.Require(auth.ScopeRead), metricsHandler.Handle)
 package handler
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"ghostrunner/backend/internal/events"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// maxEventsBuffer は /api/bus/events で指定できる購読バッファの上限です
+	maxEventsBuffer = 1024
+	// wsWriteTimeout は WebSocket の1フレームの書き込み期限です
+	wsWriteTimeout = 10 * time.Second
+	// wsPongTimeout は ping に対する pong をこの時間受け取れなければ切断とみなす期限です
+	wsPongTimeout = 2 * sseKeepaliveInterval
+)
+
+// EventsHandler はサーバー内イベントバスの購読API（SSE / WebSocket）を提供します
+type EventsHandler struct {
+	bus      *events.Bus
+	upgrader websocket.Upgrader
+}
+
+// NewEventsHandler は新しいEventsHandlerを生成します。
+// allowOrigin は WebSocket 接続元の Origin を許可するかの判定で、nil の場合は同一オリジンのみ許可します。
+func NewEventsHandler(bus *events.Bus, allowOrigin func(origin string) bool) *EventsHandler {
+	h := &EventsHandler{bus: bus}
+	if allowOrigin != nil {
+		h.upgrader.CheckOrigin = func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			return origin == "" || allowOrigin(origin)
+		}
+	}
+	return h
+}
+
+// subscribeOptions はクエリ（topics / policy / buffer）から購読設定を組み立てます
+func subscribeOptions(c *gin.Context, name string) (events.Options, error) {
+	topics, err := events.ParseTopics(c.Query("topics"))
+	if err != nil {
+		return events.Options{}, err
+	}
+	policy, err := events.ParsePolicy(c.Query("policy"), events.PolicyDropNewest)
+	if err != nil {
+		return events.Options{}, err
+	}
+	if !slices.Contains(events.ExternalPolicies, policy) {
+		return events.Options{}, fmt.Errorf("%w: policy %s is not allowed for external subscribers", events.ErrValidation, policy)
+	}
+	buffer := events.DefaultBuffer
+	if raw := c.Query("buffer"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxEventsBuffer {
+			return events.Options{}, fmt.Errorf("%w: buffer must be 1-%d: %q", events.ErrValidation, maxEventsBuffer, raw)
+		}
+		buffer = n
+	}
+	return events.Options{Topics: topics, Buffer: buffer, Policy: policy, Name: name}, nil
+}
+
+// respondSubscribeError は購読設定のエラーを返します
+func respondSubscribeError(c *gin.Context, err error) {
+	if errors.Is(err, events.ErrValidation) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"success": false,
+		"error":   "イベントの購読に失敗しました",
+	})
+}
+
+// HandleStream はトピックで絞り込んだイベントをSSEで配信します。
+// 各イベントは id: / event: <トピック> / data: <Event JSON> の1フレームです。
+// GET /api/bus/events?topics=run.*,dashboard.state&policy=drop_oldest&buffer=64
+func (h *EventsHandler) HandleStream(c *gin.Context) {
+	opts, err := subscribeOptions(c, "events")
+	if err != nil {
+		log.Printf("[EventsHandler] HandleStream rejected: error=%v", err)
+		respondSubscribeError(c, err)
+		return
+	}
+	log.Printf("[EventsHandler] HandleStream started: topics=%v, policy=%s, buffer=%d", opts.Topics, opts.Policy, opts.Buffer)
+
+	setSSEHeaders(c)
+
+	ch, unsubscribe := h.bus.Subscribe(opts)
+	defer unsubscribe()
+	defer trackSSESubscriber("events")()
+
+	writeBusSSEEvents(c, ch)
+
+	log.Println("[EventsHandler] HandleStream completed")
+}
+
+// writeBusSSEEvents はバスのイベントを event: にトピックを付けたSSE形式で送信します。
+// チャネルが閉じられた（サーバー停止・背圧による切断）場合はストリームを終えます。
+func writeBusSSEEvents(c *gin.Context, ch <-chan events.Event) {
+	w := c.Writer
+	flusher, ok := w.(interface{ Flush() })
+	if !ok {
+		log.Printf("[EventsHandler] ResponseWriter does not support Flush")
+		return
+	}
+
+	// 最初のイベントを待たずにヘッダーを送り、接続の確立をクライアントへ知らせる
+	c.Writer.WriteHeaderNow()
+	flusher.Flush()
+
+	keepalive := time.NewTicker(sseKeepaliveInterval)
+	defer keepalive.Stop()
+
+	ctx := c.Request.Context()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("[EventsHandler] Client disconnected (context canceled)")
+			return
+
+		case ev, ok := <-ch:
+			if !ok {
+				log.Printf("[EventsHandler] Event channel closed, stream completed")
+				return
+			}
+
+			data, err := json.Marshal(ev)
+			if err != nil {
+				log.Printf("[EventsHandler] Marshal error: topic=%s, error=%v", ev.Topic, err)
+				continue
+			}
+
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Topic, data); err != nil {
+				log.Printf("[EventsHandler] SSE write error (client disconnected): %v", err)
+				return
+			}
+			flusher.Flush()
+
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				log.Printf("[EventsHandler] Keepalive write error (client disconnected): %v", err)
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// HandleWebSocket はSSEの代わりにWebSocketでイベントを配信します。
+// クエリは HandleStream と同じで、各イベントを Event JSON のテキストフレームで送ります。
+// クライアントからのメッセージは読み捨て、切断検出のためにのみ読み取ります。
+// GET /api/bus/events/ws?topics=task.*
+func (h *EventsHandler) HandleWebSocket(c *gin.Context) {
+	opts, err := subscribeOptions(c, "events_ws")
+	if err != nil {
+		log.Printf("[EventsHandler] HandleWebSocket rejected: error=%v", err)
+		respondSubscribeError(c, err)
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade がエラーレスポンスを書き込み済み
+		log.Printf("[EventsHandler] HandleWebSocket upgrade failed: error=%v", err)
+		return
+	}
+	defer conn.Close()
+	log.Printf("[EventsHandler] HandleWebSocket started: topics=%v, policy=%s, buffer=%d", opts.Topics, opts.Policy, opts.Buffer)
+
+	ch, unsubscribe := h.bus.Subscribe(opts)
+	defer unsubscribe()
+	defer trackSSESubscriber("events_ws")()
+
+	// 読み取りループ（pong で期限を延ばし、切断・エラーで closed を閉じる）
+	closed := make(chan struct{})
+	_ = conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(sseKeepaliveInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-closed:
+			log.Printf("[EventsHandler] WebSocket client disconnected")
+			return
+
+		case ev, ok := <-ch:
+			if !ok {
+				_ = conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseGoingAway, "event stream closed"), time.Now().Add(wsWriteTimeout))
+				log.Printf("[EventsHandler] Event channel closed, WebSocket completed")
+				return
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := conn.WriteJSON(ev); err != nil {
+				log.Printf("[EventsHandler] WebSocket write error (client disconnected): %v", err)
+				return
+			}
+
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				log.Printf("[EventsHandler] WebSocket ping error (client disconnected): %v", err)
+				return
+			}
+		}
+	}
+}
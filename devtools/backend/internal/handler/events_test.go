@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/events"
+	"ghostrunner/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newEventsServer は EventsHandler をルーティングしたテストサーバーを返します
+func newEventsServer(t *testing.T, bus *events.Bus) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewEventsHandler(bus, nil)
+	r.GET("/api/bus/events", h.HandleStream)
+	r.GET("/api/bus/events/ws", h.HandleWebSocket)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// waitSubscribers はバスの購読者数が n になるまで待ちます
+func waitSubscribers(t *testing.T, bus *events.Bus, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool { return bus.Subscribers() == n }, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestEventsHandler_HandleStream_InvalidQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewEventsHandler(events.NewBus(nil), nil)
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "unknown family", query: "topics=unknown.*"},
+		{name: "malformed topic", query: "topics=run.*.x"},
+		{name: "unknown policy", query: "policy=latest"},
+		{name: "block policy is in-process only", query: "policy=block"},
+		{name: "buffer out of range", query: "buffer=0"},
+		{name: "buffer not a number", query: "buffer=abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/bus/events?"+tt.query, nil)
+
+			h.HandleStream(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, false, resp["success"])
+		})
+	}
+}
+
+func TestEventsHandler_HandleStream_FiltersTopics(t *testing.T) {
+	bus := events.NewBus(nil)
+	srv := newEventsServer(t, bus)
+
+	resp, err := http.Get(srv.URL + "/api/bus/events?topics=task.*,dashboard.state")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	waitSubscribers(t, bus, 1)
+	bus.Publish("patrol.project_started", map[string]string{"projectPath": "/p"})
+	bus.Publish(events.TopicTaskCreated, map[string]string{"name": "a.md"})
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for len(lines) < 3 {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if line = strings.TrimRight(line, "\n"); line != "" && !strings.HasPrefix(line, ":") {
+			lines = append(lines, line)
+		}
+	}
+	assert.True(t, strings.HasPrefix(lines[0], "id: "))
+	assert.Equal(t, "event: task.created", lines[1])
+
+	var ev events.Event
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &ev))
+	assert.Equal(t, events.TopicTaskCreated, ev.Topic)
+	assert.Equal(t, map[string]any{"name": "a.md"}, ev.Data)
+}
+
+func TestEventsHandler_HandleWebSocket(t *testing.T) {
+	bus := events.NewBus(nil)
+	srv := newEventsServer(t, bus)
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/bus/events/ws?topics=tts.*"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	waitSubscribers(t, bus, 1)
+	bus.Publish(events.TopicTaskDeleted, map[string]string{"name": "a.md"})
+	bus.Publish(events.TopicTTSSynthesized, map[string]int{"chars": 3})
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var ev events.Event
+	require.NoError(t, conn.ReadJSON(&ev))
+	assert.Equal(t, events.TopicTTSSynthesized, ev.Topic)
+	assert.Equal(t, map[string]any{"chars": float64(3)}, ev.Data)
+
+	// クライアントの切断で購読が外れる
+	require.NoError(t, conn.Close())
+	waitSubscribers(t, bus, 0)
+}
+
+func TestStreamRun_PublishesToBusAndWritesLegacySSE(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	bus := events.NewBus(nil)
+	observed, unsubscribe := bus.Subscribe(events.Options{Topics: []string{"run.*"}, Buffer: 10})
+	defer unsubscribe()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/command/stream", nil)
+
+	streamRun(c, bus, RunEvent{Kind: "command", Project: "/p"}, "CommandHandler", func(eventCh chan<- service.StreamEvent) error {
+		defer close(eventCh)
+		eventCh <- service.StreamEvent{Type: service.EventTypeInit, SessionID: "s1"}
+		eventCh <- service.StreamEvent{Type: service.EventTypeComplete, Message: "done"}
+		return nil
+	})
+
+	// 従来形式（data: <StreamEvent>）で2件返す
+	body := w.Body.String()
+	assert.Equal(t, 2, strings.Count(body, "data: "))
+	assert.Contains(t, body, `"type":"init"`)
+	assert.Contains(t, body, `"type":"complete"`)
+
+	// バスには run.<type> と run.finished が同じ RunID で流れる
+	var topics []string
+	var runIDs []string
+	for range 3 {
+		select {
+		case ev := <-observed:
+			topics = append(topics, ev.Topic)
+			run := ev.Data.(RunEvent)
+			assert.Equal(t, "command", run.Kind)
+			assert.Equal(t, "/p", run.Project)
+			runIDs = append(runIDs, run.RunID)
+		case <-time.After(2 * time.Second):
+			t.Fatal("run event not published")
+		}
+	}
+	assert.Equal(t, []string{"run.init", "run.complete", events.TopicRunFinished}, topics)
+	assert.NotEmpty(t, runIDs[0])
+	assert.Equal(t, runIDs[0], runIDs[1])
+	assert.Equal(t, runIDs[0], runIDs[2])
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"ghostrunner/backend/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// metricsContentType は Prometheus テキスト形式の Content-Type です
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// sseSubscribers は接続中のSSEクライアント数です（stream=dashboard / patrol / command / events / events_ws）
+var sseSubscribers = metrics.NewGaugeVec("ghostrunner_sse_subscribers",
+	"Connected SSE clients by stream (dashboard, patrol, command, events, events_ws).", "stream")
+
+// authRejected は認証・認可で拒否した要求数です
+// （reason=missing_token / invalid_token / insufficient_scope / not_host / invalid_code / pair_locked）
+var authRejected = metrics.NewCounterVec("ghostrunner_auth_rejected_total",
+	"Requests rejected by device auth by reason (missing_token, invalid_token, insufficient_scope, not_host, invalid_code, pair_locked).", "reason")
+
+// callbackResults は通知のボタンから呼ばれたコールバックの結果です
+// （result=resumed / invalid / expired / used / stale / failed）
+var callbackResults = metrics.NewCounterVec("ghostrunner_callbacks_total",
+	"Notification action callbacks by result (resumed, invalid, expired, used, stale, failed).", "result")
+
+// trackSSESubscriber はSSE接続の開始を記録し、切断時に呼ぶ関数を返します
+func trackSSESubscriber(stream string) func() {
+	sseSubscribers.Inc(stream)
+	return func() { sseSubscribers.Dec(stream) }
+}
+
+// MetricsHandler は運用メトリクスを返すHTTPハンドラを提供します
+type MetricsHandler struct {
+	registry *metrics.Registry
+}
+
+// NewMetricsHandler は新しいMetricsHandlerを生成します
+func NewMetricsHandler(registry *metrics.Registry) *MetricsHandler {
+	return &MetricsHandler{registry: registry}
+}
+
+// Handle は登録済みメトリクスを Prometheus テキスト形式で返します。
+// GET /metrics
+//
+// レスポンス:
+//   - 200: 成功（text/plain; version=0.0.4）
+func (h *MetricsHandler) Handle(c *gin.Context) {
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", metricsContentType)
+	if err := h.registry.WriteText(c.Writer); err != nil {
+		log.Printf("[MetricsHandler] Handle failed: error=%v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ghostrunner/backend/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsHandler_Handle(t *testing.T) {
+	reg := metrics.NewRegistry()
+	reg.NewCounterVec("test_requests_total", "Requests.", "result").Inc("ok")
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/metrics", NewMetricsHandler(reg).Handle)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, metricsContentType, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "# TYPE test_requests_total counter\n")
+	assert.Contains(t, w.Body.String(), `test_requests_total{result="ok"} 1`)
+}
+
+func TestTrackSSESubscriber(t *testing.T) {
+	before := sseSubscribers.Value("dashboard")
+	done := trackSSESubscriber("dashboard")
+	assert.Equal(t, before+1, sseSubscribers.Value("dashboard"))
+	done()
+	assert.Equal(t, before, sseSubscribers.Value("dashboard"))
+}
@@ -271,6 +271,7 @@ func (h *PatrolHandler) HandleStream(c *gin.Context) {
 	// サブスクリプション取得
 	eventCh, unsubscribe := h.patrolService.Subscribe()
 	defer unsubscribe()
+	defer trackSSESubscriber("patrol")()
 
 	// SSEイベントを送信
 	writePatrolSSEEvents(c, eventCh)
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"ghostrunner/backend/internal/events"
 	"ghostrunner/backend/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -39,12 +40,18 @@ type PlanResponse struct {
 // PlanHandler はPlan関連のHTTPハンドラを提供します
 type PlanHandler struct {
 	claudeService service.ClaudeService
+	bus           *events.Bus // ストリーム実行のイベントを run.* として発行するバス
 }
 
-// NewPlanHandler は新しいPlanHandlerを生成します
-func NewPlanHandler(claudeService service.ClaudeService) *PlanHandler {
+// NewPlanHandler は新しいPlanHandlerを生成します。
+// bus が nil の場合はハンドラ専用のバスを使います（/api/bus/events へは流れません）。
+func NewPlanHandler(claudeService service.ClaudeService, bus *events.Bus) *PlanHandler {
+	if bus == nil {
+		bus = events.NewBus(nil)
+	}
 	return &PlanHandler{
 		claudeService: claudeService,
+		bus:           bus,
 	}
 }
 
@@ -145,19 +152,10 @@ func (h *PlanHandler) HandleStream(c *gin.Context) {
 	// SSEヘッダー設定
 	setSSEHeaders(c)
 
-	// イベントチャンネル作成
-	eventCh := make(chan service.StreamEvent, 100)
-
-	// ストリーミング実行を開始
-	go func() {
-		err := h.claudeService.ExecutePlanStream(c.Request.Context(), req.Project, req.Args, eventCh)
-		if err != nil {
-			log.Printf("[PlanHandler] HandleStream error: %v", err)
-		}
-	}()
-
-	// イベントをSSEとして送信（selectベースのループで確実にコンテキストキャンセルを検出）
-	writeSSEEvents(c, eventCh, "PlanHandler")
+	// ストリーミング実行を開始し、バス経由でSSEとして送信（selectベースのループで確実にコンテキストキャンセルを検出）
+	streamRun(c, h.bus, RunEvent{Kind: "plan", Project: req.Project}, "PlanHandler", func(eventCh chan<- service.StreamEvent) error {
+		return h.claudeService.ExecutePlanStream(c.Request.Context(), req.Project, req.Args, eventCh)
+	})
 
 	log.Printf("[PlanHandler] HandleStream completed: project=%s, args=%s", req.Project, req.Args)
 }
@@ -279,19 +277,10 @@ func (h *PlanHandler) HandleContinueStream(c *gin.Context) {
 	// SSEヘッダー設定
 	setSSEHeaders(c)
 
-	// イベントチャンネル作成
-	eventCh := make(chan service.StreamEvent, 100)
-
-	// ストリーミング実行を開始
-	go func() {
-		err := h.claudeService.ContinueSessionStream(c.Request.Context(), req.Project, req.SessionID, req.Answer, eventCh)
-		if err != nil {
-			log.Printf("[PlanHandler] HandleContinueStream error: %v", err)
-		}
-	}()
-
-	// イベントをSSEとして送信（selectベースのループで確実にコンテキストキャンセルを検出）
-	writeSSEEvents(c, eventCh, "PlanHandler")
+	// ストリーミング実行を開始し、バス経由でSSEとして送信（selectベースのループで確実にコンテキストキャンセルを検出）
+	streamRun(c, h.bus, RunEvent{Kind: "plan_continue", Project: req.Project}, "PlanHandler", func(eventCh chan<- service.StreamEvent) error {
+		return h.claudeService.ContinueSessionStream(c.Request.Context(), req.Project, req.SessionID, req.Answer, eventCh)
+	})
 
 	log.Printf("[PlanHandler] HandleContinueStream completed: project=%s, sessionID=%s", req.Project, req.SessionID)
 }
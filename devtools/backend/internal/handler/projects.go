@@ -47,7 +47,7 @@ type DestroyRequest struct {
 // ProjectsHandler はプロジェクト一覧関連のHTTPハンドラを提供します
 //
 // BaseDir はスキャン対象のベースディレクトリ。
-// ゼロ値の場合はデフォルトパス /Users/user/ を使用する。
+// ゼロ値の場合はホームディレクトリを使用する。
 // HomeDir はプロジェクト削除時のパス制限に使用するホームディレクトリ。
 // テスト時にディレクトリを差し替え可能にするために公開フィールドとしている。
 type ProjectsHandler struct {
@@ -59,8 +59,10 @@ type ProjectsHandler struct {
 // NewProjectsHandler は新しいProjectsHandlerを生成します。
 // patrolConfigPath が指定された場合、Handle は patrol_projects.json に
 // 登録されたプロジェクトのみを返す。空文字の場合は従来どおり全ディレクトリをスキャンする。
-func NewProjectsHandler(patrolConfigPath string) *ProjectsHandler {
+// baseDir はスキャン対象のベースディレクトリ（設定の projectBaseDir）。削除可能範囲はホームディレクトリ直下のまま。
+func NewProjectsHandler(patrolConfigPath, baseDir string) *ProjectsHandler {
 	return &ProjectsHandler{
+		BaseDir:          baseDir,
 		PatrolConfigPath: patrolConfigPath,
 	}
 }
@@ -70,7 +72,7 @@ func (h *ProjectsHandler) baseDir() string {
 	if h.BaseDir != "" {
 		return h.BaseDir
 	}
-	return "/Users/user/"
+	return h.homeDir()
 }
 
 // homeDir はプロジェクト削除時のパス制限に使用するホームディレクトリを返します
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+
+	"ghostrunner/backend/internal/report"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportHandler は活動ダイジェスト関連のHTTPハンドラを提供します
+type ReportHandler struct {
+	generator *report.Generator
+}
+
+// NewReportHandler は新しいReportHandlerを生成します
+func NewReportHandler(generator *report.Generator) *ReportHandler {
+	return &ReportHandler{generator: generator}
+}
+
+// HandleDigest は活動ダイジェストを生成して返します。
+// GET /api/reports/digest?period=day|week&project=&to=&format=json|md|html
+//
+// to（期間の終わり、省略時は現在時刻）は RFC3339 または YYYY-MM-DD（ローカル時刻0時）で指定します。
+//
+// レスポンス:
+//   - 200: 成功（format に応じて report.Digest の JSON / Markdown / HTML）
+//   - 400: パラメータ不正（period・format 不正、日付形式不正、未登録プロジェクト）
+//   - 500: 生成失敗
+func (h *ReportHandler) HandleDigest(c *gin.Context) {
+	period, err := report.ParsePeriod(c.Query("period"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "period は day または week を指定してください",
+		})
+		return
+	}
+	format, err := report.ParseFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "format は json / md / html のいずれかを指定してください",
+		})
+		return
+	}
+	to, err := parseTimeParam(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "to の形式が不正です（RFC3339 または YYYY-MM-DD）",
+		})
+		return
+	}
+
+	q := report.Query{Period: period, Project: c.Query("project"), To: to}
+	log.Printf("[ReportHandler] HandleDigest started: period=%s, project=%s, format=%s", q.Period, q.Project, format)
+
+	d, err := h.generator.Generate(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("[ReportHandler] HandleDigest failed: error=%v", err)
+		if errors.Is(err, report.ErrValidation) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "ダイジェストの生成に失敗しました",
+		})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := report.Render(&buf, format, d); err != nil {
+		log.Printf("[ReportHandler] HandleDigest render failed: error=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "ダイジェストの描画に失敗しました",
+		})
+		return
+	}
+
+	log.Printf("[ReportHandler] HandleDigest completed: projects=%d, warnings=%d", len(d.Projects), len(d.Warnings))
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/analytics"
+	"ghostrunner/backend/internal/projects"
+	"ghostrunner/backend/internal/report"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupReportRouter(t *testing.T) (*gin.Engine, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	proj := t.TempDir()
+	provider := func() ([]projects.Project, error) {
+		return []projects.Project{{Path: proj, Name: "demo"}}, nil
+	}
+	usage := &mockAnalyticsService{
+		reportFunc: func(ctx context.Context, q analytics.Query) (*analytics.Report, error) {
+			return &analytics.Report{Projects: []analytics.ProjectStats{
+				{Name: "demo", Path: proj, Total: analytics.Stats{Sessions: 2, Tokens: analytics.TokenUsage{Input: 100, Output: 20}}},
+			}}, nil
+		},
+	}
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	gen := report.NewGenerator(provider, "", nil, usage, func() time.Time { return now })
+	r := gin.New()
+	r.GET("/api/reports/digest", NewReportHandler(gen).HandleDigest)
+	return r, proj
+}
+
+func TestReportHandler_HandleDigest(t *testing.T) {
+	r, proj := setupReportRouter(t)
+
+	tests := []struct {
+		name     string
+		url      string
+		wantCode int
+		wantType string
+	}{
+		{"JSON（既定）", "/api/reports/digest", http.StatusOK, "application/json"},
+		{"Markdown", "/api/reports/digest?period=week&format=md", http.StatusOK, "text/markdown"},
+		{"HTML", "/api/reports/digest?format=html&to=2026-10-18", http.StatusOK, "text/html"},
+		{"period不正", "/api/reports/digest?period=month", http.StatusBadRequest, "application/json"},
+		{"format不正", "/api/reports/digest?format=pdf", http.StatusBadRequest, "application/json"},
+		{"to不正", "/api/reports/digest?to=yesterday", http.StatusBadRequest, "application/json"},
+		{"未登録プロジェクト", "/api/reports/digest?project=/unknown", http.StatusBadRequest, "application/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), tt.wantType), w.Header().Get("Content-Type"))
+		})
+	}
+
+	t.Run("プロジェクトごとの集計", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/reports/digest?period=day&project="+proj, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var d report.Digest
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &d))
+		assert.Equal(t, report.PeriodDay, d.Period)
+		require.Len(t, d.Projects, 1)
+		assert.Equal(t, 2, d.Projects[0].Cost.Sessions)
+		assert.Equal(t, int64(100), d.Totals.Cost.Tokens.Input)
+	})
+}
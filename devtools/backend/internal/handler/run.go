@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log"
+
+	"ghostrunner/backend/internal/events"
+	"ghostrunner/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// runSubscriberBuffer は実行ストリーム1本あたりのバッファサイズです（従来の eventCh と同じ）
+const runSubscriberBuffer = 100
+
+// RunEvent はコマンド・計画の実行イベント（run.* トピックのデータ）です。
+// run.finished では Event を省略し、同じ RunID のイベント列が終わったことを示します。
+type RunEvent struct {
+	RunID   string               `json:"runId"`
+	Kind    string               `json:"kind"` // command / command_continue / plan / plan_continue
+	Project string               `json:"project"`
+	Event   *service.StreamEvent `json:"event,omitempty"`
+}
+
+// streamRun は実行を開始し、そのイベントをバスへ run.<type> として発行しながら、
+// 同じ実行のイベントだけを購読して従来形式の SSE（data: <StreamEvent>）で返します。
+// 購読は実行開始より前に行うため、先頭のイベントを取りこぼしません。
+func streamRun(c *gin.Context, bus *events.Bus, run RunEvent, handlerName string, start func(eventCh chan<- service.StreamEvent) error) {
+	run.RunID = newRunID()
+	runCh, unsubscribe := events.SubscribeData[RunEvent](bus, events.Options{
+		Topics: []string{events.TopicRunPrefix + "*"},
+		Filter: func(ev events.Event) bool { return ev.Data.(RunEvent).RunID == run.RunID },
+		Buffer: runSubscriberBuffer,
+		Policy: events.PolicyBlock,
+		Name:   "command",
+	})
+	defer unsubscribe()
+
+	eventCh := make(chan service.StreamEvent, runSubscriberBuffer)
+	go func() {
+		if err := start(eventCh); err != nil {
+			log.Printf("[%s] stream error: runID=%s, err=%v", handlerName, run.RunID, err)
+		}
+	}()
+	go publishRun(bus, run, eventCh)
+
+	writeSSEEvents(c, runCh, handlerName)
+}
+
+// publishRun は eventCh が閉じるまでイベントを発行し、最後に run.finished を発行します
+func publishRun(bus *events.Bus, run RunEvent, eventCh <-chan service.StreamEvent) {
+	for ev := range eventCh {
+		e := ev
+		out := run
+		out.Event = &e
+		bus.Publish(events.TopicRunPrefix+ev.Type, out)
+	}
+	bus.Publish(events.TopicRunFinished, run)
+}
+
+// newRunID は実行を識別するランダムなIDを返します
+func newRunID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand は Linux/macOS では失敗しない。万一の場合も実行は続ける
+		return ""
+	}
+	return fmt.Sprintf("%x", b)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"ghostrunner/backend/internal/search"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchHandler は全文検索関連のHTTPハンドラを提供します
+type SearchHandler struct {
+	svc search.Service
+}
+
+// NewSearchHandler は新しいSearchHandlerを生成します
+func NewSearchHandler(svc search.Service) *SearchHandler {
+	return &SearchHandler{svc: svc}
+}
+
+// Handle は会話ログと開発ドキュメントを横断検索します。
+// GET /api/search?q=&project=&kind=session|doc&limit=
+//
+// レスポンス:
+//   - 200: 成功（search.Result）
+//   - 400: パラメータ不正（q 未指定、kind 不正、limit 不正、未登録プロジェクト）
+//   - 500: 検索失敗
+func (h *SearchHandler) Handle(c *gin.Context) {
+	q := search.Query{
+		Text:        c.Query("q"),
+		ProjectPath: c.Query("project"),
+		Kind:        search.Kind(c.Query("kind")),
+	}
+	if s := c.Query("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "limit は1以上の整数で指定してください",
+			})
+			return
+		}
+		q.Limit = n
+	}
+
+	log.Printf("[SearchHandler] Handle started: q=%q, project=%s, kind=%s", q.Text, q.ProjectPath, q.Kind)
+
+	result, err := h.svc.Search(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("[SearchHandler] Handle failed: error=%v", err)
+		if errors.Is(err, search.ErrValidation) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "検索に失敗しました",
+		})
+		return
+	}
+
+	log.Printf("[SearchHandler] Handle completed: total=%d, hits=%d", result.Total, len(result.Hits))
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ghostrunner/backend/internal/search"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockSearchService はテスト用のsearch.Serviceモックです
+type mockSearchService struct {
+	searchFunc func(ctx context.Context, q search.Query) (*search.Result, error)
+}
+
+func (m *mockSearchService) Search(ctx context.Context, q search.Query) (*search.Result, error) {
+	return m.searchFunc(ctx, q)
+}
+
+func (m *mockSearchService) Refresh(ctx context.Context) error { return nil }
+func (m *mockSearchService) Start(ctx context.Context)         {}
+func (m *mockSearchService) Stop()                             {}
+
+func TestSearchHandler_Handle(t *testing.T) {
+	var got search.Query
+	mock := &mockSearchService{
+		searchFunc: func(ctx context.Context, q search.Query) (*search.Result, error) {
+			got = q
+			switch q.Text {
+			case "":
+				return nil, fmt.Errorf("%w: query is empty", search.ErrValidation)
+			case "boom":
+				return nil, fmt.Errorf("boom")
+			}
+			return &search.Result{Query: q.Text, Hits: []search.Hit{}}, nil
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/search", NewSearchHandler(mock).Handle)
+
+	tests := []struct {
+		name     string
+		url      string
+		wantCode int
+	}{
+		{"正常系", "/api/search?q=webhook&project=/p&kind=session&limit=5", http.StatusOK},
+		{"q未指定", "/api/search", http.StatusBadRequest},
+		{"limit不正", "/api/search?q=x&limit=0", http.StatusBadRequest},
+		{"検索失敗", "/api/search?q=boom", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/search?q=webhook&project=/p&kind=session&limit=5", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, search.Query{Text: "webhook", ProjectPath: "/p", Kind: search.KindSession, Limit: 5}, got)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"ghostrunner/backend/internal/export"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionsHandler は会話ログ（セッション）関連のHTTPハンドラを提供します
+type SessionsHandler struct {
+	exporter *export.Exporter
+}
+
+// NewSessionsHandler は新しいSessionsHandlerを生成します
+func NewSessionsHandler(exporter *export.Exporter) *SessionsHandler {
+	return &SessionsHandler{exporter: exporter}
+}
+
+// HandleExport は会話ログ1セッションを秘匿値を伏せてエクスポートします。
+// GET /api/exports/sessions/:id?format=md|html|json&download=1
+//
+// /api/sessions/* はフロントエンドの rewrite で even-terminal へ転送されるため、/api/exports 配下に置きます。
+//
+// download=1 の場合は Content-Disposition: attachment を付けてファイルとして返します。
+//
+// レスポンス:
+//   - 200: 成功（format に応じた Content-Type の本文）
+//   - 400: format 不正、id が session-id の形でない（パス・glob のメタ文字・.jsonl 付き）
+//   - 404: セッションが見つからない、登録プロジェクトのセッションでない
+//   - 500: 読み込み・書き出し失敗
+func (h *SessionsHandler) HandleExport(c *gin.Context) {
+	id := c.Param("id")
+	format, err := export.ParseFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "format は md / html / json のいずれかを指定してください",
+		})
+		return
+	}
+
+	log.Printf("[SessionsHandler] HandleExport started: id=%s, format=%s", id, format)
+
+	t, err := h.exporter.BuildSession(id)
+	if err != nil {
+		log.Printf("[SessionsHandler] HandleExport failed: id=%s, error=%v", id, err)
+		if errors.Is(err, export.ErrInvalidID) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "id にはセッション ID を指定してください",
+			})
+			return
+		}
+		if errors.Is(err, export.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "セッションが見つかりません",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "会話ログの読み込みに失敗しました",
+		})
+		return
+	}
+
+	// 書き出し失敗時に JSON エラーを返せるよう、バッファしてから送る
+	var buf bytes.Buffer
+	if err := export.Render(&buf, format, t); err != nil {
+		log.Printf("[SessionsHandler] HandleExport failed: render error=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "エクスポートの書き出しに失敗しました",
+		})
+		return
+	}
+
+	if c.Query("download") == "1" {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "session-"+t.SessionID+"."+string(format)))
+	}
+
+	log.Printf("[SessionsHandler] HandleExport completed: id=%s, format=%s, messages=%d, bytes=%d", id, format, len(t.Messages), buf.Len())
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/export"
+	"ghostrunner/backend/internal/projects"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionsHandler_HandleExport(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-p")
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	line := `{"type":"user","cwd":"/p","timestamp":"2026-07-01T10:00:00Z","message":{"role":"user","content":"こんにちは"}}` + "\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "sess-1.jsonl"), []byte(line), 0644))
+	// 未登録プロジェクトのセッション
+	other := filepath.Join(home, ".claude", "projects", "-other")
+	assert.NoError(t, os.MkdirAll(other, 0755))
+	otherLine := `{"type":"user","cwd":"/other","timestamp":"2026-07-01T11:00:00Z","message":{"role":"user","content":"秘密の相談"}}` + "\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(other, "sess-2.jsonl"), []byte(otherLine), 0644))
+
+	provider := func() ([]projects.Project, error) { return []projects.Project{{Name: "p", Path: "/p"}}, nil }
+	exporter := export.NewExporter(home, provider, time.Now, nil, false)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/exports/sessions/:id", NewSessionsHandler(exporter).HandleExport)
+
+	tests := []struct {
+		name        string
+		url         string
+		wantCode    int
+		wantType    string
+		wantContent string
+	}{
+		{"Markdown（デフォルト）", "/api/exports/sessions/sess-1", http.StatusOK, "text/markdown", "こんにちは"},
+		{"HTML", "/api/exports/sessions/sess-1?format=html", http.StatusOK, "text/html", "<!DOCTYPE html>"},
+		{"JSON", "/api/exports/sessions/sess-1?format=json", http.StatusOK, "application/json", `"sessionId"`},
+		{"format不正", "/api/exports/sessions/sess-1?format=pdf", http.StatusBadRequest, "application/json", "format"},
+		{"存在しないセッション", "/api/exports/sessions/nope", http.StatusNotFound, "application/json", "見つかりません"},
+		{"未登録プロジェクトのセッション", "/api/exports/sessions/sess-2", http.StatusNotFound, "application/json", "見つかりません"},
+		{"globのメタ文字", "/api/exports/sessions/*", http.StatusBadRequest, "application/json", "セッション ID"},
+		{"jsonlのパス", "/api/exports/sessions/sess-1.jsonl", http.StatusBadRequest, "application/json", "セッション ID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), tt.wantType), w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Body.String(), tt.wantContent)
+		})
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/exports/sessions/sess-1?format=html&download=1", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, `attachment; filename="session-sess-1.html"`, w.Header().Get("Content-Disposition"))
+}
@@ -7,8 +7,6 @@ import (
 	"log"
 	"time"
 
-	"ghostrunner/backend/internal/service"
-
 	"github.com/gin-gonic/gin"
 )
 
@@ -24,11 +22,12 @@ func setSSEHeaders(c *gin.Context) {
 	c.Header("X-Accel-Buffering", "no")
 }
 
-// writeSSEEvents はeventChからイベントを読み取り、SSE形式でクライアントに送信します。
+// writeSSEEvents はrunChから1実行分のイベントを読み取り、SSE形式でクライアントに送信します。
 // Ginのc.Stream()の代わりにselectベースのループを使用し、
 // コンテキストキャンセル（クライアント切断）を即座に検出します。
-// また、30秒ごとにキープアライブコメントを送信して接続を維持します。
-func writeSSEEvents(c *gin.Context, eventCh <-chan service.StreamEvent, handlerName string) {
+// run.finished（Event なし）を受け取るとストリーム完了として終了します。
+// また、15秒ごとにキープアライブコメントを送信して接続を維持します。
+func writeSSEEvents(c *gin.Context, runCh <-chan RunEvent, handlerName string) {
 	w := c.Writer
 	flusher, ok := w.(interface{ Flush() })
 	if !ok {
@@ -38,6 +37,7 @@ func writeSSEEvents(c *gin.Context, eventCh <-chan service.StreamEvent, handlerN
 
 	keepalive := time.NewTicker(sseKeepaliveInterval)
 	defer keepalive.Stop()
+	defer trackSSESubscriber("command")()
 
 	ctx := c.Request.Context()
 
@@ -47,12 +47,18 @@ func writeSSEEvents(c *gin.Context, eventCh <-chan service.StreamEvent, handlerN
 			log.Printf("[%s] Client disconnected (context canceled)", handlerName)
 			return
 
-		case event, ok := <-eventCh:
+		case run, ok := <-runCh:
 			if !ok {
-				// チャネルが閉じられた（ストリーム正常完了）
+				// バスの停止または背圧による購読切断
+				log.Printf("[%s] Run subscription closed before completion", handlerName)
+				return
+			}
+			if run.Event == nil {
+				// run.finished（ストリーム正常完了）
 				log.Printf("[%s] Event channel closed, stream completed", handlerName)
 				return
 			}
+			event := *run.Event
 
 			data, err := json.Marshal(event)
 			if err != nil {
@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"ghostrunner/backend/internal/tasks"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TasksHandler はカンバンのタスク操作（開発/実装 配下の .md）のHTTPハンドラを提供します
+type TasksHandler struct {
+	svc tasks.Service
+}
+
+// NewTasksHandler は新しいTasksHandlerを生成します
+func NewTasksHandler(svc tasks.Service) *TasksHandler {
+	return &TasksHandler{svc: svc}
+}
+
+// HandleList はプロジェクトの全レーンのタスク一覧を返します。
+// GET /api/tasks?project=
+//
+// レスポンス:
+//   - 200: 成功（tasks.Board）
+//   - 400: 未登録プロジェクト
+//   - 500: 読み取り失敗
+func (h *TasksHandler) HandleList(c *gin.Context) {
+	project := c.Query("project")
+	log.Printf("[TasksHandler] HandleList started: project=%s", project)
+
+	board, err := h.svc.List(project)
+	if err != nil {
+		respondTaskError(c, "HandleList", err, "タスク一覧の取得に失敗しました")
+		return
+	}
+
+	log.Printf("[TasksHandler] HandleList completed: project=%s", project)
+	c.JSON(http.StatusOK, board)
+}
+
+// HandleTemplates はタスク作成に使えるテンプレート一覧を返します。
+// GET /api/tasks/templates?project=
+func (h *TasksHandler) HandleTemplates(c *gin.Context) {
+	templates, err := h.svc.Templates(c.Query("project"))
+	if err != nil {
+		respondTaskError(c, "HandleTemplates", err, "テンプレート一覧の取得に失敗しました")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+// HandleGet はタスクを本文付きで返します。ETag ヘッダーに内容の etag を載せます。
+// GET /api/tasks/content?project=&lane=&name=
+//
+// レスポンス:
+//   - 200: 成功（tasks.Task）
+//   - 400: パラメータ不正
+//   - 404: タスクが存在しない
+func (h *TasksHandler) HandleGet(c *gin.Context) {
+	task, err := h.svc.Get(c.Query("project"), tasks.Lane(c.Query("lane")), c.Query("name"))
+	if err != nil {
+		respondTaskError(c, "HandleGet", err, "タスクの取得に失敗しました")
+		return
+	}
+	c.Header("ETag", `"`+task.ETag+`"`)
+	c.JSON(http.StatusOK, task)
+}
+
+// HandleCreate はテンプレートからタスクを作成します。
+// POST /api/tasks/create
+func (h *TasksHandler) HandleCreate(c *gin.Context) {
+	var req tasks.CreateRequest
+	if !bindTaskRequest(c, &req) {
+		return
+	}
+	log.Printf("[TasksHandler] HandleCreate started: project=%s, lane=%s, template=%s", req.ProjectPath, req.Lane, req.Template)
+
+	task, err := h.svc.Create(req)
+	if err != nil {
+		respondTaskError(c, "HandleCreate", err, "タスクの作成に失敗しました")
+		return
+	}
+
+	log.Printf("[TasksHandler] HandleCreate completed: name=%s", task.Name)
+	c.Header("ETag", `"`+task.ETag+`"`)
+	c.JSON(http.StatusOK, task)
+}
+
+// HandleUpdate はタスク本文を更新します。etag はリクエストボディまたは If-Match ヘッダーで渡します。
+// POST /api/tasks/update
+//
+// レスポンス:
+//   - 200: 成功（更新後の tasks.Task）
+//   - 400: パラメータ不正（etag 未指定を含む）
+//   - 404: タスクが存在しない
+//   - 409: etag 不一致（他で更新済み）
+func (h *TasksHandler) HandleUpdate(c *gin.Context) {
+	var req tasks.UpdateRequest
+	if !bindTaskRequest(c, &req) {
+		return
+	}
+	if req.ETag == "" {
+		req.ETag = c.GetHeader("If-Match")
+	}
+	log.Printf("[TasksHandler] HandleUpdate started: project=%s, lane=%s, name=%s", req.ProjectPath, req.Lane, req.Name)
+
+	task, err := h.svc.Update(req)
+	if err != nil {
+		respondTaskError(c, "HandleUpdate", err, "タスクの更新に失敗しました")
+		return
+	}
+
+	log.Printf("[TasksHandler] HandleUpdate completed: name=%s", task.Name)
+	c.Header("ETag", `"`+task.ETag+`"`)
+	c.JSON(http.StatusOK, task)
+}
+
+// HandleMove はタスクを別レーンへ移動します。
+// POST /api/tasks/move
+//
+// レスポンス:
+//   - 200: 成功（移動後の tasks.Task）
+//   - 409: 移動先に同名ファイルがある、または etag 不一致
+func (h *TasksHandler) HandleMove(c *gin.Context) {
+	var req tasks.MoveRequest
+	if !bindTaskRequest(c, &req) {
+		return
+	}
+	if req.ETag == "" {
+		req.ETag = c.GetHeader("If-Match")
+	}
+	log.Printf("[TasksHandler] HandleMove started: project=%s, name=%s, from=%s, to=%s", req.ProjectPath, req.Name, req.Lane, req.To)
+
+	task, err := h.svc.Move(req)
+	if err != nil {
+		respondTaskError(c, "HandleMove", err, "タスクの移動に失敗しました")
+		return
+	}
+
+	log.Printf("[TasksHandler] HandleMove completed: name=%s, lane=%s", task.Name, task.Lane)
+	c.JSON(http.StatusOK, task)
+}
+
+// HandleReorder はレーン内の並び順を保存します。
+// POST /api/tasks/reorder
+func (h *TasksHandler) HandleReorder(c *gin.Context) {
+	var req tasks.ReorderRequest
+	if !bindTaskRequest(c, &req) {
+		return
+	}
+	log.Printf("[TasksHandler] HandleReorder started: project=%s, lane=%s, names=%d", req.ProjectPath, req.Lane, len(req.Names))
+
+	lane, err := h.svc.Reorder(req)
+	if err != nil {
+		respondTaskError(c, "HandleReorder", err, "タスクの並び替えに失敗しました")
+		return
+	}
+	c.JSON(http.StatusOK, lane)
+}
+
+// HandleDelete はタスクを削除します。etag はリクエストボディまたは If-Match ヘッダーで渡します。
+// POST /api/tasks/delete
+func (h *TasksHandler) HandleDelete(c *gin.Context) {
+	var req tasks.DeleteRequest
+	if !bindTaskRequest(c, &req) {
+		return
+	}
+	if req.ETag == "" {
+		req.ETag = c.GetHeader("If-Match")
+	}
+	log.Printf("[TasksHandler] HandleDelete started: project=%s, lane=%s, name=%s", req.ProjectPath, req.Lane, req.Name)
+
+	if err := h.svc.Delete(req); err != nil {
+		respondTaskError(c, "HandleDelete", err, "タスクの削除に失敗しました")
+		return
+	}
+
+	log.Printf("[TasksHandler] HandleDelete completed: name=%s", req.Name)
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// bindTaskRequest はJSONボディをバインドし、失敗時は400を返します
+func bindTaskRequest(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "リクエストが不正です",
+		})
+		return false
+	}
+	return true
+}
+
+// respondTaskError は tasks のエラーをHTTPステータスへ変換して返します
+func respondTaskError(c *gin.Context, op string, err error, fallback string) {
+	log.Printf("[TasksHandler] %s failed: error=%v", op, err)
+	switch {
+	case errors.Is(err, tasks.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+	case errors.Is(err, tasks.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": "タスクが見つかりません"})
+	case errors.Is(err, tasks.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": "タスクが他で更新されたか、移動先に同名のファイルがあります"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": fallback})
+	}
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/projects"
+	"ghostrunner/backend/internal/tasks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTasksRouter(t *testing.T) (*gin.Engine, string) {
+	t.Helper()
+	project := t.TempDir()
+	dir := filepath.Join(project, "開発", "実装", "実装待ち")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.md"), []byte("# A\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	svc := tasks.NewService(func() ([]projects.Project, error) {
+		return []projects.Project{{Name: "p", Path: project}}, nil
+	}, func() time.Time { return time.Date(2026, 7, 20, 12, 0, 0, 0, time.UTC) })
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewTasksHandler(svc)
+	r.GET("/api/tasks", h.HandleList)
+	r.GET("/api/tasks/content", h.HandleGet)
+	r.POST("/api/tasks/create", h.HandleCreate)
+	r.POST("/api/tasks/update", h.HandleUpdate)
+	r.POST("/api/tasks/move", h.HandleMove)
+	return r, project
+}
+
+func TestTasksHandler(t *testing.T) {
+	r, project := setupTasksRouter(t)
+
+	do := func(method, url, body string, header map[string]string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// 取得: ETag ヘッダー付き
+	w := do("GET", "/api/tasks/content?project="+project+"&lane=waiting&name=a.md", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		header   map[string]string
+		wantCode int
+	}{
+		{"一覧", "GET", "/api/tasks?project=" + project, "", nil, http.StatusOK},
+		{"一覧_未登録プロジェクト", "GET", "/api/tasks?project=/nope", "", nil, http.StatusBadRequest},
+		{"取得_存在しない", "GET", "/api/tasks/content?project=" + project + "&lane=waiting&name=z.md", "", nil, http.StatusNotFound},
+		{"作成", "POST", "/api/tasks/create", `{"projectPath":"` + project + `","title":"新規"}`, nil, http.StatusOK},
+		{"作成_不正JSON", "POST", "/api/tasks/create", `{`, nil, http.StatusBadRequest},
+		{"更新_etag未指定", "POST", "/api/tasks/update", `{"projectPath":"` + project + `","lane":"waiting","name":"a.md","content":"x"}`, nil, http.StatusBadRequest},
+		{"更新_If-Match", "POST", "/api/tasks/update", `{"projectPath":"` + project + `","lane":"waiting","name":"a.md","content":"# A2\n"}`, map[string]string{"If-Match": etag}, http.StatusOK},
+		{"更新_古いetag", "POST", "/api/tasks/update", `{"projectPath":"` + project + `","lane":"waiting","name":"a.md","content":"x","etag":` + etag + `}`, nil, http.StatusConflict},
+		{"移動", "POST", "/api/tasks/move", `{"projectPath":"` + project + `","lane":"waiting","name":"a.md","to":"running"}`, nil, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := do(tt.method, tt.url, tt.body, tt.header)
+			assert.Equal(t, tt.wantCode, w.Code, w.Body.String())
+		})
+	}
+}
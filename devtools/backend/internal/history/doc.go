@@ -0,0 +1,28 @@
+// Package history はダッシュボード状態の時系列（プロジェクトごとのカンバン件数・質問待ち/動作中/停滞・
+// 未回答数・運用状態）をローカルに記録し、期間指定で照会する機能を提供する。
+//
+// # 概要
+//
+// dashboard.State は現在のスナップショットのみを表し、StreamService も最新 State しか保持しない。
+// 本パッケージは StreamService のスキャンごとの状態をコンパクトなサンプルとして日次 JSONL
+// （~/.ghostrunner/history/YYYY-MM-DD.jsonl）へ追記し、スループット（完了数）・質問待ちの時間・
+// 確認事項が未回答のままだった時間をグラフ化できるようにする。
+//
+// # 主要な型・関数
+//
+//   - Sample: 1プロジェクトのある時点の状態（時刻・カンバン件数・idle/running/stuck・未回答数・運用状態）
+//   - Recorder: サンプルの記録（StreamService へ dashboard.WithRecorder で設定する）
+//   - Store / NewStore: 日次 JSONL への記録と期間照会
+//   - Summarize: プロジェクトごとの状態継続秒数と完了数の集計
+//   - ErrValidation: 照会期間が不正（from > to、31日超）
+//
+// # 設計方針
+//
+//   - スキャンは2秒間隔のため、前回記録から状態が変わったプロジェクトのみ記録する。変化が無くても
+//     heartbeatInterval（10分）ごとに1件記録し、サーバー停止による記録の途切れと区別する
+//   - 各サンプルの状態は次のサンプルまで続いたとみなし、maxSampleGap（心拍の2倍）を超える区間は
+//     記録の途切れとして集計しない
+//   - 照会は期間内の日次ファイルのみ読む。期間の先頭で有効だった直前のサンプルは時刻を from に揃えて含める
+//   - retentionDays（90日）を超えた日次ファイルは日付が変わった最初の記録時に削除する
+//   - ファイルシステムを唯一の保存先とし、外部DBは使わない
+package history
@@ -0,0 +1,284 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// heartbeatInterval は状態が変わらなくても記録する間隔です。
+	// 記録の途切れ（サーバー停止）と「変化なし」を区別するために使います。
+	heartbeatInterval = 10 * time.Minute
+	// maxSampleGap はサンプルの状態が続いたとみなす最大時間です（超えた区間は記録の途切れとして数えない）。
+	maxSampleGap = 2 * heartbeatInterval
+	// retentionDays は日次ファイルの保持日数です。
+	retentionDays = 90
+	// maxQueryRange は1回の照会で指定できる最大期間です。
+	maxQueryRange = 31 * 24 * time.Hour
+	// defaultQueryRange は from 省略時の照会期間です。
+	defaultQueryRange = 24 * time.Hour
+	// dayFileLayout は日次ファイル名（ローカル日付）の書式です。
+	dayFileLayout = "2006-01-02"
+)
+
+// Recorder は状態サンプルの記録を提供します。
+type Recorder interface {
+	// Record はサンプルを記録します。前回記録から状態が変わっていないプロジェクトは心拍間隔まで記録を省きます。
+	Record(samples []Sample) error
+}
+
+// Store は状態の時系列の記録と照会を提供します。
+type Store interface {
+	Recorder
+	// Query は条件に一致するサンプルと期間集計を返します。
+	Query(q Query) (*Result, error)
+}
+
+type fileStore struct {
+	dir string
+	now func() time.Time
+
+	mu         sync.Mutex
+	last       map[string]Sample // key: Project。最後に記録したサンプル
+	prunedDate string            // 最後に古いファイルを掃除した日付
+}
+
+// NewStore は dir 配下に日次 JSONL（YYYY-MM-DD.jsonl）で時系列を保存する Store を生成します。
+// now が nil の場合は time.Now を使います。
+func NewStore(dir string, now func() time.Time) Store {
+	if now == nil {
+		now = time.Now
+	}
+	return &fileStore{dir: dir, now: now, last: make(map[string]Sample)}
+}
+
+// Record はサンプルを当日のファイルへ追記します。時刻は記録時の now で上書きします。
+func (s *fileStore) Record(samples []Sample) error {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var lines []byte
+	written := make([]Sample, 0, len(samples))
+	for _, sm := range samples {
+		if prev, ok := s.last[sm.Project]; ok && prev.sameState(sm) && now.Sub(prev.Time) < heartbeatInterval {
+			continue
+		}
+		sm.Time = now
+		data, err := json.Marshal(sm)
+		if err != nil {
+			return fmt.Errorf("failed to marshal history sample: %w", err)
+		}
+		lines = append(lines, data...)
+		lines = append(lines, '\n')
+		written = append(written, sm)
+	}
+	if len(written) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create history dir %s: %w", s.dir, err)
+	}
+	path := filepath.Join(s.dir, now.Format(dayFileLayout)+".jsonl")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file %s: %w", path, err)
+	}
+	if _, err := f.Write(lines); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write history file %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close history file %s: %w", path, err)
+	}
+
+	// 書き込めたサンプルのみ「前回記録」として扱う（失敗時は次回再送される）
+	for _, sm := range written {
+		s.last[sm.Project] = sm
+	}
+
+	if today := now.Format(dayFileLayout); today != s.prunedDate {
+		s.prunedDate = today
+		s.prune(now)
+	}
+	return nil
+}
+
+// prune は保持日数を超えた日次ファイルを削除します。
+func (s *fileStore) prune(now time.Time) {
+	cutoff := now.AddDate(0, 0, -retentionDays).Format(dayFileLayout)
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		date, ok := strings.CutSuffix(e.Name(), ".jsonl")
+		if !ok || date >= cutoff {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, e.Name())); err != nil {
+			log.Printf("[HistoryStore] failed to remove old history file: name=%s, error=%v", e.Name(), err)
+		}
+	}
+}
+
+// Query は [From, To] の日次ファイルを読み、条件に一致するサンプルを時刻順に返します。
+// To 省略時は now、From 省略時は To の24時間前です。期間は最大31日です。
+func (s *fileStore) Query(q Query) (*Result, error) {
+	if q.To.IsZero() {
+		q.To = s.now()
+	}
+	if q.From.IsZero() {
+		q.From = q.To.Add(-defaultQueryRange)
+	}
+	if q.From.After(q.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrValidation)
+	}
+	if q.To.Sub(q.From) > maxQueryRange {
+		return nil, fmt.Errorf("%w: range must be within 31 days", ErrValidation)
+	}
+
+	samples := make([]Sample, 0)
+	// 直前の状態を期間の先頭に引き継ぐため、From の前日ファイルから読む
+	day := startOfDay(q.From).AddDate(0, 0, -1)
+	for !day.After(q.To) {
+		got, err := readDay(filepath.Join(s.dir, day.Format(dayFileLayout)+".jsonl"), q.Project)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, got...)
+		day = day.AddDate(0, 0, 1)
+	}
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Time.Before(samples[j].Time) })
+
+	inRange := clip(samples, q.From, q.To)
+	return &Result{
+		From:      q.From,
+		To:        q.To,
+		Samples:   inRange,
+		Summaries: Summarize(inRange, q.To),
+	}, nil
+}
+
+// clip は [from, to] のサンプルを返します。from 時点で有効だった直前のサンプルは
+// 時刻を from に揃えて先頭に含めます（期間の頭で状態が空白にならないように）。
+func clip(samples []Sample, from, to time.Time) []Sample {
+	out := make([]Sample, 0, len(samples))
+	carried := make(map[string]Sample)
+	for _, sm := range samples {
+		switch {
+		case sm.Time.Before(from):
+			if from.Sub(sm.Time) <= maxSampleGap {
+				carried[sm.Project] = sm
+			} else {
+				delete(carried, sm.Project)
+			}
+		case sm.Time.After(to):
+		default:
+			if prev, ok := carried[sm.Project]; ok {
+				if sm.Time.After(from) {
+					prev.Time = from
+					out = append(out, prev)
+				}
+				delete(carried, sm.Project)
+			}
+			out = append(out, sm)
+		}
+	}
+	head := make([]Sample, 0, len(carried))
+	for _, sm := range carried {
+		sm.Time = from
+		head = append(head, sm)
+	}
+	sort.Slice(head, func(i, j int) bool { return head[i].Project < head[j].Project })
+	return append(head, out...)
+}
+
+// readDay は日次ファイルを読みます。ファイルが無ければ空、壊れた行は skip します。
+func readDay(path, project string) ([]Sample, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open history file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var out []Sample
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var sm Sample
+		if err := json.Unmarshal(sc.Bytes(), &sm); err != nil {
+			continue
+		}
+		if project != "" && sm.Project != project {
+			continue
+		}
+		out = append(out, sm)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file %s: %w", path, err)
+	}
+	return out, nil
+}
+
+// Summarize はプロジェクトごとに各状態の継続秒数と完了数を集計します。
+// サンプルは次のサンプル（無ければ to）まで続いたとみなし、maxSampleGap を超える区間は記録の途切れとして数えません。
+func Summarize(samples []Sample, to time.Time) []Summary {
+	byProject := make(map[string][]Sample)
+	for _, sm := range samples {
+		byProject[sm.Project] = append(byProject[sm.Project], sm)
+	}
+
+	out := make([]Summary, 0, len(byProject))
+	for project, list := range byProject {
+		sum := Summary{Project: project, Samples: len(list)}
+		for i, sm := range list {
+			end := to
+			if i+1 < len(list) {
+				end = list[i+1].Time
+				if d := list[i+1].Kanban.Done - sm.Kanban.Done; d > 0 {
+					sum.Completed += d
+				}
+			}
+			span := end.Sub(sm.Time)
+			if span <= 0 || span > maxSampleGap {
+				continue
+			}
+			secs := int64(span / time.Second)
+			if sm.Idle {
+				sum.IdleSeconds += secs
+			}
+			if sm.Running {
+				sum.RunningSeconds += secs
+			}
+			if sm.Stuck {
+				sum.StuckSeconds += secs
+			}
+			if sm.Unanswered > 0 {
+				sum.UnansweredSeconds += secs
+			}
+		}
+		out = append(out, sum)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Project < out[j].Project })
+	return out
+}
+
+// startOfDay は t のローカル日付の0時を返します。
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,199 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clock はテスト用の進められる時計です
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+func newClock(s string) *clock {
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return &clock{t: t}
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile error: %v", err)
+	}
+	return strings.Count(string(data), "\n")
+}
+
+func TestRecord_変化時と心拍のみ記録(t *testing.T) {
+	dir := t.TempDir()
+	c := newClock("2026-07-20 12:00")
+	s := NewStore(dir, c.now)
+
+	base := Sample{Project: "/a", Kanban: Kanban{Running: 1}}
+	changed := Sample{Project: "/a", Kanban: Kanban{Running: 1}, Idle: true}
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		sample  Sample
+	}{
+		{"初回は記録", 0, base},
+		{"同じ状態は記録しない", 2 * time.Second, base},
+		{"変化したら記録", 2 * time.Second, changed},
+		{"同じ状態は記録しない(2)", 5 * time.Minute, changed},
+		{"心拍間隔を超えたら記録", 6 * time.Minute, changed},
+	}
+	for _, st := range steps {
+		c.advance(st.advance)
+		if err := s.Record([]Sample{st.sample}); err != nil {
+			t.Fatalf("%s: Record error: %v", st.name, err)
+		}
+	}
+
+	if got := countLines(t, filepath.Join(dir, "2026-07-20.jsonl")); got != 3 {
+		t.Errorf("lines = %d, want 3", got)
+	}
+}
+
+func TestRecord_古い日次ファイルを削除(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "2026-01-01.jsonl")
+	recent := filepath.Join(dir, "2026-07-01.jsonl")
+	for _, p := range []string{old, recent} {
+		if err := os.WriteFile(p, []byte("{}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewStore(dir, newClock("2026-07-20 12:00").now)
+	if err := s.Record([]Sample{{Project: "/a"}}); err != nil {
+		t.Fatalf("Record error: %v", err)
+	}
+
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("old file should be removed: err=%v", err)
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Errorf("recent file should remain: err=%v", err)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	dir := t.TempDir()
+	c := newClock("2026-07-19 23:55")
+	s := NewStore(dir, c.now)
+
+	record := func(samples ...Sample) {
+		t.Helper()
+		if err := s.Record(samples); err != nil {
+			t.Fatalf("Record error: %v", err)
+		}
+	}
+	// 日付をまたいで記録する
+	record(Sample{Project: "/a", Kanban: Kanban{Done: 1}}, Sample{Project: "/b"})
+	c.advance(10 * time.Minute) // 07-20 00:05
+	record(Sample{Project: "/a", Kanban: Kanban{Done: 2}, Idle: true, Unanswered: 1})
+	c.advance(10 * time.Minute) // 00:15
+	record(Sample{Project: "/a", Kanban: Kanban{Done: 4}})
+
+	from := newClock("2026-07-20 00:00").t
+	to := newClock("2026-07-20 00:20").t
+
+	t.Run("プロジェクト指定で期間内と直前の状態を返す", func(t *testing.T) {
+		res, err := s.Query(Query{Project: "/a", From: from, To: to})
+		if err != nil {
+			t.Fatalf("Query error: %v", err)
+		}
+		if len(res.Samples) != 3 {
+			t.Fatalf("samples = %d, want 3: %+v", len(res.Samples), res.Samples)
+		}
+		if !res.Samples[0].Time.Equal(from) || res.Samples[0].Kanban.Done != 1 {
+			t.Errorf("head sample should be carried to from: %+v", res.Samples[0])
+		}
+		for _, sm := range res.Samples {
+			if sm.Project != "/a" {
+				t.Errorf("unexpected project: %s", sm.Project)
+			}
+		}
+
+		if len(res.Summaries) != 1 {
+			t.Fatalf("summaries = %d, want 1", len(res.Summaries))
+		}
+		sum := res.Summaries[0]
+		if sum.Completed != 3 {
+			t.Errorf("Completed = %d, want 3", sum.Completed)
+		}
+		if sum.IdleSeconds != 600 || sum.UnansweredSeconds != 600 {
+			t.Errorf("Idle/Unanswered seconds = %d/%d, want 600/600", sum.IdleSeconds, sum.UnansweredSeconds)
+		}
+	})
+
+	t.Run("プロジェクト省略で全プロジェクト", func(t *testing.T) {
+		res, err := s.Query(Query{From: from, To: to})
+		if err != nil {
+			t.Fatalf("Query error: %v", err)
+		}
+		if len(res.Summaries) != 2 {
+			t.Errorf("summaries = %d, want 2", len(res.Summaries))
+		}
+	})
+
+	t.Run("期間省略は直近24時間", func(t *testing.T) {
+		res, err := s.Query(Query{})
+		if err != nil {
+			t.Fatalf("Query error: %v", err)
+		}
+		if got := res.To.Sub(res.From); got != 24*time.Hour {
+			t.Errorf("range = %s, want 24h", got)
+		}
+		if len(res.Samples) != 4 {
+			t.Errorf("samples = %d, want 4", len(res.Samples))
+		}
+	})
+
+	invalid := []struct {
+		name string
+		q    Query
+	}{
+		{"fromがtoより後", Query{From: to, To: from}},
+		{"31日超", Query{From: from.AddDate(0, 0, -32), To: from}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Query(tt.q); !errors.Is(err, ErrValidation) {
+				t.Errorf("err = %v, want ErrValidation", err)
+			}
+		})
+	}
+}
+
+func TestSummarize_記録の途切れは数えない(t *testing.T) {
+	t0 := time.Date(2026, 7, 20, 12, 0, 0, 0, time.UTC)
+	samples := []Sample{
+		{Time: t0, Project: "/a", Running: true},
+		{Time: t0.Add(5 * time.Minute), Project: "/a", Running: true, Stuck: true},
+		// サーバー停止で1時間途切れた
+		{Time: t0.Add(65 * time.Minute), Project: "/a"},
+	}
+
+	got := Summarize(samples, t0.Add(70*time.Minute))
+	if len(got) != 1 {
+		t.Fatalf("summaries = %d, want 1", len(got))
+	}
+	if got[0].RunningSeconds != 300 {
+		t.Errorf("RunningSeconds = %d, want 300", got[0].RunningSeconds)
+	}
+	if got[0].StuckSeconds != 0 {
+		t.Errorf("StuckSeconds = %d, want 0", got[0].StuckSeconds)
+	}
+	if got[0].Samples != 3 {
+		t.Errorf("Samples = %d, want 3", got[0].Samples)
+	}
+}
@@ -0,0 +1,73 @@
+package history
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// ErrValidation は照会条件が不正な場合のエラーです。
+var ErrValidation = errors.New("validation error")
+
+// Kanban はカンバン各レーンの件数です（dashboard.KanbanCounts と同じ形）。
+type Kanban struct {
+	Reviewing int `json:"reviewing"`
+	Waiting   int `json:"waiting"`
+	Running   int `json:"running"`
+	Done      int `json:"done"`
+}
+
+// OpsStatus は運用エントリ1件の状態です。
+type OpsStatus struct {
+	Account string `json:"account"`
+	Kind    string `json:"kind"`
+	Status  string `json:"status"`
+	// Stale は状態ファイルの更新が止まっている（dashboard.OpsEntry.Stale）かを表します。
+	Stale bool `json:"stale,omitempty"`
+}
+
+// Sample は1プロジェクトのある時点の状態です。状態が変わった時と心拍（heartbeatInterval）ごとに記録します。
+type Sample struct {
+	Time       time.Time   `json:"t"`
+	Project    string      `json:"project"`
+	Kanban     Kanban      `json:"kanban"`
+	Idle       bool        `json:"idle,omitempty"`
+	Running    bool        `json:"running,omitempty"`
+	Stuck      bool        `json:"stuck,omitempty"`
+	Unanswered int         `json:"unanswered,omitempty"`
+	Ops        []OpsStatus `json:"ops,omitempty"`
+}
+
+// sameState は時刻以外の状態が同じかを返します。
+func (s Sample) sameState(o Sample) bool {
+	return s.Project == o.Project && s.Kanban == o.Kanban && s.Idle == o.Idle && s.Running == o.Running &&
+		s.Stuck == o.Stuck && s.Unanswered == o.Unanswered && slices.Equal(s.Ops, o.Ops)
+}
+
+// Summary は期間内の1プロジェクトの集計です（グラフの凡例・概要表示用）。
+// 各 *Seconds はその状態だった合計秒数で、サーバー停止などで記録が途切れた区間は数えません。
+type Summary struct {
+	Project           string `json:"project"`
+	Samples           int    `json:"samples"`
+	IdleSeconds       int64  `json:"idleSeconds"`
+	RunningSeconds    int64  `json:"runningSeconds"`
+	StuckSeconds      int64  `json:"stuckSeconds"`
+	UnansweredSeconds int64  `json:"unansweredSeconds"`
+	// Completed は完了レーンの増加数の合計です（スループット）。
+	Completed int `json:"completed"`
+}
+
+// Query は時系列の照会条件です。Project が空なら全プロジェクトです。
+type Query struct {
+	Project string
+	From    time.Time
+	To      time.Time
+}
+
+// Result は時系列の照会結果です。
+type Result struct {
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Samples   []Sample  `json:"samples"`
+	Summaries []Summary `json:"summaries"`
+}
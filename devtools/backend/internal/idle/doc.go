@@ -17,7 +17,8 @@
 //
 // # 主要な型
 //
-//   - Marker: 1セッションの質問待ち状態（cwd, session_id, epoch秒のtimestamp, 要約等）
+//   - Marker: 1セッションの質問待ち状態（cwd, session_id, epoch秒のtimestamp, 待機理由, 要約等）
+//   - WaitReason: 質問待ちの理由（question / plan_approval / permission / end_of_turn）と日本語表示名
 //   - RawTail: 検出時点の会話末尾（要約前の生テキスト。lastAssistant / lastPrompt）
 //   - Reader: 質問待ちの読み取りを抽象化するインターフェース（transcript が実装）
 //   - Writer: 要約書き戻しを抽象化するインターフェース（summaryCacheWriter が実装）
@@ -32,6 +33,8 @@
 //     新スライスをイミュータブルに返す（reader の List 内で呼び Summary 込み Marker を返す契約）。
 //   - PruneSummaryCache: 現存 marker 以外の孤児キャッシュを掃除する。
 //   - CacheKey: 要約キャッシュのファイル名キー（<sessionID>_<timestamp>）を生成する。
+//   - WriteProgressCache / ReadProgressCache / LatestProgressCache / PruneProgressCache: 動作中セッションの
+//     進捗要約のキャッシュ。key は CacheKey(sessionID, 会話ログの位置) で、同じセッションの古い位置は書き込み時に消す。
 //   - MatchProject: cwd がどの登録プロジェクトに属するかをパス前方一致で判定する。
 //     複数一致時は最長一致（最も深いパス）を優先し、セグメント境界を担保する。
 //   - IsExpired: 待機が TTL を超過して失効しているかを判定する。
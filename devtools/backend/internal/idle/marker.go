@@ -6,6 +6,9 @@ import "context"
 type RawTail struct {
 	LastAssistant string `json:"lastAssistant"`
 	LastPrompt    string `json:"lastPrompt"`
+	// QuestionHeader / QuestionOptions は質問待ち（WaitQuestion）の AskUserQuestion の見出しと選択肢ラベルです
+	QuestionHeader  string   `json:"questionHeader,omitempty"`
+	QuestionOptions []string `json:"questionOptions,omitempty"`
 }
 
 // Status はセッションの状態種別を表します。
@@ -20,19 +23,54 @@ const (
 	StatusRunning Status = "running"
 )
 
+// WaitReason は質問待ち（StatusWaiting）の理由種別を表します。
+// 何を待っているかでユーザーが取るべき行動（回答・計画承認・実行許可・返信）が変わるため、
+// reader（transcript）が会話末尾から判定して Marker に載せ、dashboard が表示と導線を分けます。
+type WaitReason string
+
+const (
+	// WaitQuestion は AskUserQuestion による質問への回答待ちです。
+	WaitQuestion WaitReason = "question"
+	// WaitPlanApproval は ExitPlanMode による計画の承認待ちです。
+	WaitPlanApproval WaitReason = "plan_approval"
+	// WaitPermission はツール実行の許可プロンプト待ちです（未応答 tool_use の滞留から推定）。
+	WaitPermission WaitReason = "permission"
+	// WaitEndOfTurn は回答を提示してターンを終えた後のユーザー返信待ちです。
+	WaitEndOfTurn WaitReason = "end_of_turn"
+)
+
+// Label は待機理由の日本語表示名を返します。未知・空の理由は汎用の「質問待ち」です。
+func (r WaitReason) Label() string {
+	switch r {
+	case WaitQuestion:
+		return "質問への回答待ち"
+	case WaitPlanApproval:
+		return "計画の承認待ち"
+	case WaitPermission:
+		return "実行許可待ち"
+	case WaitEndOfTurn:
+		return "返信待ち"
+	}
+	return "質問待ち"
+}
+
 // Marker は1セッションの代表状態マーカーを表します。
 // reader がプロジェクト毎に最新 mtime の代表1件へ collapse して返します。
 // Timestamp の意味は Status で分岐します: waiting は待機開始 entry-time（要約 key の同一性用・C1）、
 // running は代表セッションの mtime です（epoch 秒）。
+// WaitReason は waiting の場合のみ設定します（running では空）。
 type Marker struct {
-	Cwd          string  `json:"cwd"`
-	SessionID    string  `json:"session_id"`
-	Timestamp    int64   `json:"timestamp"`
-	Status       Status  `json:"status,omitempty"`
-	SessionCount int     `json:"sessionCount,omitempty"`
-	RawTail      RawTail `json:"rawTail"`
-	Summary      string  `json:"summary"`
-	SummarizedAt string  `json:"summarizedAt"`
+	Cwd        string     `json:"cwd"`
+	SessionID  string     `json:"session_id"`
+	Timestamp  int64      `json:"timestamp"`
+	Status     Status     `json:"status,omitempty"`
+	WaitReason WaitReason `json:"waitReason,omitempty"`
+	// TranscriptPath は代表セッションの会話ログ JSONL のパスです（停滞監視が進捗の追跡に使う）。
+	TranscriptPath string  `json:"transcriptPath,omitempty"`
+	SessionCount   int     `json:"sessionCount,omitempty"`
+	RawTail        RawTail `json:"rawTail"`
+	Summary        string  `json:"summary"`
+	SummarizedAt   string  `json:"summarizedAt"`
 }
 
 // Reader は質問待ちマーカーの読み取りを提供します。
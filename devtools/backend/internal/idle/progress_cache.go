@@ -0,0 +1,131 @@
+package idle
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ProgressCacheEntry は実行中セッションの進捗要約1件の永続形式です。
+// key（ファイル名）は CacheKey(sessionID, offset) で、offset は要約に含めた会話ログの末尾位置です。
+// 同じ位置までの要約は再生成せずキャッシュを使い、サーバー再起動後も続きの差分から要約を再開します。
+type ProgressCacheEntry struct {
+	SessionID string `json:"sessionId"`
+	Offset    int64  `json:"offset"`
+	Summary   string `json:"summary"`
+	// EditedFiles はここまでに編集・作成したファイル（前回までの分を含む）です
+	EditedFiles  []string `json:"editedFiles,omitempty"`
+	SummarizedAt string   `json:"summarizedAt"`
+}
+
+// WriteProgressCache は進捗要約を cacheDir へ保存し、同じセッションの古い位置のキャッシュを削除します。
+// 書き込みは temp+rename で原子的に行います。
+func WriteProgressCache(cacheDir string, entry ProgressCacheEntry) error {
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return fmt.Errorf("failed to create progress cache dir %s: %w", cacheDir, err)
+	}
+
+	path := filepath.Join(cacheDir, CacheKey(entry.SessionID, entry.Offset)+".json")
+	data, err := json.MarshalIndent(&entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal progress cache entry %s: %w", path, err)
+	}
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write temp progress cache %s: %w", tmpFile, err)
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		if rmErr := os.Remove(tmpFile); rmErr != nil {
+			log.Printf("[idle] failed to remove temp progress cache: path=%s, error=%v", tmpFile, rmErr)
+		}
+		return fmt.Errorf("failed to rename progress cache %s: %w", path, err)
+	}
+
+	for offset, old := range progressCachePaths(cacheDir, entry.SessionID) {
+		if offset == entry.Offset {
+			continue
+		}
+		if err := os.Remove(old); err != nil && !os.IsNotExist(err) {
+			log.Printf("[idle] failed to remove old progress cache: path=%s, error=%v", old, err)
+		}
+	}
+	return nil
+}
+
+// ReadProgressCache は sessionID の offset 位置までの進捗要約を読みます。
+// 不在・壊れ JSON は (空, false) を返します。
+func ReadProgressCache(cacheDir, sessionID string, offset int64) (ProgressCacheEntry, bool) {
+	return readProgressCacheFile(filepath.Join(cacheDir, CacheKey(sessionID, offset)+".json"))
+}
+
+// LatestProgressCache は sessionID の最も進んだ位置の進捗要約を読みます（不在なら (空, false)）。
+func LatestProgressCache(cacheDir, sessionID string) (ProgressCacheEntry, bool) {
+	latest, found := int64(-1), ""
+	for offset, path := range progressCachePaths(cacheDir, sessionID) {
+		if offset > latest {
+			latest, found = offset, path
+		}
+	}
+	if found == "" {
+		return ProgressCacheEntry{}, false
+	}
+	return readProgressCacheFile(found)
+}
+
+// PruneProgressCache は aliveSessions（sessionID の集合）に含まれないセッションの進捗要約を削除します。
+func PruneProgressCache(cacheDir string, aliveSessions map[string]bool) error {
+	paths, err := filepath.Glob(filepath.Join(cacheDir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("failed to glob progress cache %s: %w", cacheDir, err)
+	}
+	alive := make(map[string]bool, len(aliveSessions))
+	for id := range aliveSessions {
+		alive[sanitizeSessionID(id)] = true
+	}
+	for _, path := range paths {
+		key := strings.TrimSuffix(filepath.Base(path), ".json")
+		i := strings.LastIndexByte(key, '_')
+		if i > 0 && alive[key[:i]] {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Printf("[idle] failed to prune progress cache: path=%s, error=%v", path, err)
+		}
+	}
+	return nil
+}
+
+// progressCachePaths は sessionID の進捗要約ファイルを offset → パスで返します。
+func progressCachePaths(cacheDir, sessionID string) map[int64]string {
+	prefix := sanitizeSessionID(sessionID) + "_"
+	paths, err := filepath.Glob(filepath.Join(cacheDir, prefix+"*.json"))
+	if err != nil {
+		return nil
+	}
+	out := make(map[int64]string, len(paths))
+	for _, path := range paths {
+		raw := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), prefix), ".json")
+		if offset, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			out[offset] = path
+		}
+	}
+	return out
+}
+
+// readProgressCacheFile は進捗要約1件を読みます。不在・壊れ JSON は (空, false) を返します。
+func readProgressCacheFile(path string) (ProgressCacheEntry, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ProgressCacheEntry{}, false
+	}
+	var entry ProgressCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		log.Printf("[idle] skip progress cache (invalid JSON): path=%s, error=%v", path, err)
+		return ProgressCacheEntry{}, false
+	}
+	return entry, true
+}
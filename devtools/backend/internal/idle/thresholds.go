@@ -26,4 +26,10 @@ const (
 	// これを超えた midTurn は Ctrl-C / クラッシュで固まった last=tool_use とみなし none へ倒します
 	// （固まったセッションが最大 TTL(6h) にわたり青表示され続けるのを防ぐ）。
 	RunningMaxAge = 10 * time.Minute
+
+	// PermissionWaitAge は未応答 tool_use（許可が要るツール）を実行許可待ちとみなす最小滞留時間です。
+	// 許可プロンプトは会話ログに記録されないため、tool_use の結果が一定時間届かないことから推定します。
+	// これ未満は通常の実行中（running）、これ以上 RunningMaxAge 未満を waiting（permission）とします。
+	// 長時間かかるコマンド（ビルド・テスト）を許可待ちと誤認しにくいよう MinAge より長く取ります。
+	PermissionWaitAge = 3 * time.Minute
 )
@@ -0,0 +1,26 @@
+// Package metrics は Prometheus テキスト形式（text/plain; version=0.0.4）で公開する運用メトリクスを提供する。
+//
+// # 概要
+//
+// 夜間に放置するサーバーをローカルの Prometheus / Grafana から監視できるよう、Claude 実行数・巡回スロットの
+// 使用数・ダッシュボードのスキャン時間・会話ログパースキャッシュのヒット数・要約の試行数・TTS キャッシュ・
+// SSE 接続数などを GET /metrics で公開する。外部ライブラリ（client_golang）は使わず、必要な
+// Counter / Gauge / Histogram とテキスト出力のみを実装する。
+//
+// # 主要な型・関数
+//
+//   - Registry / NewRegistry / Default: メトリクスの登録先と、各パッケージが共有する既定の登録先
+//   - CounterVec / NewCounterVec: 単調増加のカウンタ（ラベル付き）
+//   - GaugeVec / NewGaugeVec: 増減するゲージ（ラベル付き）
+//   - HistogramVec / NewHistogramVec: 累積バケットのヒストグラム（ラベル付き）
+//   - Registry.GaugeFunc: 収集時に関数で値を読むゲージ（インスタンスが持つ値の公開用）
+//   - Registry.WriteText: テキスト形式の出力
+//
+// # 設計方針
+//
+//   - 計測する側のパッケージは package 変数でメトリクスを定義し、Default へ登録する
+//     （サービスの生成回数に依存せず、テストで何度生成しても二重登録にならない）
+//   - 同名の二重登録・ラベル数の不一致はプログラミングエラーとして panic する
+//   - ラベル値は有限集合（コマンド名・結果種別など）に限り、パスやIDは載せない（系列数の爆発を防ぐ）
+//   - 出力はメトリクス名・ラベル値の順にソートし、スクレイプごとに安定させる
+package metrics
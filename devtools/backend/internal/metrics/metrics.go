@@ -0,0 +1,344 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets は秒単位の所要時間向けの既定バケットです。
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Default は各パッケージが共有する既定の Registry です。
+var Default = NewRegistry()
+
+// collector は Registry に登録されテキスト出力されるメトリクスです。
+type collector interface {
+	name() string
+	write(w *bufio.Writer)
+}
+
+// Registry はメトリクスの登録先です。
+type Registry struct {
+	mu         sync.Mutex
+	collectors map[string]collector
+}
+
+// NewRegistry は新しい Registry を生成します。
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]collector)}
+}
+
+// register は collector を登録します。同名が登録済みなら panic します。
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.collectors[c.name()]; ok {
+		panic(fmt.Sprintf("metrics: duplicate registration: %s", c.name()))
+	}
+	r.collectors[c.name()] = c
+}
+
+// WriteText は登録済みの全メトリクスを Prometheus テキスト形式で w へ書き出します。
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	list := make([]collector, 0, len(r.collectors))
+	for _, c := range r.collectors {
+		list = append(list, c)
+	}
+	r.mu.Unlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].name() < list[j].name() })
+
+	bw := bufio.NewWriter(w)
+	for _, c := range list {
+		c.write(bw)
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	return nil
+}
+
+// desc はメトリクス名・説明・ラベル名の共通部分です。
+type desc struct {
+	metricName string
+	help       string
+	labels     []string
+}
+
+func (d *desc) name() string { return d.metricName }
+
+// key はラベル値の組を系列のキーへ変換します。ラベル数が一致しなければ panic します。
+func (d *desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metrics: %s: expected %d label values, got %d", d.metricName, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// header は HELP / TYPE 行を書き出します。
+func (d *desc) header(w *bufio.Writer, typ string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", d.metricName, escapeHelp(d.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", d.metricName, typ)
+}
+
+// labelString は {k="v",...} 形式のラベル表記を返します（ラベル無しは空文字）。
+// extra は histogram の le のような追加ラベルです。
+func (d *desc) labelString(key string, extra ...string) string {
+	pairs := make([]string, 0, len(d.labels)+1)
+	if len(d.labels) > 0 {
+		for i, v := range strings.Split(key, "\xff") {
+			pairs = append(pairs, fmt.Sprintf(`%s="%s"`, d.labels[i], escapeLabel(v)))
+		}
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extra[i], escapeLabel(extra[i+1])))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// sortedKeys は系列キーをソートして返します。
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// CounterVec はラベル付きの単調増加カウンタです。
+type CounterVec struct {
+	desc
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewCounterVec は Default に登録したカウンタを生成します。
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return Default.NewCounterVec(name, help, labels...)
+}
+
+// NewCounterVec は r に登録したカウンタを生成します。
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{desc: desc{metricName: name, help: help, labels: labels}, values: make(map[string]float64)}
+	r.register(c)
+	return c
+}
+
+// Inc はラベル値の系列を1増やします。
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add はラベル値の系列を v 増やします。負の値は無視します（カウンタは減らない）。
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	k := c.key(labelValues)
+	c.mu.Lock()
+	c.values[k] += v
+	c.mu.Unlock()
+}
+
+// Value はラベル値の系列の現在値を返します（主にテスト用）。
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	k := c.key(labelValues)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[k]
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.header(w, "counter")
+	for _, k := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.metricName, c.labelString(k), formatFloat(c.values[k]))
+	}
+}
+
+// GaugeVec はラベル付きの増減するゲージです。
+type GaugeVec struct {
+	desc
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewGaugeVec は Default に登録したゲージを生成します。
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return Default.NewGaugeVec(name, help, labels...)
+}
+
+// NewGaugeVec は r に登録したゲージを生成します。
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{desc: desc{metricName: name, help: help, labels: labels}, values: make(map[string]float64)}
+	r.register(g)
+	return g
+}
+
+// Set はラベル値の系列を v にします。
+func (g *GaugeVec) Set(v float64, labelValues ...string) {
+	k := g.key(labelValues)
+	g.mu.Lock()
+	g.values[k] = v
+	g.mu.Unlock()
+}
+
+// Add はラベル値の系列に v を加えます（負の値で減らす）。
+func (g *GaugeVec) Add(v float64, labelValues ...string) {
+	k := g.key(labelValues)
+	g.mu.Lock()
+	g.values[k] += v
+	g.mu.Unlock()
+}
+
+// Inc はラベル値の系列を1増やします。
+func (g *GaugeVec) Inc(labelValues ...string) { g.Add(1, labelValues...) }
+
+// Dec はラベル値の系列を1減らします。
+func (g *GaugeVec) Dec(labelValues ...string) { g.Add(-1, labelValues...) }
+
+// Value はラベル値の系列の現在値を返します（主にテスト用）。
+func (g *GaugeVec) Value(labelValues ...string) float64 {
+	k := g.key(labelValues)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.values[k]
+}
+
+func (g *GaugeVec) write(w *bufio.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.header(w, "gauge")
+	for _, k := range sortedKeys(g.values) {
+		fmt.Fprintf(w, "%s%s %s\n", g.metricName, g.labelString(k), formatFloat(g.values[k]))
+	}
+}
+
+// gaugeFunc は収集時に関数で値を読むラベル無しゲージです。
+type gaugeFunc struct {
+	desc
+	fn func() float64
+}
+
+// GaugeFunc は収集時に fn を呼んで値を読むゲージを r に登録します。
+// サービスのインスタンスが保持する値（キャッシュのバイト数など）の公開に使います。
+func (r *Registry) GaugeFunc(name, help string, fn func() float64) {
+	r.register(&gaugeFunc{desc: desc{metricName: name, help: help}, fn: fn})
+}
+
+func (g *gaugeFunc) write(w *bufio.Writer) {
+	g.header(w, "gauge")
+	fmt.Fprintf(w, "%s %s\n", g.metricName, formatFloat(g.fn()))
+}
+
+// histogramSeries は1系列分のヒストグラムの集計値です。
+type histogramSeries struct {
+	counts []uint64 // buckets と同じ長さ（各バケット以下の件数。累積は出力時に計算）
+	count  uint64
+	sum    float64
+}
+
+// HistogramVec はラベル付きのヒストグラムです。
+type HistogramVec struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+// NewHistogramVec は Default に登録したヒストグラムを生成します。buckets が空なら DefaultBuckets を使います。
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return Default.NewHistogramVec(name, help, buckets, labels...)
+}
+
+// NewHistogramVec は r に登録したヒストグラムを生成します。buckets が空なら DefaultBuckets を使います。
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	b := append([]float64(nil), buckets...)
+	sort.Float64s(b)
+	h := &HistogramVec{desc: desc{metricName: name, help: help, labels: labels}, buckets: b, series: make(map[string]*histogramSeries)}
+	r.register(h)
+	return h
+}
+
+// Observe はラベル値の系列に観測値 v を記録します。
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	k := h.key(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[k]
+	if !ok {
+		s = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[k] = s
+	}
+	for i, ub := range h.buckets {
+		if v <= ub {
+			s.counts[i]++
+			break
+		}
+	}
+	s.count++
+	s.sum += v
+}
+
+// Count はラベル値の系列の観測数を返します（主にテスト用）。
+func (h *HistogramVec) Count(labelValues ...string) uint64 {
+	k := h.key(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s, ok := h.series[k]; ok {
+		return s.count
+	}
+	return 0
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.header(w, "histogram")
+	for _, k := range sortedKeys(h.series) {
+		s := h.series[k]
+		var cum uint64
+		for i, ub := range h.buckets {
+			cum += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, h.labelString(k, "le", formatFloat(ub)), cum)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, h.labelString(k, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.metricName, h.labelString(k), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.metricName, h.labelString(k), s.count)
+	}
+}
+
+// formatFloat はテキスト形式の数値表記を返します。
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistryWriteText(t *testing.T) {
+	r := NewRegistry()
+	runs := r.NewCounterVec("test_runs_total", "Runs by result.", "command", "result")
+	subs := r.NewGaugeVec("test_subscribers", "SSE subscribers.", "stream")
+	dur := r.NewHistogramVec("test_duration_seconds", "Scan duration.", []float64{0.1, 1})
+	r.GaugeFunc("test_cache_bytes", "Cache bytes.", func() float64 { return 2048 })
+
+	runs.Inc("plan", "started")
+	runs.Inc("plan", "started")
+	runs.Inc("fullstack", "errored")
+	runs.Add(-1, "plan", "started") // カウンタは減らない
+	subs.Inc("dashboard")
+	subs.Inc("dashboard")
+	subs.Dec("dashboard")
+	subs.Set(3, `pa"trol`)
+	dur.Observe(0.05)
+	dur.Observe(0.5)
+	dur.Observe(5)
+
+	var sb strings.Builder
+	if err := r.WriteText(&sb); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+
+	want := `# HELP test_cache_bytes Cache bytes.
+# TYPE test_cache_bytes gauge
+test_cache_bytes 2048
+# HELP test_duration_seconds Scan duration.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{le="0.1"} 1
+test_duration_seconds_bucket{le="1"} 2
+test_duration_seconds_bucket{le="+Inf"} 3
+test_duration_seconds_sum 5.55
+test_duration_seconds_count 3
+# HELP test_runs_total Runs by result.
+# TYPE test_runs_total counter
+test_runs_total{command="fullstack",result="errored"} 1
+test_runs_total{command="plan",result="started"} 2
+# HELP test_subscribers SSE subscribers.
+# TYPE test_subscribers gauge
+test_subscribers{stream="dashboard"} 1
+test_subscribers{stream="pa\"trol"} 3
+`
+	if got := sb.String(); got != want {
+		t.Errorf("WriteText mismatch:\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+}
+
+func TestRegistry_Panics(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(r *Registry)
+	}{
+		{"同名の二重登録", func(r *Registry) {
+			r.NewCounterVec("dup", "a")
+			r.NewGaugeVec("dup", "b")
+		}},
+		{"ラベル数の不一致", func(r *Registry) {
+			r.NewCounterVec("c", "a", "x").Inc("1", "2")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			tt.fn(NewRegistry())
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultConfigFile は状態ディレクトリ配下の既定の通知設定ファイル名です
+	DefaultConfigFile = "notify.yaml"
+	// defaultNtfyServer は ntfy の既定のサーバーです
+	defaultNtfyServer = "https://ntfy.sh"
+	// defaultSMTPPort は SMTP の既定のポート（STARTTLS）です
+	defaultSMTPPort = 587
+	// defaultMaxAttempts は1通知・1チャネルあたりの既定の配送試行回数です
+	defaultMaxAttempts = 4
+	// defaultInitialBackoff は再試行の既定の初回待ち時間です（以降は倍々）
+	defaultInitialBackoff = 2 * time.Second
+	// defaultMaxBackoff は再試行の待ち時間の既定の上限です
+	defaultMaxBackoff = time.Minute
+)
+
+// チャネルの種類です
+const (
+	TypeNtfy             = "ntfy"
+	TypeWebhook          = "webhook"
+	TypeSlack            = "slack"
+	TypeEmail            = "email"
+	TypeNotifySend       = "notify-send"
+	TypeTerminalNotifier = "terminal-notifier"
+)
+
+// Config は通知設定（<stateDir>/notify.yaml）です
+type Config struct {
+	// Channels はチャネル名ごとの配送先です
+	Channels map[string]ChannelConfig `yaml:"channels"`
+	// Routes は種類・プロジェクトごとの通知先です。上から順に照合し、最初に一致したルートを使います
+	Routes []Route `yaml:"routes"`
+	// Default はどのルートにも一致しない通知の通知先です（省略時は全チャネル）
+	Default []string `yaml:"default"`
+	Retry   Retry    `yaml:"retry"`
+	// Rules は静音時間・重複抑制・送信数の上限・未回答の再通知のルールです（省略時はすべて無効）
+	Rules Rules `yaml:"rules"`
+}
+
+// ChannelConfig は1つのチャネルの設定です。使う項目は type ごとに異なります
+type ChannelConfig struct {
+	// Type は ntfy / webhook / slack / email / notify-send / terminal-notifier
+	Type string `yaml:"type"`
+	// MinPriority はこの優先度未満の通知を送らない（省略時は全て送る）
+	MinPriority Priority `yaml:"minPriority"`
+
+	// ntfy: Server（既定 https://ntfy.sh。セルフホスト可）、Topic、Token（アクセストークン）
+	Server string `yaml:"server"`
+	Topic  string `yaml:"topic"`
+	Token  string `yaml:"token"`
+
+	// webhook / slack: URL（Incoming Webhook の URL）、Headers（webhook のみ。認証ヘッダー等）
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+
+	// email: SMTP サーバーと差出人・宛先。Username が空なら認証しません
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+// Route は通知先の選択ルールです
+type Route struct {
+	// Events は対象の種類（"ops.*" で前方一致、"*" または省略で全て）
+	Events []string `yaml:"events"`
+	// Projects は対象のプロジェクト（パスまたはディレクトリ名。省略で全て）
+	Projects []string `yaml:"projects"`
+	// Channels は通知先のチャネル名。空リスト [] は「通知しない」を意味します
+	Channels []string `yaml:"channels"`
+}
+
+// Retry は配送失敗時の再試行の設定です
+type Retry struct {
+	// MaxAttempts は1チャネルあたりの試行回数（初回を含む。既定 4）
+	MaxAttempts int `yaml:"maxAttempts"`
+	// InitialBackoff は初回の待ち時間（既定 "2s"。以降は倍々）
+	InitialBackoff string `yaml:"initialBackoff"`
+	// MaxBackoff は待ち時間の上限（既定 "1m"）
+	MaxBackoff string `yaml:"maxBackoff"`
+
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// LoadConfig は通知設定を読み込みます。ファイルが無い場合は環境変数（NTFY_TOPIC 等）から組み立てます。
+// token / url / password / headers の値の ${VAR} は getenv で展開するため、秘密情報はファイルに書かずに済みます
+func LoadConfig(path string, getenv func(string) string, lookPath func(string) (string, error)) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ConfigFromEnv(getenv, lookPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var cfg Config
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: invalid notify config %s: %v", ErrValidation, path, err)
+	}
+	for name, ch := range cfg.Channels {
+		ch.Token = os.Expand(ch.Token, getenv)
+		ch.URL = os.Expand(ch.URL, getenv)
+		ch.Password = os.Expand(ch.Password, getenv)
+		for k, v := range ch.Headers {
+			ch.Headers[k] = os.Expand(v, getenv)
+		}
+		cfg.Channels[name] = ch
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid notify config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// ConfigFromEnv は設定ファイルが無いときの通知設定を環境変数から組み立てます。
+// NTFY_TOPIC があれば ntfy（NTFY_SERVER / NTFY_TOKEN でセルフホスト）と、見つかればデスクトップ通知
+// （terminal-notifier、無ければ notify-send）を全通知の通知先にします。NTFY_TOPIC が無ければチャネル無しです
+func ConfigFromEnv(getenv func(string) string, lookPath func(string) (string, error)) (*Config, error) {
+	cfg := &Config{Channels: make(map[string]ChannelConfig)}
+	topic := getenv("NTFY_TOPIC")
+	if topic == "" {
+		return cfg, nil
+	}
+	cfg.Channels["ntfy"] = ChannelConfig{
+		Type:   TypeNtfy,
+		Server: getenv("NTFY_SERVER"),
+		Topic:  topic,
+		Token:  getenv("NTFY_TOKEN"),
+	}
+	for _, typ := range []string{TypeTerminalNotifier, TypeNotifySend} {
+		if _, err := lookPath(typ); err == nil {
+			cfg.Channels["desktop"] = ChannelConfig{Type: typ}
+			break
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate は設定を検証し、既定値を補います。問題はすべてまとめて返します
+func (c *Config) Validate() error {
+	var errs []error
+	for _, name := range c.ChannelNames() {
+		ch := c.Channels[name]
+		if err := ch.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%w: channels.%s: %v", ErrValidation, name, err))
+		}
+		c.Channels[name] = ch
+	}
+	checkRefs := func(where string, names []string) {
+		for _, n := range names {
+			if _, ok := c.Channels[n]; !ok {
+				errs = append(errs, fmt.Errorf("%w: %s: unknown channel: %s", ErrValidation, where, n))
+			}
+		}
+	}
+	for i, r := range c.Routes {
+		where := fmt.Sprintf("routes[%d]", i)
+		if r.Channels == nil {
+			errs = append(errs, fmt.Errorf("%w: %s: channels is required (use [] to drop)", ErrValidation, where))
+		}
+		checkRefs(where, r.Channels)
+	}
+	checkRefs("default", c.Default)
+
+	if c.Retry.MaxAttempts < 0 {
+		errs = append(errs, fmt.Errorf("%w: retry.maxAttempts must not be negative: %d", ErrValidation, c.Retry.MaxAttempts))
+	} else if c.Retry.MaxAttempts == 0 {
+		c.Retry.MaxAttempts = defaultMaxAttempts
+	}
+	parse := func(key, raw string, def time.Duration, dst *time.Duration) {
+		if raw == "" {
+			*dst = def
+			return
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			errs = append(errs, fmt.Errorf("%w: retry.%s must be a positive duration: %q", ErrValidation, key, raw))
+			return
+		}
+		*dst = d
+	}
+	parse("initialBackoff", c.Retry.InitialBackoff, defaultInitialBackoff, &c.Retry.initialBackoff)
+	parse("maxBackoff", c.Retry.MaxBackoff, defaultMaxBackoff, &c.Retry.maxBackoff)
+	errs = append(errs, c.Rules.validate()...)
+	return errors.Join(errs...)
+}
+
+// validate は種類ごとの必須項目を検証し、既定値を補います
+func (ch *ChannelConfig) validate() error {
+	switch ch.MinPriority {
+	case "", PriorityLow, PriorityDefault, PriorityHigh:
+	default:
+		return fmt.Errorf("unknown minPriority: %s", ch.MinPriority)
+	}
+	switch ch.Type {
+	case TypeNtfy:
+		if ch.Server == "" {
+			ch.Server = defaultNtfyServer
+		}
+		if err := validateURL(ch.Server); err != nil {
+			return fmt.Errorf("server: %v", err)
+		}
+		if ch.Topic == "" {
+			return fmt.Errorf("topic is required")
+		}
+	case TypeWebhook, TypeSlack:
+		if err := validateURL(ch.URL); err != nil {
+			return fmt.Errorf("url: %v", err)
+		}
+	case TypeEmail:
+		if ch.Host == "" || ch.From == "" || len(ch.To) == 0 {
+			return fmt.Errorf("host, from and to are required")
+		}
+		if ch.Port == 0 {
+			ch.Port = defaultSMTPPort
+		}
+		if ch.Port < 1 || ch.Port > 65535 {
+			return fmt.Errorf("port must be 1-65535: %d", ch.Port)
+		}
+	case TypeNotifySend, TypeTerminalNotifier:
+	case "":
+		return fmt.Errorf("type is required")
+	default:
+		return fmt.Errorf("unknown type: %s", ch.Type)
+	}
+	return nil
+}
+
+// validateURL は http / https の絶対 URL かを検証します（値は秘密を含みうるため出力しません）
+func validateURL(raw string) error {
+	if raw == "" {
+		return fmt.Errorf("is required")
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an http(s) URL")
+	}
+	return nil
+}
+
+// ChannelNames はチャネル名を名前順で返します
+func (c *Config) ChannelNames() []string {
+	names := make([]string, 0, len(c.Channels))
+	for name := range c.Channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// channelsFor は msg の通知先のチャネル名を返します。
+// 最初に一致したルートのチャネル、一致しなければ Default（省略時は全チャネル）です
+func (c *Config) channelsFor(msg Message) []string {
+	for _, r := range c.Routes {
+		if r.matches(msg) {
+			return r.Channels
+		}
+	}
+	if c.Default != nil {
+		return c.Default
+	}
+	return c.ChannelNames()
+}
+
+// matches はルートが msg の種類とプロジェクトに一致するかを返します
+func (r Route) matches(msg Message) bool {
+	if len(r.Events) > 0 && !slices.ContainsFunc(r.Events, func(p string) bool { return matchEvent(p, msg.Event) }) {
+		return false
+	}
+	if len(r.Projects) > 0 && !slices.ContainsFunc(r.Projects, func(p string) bool {
+		return msg.Project != "" && (p == msg.Project || p == filepath.Base(msg.Project))
+	}) {
+		return false
+	}
+	return true
+}
+
+// matchEvent は種類のパターン（完全一致、"ops.*" の前方一致、"*" の全一致）を照合します
+func matchEvent(pattern string, ev Event) bool {
+	if pattern == "*" {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(string(ev), prefix)
+	}
+	return pattern == string(ev)
+}
+
+// allows は優先度が MinPriority 以上かを返します
+func (ch ChannelConfig) allows(p Priority) bool {
+	return priorityRank(p) >= priorityRank(ch.MinPriority)
+}
+
+// priorityRank は優先度の順位を返します（未指定は最低）
+func priorityRank(p Priority) int {
+	switch p {
+	case PriorityHigh:
+		return 2
+	case PriorityDefault:
+		return 1
+	}
+	return 0
+}
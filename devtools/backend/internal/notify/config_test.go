@@ -0,0 +1,166 @@
+package notify
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func envOf(m map[string]string) func(string) string {
+	return func(k string) string { return m[k] }
+}
+
+func noLookPath(string) (string, error) { return "", errors.New("not found") }
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), DefaultConfigFile)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig_EnvFallback(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), DefaultConfigFile)
+
+	cfg, err := LoadConfig(missing, envOf(nil), noLookPath)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if len(cfg.Channels) != 0 {
+		t.Errorf("channels without NTFY_TOPIC = %v, want none", cfg.ChannelNames())
+	}
+
+	cfg, err = LoadConfig(missing, envOf(map[string]string{"NTFY_TOPIC": "gr", "NTFY_TOKEN": "tk"}),
+		func(name string) (string, error) {
+			if name == TypeNotifySend {
+				return "/usr/bin/notify-send", nil
+			}
+			return "", errors.New("not found")
+		})
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	ntfy := cfg.Channels["ntfy"]
+	if ntfy.Server != defaultNtfyServer || ntfy.Topic != "gr" || ntfy.Token != "tk" {
+		t.Errorf("ntfy channel = %+v", ntfy)
+	}
+	if cfg.Channels["desktop"].Type != TypeNotifySend {
+		t.Errorf("desktop channel = %+v, want notify-send", cfg.Channels["desktop"])
+	}
+	if cfg.Retry.MaxAttempts != defaultMaxAttempts {
+		t.Errorf("MaxAttempts = %d, want %d", cfg.Retry.MaxAttempts, defaultMaxAttempts)
+	}
+}
+
+func TestLoadConfig_File(t *testing.T) {
+	path := writeConfig(t, `
+channels:
+  phone: {type: ntfy, server: "https://ntfy.example.com/", topic: gr, token: "${NTFY_TOKEN}"}
+  team: {type: slack, url: "${SLACK_URL}", minPriority: high}
+  mail: {type: email, host: smtp.example.com, from: a@example.com, to: [b@example.com]}
+routes:
+  - {events: ["ops.*"], projects: [shop], channels: [phone, team]}
+default: [phone]
+retry: {maxAttempts: 2, initialBackoff: 10ms}
+`)
+	cfg, err := LoadConfig(path, envOf(map[string]string{"NTFY_TOKEN": "secret", "SLACK_URL": "https://hooks.example.com/x"}), noLookPath)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Channels["phone"].Token != "secret" {
+		t.Errorf("token not expanded: %q", cfg.Channels["phone"].Token)
+	}
+	if cfg.Channels["team"].URL != "https://hooks.example.com/x" {
+		t.Errorf("url not expanded: %q", cfg.Channels["team"].URL)
+	}
+	if cfg.Channels["mail"].Port != defaultSMTPPort {
+		t.Errorf("mail port = %d, want %d", cfg.Channels["mail"].Port, defaultSMTPPort)
+	}
+	if cfg.Retry.MaxAttempts != 2 || cfg.Retry.initialBackoff.Milliseconds() != 10 || cfg.Retry.maxBackoff != defaultMaxBackoff {
+		t.Errorf("retry = %+v", cfg.Retry)
+	}
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"unknown key", "channel: {}\n", []string{"field channel not found"}},
+		{"missing required fields", `
+channels:
+  a: {type: ntfy}
+  b: {type: webhook, url: "ftp://x"}
+  c: {type: email, host: h}
+  d: {type: pager}
+`, []string{"channels.a: topic is required", "channels.b: url: must be an http(s) URL", "channels.c: host, from and to", "channels.d: unknown type"}},
+		{"unknown channel references", `
+channels:
+  a: {type: notify-send}
+routes:
+  - {events: [command.error], channels: [b]}
+  - {events: [command.complete]}
+default: [c]
+`, []string{"routes[0]: unknown channel: b", "routes[1]: channels is required", "default: unknown channel: c"}},
+		{"bad retry", "retry: {maxAttempts: -1, maxBackoff: soon}\n", []string{"retry.maxAttempts", "retry.maxBackoff"}},
+		{"bad rules", `
+rules:
+  quietHours: {start: "25:00", end: "07:00", timezone: Mars/Base}
+  rateLimit: {max: 0}
+  escalation: {after: -5m}
+`, []string{"quietHours: start and end must be HH:MM", "quietHours.timezone", "rateLimit.max", "escalation.after"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfig(t, tt.content), envOf(nil), noLookPath)
+			if !errors.Is(err, ErrValidation) {
+				t.Fatalf("err = %v, want ErrValidation", err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(err.Error(), w) {
+					t.Errorf("error %q does not contain %q", err, w)
+				}
+			}
+		})
+	}
+}
+
+func TestConfig_ChannelsFor(t *testing.T) {
+	cfg := &Config{
+		Channels: map[string]ChannelConfig{"phone": {}, "team": {}, "desktop": {}},
+		Routes: []Route{
+			{Events: []string{"session.stuck"}, Channels: []string{}},
+			{Events: []string{"ops.*"}, Projects: []string{"shop"}, Channels: []string{"phone", "team"}},
+			{Events: []string{"command.complete"}, Channels: []string{"desktop"}},
+		},
+	}
+	tests := []struct {
+		name string
+		msg  Message
+		want []string
+	}{
+		{"prefix and project name", Message{Event: EventOpsAlert, Project: "/home/u/shop"}, []string{"phone", "team"}},
+		{"project path", Message{Event: EventOpsCleared, Project: "shop"}, []string{"phone", "team"}},
+		{"other project falls to default", Message{Event: EventOpsAlert, Project: "/home/u/blog"}, []string{"desktop", "phone", "team"}},
+		{"exact event", Message{Event: EventCommandComplete}, []string{"desktop"}},
+		{"dropped", Message{Event: EventSessionStuck, Project: "/home/u/shop"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.channelsFor(tt.msg); !slices.Equal(got, tt.want) {
+				t.Errorf("channelsFor = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	cfg.Default = []string{"phone"}
+	if got := cfg.channelsFor(Message{Event: EventInfo}); !slices.Equal(got, []string{"phone"}) {
+		t.Errorf("channelsFor with default = %v, want [phone]", got)
+	}
+}
@@ -0,0 +1,41 @@
+// Package notify は複数チャネルへの通知の配送を提供する。
+//
+// # 概要
+//
+// コマンド完了・巡回の承認待ち・運用アラート・停滞検出などの通知を、<stateDir>/notify.yaml で宣言した
+// チャネル（ntfy、JSON webhook、Slack 互換 Incoming Webhook、SMTP メール、notify-send、terminal-notifier）へ配送する。
+// 通知先は種類（Event）とプロジェクトごとのルートで選ぶ。設定ファイルが無い場合は従来どおり
+// NTFY_TOPIC（と NTFY_SERVER / NTFY_TOKEN）の ntfy と、見つかればデスクトップ通知へ送る。
+//
+//	channels:
+//	  phone:   {type: ntfy, server: https://ntfy.example.com, topic: gr, token: "${NTFY_TOKEN}"}
+//	  team:    {type: slack, url: "${SLACK_WEBHOOK_URL}"}
+//	  desktop: {type: notify-send}
+//	routes:
+//	  - {events: ["ops.*"], projects: [shop], channels: [phone, team]}
+//	  - {events: [command.complete], channels: [desktop]}
+//	default: [phone]
+//
+// # 主要な型・関数
+//
+//   - Notifier / EventNotifier / Send: 通知の呼び出し側のインターフェース。Send は種類・プロジェクト付きで送り、
+//     EventNotifier でない Notifier（テストのモック等）には優先度に応じて Notify / NotifyError で送る
+//   - Message / Event / Priority: 通知1件、通知の種類、優先度
+//   - Config / LoadConfig / ConfigFromEnv: 通知設定の読み込み・環境変数からの組み立て・検証
+//   - Registry / NewRegistry: チャネルとルートを保持して配送する Notifier の実装。Start / Stop
+//   - Driver: チャネルへの1回の配送
+//   - Rules / RuleEngine / NewRuleEngine: 静音時間・重複抑制・送信数の上限を適用して Registry へ送る Notifier。
+//     保留した通知は送れるようになった時点で1通のまとめ通知（digest）にする（ボタン付きの通知は個別に送る）。Start / Stop
+//
+// # 設計方針
+//
+//   - 配送はチャネルごとのキューと goroutine で行い、遅い・落ちているチャネルが他のチャネルや呼び出し元を止めない
+//   - 失敗した配送は指数バックオフ（既定 2s から倍々、上限 1m、4回まで）で再試行する。
+//     408 / 429 以外の HTTP 4xx は設定の誤りとみなし再試行しない
+//   - ルートは上から順に照合して最初に一致したものを使う。channels: [] のルートはその通知を送らない
+//   - Stop は未配送の通知を送り切ってから戻る（短命な gr-run が終了前に呼ぶ）
+//   - 秘密情報は ${VAR} で環境変数から読み、ログ・検証エラーに URL やトークンを出さない
+//   - 配送結果は ghostrunner_notifications_total{channel,result} に記録する
+//   - ルールは Registry の手前に挟み、ルートによる通知先の選択とは独立させる。未回答の再通知（escalation）は
+//     会話ログの待機開始時刻が必要なため dashboard.Escalator が行い、本パッケージは設定だけを持つ
+package notify
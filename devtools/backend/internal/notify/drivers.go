@@ -0,0 +1,280 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// newDriver はチャネル設定からドライバーを生成します
+func newDriver(ch ChannelConfig, client *http.Client) (Driver, error) {
+	switch ch.Type {
+	case TypeNtfy:
+		return &ntfyDriver{
+			server: strings.TrimRight(ch.Server, "/"),
+			topic:  ch.Topic,
+			token:  ch.Token,
+			client: client,
+		}, nil
+	case TypeWebhook:
+		return &webhookDriver{url: ch.URL, headers: ch.Headers, client: client}, nil
+	case TypeSlack:
+		return &slackDriver{url: ch.URL, client: client}, nil
+	case TypeEmail:
+		return &emailDriver{
+			addr:     net.JoinHostPort(ch.Host, strconv.Itoa(ch.Port)),
+			host:     ch.Host,
+			username: ch.Username,
+			password: ch.Password,
+			from:     ch.From,
+			to:       ch.To,
+		}, nil
+	case TypeNotifySend, TypeTerminalNotifier:
+		path, err := exec.LookPath(ch.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s not found: %w", ch.Type, err)
+		}
+		return &desktopDriver{kind: ch.Type, path: path}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown channel type: %s", ErrValidation, ch.Type)
+}
+
+// ntfyMaxActions は ntfy が1通知に付けられるボタンの上限です
+const ntfyMaxActions = 3
+
+// ntfyDriver は ntfy（ntfy.sh またはセルフホスト）へ送るドライバーです
+type ntfyDriver struct {
+	server string
+	topic  string
+	token  string
+	client *http.Client
+}
+
+// ntfyPublish は ntfy の JSON 発行形式です（ボタン付きの通知に使用）
+type ntfyPublish struct {
+	Topic    string           `json:"topic"`
+	Title    string           `json:"title"`
+	Message  string           `json:"message"`
+	Priority int              `json:"priority"`
+	Tags     []string         `json:"tags"`
+	Actions  []ntfyHTTPAction `json:"actions"`
+}
+
+// ntfyHTTPAction は押すと URL へ HTTP 要求を送る ntfy のボタンです
+type ntfyHTTPAction struct {
+	Action string `json:"action"`
+	Label  string `json:"label"`
+	URL    string `json:"url"`
+	Method string `json:"method"`
+	Clear  bool   `json:"clear"`
+}
+
+// Deliver はトピックへ本文を POST し、タイトル・優先度・タグをヘッダーで渡します。
+// ボタンがある場合はラベルを安全に渡せる JSON 形式でサーバーのルートへ POST します（先頭 3 つまで）
+func (d *ntfyDriver) Deliver(ctx context.Context, msg Message) error {
+	tags := "white_check_mark"
+	if msg.Priority == PriorityHigh {
+		tags = "x"
+	}
+	var req *http.Request
+	var err error
+	if len(msg.Actions) > 0 {
+		pub := ntfyPublish{Topic: d.topic, Title: msg.Title, Message: msg.Body, Priority: ntfyPriority(msg.Priority), Tags: []string{tags}}
+		for _, a := range msg.Actions[:min(len(msg.Actions), ntfyMaxActions)] {
+			pub.Actions = append(pub.Actions, ntfyHTTPAction{Action: "http", Label: a.Label, URL: a.URL, Method: http.MethodPost, Clear: true})
+		}
+		body, merr := json.Marshal(pub)
+		if merr != nil {
+			return &permanentError{fmt.Errorf("failed to marshal payload: %w", merr)}
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, d.server, bytes.NewReader(body))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	} else {
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, d.server+"/"+d.topic, strings.NewReader(msg.Body))
+		if err == nil {
+			req.Header.Set("Title", msg.Title)
+			req.Header.Set("Priority", string(msg.Priority))
+			req.Header.Set("Tags", tags)
+		}
+	}
+	if err != nil {
+		return &permanentError{fmt.Errorf("failed to create request: %w", err)}
+	}
+	if d.token != "" {
+		req.Header.Set("Authorization", "Bearer "+d.token)
+	}
+	return doRequest(d.client, req)
+}
+
+// ntfyPriority は優先度を ntfy の数値（1〜5）にします
+func ntfyPriority(p Priority) int {
+	switch p {
+	case PriorityHigh:
+		return 4
+	case PriorityLow:
+		return 2
+	}
+	return 3
+}
+
+// webhookDriver は通知を JSON で任意の URL へ送るドライバーです
+type webhookDriver struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// webhookPayload は webhook の本文です
+type webhookPayload struct {
+	Message
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Deliver は Message と送信時刻を JSON で POST します
+func (d *webhookDriver) Deliver(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(webhookPayload{Message: msg, Timestamp: time.Now()})
+	if err != nil {
+		return &permanentError{fmt.Errorf("failed to marshal payload: %w", err)}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(body))
+	if err != nil {
+		return &permanentError{fmt.Errorf("failed to create request: %w", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range d.headers {
+		req.Header.Set(k, v)
+	}
+	return doRequest(d.client, req)
+}
+
+// slackDriver は Slack 互換の Incoming Webhook へ送るドライバーです
+type slackDriver struct {
+	url    string
+	client *http.Client
+}
+
+// Deliver はタイトルを太字にした text を POST します
+func (d *slackDriver) Deliver(ctx context.Context, msg Message) error {
+	text := fmt.Sprintf("*%s*\n%s", msg.Title, msg.Body)
+	if msg.Priority == PriorityHigh {
+		text = ":x: " + text
+	}
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return &permanentError{fmt.Errorf("failed to marshal payload: %w", err)}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(body))
+	if err != nil {
+		return &permanentError{fmt.Errorf("failed to create request: %w", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return doRequest(d.client, req)
+}
+
+// doRequest は要求を送り、2xx 以外をエラーにします。408 / 429 / 5xx 以外の 4xx は再試行しません
+func doRequest(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		// url.Error は URL（Webhook の秘密を含みうる）を含むため、原因だけを返す
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			err = ue.Err
+		}
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("unexpected response status: %d", resp.StatusCode)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return &permanentError{err}
+	}
+	return err
+}
+
+// emailDriver は SMTP でメールを送るドライバーです
+type emailDriver struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+// Deliver は件名をタイトル、本文をメッセージとしたテキストメールを送ります。
+// net/smtp は ctx に対応しないため、キャンセルは次の試行から効きます
+func (d *emailDriver) Deliver(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if d.username != "" {
+		auth = smtp.PlainAuth("", d.username, d.password, d.host)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", d.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(d.to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Title))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	if err := smtp.SendMail(d.addr, auth, d.from, d.to, []byte(b.String())); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	return nil
+}
+
+// desktopDriver はローカルのデスクトップ通知（Linux の notify-send / macOS の terminal-notifier）です
+type desktopDriver struct {
+	kind string
+	path string
+}
+
+// Deliver は通知コマンドを実行します。エラー通知は緊急度・音を変えます
+func (d *desktopDriver) Deliver(ctx context.Context, msg Message) error {
+	var args []string
+	switch d.kind {
+	case TypeNotifySend:
+		urgency := "normal"
+		switch msg.Priority {
+		case PriorityHigh:
+			urgency = "critical"
+		case PriorityLow:
+			urgency = "low"
+		}
+		args = []string{"--app-name=Ghostrunner", "--urgency=" + urgency, msg.Title, msg.Body}
+	default:
+		sound := "default"
+		if msg.Priority == PriorityHigh {
+			sound = "Basso"
+		}
+		args = []string{"-title", msg.Title, "-message", msg.Body, "-sound", sound}
+	}
+	if out, err := exec.CommandContext(ctx, d.path, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to run %s: %w: %s", d.kind, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
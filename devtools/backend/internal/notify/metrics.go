@@ -0,0 +1,11 @@
+package notify
+
+import "ghostrunner/backend/internal/metrics"
+
+// notificationsTotal はチャネルごとの配送結果です（result=sent / failed / retried / dropped）
+var notificationsTotal = metrics.NewCounterVec("ghostrunner_notifications_total",
+	"Notification deliveries by channel and result (sent, failed, retried, dropped).", "channel", "result")
+
+// ruleResults は通知ルールで止めた通知の件数です（result=deduped / held / limited / digested / released）
+var ruleResults = metrics.NewCounterVec("ghostrunner_notification_rules_total",
+	"Notifications intercepted by notification rules (deduped, held, limited, digested).", "result")
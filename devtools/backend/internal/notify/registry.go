@@ -0,0 +1,212 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// queueSize はチャネルごとの未配送通知の上限です（超えた通知は破棄します）
+	queueSize = 64
+	// deliverTimeout は1回の配送の制限時間です
+	deliverTimeout = 15 * time.Second
+	// stopDrainTimeout は Stop が未配送の通知を送り切るまで待つ上限です
+	stopDrainTimeout = 20 * time.Second
+)
+
+// channel は1チャネルの配送キューです
+type channel struct {
+	name   string
+	cfg    ChannelConfig
+	driver Driver
+	queue  chan Message
+}
+
+// Registry は通知設定のチャネルとルートを保持し、通知をチャネルごとのキューで配送します。
+// 配送に失敗した通知は Retry の設定に従い指数バックオフで再試行します。
+// Notifier / EventNotifier を実装し、既存の通知の呼び出し元にそのまま渡せます
+type Registry struct {
+	cfg        *Config
+	httpClient *http.Client
+	overrides  map[string]Driver
+	channels   map[string]*channel
+
+	mu      sync.RWMutex
+	stopped bool
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Option は Registry の設定オプションです
+type Option func(*Registry)
+
+// WithDriver は name のチャネルのドライバーを差し替えます（テストや独自の配送先に使用）
+func WithDriver(name string, d Driver) Option {
+	return func(r *Registry) {
+		r.overrides[name] = d
+	}
+}
+
+// WithHTTPClient は HTTP 系ドライバー（ntfy / webhook / slack）の HTTP クライアントを設定します
+func WithHTTPClient(c *http.Client) Option {
+	return func(r *Registry) {
+		r.httpClient = c
+	}
+}
+
+// NewRegistry は検証済みの設定から Registry を生成します。
+// ドライバーを生成できないチャネル（notify-send が無い等）があればエラーを返します。配送は Start 後に始まります
+func NewRegistry(cfg *Config, opts ...Option) (*Registry, error) {
+	r := &Registry{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: deliverTimeout},
+		overrides:  make(map[string]Driver),
+		channels:   make(map[string]*channel),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	for _, name := range cfg.ChannelNames() {
+		chCfg := cfg.Channels[name]
+		d, ok := r.overrides[name]
+		if !ok {
+			var err error
+			if d, err = newDriver(chCfg, r.httpClient); err != nil {
+				return nil, fmt.Errorf("failed to create channel %s: %w", name, err)
+			}
+		}
+		r.channels[name] = &channel{name: name, cfg: chCfg, driver: d, queue: make(chan Message, queueSize)}
+	}
+	return r, nil
+}
+
+// Len はチャネル数を返します
+func (r *Registry) Len() int {
+	return len(r.channels)
+}
+
+// Start はチャネルごとの配送を開始します。ctx のキャンセルで未配送の通知を破棄して終了します
+func (r *Registry) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+	for _, ch := range r.channels {
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			for msg := range ch.queue {
+				r.deliver(ctx, ch, msg)
+			}
+		}()
+	}
+	log.Printf("[Notify] started: channels=%v, routes=%d", r.cfg.ChannelNames(), len(r.cfg.Routes))
+}
+
+// Stop は新しい通知の受け付けを止め、キューに残った通知を送り切るまで待機します。
+// stopDrainTimeout を過ぎた場合は再試行を打ち切って終了します
+func (r *Registry) Stop() {
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		return
+	}
+	r.stopped = true
+	for _, ch := range r.channels {
+		close(ch.queue)
+	}
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(stopDrainTimeout):
+		log.Printf("[Notify] drain timed out, abandoning pending notifications")
+		if r.cancel != nil {
+			r.cancel()
+		}
+		<-done
+	}
+	if r.cancel != nil {
+		r.cancel()
+	}
+	log.Printf("[Notify] stopped")
+}
+
+// Notify は種類を指定しない通常の通知を送信します
+func (r *Registry) Notify(title, message string) {
+	r.Send(Message{Event: EventInfo, Title: title, Body: message, Priority: PriorityDefault})
+}
+
+// NotifyError は種類を指定しないエラー通知を送信します
+func (r *Registry) NotifyError(title, message string) {
+	r.Send(Message{Event: EventError, Title: title, Body: message, Priority: PriorityHigh})
+}
+
+// Send はルートで選んだチャネルのキューへ通知を積みます。キューが一杯のチャネルでは破棄します
+func (r *Registry) Send(msg Message) {
+	if msg.Priority == "" {
+		msg.Priority = PriorityDefault
+	}
+	if msg.Event == "" {
+		msg.Event = EventInfo
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.stopped {
+		log.Printf("[Notify] registry stopped, notification dropped: event=%s, title=%s", msg.Event, msg.Title)
+		return
+	}
+	for _, name := range r.cfg.channelsFor(msg) {
+		ch, ok := r.channels[name]
+		if !ok || !ch.cfg.allows(msg.Priority) {
+			continue
+		}
+		select {
+		case ch.queue <- msg:
+		default:
+			notificationsTotal.Inc(name, "dropped")
+			log.Printf("[Notify] queue full, notification dropped: channel=%s, event=%s, title=%s", name, msg.Event, msg.Title)
+		}
+	}
+}
+
+// deliver は1通知を1チャネルへ配送し、失敗時は再試行不要なエラーか試行回数の上限まで再試行します
+func (r *Registry) deliver(ctx context.Context, ch *channel, msg Message) {
+	backoff := r.cfg.Retry.initialBackoff
+	for attempt := 1; ; attempt++ {
+		dctx, cancel := context.WithTimeout(ctx, deliverTimeout)
+		err := ch.driver.Deliver(dctx, msg)
+		cancel()
+		if err == nil {
+			notificationsTotal.Inc(ch.name, "sent")
+			log.Printf("[Notify] notification sent: channel=%s, event=%s, title=%s, attempt=%d", ch.name, msg.Event, msg.Title, attempt)
+			return
+		}
+		if isPermanent(err) || attempt >= r.cfg.Retry.MaxAttempts || ctx.Err() != nil {
+			notificationsTotal.Inc(ch.name, "failed")
+			log.Printf("[Notify] notification failed: channel=%s, event=%s, title=%s, attempt=%d, error=%v", ch.name, msg.Event, msg.Title, attempt, err)
+			return
+		}
+		notificationsTotal.Inc(ch.name, "retried")
+		log.Printf("[Notify] delivery failed, retrying: channel=%s, event=%s, attempt=%d, backoff=%s, error=%v", ch.name, msg.Event, attempt, backoff, err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			notificationsTotal.Inc(ch.name, "failed")
+			log.Printf("[Notify] notification abandoned: channel=%s, event=%s, title=%s", ch.name, msg.Event, msg.Title)
+			return
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, r.cfg.Retry.maxBackoff)
+	}
+}
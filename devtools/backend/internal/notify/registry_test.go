@@ -0,0 +1,150 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// fakeDriver は失敗回数を指定できるテスト用ドライバーです
+type fakeDriver struct {
+	mu       sync.Mutex
+	failures int
+	err      error
+	attempts int
+	got      []Message
+}
+
+func (d *fakeDriver) Deliver(_ context.Context, msg Message) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.attempts++
+	if d.attempts <= d.failures {
+		return d.err
+	}
+	d.got = append(d.got, msg)
+	return nil
+}
+
+func newTestRegistry(t *testing.T, cfg *Config, drivers map[string]Driver) *Registry {
+	t.Helper()
+	cfg.Retry = Retry{MaxAttempts: 3, InitialBackoff: "1ms", MaxBackoff: "2ms"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	var opts []Option
+	for name, d := range drivers {
+		opts = append(opts, WithDriver(name, d))
+	}
+	r, err := NewRegistry(cfg, opts...)
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	r.Start(context.Background())
+	return r
+}
+
+func TestRegistry_RoutesAndRetries(t *testing.T) {
+	phone := &fakeDriver{failures: 2, err: errors.New("connection refused")}
+	team := &fakeDriver{}
+	r := newTestRegistry(t, &Config{
+		Channels: map[string]ChannelConfig{
+			"phone": {Type: TypeNotifySend},
+			"team":  {Type: TypeNotifySend, MinPriority: PriorityHigh},
+		},
+	}, map[string]Driver{"phone": phone, "team": team})
+
+	r.Notify("done", "ok")
+	r.Send(Message{Event: EventCommandError, Project: "/p", Title: "failed", Body: "boom", Priority: PriorityHigh})
+	r.Stop()
+
+	if phone.attempts != 4 || len(phone.got) != 2 {
+		t.Errorf("phone attempts=%d delivered=%d, want 4 attempts and 2 deliveries", phone.attempts, len(phone.got))
+	}
+	if len(team.got) != 1 || team.got[0].Event != EventCommandError || team.got[0].Project != "/p" {
+		t.Errorf("team got %+v, want only the high priority command.error", team.got)
+	}
+
+	r.Notify("after stop", "ignored")
+	if len(phone.got) != 2 {
+		t.Errorf("notification after Stop was delivered")
+	}
+}
+
+func TestRegistry_GivesUp(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantAttempts int
+	}{
+		{"transient errors up to MaxAttempts", errors.New("timeout"), 3},
+		{"permanent error is not retried", &permanentError{errors.New("unexpected response status: 403")}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &fakeDriver{failures: 10, err: tt.err}
+			r := newTestRegistry(t, &Config{Channels: map[string]ChannelConfig{"a": {Type: TypeNotifySend}}},
+				map[string]Driver{"a": d})
+			r.NotifyError("t", "m")
+			r.Stop()
+			if d.attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", d.attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestHTTPDrivers(t *testing.T) {
+	var got *http.Request
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	d, err := newDriver(ChannelConfig{Type: TypeNtfy, Server: server.URL + "/", Topic: "gr", Token: "tk"}, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Deliver(context.Background(), Message{Title: "T", Body: "B", Priority: PriorityHigh}); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	if got.URL.Path != "/gr" || got.Header.Get("Title") != "T" || got.Header.Get("Priority") != "high" ||
+		got.Header.Get("Tags") != "x" || got.Header.Get("Authorization") != "Bearer tk" {
+		t.Errorf("ntfy request = %s %v", got.URL.Path, got.Header)
+	}
+
+	d, _ = newDriver(ChannelConfig{Type: TypeWebhook, URL: server.URL}, server.Client())
+	status = http.StatusNotFound
+	if err := d.Deliver(context.Background(), Message{Title: "T"}); !isPermanent(err) {
+		t.Errorf("404 err = %v, want permanent", err)
+	}
+	status = http.StatusTooManyRequests
+	if err := d.Deliver(context.Background(), Message{Title: "T"}); err == nil || isPermanent(err) {
+		t.Errorf("429 err = %v, want retryable", err)
+	}
+}
+
+// legacyNotifier は EventNotifier でない Notifier です
+type legacyNotifier struct {
+	calls []string
+}
+
+func (n *legacyNotifier) Notify(title, _ string) { n.calls = append(n.calls, "Notify:"+title) }
+func (n *legacyNotifier) NotifyError(title, _ string) {
+	n.calls = append(n.calls, "NotifyError:"+title)
+}
+
+func TestSend_FallsBackToNotifier(t *testing.T) {
+	n := &legacyNotifier{}
+	Send(n, Message{Event: EventCommandComplete, Title: "a"})
+	Send(n, Message{Event: EventCommandError, Title: "b", Priority: PriorityHigh})
+	Send(nil, Message{Title: "c"})
+	if len(n.calls) != 2 || n.calls[0] != "Notify:a" || n.calls[1] != "NotifyError:b" {
+		t.Errorf("calls = %v", n.calls)
+	}
+}
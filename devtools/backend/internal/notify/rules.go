@@ -0,0 +1,432 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultDedupWindow は同じ通知を抑制する既定の時間です
+	defaultDedupWindow = 30 * time.Minute
+	// defaultRateLimitWindow は送信数を数える既定の時間枠です
+	defaultRateLimitWindow = time.Hour
+	// defaultEscalateAfter は未回答の質問を再通知するまでの既定の時間です
+	defaultEscalateAfter = 15 * time.Minute
+	// ruleFlushInterval は保留した通知をまとめて送るかの判定間隔です
+	ruleFlushInterval = time.Minute
+	// digestMaxLines はまとめ通知の本文に並べる通知の上限です
+	digestMaxLines = 20
+	// digestLineMaxRunes はまとめ通知の1行の文字数の上限です
+	digestLineMaxRunes = 80
+)
+
+// defaultDedupEvents は dedup.events を省略した場合に重複を抑制する種類です（繰り返しやすいエラー）
+var defaultDedupEvents = []string{string(EventError), string(EventCommandError), string(EventRunError)}
+
+// Rules は通知のルールです（notify.yaml の rules）。省略した項目は無効です
+type Rules struct {
+	QuietHours *QuietHours `yaml:"quietHours"`
+	Dedup      *Dedup      `yaml:"dedup"`
+	RateLimit  *RateLimit  `yaml:"rateLimit"`
+	Escalation *Escalation `yaml:"escalation"`
+}
+
+// QuietHours は通知を保留し、終了時刻にまとめて送る時間帯です
+type QuietHours struct {
+	// Start / End は "HH:MM"（Start > End なら日をまたぐ）
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+	// Timezone は IANA のタイムゾーン名（省略時はサーバーのローカル時刻）
+	Timezone string `yaml:"timezone"`
+	// Bypass はこの優先度以上の通知を静音時間中も送ります（省略時はすべて保留）
+	Bypass Priority `yaml:"bypass"`
+
+	start, end int // 0時からの分
+	loc        *time.Location
+}
+
+// Dedup は同じ通知（種類・プロジェクト・タイトルが同じ）を一定時間送らない設定です
+type Dedup struct {
+	// Window は最初の通知から同じ通知を抑制する時間（既定 "30m"）
+	Window string `yaml:"window"`
+	// Events は対象の種類（省略時は error / command.error / run.error）
+	Events []string `yaml:"events"`
+
+	window time.Duration
+}
+
+// RateLimit はプロジェクトごとの送信数の上限です。超えた通知は時間枠の終わりにまとめて送ります
+type RateLimit struct {
+	// Max は時間枠あたりの通知数の上限
+	Max int `yaml:"max"`
+	// Window は時間枠（既定 "1h"）
+	Window string `yaml:"window"`
+	// Events は対象の種類（省略時は全て）
+	Events []string `yaml:"events"`
+
+	window time.Duration
+}
+
+// Escalation は質問待ちが一定時間を超えても回答されない場合の再通知の設定です
+type Escalation struct {
+	// After は質問待ちの開始から再通知までの時間（既定 "15m"）
+	After string `yaml:"after"`
+
+	after time.Duration
+}
+
+// Enabled は RuleEngine を挟む必要があるルール（静音時間・重複抑制・送信数の上限）があるかを返します
+func (r Rules) Enabled() bool {
+	return r.QuietHours != nil || r.Dedup != nil || r.RateLimit != nil
+}
+
+// EscalateAfter は未回答の質問を再通知するまでの時間を返します。再通知しない場合は 0 です
+func (r Rules) EscalateAfter() time.Duration {
+	if r.Escalation == nil {
+		return 0
+	}
+	return r.Escalation.after
+}
+
+// validate はルールを検証し、既定値を補います
+func (r *Rules) validate() []error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: rules."+format, append([]any{ErrValidation}, args...)...))
+	}
+	duration := func(key, raw string, def time.Duration, dst *time.Duration) {
+		if raw == "" {
+			*dst = def
+			return
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			fail("%s must be a positive duration: %q", key, raw)
+			return
+		}
+		*dst = d
+	}
+
+	if q := r.QuietHours; q != nil {
+		var ok1, ok2 bool
+		q.start, ok1 = parseClock(q.Start)
+		q.end, ok2 = parseClock(q.End)
+		switch {
+		case !ok1 || !ok2:
+			fail("quietHours: start and end must be HH:MM: %q, %q", q.Start, q.End)
+		case q.start == q.end:
+			fail("quietHours: start and end must differ: %s", q.Start)
+		}
+		q.loc = time.Local
+		if q.Timezone != "" {
+			loc, err := time.LoadLocation(q.Timezone)
+			if err != nil {
+				fail("quietHours.timezone: unknown time zone: %s", q.Timezone)
+			} else {
+				q.loc = loc
+			}
+		}
+		switch q.Bypass {
+		case "", PriorityLow, PriorityDefault, PriorityHigh:
+		default:
+			fail("quietHours.bypass: unknown priority: %s", q.Bypass)
+		}
+	}
+	if d := r.Dedup; d != nil {
+		duration("dedup.window", d.Window, defaultDedupWindow, &d.window)
+		if len(d.Events) == 0 {
+			d.Events = defaultDedupEvents
+		}
+	}
+	if rl := r.RateLimit; rl != nil {
+		duration("rateLimit.window", rl.Window, defaultRateLimitWindow, &rl.window)
+		if rl.Max < 1 {
+			fail("rateLimit.max must be positive: %d", rl.Max)
+		}
+	}
+	if e := r.Escalation; e != nil {
+		duration("escalation.after", e.After, defaultEscalateAfter, &e.after)
+	}
+	return errs
+}
+
+// parseClock は "HH:MM" を0時からの分に変換します
+func parseClock(s string) (int, bool) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// active は t が静音時間内かを返します
+func (q *QuietHours) active(t time.Time) bool {
+	t = t.In(q.loc)
+	m := t.Hour()*60 + t.Minute()
+	if q.start < q.end {
+		return m >= q.start && m < q.end
+	}
+	return m >= q.start || m < q.end
+}
+
+// bypasses は msg が静音時間中も送る優先度かを返します
+func (q *QuietHours) bypasses(msg Message) bool {
+	return q.Bypass != "" && priorityRank(msg.Priority) >= priorityRank(q.Bypass)
+}
+
+// matchesAny は種類がパターンのいずれかに一致するかを返します（パターンが空なら全て一致）
+func matchesAny(patterns []string, ev Event) bool {
+	return len(patterns) == 0 || slices.ContainsFunc(patterns, func(p string) bool { return matchEvent(p, ev) })
+}
+
+// dedupEntry は重複抑制中の通知です
+type dedupEntry struct {
+	until      time.Time
+	suppressed int
+}
+
+// rateWindow はプロジェクトごとの送信数の時間枠です
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// heldMessage は保留中の通知です。due がゼロなら静音時間の終わりに、そうでなければ due 以降に送ります
+type heldMessage struct {
+	msg Message
+	due time.Time
+}
+
+// RuleEngine は通知の発生元と配送（Registry）の間で、静音時間・重複抑制・送信数の上限を適用します。
+// 静音時間中と上限を超えた通知は保留し、送れるようになった時点で1通のまとめ通知（digest）にして送ります。
+// Notifier / EventNotifier を実装し、Registry の代わりに通知の呼び出し元へ渡せます。
+// 保留中の通知はメモリのみに保持するため、再起動で失われます
+type RuleEngine struct {
+	rules Rules
+	next  Notifier
+	now   func() time.Time
+
+	mu      sync.Mutex
+	dedup   map[string]*dedupEntry // key: 種類 + プロジェクト + タイトル
+	windows map[string]*rateWindow // key: プロジェクト
+	held    []heldMessage
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRuleEngine は検証済みのルールで next へ送る RuleEngine を生成します。now が nil なら time.Now を使います
+func NewRuleEngine(rules Rules, next Notifier, now func() time.Time) *RuleEngine {
+	if now == nil {
+		now = time.Now
+	}
+	return &RuleEngine{
+		rules:   rules,
+		next:    next,
+		now:     now,
+		dedup:   make(map[string]*dedupEntry),
+		windows: make(map[string]*rateWindow),
+	}
+}
+
+// Start は保留した通知を定期的にまとめて送る処理を開始します
+func (e *RuleEngine) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	e.cancel = cancel
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		ticker := time.NewTicker(ruleFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				e.Flush()
+			}
+		}
+	}()
+	log.Printf("[NotifyRules] started: quietHours=%t, dedup=%t, rateLimit=%t",
+		e.rules.QuietHours != nil, e.rules.Dedup != nil, e.rules.RateLimit != nil)
+}
+
+// Stop は定期処理を停止します。保留中の通知は送らずに破棄します（夜間の停止で通知を鳴らさないため）
+func (e *RuleEngine) Stop() {
+	if e.cancel != nil {
+		e.cancel()
+	}
+	e.wg.Wait()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.held) > 0 {
+		log.Printf("[NotifyRules] stopped, held notifications discarded: count=%d", len(e.held))
+	}
+}
+
+// Notify は種類を指定しない通常の通知を送信します
+func (e *RuleEngine) Notify(title, message string) {
+	e.Send(Message{Event: EventInfo, Title: title, Body: message, Priority: PriorityDefault})
+}
+
+// NotifyError は種類を指定しないエラー通知を送信します
+func (e *RuleEngine) NotifyError(title, message string) {
+	e.Send(Message{Event: EventError, Title: title, Body: message, Priority: PriorityHigh})
+}
+
+// Send はルールを適用し、通過した通知を next へ送ります
+func (e *RuleEngine) Send(msg Message) {
+	if msg.Priority == "" {
+		msg.Priority = PriorityDefault
+	}
+	if msg.Event == "" {
+		msg.Event = EventInfo
+	}
+	e.mu.Lock()
+	result := e.admitLocked(&msg, e.now())
+	e.mu.Unlock()
+	if result != "" {
+		ruleResults.Inc(result)
+		log.Printf("[NotifyRules] notification %s: event=%s, project=%s, title=%s", result, msg.Event, msg.Project, msg.Title)
+		return
+	}
+	Send(e.next, msg)
+}
+
+// admitLocked は重複抑制・静音時間・送信数の上限の順にルールを適用します。
+// 送る場合は空文字、止めた場合は理由（deduped / held / limited）を返します
+func (e *RuleEngine) admitLocked(msg *Message, now time.Time) string {
+	if d := e.rules.Dedup; d != nil && matchesAny(d.Events, msg.Event) {
+		key := string(msg.Event) + "\x00" + msg.Project + "\x00" + msg.Title
+		if ent, ok := e.dedup[key]; ok && now.Before(ent.until) {
+			ent.suppressed++
+			return "deduped"
+		} else if ok && ent.suppressed > 0 {
+			msg.Body += fmt.Sprintf("\n（前回の通知の後、同じ通知を%d件省略しました）", ent.suppressed)
+		}
+		e.dedup[key] = &dedupEntry{until: now.Add(d.window)}
+	}
+	if q := e.rules.QuietHours; q != nil && q.active(now) && !q.bypasses(*msg) {
+		e.held = append(e.held, heldMessage{msg: *msg})
+		return "held"
+	}
+	if rl := e.rules.RateLimit; rl != nil && matchesAny(rl.Events, msg.Event) {
+		w, ok := e.windows[msg.Project]
+		if !ok || !now.Before(w.start.Add(rl.window)) {
+			w = &rateWindow{start: now}
+			e.windows[msg.Project] = w
+		}
+		if w.count >= rl.Max {
+			e.held = append(e.held, heldMessage{msg: *msg, due: w.start.Add(rl.window)})
+			return "limited"
+		}
+		w.count++
+	}
+	return ""
+}
+
+// Flush は送れるようになった保留中の通知（静音時間の終わった通知、時間枠の終わった上限超過の通知）を
+// 1通のまとめ通知にして送ります。ボタン（Actions）付きの通知はまとめると回答ボタンが失われるため個別に送ります。
+// 静音時間中は何もしません。Start 後は ruleFlushInterval ごとに呼ばれます
+func (e *RuleEngine) Flush() {
+	now := e.now()
+	e.mu.Lock()
+	e.pruneLocked(now)
+	if q := e.rules.QuietHours; q != nil && q.active(now) {
+		e.mu.Unlock()
+		return
+	}
+	var due []Message
+	kept := e.held[:0]
+	for _, h := range e.held {
+		if now.Before(h.due) {
+			kept = append(kept, h)
+			continue
+		}
+		due = append(due, h.msg)
+	}
+	e.held = kept
+	e.mu.Unlock()
+
+	plain := due[:0]
+	for _, m := range due {
+		if len(m.Actions) > 0 {
+			ruleResults.Inc("released")
+			log.Printf("[NotifyRules] releasing held notification with actions: event=%s, project=%s", m.Event, m.Project)
+			Send(e.next, m)
+			continue
+		}
+		plain = append(plain, m)
+	}
+	due = plain
+	if len(due) == 0 {
+		return
+	}
+	ruleResults.Inc("digested")
+	log.Printf("[NotifyRules] sending digest: count=%d", len(due))
+	Send(e.next, digest(due))
+}
+
+// pruneLocked は期限を過ぎた重複抑制・送信数の時間枠を削除します。
+// 省略件数を次の通知に添えるため、重複抑制は期限からさらに1窓分だけ残します
+func (e *RuleEngine) pruneLocked(now time.Time) {
+	if d := e.rules.Dedup; d != nil {
+		for key, ent := range e.dedup {
+			if !now.Before(ent.until.Add(d.window)) {
+				delete(e.dedup, key)
+			}
+		}
+	}
+	if rl := e.rules.RateLimit; rl != nil {
+		for project, w := range e.windows {
+			if !now.Before(w.start.Add(rl.window)) {
+				delete(e.windows, project)
+			}
+		}
+	}
+}
+
+// digest は保留した通知を1通にまとめます。優先度は含まれる通知の最高、プロジェクトは全て同じ場合のみ設定します
+func digest(msgs []Message) Message {
+	d := Message{
+		Event:    EventDigest,
+		Project:  msgs[0].Project,
+		Title:    fmt.Sprintf("保留していた通知 %d件", len(msgs)),
+		Priority: PriorityLow,
+	}
+	var lines []string
+	for i, m := range msgs {
+		if m.Project != d.Project {
+			d.Project = ""
+		}
+		if priorityRank(m.Priority) > priorityRank(d.Priority) {
+			d.Priority = m.Priority
+		}
+		if i >= digestMaxLines {
+			continue
+		}
+		line := m.Title
+		if m.Project != "" {
+			line = "[" + filepath.Base(m.Project) + "] " + line
+		}
+		if first, _, _ := strings.Cut(strings.TrimSpace(m.Body), "\n"); first != "" {
+			line += ": " + first
+		}
+		if r := []rune(line); len(r) > digestLineMaxRunes {
+			line = string(r[:digestLineMaxRunes]) + "…"
+		}
+		lines = append(lines, "・"+line)
+	}
+	if len(msgs) > digestMaxLines {
+		lines = append(lines, fmt.Sprintf("ほか %d件", len(msgs)-digestMaxLines))
+	}
+	d.Body = strings.Join(lines, "\n")
+	return d
+}
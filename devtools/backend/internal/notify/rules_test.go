@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordingNotifier は受け取った通知を記録する EventNotifier です
+type recordingNotifier struct {
+	got []Message
+}
+
+func (n *recordingNotifier) Notify(title, message string) {
+	n.Send(Message{Title: title, Body: message})
+}
+func (n *recordingNotifier) NotifyError(title, message string) {
+	n.Send(Message{Title: title, Body: message, Priority: PriorityHigh})
+}
+func (n *recordingNotifier) Send(msg Message) { n.got = append(n.got, msg) }
+
+func newTestRuleEngine(t *testing.T, rules Rules, now *time.Time) (*RuleEngine, *recordingNotifier) {
+	t.Helper()
+	cfg := &Config{Channels: map[string]ChannelConfig{}, Rules: rules}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	next := &recordingNotifier{}
+	return NewRuleEngine(cfg.Rules, next, func() time.Time { return *now }), next
+}
+
+func TestRuleEngine_QuietHours(t *testing.T) {
+	now := time.Date(2026, 10, 19, 23, 30, 0, 0, time.UTC)
+	e, next := newTestRuleEngine(t, Rules{QuietHours: &QuietHours{Start: "22:00", End: "07:00", Timezone: "UTC", Bypass: PriorityHigh}}, &now)
+
+	e.Send(Message{Event: EventCommandComplete, Project: "/home/u/shop", Title: "完了", Body: "テスト追加\n詳細"})
+	e.Send(Message{Event: EventOpsAlert, Project: "/home/u/blog", Title: "アラート", Priority: PriorityDefault})
+	e.Send(Message{Event: EventCommandError, Title: "失敗", Priority: PriorityHigh})
+	if len(next.got) != 1 || next.got[0].Title != "失敗" {
+		t.Fatalf("during quiet hours got %+v, want only the high priority bypass", next.got)
+	}
+
+	now = time.Date(2026, 10, 20, 6, 59, 0, 0, time.UTC)
+	e.Flush()
+	if len(next.got) != 1 {
+		t.Fatalf("digest sent before quiet hours ended")
+	}
+
+	now = time.Date(2026, 10, 20, 7, 0, 0, 0, time.UTC)
+	e.Flush()
+	e.Flush()
+	if len(next.got) != 2 {
+		t.Fatalf("got %d notifications, want 1 digest after quiet hours", len(next.got)-1)
+	}
+	d := next.got[1]
+	if d.Event != EventDigest || d.Project != "" || d.Title != "保留していた通知 2件" {
+		t.Errorf("digest = %+v", d)
+	}
+	if !strings.Contains(d.Body, "・[shop] 完了: テスト追加\n・[blog] アラート") {
+		t.Errorf("digest body = %q", d.Body)
+	}
+}
+
+func TestRuleEngine_QuietHours_KeepsActions(t *testing.T) {
+	now := time.Date(2026, 10, 19, 23, 30, 0, 0, time.UTC)
+	e, next := newTestRuleEngine(t, Rules{QuietHours: &QuietHours{Start: "22:00", End: "07:00", Timezone: "UTC"}}, &now)
+
+	actions := []Action{{Label: "はい", URL: "https://mac.example.ts.net/api/callbacks/tok1"}}
+	e.Send(Message{Event: EventPatrolQuestion, Project: "/home/u/shop", Title: "質問", Body: "進めますか", Actions: actions})
+	e.Send(Message{Event: EventCommandComplete, Project: "/home/u/shop", Title: "完了"})
+	if len(next.got) != 0 {
+		t.Fatalf("during quiet hours got %+v, want none", next.got)
+	}
+
+	now = time.Date(2026, 10, 20, 7, 0, 0, 0, time.UTC)
+	e.Flush()
+	if len(next.got) != 2 {
+		t.Fatalf("got %d notifications, want the question and a digest", len(next.got))
+	}
+	q := next.got[0]
+	if q.Title != "質問" || len(q.Actions) != 1 || q.Actions[0] != actions[0] {
+		t.Errorf("released question = %+v, want original message with actions", q)
+	}
+	if d := next.got[1]; d.Event != EventDigest || d.Title != "保留していた通知 1件" {
+		t.Errorf("digest = %+v", d)
+	}
+}
+
+func TestRuleEngine_Dedup(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	e, next := newTestRuleEngine(t, Rules{Dedup: &Dedup{Window: "10m"}}, &now)
+
+	for range 3 {
+		e.Send(Message{Event: EventCommandError, Project: "/p", Title: "コマンド失敗", Body: "exit 1", Priority: PriorityHigh})
+	}
+	e.Send(Message{Event: EventCommandError, Project: "/other", Title: "コマンド失敗", Priority: PriorityHigh})
+	e.Send(Message{Event: EventCommandComplete, Project: "/p", Title: "完了"})
+	e.Send(Message{Event: EventCommandComplete, Project: "/p", Title: "完了"})
+	if len(next.got) != 4 {
+		t.Fatalf("got %d notifications, want 4 (one per project, completions not deduped)", len(next.got))
+	}
+
+	now = now.Add(11 * time.Minute)
+	e.Send(Message{Event: EventCommandError, Project: "/p", Title: "コマンド失敗", Body: "exit 1", Priority: PriorityHigh})
+	last := next.got[len(next.got)-1]
+	if !strings.HasSuffix(last.Body, "同じ通知を2件省略しました）") {
+		t.Errorf("body after window = %q, want the suppressed count", last.Body)
+	}
+}
+
+func TestRuleEngine_RateLimit(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	e, next := newTestRuleEngine(t, Rules{RateLimit: &RateLimit{Max: 2, Window: "1h", Events: []string{"command.*"}}}, &now)
+
+	for range 4 {
+		e.Send(Message{Event: EventCommandComplete, Project: "/p", Title: "完了"})
+	}
+	e.Send(Message{Event: EventOpsAlert, Project: "/p", Title: "アラート"})
+	if len(next.got) != 3 {
+		t.Fatalf("got %d notifications, want 2 completions and the unlimited alert", len(next.got))
+	}
+
+	now = now.Add(59 * time.Minute)
+	e.Flush()
+	if len(next.got) != 3 {
+		t.Fatalf("digest sent before the window ended")
+	}
+	now = now.Add(time.Minute)
+	e.Flush()
+	if len(next.got) != 4 || next.got[3].Event != EventDigest || next.got[3].Project != "/p" {
+		t.Fatalf("got %+v, want a digest of the 2 limited notifications", next.got[3:])
+	}
+	e.Send(Message{Event: EventCommandComplete, Project: "/p", Title: "完了"})
+	if len(next.got) != 5 {
+		t.Errorf("notification in the new window was not sent")
+	}
+}
@@ -0,0 +1,128 @@
+package notify
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrValidation は通知設定の不備を表すエラーです
+var ErrValidation = errors.New("validation error")
+
+// Event は通知の種類です。ルートの events で通知先の選択に使います
+type Event string
+
+const (
+	// EventInfo は種類を指定しない通常の通知です（Notify）
+	EventInfo Event = "info"
+	// EventError は種類を指定しないエラー通知です（NotifyError）
+	EventError Event = "error"
+	// EventCommandComplete は Claude CLI のコマンド完了です
+	EventCommandComplete Event = "command.complete"
+	// EventCommandError は Claude CLI のコマンド失敗です
+	EventCommandError Event = "command.error"
+	// EventPatrolQuestion は巡回中のプロジェクトが承認・回答待ちになったことです
+	EventPatrolQuestion Event = "patrol.question"
+	// EventAutoAnswerEscalate は自動回答ルールのエスカレーションです
+	EventAutoAnswerEscalate Event = "autoanswer.escalate"
+	// EventOpsAlert は運用アラートの発火です
+	EventOpsAlert Event = "ops.alert"
+	// EventOpsCleared は運用アラートの解除です
+	EventOpsCleared Event = "ops.cleared"
+	// EventSessionStuck はセッションの停滞です
+	EventSessionStuck Event = "session.stuck"
+	// EventSessionProgress は動作中セッションの進捗要約の更新です
+	EventSessionProgress Event = "session.progress"
+	// EventRunFinished は gr-run のタスク実行の終了です
+	EventRunFinished Event = "run.finished"
+	// EventRunError は gr-run のタスク実行の異常終了です
+	EventRunError Event = "run.error"
+	// EventQuestionEscalate は質問待ちが一定時間を超えても回答されていないことの再通知です
+	EventQuestionEscalate Event = "question.escalate"
+	// EventDigest は静音時間中・送信数の上限超過で保留した通知のまとめです
+	EventDigest Event = "digest"
+	// EventReportDigest は定期配信する活動ダイジェスト（日次・週次）です
+	EventReportDigest Event = "report.digest"
+)
+
+// Priority は通知の優先度です
+type Priority string
+
+const (
+	// PriorityLow は低い優先度です（音を鳴らさない等はドライバー次第）
+	PriorityLow Priority = "low"
+	// PriorityDefault は通常の優先度です
+	PriorityDefault Priority = "default"
+	// PriorityHigh はエラー・要対応の優先度です
+	PriorityHigh Priority = "high"
+)
+
+// Message は1件の通知です
+type Message struct {
+	Event Event `json:"event"`
+	// Project はプロジェクトのパスまたは名前（ルートの projects はどちらでも照合します）
+	Project  string   `json:"project,omitempty"`
+	Title    string   `json:"title"`
+	Body     string   `json:"message"`
+	Priority Priority `json:"priority"`
+	// Actions は通知に付けるボタンです（ntfy はボタン、webhook は JSON に含めます。他のチャネルは無視します）
+	Actions []Action `json:"actions,omitempty"`
+}
+
+// Action は押すと URL へ POST する通知のボタンです。URL は署名付きの単回使用コールバックを想定します
+type Action struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// Notifier は通知送信のインターフェースです。
+// 完了通知などの通常の通知とエラー通知だけを区別し、種類・プロジェクトは Send で渡します
+type Notifier interface {
+	// Notify は通常の通知を送信します
+	Notify(title, message string)
+	// NotifyError はエラー通知を送信します
+	NotifyError(title, message string)
+}
+
+// EventNotifier は種類とプロジェクト付きの通知を受け付ける Notifier です（Registry が実装）
+type EventNotifier interface {
+	Notifier
+	// Send は通知をルートに従って配送します
+	Send(msg Message)
+}
+
+// Send は msg を n へ送ります。n が EventNotifier なら種類・プロジェクト付きで、
+// そうでなければ優先度に応じて Notify / NotifyError で送ります。n が nil なら何もしません
+func Send(n Notifier, msg Message) {
+	if n == nil {
+		return
+	}
+	if en, ok := n.(EventNotifier); ok {
+		en.Send(msg)
+		return
+	}
+	if msg.Priority == PriorityHigh {
+		n.NotifyError(msg.Title, msg.Body)
+		return
+	}
+	n.Notify(msg.Title, msg.Body)
+}
+
+// Driver は1つの通知チャネルへの配送手段です
+type Driver interface {
+	// Deliver は通知を1回配送します。再試行は Registry が行います
+	Deliver(ctx context.Context, msg Message) error
+}
+
+// permanentError は再試行しても成功しない配送エラーです（HTTP 4xx 等）
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// isPermanent は err が再試行不要な配送エラーかを返します
+func isPermanent(err error) bool {
+	var pe *permanentError
+	return errors.As(err, &pe)
+}
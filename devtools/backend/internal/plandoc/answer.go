@@ -0,0 +1,140 @@
+package plandoc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AnswerWindow は回答書き戻しで指定行から前後に未回答のステータス行を探す行数です
+// （表示から回答までの間に計画書が少し編集されても書き戻せるようにする）
+const AnswerWindow = 2
+
+// ErrDuplicateAnswer は1回の書き戻しで同じ確認事項に複数の回答が指定された場合のエラーです
+var ErrDuplicateAnswer = errors.New("multiple answers for the same question")
+
+// Answer は1つの確認事項への回答です
+type Answer struct {
+	// QuestionID は確認事項の ID（例: Q1）。指定時は Line より優先します
+	QuestionID string
+	// Line はステータス行の行番号（1始まり）。前後 AnswerWindow 行以内の未回答を探します
+	Line int
+	Text string
+	// AnsweredAt は「**回答日時**:」に RFC3339 で書き込みます（ゼロ値なら書き込まない）
+	AnsweredAt time.Time
+	// Source は「**回答元**:」に書き込みます（空なら書き込まない）
+	Source string
+}
+
+// FindUnanswered は line（1始まり）から前後 AnswerWindow 行以内にステータス行がある未回答の確認事項のうち、
+// 最も近いものを返します（同距離なら前方を優先）
+func (d *Document) FindUnanswered(line int) (Question, bool) {
+	var best Question
+	bestDist := -1
+	for _, q := range d.Questions {
+		if !q.Unanswered() {
+			continue
+		}
+		dist := q.StatusLine - line
+		if dist < 0 {
+			dist = -dist
+		}
+		if dist > AnswerWindow {
+			continue
+		}
+		if bestDist < 0 || dist < bestDist {
+			best = q
+			bestDist = dist
+		}
+	}
+	return best, bestDist >= 0
+}
+
+// FindUnansweredByID は ID が一致する未回答の確認事項を返します
+func (d *Document) FindUnansweredByID(id string) (Question, bool) {
+	for _, q := range d.Questions {
+		if q.ID == id && q.Unanswered() {
+			return q, true
+		}
+	}
+	return Question{}, false
+}
+
+// ApplyAnswers は複数の未回答の確認事項を「回答済」にし、回答行と回答日時・回答元の行を書き込んだ内容を返します。
+// 既存の「**回答**:」行（空・「-」を含む）は置き換え、無ければステータス行の直後に挿入します。
+// 既存の回答日時・回答元の行は取り除いて回答行の直後に書き直します。
+// 1件でも対象が見つからなければ ErrNoUnanswered、同じ確認事項を2回指定すると ErrDuplicateAnswer を返し、
+// いずれも内容は変更しません（全件適用か無変更のどちらか）。
+func ApplyAnswers(data []byte, answers []Answer) ([]byte, error) {
+	doc := Parse(data)
+
+	// Parse と同じ分割で行番号を合わせる（CR は各行に残して書き戻す）
+	lines := strings.Split(string(data), "\n")
+	replace := map[int]string{}
+	remove := map[int]bool{}
+	insertAfter := map[int][]string{}
+	targeted := map[int]int{}
+
+	for i, a := range answers {
+		text := strings.TrimSpace(a.Text)
+		if text == "" {
+			return nil, fmt.Errorf("answers[%d]: answer must not be empty", i)
+		}
+
+		var q Question
+		var ok bool
+		if a.QuestionID != "" {
+			q, ok = doc.FindUnansweredByID(a.QuestionID)
+		} else {
+			q, ok = doc.FindUnanswered(a.Line)
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: answers[%d] questionId=%q line=%d", ErrNoUnanswered, i, a.QuestionID, a.Line)
+		}
+		if prev, dup := targeted[q.StatusLine]; dup {
+			return nil, fmt.Errorf("%w: answers[%d] and answers[%d] (line=%d)", ErrDuplicateAnswer, prev, i, q.StatusLine)
+		}
+		targeted[q.StatusLine] = i
+
+		statusIdx := q.StatusLine - 1
+		eol := ""
+		if strings.HasSuffix(lines[statusIdx], "\r") {
+			eol = "\r"
+		}
+		replace[statusIdx] = strings.Replace(lines[statusIdx], string(StatusUnanswered), string(StatusAnswered), 1)
+
+		added := []string{fmt.Sprintf("**回答**: %s", text) + eol}
+		if !a.AnsweredAt.IsZero() {
+			added = append(added, fmt.Sprintf("**%s**: %s", fieldAnsweredAt, a.AnsweredAt.Format(time.RFC3339))+eol)
+		}
+		if a.Source != "" {
+			added = append(added, fmt.Sprintf("**%s**: %s", fieldSource, a.Source)+eol)
+		}
+		if q.AnsweredAtLine > 0 {
+			remove[q.AnsweredAtLine-1] = true
+		}
+		if q.SourceLine > 0 {
+			remove[q.SourceLine-1] = true
+		}
+
+		if q.AnswerLine > 0 {
+			replace[q.AnswerLine-1] = added[0]
+			insertAfter[q.AnswerLine-1] = added[1:]
+		} else {
+			insertAfter[statusIdx] = added
+		}
+	}
+
+	out := make([]string, 0, len(lines)+2*len(answers))
+	for i, l := range lines {
+		if !remove[i] {
+			if r, ok := replace[i]; ok {
+				l = r
+			}
+			out = append(out, l)
+		}
+		out = append(out, insertAfter[i]...)
+	}
+	return []byte(strings.Join(out, "\n")), nil
+}
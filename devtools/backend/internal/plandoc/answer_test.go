@@ -0,0 +1,136 @@
+package plandoc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyAnswers_Single(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		line    int
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "空行の前に回答行を挿入",
+			content: "### Q1: DB\n**ステータス**: 未回答\n\n### Q2: 次\n",
+			line:    2,
+			want:    "### Q1: DB\n**ステータス**: 回答済\n**回答**: A案で\n\n### Q2: 次\n",
+		},
+		{
+			name:    "既存の空の回答行を置換",
+			content: "### Q1: DB\n**ステータス**: 未回答\n**回答**:\n",
+			line:    2,
+			want:    "### Q1: DB\n**ステータス**: 回答済\n**回答**: A案で\n",
+		},
+		{
+			name:    "選択肢の後ろの回答行を置換",
+			content: "#### Q-BE-1: 構成\n**ステータス**: 未回答\n**選択肢**:\n- A案: 集約\n**回答**: -",
+			line:    2,
+			want:    "#### Q-BE-1: 構成\n**ステータス**: 回答済\n**選択肢**:\n- A案: 集約\n**回答**: A案で",
+		},
+		{
+			name:    "行ずれは前後2行まで許容",
+			content: "# 計画\n\n\n### Q1: DB\n**ステータス**: 未回答",
+			line:    3,
+			want:    "# 計画\n\n\n### Q1: DB\n**ステータス**: 回答済\n**回答**: A案で",
+		},
+		{
+			name:    "CRLF を保つ",
+			content: "### Q1: DB\r\n**ステータス**: 未回答\r\n",
+			line:    2,
+			want:    "### Q1: DB\r\n**ステータス**: 回答済\r\n**回答**: A案で\r\n",
+		},
+		{
+			name:    "回答済はエラー",
+			content: "### Q1: DB\n**ステータス**: 回答済\n**回答**: B\n",
+			line:    2,
+			wantErr: ErrNoUnanswered,
+		},
+		{
+			name:    "窓の外はエラー",
+			content: "### Q1: DB\n\n\n\n**ステータス**: 未回答\n",
+			line:    1,
+			wantErr: ErrNoUnanswered,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyAnswers([]byte(tt.content), []Answer{{Line: tt.line, Text: " A案で "}})
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "err = %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestFindUnanswered_Nearest(t *testing.T) {
+	d := Parse([]byte("**ステータス**: 未回答\n**ステータス**: 未回答\n\n**ステータス**: 未回答\n"))
+
+	q, ok := d.FindUnanswered(3)
+	require.True(t, ok)
+	assert.Equal(t, 2, q.StatusLine, "同距離なら前方を優先")
+}
+
+func TestApplyAnswers_Batch(t *testing.T) {
+	content := "### Q1: DB\n**ステータス**: 未回答\n\n### Q2: API\n**ステータス**: 未回答\n**回答**: -\n**回答日時**: 古い値\n\n### Q3: 残り\n**ステータス**: 未回答\n"
+	at := time.Date(2026, 7, 20, 10, 0, 0, 0, time.FixedZone("JST", 9*3600))
+
+	got, err := ApplyAnswers([]byte(content), []Answer{
+		{QuestionID: "Q2", Text: "REST", AnsweredAt: at, Source: "voice"},
+		{Line: 2, Text: "A案", AnsweredAt: at, Source: "dashboard"},
+	})
+	require.NoError(t, err)
+
+	want := "### Q1: DB\n**ステータス**: 回答済\n**回答**: A案\n**回答日時**: 2026-07-20T10:00:00+09:00\n**回答元**: dashboard\n\n" +
+		"### Q2: API\n**ステータス**: 回答済\n**回答**: REST\n**回答日時**: 2026-07-20T10:00:00+09:00\n**回答元**: voice\n\n" +
+		"### Q3: 残り\n**ステータス**: 未回答\n"
+	assert.Equal(t, want, string(got))
+
+	d := Parse(got)
+	q3, ok := d.FindUnansweredByID("Q3")
+	require.True(t, ok)
+	assert.Equal(t, "Q3", q3.ID)
+	assert.Equal(t, "2026-07-20T10:00:00+09:00", d.Questions[1].AnsweredAt)
+	assert.Equal(t, "voice", d.Questions[1].Source)
+	assert.Empty(t, Lint(d))
+}
+
+func TestApplyAnswers_AllOrNothing(t *testing.T) {
+	content := "### Q1: DB\n**ステータス**: 未回答\n### Q2: API\n**ステータス**: 回答済\n**回答**: REST\n"
+
+	tests := []struct {
+		name    string
+		answers []Answer
+		wantErr error
+	}{
+		{
+			name:    "1件でも回答済を含めば全体を拒否",
+			answers: []Answer{{QuestionID: "Q1", Text: "A"}, {QuestionID: "Q2", Text: "B"}},
+			wantErr: ErrNoUnanswered,
+		},
+		{
+			name:    "ID と行番号で同じ確認事項を指定",
+			answers: []Answer{{QuestionID: "Q1", Text: "A"}, {Line: 2, Text: "B"}},
+			wantErr: ErrDuplicateAnswer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyAnswers([]byte(content), tt.answers)
+			assert.True(t, errors.Is(err, tt.wantErr), "err = %v", err)
+			assert.Nil(t, got)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+// Package plandoc は計画書（開発/実装 配下の *_plan.md）の Markdown を型付きのモデルに構造化する。
+//
+// # 概要
+//
+// 計画書の確認事項はこれまで dashboard（scanUnanswered・回答書き戻し）と grrun（ClassifyResult）が
+// それぞれ正規表現で行単位に拾っていた。本パッケージは計画書を1度だけ解析し、メタデータ・見出し・
+// 確認事項（ステータス・回答・選択肢）・チェック項目・受け入れ条件を返す。未回答の検出、回答の書き戻し、
+// 記法の検査（gr-plan lint）はすべてこのモデルを通す。
+//
+// 確認事項の記法（chief-director.md と /plan の出力形式）:
+//
+//	### Q1: 見出し
+//	質問文
+//	**ステータス**: 未回答
+//	**選択肢**:
+//	- A案: ...
+//	- B案: ...
+//	**推奨**: A案
+//	**回答**: ...
+//	**回答日時**: 2026-07-20T10:00:00+09:00
+//	**回答元**: dashboard
+//
+// # 主要な型・関数
+//
+//   - Parse / ParseFile: 計画書を Document に構造化する（解析は失敗しない。読み込みのみエラー）
+//   - Document: Title・Metadata・Sections・Questions・Checklist・Acceptance
+//   - Question / Option / Status: 確認事項と選択肢、ステータス（未回答 / 回答済）
+//   - CheckItem / Criterion: チェック項目（Progress で完了数）と受け入れ条件
+//   - ApplyAnswers / Answer: 複数の未回答を一括で回答済にし、回答・回答日時・回答元の行を書き込む（全件適用か無変更）。
+//     対象は ID（FindUnansweredByID）または指定行の前後 AnswerWindow 行（FindUnanswered）で特定する
+//   - Lint / Issue: 見出しの無いステータス行・ステータス行の欠落・未知のステータス・回答の無い回答済・ID 重複を報告する
+//   - UnansweredPattern: 未回答ステータス行の SSOT パターン（grrun.UnansweredPattern はこの別名）
+//
+// # 設計方針
+//
+//   - 確認事項の見出しは「Q」で始まる ID と「:」（Q1: / Q-BE-1:）。見出しレベルは問わず、
+//     同レベル以上の次の見出しまでを1つの確認事項とする
+//   - ステータス行は行頭（箇条書き記号は可）の「**ステータス**:」のみ。表や文中の記法の言及は数えない。
+//     見出しの無いステータス行も確認事項として扱い（従来の検出と互換）、Lint で報告する
+//   - フェンスコードブロックの中は記法の例示とみなし解析しない
+//   - 質問文は見出しとステータス行の間の本文（「**」で始まる行と選択肢を除く）と「**質問**:」の値
+//   - 受け入れ条件は見出しに「受け入れ条件」「受け入れ基準」「完了条件」等を含む節の箇条書きと表の行
+//     （表のヘッダ行・区切り行を除く）
+//   - 行番号は1始まりで、CRLF の文書でも書き戻し側の行分割と一致させる
+package plandoc
@@ -0,0 +1,97 @@
+package plandoc
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Issue の種別
+const (
+	// IssueOrphanStatus は確認事項の見出し（Q1: 等）の無いステータス行です
+	IssueOrphanStatus = "orphan_status"
+	// IssueMissingStatus はステータス行の無い確認事項です
+	IssueMissingStatus = "missing_status"
+	// IssueUnknownStatus は未回答・回答済以外のステータスです
+	IssueUnknownStatus = "unknown_status"
+	// IssueDuplicateStatus は1つの確認事項に複数あるステータス行です
+	IssueDuplicateStatus = "duplicate_status"
+	// IssueMissingAnswer は回答済なのに回答が空（または「-」）の確認事項です
+	IssueMissingAnswer = "missing_answer"
+	// IssueDuplicateID は同じ ID の確認事項です
+	IssueDuplicateID = "duplicate_id"
+)
+
+// Issue は計画書の確認事項の記法上の問題です
+type Issue struct {
+	Line       int    `json:"line"`
+	Code       string `json:"code"`
+	QuestionID string `json:"questionId,omitempty"`
+	Message    string `json:"message"`
+}
+
+// String は「行: 種別: メッセージ」形式で返します
+func (i Issue) String() string {
+	return fmt.Sprintf("%d: %s: %s", i.Line, i.Code, i.Message)
+}
+
+// Lint は確認事項の記法上の問題を行番号順に返します。
+// 未回答の検出（Dashboard・gr-run）や回答の書き戻しが確認事項を取りこぼす・取り違える原因を報告します。
+func Lint(d *Document) []Issue {
+	issues := []Issue{}
+	firstLine := map[string]int{}
+
+	for _, q := range d.Questions {
+		if q.ID == "" {
+			issues = append(issues, Issue{
+				Line:    q.StatusLine,
+				Code:    IssueOrphanStatus,
+				Message: "ステータス行の前に「### Q1: ...」形式の見出しがありません",
+			})
+		} else if prev, ok := firstLine[q.ID]; ok {
+			issues = append(issues, Issue{
+				Line:       q.Line,
+				Code:       IssueDuplicateID,
+				QuestionID: q.ID,
+				Message:    fmt.Sprintf("%s は %d 行目と重複しています", q.ID, prev),
+			})
+		} else {
+			firstLine[q.ID] = q.Line
+		}
+
+		switch {
+		case q.StatusLine == 0:
+			issues = append(issues, Issue{
+				Line:       q.Line,
+				Code:       IssueMissingStatus,
+				QuestionID: q.ID,
+				Message:    "「**ステータス**: 未回答」行がありません",
+			})
+		case q.Status != StatusUnanswered && q.Status != StatusAnswered:
+			issues = append(issues, Issue{
+				Line:       q.StatusLine,
+				Code:       IssueUnknownStatus,
+				QuestionID: q.ID,
+				Message:    fmt.Sprintf("ステータス %q は未回答・回答済のいずれでもありません", string(q.Status)),
+			})
+		case q.Status == StatusAnswered && (q.Answer == "" || q.Answer == "-"):
+			issues = append(issues, Issue{
+				Line:       q.StatusLine,
+				Code:       IssueMissingAnswer,
+				QuestionID: q.ID,
+				Message:    "回答済ですが「**回答**:」がありません",
+			})
+		}
+
+		for _, l := range q.extraStatusLines {
+			issues = append(issues, Issue{
+				Line:       l,
+				Code:       IssueDuplicateStatus,
+				QuestionID: q.ID,
+				Message:    fmt.Sprintf("ステータス行が %d 行目にもあります", q.StatusLine),
+			})
+		}
+	}
+
+	sort.SliceStable(issues, func(a, b int) bool { return issues[a].Line < issues[b].Line })
+	return issues
+}
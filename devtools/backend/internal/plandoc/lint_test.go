@@ -0,0 +1,57 @@
+package plandoc
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "正常",
+			content: "### Q1: A\n**ステータス**: 未回答\n### Q2: B\n**ステータス**: 回答済\n**回答**: はい\n",
+			want:    []string{},
+		},
+		{
+			name:    "見出しの無いステータス行",
+			content: "# 計画\n**ステータス**: 未回答\n",
+			want:    []string{"2:" + IssueOrphanStatus},
+		},
+		{
+			name:    "ステータス行が無い",
+			content: "### Q1: A\n本文\n",
+			want:    []string{"1:" + IssueMissingStatus},
+		},
+		{
+			name:    "未知のステータス",
+			content: "### Q1: A\n**ステータス**: 検討中\n",
+			want:    []string{"2:" + IssueUnknownStatus},
+		},
+		{
+			name:    "回答済なのに回答が無い",
+			content: "### Q1: A\n**ステータス**: 回答済\n**回答**: -\n",
+			want:    []string{"2:" + IssueMissingAnswer},
+		},
+		{
+			name:    "ID の重複とステータス行の重複",
+			content: "### Q1: A\n**ステータス**: 未回答\n**ステータス**: 未回答\n### Q1: B\n**ステータス**: 未回答\n",
+			want:    []string{"3:" + IssueDuplicateStatus, "4:" + IssueDuplicateID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, is := range Lint(Parse([]byte(tt.content))) {
+				got = append(got, fmt.Sprintf("%d:%s", is.Line, is.Code))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,377 @@
+package plandoc
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	// headingRe は ATX 見出し行です
+	headingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*$`)
+	// questionTitleRe は確認事項の見出し（Q1: / Q-BE-1: 等）です
+	questionTitleRe = regexp.MustCompile(`^(Q[A-Za-z0-9_-]*\d)\s*[:：]\s*(.*)$`)
+	// fieldRe は「**キー**: 値」行です（箇条書きの「- **キー**: 値」も含む）
+	fieldRe = regexp.MustCompile(`^\s*(?:[-*+]\s+)?\*\*([^*]+?)\*\*\s*[:：]\s*(.*?)\s*$`)
+	// frontMatterFieldRe は YAML フロントマターの「キー: 値」行です
+	frontMatterFieldRe = regexp.MustCompile(`^([A-Za-z0-9_-]+)\s*:\s*(.*?)\s*$`)
+	// listItemRe は箇条書き・番号付きリストの項目です
+	listItemRe = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+(.*?)\s*$`)
+	// checkItemRe はチェック項目です
+	checkItemRe = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\]\s+(.*?)\s*$`)
+	// optionLabelRe は選択肢のラベル（A案: / B: 等）です
+	optionLabelRe = regexp.MustCompile(`^([A-Za-z0-9]+案|[A-Z])\s*[:：]\s*(.*)$`)
+	// tableSeparatorRe は表の区切り行（|---|:--:|）です
+	tableSeparatorRe = regexp.MustCompile(`^\|?(\s*:?-+:?\s*\|)*\s*:?-+:?\s*\|?$`)
+)
+
+// フィールド名（「**キー**:」のキー）
+const (
+	fieldStatus     = "ステータス"
+	fieldAnswer     = "回答"
+	fieldQuestion   = "質問"
+	fieldOptions    = "選択肢"
+	fieldRecommend  = "推奨"
+	fieldAnsweredAt = "回答日時"
+	fieldSource     = "回答元"
+)
+
+// acceptanceKeywords は受け入れ条件の節とみなす見出しの語です
+var acceptanceKeywords = []string{"受け入れ条件", "受け入れ基準", "受入条件", "完了条件", "Acceptance"}
+
+// ParseFile は計画書ファイルを読み込んで構造化します
+func ParseFile(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan file: %w", err)
+	}
+	d := Parse(data)
+	d.Path = path
+	return d, nil
+}
+
+// Parse は計画書の Markdown を構造化します。
+// フェンスコードブロック（``` / ~~~）の中は記法の例示とみなし、見出し・確認事項・チェック項目として扱いません。
+func Parse(data []byte) *Document {
+	p := &parser{
+		doc: &Document{
+			Metadata:   map[string]string{},
+			Sections:   []Section{},
+			Questions:  []Question{},
+			Checklist:  []CheckItem{},
+			Acceptance: []Criterion{},
+		},
+		cur: -1,
+	}
+	lines := splitLines(string(data))
+
+	start := p.frontMatter(lines)
+	for i := start; i < len(lines); i++ {
+		p.line(i+1, lines[i])
+	}
+	p.closeSections(0, len(lines))
+	p.closeQuestion(len(lines))
+	return p.doc
+}
+
+// splitLines は行に分割し、CRLF の CR を取り除きます。行番号は書き戻し側の分割と一致します。
+func splitLines(s string) []string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSuffix(l, "\r")
+	}
+	return lines
+}
+
+// parser は1文書の解析状態です
+type parser struct {
+	doc *Document
+
+	// fence は開いているフェンスの記号（``` / ~~~）。閉じていれば空
+	fence string
+	// open は開いている見出しの Sections 上の添字（外側から順）
+	open []int
+	// seenSection は ## 以下の見出しを1つでも見たか（冒頭メタデータの終端判定）
+	seenSection bool
+	// inTable は直前の行が表の行だったか（表の先頭行＝ヘッダ行の判定）
+	inTable bool
+
+	// cur は解析中の確認事項の Questions 上の添字（無ければ -1）
+	cur int
+	// inOptions は「**選択肢**:」の直後の箇条書きを読んでいるか
+	inOptions bool
+	// textParts は解析中の確認事項の本文
+	textParts []string
+}
+
+// frontMatter は先頭の YAML フロントマターを Metadata に読み込み、本文の開始行（0始まり）を返します
+func (p *parser) frontMatter(lines []string) int {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return 0
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			for _, l := range lines[1:i] {
+				if m := frontMatterFieldRe.FindStringSubmatch(l); m != nil {
+					p.doc.Metadata[m[1]] = strings.Trim(m[2], `"'`)
+				}
+			}
+			return i + 1
+		}
+	}
+	// 閉じられていなければフロントマターとみなさない
+	return 0
+}
+
+// line は1行を解析します（n は1始まりの行番号）
+func (p *parser) line(n int, line string) {
+	trimmed := strings.TrimSpace(line)
+
+	if p.fence != "" {
+		if strings.HasPrefix(trimmed, p.fence) {
+			p.fence = ""
+		}
+		return
+	}
+	if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+		p.fence = trimmed[:3]
+		p.inTable = false
+		return
+	}
+
+	if m := headingRe.FindStringSubmatch(line); m != nil {
+		p.heading(n, len(m[1]), m[2], trimmed)
+		p.inTable = false
+		return
+	}
+
+	wasTable := p.inTable
+	p.inTable = strings.HasPrefix(trimmed, "|")
+
+	if m := checkItemRe.FindStringSubmatch(line); m != nil {
+		done := m[1] != " "
+		p.doc.Checklist = append(p.doc.Checklist, CheckItem{
+			Text:    m[2],
+			Done:    done,
+			Line:    n,
+			Section: p.innermostTitle(),
+		})
+		if p.inAcceptance() {
+			p.doc.Acceptance = append(p.doc.Acceptance, Criterion{Text: m[2], Line: n, Done: &done})
+		}
+	} else if p.inAcceptance() {
+		if m := listItemRe.FindStringSubmatch(line); m != nil && m[1] != "" {
+			p.doc.Acceptance = append(p.doc.Acceptance, Criterion{Text: m[1], Line: n})
+		} else if p.inTable && wasTable && !tableSeparatorRe.MatchString(trimmed) {
+			p.doc.Acceptance = append(p.doc.Acceptance, Criterion{Text: tableCells(trimmed), Line: n})
+		}
+	}
+
+	p.body(n, line, trimmed)
+}
+
+// heading は見出し行を処理します
+func (p *parser) heading(n, level int, title, raw string) {
+	// 同レベル以上の見出しで確認事項を閉じる（見出しの無いステータス行由来は任意の見出しで閉じる）
+	if p.cur >= 0 {
+		q := p.doc.Questions[p.cur]
+		if q.ID == "" || level <= q.Level {
+			p.closeQuestion(n - 1)
+		}
+	}
+	p.closeSections(level, n-1)
+
+	p.doc.Sections = append(p.doc.Sections, Section{Level: level, Title: title, Line: n})
+	p.open = append(p.open, len(p.doc.Sections)-1)
+
+	if level == 1 && p.doc.Title == "" {
+		p.doc.Title = title
+	}
+	if level >= 2 {
+		p.seenSection = true
+	}
+
+	if m := questionTitleRe.FindStringSubmatch(title); m != nil {
+		p.doc.Questions = append(p.doc.Questions, Question{
+			ID:      m[1],
+			Heading: raw,
+			Title:   strings.TrimSpace(m[2]),
+			Level:   level,
+			Line:    n,
+		})
+		p.cur = len(p.doc.Questions) - 1
+	}
+}
+
+// body は見出し以外の行を確認事項・冒頭メタデータとして処理します
+func (p *parser) body(n int, line, trimmed string) {
+	field := fieldRe.FindStringSubmatch(line)
+
+	if field != nil && field[1] == fieldStatus {
+		p.status(n, field[2])
+		return
+	}
+
+	if p.cur < 0 {
+		if field != nil && !p.seenSection {
+			p.doc.Metadata[field[1]] = field[2]
+		}
+		return
+	}
+
+	q := &p.doc.Questions[p.cur]
+	if field != nil {
+		p.inOptions = false
+		switch field[1] {
+		case fieldAnswer:
+			if q.AnswerLine == 0 {
+				q.Answer = field[2]
+				q.AnswerLine = n
+			}
+		case fieldQuestion:
+			if field[2] != "" {
+				p.textParts = append(p.textParts, field[2])
+			}
+		case fieldOptions:
+			p.inOptions = true
+		case fieldRecommend:
+			q.Recommended = field[2]
+		case fieldAnsweredAt:
+			if q.AnsweredAtLine == 0 {
+				q.AnsweredAt = field[2]
+				q.AnsweredAtLine = n
+			}
+		case fieldSource:
+			if q.SourceLine == 0 {
+				q.Source = field[2]
+				q.SourceLine = n
+			}
+		}
+		return
+	}
+
+	if trimmed == "" {
+		if len(q.Options) > 0 {
+			p.inOptions = false
+		}
+		return
+	}
+
+	if m := listItemRe.FindStringSubmatch(line); m != nil {
+		if lm := optionLabelRe.FindStringSubmatch(m[1]); lm != nil {
+			q.Options = append(q.Options, Option{Label: lm[1], Text: strings.TrimSpace(lm[2]), Line: n})
+			return
+		}
+		if p.inOptions {
+			q.Options = append(q.Options, Option{Text: m[1], Line: n})
+			return
+		}
+	}
+
+	// 見出しとステータス行の間の本文を質問文とする
+	if q.StatusLine == 0 && !strings.HasPrefix(trimmed, "**") {
+		p.textParts = append(p.textParts, trimmed)
+	}
+}
+
+// status はステータス行を処理します
+func (p *parser) status(n int, value string) {
+	st := parseStatus(value)
+
+	if p.cur >= 0 {
+		q := &p.doc.Questions[p.cur]
+		// 見出しの無いステータス行が続く場合はそれぞれを別の確認事項とする
+		if q.ID == "" && q.StatusLine != 0 {
+			p.closeQuestion(n - 1)
+		} else {
+			if q.StatusLine == 0 {
+				q.Status = st
+				q.StatusLine = n
+			} else {
+				q.extraStatusLines = append(q.extraStatusLines, n)
+			}
+			p.inOptions = false
+			return
+		}
+	}
+
+	p.doc.Questions = append(p.doc.Questions, Question{
+		Line:       n,
+		Status:     st,
+		StatusLine: n,
+	})
+	p.cur = len(p.doc.Questions) - 1
+}
+
+// closeQuestion は解析中の確認事項を endLine で閉じます
+func (p *parser) closeQuestion(endLine int) {
+	if p.cur < 0 {
+		return
+	}
+	q := &p.doc.Questions[p.cur]
+	q.EndLine = endLine
+	if q.EndLine < q.Line {
+		q.EndLine = q.Line
+	}
+	q.Text = strings.Join(p.textParts, " ")
+	p.cur = -1
+	p.inOptions = false
+	p.textParts = nil
+}
+
+// closeSections は level 以上の深さで開いている見出しを endLine で閉じます
+func (p *parser) closeSections(level, endLine int) {
+	for len(p.open) > 0 {
+		idx := p.open[len(p.open)-1]
+		if p.doc.Sections[idx].Level < level {
+			return
+		}
+		p.doc.Sections[idx].EndLine = endLine
+		p.open = p.open[:len(p.open)-1]
+	}
+}
+
+// innermostTitle は最も内側で開いている見出しを返します
+func (p *parser) innermostTitle() string {
+	if len(p.open) == 0 {
+		return ""
+	}
+	return p.doc.Sections[p.open[len(p.open)-1]].Title
+}
+
+// inAcceptance は受け入れ条件の節（またはその配下）にいるかを返します
+func (p *parser) inAcceptance() bool {
+	for _, idx := range p.open {
+		title := p.doc.Sections[idx].Title
+		for _, kw := range acceptanceKeywords {
+			if strings.Contains(title, kw) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseStatus はステータス行の値を Status に変換します（未知の値はそのまま返します）
+func parseStatus(value string) Status {
+	switch {
+	case strings.HasPrefix(value, string(StatusUnanswered)):
+		return StatusUnanswered
+	case strings.HasPrefix(value, string(StatusAnswered)):
+		return StatusAnswered
+	default:
+		return Status(value)
+	}
+}
+
+// tableCells は表の行の空でないセルを「 | 」で連結します
+func tableCells(row string) string {
+	var cells []string
+	for _, c := range strings.Split(strings.Trim(row, "|"), "|") {
+		if c = strings.TrimSpace(c); c != "" {
+			cells = append(cells, c)
+		}
+	}
+	return strings.Join(cells, " | ")
+}
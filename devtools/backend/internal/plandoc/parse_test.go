@@ -0,0 +1,179 @@
+package plandoc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const samplePlan = `---
+owner: go-impl
+---
+# 質問待ち検知 実装計画
+
+**作成日**: 2026-07-20
+**ブランチ**: feat/idle
+
+## 概要
+
+概要本文。
+
+## 確認事項
+
+### Q1: DBスキーマについて
+テーブル構成はどうしますか？
+**ステータス**: 未回答
+
+### Q2: APIエンドポイント
+RESTとGraphQLどちらにしますか？
+**ステータス**: 回答済
+**回答**: RESTで
+
+#### Q-BE-1: パッケージ構成
+
+**ステータス**: 未回答
+**選択肢**:
+- A案: internal/tts に集約
+- B案: handler と service に分散
+
+**推奨**: A案
+
+## 実装ステップ
+
+- [x] 型を追加
+- [ ] ハンドラを追加
+
+` + "```markdown" + `
+### Q9: 記法の例
+**ステータス**: 未回答
+- [ ] 例のチェック
+` + "```" + `
+
+## 受け入れ条件
+
+| # | 条件 | 検証手段 |
+|---|---|---|
+| AC1 | 質問待ちが表示される | 実機 |
+
+- [ ] go test が通る
+`
+
+func TestParse(t *testing.T) {
+	d := Parse([]byte(samplePlan))
+
+	assert.Equal(t, "質問待ち検知 実装計画", d.Title)
+	assert.Equal(t, map[string]string{
+		"owner": "go-impl",
+		"作成日":   "2026-07-20",
+		"ブランチ":  "feat/idle",
+	}, d.Metadata)
+
+	require.Len(t, d.Questions, 3, "フェンス内の Q9 は数えない")
+
+	q1 := d.Questions[0]
+	assert.Equal(t, "Q1", q1.ID)
+	assert.Equal(t, "### Q1: DBスキーマについて", q1.Heading)
+	assert.Equal(t, "DBスキーマについて", q1.Title)
+	assert.Equal(t, "テーブル構成はどうしますか？", q1.Text)
+	assert.Equal(t, StatusUnanswered, q1.Status)
+	assert.Equal(t, 15, q1.Line)
+	assert.Equal(t, 17, q1.StatusLine)
+
+	q2 := d.Questions[1]
+	assert.Equal(t, StatusAnswered, q2.Status)
+	assert.Equal(t, "RESTで", q2.Answer)
+	assert.Equal(t, 22, q2.AnswerLine)
+
+	q3 := d.Questions[2]
+	assert.Equal(t, "Q-BE-1", q3.ID)
+	assert.Equal(t, 4, q3.Level)
+	assert.Equal(t, StatusUnanswered, q3.Status)
+	assert.Equal(t, []Option{
+		{Label: "A案", Text: "internal/tts に集約", Line: 28},
+		{Label: "B案", Text: "handler と service に分散", Line: 29},
+	}, q3.Options)
+	assert.Equal(t, "A案", q3.Recommended)
+	assert.Equal(t, 32, q3.EndLine, "次の ## 見出しの手前で閉じる")
+
+	assert.True(t, d.HasUnanswered())
+	assert.Len(t, d.Unanswered(), 2)
+
+	done, total := d.Progress()
+	assert.Equal(t, 1, done)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, "実装ステップ", d.Checklist[0].Section)
+
+	require.Len(t, d.Acceptance, 2)
+	assert.Equal(t, "AC1 | 質問待ちが表示される | 実機", d.Acceptance[0].Text)
+	assert.Nil(t, d.Acceptance[0].Done)
+	assert.Equal(t, "go test が通る", d.Acceptance[1].Text)
+	require.NotNil(t, d.Acceptance[1].Done)
+	assert.False(t, *d.Acceptance[1].Done)
+
+	var titles []string
+	for _, s := range d.Sections {
+		titles = append(titles, s.Title)
+	}
+	assert.Equal(t, []string{"質問待ち検知 実装計画", "概要", "確認事項", "Q1: DBスキーマについて", "Q2: APIエンドポイント", "Q-BE-1: パッケージ構成", "実装ステップ", "受け入れ条件"}, titles)
+	assert.Equal(t, len(splitLines(samplePlan)), d.Sections[0].EndLine)
+}
+
+func TestParse_Questions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Question
+	}{
+		{
+			name:    "見出しの無いステータス行は個別の確認事項",
+			content: "# 計画\n**ステータス**: 未回答\n**ステータス**: 未回答\n",
+			want: []Question{
+				{Line: 2, EndLine: 2, Status: StatusUnanswered, StatusLine: 2},
+				{Line: 3, EndLine: 4, Status: StatusUnanswered, StatusLine: 3},
+			},
+		},
+		{
+			name:    "文中のステータス記法は数えない",
+			content: "# 計画\n| 答え待ち | `**ステータス**: 未回答` で検出 |\n",
+			want:    []Question{},
+		},
+		{
+			name:    "質問フィールドと CRLF",
+			content: "### Q1: 方式\r\n**質問**: A と B のどちらにするか\r\n**ステータス**: 回答済\r\n**回答**: -\r\n",
+			want: []Question{
+				{ID: "Q1", Heading: "### Q1: 方式", Title: "方式", Level: 3, Line: 1, EndLine: 5, Text: "A と B のどちらにするか", Status: StatusAnswered, StatusLine: 3, Answer: "-", AnswerLine: 4},
+			},
+		},
+		{
+			name:    "深い見出しは確認事項を閉じない",
+			content: "### Q1: 方式\n#### 補足\n説明\n**ステータス**: 未回答\n### Q2: 次\n",
+			want: []Question{
+				{ID: "Q1", Heading: "### Q1: 方式", Title: "方式", Level: 3, Line: 1, EndLine: 4, Text: "説明", Status: StatusUnanswered, StatusLine: 4},
+				{ID: "Q2", Heading: "### Q2: 次", Title: "次", Level: 3, Line: 5, EndLine: 6},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse([]byte(tt.content)).Questions
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.md")
+	require.NoError(t, os.WriteFile(path, []byte("# 計画\n"), 0644))
+
+	d, err := ParseFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, path, d.Path)
+	assert.Equal(t, "計画", d.Title)
+
+	_, err = ParseFile(filepath.Join(t.TempDir(), "missing.md"))
+	assert.Error(t, err)
+}
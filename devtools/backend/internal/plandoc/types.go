@@ -0,0 +1,143 @@
+package plandoc
+
+import "errors"
+
+// ErrNoUnanswered は指定行の付近に未回答の確認事項が見つからない場合のエラーです
+// （既に回答済み、または計画書の編集で行がずれた）
+var ErrNoUnanswered = errors.New("no unanswered question near the line")
+
+// UnansweredPattern は確認事項の未回答を検出する正規表現パターンです。
+// chief-director.md の SSOT パターンと一致させること。Parse はこれを行頭に固定した
+// 「**ステータス**:」行として解釈します。
+const UnansweredPattern = `\*\*ステータス\*\*:\s*未回答`
+
+// Status は確認事項のステータス行の値です
+type Status string
+
+const (
+	// StatusUnanswered は「**ステータス**: 未回答」です
+	StatusUnanswered Status = "未回答"
+	// StatusAnswered は「**ステータス**: 回答済」です
+	StatusAnswered Status = "回答済"
+	// StatusMissing はステータス行が無い確認事項です
+	StatusMissing Status = ""
+)
+
+// Document は1つの計画書を構造化したものです
+type Document struct {
+	// Path は ParseFile に渡したパス（Parse の場合は空）
+	Path string `json:"path,omitempty"`
+	// Title は最初の「# 見出し」
+	Title string `json:"title"`
+	// Metadata は冒頭（最初の ## 見出しより前）の「**キー**: 値」行と YAML フロントマターの「キー: 値」行です
+	Metadata map[string]string `json:"metadata"`
+	// Sections は見出しを出現順に並べたもの（入れ子は Level で表す）
+	Sections []Section `json:"sections"`
+	// Questions は確認事項（「Q1:」「Q-BE-1:」形式の見出しと、見出しの無いステータス行）
+	Questions []Question `json:"questions"`
+	// Checklist は「- [ ]」「- [x]」のチェック項目です
+	Checklist []CheckItem `json:"checklist"`
+	// Acceptance は受け入れ条件・完了条件の節にある箇条書きと表の行です
+	Acceptance []Criterion `json:"acceptance"`
+}
+
+// Section は1つの見出しとその範囲（次の同レベル以上の見出しの手前まで）です。行番号は1始まり。
+type Section struct {
+	Level   int    `json:"level"`
+	Title   string `json:"title"`
+	Line    int    `json:"line"`
+	EndLine int    `json:"endLine"`
+}
+
+// Question は1つの確認事項です。行番号は1始まりで、該当行が無い場合は0です。
+type Question struct {
+	// ID は見出しの識別子（例: Q1, Q-BE-1）。見出しの無いステータス行では空
+	ID string `json:"id"`
+	// Heading は見出し行そのもの（例: ### Q1: DBスキーマについて）
+	Heading string `json:"heading"`
+	// Title は見出しの「:」以降
+	Title string `json:"title"`
+	Level int    `json:"level"`
+	// Line は見出し行（見出しが無い場合はステータス行）
+	Line    int `json:"line"`
+	EndLine int `json:"endLine"`
+	// Text は見出しとステータス行の間の本文と「**質問**:」の値を空白で連結したもの
+	Text       string `json:"text"`
+	Status     Status `json:"status"`
+	StatusLine int    `json:"statusLine"`
+	Answer     string `json:"answer,omitempty"`
+	AnswerLine int    `json:"answerLine,omitempty"`
+	// AnsweredAt は「**回答日時**:」の値（ApplyAnswers が RFC3339 で書き込む）
+	AnsweredAt     string `json:"answeredAt,omitempty"`
+	AnsweredAtLine int    `json:"answeredAtLine,omitempty"`
+	// Source は「**回答元**:」の値（dashboard / voice / ntfy 等）
+	Source      string   `json:"source,omitempty"`
+	SourceLine  int      `json:"sourceLine,omitempty"`
+	Options     []Option `json:"options,omitempty"`
+	Recommended string   `json:"recommended,omitempty"`
+
+	// extraStatusLines は2つ目以降のステータス行です（Lint 用）
+	extraStatusLines []int
+}
+
+// Unanswered は未回答かを返します
+func (q Question) Unanswered() bool {
+	return q.Status == StatusUnanswered
+}
+
+// Option は確認事項の選択肢（「- A案: ...」）です
+type Option struct {
+	// Label は「A案」「B」等（ラベルの無い箇条書きは空）
+	Label string `json:"label,omitempty"`
+	Text  string `json:"text"`
+	Line  int    `json:"line"`
+}
+
+// CheckItem は1つのチェック項目です
+type CheckItem struct {
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+	Line int    `json:"line"`
+	// Section は項目を含む最も内側の見出し
+	Section string `json:"section,omitempty"`
+}
+
+// Criterion は1つの受け入れ条件です
+type Criterion struct {
+	// Text は箇条書きの本文、または表の行のセルを「 | 」で連結したもの
+	Text string `json:"text"`
+	Line int    `json:"line"`
+	// Done はチェック項目の場合のみ設定されます
+	Done *bool `json:"done,omitempty"`
+}
+
+// Unanswered は未回答の確認事項を出現順に返します
+func (d *Document) Unanswered() []Question {
+	var out []Question
+	for _, q := range d.Questions {
+		if q.Unanswered() {
+			out = append(out, q)
+		}
+	}
+	return out
+}
+
+// HasUnanswered は未回答の確認事項があるかを返します
+func (d *Document) HasUnanswered() bool {
+	for _, q := range d.Questions {
+		if q.Unanswered() {
+			return true
+		}
+	}
+	return false
+}
+
+// Progress はチェック項目の完了数と総数を返します
+func (d *Document) Progress() (done, total int) {
+	for _, c := range d.Checklist {
+		if c.Done {
+			done++
+		}
+	}
+	return done, len(d.Checklist)
+}
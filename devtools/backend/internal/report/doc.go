@@ -0,0 +1,32 @@
+// Package report は登録プロジェクトの活動ダイジェスト（日次・週次）の生成・描画・定期配信を提供する。
+//
+// # 概要
+//
+// 期間内に 開発/実装/完了 へ移ったタスク、異常終了・要確認で終わった gr-run の実行、
+// 確認事項の質問と回答、実行回数・実行時間・トークン使用量、停滞した運用エントリをプロジェクトごとにまとめる。
+// GET /api/reports/digest から JSON / Markdown / HTML で取得でき、設定の digest.daily / digest.weekly の時刻に
+// 通知（report.digest）で送る。digest.writeDocs が有効なら各プロジェクトの 開発/資料 にも Markdown を書き出す。
+//
+// # 主要な型・関数
+//
+//   - Generator / NewGenerator: ダイジェストの生成（Generate）
+//   - Query / Period: 生成条件（day=直近24時間・week=直近7日、終わりの時刻、プロジェクト）
+//   - Digest / ProjectDigest / Totals: 生成結果
+//   - Render / Format / Markdown / Brief: JSON・Markdown・HTML への描画と通知本文向けの要約
+//   - Scheduler / NewScheduler / Schedule: 配信予定の時刻に生成して通知する定期ジョブ
+//   - WriteDoc: 開発/資料/digest_<期間>_<日付>.md への書き出し
+//   - ErrValidation: 不正な期間・形式・未登録プロジェクト
+//
+// # 設計方針
+//
+//   - 新しい記録は持たず、既存の記録から集める。完了タスクは 開発/実装 の git 履歴（gitinfo.Arrivals）、
+//     実行結果は gr-run の実行履歴、回答は計画書の回答日時、質問数と運用の停滞はダッシュボードの時系列
+//     （history）、トークン使用量は会話ログの分析（analytics）から求める
+//   - 質問数は計画書に質問日時が無いため、時系列の未回答数の増加分の合計とする
+//   - 実行のコストは金額が記録されておらずモデルごとの単価も持たないため、Cost としてトークン数で報告する
+//     （金額への換算はしない。実行回数・実行時間は Runs に別に載せる）
+//   - 完了の中での移動（アーカイブへの整理）は完了に数えない
+//   - 取得元のいずれかが失敗しても全体は失敗させず、Warnings に記録して取れた範囲で返す
+//   - 内容の無いプロジェクトは含めない（通知・書き出しも行わない）
+//   - 定期配信は1分ごとに時刻を確認し、同じ予定は同じ日に1回だけ送る。停止中に過ぎた分は送り直さない
+package report
@@ -0,0 +1,246 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"ghostrunner/backend/internal/analytics"
+	"ghostrunner/backend/internal/dashboard"
+	"ghostrunner/backend/internal/gitinfo"
+	"ghostrunner/backend/internal/grrun"
+	"ghostrunner/backend/internal/history"
+	"ghostrunner/backend/internal/projects"
+)
+
+// maxAnswers はダイジェストに集める回答の上限です（回答履歴の上限と同じ）
+const maxAnswers = 1000
+
+// Generator は登録プロジェクトのダイジェストを生成します。
+// 完了タスクは git の履歴、実行結果は gr-run の実行履歴、質問はダッシュボードの時系列と計画書の回答日時、
+// トークン使用量は会話ログの分析、運用の停滞はダッシュボードの時系列から集めます。
+// いずれかの取得に失敗しても全体は失敗させず、Digest.Warnings に記録して取れた範囲で返します
+type Generator struct {
+	projectsProvider func() ([]projects.Project, error)
+	runsDir          string
+	history          history.Store
+	analytics        analytics.Service
+	now              func() time.Time
+	arrivals         func(ctx context.Context, dir, pathspec string, from, to time.Time) ([]gitinfo.FileArrival, error)
+}
+
+// NewGenerator は新しい Generator を生成します。runsDir は gr-run の実行履歴の格納先です。
+// hist・an は nil 許容で、nil の場合は該当する項目を集めません。now が nil なら time.Now を使います
+func NewGenerator(projectsProvider func() ([]projects.Project, error), runsDir string, hist history.Store, an analytics.Service, now func() time.Time) *Generator {
+	if now == nil {
+		now = time.Now
+	}
+	return &Generator{
+		projectsProvider: projectsProvider,
+		runsDir:          runsDir,
+		history:          hist,
+		analytics:        an,
+		now:              now,
+		arrivals:         gitinfo.Arrivals,
+	}
+}
+
+// Generate はダイジェストを生成します。内容の無いプロジェクトは含めません
+func (g *Generator) Generate(ctx context.Context, q Query) (*Digest, error) {
+	if q.Period == "" {
+		q.Period = PeriodDay
+	}
+	if _, err := ParsePeriod(string(q.Period)); err != nil {
+		return nil, err
+	}
+	now := g.now()
+	to := q.To
+	if to.IsZero() {
+		to = now
+	}
+	from := to.Add(-q.Period.span())
+
+	projs, err := g.projectsProvider()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load projects: %w", err)
+	}
+	if q.Project != "" {
+		var matched []projects.Project
+		for _, p := range projs {
+			if filepath.Clean(p.Path) == filepath.Clean(q.Project) {
+				matched = append(matched, p)
+			}
+		}
+		if len(matched) == 0 {
+			return nil, fmt.Errorf("%w: project not in allowed list: %s", ErrValidation, q.Project)
+		}
+		projs = matched
+	}
+
+	d := &Digest{Period: q.Period, From: from, To: to, GeneratedAt: now, Projects: []ProjectDigest{}}
+	warn := func(format string, args ...any) {
+		d.Warnings = append(d.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	answered := g.answered(projs, from, to, &d.Warnings)
+	samples := g.samples(q.Project, from, to, warn)
+	costs := g.costs(ctx, q.Project, from, to, warn)
+
+	for _, p := range projs {
+		pd := ProjectDigest{
+			Name:      p.Name,
+			Path:      p.Path,
+			Completed: g.completed(ctx, p.Path, from, to, warn),
+			Answered:  answered[filepath.Clean(p.Path)],
+		}
+		if pd.Name == "" {
+			pd.Name = filepath.Base(p.Path)
+		}
+		pd.Problems, pd.Runs = g.runs(p.Path, from, to, warn)
+		pd.QuestionsAsked, pd.StaleOps = fromSamples(samples[filepath.Clean(p.Path)])
+		pd.Cost = costs[filepath.Clean(p.Path)]
+		if pd.empty() {
+			continue
+		}
+
+		d.Totals.add(pd)
+		d.Projects = append(d.Projects, pd)
+	}
+	return d, nil
+}
+
+// completed は 開発/実装 の git 履歴から、期間内のコミットで 開発/実装/完了 の直下へ移動・追加されたタスクを返します。
+// 完了の中での移動（アーカイブへの整理を含む）は数えません
+func (g *Generator) completed(ctx context.Context, projectPath string, from, to time.Time, warn func(string, ...any)) []CompletedTask {
+	arrivals, err := g.arrivals(ctx, projectPath, path.Dir(grrun.RelDone), from, to)
+	if err != nil {
+		if !errors.Is(err, gitinfo.ErrNotRepository) {
+			warn("failed to read git history of %s: %v", projectPath, err)
+		}
+		return []CompletedTask{}
+	}
+	tasks := []CompletedTask{}
+	for _, a := range arrivals {
+		if path.Dir(a.Path) != grrun.RelDone || !strings.HasSuffix(a.Path, ".md") || strings.HasPrefix(a.From, grrun.RelDone+"/") {
+			continue
+		}
+		tasks = append(tasks, CompletedTask{Name: path.Base(a.Path), Commit: a.Commit, At: a.At})
+	}
+	return tasks
+}
+
+// runs は期間内に終わった gr-run の実行を集計し、異常終了・要確認の実行を返します
+func (g *Generator) runs(projectPath string, from, to time.Time, warn func(string, ...any)) ([]RunProblem, RunTotals) {
+	problems := []RunProblem{}
+	totals := RunTotals{Outcomes: map[string]int{}}
+	if g.runsDir == "" {
+		return problems, totals
+	}
+	records, err := grrun.LoadRunRecords(g.runsDir, projectPath)
+	if err != nil {
+		warn("failed to read run history of %s: %v", projectPath, err)
+		return problems, totals
+	}
+	for _, rec := range records {
+		if rec.EndedAt.Before(from) || !rec.EndedAt.Before(to) {
+			continue
+		}
+		totals.Runs++
+		if rec.Resumed {
+			totals.Resumed++
+		}
+		if rec.EndedAt.After(rec.StartedAt) {
+			totals.DurationSec += int64(rec.EndedAt.Sub(rec.StartedAt).Seconds())
+		}
+		totals.Outcomes[string(rec.Outcome)]++
+		if rec.Outcome == grrun.OutcomeAbnormal || rec.Outcome == grrun.OutcomeNeedsCheck {
+			problems = append(problems, RunProblem{TaskFile: rec.TaskFile, Outcome: string(rec.Outcome), ExitCode: rec.ExitCode, At: rec.EndedAt})
+		}
+	}
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].At.After(problems[j].At) })
+	return problems, totals
+}
+
+// answered は期間内に回答した確認事項をプロジェクトごとに返します
+func (g *Generator) answered(projs []projects.Project, from, to time.Time, warnings *[]string) map[string][]AnsweredQuestion {
+	out := make(map[string][]AnsweredQuestion)
+	hist, err := dashboard.CollectAnswerHistory(dashboard.AnswerHistoryQuery{From: from, To: to, Limit: maxAnswers}, projs, warnings)
+	if err != nil {
+		*warnings = append(*warnings, fmt.Sprintf("failed to collect answers: %v", err))
+		return out
+	}
+	for _, a := range hist.Answers {
+		if !a.AnsweredAt.Before(to) {
+			continue
+		}
+		key := filepath.Clean(a.ProjectPath)
+		out[key] = append(out[key], AnsweredQuestion{
+			PlanPath:   a.PlanPath,
+			Heading:    a.Heading,
+			Answer:     a.Answer,
+			Source:     string(a.Source),
+			AnsweredAt: a.AnsweredAt,
+		})
+	}
+	return out
+}
+
+// samples はダッシュボードの時系列をプロジェクトごとに返します
+func (g *Generator) samples(project string, from, to time.Time, warn func(string, ...any)) map[string][]history.Sample {
+	out := make(map[string][]history.Sample)
+	if g.history == nil {
+		return out
+	}
+	res, err := g.history.Query(history.Query{Project: project, From: from, To: to})
+	if err != nil {
+		warn("failed to read dashboard history: %v", err)
+		return out
+	}
+	for _, s := range res.Samples {
+		key := filepath.Clean(s.Project)
+		out[key] = append(out[key], s)
+	}
+	return out
+}
+
+// fromSamples は1プロジェクトの時系列から、未回答の確認事項の増加数と停滞した運用エントリを求めます
+func fromSamples(samples []history.Sample) (asked int, stale []StaleOps) {
+	stale = []StaleOps{}
+	seen := make(map[string]bool)
+	for i, s := range samples {
+		if i > 0 && s.Unanswered > samples[i-1].Unanswered {
+			asked += s.Unanswered - samples[i-1].Unanswered
+		}
+		for _, op := range s.Ops {
+			key := op.Account + "\x00" + op.Kind
+			if !op.Stale || seen[key] {
+				continue
+			}
+			seen[key] = true
+			stale = append(stale, StaleOps{Account: op.Account, Kind: op.Kind, Since: s.Time})
+		}
+	}
+	return asked, stale
+}
+
+// costs は期間内に始まった会話ログのセッション数とトークン数をプロジェクトごとの実行コストとして返します
+func (g *Generator) costs(ctx context.Context, project string, from, to time.Time, warn func(string, ...any)) map[string]Cost {
+	out := make(map[string]Cost)
+	if g.analytics == nil {
+		return out
+	}
+	rep, err := g.analytics.Report(ctx, analytics.Query{ProjectPath: project, From: from, To: to})
+	if err != nil {
+		warn("failed to compute token usage: %v", err)
+		return out
+	}
+	for _, p := range rep.Projects {
+		out[filepath.Clean(p.Path)] = Cost{Sessions: p.Total.Sessions, Tokens: p.Total.Tokens}
+	}
+	return out
+}
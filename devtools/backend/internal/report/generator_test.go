@@ -0,0 +1,125 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/gitinfo"
+	"ghostrunner/backend/internal/grrun"
+	"ghostrunner/backend/internal/history"
+	"ghostrunner/backend/internal/projects"
+)
+
+// fakeHistory は固定のサンプルを返す history.Store です
+type fakeHistory struct {
+	samples []history.Sample
+}
+
+func (f *fakeHistory) Record(samples []history.Sample) error { return nil }
+
+func (f *fakeHistory) Query(q history.Query) (*history.Result, error) {
+	return &history.Result{Samples: f.samples}, nil
+}
+
+// newTestGenerator は /a（活動あり）と /b（活動なし）を登録した Generator を返します
+func newTestGenerator(t *testing.T, now time.Time) (*Generator, string, string) {
+	t.Helper()
+	projA, projB := t.TempDir(), t.TempDir()
+	runsDir := t.TempDir()
+
+	records := []grrun.RunRecord{
+		{Project: projA, TaskFile: "old.md", StartedAt: now.Add(-48 * time.Hour), EndedAt: now.Add(-47 * time.Hour), Outcome: grrun.OutcomeAbnormal},
+		{Project: projA, TaskFile: "a.md", StartedAt: now.Add(-3 * time.Hour), EndedAt: now.Add(-2 * time.Hour), Outcome: grrun.OutcomeCompleted},
+		{Project: projA, TaskFile: "b.md", Resumed: true, StartedAt: now.Add(-90 * time.Minute), EndedAt: now.Add(-60 * time.Minute), ExitCode: 1, Outcome: grrun.OutcomeNeedsCheck},
+	}
+	for _, rec := range records {
+		if err := grrun.AppendRunRecord(runsDir, rec); err != nil {
+			t.Fatalf("AppendRunRecord: %v", err)
+		}
+	}
+
+	hist := &fakeHistory{samples: []history.Sample{
+		{Time: now.Add(-5 * time.Hour), Project: projA, Unanswered: 1},
+		{Time: now.Add(-4 * time.Hour), Project: projA, Unanswered: 3, Ops: []history.OpsStatus{{Account: "acc", Kind: "x", Stale: true}}},
+		{Time: now.Add(-3 * time.Hour), Project: projA, Unanswered: 0, Ops: []history.OpsStatus{{Account: "acc", Kind: "x", Stale: true}}},
+		{Time: now.Add(-2 * time.Hour), Project: projA, Unanswered: 1},
+	}}
+
+	provider := func() ([]projects.Project, error) {
+		return []projects.Project{{Path: projA, Name: "A"}, {Path: projB, Name: "B"}}, nil
+	}
+	g := NewGenerator(provider, runsDir, hist, nil, func() time.Time { return now })
+	g.arrivals = func(ctx context.Context, dir, pathspec string, from, to time.Time) ([]gitinfo.FileArrival, error) {
+		if pathspec != "開発/実装" {
+			t.Errorf("pathspec = %q", pathspec)
+		}
+		if dir != projA {
+			return nil, gitinfo.ErrNotRepository
+		}
+		return []gitinfo.FileArrival{
+			{Path: "開発/実装/完了/task1.md", From: "開発/実装/実行中/task1.md", Commit: "c1", At: now.Add(-time.Hour)},
+			{Path: "開発/実装/完了/task2.md", Commit: "c2", At: now.Add(-2 * time.Hour)},
+			{Path: "開発/実装/完了/アーカイブ/old.md", From: "開発/実装/完了/old.md", Commit: "c3", At: now.Add(-time.Hour)},
+			{Path: "開発/実装/実行中/task3.md", From: "開発/実装/実装待ち/task3.md", Commit: "c4", At: now.Add(-time.Hour)},
+		}, nil
+	}
+	return g, projA, projB
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	g, projA, _ := newTestGenerator(t, now)
+
+	d, err := g.Generate(context.Background(), Query{Period: PeriodDay})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !d.From.Equal(now.Add(-24*time.Hour)) || !d.To.Equal(now) {
+		t.Errorf("range = %v - %v", d.From, d.To)
+	}
+	if len(d.Projects) != 1 || d.Projects[0].Path != projA {
+		t.Fatalf("projects = %+v, want only A", d.Projects)
+	}
+	p := d.Projects[0]
+
+	if len(p.Completed) != 2 || p.Completed[0].Name != "task1.md" || p.Completed[1].Name != "task2.md" {
+		t.Errorf("completed = %+v", p.Completed)
+	}
+	if p.Runs.Runs != 2 || p.Runs.Resumed != 1 || p.Runs.DurationSec != 5400 {
+		t.Errorf("runs = %+v", p.Runs)
+	}
+	if len(p.Problems) != 1 || p.Problems[0].TaskFile != "b.md" || p.Problems[0].Outcome != "needs_check" {
+		t.Errorf("problems = %+v", p.Problems)
+	}
+	if p.QuestionsAsked != 3 {
+		t.Errorf("questionsAsked = %d, want 3", p.QuestionsAsked)
+	}
+	if len(p.StaleOps) != 1 || !p.StaleOps[0].Since.Equal(now.Add(-4*time.Hour)) {
+		t.Errorf("staleOps = %+v", p.StaleOps)
+	}
+	if d.Totals.Completed != 2 || d.Totals.Problems != 1 || d.Totals.Runs != 2 {
+		t.Errorf("totals = %+v", d.Totals)
+	}
+}
+
+func TestGenerator_Generate_Validation(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	g, _, projB := newTestGenerator(t, now)
+
+	if _, err := g.Generate(context.Background(), Query{Period: "month"}); !errors.Is(err, ErrValidation) {
+		t.Errorf("period month: err = %v, want ErrValidation", err)
+	}
+	if _, err := g.Generate(context.Background(), Query{Project: "/not/registered"}); !errors.Is(err, ErrValidation) {
+		t.Errorf("unregistered project: err = %v, want ErrValidation", err)
+	}
+
+	d, err := g.Generate(context.Background(), Query{Period: PeriodWeek, Project: projB})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(d.Projects) != 0 || !d.From.Equal(now.Add(-7*24*time.Hour)) {
+		t.Errorf("digest = %+v", d)
+	}
+}
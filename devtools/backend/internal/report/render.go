@@ -0,0 +1,232 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+)
+
+// Format はダイジェストの出力形式です
+type Format string
+
+const (
+	// FormatJSON は Digest をそのまま JSON にしたものです
+	FormatJSON Format = "json"
+	// FormatMarkdown は通知本文・開発/資料 への書き出しにも使う Markdown です
+	FormatMarkdown Format = "md"
+	// FormatHTML は外部アセットに依存しない単一 HTML ファイルです
+	FormatHTML Format = "html"
+)
+
+// ParseFormat は文字列を出力形式に変換します。空文字は FormatJSON です
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatJSON:
+		return FormatJSON, nil
+	case FormatMarkdown, FormatHTML:
+		return Format(s), nil
+	}
+	return "", fmt.Errorf("%w: unsupported format: %s", ErrValidation, s)
+}
+
+// ContentType は形式に対応する Content-Type を返します
+func (f Format) ContentType() string {
+	switch f {
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	}
+	return "application/json; charset=utf-8"
+}
+
+// Render は Digest を指定形式で w に書き出します
+func Render(w io.Writer, f Format, d *Digest) error {
+	switch f {
+	case FormatHTML:
+		return htmlTemplate.Execute(w, d)
+	case FormatMarkdown:
+		_, err := io.WriteString(w, Markdown(d))
+		return err
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(d)
+	}
+	return fmt.Errorf("unsupported format: %s", f)
+}
+
+// Title はダイジェストの見出しを返します（例: 日次ダイジェスト 2026-10-19）
+func Title(d *Digest) string {
+	return fmt.Sprintf("%sダイジェスト %s", d.Period.label(), d.To.Local().Format("2006-01-02"))
+}
+
+// Summary は合計件数の1行要約を返します（通知のタイトル補足などに使います）
+func Summary(d *Digest) string {
+	return fmt.Sprintf("完了 %d・問題 %d・質問 %d/回答 %d・実行 %d・停滞 %d",
+		d.Totals.Completed, d.Totals.Problems, d.Totals.QuestionsAsked, d.Totals.QuestionsAnswered, d.Totals.Runs, d.Totals.StaleOps)
+}
+
+// Brief は通知本文向けの短い要約を返します（合計1行と、プロジェクトごとの1行）
+func Brief(d *Digest) string {
+	lines := []string{Summary(d)}
+	for _, p := range d.Projects {
+		lines = append(lines, fmt.Sprintf("%s: 完了 %d・問題 %d・質問 %d/回答 %d・実行 %d",
+			p.Name, len(p.Completed), len(p.Problems), p.QuestionsAsked, len(p.Answered), p.Runs.Runs))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatTime は表示用の時刻文字列を返します（ゼロ値は空）
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// formatDuration は実行時間の合計秒を表示用の文字列にします
+func formatDuration(sec int64) string {
+	return (time.Duration(sec) * time.Second).String()
+}
+
+// outcomeLabel は gr-run の結果分類の表示名を返します
+func outcomeLabel(outcome string) string {
+	switch outcome {
+	case "abnormal":
+		return "異常終了"
+	case "needs_check":
+		return "要確認"
+	}
+	return outcome
+}
+
+// Markdown は Digest を Markdown にします
+func Markdown(d *Digest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", Title(d))
+	fmt.Fprintf(&b, "- 期間: %s 〜 %s\n", formatTime(d.From), formatTime(d.To))
+	fmt.Fprintf(&b, "- 合計: %s\n", Summary(d))
+	fmt.Fprintf(&b, "- コスト（トークン）: 入力 %d・出力 %d（%d セッション）\n\n", d.Totals.Cost.Tokens.Input, d.Totals.Cost.Tokens.Output, d.Totals.Cost.Sessions)
+
+	if len(d.Projects) == 0 {
+		b.WriteString("期間内の活動はありません。\n")
+	}
+	for _, p := range d.Projects {
+		writeProjectMarkdown(&b, p)
+	}
+	if len(d.Warnings) > 0 {
+		b.WriteString("## 警告\n\n")
+		for _, w := range d.Warnings {
+			fmt.Fprintf(&b, "- %s\n", w)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// writeProjectMarkdown は1プロジェクト分の Markdown を書き出します
+func writeProjectMarkdown(b *strings.Builder, p ProjectDigest) {
+	fmt.Fprintf(b, "## %s\n\n", p.Name)
+	if len(p.Completed) > 0 {
+		fmt.Fprintf(b, "### 完了したタスク（%d）\n\n", len(p.Completed))
+		for _, t := range p.Completed {
+			fmt.Fprintf(b, "- %s（%s）\n", t.Name, formatTime(t.At))
+		}
+		b.WriteString("\n")
+	}
+	if len(p.Problems) > 0 {
+		fmt.Fprintf(b, "### 異常終了・要確認（%d）\n\n", len(p.Problems))
+		for _, r := range p.Problems {
+			fmt.Fprintf(b, "- %s: %s（exit %d, %s）\n", outcomeLabel(r.Outcome), r.TaskFile, r.ExitCode, formatTime(r.At))
+		}
+		b.WriteString("\n")
+	}
+	if p.QuestionsAsked > 0 || len(p.Answered) > 0 {
+		fmt.Fprintf(b, "### 確認事項（質問 %d・回答 %d）\n\n", p.QuestionsAsked, len(p.Answered))
+		for _, a := range p.Answered {
+			fmt.Fprintf(b, "- %s: %s → %s\n", a.PlanPath, a.Heading, a.Answer)
+		}
+		if len(p.Answered) > 0 {
+			b.WriteString("\n")
+		}
+	}
+	if p.Runs.Runs > 0 || p.Cost.Sessions > 0 {
+		b.WriteString("### 実行\n\n")
+		if p.Runs.Runs > 0 {
+			fmt.Fprintf(b, "- gr-run: %d 回（再開 %d）、合計 %s\n", p.Runs.Runs, p.Runs.Resumed, formatDuration(p.Runs.DurationSec))
+		}
+		if p.Cost.Sessions > 0 {
+			fmt.Fprintf(b, "- コスト（トークン）: 入力 %d・出力 %d（%d セッション）\n", p.Cost.Tokens.Input, p.Cost.Tokens.Output, p.Cost.Sessions)
+		}
+		b.WriteString("\n")
+	}
+	if len(p.StaleOps) > 0 {
+		fmt.Fprintf(b, "### 停滞した運用（%d）\n\n", len(p.StaleOps))
+		for _, s := range p.StaleOps {
+			fmt.Fprintf(b, "- %s / %s（%s〜）\n", s.Account, s.Kind, formatTime(s.Since))
+		}
+		b.WriteString("\n")
+	}
+}
+
+// htmlTemplate は単一ファイルで完結する HTML のテンプレートです
+var htmlTemplate = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"title":          Title,
+	"summary":        Summary,
+	"formatTime":     formatTime,
+	"formatDuration": formatDuration,
+	"outcomeLabel":   outcomeLabel,
+}).Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{title .}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Hiragino Sans", "Noto Sans JP", sans-serif; max-width: 960px; margin: 2rem auto; padding: 0 1rem; color: #1f2328; line-height: 1.6; }
+header { border-bottom: 1px solid #d0d7de; margin-bottom: 1.5rem; }
+dl.meta { display: grid; grid-template-columns: max-content 1fr; gap: .25rem 1rem; font-size: .9rem; }
+dl.meta dt { color: #656d76; }
+section.project { border: 1px solid #d0d7de; border-radius: 8px; margin: 1rem 0; padding: .5rem 1rem; }
+h3 { font-size: 1rem; margin-bottom: .25rem; }
+.problem { color: #cf222e; }
+.muted { color: #656d76; font-size: .85rem; }
+.warn { background: #fff8c5; padding: .5rem 1rem; border-radius: 6px; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{title .}}</h1>
+<dl class="meta">
+<dt>期間</dt><dd>{{formatTime .From}} 〜 {{formatTime .To}}</dd>
+<dt>合計</dt><dd>{{summary .}}</dd>
+<dt>コスト（トークン）</dt><dd>入力 {{.Totals.Cost.Tokens.Input}}・出力 {{.Totals.Cost.Tokens.Output}}（{{.Totals.Cost.Sessions}} セッション）</dd>
+</dl>
+</header>
+{{if not .Projects}}<p>期間内の活動はありません。</p>{{end}}
+{{range .Projects}}
+<section class="project">
+<h2>{{.Name}}</h2>
+{{if .Completed}}<h3>完了したタスク（{{len .Completed}}）</h3>
+<ul>{{range .Completed}}<li>{{.Name}} <span class="muted">{{formatTime .At}}</span></li>{{end}}</ul>{{end}}
+{{if .Problems}}<h3 class="problem">異常終了・要確認（{{len .Problems}}）</h3>
+<ul>{{range .Problems}}<li>{{outcomeLabel .Outcome}}: {{.TaskFile}} <span class="muted">exit {{.ExitCode}}, {{formatTime .At}}</span></li>{{end}}</ul>{{end}}
+{{if or .QuestionsAsked .Answered}}<h3>確認事項（質問 {{.QuestionsAsked}}・回答 {{len .Answered}}）</h3>
+{{if .Answered}}<ul>{{range .Answered}}<li>{{.PlanPath}}: {{.Heading}} → {{.Answer}}</li>{{end}}</ul>{{end}}{{end}}
+{{if or .Runs.Runs .Cost.Sessions}}<h3>実行</h3>
+<ul>
+{{if .Runs.Runs}}<li>gr-run: {{.Runs.Runs}} 回（再開 {{.Runs.Resumed}}）、合計 {{formatDuration .Runs.DurationSec}}</li>{{end}}
+{{if .Cost.Sessions}}<li>コスト（トークン）: 入力 {{.Cost.Tokens.Input}}・出力 {{.Cost.Tokens.Output}}（{{.Cost.Sessions}} セッション）</li>{{end}}
+</ul>{{end}}
+{{if .StaleOps}}<h3>停滞した運用（{{len .StaleOps}}）</h3>
+<ul>{{range .StaleOps}}<li>{{.Account}} / {{.Kind}} <span class="muted">{{formatTime .Since}}〜</span></li>{{end}}</ul>{{end}}
+</section>
+{{end}}
+{{if .Warnings}}<section class="warn"><h2>警告</h2><ul>{{range .Warnings}}<li>{{.}}</li>{{end}}</ul></section>{{end}}
+</body>
+</html>
+`))
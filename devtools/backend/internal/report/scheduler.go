@@ -0,0 +1,180 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"ghostrunner/backend/internal/notify"
+)
+
+// schedulerInterval は配信時刻の確認間隔です
+const schedulerInterval = time.Minute
+
+// RelDocs はダイジェストを書き出すディレクトリ（プロジェクトルートからの相対パス）です
+const RelDocs = "開発/資料"
+
+// Schedule はダイジェストの配信予定1件です
+type Schedule struct {
+	Period Period
+	// Clock は配信時刻（"HH:MM"、ローカル時刻）です
+	Clock string
+	// Weekly が true の場合は Weekday の Clock にだけ配信します
+	Weekly  bool
+	Weekday time.Weekday
+}
+
+// due は now がこの配信予定の時刻かを返します
+func (s Schedule) due(now time.Time) bool {
+	if s.Weekly && now.Weekday() != s.Weekday {
+		return false
+	}
+	return now.Format("15:04") == s.Clock
+}
+
+// SchedulerOption は Scheduler の任意設定です
+type SchedulerOption func(*Scheduler)
+
+// WithWriteDocs は配信時に各プロジェクトの 開発/資料 へ Markdown を書き出します
+func WithWriteDocs() SchedulerOption {
+	return func(s *Scheduler) {
+		s.writeDocs = true
+	}
+}
+
+// Scheduler は配信予定の時刻にダイジェストを生成し、通知で送るバックグラウンドジョブです。
+// 1分ごとに時刻を確認し、同じ予定は同じ日に1回だけ送ります。
+// 再起動で配信時刻を過ぎた分は送り直しません。
+type Scheduler struct {
+	generator *Generator
+	notifier  notify.Notifier
+	schedules []Schedule
+	writeDocs bool
+	now       func() time.Time
+
+	mu    sync.Mutex
+	fired map[string]bool // key: 期間 + 日付
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewScheduler は新しい Scheduler を生成します。now が nil の場合は time.Now を使います
+func NewScheduler(generator *Generator, notifier notify.Notifier, schedules []Schedule, now func() time.Time, opts ...SchedulerOption) *Scheduler {
+	if now == nil {
+		now = time.Now
+	}
+	s := &Scheduler{
+		generator: generator,
+		notifier:  notifier,
+		schedules: schedules,
+		now:       now,
+		fired:     make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Start は配信を開始します。ctx のキャンセルまたは Stop で終了します
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(schedulerInterval)
+		defer ticker.Stop()
+
+		log.Printf("[DigestScheduler] started: schedules=%d, writeDocs=%t", len(s.schedules), s.writeDocs)
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("[DigestScheduler] stopped")
+				return
+			case <-ticker.C:
+				s.Tick(ctx, s.now())
+			}
+		}
+	}()
+}
+
+// Stop は配信を停止し、実行中の配信が終わるまで待機します
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+// Tick は now が配信時刻の予定についてダイジェストを生成して送ります
+func (s *Scheduler) Tick(ctx context.Context, now time.Time) {
+	for _, sch := range s.schedules {
+		if !sch.due(now) || !s.claim(sch.Period, now) {
+			continue
+		}
+		if err := s.Deliver(ctx, sch.Period, now); err != nil {
+			log.Printf("[DigestScheduler] deliver failed: period=%s, error=%v", sch.Period, err)
+		}
+	}
+}
+
+// claim はその日の配信が未送信なら送信済みにして true を返します
+func (s *Scheduler) claim(period Period, now time.Time) bool {
+	key := string(period) + " " + now.Format("2006-01-02")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fired[key] {
+		return false
+	}
+	s.fired[key] = true
+	return true
+}
+
+// Deliver は to までのダイジェストを生成し、通知と（有効なら）開発/資料 への書き出しを行います
+func (s *Scheduler) Deliver(ctx context.Context, period Period, to time.Time) error {
+	d, err := s.generator.Generate(ctx, Query{Period: period, To: to})
+	if err != nil {
+		return fmt.Errorf("failed to generate digest: %w", err)
+	}
+	log.Printf("[DigestScheduler] generated: period=%s, projects=%d, warnings=%d", period, len(d.Projects), len(d.Warnings))
+
+	notify.Send(s.notifier, notify.Message{
+		Event:    notify.EventReportDigest,
+		Title:    Title(d),
+		Body:     Brief(d),
+		Priority: notify.PriorityLow,
+	})
+
+	if !s.writeDocs {
+		return nil
+	}
+	for _, p := range d.Projects {
+		path, err := WriteDoc(d.project(p), p.Path)
+		if err != nil {
+			log.Printf("[DigestScheduler] write doc failed: project=%s, error=%v", p.Path, err)
+			continue
+		}
+		log.Printf("[DigestScheduler] wrote doc: path=%s", path)
+	}
+	return nil
+}
+
+// WriteDoc は d を projectPath の 開発/資料/digest_<期間>_<日付>.md に書き出し、書き出したパスを返します
+func WriteDoc(d *Digest, projectPath string) (string, error) {
+	dir := filepath.Join(projectPath, filepath.FromSlash(RelDocs))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create docs dir: %w", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("digest_%s_%s.md", d.Period, d.To.Local().Format("2006-01-02")))
+	if err := os.WriteFile(path, []byte(Markdown(d)), 0644); err != nil {
+		return "", fmt.Errorf("failed to write digest: %w", err)
+	}
+	return path, nil
+}
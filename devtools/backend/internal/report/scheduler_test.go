@@ -0,0 +1,60 @@
+package report
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordingNotifier は受け取った通知を記録します
+type recordingNotifier struct {
+	titles []string
+	bodies []string
+}
+
+func (r *recordingNotifier) Notify(title, message string) {
+	r.titles = append(r.titles, title)
+	r.bodies = append(r.bodies, message)
+}
+
+func (r *recordingNotifier) NotifyError(title, message string) { r.Notify(title, message) }
+
+func TestScheduler_Tick(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local) // 月曜
+	g, projA, projB := newTestGenerator(t, now)
+	n := &recordingNotifier{}
+	s := NewScheduler(g, n, []Schedule{
+		{Period: PeriodDay, Clock: "09:00"},
+		{Period: PeriodWeek, Clock: "09:00", Weekly: true, Weekday: time.Tuesday},
+	}, nil, WithWriteDocs())
+
+	s.Tick(context.Background(), now.Add(-time.Minute))
+	if len(n.titles) != 0 {
+		t.Fatalf("sent before schedule: %v", n.titles)
+	}
+
+	s.Tick(context.Background(), now)
+	s.Tick(context.Background(), now.Add(30*time.Second))
+	if len(n.titles) != 1 {
+		t.Fatalf("titles = %v, want 1 daily digest", n.titles)
+	}
+	if n.titles[0] != "日次ダイジェスト 2026-10-19" || !strings.Contains(n.bodies[0], "A: 完了 2・問題 1") {
+		t.Errorf("notification = %q / %q", n.titles[0], n.bodies[0])
+	}
+
+	doc, err := os.ReadFile(filepath.Join(projA, "開発", "資料", "digest_day_2026-10-19.md"))
+	if err != nil {
+		t.Fatalf("read doc: %v", err)
+	}
+	for _, want := range []string{"# 日次ダイジェスト 2026-10-19", "## A", "- task1.md", "- 要確認: b.md"} {
+		if !strings.Contains(string(doc), want) {
+			t.Errorf("doc missing %q:\n%s", want, doc)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(projB, "開発", "資料")); !os.IsNotExist(err) {
+		t.Errorf("doc written for project without activity: %v", err)
+	}
+}
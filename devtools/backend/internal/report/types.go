@@ -0,0 +1,177 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"ghostrunner/backend/internal/analytics"
+)
+
+// ErrValidation は照会条件が不正な場合のエラーです（不正な期間・未登録プロジェクト）
+var ErrValidation = errors.New("validation error")
+
+// Period はダイジェストの期間です
+type Period string
+
+const (
+	// PeriodDay は直近24時間です
+	PeriodDay Period = "day"
+	// PeriodWeek は直近7日です
+	PeriodWeek Period = "week"
+)
+
+// ParsePeriod は文字列を期間に変換します。空文字は PeriodDay です
+func ParsePeriod(s string) (Period, error) {
+	switch Period(s) {
+	case "", PeriodDay:
+		return PeriodDay, nil
+	case PeriodWeek:
+		return PeriodWeek, nil
+	}
+	return "", fmt.Errorf("%w: period must be day or week: %s", ErrValidation, s)
+}
+
+// span は期間の長さを返します
+func (p Period) span() time.Duration {
+	if p == PeriodWeek {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// label は期間の表示名を返します
+func (p Period) label() string {
+	if p == PeriodWeek {
+		return "週次"
+	}
+	return "日次"
+}
+
+// Query はダイジェストの生成条件です
+type Query struct {
+	Period Period
+	// Project はプロジェクトパス（空なら全登録プロジェクト）
+	Project string
+	// To は期間の終わり（ゼロ値なら現在時刻）。期間は [To - Period, To) です
+	To time.Time
+}
+
+// Digest はダイジェスト1件です
+type Digest struct {
+	Period      Period          `json:"period"`
+	From        time.Time       `json:"from"`
+	To          time.Time       `json:"to"`
+	Totals      Totals          `json:"totals"`
+	Projects    []ProjectDigest `json:"projects"`
+	GeneratedAt time.Time       `json:"generatedAt"`
+	Warnings    []string        `json:"warnings,omitempty"`
+}
+
+// project は1プロジェクトだけを含むダイジェストを返します（開発/資料 への書き出し用）
+func (d *Digest) project(p ProjectDigest) *Digest {
+	out := &Digest{Period: d.Period, From: d.From, To: d.To, Projects: []ProjectDigest{p}, GeneratedAt: d.GeneratedAt}
+	out.Totals.add(p)
+	return out
+}
+
+// Totals は全プロジェクトの件数の合計です
+type Totals struct {
+	Completed         int  `json:"completed"`
+	Problems          int  `json:"problems"`
+	QuestionsAsked    int  `json:"questionsAsked"`
+	QuestionsAnswered int  `json:"questionsAnswered"`
+	Runs              int  `json:"runs"`
+	StaleOps          int  `json:"staleOps"`
+	Cost              Cost `json:"cost"`
+}
+
+// add は1プロジェクト分の件数を合計に加えます
+func (t *Totals) add(p ProjectDigest) {
+	t.Completed += len(p.Completed)
+	t.Problems += len(p.Problems)
+	t.QuestionsAsked += p.QuestionsAsked
+	t.QuestionsAnswered += len(p.Answered)
+	t.Runs += p.Runs.Runs
+	t.StaleOps += len(p.StaleOps)
+	t.Cost.Sessions += p.Cost.Sessions
+	t.Cost.Tokens.Input += p.Cost.Tokens.Input
+	t.Cost.Tokens.Output += p.Cost.Tokens.Output
+	t.Cost.Tokens.CacheCreation += p.Cost.Tokens.CacheCreation
+	t.Cost.Tokens.CacheRead += p.Cost.Tokens.CacheRead
+}
+
+// ProjectDigest は1プロジェクトのダイジェストです
+type ProjectDigest struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Completed は期間内のコミットで 開発/実装/完了 へ移動・追加されたタスクです（新しい順）
+	Completed []CompletedTask `json:"completed"`
+	// Problems は期間内に終わった gr-run の実行のうち異常終了・要確認のものです（新しい順）
+	Problems []RunProblem `json:"problems"`
+	// QuestionsAsked は期間内に増えた未回答の確認事項の数です（ダッシュボードの時系列の増加分）
+	QuestionsAsked int `json:"questionsAsked"`
+	// Answered は期間内に回答した確認事項です（新しい順）
+	Answered []AnsweredQuestion `json:"answered"`
+	Runs     RunTotals          `json:"runs"`
+	Cost     Cost               `json:"cost"`
+	// StaleOps は期間内に状態ファイルの更新が止まった運用エントリです
+	StaleOps []StaleOps `json:"staleOps"`
+}
+
+// empty は報告する内容が無いかを返します
+func (p ProjectDigest) empty() bool {
+	return len(p.Completed) == 0 && len(p.Problems) == 0 && p.QuestionsAsked == 0 && len(p.Answered) == 0 &&
+		p.Runs.Runs == 0 && p.Cost.Sessions == 0 && len(p.StaleOps) == 0
+}
+
+// CompletedTask は完了したタスク1件です
+type CompletedTask struct {
+	// Name はタスクファイル名です
+	Name string `json:"name"`
+	// Commit は完了へ移したコミットです
+	Commit string    `json:"commit"`
+	At     time.Time `json:"at"`
+}
+
+// RunProblem は異常終了・要確認で終わった gr-run の実行1件です
+type RunProblem struct {
+	TaskFile string    `json:"taskFile"`
+	Outcome  string    `json:"outcome"`
+	ExitCode int       `json:"exitCode"`
+	At       time.Time `json:"at"`
+}
+
+// AnsweredQuestion は回答した確認事項1件です
+type AnsweredQuestion struct {
+	PlanPath   string    `json:"planPath"`
+	Heading    string    `json:"heading"`
+	Answer     string    `json:"answer"`
+	Source     string    `json:"source,omitempty"`
+	AnsweredAt time.Time `json:"answeredAt"`
+}
+
+// RunTotals は期間内に終わった gr-run の実行の集計です
+type RunTotals struct {
+	Runs    int `json:"runs"`
+	Resumed int `json:"resumed"`
+	// DurationSec は実行時間の合計秒です
+	DurationSec int64 `json:"durationSec"`
+	// Outcomes は結果分類ごとの件数です
+	Outcomes map[string]int `json:"outcomes"`
+}
+
+// Cost は実行コストです。金額は記録されていないため、期間内に始まった会話ログのセッション数と
+// トークン数で表します（analytics の集計）
+type Cost struct {
+	Sessions int                  `json:"sessions"`
+	Tokens   analytics.TokenUsage `json:"tokens"`
+}
+
+// StaleOps は期間内に停滞した運用エントリ1件です
+type StaleOps struct {
+	Account string `json:"account"`
+	Kind    string `json:"kind"`
+	// Since は期間内で最初に停滞を記録した時刻です
+	Since time.Time `json:"since"`
+}
@@ -0,0 +1,27 @@
+// Package search は全プロジェクトの会話ログと 開発/ 配下の Markdown を横断する全文検索を提供する。
+//
+// # 概要
+//
+// 「決済 webhook の話をしたのはどのセッションか」をプロジェクト横断で探せるよう、
+// ~/.claude/projects/ 配下の会話ログ JSONL（プロンプトと assistant の本文）と、登録プロジェクトの
+// 開発/<handler.DevFolders> 配下の .md をローカルの転置索引に載せる。索引は pure Go の gob 形式で
+// ~/.ghostrunner/index に永続化し、再起動後は差分だけを再索引する。GET /api/search の実体。
+//
+// # 主要な型・関数
+//
+//   - Service / NewService: 索引の読み込み・インクリメンタル更新（Refresh / Start / Stop）・検索（Search）
+//   - Query / Result / Hit: 検索条件と、スニペット・リンク付きの検索結果
+//   - tokenize: 英数字は小文字化した単語、CJK は文字 bigram に分割する索引語化
+//   - invertedIndex: 索引語→(ドキュメントID→出現回数) の転置索引と BM25 スコアリング
+//
+// # 設計方針
+//
+//   - 形態素解析の辞書を持たず、CJK は bigram で部分一致を成立させる（依存ゼロ・pure Go）
+//   - 検索は空白区切りの全語を含むドキュメントに絞る AND 検索
+//   - インクリメンタル更新: ドキュメントの mtime + size が索引時と同じなら再索引しない。
+//     消えたファイル・登録解除されたプロジェクトのドキュメントは次回 Refresh で索引から外す
+//   - 会話ログの帰属は transcript と同じく実 cwd + idle.MatchProject（C2）
+//   - tool_result と thinking は索引しない（ファイル全文やコマンド出力で索引が肥大化しノイズになるため）
+//   - スニペットは検索時に元ファイルを読み直して作る（本文を索引に持たず索引サイズを抑える）
+//   - 永続化ファイルが壊れている・形式が古い場合は空から再構築する（索引は再生成可能なキャッシュ）
+package search
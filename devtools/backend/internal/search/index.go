@@ -0,0 +1,235 @@
+package search
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// indexFormatVersion は永続化形式のバージョンです。不一致なら読み捨てて再構築します。
+const indexFormatVersion = 1
+
+// indexFileName は索引ディレクトリ内の永続化ファイル名です。
+const indexFileName = "index.gob"
+
+// docMeta は索引済みドキュメント1件のメタ情報です。
+// ModTime + Size が変わらない限り再索引しません（インクリメンタル更新の判定キー）。
+type docMeta struct {
+	Kind        Kind
+	ProjectPath string
+	Path        string
+	RelPath     string
+	SessionID   string
+	Title       string
+	ModTime     time.Time
+	Size        int64
+	// Length は索引語数（スコアの文書長正規化に使用）です。
+	Length int
+	// Terms はこのドキュメントが持つ索引語の一覧です（削除時に postings から外すため保持）。
+	Terms []string
+}
+
+// invertedIndex は索引語→(ドキュメントID→出現回数) の転置索引です。
+// 並行制御は呼び出し側（indexer）が行います。
+type invertedIndex struct {
+	Docs     map[uint32]*docMeta
+	ByPath   map[string]uint32
+	Postings map[string]map[uint32]uint32
+	NextID   uint32
+	// TotalLength は全ドキュメントの Length 合計です（平均文書長の算出用）。
+	TotalLength int64
+}
+
+// persistedIndex は永続化ファイルのトップレベル構造です。
+type persistedIndex struct {
+	Version int
+	Index   *invertedIndex
+}
+
+// scoredDoc は検索でヒットしたドキュメントとスコアです。
+type scoredDoc struct {
+	meta  *docMeta
+	score float64
+}
+
+// newInvertedIndex は空の転置索引を生成します。
+func newInvertedIndex() *invertedIndex {
+	return &invertedIndex{
+		Docs:     make(map[uint32]*docMeta),
+		ByPath:   make(map[string]uint32),
+		Postings: make(map[string]map[uint32]uint32),
+	}
+}
+
+// lookup は path の索引済みメタ情報を返します。
+func (ix *invertedIndex) lookup(path string) (*docMeta, bool) {
+	id, ok := ix.ByPath[path]
+	if !ok {
+		return nil, false
+	}
+	return ix.Docs[id], true
+}
+
+// put は meta のドキュメントを索引に追加します。同じ path の既存ドキュメントは置き換えます。
+func (ix *invertedIndex) put(meta docMeta, tf map[string]uint32, length int) {
+	ix.remove(meta.Path)
+
+	id := ix.NextID
+	ix.NextID++
+
+	meta.Length = length
+	meta.Terms = make([]string, 0, len(tf))
+	for term, n := range tf {
+		p, ok := ix.Postings[term]
+		if !ok {
+			p = make(map[uint32]uint32)
+			ix.Postings[term] = p
+		}
+		p[id] = n
+		meta.Terms = append(meta.Terms, term)
+	}
+	ix.Docs[id] = &meta
+	ix.ByPath[meta.Path] = id
+	ix.TotalLength += int64(length)
+}
+
+// remove は path のドキュメントを索引から外します。未索引なら何もしません。
+func (ix *invertedIndex) remove(path string) {
+	id, ok := ix.ByPath[path]
+	if !ok {
+		return
+	}
+	meta := ix.Docs[id]
+	for _, term := range meta.Terms {
+		p := ix.Postings[term]
+		delete(p, id)
+		if len(p) == 0 {
+			delete(ix.Postings, term)
+		}
+	}
+	ix.TotalLength -= int64(meta.Length)
+	delete(ix.Docs, id)
+	delete(ix.ByPath, path)
+}
+
+// search は terms を全て含むドキュメントを BM25 でスコア付けし、スコア降順で返します。
+// filter が false を返すドキュメントは除外します。
+func (ix *invertedIndex) search(terms []string, filter func(*docMeta) bool) []scoredDoc {
+	if len(terms) == 0 || len(ix.Docs) == 0 {
+		return nil
+	}
+
+	// 出現文書数の少ない語から積集合を取る
+	lists := make([]map[uint32]uint32, 0, len(terms))
+	for _, t := range terms {
+		p, ok := ix.Postings[t]
+		if !ok {
+			return nil
+		}
+		lists = append(lists, p)
+	}
+	sort.Slice(lists, func(i, j int) bool { return len(lists[i]) < len(lists[j]) })
+
+	const k1, b = 1.2, 0.75
+	n := float64(len(ix.Docs))
+	avgLen := float64(ix.TotalLength) / n
+	if avgLen <= 0 {
+		avgLen = 1
+	}
+
+	out := make([]scoredDoc, 0)
+	for id := range lists[0] {
+		meta := ix.Docs[id]
+		if filter != nil && !filter(meta) {
+			continue
+		}
+		score := 0.0
+		matched := true
+		for _, p := range lists {
+			tf, ok := p[id]
+			if !ok {
+				matched = false
+				break
+			}
+			idf := math.Log(1 + (n-float64(len(p))+0.5)/(float64(len(p))+0.5))
+			f := float64(tf)
+			score += idf * f * (k1 + 1) / (f + k1*(1-b+b*float64(meta.Length)/avgLen))
+		}
+		if matched {
+			out = append(out, scoredDoc{meta: meta, score: score})
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].score != out[j].score {
+			return out[i].score > out[j].score
+		}
+		return out[i].meta.ModTime.After(out[j].meta.ModTime)
+	})
+	return out
+}
+
+// loadIndex は dir の永続化ファイルから索引を読み込みます。
+// ファイルが無い場合は空の索引を返します。形式不一致・破損はエラーを返します。
+func loadIndex(dir string) (*invertedIndex, error) {
+	f, err := os.Open(filepath.Join(dir, indexFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return newInvertedIndex(), nil
+		}
+		return nil, fmt.Errorf("failed to open index: %w", err)
+	}
+	defer f.Close()
+
+	var p persistedIndex
+	if err := gob.NewDecoder(f).Decode(&p); err != nil {
+		return nil, fmt.Errorf("failed to decode index: %w", err)
+	}
+	if p.Version != indexFormatVersion || p.Index == nil {
+		return nil, fmt.Errorf("unsupported index version: %d", p.Version)
+	}
+	ix := p.Index
+	if ix.Docs == nil {
+		ix.Docs = make(map[uint32]*docMeta)
+	}
+	if ix.ByPath == nil {
+		ix.ByPath = make(map[string]uint32)
+	}
+	if ix.Postings == nil {
+		ix.Postings = make(map[string]map[uint32]uint32)
+	}
+	return ix, nil
+}
+
+// save は索引を dir の永続化ファイルへ write-to-temp + rename で書き出します。
+func (ix *invertedIndex) save(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create index dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".index-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if err := gob.NewEncoder(tmp).Encode(persistedIndex{Version: indexFormatVersion, Index: ix}); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to encode index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, filepath.Join(dir, indexFileName)); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename index: %w", err)
+	}
+	return nil
+}
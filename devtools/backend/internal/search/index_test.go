@@ -0,0 +1,114 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"英数字は小文字化した単語", "Payment Webhook_v2!", []string{"payment", "webhook_v2"}},
+		{"CJKはbigram", "決済処理", []string{"決済", "済処", "処理"}},
+		{"CJK1文字はそのまま", "A案", []string{"a", "案"}},
+		{"混在", "Stripeの決済", []string{"stripe", "の決", "決済"}},
+		{"記号のみ", "--- ***", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tokenize(tt.in)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenize(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvertedIndex_PutSearchRemove(t *testing.T) {
+	ix := newInvertedIndex()
+	add := func(path, text string) {
+		tf, n := termFrequencies(text)
+		ix.put(docMeta{Kind: KindDoc, Path: path, ModTime: time.Unix(0, 0)}, tf, n)
+	}
+	add("/a.md", "決済 webhook の署名検証")
+	add("/b.md", "webhook の再送設定")
+	add("/c.md", "ログ設計")
+
+	got := ix.search(queryTerms("webhook 決済"), nil)
+	if len(got) != 1 || got[0].meta.Path != "/a.md" {
+		t.Fatalf("AND 検索で /a.md のみヒットするはず: %+v", got)
+	}
+
+	got = ix.search(queryTerms("webhook"), func(m *docMeta) bool { return m.Path != "/a.md" })
+	if len(got) != 1 || got[0].meta.Path != "/b.md" {
+		t.Fatalf("filter が効いていない: %+v", got)
+	}
+
+	// 置換: 同じ path を put し直すと古い索引語は外れる
+	add("/a.md", "ログ")
+	if got := ix.search(queryTerms("決済"), nil); len(got) != 0 {
+		t.Errorf("置換後も旧索引語でヒットする: %+v", got)
+	}
+
+	ix.remove("/c.md")
+	ix.remove("/c.md")
+	if got := ix.search(queryTerms("ログ"), nil); len(got) != 1 || got[0].meta.Path != "/a.md" {
+		t.Errorf("remove 後の検索結果が不正: %+v", got)
+	}
+	if _, ok := ix.Postings["設計"]; ok {
+		t.Error("出現文書0件の索引語が残っている")
+	}
+}
+
+func TestInvertedIndex_SaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	ix := newInvertedIndex()
+	tf, n := termFrequencies("決済 webhook")
+	ix.put(docMeta{Kind: KindSession, Path: "/s.jsonl", SessionID: "s"}, tf, n)
+	if err := ix.save(dir); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+
+	loaded, err := loadIndex(dir)
+	if err != nil {
+		t.Fatalf("loadIndex error: %v", err)
+	}
+	if got := loaded.search(queryTerms("webhook"), nil); len(got) != 1 || got[0].meta.SessionID != "s" {
+		t.Errorf("永続化後の検索結果が不正: %+v", got)
+	}
+
+	t.Run("ファイル無しは空索引", func(t *testing.T) {
+		ix, err := loadIndex(t.TempDir())
+		if err != nil || len(ix.Docs) != 0 {
+			t.Errorf("loadIndex = %v, %v", ix, err)
+		}
+	})
+
+	t.Run("破損はエラー", func(t *testing.T) {
+		bad := t.TempDir()
+		if err := os.WriteFile(filepath.Join(bad, indexFileName), []byte("garbage"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadIndex(bad); err == nil {
+			t.Error("破損ファイルでエラーにならない")
+		}
+	})
+}
+
+func TestBuildSnippet(t *testing.T) {
+	text := "前置き。ここで Payment Webhook の署名を検証する。後書き"
+	got := buildSnippet(text, "payment webhook", queryTerms("payment webhook"))
+	if !strings.Contains(got, "Payment Webhook") {
+		t.Errorf("snippet = %q", got)
+	}
+	if buildSnippet("", "x", nil) != "" {
+		t.Error("空本文は空スニペット")
+	}
+}
@@ -0,0 +1,509 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"ghostrunner/backend/internal/idle"
+	"ghostrunner/backend/internal/projects"
+	"ghostrunner/backend/internal/transcript"
+)
+
+// refreshInterval は索引のインクリメンタル更新間隔です。
+const refreshInterval = 2 * time.Minute
+
+// snippetRadius はスニペットとしてヒット位置の前後に切り出す文字数です。
+const snippetRadius = 60
+
+// titleMaxRunes はセッションのタイトル（最初のプロンプト冒頭）の最大文字数です。
+const titleMaxRunes = 60
+
+// ErrValidation は検索条件が不正な場合のエラーです。
+var ErrValidation = errors.New("validation error")
+
+// Service は会話ログと 開発/ 配下の Markdown を横断する全文検索のインターフェースです。
+type Service interface {
+	// Search は索引を検索し、スニペット付きの結果を返します。
+	Search(ctx context.Context, q Query) (*Result, error)
+	// Refresh は変更のあったドキュメントだけを再索引します（インクリメンタル更新）。
+	Refresh(ctx context.Context) error
+	// Start は定期的なインクリメンタル更新を開始します（初回更新を即時に行う）。
+	Start(ctx context.Context)
+	// Stop は定期更新を停止し、実行中の更新の完了を待ちます。
+	Stop()
+}
+
+// source は索引対象の 開発/ 配下ドキュメント1件の所在です。
+type source struct {
+	projectPath string
+	path        string
+	relPath     string
+	modTime     time.Time
+	size        int64
+}
+
+// serviceImpl は Service の実装です。
+type serviceImpl struct {
+	indexDir         string
+	homeDir          string
+	projectsProvider func() ([]projects.Project, error)
+	devFolders       []string
+	now              func() time.Time
+
+	// mu は index と indexedAt を保護します。
+	mu        sync.RWMutex
+	index     *invertedIndex
+	indexedAt time.Time
+
+	// refreshMu は Refresh の多重実行を防ぎます。
+	refreshMu sync.Mutex
+	// unmatched は登録プロジェクトに帰属しなかった会話ログの mtime です（mtime 不変なら再読みしない）。
+	unmatched map[string]time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewService は全文検索サービスを生成します。
+// indexDir は索引の永続化先（~/.ghostrunner/index）、devFolders は 開発/ 配下の対象フォルダ
+// （handler.DevFolders と同じ一覧）です。永続化済みの索引が読めない場合は空から再構築します。
+func NewService(indexDir, homeDir string, projectsProvider func() ([]projects.Project, error), devFolders []string, now func() time.Time) Service {
+	ix, err := loadIndex(indexDir)
+	if err != nil {
+		log.Printf("[SearchService] failed to load index, rebuilding: dir=%s, error=%v", indexDir, err)
+		ix = newInvertedIndex()
+	}
+	return &serviceImpl{
+		indexDir:         indexDir,
+		homeDir:          homeDir,
+		projectsProvider: projectsProvider,
+		devFolders:       devFolders,
+		now:              now,
+		index:            ix,
+		unmatched:        make(map[string]time.Time),
+	}
+}
+
+// Start は定期的なインクリメンタル更新を開始します。
+func (s *serviceImpl) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("[SearchService] Refresh failed: error=%v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	log.Printf("[SearchService] Started: interval=%s, dir=%s", refreshInterval, s.indexDir)
+}
+
+// Stop は定期更新を停止します。
+func (s *serviceImpl) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	log.Println("[SearchService] Stopped")
+}
+
+// Refresh は変更のあったドキュメントだけを再索引し、消えたドキュメントを索引から外します。
+func (s *serviceImpl) Refresh(ctx context.Context) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	projs, err := s.projectsProvider()
+	if err != nil {
+		return fmt.Errorf("failed to load projects: %w", err)
+	}
+
+	sources := s.collectDocs(projs)
+	sessions, err := transcript.ListSessions(s.homeDir, projs)
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	alive := make(map[string]struct{}, len(sources)+len(sessions))
+	updated, removed := 0, 0
+
+	for _, src := range sources {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		alive[src.path] = struct{}{}
+		if s.isFresh(src.path, src.modTime, src.size) {
+			continue
+		}
+		data, err := os.ReadFile(src.path)
+		if err != nil {
+			continue
+		}
+		tf, n := termFrequencies(string(data))
+		s.put(docMeta{
+			Kind:        KindDoc,
+			ProjectPath: src.projectPath,
+			Path:        src.path,
+			RelPath:     src.relPath,
+			Title:       filepath.Base(src.path),
+			ModTime:     src.modTime,
+			Size:        src.size,
+		}, tf, n)
+		updated++
+	}
+
+	for _, sess := range sessions {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if at, ok := s.unmatched[sess.Path]; ok && at.Equal(sess.ModTime) {
+			continue
+		}
+		alive[sess.Path] = struct{}{}
+		if s.isFresh(sess.Path, sess.ModTime, sess.Size) {
+			continue
+		}
+		entries, err := transcript.ReadEntries(sess.Path)
+		if err != nil {
+			continue
+		}
+		// C2: 帰属は実 cwd + MatchProject
+		projPath, ok := idle.MatchProject(firstCwd(entries), projs)
+		if !ok {
+			s.unmatched[sess.Path] = sess.ModTime
+			delete(alive, sess.Path)
+			continue
+		}
+		delete(s.unmatched, sess.Path)
+
+		text, title := sessionText(entries)
+		tf, n := termFrequencies(text)
+		s.put(docMeta{
+			Kind:        KindSession,
+			ProjectPath: projPath,
+			Path:        sess.Path,
+			SessionID:   sess.ID,
+			Title:       title,
+			ModTime:     sess.ModTime,
+			Size:        sess.Size,
+		}, tf, n)
+		updated++
+	}
+
+	s.mu.Lock()
+	for path := range s.index.ByPath {
+		if _, ok := alive[path]; !ok {
+			s.index.remove(path)
+			removed++
+		}
+	}
+	s.indexedAt = s.now()
+	var saveErr error
+	if updated > 0 || removed > 0 {
+		saveErr = s.index.save(s.indexDir)
+	}
+	total := len(s.index.Docs)
+	s.mu.Unlock()
+
+	if updated > 0 || removed > 0 {
+		log.Printf("[SearchService] Refresh completed: updated=%d, removed=%d, total=%d", updated, removed, total)
+	}
+	if saveErr != nil {
+		return fmt.Errorf("failed to save index: %w", saveErr)
+	}
+	return nil
+}
+
+// Search は索引を検索します。
+func (s *serviceImpl) Search(ctx context.Context, q Query) (*Result, error) {
+	terms := queryTerms(q.Text)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%w: query is empty", ErrValidation)
+	}
+	if q.Kind != "" && q.Kind != KindSession && q.Kind != KindDoc {
+		return nil, fmt.Errorf("%w: kind must be session or doc: %s", ErrValidation, q.Kind)
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	projs, err := s.projectsProvider()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load projects: %w", err)
+	}
+	names := make(map[string]string, len(projs))
+	for _, p := range projs {
+		names[filepath.Clean(p.Path)] = p.Name
+	}
+	project := ""
+	if q.ProjectPath != "" {
+		project = filepath.Clean(q.ProjectPath)
+		if _, ok := names[project]; !ok {
+			return nil, fmt.Errorf("%w: project is not registered: %s", ErrValidation, q.ProjectPath)
+		}
+	}
+
+	s.mu.RLock()
+	scored := s.index.search(terms, func(m *docMeta) bool {
+		if q.Kind != "" && m.Kind != q.Kind {
+			return false
+		}
+		if project != "" && m.ProjectPath != project {
+			return false
+		}
+		// 登録解除済みプロジェクトのドキュメントは次回 Refresh まで索引に残るため除外する
+		_, ok := names[m.ProjectPath]
+		return ok
+	})
+	// 索引は Refresh で書き換わるため、必要なメタ情報はロック内で複製する
+	total := len(scored)
+	if len(scored) > limit {
+		scored = scored[:limit]
+	}
+	metas := make([]docMeta, len(scored))
+	scores := make([]float64, len(scored))
+	for i, sd := range scored {
+		metas[i] = *sd.meta
+		scores[i] = sd.score
+	}
+	indexed := len(s.index.Docs)
+	indexedAt := s.indexedAt
+	s.mu.RUnlock()
+
+	hits := make([]Hit, 0, len(metas))
+	for i, m := range metas {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		hits = append(hits, Hit{
+			Kind:        m.Kind,
+			ProjectPath: m.ProjectPath,
+			ProjectName: names[m.ProjectPath],
+			Path:        m.Path,
+			RelPath:     m.RelPath,
+			SessionID:   m.SessionID,
+			Title:       m.Title,
+			Snippet:     buildSnippet(loadText(m), q.Text, terms),
+			Score:       scores[i],
+			ModTime:     m.ModTime,
+			Link:        linkFor(m),
+		})
+	}
+
+	log.Printf("[SearchService] Search completed: q=%q, project=%s, kind=%s, total=%d", q.Text, q.ProjectPath, q.Kind, total)
+	return &Result{Query: q.Text, Total: total, Hits: hits, Indexed: indexed, IndexedAt: indexedAt}, nil
+}
+
+// isFresh は path が同じ mtime + size で索引済みかを返します。
+func (s *serviceImpl) isFresh(path string, modTime time.Time, size int64) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	m, ok := s.index.lookup(path)
+	return ok && m.ModTime.Equal(modTime) && m.Size == size
+}
+
+// put はドキュメントを索引に追加（置換）します。
+func (s *serviceImpl) put(meta docMeta, tf map[string]uint32, n int) {
+	s.mu.Lock()
+	s.index.put(meta, tf, n)
+	s.mu.Unlock()
+}
+
+// collectDocs は各プロジェクトの 開発/<devFolders> 配下の .md を再帰的に列挙します。
+// 隠しファイル・隠しディレクトリは対象外です。存在しないフォルダは黙って skip します。
+func (s *serviceImpl) collectDocs(projs []projects.Project) []source {
+	out := make([]source, 0)
+	for _, p := range projs {
+		root := filepath.Clean(p.Path)
+		for _, folder := range s.devFolders {
+			dir := filepath.Join(root, "開発", folder)
+			_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					if path == dir {
+						return fs.SkipDir
+					}
+					return nil
+				}
+				name := d.Name()
+				if d.IsDir() {
+					if path != dir && strings.HasPrefix(name, ".") {
+						return fs.SkipDir
+					}
+					return nil
+				}
+				if strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".md") {
+					return nil
+				}
+				info, err := d.Info()
+				if err != nil {
+					return nil
+				}
+				rel, err := filepath.Rel(root, path)
+				if err != nil {
+					return nil
+				}
+				out = append(out, source{
+					projectPath: root,
+					path:        path,
+					relPath:     rel,
+					modTime:     info.ModTime(),
+					size:        info.Size(),
+				})
+				return nil
+			})
+		}
+	}
+	return out
+}
+
+// firstCwd はエントリ列で最初に現れた cwd を返します。
+func firstCwd(entries []transcript.Entry) string {
+	for _, e := range entries {
+		if e.Cwd != "" {
+			return e.Cwd
+		}
+	}
+	return ""
+}
+
+// sessionText は会話ログの検索対象テキスト（プロンプトと assistant の text 要素）とタイトルを返します。
+// tool_result（ファイル内容・コマンド出力）と thinking は索引が肥大化し検索ノイズになるため対象外です。
+func sessionText(entries []transcript.Entry) (string, string) {
+	var b strings.Builder
+	title := ""
+	for _, e := range entries {
+		if e.Type != "assistant" && !e.IsPrompt() {
+			continue
+		}
+		text := e.Text()
+		if text == "" {
+			continue
+		}
+		if title == "" && e.IsPrompt() {
+			title = truncateRunes(strings.Join(strings.Fields(text), " "), titleMaxRunes)
+		}
+		b.WriteString(text)
+		b.WriteString("\n")
+	}
+	return b.String(), title
+}
+
+// loadText はスニペット作成用にドキュメント本文を読み直します。読めなければ空を返します。
+func loadText(m docMeta) string {
+	if m.Kind == KindSession {
+		entries, err := transcript.ReadEntries(m.Path)
+		if err != nil {
+			return ""
+		}
+		text, _ := sessionText(entries)
+		return text
+	}
+	data, err := os.ReadFile(m.Path)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// buildSnippet はヒット位置の前後 snippetRadius 文字を切り出します。
+// クエリ全体 → 空白区切りの語 → 索引語 の順に最初の出現位置を探し、見つからなければ冒頭を返します。
+func buildSnippet(text, rawQuery string, terms []string) string {
+	if text == "" {
+		return ""
+	}
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(runes) {
+		// 小文字化で文字数が変わる特殊文字を含む場合は大文字小文字を区別して探す
+		lower = runes
+	}
+
+	candidates := append([]string{rawQuery}, strings.Fields(rawQuery)...)
+	candidates = append(candidates, terms...)
+	pos, length := -1, 0
+	for _, c := range candidates {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c == "" {
+			continue
+		}
+		if i := indexRunes(lower, []rune(c)); i >= 0 {
+			pos, length = i, len([]rune(c))
+			break
+		}
+	}
+
+	start, end := 0, len(runes)
+	if pos >= 0 {
+		start = max(0, pos-snippetRadius)
+		end = min(len(runes), pos+length+snippetRadius)
+	} else if end > snippetRadius*2 {
+		end = snippetRadius * 2
+	}
+
+	snippet := strings.Join(strings.Fields(string(runes[start:end])), " ")
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(runes) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// indexRunes は haystack 内で needle が最初に現れる位置（rune 単位）を返します。
+func indexRunes(haystack, needle []rune) int {
+	if len(needle) == 0 || len(needle) > len(haystack) {
+		return -1
+	}
+outer:
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		for j := range needle {
+			if haystack[i+j] != needle[j] {
+				continue outer
+			}
+		}
+		return i
+	}
+	return -1
+}
+
+// linkFor はヒットしたドキュメントを開くためのリンクを返します。
+// doc は VS Code で開く vscode://file/ リンク、session は HTML エクスポート API の相対 URL です。
+func linkFor(m docMeta) string {
+	if m.Kind == KindDoc {
+		return "vscode://file" + filepath.ToSlash(m.Path)
+	}
+	return "/api/exports/sessions/" + url.PathEscape(m.SessionID) + "?format=html"
+}
+
+// truncateRunes は s を最大 n 文字（rune）に切り詰めます。
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
@@ -0,0 +1,121 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/projects"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func sessionLines(cwd string, texts ...string) string {
+	var b strings.Builder
+	for i, text := range texts {
+		role := "user"
+		if i%2 == 1 {
+			role = "assistant"
+		}
+		b.WriteString(`{"type":"` + role + `","cwd":"` + cwd + `","timestamp":"2026-07-01T00:00:0` + string(rune('0'+i)) + `Z","message":{"role":"` + role + `","content":[{"type":"text","text":"` + text + `"}]}}` + "\n")
+	}
+	return b.String()
+}
+
+func TestService_RefreshAndSearch(t *testing.T) {
+	home := t.TempDir()
+	projDir := filepath.Join(t.TempDir(), "shop")
+	projs := []projects.Project{{Name: "shop", Path: projDir}}
+	provider := func() ([]projects.Project, error) { return projs, nil }
+	now := func() time.Time { return time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC) }
+	indexDir := filepath.Join(home, ".ghostrunner", "index")
+
+	planPath := filepath.Join(projDir, "開発", "実装", "完了", "アーカイブ", "決済_plan.md")
+	writeFile(t, planPath, "# 決済\n\nStripe の webhook 署名検証を追加する。\n")
+	writeFile(t, filepath.Join(projDir, "開発", "資料", "ログ.md"), "ログ設計の資料\n")
+	writeFile(t, filepath.Join(projDir, "開発", "実装", "実行中", "対象外.md"), "webhook\n")
+	sessPath := filepath.Join(home, ".claude", "projects", "-shop", "sess-1.jsonl")
+	writeFile(t, sessPath, sessionLines(projDir, "payment webhook を直して", "webhook の再送処理を修正しました"))
+	// 未登録プロジェクトの会話ログは索引しない
+	writeFile(t, filepath.Join(home, ".claude", "projects", "-other", "sess-2.jsonl"), sessionLines("/other", "webhook"))
+
+	svc := NewService(indexDir, home, provider, []string{"実装/完了", "資料"}, now)
+	if err := svc.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh error: %v", err)
+	}
+
+	res, err := svc.Search(context.Background(), Query{Text: "webhook"})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if res.Total != 2 || res.Indexed != 3 {
+		t.Fatalf("total=%d indexed=%d, want 2/3: %+v", res.Total, res.Indexed, res.Hits)
+	}
+
+	res, err = svc.Search(context.Background(), Query{Text: "webhook", Kind: KindSession})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(res.Hits) != 1 || res.Hits[0].SessionID != "sess-1" || res.Hits[0].Title != "payment webhook を直して" || res.Hits[0].ProjectName != "shop" {
+		t.Fatalf("session hit = %+v", res.Hits)
+	}
+
+	res, err = svc.Search(context.Background(), Query{Text: "署名検証", Kind: KindDoc, ProjectPath: projDir})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(res.Hits) != 1 || res.Hits[0].RelPath != filepath.Join("開発", "実装", "完了", "アーカイブ", "決済_plan.md") {
+		t.Fatalf("doc hit = %+v", res.Hits)
+	}
+	if !strings.Contains(res.Hits[0].Snippet, "署名検証") || !strings.HasPrefix(res.Hits[0].Link, "vscode://file/") {
+		t.Errorf("snippet=%q link=%q", res.Hits[0].Snippet, res.Hits[0].Link)
+	}
+
+	t.Run("インクリメンタル更新と永続化", func(t *testing.T) {
+		writeFile(t, planPath, "# 決済\n\n署名は廃止。\n")
+		future := time.Now().Add(time.Minute)
+		if err := os.Chtimes(planPath, future, future); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Remove(filepath.Join(projDir, "開発", "資料", "ログ.md")); err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.Refresh(context.Background()); err != nil {
+			t.Fatalf("Refresh error: %v", err)
+		}
+
+		// 再起動相当: 永続化済み索引から読み込む
+		reloaded := NewService(indexDir, home, provider, []string{"実装/完了", "資料"}, now)
+		res, err := reloaded.Search(context.Background(), Query{Text: "webhook", Kind: KindDoc})
+		if err != nil {
+			t.Fatalf("Search error: %v", err)
+		}
+		if res.Total != 0 || res.Indexed != 2 {
+			t.Errorf("更新・削除が反映されていない: total=%d indexed=%d", res.Total, res.Indexed)
+		}
+	})
+
+	t.Run("バリデーション", func(t *testing.T) {
+		for _, q := range []Query{
+			{Text: "  "},
+			{Text: "x", Kind: "file"},
+			{Text: "x", ProjectPath: "/not/registered"},
+		} {
+			if _, err := svc.Search(context.Background(), q); !errors.Is(err, ErrValidation) {
+				t.Errorf("Search(%+v) err = %v, want ErrValidation", q, err)
+			}
+		}
+	})
+}
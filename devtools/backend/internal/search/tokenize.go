@@ -0,0 +1,89 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// maxTokenRunes は英数字トークンとして索引する最大文字数です。
+// base64 やハッシュ等の長大な文字列で索引が肥大化するのを防ぎます。
+const maxTokenRunes = 40
+
+// tokenize はテキストを索引語に分割します。
+//
+// 日本語は分かち書きされないため、CJK（漢字・ひらがな・カタカナ）の連続は文字 bigram に分割し、
+// 1文字だけの連続はその1文字を索引語とします。英数字の連続は小文字化した単語とします。
+// 辞書を持たずに日本語の部分一致検索を成立させるための pure Go の簡易方式です。
+func tokenize(text string) []string {
+	out := make([]string, 0, len(text)/4)
+	var word []rune
+	var cjk []rune
+
+	flushWord := func() {
+		if len(word) > 0 && len(word) <= maxTokenRunes {
+			out = append(out, strings.ToLower(string(word)))
+		}
+		word = word[:0]
+	}
+	flushCJK := func() {
+		switch len(cjk) {
+		case 0:
+		case 1:
+			out = append(out, string(cjk))
+		default:
+			for i := 0; i+1 < len(cjk); i++ {
+				out = append(out, string(cjk[i:i+2]))
+			}
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+	return out
+}
+
+// isCJK は r が bigram 分割対象の文字（漢字・ひらがな・カタカナ・長音）かを返します。
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		r == 'ー'
+}
+
+// termFrequencies は索引語ごとの出現回数を返します。
+func termFrequencies(text string) (map[string]uint32, int) {
+	tokens := tokenize(text)
+	tf := make(map[string]uint32, len(tokens)/2)
+	for _, t := range tokens {
+		tf[t]++
+	}
+	return tf, len(tokens)
+}
+
+// queryTerms はクエリを重複排除した索引語に分割します。
+func queryTerms(q string) []string {
+	seen := make(map[string]struct{})
+	out := make([]string, 0)
+	for _, t := range tokenize(q) {
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		out = append(out, t)
+	}
+	return out
+}
@@ -0,0 +1,61 @@
+package search
+
+import "time"
+
+// Kind は検索対象ドキュメントの種別です。
+type Kind string
+
+const (
+	// KindSession は Claude Code の会話ログ（~/.claude/projects/ 配下の JSONL）です。
+	KindSession Kind = "session"
+	// KindDoc は登録プロジェクトの 開発/ 配下の Markdown ファイルです。
+	KindDoc Kind = "doc"
+)
+
+// DefaultLimit はクエリで件数指定が無い場合の最大件数です。
+const DefaultLimit = 20
+
+// MaxLimit はクエリで指定できる最大件数です。
+const MaxLimit = 100
+
+// Query は検索条件です。
+type Query struct {
+	// Text は検索語です（空白区切りの語はすべて含むドキュメントに絞る AND 検索）。
+	Text string
+	// ProjectPath は対象プロジェクトの絶対パスです（空なら全プロジェクト）。
+	ProjectPath string
+	// Kind は対象種別です（空なら両方）。
+	Kind Kind
+	// Limit は最大件数です（0 なら DefaultLimit）。
+	Limit int
+}
+
+// Hit は検索結果1件です。
+type Hit struct {
+	Kind        Kind   `json:"kind"`
+	ProjectPath string `json:"projectPath"`
+	ProjectName string `json:"projectName"`
+	// Path はファイルの絶対パスです。
+	Path string `json:"path"`
+	// RelPath は doc の場合のプロジェクトルートからの相対パスです（例: 開発/資料/x.md）。
+	RelPath string `json:"relPath,omitempty"`
+	// SessionID は session の場合の session-id です。
+	SessionID string `json:"sessionId,omitempty"`
+	// Title は doc ならファイル名、session なら最初のプロンプト冒頭です。
+	Title   string    `json:"title"`
+	Snippet string    `json:"snippet"`
+	Score   float64   `json:"score"`
+	ModTime time.Time `json:"modTime"`
+	// Link はセッション/ファイルを開くためのリンクです。
+	Link string `json:"link"`
+}
+
+// Result は検索結果全体です。
+type Result struct {
+	Query   string `json:"query"`
+	Total   int    `json:"total"`
+	Hits    []Hit  `json:"hits"`
+	Indexed int    `json:"indexed"`
+	// IndexedAt は最後に索引更新が完了した時刻です（未完了ならゼロ値）。
+	IndexedAt time.Time `json:"indexedAt"`
+}
@@ -14,6 +14,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"ghostrunner/backend/internal/notify"
 )
 
 // ClaudeService はClaude CLI操作のインターフェースを定義します
@@ -64,7 +66,7 @@ func (s *claudeServiceImpl) ExecuteCommand(ctx context.Context, project, command
 
 	// プロンプト構築: "/<command> <args>"
 	prompt := buildPromptWithImages(command, args, imagePaths)
-	return s.executeCommand(ctx, project, prompt, "")
+	return s.executeCommand(ctx, project, command, prompt, "")
 }
 
 // ExecuteCommandStream はカスタムコマンドをストリーミングで実行します
@@ -87,7 +89,7 @@ func (s *claudeServiceImpl) ExecuteCommandStream(ctx context.Context, project, c
 
 	// プロンプト構築: "/<command> <args>"
 	prompt := buildPromptWithImages(command, args, imagePaths)
-	return s.executeCommandStream(ctx, project, prompt, "", eventCh)
+	return s.executeCommandStream(ctx, project, command, prompt, "", eventCh)
 }
 
 // ExecutePlan は/planコマンドを実行します（互換性維持）
@@ -101,7 +103,7 @@ func (s *claudeServiceImpl) ExecutePlan(ctx context.Context, project, args strin
 func (s *claudeServiceImpl) ContinueSession(ctx context.Context, project, sessionID, answer string) (*CommandResult, error) {
 	log.Printf("[ClaudeService] ContinueSession started: project=%s, sessionID=%s, answer=%s", project, sessionID, truncateLog(answer, 100))
 
-	return s.executeCommand(ctx, project, answer, sessionID)
+	return s.executeCommand(ctx, project, continueCommandLabel, answer, sessionID)
 }
 
 // ExecutePlanStream は/planコマンドをストリーミングで実行します（互換性維持）
@@ -115,11 +117,19 @@ func (s *claudeServiceImpl) ExecutePlanStream(ctx context.Context, project, args
 func (s *claudeServiceImpl) ContinueSessionStream(ctx context.Context, project, sessionID, answer string, eventCh chan<- StreamEvent) error {
 	log.Printf("[ClaudeService] ContinueSessionStream started: project=%s, sessionID=%s", project, sessionID)
 
-	return s.executeCommandStream(ctx, project, answer, sessionID, eventCh)
+	return s.executeCommandStream(ctx, project, continueCommandLabel, answer, sessionID, eventCh)
+}
+
+// executeCommandStream はCLIコマンドをストリーミングで実行し、実行数をメトリクスに記録します
+func (s *claudeServiceImpl) executeCommandStream(ctx context.Context, project, command, prompt, sessionID string, eventCh chan<- StreamEvent) error {
+	done := trackClaudeRun(command)
+	err := s.runCommandStream(ctx, project, prompt, sessionID, eventCh)
+	done(err)
+	return err
 }
 
-// executeCommandStream はCLIコマンドをストリーミングで実行します
-func (s *claudeServiceImpl) executeCommandStream(ctx context.Context, project, prompt, sessionID string, eventCh chan<- StreamEvent) error {
+// runCommandStream はCLIコマンドをストリーミングで実行します
+func (s *claudeServiceImpl) runCommandStream(ctx context.Context, project, prompt, sessionID string, eventCh chan<- StreamEvent) error {
 	defer close(eventCh)
 
 	// タイムアウト付きコンテキストを作成
@@ -143,7 +153,7 @@ func (s *claudeServiceImpl) executeCommandStream(ctx context.Context, project, p
 	// stdoutをパイプで取得
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		s.notifyError("Failed to create stdout pipe: " + err.Error())
+		s.notifyError(project, "Failed to create stdout pipe: "+err.Error())
 		eventCh <- StreamEvent{Type: EventTypeError, Message: "Failed to create stdout pipe: " + err.Error()}
 		return fmt.Errorf("failed to create stdout pipe: %w", err)
 	}
@@ -153,7 +163,7 @@ func (s *claudeServiceImpl) executeCommandStream(ctx context.Context, project, p
 
 	// コマンド開始
 	if err := cmd.Start(); err != nil {
-		s.notifyError("Failed to start command: " + err.Error())
+		s.notifyError(project, "Failed to start command: "+err.Error())
 		eventCh <- StreamEvent{Type: EventTypeError, Message: "Failed to start command: " + err.Error()}
 		return fmt.Errorf("failed to start command: %w", err)
 	}
@@ -191,7 +201,7 @@ func (s *claudeServiceImpl) executeCommandStream(ctx context.Context, project, p
 			// 最終結果を保持
 			if event.Type == EventTypeComplete && event.Result != nil {
 				finalResult = event.Result
-				s.notifyComplete(event.Result.Output)
+				s.notifyComplete(project, event.Result.Output)
 			}
 
 			// AskUserQuestion検出時はプロセスを停止してユーザー入力を待つ
@@ -225,7 +235,7 @@ func (s *claudeServiceImpl) executeCommandStream(ctx context.Context, project, p
 
 	if err := scanner.Err(); err != nil {
 		log.Printf("[ClaudeService] Scanner error: %v", err)
-		s.notifyError("Stream read error: " + err.Error())
+		s.notifyError(project, "Stream read error: "+err.Error())
 		select {
 		case eventCh <- StreamEvent{Type: EventTypeError, Message: "Stream read error: " + err.Error()}:
 		case <-ctx.Done():
@@ -239,7 +249,7 @@ func (s *claudeServiceImpl) executeCommandStream(ctx context.Context, project, p
 	if err != nil {
 		// コンテキストキャンセルの場合
 		if ctx.Err() == context.DeadlineExceeded {
-			s.notifyError("Execution timeout")
+			s.notifyError(project, "Execution timeout")
 			select {
 			case eventCh <- StreamEvent{Type: EventTypeError, Message: "Execution timeout"}:
 			default:
@@ -261,7 +271,7 @@ func (s *claudeServiceImpl) executeCommandStream(ctx context.Context, project, p
 		}
 
 		log.Printf("[ClaudeService] Command failed: error=%v", err)
-		s.notifyError("Command failed: " + err.Error())
+		s.notifyError(project, "Command failed: "+err.Error())
 		select {
 		case eventCh <- StreamEvent{Type: EventTypeError, Message: "Command failed: " + err.Error()}:
 		default:
@@ -271,7 +281,7 @@ func (s *claudeServiceImpl) executeCommandStream(ctx context.Context, project, p
 
 	// 最終結果がない場合は完了イベントを送信
 	if finalResult == nil {
-		s.notifyComplete("")
+		s.notifyComplete(project, "")
 		eventCh <- StreamEvent{
 			Type:      EventTypeComplete,
 			SessionID: currentSessionID,
@@ -523,8 +533,16 @@ func formatToolMessage(toolName string, toolInput interface{}) string {
 	}
 }
 
-// executeCommand はCLIコマンドを実行し、結果をパースします
-func (s *claudeServiceImpl) executeCommand(ctx context.Context, project, prompt, sessionID string) (*CommandResult, error) {
+// executeCommand はCLIコマンドを実行し、実行数をメトリクスに記録します
+func (s *claudeServiceImpl) executeCommand(ctx context.Context, project, command, prompt, sessionID string) (*CommandResult, error) {
+	done := trackClaudeRun(command)
+	result, err := s.runCommand(ctx, project, prompt, sessionID)
+	done(err)
+	return result, err
+}
+
+// runCommand はCLIコマンドを実行し、結果をパースします
+func (s *claudeServiceImpl) runCommand(ctx context.Context, project, prompt, sessionID string) (*CommandResult, error) {
 	// タイムアウト付きコンテキストを作成
 	ctx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
@@ -559,7 +577,7 @@ func (s *claudeServiceImpl) executeCommand(ctx context.Context, project, prompt,
 		// コンテキストキャンセルの場合は特別なエラーメッセージ
 		if ctx.Err() == context.DeadlineExceeded {
 			log.Printf("[ClaudeService] Command timeout: project=%s", project)
-			s.notifyError("Command timeout")
+			s.notifyError(project, "Command timeout")
 			return nil, fmt.Errorf("execution timeout after %v: %w", s.timeout, err)
 		}
 		if ctx.Err() == context.Canceled {
@@ -572,13 +590,13 @@ func (s *claudeServiceImpl) executeCommand(ctx context.Context, project, prompt,
 			result, parseErr := s.parseResponse(stdoutStr)
 			if parseErr == nil {
 				log.Printf("[ClaudeService] Parsed response from error state: sessionID=%s, questions=%d", result.SessionID, len(result.Questions))
-				s.notifyComplete(result.Output)
+				s.notifyComplete(project, result.Output)
 				return result, nil
 			}
 		}
 
 		log.Printf("[ClaudeService] Command failed: project=%s, error=%v, stdout=%s, stderr=%s", project, err, stdoutStr, stderrStr)
-		s.notifyError("Command execution failed")
+		s.notifyError(project, "Command execution failed")
 		return nil, fmt.Errorf("claude cli execution failed: %w", err)
 	}
 
@@ -595,7 +613,7 @@ func (s *claudeServiceImpl) executeCommand(ctx context.Context, project, prompt,
 
 	log.Printf("[ClaudeService] Command completed: sessionID=%s, questions=%d, completed=%v", result.SessionID, len(result.Questions), result.Completed)
 	if result.Completed {
-		s.notifyComplete(result.Output)
+		s.notifyComplete(project, result.Output)
 	}
 	return result, nil
 }
@@ -791,8 +809,8 @@ func buildPromptWithImages(command, args string, imagePaths []string) string {
 	return basePrompt + imageInfo
 }
 
-// notifyComplete はコマンド完了時にntfy通知を送信します
-func (s *claudeServiceImpl) notifyComplete(output string) {
+// notifyComplete はコマンド完了時に通知を送信します
+func (s *claudeServiceImpl) notifyComplete(project, output string) {
 	if s.ntfyService == nil {
 		return
 	}
@@ -800,13 +818,25 @@ func (s *claudeServiceImpl) notifyComplete(output string) {
 	if output != "" {
 		message = truncateLog(output, 100)
 	}
-	s.ntfyService.Notify("Claude Code - Complete", message)
+	notify.Send(s.ntfyService, notify.Message{
+		Event:    notify.EventCommandComplete,
+		Project:  project,
+		Title:    "Claude Code - Complete",
+		Body:     message,
+		Priority: notify.PriorityDefault,
+	})
 }
 
-// notifyError はエラー発生時にntfy通知を送信します
-func (s *claudeServiceImpl) notifyError(message string) {
+// notifyError はエラー発生時に通知を送信します
+func (s *claudeServiceImpl) notifyError(project, message string) {
 	if s.ntfyService == nil {
 		return
 	}
-	s.ntfyService.NotifyError("Claude Code - Error", message)
+	notify.Send(s.ntfyService, notify.Message{
+		Event:    notify.EventCommandError,
+		Project:  project,
+		Title:    "Claude Code - Error",
+		Body:     message,
+		Priority: notify.PriorityHigh,
+	})
 }
@@ -40,11 +40,11 @@
 //
 // # NtfyService
 //
-// ntfy.sh を使ったプッシュ通知を担当するサービス。
-// NTFY_TOPIC 環境変数が設定されていない場合は nil を返し、通知機能が無効になる。
-// ClaudeServiceに注入され、コマンド完了時やエラー発生時にHTTP POSTで通知を送信する。
-// 通知送信はfire-and-forget方式（ゴルーチンで非同期実行）のため、
-// 通知の成否がコマンド実行の結果に影響を与えることはない。
+// 通知送信のインターフェース（notify.Notifier の別名）。サーバーでは notify.Registry を注入し、
+// 通知チャネルが無い場合は nil になる。ClaudeService・PatrolService は notify.Send で種類
+// （command.complete / command.error / patrol.question）とプロジェクトを付けて送り、通知先は notify.yaml のルートで決まる。
+// 配送は Registry のキューで非同期に行うため、通知の成否がコマンド実行の結果に影響を与えることはない。
+// NewNtfyService（ntfy.sh へ直接送る旧実装）は非推奨。
 //
 // 主なメソッド:
 //   - Notify: 通常の通知を送信（完了通知など、優先度: default）
@@ -62,6 +62,22 @@
 //   - model: 使用するモデル（未指定時: gpt-4o-realtime-preview-2024-12-17）
 //   - voice: 音声タイプ（未指定時: verse）
 //
+// # SummarizeService / SummarizeProvider
+//
+// 質問待ちセッションの会話末尾を日本語1行に要約するサービス。dashboard.Summarizer から利用される。
+// SummarizeProvider は名前付きの SummarizeService で、NewSummarizeProvider で名前から生成する。
+//
+// プロバイダー:
+//   - claude: claude -p --model haiku（NewSummarizeService と同じ）
+//   - openai: Chat Completions API（OPENAI_API_KEY、既定モデル gpt-4o-mini）
+//   - gemini: generateContent API（GEMINI_API_KEY、既定モデル gemini-2.0-flash）
+//   - local: OpenAI 互換のローカルエンドポイント（BaseURL 必須、SUMMARIZER_LOCAL_API_KEY は任意）
+//   - extractive: モデルを使わず最後の質問文（無ければ最後の文）を抜き出す決定的な要約（常に成功）
+//
+// SummarizeProvider は ProgressSummarizeService も実装し、動作中セッションの会話ログの差分（ProgressInput）を
+// 「いま: …／済: …」の1行に要約する（dashboard.ProgressSummarizer から利用される）。extractive は直近の
+// ツール呼び出しと編集したファイルから組み立てる。
+//
 // # CreateProjectService
 //
 // GUIからのプロジェクト生成を担当するサービス。
@@ -127,7 +143,19 @@
 //   - running -> waiting_approval: 質問（設計判断等）発生時
 //   - running -> completed: Claude CLI正常完了時
 //   - running -> error: エラー発生時
-//   - waiting_approval -> running: ユーザーが回答を送信した時
+//   - waiting_approval -> running: ユーザーが回答を送信した時（自動回答ルールによる回答を含む）
+//
+// 自動回答（WithAutoAnswer 設定時）:
+//   - 質問が1件の質問イベントをプロジェクトの .ghostrunner/auto_answer.yaml に照合する
+//   - answer は即座に ResumeProject で回答し、承認待ちの通知は送らない
+//   - timeout は通常の通知に予定を添え、待ち時間後も同じセッションの同じ質問（見出し・本文・選択肢）で承認待ちなら回答する。
+//     予約は ResumeProject（人手の回答）と StopPatrol で取り消す
+//   - escalate は通常の通知の代わりにエラー通知を送る
+//   - 自動回答とエスカレーションは autoanswer の監査ログに記録する
+//
+// 回答ボタン（WithAnswerActions 設定時）:
+//   - 選択肢のある承認待ちの通知に、選択肢ごとの署名付きコールバック URL をボタンとして付ける
+//   - ボタンの処理（検証・ResumeProject）は handler の CallbackHandler が行う
 //
 // 並列実行制御:
 //   - セマフォ（バッファ付きチャンネル、容量5）による並列数制限
@@ -205,8 +233,9 @@
 //
 // 同期実行（汎用コマンド、テキストのみ）:
 //
-//	ntfy := service.NewNtfyService() // NTFY_TOPIC 未設定時は nil
-//	svc := service.NewClaudeService(ntfy)
+//	registry, _ := notify.NewRegistry(notifyConfig) // チャネル未設定時は ClaudeService に nil を渡す
+//	registry.Start(ctx)
+//	svc := service.NewClaudeService(registry)
 //	result, err := svc.ExecuteCommand(ctx, "/path/to/project", "fullstack", "implement feature X", nil)
 //	if err != nil {
 //	    // エラーハンドリング
@@ -226,8 +255,7 @@
 //
 // 同期実行（後方互換性）:
 //
-//	ntfy := service.NewNtfyService()
-//	svc := service.NewClaudeService(ntfy)
+//	svc := service.NewClaudeService(registry)
 //	result, err := svc.ExecutePlan(ctx, "/path/to/project", "implement feature X")
 //	if err != nil {
 //	    // エラーハンドリング
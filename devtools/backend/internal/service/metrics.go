@@ -0,0 +1,41 @@
+package service
+
+import "ghostrunner/backend/internal/metrics"
+
+// continueCommandLabel はセッション継続（回答送信）の実行を command ラベルで表す値です
+const continueCommandLabel = "continue"
+
+var (
+	// claudeRunsTotal は Claude CLI の実行数です（command 別、result=started / completed / errored）
+	claudeRunsTotal = metrics.NewCounterVec("ghostrunner_claude_runs_total",
+		"Claude CLI runs by command and result (started, completed, errored).", "command", "result")
+	// claudeRunsInFlight は実行中の Claude CLI プロセス数です
+	claudeRunsInFlight = metrics.NewGaugeVec("ghostrunner_claude_runs_in_flight",
+		"Claude CLI runs currently in progress.")
+	// patrolSlotsInUse は使用中の巡回スロット数です
+	patrolSlotsInUse = metrics.NewGaugeVec("ghostrunner_patrol_slots_in_use",
+		"Patrol execution slots currently in use.")
+	// patrolSlotsCapacity は巡回スロットの上限です（使用率 = in_use / capacity）
+	patrolSlotsCapacity = metrics.NewGaugeVec("ghostrunner_patrol_slots_capacity",
+		"Maximum number of parallel patrol execution slots.")
+)
+
+// trackClaudeRun は Claude CLI 実行の開始を記録し、終了時に結果を記録する関数を返します。
+func trackClaudeRun(command string) func(err error) {
+	claudeRunsTotal.Inc(command, "started")
+	claudeRunsInFlight.Inc()
+	return func(err error) {
+		claudeRunsInFlight.Dec()
+		if err != nil {
+			claudeRunsTotal.Inc(command, "errored")
+			return
+		}
+		claudeRunsTotal.Inc(command, "completed")
+	}
+}
+
+// acquirePatrolSlot は巡回スロットの取得をメトリクスに記録します（スロット取得の直後に呼ぶ）
+func acquirePatrolSlot() { patrolSlotsInUse.Inc() }
+
+// releasePatrolSlot は巡回スロットの解放をメトリクスに記録します（スロット解放の直前に呼ぶ）
+func releasePatrolSlot() { patrolSlotsInUse.Dec() }
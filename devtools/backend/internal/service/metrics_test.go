@@ -0,0 +1,32 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTrackClaudeRun(t *testing.T) {
+	before := map[string]float64{
+		"started":   claudeRunsTotal.Value("test-cmd", "started"),
+		"completed": claudeRunsTotal.Value("test-cmd", "completed"),
+		"errored":   claudeRunsTotal.Value("test-cmd", "errored"),
+	}
+	inFlight := claudeRunsInFlight.Value()
+
+	done := trackClaudeRun("test-cmd")
+	if got := claudeRunsInFlight.Value(); got != inFlight+1 {
+		t.Errorf("in flight during run = %v, want %v", got, inFlight+1)
+	}
+	done(nil)
+	trackClaudeRun("test-cmd")(errors.New("boom"))
+
+	want := map[string]float64{"started": 2, "completed": 1, "errored": 1}
+	for result, n := range want {
+		if got := claudeRunsTotal.Value("test-cmd", result) - before[result]; got != n {
+			t.Errorf("%s delta = %v, want %v", result, got, n)
+		}
+	}
+	if got := claudeRunsInFlight.Value(); got != inFlight {
+		t.Errorf("in flight after runs = %v, want %v", got, inFlight)
+	}
+}
@@ -10,15 +10,13 @@ import (
 	"os/exec"
 	"strings"
 	"time"
+
+	"ghostrunner/backend/internal/notify"
 )
 
-// NtfyService はntfy.sh通知操作のインターフェースを定義します
-type NtfyService interface {
-	// Notify は通常の通知を送信します（完了通知など）
-	Notify(title, message string)
-	// NotifyError はエラー通知を送信します
-	NotifyError(title, message string)
-}
+// NtfyService は通知操作のインターフェースです（notify.Notifier）。
+// サーバーと gr-run は notify.Registry を注入し、種類・プロジェクト付きの通知は notify.Send で送ります
+type NtfyService = notify.Notifier
 
 // ntfyServiceImpl はNtfyServiceの実装です
 type ntfyServiceImpl struct {
@@ -27,8 +25,10 @@ type ntfyServiceImpl struct {
 	terminalNotifierPath string
 }
 
-// NewNtfyService は新しいNtfyServiceを生成します
+// NewNtfyService は ntfy.sh へ直接送る NtfyService を生成します
 // 環境変数 NTFY_TOPIC が未設定の場合は nil を返します（オプショナル機能）
+//
+// Deprecated: 再試行・複数チャネル・ルートに対応した notify.NewRegistry を使ってください。
 func NewNtfyService() NtfyService {
 	topic := os.Getenv("NTFY_TOPIC")
 	if topic == "" {
@@ -253,7 +253,7 @@ func TestNotifyComplete_MessageTruncation(t *testing.T) {
 				ntfyService: mock,
 			}
 
-			svc.notifyComplete(tt.output)
+			svc.notifyComplete("/tmp/project", tt.output)
 
 			mock.mu.Lock()
 			defer mock.mu.Unlock()
@@ -7,11 +7,17 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"sync"
 	"time"
+
+	"ghostrunner/backend/internal/autoanswer"
+	"ghostrunner/backend/internal/callback"
+	"ghostrunner/backend/internal/events"
+	"ghostrunner/backend/internal/gitinfo"
+	"ghostrunner/backend/internal/notify"
 )
 
 // PatrolService は複数プロジェクト自動巡回のインターフェースを定義します
@@ -52,15 +58,51 @@ type patrolServiceImpl struct {
 	patrolRunning bool               // 巡回実行中フラグ
 	patrolCancel  context.CancelFunc // 巡回キャンセル用
 
-	subMu       sync.Mutex
-	subscribers map[int]chan PatrolEvent
-	nextSubID   int
+	bus *events.Bus // 巡回イベントの配信先（patrol.* トピック）
 
 	pollingCancel context.CancelFunc
+
+	autoAnswer   *autoanswer.Engine     // nil の場合は自動回答しない
+	answerTimers map[string]*time.Timer // 待ち時間後の自動回答の予約（key: path。s.mu で保護）
+
+	answerActions AnswerActionIssuer // nil の場合は通知にボタンを付けない
+}
+
+// AnswerActionIssuer は承認待ちの質問の選択肢から、押すとその選択肢で回答する通知のボタンを発行します
+// （callback.Service が実装）
+type AnswerActionIssuer interface {
+	AnswerActions(projectPath, sessionID string, q callback.Question) []notify.Action
+}
+
+// PatrolOption は PatrolService の任意の依存を設定します
+type PatrolOption func(*patrolServiceImpl)
+
+// WithAutoAnswer は質問の自動回答ルールを設定します。設定時は Claude の質問（AskUserQuestion）を
+// プロジェクトの .ghostrunner/auto_answer.yaml に照合し、即時回答・待ち時間後の回答・エスカレーションを行います。
+func WithAutoAnswer(e *autoanswer.Engine) PatrolOption {
+	return func(s *patrolServiceImpl) {
+		s.autoAnswer = e
+	}
+}
+
+// WithAnswerActions は承認待ちの通知に質問の選択肢のボタンを付けます。
+// ボタンは署名付き・単回使用のコールバック URL で、押すとその選択肢のラベルで ResumeProject します。
+func WithAnswerActions(issuer AnswerActionIssuer) PatrolOption {
+	return func(s *patrolServiceImpl) {
+		s.answerActions = issuer
+	}
+}
+
+// WithEventBus は巡回イベントを配信するイベントバスを設定します。
+// 未設定時はサービス専用のバスを使い、Subscribe の購読者にのみ配信します。
+func WithEventBus(b *events.Bus) PatrolOption {
+	return func(s *patrolServiceImpl) {
+		s.bus = b
+	}
 }
 
 // NewPatrolService は新しいPatrolServiceを生成します
-func NewPatrolService(claudeService ClaudeService, ntfyService NtfyService, configPath string) PatrolService {
+func NewPatrolService(claudeService ClaudeService, ntfyService NtfyService, configPath string, opts ...PatrolOption) PatrolService {
 	s := &patrolServiceImpl{
 		projects:      make(map[string]PatrolProject),
 		states:        make(map[string]*ProjectState),
@@ -68,8 +110,14 @@ func NewPatrolService(claudeService ClaudeService, ntfyService NtfyService, conf
 		claudeService: claudeService,
 		ntfyService:   ntfyService,
 		configPath:    configPath,
-		subscribers:   make(map[int]chan PatrolEvent),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.bus == nil {
+		s.bus = events.NewBus(nil)
+	}
+	patrolSlotsCapacity.Set(MaxParallelSlots)
 
 	// 設定ファイルからプロジェクト一覧を読み込み
 	if err := s.loadConfig(); err != nil {
@@ -250,7 +298,11 @@ func (s *patrolServiceImpl) StartPatrol() error {
 				log.Printf("[PatrolService] Patrol cancelled, skipping project: path=%s", sr.Project.Path)
 				return
 			}
-			defer func() { <-s.slots }()
+			acquirePatrolSlot()
+			defer func() {
+				releasePatrolSlot()
+				<-s.slots
+			}()
 
 			s.startProjectExecution(sr.Project, sr.PendingTasks[0], sr.GitLog)
 		}(result)
@@ -271,7 +323,7 @@ func (s *patrolServiceImpl) StartPatrol() error {
 	return nil
 }
 
-// StopPatrol は巡回を停止します
+// StopPatrol は巡回を停止します。予約済みの待ち時間後の自動回答も取り消します
 func (s *patrolServiceImpl) StopPatrol() {
 	log.Printf("[PatrolService] StopPatrol called")
 	s.mu.Lock()
@@ -280,6 +332,10 @@ func (s *patrolServiceImpl) StopPatrol() {
 		s.patrolCancel()
 		s.patrolCancel = nil
 	}
+	for path, t := range s.answerTimers {
+		t.Stop()
+		delete(s.answerTimers, path)
+	}
 	s.mu.Unlock()
 }
 
@@ -305,16 +361,21 @@ func (s *patrolServiceImpl) ResumeProject(projectPath, answer string) error {
 		return fmt.Errorf("no session ID for project: %s", cleanPath)
 	}
 
-	// 状態を実行中に更新
+	// 状態を実行中に更新し、回答した質問の待ち時間後の自動回答を取り消す
 	s.updateState(cleanPath, func(st *ProjectState) {
 		st.Status = StatusRunning
 		st.Question = nil
 	})
+	s.cancelAutoAnswer(cleanPath)
 
 	// セマフォでスロット取得して再開
 	go func() {
 		s.slots <- struct{}{}
-		defer func() { <-s.slots }()
+		acquirePatrolSlot()
+		defer func() {
+			releasePatrolSlot()
+			<-s.slots
+		}()
 
 		s.resumeProjectExecution(cleanPath, sessionID, answer)
 	}()
@@ -380,24 +441,15 @@ func (s *patrolServiceImpl) StopPolling() {
 	}
 }
 
-// Subscribe はSSEイベントのサブスクリプションを返します
+// Subscribe はSSEイベントのサブスクリプションを返します。
+// イベントバスの patrol.* を購読し、バッファ（100件）が満杯の間のイベントは捨てます。
 func (s *patrolServiceImpl) Subscribe() (<-chan PatrolEvent, func()) {
-	s.subMu.Lock()
-	defer s.subMu.Unlock()
-
-	ch := make(chan PatrolEvent, 100)
-	id := s.nextSubID
-	s.nextSubID++
-	s.subscribers[id] = ch
-
-	unsubscribe := func() {
-		s.subMu.Lock()
-		defer s.subMu.Unlock()
-		delete(s.subscribers, id)
-		close(ch)
-	}
-
-	return ch, unsubscribe
+	return events.SubscribeData[PatrolEvent](s.bus, events.Options{
+		Topics: []string{events.TopicPatrolPrefix + "*"},
+		Buffer: patrolSubscriberBuffer,
+		Policy: events.PolicyDropNewest,
+		Name:   "patrol",
+	})
 }
 
 // startProjectExecution はプロジェクトのClaude CLI実行を開始します
@@ -467,6 +519,12 @@ func (s *patrolServiceImpl) monitorStreamEvents(projectPath string, eventCh <-ch
 				st.Question = question
 			})
 
+			// 自動回答ルールに一致すれば回答・エスカレーションし、通常の通知は送らない
+			decision, matched := s.decideAutoAnswer(projectPath, event)
+			if matched && s.applyAutoAnswer(projectPath, event.SessionID, question, decision) {
+				return
+			}
+
 			// ntfy通知
 			if s.ntfyService != nil {
 				projectName := filepath.Base(projectPath)
@@ -474,7 +532,17 @@ func (s *patrolServiceImpl) monitorStreamEvents(projectPath string, eventCh <-ch
 				if question != nil {
 					msg = fmt.Sprintf("[%s] %s", projectName, question.Question)
 				}
-				s.ntfyService.Notify("Patrol - Approval Required", msg)
+				if matched && decision.Action == autoanswer.ActionTimeout {
+					msg += fmt.Sprintf("（%s 後に「%s」で自動回答）", decision.Timeout, decision.Answer)
+				}
+				notify.Send(s.ntfyService, notify.Message{
+					Event:    notify.EventPatrolQuestion,
+					Project:  projectPath,
+					Title:    "Patrol - Approval Required",
+					Body:     msg,
+					Priority: notify.PriorityDefault,
+					Actions:  s.questionActions(projectPath, event.SessionID, question),
+				})
 			}
 
 			// SSEイベント配信
@@ -517,6 +585,125 @@ func (s *patrolServiceImpl) monitorStreamEvents(projectPath string, eventCh <-ch
 	log.Printf("[PatrolService] Project completed: path=%s", projectPath)
 }
 
+// decideAutoAnswer は質問イベントに適用する自動回答ルールを決めます。
+// 回答は1つしか送れないため、質問が1件のときだけ照合します。
+func (s *patrolServiceImpl) decideAutoAnswer(projectPath string, event StreamEvent) (autoanswer.Decision, bool) {
+	if s.autoAnswer == nil || event.Result == nil || len(event.Result.Questions) != 1 {
+		return autoanswer.Decision{}, false
+	}
+	return s.autoAnswer.Decide(projectPath, toAutoAnswerQuestion(event.Result.Questions[0]))
+}
+
+// questionActions は質問の選択肢から通知のボタンを作ります（発行元が未設定・選択肢なしの場合は nil）
+func (s *patrolServiceImpl) questionActions(projectPath, sessionID string, question *Question) []notify.Action {
+	if s.answerActions == nil || question == nil || len(question.Options) == 0 {
+		return nil
+	}
+	return s.answerActions.AnswerActions(projectPath, sessionID, question.CallbackQuestion())
+}
+
+// applyAutoAnswer は自動回答ルールの対応を実行します。
+// 即時回答・エスカレーションを行った場合は true（通常の承認待ち通知は不要）、待ち時間後の回答を
+// 予約した場合は false を返します。
+func (s *patrolServiceImpl) applyAutoAnswer(projectPath, sessionID string, question *Question, d autoanswer.Decision) bool {
+	rec := autoanswer.Record{
+		Project:   projectPath,
+		SessionID: sessionID,
+		Source:    autoanswer.SourcePatrol,
+		Rule:      d.Rule,
+		Action:    d.Action,
+	}
+	if question != nil {
+		q := toAutoAnswerQuestion(*question)
+		rec.Header, rec.Question, rec.Options = q.Header, q.Text, q.Options
+	}
+
+	switch d.Action {
+	case autoanswer.ActionAnswer:
+		rec.Answer = d.Answer
+		if err := s.ResumeProject(projectPath, d.Answer); err != nil {
+			log.Printf("[PatrolService] Auto answer failed: path=%s, rule=%s, error=%v", projectPath, d.Rule, err)
+			return false
+		}
+		s.autoAnswer.Record(rec)
+		log.Printf("[PatrolService] Auto answered: path=%s, rule=%s, answer=%s", projectPath, d.Rule, d.Answer)
+		return true
+
+	case autoanswer.ActionEscalate:
+		s.autoAnswer.Escalate(projectPath+"\x00"+sessionID+"\x00"+rec.Question, rec)
+		s.broadcastState(PatrolEventProjectQuestion, projectPath)
+		log.Printf("[PatrolService] Question escalated: path=%s, rule=%s", projectPath, d.Rule)
+		return true
+
+	case autoanswer.ActionTimeout:
+		rec.Answer = d.Answer
+		s.scheduleAutoAnswer(projectPath, d.Timeout, func() {
+			s.autoAnswerOnTimeout(projectPath, sessionID, rec)
+		})
+		log.Printf("[PatrolService] Auto answer scheduled: path=%s, rule=%s, after=%s", projectPath, d.Rule, d.Timeout)
+	}
+	return false
+}
+
+// scheduleAutoAnswer は待ち時間後の自動回答を予約します。同じプロジェクトの前の予約は取り消します
+func (s *patrolServiceImpl) scheduleAutoAnswer(projectPath string, after time.Duration, fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.answerTimers == nil {
+		s.answerTimers = make(map[string]*time.Timer)
+	}
+	if t, ok := s.answerTimers[projectPath]; ok {
+		t.Stop()
+	}
+	s.answerTimers[projectPath] = time.AfterFunc(after, fn)
+}
+
+// cancelAutoAnswer はプロジェクトの待ち時間後の自動回答の予約を取り消します
+func (s *patrolServiceImpl) cancelAutoAnswer(projectPath string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.answerTimers[projectPath]; ok {
+		t.Stop()
+		delete(s.answerTimers, projectPath)
+	}
+}
+
+// autoAnswerOnTimeout は待ち時間が過ぎても同じ質問で承認待ちのままなら自動回答します。
+// 巡回は --resume で同じセッションを続けるため、セッション ID に加えて質問（見出し・本文・選択肢）も照合し、
+// 回答後に同じセッションが尋ねた次の質問へ前のルールの回答を送らないようにします
+func (s *patrolServiceImpl) autoAnswerOnTimeout(projectPath, sessionID string, rec autoanswer.Record) {
+	s.mu.RLock()
+	state, exists := s.states[projectPath]
+	waiting := exists && state.Status == StatusWaitingApproval && state.SessionID == sessionID &&
+		state.Question != nil && sameQuestion(toAutoAnswerQuestion(*state.Question), rec)
+	s.mu.RUnlock()
+	if !waiting {
+		log.Printf("[PatrolService] Auto answer skipped (answered or question changed): path=%s, rule=%s", projectPath, rec.Rule)
+		return
+	}
+
+	if err := s.ResumeProject(projectPath, rec.Answer); err != nil {
+		log.Printf("[PatrolService] Auto answer failed: path=%s, rule=%s, error=%v", projectPath, rec.Rule, err)
+		return
+	}
+	s.autoAnswer.Record(rec)
+	log.Printf("[PatrolService] Auto answered after timeout: path=%s, rule=%s, answer=%s", projectPath, rec.Rule, rec.Answer)
+}
+
+// sameQuestion は質問が自動回答の記録の質問と同じかを返します
+func sameQuestion(q autoanswer.Question, rec autoanswer.Record) bool {
+	return q.Header == rec.Header && q.Text == rec.Question && slices.Equal(q.Options, rec.Options)
+}
+
+// toAutoAnswerQuestion は質問を自動回答ルールの照合用に変換します
+func toAutoAnswerQuestion(q Question) autoanswer.Question {
+	options := make([]string, len(q.Options))
+	for i, o := range q.Options {
+		options[i] = o.Label
+	}
+	return autoanswer.Question{Header: q.Header, Text: q.Question, Options: options}
+}
+
 // updateState はプロジェクト状態を更新します
 func (s *patrolServiceImpl) updateState(projectPath string, fn func(st *ProjectState)) {
 	s.mu.Lock()
@@ -538,19 +725,9 @@ func (s *patrolServiceImpl) updateState(projectPath string, fn func(st *ProjectS
 	state.UpdatedAt = &now
 }
 
-// broadcast はPatrolEventを全subscriberに配信します
+// broadcast はPatrolEventをイベントバスの patrol.<type> へ発行します
 func (s *patrolServiceImpl) broadcast(event PatrolEvent) {
-	s.subMu.Lock()
-	defer s.subMu.Unlock()
-
-	for _, ch := range s.subscribers {
-		select {
-		case ch <- event:
-		default:
-			// バッファがいっぱいの場合はスキップ
-			log.Printf("[PatrolService] Subscriber buffer full, skipping event: type=%s", event.Type)
-		}
-	}
+	s.bus.Publish(events.TopicPatrolPrefix+event.Type, event)
 }
 
 // broadcastState はプロジェクト状態変更イベントを全subscriberに配信します
@@ -572,16 +749,9 @@ func (s *patrolServiceImpl) broadcastState(eventType, projectPath string) {
 }
 
 // getGitLog はプロジェクトのgit logを取得します
+// git の呼び出しはダッシュボードと共有の gitinfo に集約しています。
 func (s *patrolServiceImpl) getGitLog(projectPath string) (string, error) {
-	cmd := exec.Command("git", "log", "--oneline", "-5")
-	cmd.Dir = projectPath
-
-	output, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("failed to get git log: %w", err)
-	}
-
-	return string(output), nil
+	return gitinfo.RecentLog(context.Background(), projectPath, 5)
 }
 
 // getPendingTasks は未処理タスクのファイル名一覧を取得します
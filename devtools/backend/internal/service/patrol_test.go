@@ -6,9 +6,15 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sync"
 	"testing"
 	"time"
+
+	"ghostrunner/backend/internal/autoanswer"
+	"ghostrunner/backend/internal/callback"
+	"ghostrunner/backend/internal/events"
+	"ghostrunner/backend/internal/notify"
 )
 
 // mockClaudeService はテスト用のClaudeServiceモックです
@@ -75,6 +81,34 @@ func (m *patrolMockNtfyService) NotifyError(title, message string) {
 	m.notified = append(m.notified, struct{ title, message string }{title, message})
 }
 
+// patrolMockEventNotifier は種類・ボタン付きの通知を記録する EventNotifier のモックです
+type patrolMockEventNotifier struct {
+	patrolMockNtfyService
+	sent []notify.Message
+}
+
+func (m *patrolMockEventNotifier) Send(msg notify.Message) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+}
+
+// patrolMockActionIssuer は選択肢ごとに固定 URL のボタンを返す AnswerActionIssuer のモックです
+type patrolMockActionIssuer struct {
+	project, sessionID string
+	labels             []string
+}
+
+func (m *patrolMockActionIssuer) AnswerActions(projectPath, sessionID string, q callback.Question) []notify.Action {
+	labels := q.Options
+	m.project, m.sessionID, m.labels = projectPath, sessionID, labels
+	var actions []notify.Action
+	for _, l := range labels {
+		actions = append(actions, notify.Action{Label: l, URL: "https://example.com/api/callbacks/" + l})
+	}
+	return actions
+}
+
 // newTestPatrolService はテスト用のPatrolServiceを生成します
 func newTestPatrolService(t *testing.T, claude ClaudeService, ntfy NtfyService) (PatrolService, string) {
 	t.Helper()
@@ -616,7 +650,7 @@ func TestPatrolService_ResumeProject(t *testing.T) {
 				slots:         make(chan struct{}, MaxParallelSlots),
 				claudeService: claude,
 				configPath:    configPath,
-				subscribers:   make(map[int]chan PatrolEvent),
+				bus:           events.NewBus(nil),
 			}
 
 			projectPath := tt.setup(t, impl)
@@ -886,7 +920,7 @@ func TestPatrolService_MonitorStreamEvents(t *testing.T) {
 			slots:       make(chan struct{}, MaxParallelSlots),
 			ntfyService: ntfy,
 			configPath:  configPath,
-			subscribers: make(map[int]chan PatrolEvent),
+			bus:         events.NewBus(nil),
 		}
 
 		projectPath := "/test/project"
@@ -939,15 +973,54 @@ func TestPatrolService_MonitorStreamEvents(t *testing.T) {
 		}
 	})
 
+	t.Run("選択肢のある質問は回答ボタン付きで通知", func(t *testing.T) {
+		ntfy := &patrolMockEventNotifier{}
+		issuer := &patrolMockActionIssuer{}
+		impl := &patrolServiceImpl{
+			projects:      make(map[string]PatrolProject),
+			states:        make(map[string]*ProjectState),
+			slots:         make(chan struct{}, MaxParallelSlots),
+			ntfyService:   ntfy,
+			configPath:    filepath.Join(t.TempDir(), "config.json"),
+			bus:           events.NewBus(nil),
+			answerActions: issuer,
+		}
+		projectPath := "/test/project"
+		impl.states[projectPath] = &ProjectState{Project: PatrolProject{Path: projectPath}, Status: StatusRunning}
+
+		eventCh := make(chan StreamEvent, 1)
+		eventCh <- StreamEvent{
+			Type:      EventTypeQuestion,
+			SessionID: "session-abc",
+			Result: &CommandResult{
+				Questions: []Question{{Question: "Which DB?", Options: []Option{{Label: "PostgreSQL"}, {Label: "SQLite"}}}},
+			},
+		}
+		close(eventCh)
+
+		impl.monitorStreamEvents(projectPath, eventCh)
+
+		if issuer.project != projectPath || issuer.sessionID != "session-abc" || !slices.Equal(issuer.labels, []string{"PostgreSQL", "SQLite"}) {
+			t.Errorf("issuer called with %s %s %v", issuer.project, issuer.sessionID, issuer.labels)
+		}
+		if len(ntfy.sent) != 1 {
+			t.Fatalf("sent count: got %d, want 1", len(ntfy.sent))
+		}
+		msg := ntfy.sent[0]
+		if msg.Event != notify.EventPatrolQuestion || msg.Project != projectPath || len(msg.Actions) != 2 || msg.Actions[1].Label != "SQLite" {
+			t.Errorf("message: got %+v", msg)
+		}
+	})
+
 	t.Run("Errorイベントでエラー状態に遷移", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		configPath := filepath.Join(tmpDir, "config.json")
 		impl := &patrolServiceImpl{
-			projects:    make(map[string]PatrolProject),
-			states:      make(map[string]*ProjectState),
-			slots:       make(chan struct{}, MaxParallelSlots),
-			configPath:  configPath,
-			subscribers: make(map[int]chan PatrolEvent),
+			projects:   make(map[string]PatrolProject),
+			states:     make(map[string]*ProjectState),
+			slots:      make(chan struct{}, MaxParallelSlots),
+			configPath: configPath,
+			bus:        events.NewBus(nil),
 		}
 
 		projectPath := "/test/error-project"
@@ -983,11 +1056,11 @@ func TestPatrolService_MonitorStreamEvents(t *testing.T) {
 		tmpDir := t.TempDir()
 		configPath := filepath.Join(tmpDir, "config.json")
 		impl := &patrolServiceImpl{
-			projects:    make(map[string]PatrolProject),
-			states:      make(map[string]*ProjectState),
-			slots:       make(chan struct{}, MaxParallelSlots),
-			configPath:  configPath,
-			subscribers: make(map[int]chan PatrolEvent),
+			projects:   make(map[string]PatrolProject),
+			states:     make(map[string]*ProjectState),
+			slots:      make(chan struct{}, MaxParallelSlots),
+			configPath: configPath,
+			bus:        events.NewBus(nil),
 		}
 
 		projectPath := "/test/complete-project"
@@ -1021,6 +1094,223 @@ func TestPatrolService_MonitorStreamEvents(t *testing.T) {
 	})
 }
 
+// --- 自動回答テスト ---
+
+func TestPatrolService_MonitorStreamEvents_AutoAnswer(t *testing.T) {
+	rules := `
+rules:
+  - name: recommended
+    match: {option: "推奨"}
+    action: answer
+  - name: naming
+    match: {header: "^命名$"}
+    action: timeout
+    timeout: 20ms
+  - name: destructive
+    match: {question: "削除"}
+    action: escalate
+`
+	tests := []struct {
+		name          string
+		question      Question
+		wantAnswer    string
+		wantNotified  int
+		wantEscalated bool
+	}{
+		{
+			name:       "answer: 一致した選択肢で即時に再開",
+			question:   Question{Question: "どちらにしますか", Options: []Option{{Label: "A案"}, {Label: "B案（推奨）"}}},
+			wantAnswer: "B案（推奨）",
+		},
+		{
+			name:         "timeout: 通常通知の後に先頭の選択肢で再開",
+			question:     Question{Header: "命名", Question: "名前は？", Options: []Option{{Label: "foo"}, {Label: "bar"}}},
+			wantAnswer:   "foo",
+			wantNotified: 1,
+		},
+		{
+			name:          "escalate: 回答せずエラー通知",
+			question:      Question{Question: "ファイルを削除しますか", Options: []Option{{Label: "はい"}}},
+			wantNotified:  1,
+			wantEscalated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectPath := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(projectPath, ".ghostrunner"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(projectPath, autoanswer.RulesFile), []byte(rules), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			answered := make(chan string, 1)
+			claude := &mockClaudeService{
+				continueSessionStreamFn: func(ctx context.Context, project, sessionID, answer string, eventCh chan<- StreamEvent) error {
+					answered <- answer
+					close(eventCh)
+					return nil
+				},
+			}
+			ntfy := &patrolMockNtfyService{}
+			audit := autoanswer.NewAuditLog(filepath.Join(t.TempDir(), "auto_answers.jsonl"))
+			impl := &patrolServiceImpl{
+				projects:      make(map[string]PatrolProject),
+				states:        make(map[string]*ProjectState),
+				slots:         make(chan struct{}, MaxParallelSlots),
+				claudeService: claude,
+				ntfyService:   ntfy,
+				configPath:    filepath.Join(t.TempDir(), "config.json"),
+				bus:           events.NewBus(nil),
+				autoAnswer:    autoanswer.NewEngine(audit, ntfy, nil),
+			}
+
+			eventCh := make(chan StreamEvent, 1)
+			eventCh <- StreamEvent{
+				Type:      EventTypeQuestion,
+				SessionID: "session-abc",
+				Result:    &CommandResult{Questions: []Question{tt.question}},
+			}
+			close(eventCh)
+			impl.monitorStreamEvents(projectPath, eventCh)
+
+			if tt.wantAnswer != "" {
+				select {
+				case got := <-answered:
+					if got != tt.wantAnswer {
+						t.Errorf("answer: got %q, want %q", got, tt.wantAnswer)
+					}
+				case <-time.After(2 * time.Second):
+					t.Fatal("session was not resumed")
+				}
+			}
+
+			ntfy.mu.Lock()
+			notified := len(ntfy.notified)
+			ntfy.mu.Unlock()
+			if notified != tt.wantNotified {
+				t.Errorf("notify count: got %d, want %d", notified, tt.wantNotified)
+			}
+
+			// 自動回答の記録は ResumeProject の後に書かれるため、少し待ってから確認する
+			var result *autoanswer.Result
+			for i := 0; i < 100; i++ {
+				var err error
+				if result, err = audit.Query(autoanswer.Query{}); err != nil {
+					t.Fatal(err)
+				}
+				if result.Total > 0 {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			if result.Total != 1 {
+				t.Fatalf("audit records: got %d, want 1", result.Total)
+			}
+			if got := result.Records[0]; (got.Action == autoanswer.ActionEscalate) != tt.wantEscalated || got.Answer != tt.wantAnswer {
+				t.Errorf("audit record: %+v", got)
+			}
+		})
+	}
+}
+
+func TestPatrolService_AutoAnswerTimeout_SkipsNewQuestionInSameSession(t *testing.T) {
+	rules := `
+rules:
+  - name: naming
+    match: {header: "^命名$"}
+    action: timeout
+    timeout: 50ms
+`
+	projectPath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(projectPath, ".ghostrunner"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectPath, autoanswer.RulesFile), []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	q1 := Question{Header: "命名", Question: "名前は？", Options: []Option{{Label: "foo"}, {Label: "bar"}}}
+	q2 := Question{Header: "DB", Question: "どれにしますか？", Options: []Option{{Label: "PostgreSQL"}, {Label: "MySQL"}}}
+
+	// ユーザーが Q1 に回答すると、--resume した同じセッションが Q2 を尋ねる
+	var mu sync.Mutex
+	var answers []string
+	asked := make(chan struct{})
+	var askedOnce sync.Once
+	claude := &mockClaudeService{
+		continueSessionStreamFn: func(ctx context.Context, project, sessionID, answer string, eventCh chan<- StreamEvent) error {
+			mu.Lock()
+			answers = append(answers, answer)
+			mu.Unlock()
+			eventCh <- StreamEvent{Type: EventTypeQuestion, SessionID: sessionID, Result: &CommandResult{Questions: []Question{q2}}}
+			close(eventCh)
+			askedOnce.Do(func() { close(asked) })
+			return nil
+		},
+	}
+	ntfy := &patrolMockNtfyService{}
+	audit := autoanswer.NewAuditLog(filepath.Join(t.TempDir(), "auto_answers.jsonl"))
+	impl := &patrolServiceImpl{
+		projects:      make(map[string]PatrolProject),
+		states:        make(map[string]*ProjectState),
+		slots:         make(chan struct{}, MaxParallelSlots),
+		claudeService: claude,
+		ntfyService:   ntfy,
+		configPath:    filepath.Join(t.TempDir(), "config.json"),
+		bus:           events.NewBus(nil),
+		autoAnswer:    autoanswer.NewEngine(audit, ntfy, nil),
+	}
+
+	eventCh := make(chan StreamEvent, 1)
+	eventCh <- StreamEvent{Type: EventTypeQuestion, SessionID: "session-abc", Result: &CommandResult{Questions: []Question{q1}}}
+	close(eventCh)
+	impl.monitorStreamEvents(projectPath, eventCh)
+
+	if err := impl.ResumeProject(projectPath, "bar"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-asked:
+	case <-time.After(2 * time.Second):
+		t.Fatal("session was not resumed")
+	}
+	// Q2 の承認待ちになるまで待つ
+	for i := 0; i < 100; i++ {
+		if st := impl.GetStates()[projectPath]; st.Status == StatusWaitingApproval && st.Question != nil && st.Question.Header == "DB" {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Q1 の待ち時間が過ぎても Q2 には回答しない
+	time.Sleep(150 * time.Millisecond)
+	mu.Lock()
+	got := slices.Clone(answers)
+	mu.Unlock()
+	if !slices.Equal(got, []string{"bar"}) {
+		t.Errorf("answers = %v, want [bar]", got)
+	}
+	if st := impl.GetStates()[projectPath]; st.Status != StatusWaitingApproval || st.Question == nil || st.Question.Header != "DB" {
+		t.Errorf("state = %+v, want waiting on Q2", st)
+	}
+
+	// 予約が取り消されずに発火した場合も、質問が変わっていれば回答しない
+	rec := autoanswer.Record{Rule: "naming", Header: q1.Header, Question: q1.Question, Options: []string{"foo", "bar"}, Answer: "foo"}
+	impl.autoAnswerOnTimeout(projectPath, "session-abc", rec)
+	if st := impl.GetStates()[projectPath]; st.Status != StatusWaitingApproval {
+		t.Errorf("status = %s, want waiting_approval", st.Status)
+	}
+	impl.mu.RLock()
+	pending := len(impl.answerTimers)
+	impl.mu.RUnlock()
+	if pending != 0 {
+		t.Errorf("answer timers = %d, want 0 after the answered question", pending)
+	}
+}
+
 // --- GetStates テスト ---
 
 func TestPatrolService_GetStates(t *testing.T) {
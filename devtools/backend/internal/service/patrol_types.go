@@ -9,6 +9,8 @@ const (
 	MaxParallelSlots = 5
 	// PollingInterval はポーリングの間隔
 	PollingInterval = 5 * time.Minute
+	// patrolSubscriberBuffer は巡回イベント購読者1人あたりのバッファ件数
+	patrolSubscriberBuffer = 100
 )
 
 // PatrolStatus はプロジェクトの巡回状態を表します
@@ -28,21 +28,29 @@ type SummarizeService interface {
 // summarizeExecFunc は要約用CLIの実行を抽象化します（テストでモック可能）
 type summarizeExecFunc func(ctx context.Context, prompt string) (string, error)
 
+// summarizeServiceImpl はプロンプトをモデルへ渡して要約する SummarizeProvider です。
+// モデルの呼び出し（claude CLI・HTTP API）は exec で差し替えます
 type summarizeServiceImpl struct {
+	name    string
 	exec    summarizeExecFunc
 	timeout time.Duration
 }
 
 // NewSummarizeService は claude CLI(haikuモデル)を用いる本番用SummarizeServiceを生成します
 func NewSummarizeService() SummarizeService {
-	s := &summarizeServiceImpl{timeout: summarizeTimeout}
+	s := &summarizeServiceImpl{name: SummarizeProviderClaude, timeout: summarizeTimeout}
 	s.exec = runClaudeSummarize
 	return s
 }
 
 // newSummarizeServiceWithExec はCLI実行を差し替えたSummarizeServiceを生成します（テスト用）
 func newSummarizeServiceWithExec(exec summarizeExecFunc, timeout time.Duration) SummarizeService {
-	return &summarizeServiceImpl{exec: exec, timeout: timeout}
+	return &summarizeServiceImpl{name: "test", exec: exec, timeout: timeout}
+}
+
+// Name はプロバイダー名を返します
+func (s *summarizeServiceImpl) Name() string {
+	return s.name
 }
 
 // SummarizeIdle は会話末尾から日本語1行要約を返します
@@ -57,14 +65,14 @@ func (s *summarizeServiceImpl) SummarizeIdle(ctx context.Context, lastAssistant,
 	ctx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
 
-	log.Printf("[SummarizeService] SummarizeIdle started")
+	log.Printf("[SummarizeService] SummarizeIdle started: provider=%s", s.name)
 	out, err := s.exec(ctx, prompt)
 	if err != nil {
 		return "", fmt.Errorf("failed to summarize idle: %w", err)
 	}
 
 	summary := firstLine(out)
-	log.Printf("[SummarizeService] SummarizeIdle completed: summary=%s", summary)
+	log.Printf("[SummarizeService] SummarizeIdle completed: provider=%s, summary=%s", s.name, summary)
 	return summary, nil
 }
 
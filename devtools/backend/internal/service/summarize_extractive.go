@@ -0,0 +1,95 @@
+// Package service はビジネスロジックを提供します
+package service
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+)
+
+// extractiveMaxRunes は抽出要約の最大文字数です（超えた分は … で切り詰めます）
+const extractiveMaxRunes = 40
+
+// extractiveSummarizer はモデルを使わず会話末尾から1文を抜き出す SummarizeProvider です。
+// 同じ入力には常に同じ要約を返し、ネットワーク・クォータに依存しないため最後のフォールバックに使います
+type extractiveSummarizer struct{}
+
+// NewExtractiveSummarizer は抽出要約の SummarizeProvider を生成します
+func NewExtractiveSummarizer() SummarizeProvider {
+	return extractiveSummarizer{}
+}
+
+// Name はプロバイダー名を返します
+func (extractiveSummarizer) Name() string {
+	return SummarizeProviderExtractive
+}
+
+// SummarizeIdle はアシスタントの最後の発言（空ならユーザーの直近の発言）から、
+// 最後の質問文（？/? を含む文）を、無ければ最後の文を抜き出して返します。
+// コードブロックと Markdown の記号は除きます
+func (extractiveSummarizer) SummarizeIdle(ctx context.Context, lastAssistant, lastPrompt string) (string, error) {
+	text := lastAssistant
+	if strings.TrimSpace(text) == "" {
+		text = lastPrompt
+	}
+
+	var last, question string
+	inFence := false
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		for _, sentence := range splitSentences(cleanMarkdownLine(line)) {
+			last = sentence
+			if strings.ContainsAny(sentence, "?？") {
+				question = sentence
+			}
+		}
+	}
+	if question != "" {
+		return truncateRunes(question, extractiveMaxRunes), nil
+	}
+	return truncateRunes(last, extractiveMaxRunes), nil
+}
+
+// cleanMarkdownLine は行頭の見出し・箇条書き・引用・番号の記号と、強調・コードの記号を取り除きます
+func cleanMarkdownLine(line string) string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimLeft(line, "#>-*+ \t")
+	if i := strings.IndexAny(line, ".)"); i > 0 && i <= 3 && strings.Trim(line[:i], "0123456789") == "" {
+		line = line[i+1:]
+	}
+	line = strings.NewReplacer("**", "", "__", "", "`", "").Replace(line)
+	return strings.TrimSpace(line)
+}
+
+// splitSentences は行を「。」「！」「？」等の文末で文に分けます（空の文は除きます）
+func splitSentences(line string) []string {
+	var out []string
+	start := 0
+	for i, r := range line {
+		if strings.ContainsRune("。！？!?", r) {
+			end := i + utf8.RuneLen(r)
+			if s := strings.TrimSpace(line[start:end]); s != "" {
+				out = append(out, s)
+			}
+			start = end
+		}
+	}
+	if s := strings.TrimSpace(line[start:]); s != "" {
+		out = append(out, s)
+	}
+	return out
+}
+
+// truncateRunes は s を最大 n 文字に切り詰めます（切り詰めた場合は末尾に … を付けます）
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
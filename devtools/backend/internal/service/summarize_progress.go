@@ -0,0 +1,128 @@
+// Package service はビジネスロジックを提供します
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+// progressMaxRunes は抽出による進捗要約の「いま」「済」それぞれの最大文字数です
+const progressMaxRunes = 40
+
+// ProgressInput は実行中セッションの進捗要約の材料です（会話ログの前回要約以降の差分）
+type ProgressInput struct {
+	// ToolCalls は時系列のツール呼び出し（"Edit: internal/foo.go" 形式）です
+	ToolCalls []string
+	// EditedFiles はこれまでに編集・作成したファイル（前回までの分を含む）です
+	EditedFiles []string
+	// LastAssistant はアシスタントの最後の発言です
+	LastAssistant string
+	// Previous は前回の進捗要約です（初回は空）
+	Previous string
+}
+
+// Empty は要約の材料が無いかを返します
+func (in ProgressInput) Empty() bool {
+	return len(in.ToolCalls) == 0 && strings.TrimSpace(in.LastAssistant) == ""
+}
+
+// ProgressSummarizeService は実行中セッションの「いま何をしているか／ここまでに済んだこと」を
+// 日本語1行に要約するサービスです。SummarizeProvider はすべて実装します
+type ProgressSummarizeService interface {
+	// SummarizeProgress は差分と前回の要約から「いま: …／済: …」形式の1行要約を返します。
+	// 材料が無い場合はモデルを呼ばず空文字を返します
+	SummarizeProgress(ctx context.Context, in ProgressInput) (string, error)
+}
+
+// SummarizeProgress は差分と前回の要約からモデルで進捗の1行要約を返します
+func (s *summarizeServiceImpl) SummarizeProgress(ctx context.Context, in ProgressInput) (string, error) {
+	if in.Empty() {
+		return "", nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	log.Printf("[SummarizeService] SummarizeProgress started: provider=%s, tools=%d", s.name, len(in.ToolCalls))
+	out, err := s.exec(ctx, buildProgressPrompt(in))
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize progress: %w", err)
+	}
+
+	summary := firstLine(out)
+	log.Printf("[SummarizeService] SummarizeProgress completed: provider=%s, summary=%s", s.name, summary)
+	return summary, nil
+}
+
+// SummarizeProgress は直近のツール呼び出し（無ければ最後の発言）を「いま」、
+// 編集したファイル（無ければ前回の要約の「済」）を「済」として組み立てます
+func (extractiveSummarizer) SummarizeProgress(ctx context.Context, in ProgressInput) (string, error) {
+	if in.Empty() {
+		return "", nil
+	}
+
+	current := ""
+	if n := len(in.ToolCalls); n > 0 {
+		current = shortenToolCall(in.ToolCalls[n-1])
+	} else {
+		current, _ = extractiveSummarizer{}.SummarizeIdle(ctx, in.LastAssistant, "")
+	}
+
+	done := ""
+	switch n := len(in.EditedFiles); {
+	case n == 1:
+		done = filepath.Base(in.EditedFiles[0]) + " を編集"
+	case n > 1:
+		done = fmt.Sprintf("%s ほか%dファイルを編集", filepath.Base(in.EditedFiles[n-1]), n-1)
+	default:
+		if _, prev, ok := strings.Cut(in.Previous, "済: "); ok {
+			done = prev
+		}
+	}
+
+	summary := "いま: " + truncateRunes(current, progressMaxRunes)
+	if done != "" {
+		summary += "／済: " + truncateRunes(done, progressMaxRunes)
+	}
+	return summary, nil
+}
+
+// shortenToolCall は "Edit: /abs/path/foo.go" のファイルパスをファイル名に縮めます（コマンド等はそのまま）
+func shortenToolCall(call string) string {
+	name, target, ok := strings.Cut(call, ": ")
+	if !ok || !strings.HasPrefix(target, "/") || strings.ContainsAny(target, " \n") {
+		return call
+	}
+	return name + ": " + filepath.Base(target)
+}
+
+// buildProgressPrompt は進捗要約のプロンプトを構築します
+func buildProgressPrompt(in ProgressInput) string {
+	var b strings.Builder
+	b.WriteString("実行中のコーディング作業のログから、「いま何をしているか」と「ここまでに済んだこと」を")
+	b.WriteString("「いま: …／済: …」の形式で日本語1行(60字程度)に要約。前置き不要。\n\n")
+	if in.Previous != "" {
+		b.WriteString("前回の要約:\n")
+		b.WriteString(in.Previous)
+		b.WriteString("\n\n")
+	}
+	if len(in.EditedFiles) > 0 {
+		b.WriteString("これまでに編集したファイル:\n")
+		b.WriteString(strings.Join(in.EditedFiles, "\n"))
+		b.WriteString("\n\n")
+	}
+	if len(in.ToolCalls) > 0 {
+		b.WriteString("前回以降のツール呼び出し（古い順）:\n")
+		b.WriteString(strings.Join(in.ToolCalls, "\n"))
+		b.WriteString("\n\n")
+	}
+	if in.LastAssistant != "" {
+		b.WriteString("アシスタントの最後の発言:\n")
+		b.WriteString(in.LastAssistant)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,201 @@
+// Package service はビジネスロジックを提供します
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// 要約プロバイダー名（サーバー設定 summarizer.providers に書く値）
+const (
+	// SummarizeProviderClaude は claude CLI（haiku）です
+	SummarizeProviderClaude = "claude"
+	// SummarizeProviderOpenAI は OpenAI の Chat Completions API です（OPENAI_API_KEY）
+	SummarizeProviderOpenAI = "openai"
+	// SummarizeProviderGemini は Gemini の generateContent API です（GEMINI_API_KEY）
+	SummarizeProviderGemini = "gemini"
+	// SummarizeProviderLocal は OpenAI 互換のローカルエンドポイント（Ollama・LM Studio・llama.cpp server 等）です
+	SummarizeProviderLocal = "local"
+	// SummarizeProviderExtractive はモデルを使わない抽出要約です（常に成功する最後の手段）
+	SummarizeProviderExtractive = "extractive"
+)
+
+const (
+	// defaultOpenAISummarizeURL は OpenAI API のベース URL です
+	defaultOpenAISummarizeURL = "https://api.openai.com/v1"
+	// defaultOpenAISummarizeModel は OpenAI の要約モデルの既定値です
+	defaultOpenAISummarizeModel = "gpt-4o-mini"
+	// defaultGeminiSummarizeURL は Gemini API のベース URL です
+	defaultGeminiSummarizeURL = "https://generativelanguage.googleapis.com/v1beta"
+	// defaultGeminiSummarizeModel は Gemini の要約モデルの既定値です
+	defaultGeminiSummarizeModel = "gemini-2.0-flash"
+	// summarizeMaxTokens は要約の出力トークンの上限です（1行要約のため小さくします）
+	summarizeMaxTokens = 100
+	// maxSummarizeResponseBytes は要約 API のレスポンスとして読む上限です
+	maxSummarizeResponseBytes = 1 << 20
+)
+
+// SummarizeProvider は名前付きの SummarizeService です。
+// dashboard.Summarizer / dashboard.ProgressSummarizer は複数のプロバイダーを順に試し、エラーになったら次へフォールバックします
+type SummarizeProvider interface {
+	SummarizeService
+	ProgressSummarizeService
+	// Name はプロバイダー名（claude / openai / gemini / local / extractive）を返します
+	Name() string
+}
+
+// SummarizeEndpoint は HTTP の要約プロバイダーの接続先です。空の項目はプロバイダーの既定値を使います
+type SummarizeEndpoint struct {
+	// BaseURL は API のベース URL（local は必須。例: http://localhost:11434/v1）
+	BaseURL string
+	// Model はモデル名
+	Model string
+	// APIKey は API キー。空なら環境変数（OPENAI_API_KEY / GEMINI_API_KEY / SUMMARIZER_LOCAL_API_KEY）を使います
+	APIKey string
+}
+
+// NewSummarizeProvider は名前に対応する要約プロバイダーを生成します。
+// openai / gemini は API キーが無い場合、local は BaseURL が無い場合にエラーを返します
+func NewSummarizeProvider(name string, ep SummarizeEndpoint) (SummarizeProvider, error) {
+	client := &http.Client{Timeout: summarizeTimeout}
+	switch name {
+	case SummarizeProviderClaude:
+		return NewSummarizeService().(SummarizeProvider), nil
+	case SummarizeProviderOpenAI:
+		key := firstNonEmpty(ep.APIKey, os.Getenv("OPENAI_API_KEY"))
+		if key == "" {
+			return nil, fmt.Errorf("summarizer %s: OPENAI_API_KEY is not set", name)
+		}
+		exec := chatCompletionsExec(client, firstNonEmpty(ep.BaseURL, defaultOpenAISummarizeURL), key, firstNonEmpty(ep.Model, defaultOpenAISummarizeModel))
+		return &summarizeServiceImpl{name: name, exec: exec, timeout: summarizeTimeout}, nil
+	case SummarizeProviderGemini:
+		key := firstNonEmpty(ep.APIKey, os.Getenv("GEMINI_API_KEY"))
+		if key == "" {
+			return nil, fmt.Errorf("summarizer %s: GEMINI_API_KEY is not set", name)
+		}
+		exec := geminiGenerateExec(client, firstNonEmpty(ep.BaseURL, defaultGeminiSummarizeURL), key, firstNonEmpty(ep.Model, defaultGeminiSummarizeModel))
+		return &summarizeServiceImpl{name: name, exec: exec, timeout: summarizeTimeout}, nil
+	case SummarizeProviderLocal:
+		if ep.BaseURL == "" {
+			return nil, fmt.Errorf("summarizer %s: base URL is required", name)
+		}
+		key := firstNonEmpty(ep.APIKey, os.Getenv("SUMMARIZER_LOCAL_API_KEY"))
+		exec := chatCompletionsExec(client, ep.BaseURL, key, ep.Model)
+		return &summarizeServiceImpl{name: name, exec: exec, timeout: summarizeTimeout}, nil
+	case SummarizeProviderExtractive:
+		return NewExtractiveSummarizer(), nil
+	}
+	return nil, fmt.Errorf("unknown summarizer provider: %s", name)
+}
+
+// chatCompletionsExec は OpenAI 互換の POST <baseURL>/chat/completions で要約する exec を返します。
+// apiKey が空なら Authorization ヘッダーを付けません（認証の無いローカルサーバー向け）
+func chatCompletionsExec(client *http.Client, baseURL, apiKey, model string) summarizeExecFunc {
+	endpoint := strings.TrimSuffix(baseURL, "/") + "/chat/completions"
+	return func(ctx context.Context, prompt string) (string, error) {
+		reqBody := map[string]any{
+			"messages":    []map[string]string{{"role": "user", "content": prompt}},
+			"max_tokens":  summarizeMaxTokens,
+			"temperature": 0,
+		}
+		if model != "" {
+			reqBody["model"] = model
+		}
+		var resp struct {
+			Choices []struct {
+				Message struct {
+					Content string `json:"content"`
+				} `json:"message"`
+			} `json:"choices"`
+		}
+		header := http.Header{}
+		if apiKey != "" {
+			header.Set("Authorization", "Bearer "+apiKey)
+		}
+		if err := postSummarizeJSON(ctx, client, endpoint, header, reqBody, &resp); err != nil {
+			return "", err
+		}
+		if len(resp.Choices) == 0 {
+			return "", fmt.Errorf("summarize response has no choices")
+		}
+		return resp.Choices[0].Message.Content, nil
+	}
+}
+
+// geminiGenerateExec は POST <baseURL>/models/<model>:generateContent で要約する exec を返します
+func geminiGenerateExec(client *http.Client, baseURL, apiKey, model string) summarizeExecFunc {
+	endpoint := strings.TrimSuffix(baseURL, "/") + "/models/" + url.PathEscape(model) + ":generateContent"
+	return func(ctx context.Context, prompt string) (string, error) {
+		reqBody := map[string]any{
+			"contents":         []map[string]any{{"parts": []map[string]string{{"text": prompt}}}},
+			"generationConfig": map[string]any{"maxOutputTokens": summarizeMaxTokens, "temperature": 0},
+		}
+		var resp struct {
+			Candidates []struct {
+				Content struct {
+					Parts []struct {
+						Text string `json:"text"`
+					} `json:"parts"`
+				} `json:"content"`
+			} `json:"candidates"`
+		}
+		header := http.Header{}
+		header.Set("x-goog-api-key", apiKey)
+		if err := postSummarizeJSON(ctx, client, endpoint, header, reqBody, &resp); err != nil {
+			return "", err
+		}
+		if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+			return "", fmt.Errorf("summarize response has no candidates")
+		}
+		return resp.Candidates[0].Content.Parts[0].Text, nil
+	}
+}
+
+// postSummarizeJSON は reqBody を JSON で POST し、2xx のレスポンスを out へデコードします
+func postSummarizeJSON(ctx context.Context, client *http.Client, endpoint string, header http.Header, reqBody, out any) error {
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header = header
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send summarize request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSummarizeResponseBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read summarize response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("summarize API returned status %d: %s", resp.StatusCode, truncateRunes(strings.TrimSpace(string(data)), 200))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse summarize response: %w", err)
+	}
+	return nil
+}
+
+// firstNonEmpty は最初の空でない文字列を返します
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
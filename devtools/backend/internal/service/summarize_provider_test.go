@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewSummarizeProvider_OpenAI互換のローカルエンドポイント(t *testing.T) {
+	var gotPath, gotAuth string
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.Path, r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"  DB の選択待ち\n補足"}}]}`))
+	}))
+	defer srv.Close()
+	t.Setenv("SUMMARIZER_LOCAL_API_KEY", "")
+
+	p, err := NewSummarizeProvider(SummarizeProviderLocal, SummarizeEndpoint{BaseURL: srv.URL + "/v1/", Model: "qwen"})
+	if err != nil {
+		t.Fatalf("NewSummarizeProvider: %v", err)
+	}
+	got, err := p.SummarizeIdle(context.Background(), "どのDBにしますか", "")
+	if err != nil {
+		t.Fatalf("SummarizeIdle: %v", err)
+	}
+	if got != "DB の選択待ち" || p.Name() != SummarizeProviderLocal {
+		t.Errorf("summary = %q, name = %q", got, p.Name())
+	}
+	if gotPath != "/v1/chat/completions" || gotAuth != "" || gotBody["model"] != "qwen" {
+		t.Errorf("request: path=%s auth=%q body=%v", gotPath, gotAuth, gotBody)
+	}
+}
+
+func TestNewSummarizeProvider_Gemini(t *testing.T) {
+	var gotPath, gotKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotKey = r.URL.Path, r.Header.Get("x-goog-api-key")
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"承認待ち"}]}}]}`))
+	}))
+	defer srv.Close()
+
+	p, err := NewSummarizeProvider(SummarizeProviderGemini, SummarizeEndpoint{BaseURL: srv.URL, APIKey: "k"})
+	if err != nil {
+		t.Fatalf("NewSummarizeProvider: %v", err)
+	}
+	got, err := p.SummarizeIdle(context.Background(), "進めてよいですか", "")
+	if err != nil || got != "承認待ち" {
+		t.Fatalf("SummarizeIdle = %q, %v", got, err)
+	}
+	if gotPath != "/models/"+defaultGeminiSummarizeModel+":generateContent" || gotKey != "k" {
+		t.Errorf("request: path=%s key=%q", gotPath, gotKey)
+	}
+}
+
+func TestNewSummarizeProvider_エラー(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"rate limited"}`, http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	p, err := NewSummarizeProvider(SummarizeProviderOpenAI, SummarizeEndpoint{BaseURL: srv.URL, APIKey: "k"})
+	if err != nil {
+		t.Fatalf("NewSummarizeProvider: %v", err)
+	}
+	if _, err := p.SummarizeIdle(context.Background(), "質問", ""); err == nil || !strings.Contains(err.Error(), "429") {
+		t.Errorf("err = %v, want status 429", err)
+	}
+
+	t.Setenv("OPENAI_API_KEY", "")
+	if _, err := NewSummarizeProvider(SummarizeProviderOpenAI, SummarizeEndpoint{}); err == nil {
+		t.Errorf("openai without key should fail")
+	}
+	if _, err := NewSummarizeProvider(SummarizeProviderLocal, SummarizeEndpoint{}); err == nil {
+		t.Errorf("local without base URL should fail")
+	}
+	if _, err := NewSummarizeProvider("unknown", SummarizeEndpoint{}); err == nil {
+		t.Errorf("unknown provider should fail")
+	}
+}
+
+func TestExtractiveSummarizer(t *testing.T) {
+	tests := []struct {
+		name          string
+		lastAssistant string
+		lastPrompt    string
+		want          string
+	}{
+		{
+			name:          "最後の質問文を抜き出す",
+			lastAssistant: "## 方針\n実装を確認しました。\n\n- **認証**はJWTを想定しています。どちらの方式にしますか？\n1. 後で決めます。",
+			want:          "どちらの方式にしますか？",
+		},
+		{
+			name:          "質問が無ければ最後の文",
+			lastAssistant: "テストを追加しました。ビルドも通っています。\n```go\nfunc x() {}\n```",
+			want:          "ビルドも通っています。",
+		},
+		{
+			name:       "アシスタントが空ならユーザーの発言",
+			lastPrompt: "`main` にマージして",
+			want:       "main にマージして",
+		},
+		{
+			name:          "長い文は切り詰める",
+			lastAssistant: strings.Repeat("あ", 60) + "？",
+			want:          strings.Repeat("あ", extractiveMaxRunes-1) + "…",
+		},
+		{name: "双方空", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewExtractiveSummarizer().SummarizeIdle(context.Background(), tt.lastAssistant, tt.lastPrompt)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractiveSummarizer_SummarizeProgress(t *testing.T) {
+	tests := []struct {
+		name string
+		in   ProgressInput
+		want string
+	}{
+		{
+			name: "直近のツール呼び出しと編集ファイル",
+			in: ProgressInput{
+				ToolCalls:   []string{"Edit: /Users/x/app/a.go", "Bash: go test ./..."},
+				EditedFiles: []string{"/Users/x/app/a.go", "/Users/x/app/b.go"},
+			},
+			want: "いま: Bash: go test ./...／済: b.go ほか1ファイルを編集",
+		},
+		{
+			name: "ファイルパスはファイル名に縮める",
+			in: ProgressInput{
+				ToolCalls:   []string{"Edit: /Users/x/app/a.go"},
+				EditedFiles: []string{"/Users/x/app/a.go"},
+			},
+			want: "いま: Edit: a.go／済: a.go を編集",
+		},
+		{
+			name: "ツール呼び出しが無ければ最後の発言、編集が無ければ前回の済",
+			in: ProgressInput{
+				LastAssistant: "テストが通りました。次はドキュメントを更新します。",
+				Previous:      "いま: Bash: go test／済: API を実装",
+			},
+			want: "いま: 次はドキュメントを更新します。／済: API を実装",
+		},
+		{name: "材料なし", in: ProgressInput{Previous: "いま: x"}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewExtractiveSummarizer().SummarizeProgress(context.Background(), tt.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 // Package service はビジネスロジックを提供します
 package service
 
+import "ghostrunner/backend/internal/callback"
+
 // AllowedCommands は許可されたスラッシュコマンドのリストです
 var AllowedCommands = map[string]bool{
 	"plan":     true,
@@ -39,6 +41,15 @@ type Question struct {
 	MultiSelect bool     `json:"multiSelect"`
 }
 
+// CallbackQuestion は通知のボタンの発行・照合に使う形（見出し・本文・選択肢のラベル）に変換します
+func (q Question) CallbackQuestion() callback.Question {
+	options := make([]string, len(q.Options))
+	for i, o := range q.Options {
+		options[i] = o.Label
+	}
+	return callback.Question{Header: q.Header, Text: q.Question, Options: options}
+}
+
 // Option は質問の選択肢を表します
 type Option struct {
 	Label       string `json:"label"`
@@ -0,0 +1,39 @@
+// Package tasks はカンバン（開発/実装 配下のレーン）のタスクファイルを API から操作する機能を提供する。
+//
+// # 概要
+//
+// タスクはこれまで手作業またはエージェントによるファイル編集でのみ作成・移動され、API からは
+// .md のファイル名一覧（FilesHandler）と件数（dashboard の countKanban）しか扱えなかった。本パッケージは
+// テンプレートからの作成、内容の取得と楽観的排他付きの更新、レーン間の移動、レーン内の並び替え、削除を提供する。
+//
+// レーンとディレクトリの対応:
+//
+//   - reviewing: 開発/実装/レビュー
+//   - waiting: 開発/実装/実装待ち
+//   - running: 開発/実装/実行中
+//   - done: 開発/実装/完了
+//   - archived: 開発/実装/完了/アーカイブ（テンプレートの既存フォルダ構成に合わせる）
+//
+// # 主要な型・関数
+//
+//   - Service / NewService: タスクの一覧・取得・作成・更新・移動・並び替え・削除
+//   - Lane / Lanes: レーン（値は dashboard.KanbanCounts の JSON キーと揃える）
+//   - Task / LaneTasks / Board: タスク・レーン・プロジェクト全体
+//   - ErrValidation / ErrNotFound / ErrConflict: 400 / 404 / 409 に対応するエラー
+//   - WithPublisher / Event: 変更操作をイベントバスへ発行する（task.created / updated / moved / reordered / deleted）
+//
+// # 設計方針
+//
+//   - ファイルシステムを唯一の真実源とし、データベースや索引は持たない
+//   - 全操作で projectPath を登録済みプロジェクト（patrol_projects.json）と照合する（AnswerQuestion と同じ方針）。
+//     ファイル名はパス区切りを含まない .md のみ許可し、レーン外への書き込みを防ぐ
+//   - etag は内容の SHA-256 先頭16桁。mtime は秒精度で同一秒の編集を取りこぼすため内容で判定する。
+//     更新・削除では必須、移動では任意
+//   - 更新は temp + rename、作成は temp + link、移動は link + remove（どちらも既存ファイルを上書きしない）。
+//     本サービス経由の変更は mutex で直列化し、エージェントの直接編集とは etag 照合で競合を検出する
+//   - 並び順はレーンごとの .order（1行1ファイル名）に保存する。.order に無いファイルは後ろにファイル名順で並ぶ
+//   - テンプレートは組み込み（plan / blank）と、プロジェクトの 開発/テンプレート/<name>.md（優先）。
+//     {{title}} / {{body}} / {{date}} を置換する
+//   - イベントは変更が成功した後にのみ発行し、本文（content）は含めない。エージェントの直接編集は
+//     発行しない（ダッシュボード状態の dashboard.state で件数の変化として届く）
+package tasks
@@ -0,0 +1,620 @@
+package tasks
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"ghostrunner/backend/internal/events"
+	"ghostrunner/backend/internal/projects"
+)
+
+const (
+	// orderFileName はレーン内の並び順を保存するファイル名です（1行1ファイル名）
+	orderFileName = ".order"
+	// maxSlugRunes は作成時のファイル名に使うタイトルの最大文字数です
+	maxSlugRunes = 60
+)
+
+// Service はカンバンのタスクファイル操作を提供します。
+// すべての操作は登録済みプロジェクト（patrol_projects.json）のみを対象とします。
+type Service interface {
+	// List はプロジェクトの全レーンのタスク一覧を返します（本文は含めない）
+	List(projectPath string) (*Board, error)
+	// Get はタスクを本文付きで返します
+	Get(projectPath string, lane Lane, name string) (*Task, error)
+	// Templates は作成に使えるテンプレート一覧を返します
+	Templates(projectPath string) ([]Template, error)
+	// Create はテンプレートからタスクを作成します
+	Create(req CreateRequest) (*Task, error)
+	// Update はタスク本文を更新します（etag 不一致は ErrConflict）
+	Update(req UpdateRequest) (*Task, error)
+	// Move はタスクを別レーンへ移動します（移動先に同名があれば ErrConflict）
+	Move(req MoveRequest) (*Task, error)
+	// Reorder はレーン内の並び順を保存します
+	Reorder(req ReorderRequest) (*LaneTasks, error)
+	// Delete はタスクを削除します（etag 不一致は ErrConflict）
+	Delete(req DeleteRequest) error
+}
+
+type serviceImpl struct {
+	projectsProvider func() ([]projects.Project, error)
+	now              func() time.Time
+	publisher        events.Publisher // nil の場合はイベントを発行しない
+
+	// mu は本サービス経由の変更（etag 照合→書き込み、存在確認→rename）を直列化します。
+	// エージェントや手作業による直接編集とは排他できないため、etag 照合で競合を検出します。
+	mu sync.Mutex
+}
+
+// Option は Service の任意の依存を設定します
+type Option func(*serviceImpl)
+
+// WithPublisher は変更操作のイベント（task.created / updated / moved / reordered / deleted）の発行先を設定します
+func WithPublisher(p events.Publisher) Option {
+	return func(s *serviceImpl) {
+		s.publisher = p
+	}
+}
+
+// NewService は新しいServiceを生成します。now が nil の場合は time.Now を使います。
+func NewService(projectsProvider func() ([]projects.Project, error), now func() time.Time, opts ...Option) Service {
+	if now == nil {
+		now = time.Now
+	}
+	s := &serviceImpl{projectsProvider: projectsProvider, now: now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// publish は変更操作のイベントを発行します（発行先未設定時は何もしない）
+func (s *serviceImpl) publish(topic string, ev Event) {
+	if s.publisher == nil {
+		return
+	}
+	if ev.Task != nil {
+		t := *ev.Task
+		t.Content = ""
+		ev.Task = &t
+	}
+	s.publisher.Publish(topic, ev)
+}
+
+// List はプロジェクトの全レーンのタスク一覧を返します。存在しないレーンは空で返します。
+func (s *serviceImpl) List(projectPath string) (*Board, error) {
+	project, err := s.resolveProject(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	board := &Board{ProjectPath: project, Lanes: make([]LaneTasks, 0, len(Lanes))}
+	for _, lane := range Lanes {
+		lt, err := listLane(project, lane)
+		if err != nil {
+			return nil, err
+		}
+		board.Lanes = append(board.Lanes, *lt)
+	}
+	return board, nil
+}
+
+// Get はタスクを本文付きで返します
+func (s *serviceImpl) Get(projectPath string, lane Lane, name string) (*Task, error) {
+	abs, err := s.resolveTask(projectPath, lane, name)
+	if err != nil {
+		return nil, err
+	}
+	return readTask(abs, lane, true)
+}
+
+// Templates は組み込みテンプレートとプロジェクトの 開発/テンプレート/*.md を返します
+func (s *serviceImpl) Templates(projectPath string) ([]Template, error) {
+	project, err := s.resolveProject(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	return listTemplates(project), nil
+}
+
+// Create はテンプレートからタスクを作成します。ファイル名は「YYYY-MM-DD_<タイトル>[_plan].md」で、
+// 同名があれば _2, _3 … を付けます。
+func (s *serviceImpl) Create(req CreateRequest) (*Task, error) {
+	if req.Lane == "" {
+		req.Lane = LaneWaiting
+	}
+	if req.Template == "" {
+		req.Template = defaultTemplate
+	}
+	if req.Lane.Dir() == "" {
+		return nil, fmt.Errorf("%w: invalid lane: %s", ErrValidation, req.Lane)
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, fmt.Errorf("%w: title must not be empty", ErrValidation)
+	}
+	slug := slugify(title)
+	if slug == "" {
+		return nil, fmt.Errorf("%w: title has no usable characters: %s", ErrValidation, req.Title)
+	}
+	project, err := s.resolveProject(req.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := loadTemplate(project, req.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	content := tmpl.render(title, req.Body, now)
+	dir := filepath.Join(project, req.Lane.Dir())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lane dir %s: %w", dir, err)
+	}
+	base := now.Format("2006-01-02") + "_" + slug + tmpl.suffix
+	for i := 1; ; i++ {
+		name := base + ".md"
+		if i > 1 {
+			name = fmt.Sprintf("%s_%d.md", base, i)
+		}
+		abs := filepath.Join(dir, name)
+		created, err := writeNew(abs, content)
+		if err != nil {
+			return nil, err
+		}
+		if !created {
+			continue
+		}
+		log.Printf("[TaskService] task created: project=%s, lane=%s, name=%s", project, req.Lane, name)
+		task, err := readTask(abs, req.Lane, true)
+		if err != nil {
+			return nil, err
+		}
+		s.publish(events.TopicTaskCreated, Event{ProjectPath: project, Lane: req.Lane, Name: name, Task: task})
+		return task, nil
+	}
+}
+
+// Update はタスク本文を etag 照合のうえ置き換えます（temp + rename）
+func (s *serviceImpl) Update(req UpdateRequest) (*Task, error) {
+	if req.ETag == "" {
+		return nil, fmt.Errorf("%w: etag is required", ErrValidation)
+	}
+	abs, err := s.resolveTask(req.ProjectPath, req.Lane, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := checkETag(abs, req.ETag); err != nil {
+		return nil, err
+	}
+	if err := writeAtomic(abs, req.Content); err != nil {
+		return nil, err
+	}
+	log.Printf("[TaskService] task updated: path=%s", abs)
+	task, err := readTask(abs, req.Lane, true)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(events.TopicTaskUpdated, Event{ProjectPath: filepath.Clean(req.ProjectPath), Lane: req.Lane, Name: req.Name, Task: task})
+	return task, nil
+}
+
+// Move はタスクを別レーンへ移動し（移動先の同名ファイルは上書きしない）、両レーンの並び順を更新します
+func (s *serviceImpl) Move(req MoveRequest) (*Task, error) {
+	if req.To.Dir() == "" {
+		return nil, fmt.Errorf("%w: invalid destination lane: %s", ErrValidation, req.To)
+	}
+	src, err := s.resolveTask(req.ProjectPath, req.Lane, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	project := filepath.Clean(req.ProjectPath)
+	srcDir := filepath.Dir(src)
+	dstDir := filepath.Join(project, req.To.Dir())
+	dst := filepath.Join(dstDir, req.Name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.ETag != "" {
+		if err := checkETag(src, req.ETag); err != nil {
+			return nil, err
+		}
+	}
+	if req.To != req.Lane {
+		if err := os.MkdirAll(dstDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create lane dir %s: %w", dstDir, err)
+		}
+		moved, err := moveNew(src, dst)
+		if err != nil {
+			return nil, err
+		}
+		if !moved {
+			return nil, fmt.Errorf("%w: %s already exists in %s", ErrConflict, req.Name, req.To)
+		}
+		order := readOrder(srcDir)
+		if i := slices.Index(order, req.Name); i >= 0 {
+			if err := writeOrder(srcDir, slices.Delete(order, i, i+1)); err != nil {
+				log.Printf("[TaskService] failed to update source order: dir=%s, error=%v", srcDir, err)
+			}
+		}
+	}
+
+	// 移動先の並び順: 明示位置があれば挿入、無ければ .order がある場合のみ末尾へ追加
+	order := readOrder(dstDir)
+	if i := slices.Index(order, req.Name); i >= 0 {
+		order = slices.Delete(order, i, i+1)
+	}
+	switch {
+	case req.Position != nil:
+		pos := min(max(*req.Position, 0), len(order))
+		order = slices.Insert(order, pos, req.Name)
+	case len(order) > 0:
+		order = append(order, req.Name)
+	}
+	if len(order) > 0 {
+		if err := writeOrder(dstDir, order); err != nil {
+			log.Printf("[TaskService] failed to update destination order: dir=%s, error=%v", dstDir, err)
+		}
+	}
+
+	log.Printf("[TaskService] task moved: project=%s, name=%s, from=%s, to=%s", project, req.Name, req.Lane, req.To)
+	task, err := readTask(dst, req.To, false)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(events.TopicTaskMoved, Event{ProjectPath: project, Lane: req.To, Name: req.Name, From: req.Lane, Task: task})
+	return task, nil
+}
+
+// Reorder はレーン内の並び順を .order に保存します。存在しないファイル名は ErrValidation です。
+func (s *serviceImpl) Reorder(req ReorderRequest) (*LaneTasks, error) {
+	if req.Lane.Dir() == "" {
+		return nil, fmt.Errorf("%w: invalid lane: %s", ErrValidation, req.Lane)
+	}
+	project, err := s.resolveProject(req.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(project, req.Lane.Dir())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[string]bool, len(req.Names))
+	for _, name := range req.Names {
+		if err := validateName(name); err != nil {
+			return nil, err
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: duplicate name: %s", ErrValidation, name)
+		}
+		seen[name] = true
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return nil, fmt.Errorf("%w: %s/%s", ErrNotFound, req.Lane, name)
+		}
+	}
+	if err := writeOrder(dir, req.Names); err != nil {
+		return nil, err
+	}
+	s.publish(events.TopicTaskReordered, Event{ProjectPath: project, Lane: req.Lane, Names: req.Names})
+	return listLane(project, req.Lane)
+}
+
+// Delete はタスクを etag 照合のうえ削除します
+func (s *serviceImpl) Delete(req DeleteRequest) error {
+	if req.ETag == "" {
+		return fmt.Errorf("%w: etag is required", ErrValidation)
+	}
+	abs, err := s.resolveTask(req.ProjectPath, req.Lane, req.Name)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := checkETag(abs, req.ETag); err != nil {
+		return err
+	}
+	if err := os.Remove(abs); err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	dir := filepath.Dir(abs)
+	order := readOrder(dir)
+	if i := slices.Index(order, req.Name); i >= 0 {
+		if err := writeOrder(dir, slices.Delete(order, i, i+1)); err != nil {
+			log.Printf("[TaskService] failed to update order: dir=%s, error=%v", dir, err)
+		}
+	}
+	log.Printf("[TaskService] task deleted: path=%s", abs)
+	s.publish(events.TopicTaskDeleted, Event{ProjectPath: filepath.Clean(req.ProjectPath), Lane: req.Lane, Name: req.Name})
+	return nil
+}
+
+// resolveProject はプロジェクトパスが登録済みか検証し、Clean 済みのパスを返します
+func (s *serviceImpl) resolveProject(projectPath string) (string, error) {
+	if projectPath == "" {
+		return "", fmt.Errorf("%w: projectPath is required", ErrValidation)
+	}
+	projs, err := s.projectsProvider()
+	if err != nil {
+		return "", fmt.Errorf("failed to load projects: %w", err)
+	}
+	clean := filepath.Clean(projectPath)
+	for _, p := range projs {
+		if filepath.Clean(p.Path) == clean {
+			return clean, nil
+		}
+	}
+	return "", fmt.Errorf("%w: project is not registered: %s", ErrValidation, projectPath)
+}
+
+// resolveTask はプロジェクト・レーン・ファイル名を検証し、タスクの絶対パスを返します
+func (s *serviceImpl) resolveTask(projectPath string, lane Lane, name string) (string, error) {
+	if lane.Dir() == "" {
+		return "", fmt.Errorf("%w: invalid lane: %s", ErrValidation, lane)
+	}
+	if err := validateName(name); err != nil {
+		return "", err
+	}
+	project, err := s.resolveProject(projectPath)
+	if err != nil {
+		return "", err
+	}
+	abs := filepath.Join(project, lane.Dir(), name)
+	info, err := os.Lstat(abs)
+	if err != nil || !info.Mode().IsRegular() {
+		return "", fmt.Errorf("%w: %s/%s", ErrNotFound, lane, name)
+	}
+	return abs, nil
+}
+
+// validateName はタスクのファイル名（パス区切りを含まない .md、先頭ドット不可）を検証します
+func validateName(name string) error {
+	if name == "" || name != filepath.Base(name) || strings.ContainsAny(name, `/\`) ||
+		strings.HasPrefix(name, ".") || filepath.Ext(name) != ".md" {
+		return fmt.Errorf("%w: invalid task name: %s", ErrValidation, name)
+	}
+	return nil
+}
+
+// listLane はレーンのタスク一覧を .order → ファイル名の順で返します
+func listLane(project string, lane Lane) (*LaneTasks, error) {
+	dir := filepath.Join(project, lane.Dir())
+	lt := &LaneTasks{Lane: lane, Dir: filepath.ToSlash(lane.Dir()), Tasks: []Task{}}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return lt, nil
+		}
+		return nil, fmt.Errorf("failed to read lane dir %s: %w", dir, err)
+	}
+
+	rank := make(map[string]int)
+	for i, name := range readOrder(dir) {
+		rank[name] = i
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.Type().IsRegular() || validateName(e.Name()) != nil {
+			continue
+		}
+		names = append(names, e.Name())
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		ri, oki := rank[names[i]]
+		rj, okj := rank[names[j]]
+		switch {
+		case oki && okj:
+			return ri < rj
+		case oki != okj:
+			return oki
+		default:
+			return names[i] < names[j]
+		}
+	})
+
+	for _, name := range names {
+		t, err := readTask(filepath.Join(dir, name), lane, false)
+		if err != nil {
+			// 一覧取得中に移動・削除されたファイルは飛ばす
+			continue
+		}
+		lt.Tasks = append(lt.Tasks, *t)
+	}
+	return lt, nil
+}
+
+// readTask はタスクファイルを読み、Task を組み立てます
+func readTask(abs string, lane Lane, withContent bool) (*Task, error) {
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, filepath.Base(abs))
+		}
+		return nil, fmt.Errorf("failed to read task %s: %w", abs, err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat task %s: %w", abs, err)
+	}
+	name := filepath.Base(abs)
+	t := &Task{
+		Lane:    lane,
+		Name:    name,
+		Path:    filepath.ToSlash(filepath.Join(lane.Dir(), name)),
+		Title:   extractTitle(data, name),
+		ETag:    computeETag(data),
+		ModTime: info.ModTime().Format(time.RFC3339),
+		Size:    info.Size(),
+	}
+	if withContent {
+		t.Content = string(data)
+	}
+	return t, nil
+}
+
+// computeETag は内容の SHA-256 先頭16桁を返します（mtime は秒精度で衝突しうるため内容で判定する）
+func computeETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// checkETag は現在の内容の etag が want と一致するか確認します
+func checkETag(abs, want string) error {
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", ErrNotFound, filepath.Base(abs))
+		}
+		return fmt.Errorf("failed to read task %s: %w", abs, err)
+	}
+	if got := computeETag(data); got != strings.Trim(want, `"`) {
+		return fmt.Errorf("%w: etag mismatch (current=%s)", ErrConflict, got)
+	}
+	return nil
+}
+
+// extractTitle は最初の「# 見出し」を返します（無ければ拡張子を除いたファイル名）
+func extractTitle(data []byte, name string) string {
+	sc := bufio.NewScanner(strings.NewReader(string(data)))
+	for sc.Scan() {
+		if title, ok := strings.CutPrefix(strings.TrimSpace(sc.Text()), "# "); ok {
+			return strings.TrimSpace(title)
+		}
+	}
+	return strings.TrimSuffix(name, ".md")
+}
+
+// slugify はタイトルをファイル名に使える形へ変換します（空白・パス記号は _、最大 maxSlugRunes 文字）
+func slugify(title string) string {
+	var b strings.Builder
+	lastUnderscore := false
+	n := 0
+	for _, r := range title {
+		if n >= maxSlugRunes {
+			break
+		}
+		if r == ' ' || r == '　' || r == '\t' || r == '\n' || r == '\r' || strings.ContainsRune(`/\:*?"<>|.`, r) {
+			if !lastUnderscore && b.Len() > 0 {
+				b.WriteByte('_')
+				lastUnderscore = true
+				n++
+			}
+			continue
+		}
+		b.WriteRune(r)
+		lastUnderscore = false
+		n++
+	}
+	return strings.Trim(b.String(), "_")
+}
+
+// writeNew は abs が存在しない場合のみ内容を書き込みます（temp + link で既存ファイルを上書きしない）。
+// 既に存在した場合は (false, nil) を返します。
+func writeNew(abs, content string) (bool, error) {
+	tmpPath, err := writeTemp(filepath.Dir(abs), content)
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmpPath)
+	if err := os.Link(tmpPath, abs); err != nil {
+		if os.IsExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create task file: %w", err)
+	}
+	return true, nil
+}
+
+// moveNew は src を dst へ移動します。dst が既にあれば上書きせず false を返します。
+// 存在確認と rename の間に作られたファイルを上書きしないよう、writeNew と同じく os.Link で置いてから src を消します
+func moveNew(src, dst string) (bool, error) {
+	if err := os.Link(src, dst); err != nil {
+		if os.IsExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to move task: %w", err)
+	}
+	if err := os.Remove(src); err != nil {
+		os.Remove(dst)
+		return false, fmt.Errorf("failed to remove moved task: %w", err)
+	}
+	return true, nil
+}
+
+// writeAtomic は同一ディレクトリの temp ファイルへ書いて rename します
+func writeAtomic(abs, content string) error {
+	tmpPath, err := writeTemp(filepath.Dir(abs), content)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, abs); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
+
+// writeTemp は dir に内容を書いた temp ファイルを作り、そのパスを返します
+func writeTemp(dir, content string) (string, error) {
+	tmpFile, err := os.CreateTemp(dir, ".task.tmp.*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	if _, err := tmpFile.WriteString(content); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to close temp file: %w", err)
+	}
+	return tmpPath, nil
+}
+
+// readOrder はレーンの .order を読みます（無ければ nil）
+func readOrder(dir string) []string {
+	data, err := os.ReadFile(filepath.Join(dir, orderFileName))
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			names = append(names, line)
+		}
+	}
+	return names
+}
+
+// writeOrder はレーンの .order をアトミックに書き込みます
+func writeOrder(dir string, names []string) error {
+	content := strings.Join(names, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	return writeAtomic(filepath.Join(dir, orderFileName), content)
+}
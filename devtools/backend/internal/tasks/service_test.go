@@ -0,0 +1,325 @@
+package tasks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ghostrunner/backend/internal/projects"
+)
+
+var fixedNow = time.Date(2026, 7, 20, 12, 0, 0, 0, time.UTC)
+
+// newTestService は t.TempDir 配下に登録済みプロジェクトを1つ作り、Service とそのパスを返します
+func newTestService(t *testing.T) (Service, string) {
+	t.Helper()
+	project := t.TempDir()
+	provider := func() ([]projects.Project, error) {
+		return []projects.Project{{Name: "proj", Path: project}}, nil
+	}
+	return NewService(provider, func() time.Time { return fixedNow }), project
+}
+
+// writeTask はレーン配下にタスクファイルを作成します
+func writeTask(t *testing.T, project string, lane Lane, name, content string) {
+	t.Helper()
+	dir := filepath.Join(project, lane.Dir())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func laneNames(b *Board, lane Lane) []string {
+	for _, lt := range b.Lanes {
+		if lt.Lane == lane {
+			names := []string{}
+			for _, task := range lt.Tasks {
+				names = append(names, task.Name)
+			}
+			return names
+		}
+	}
+	return nil
+}
+
+func TestCreate(t *testing.T) {
+	svc, project := newTestService(t)
+
+	task, err := svc.Create(CreateRequest{ProjectPath: project, Title: "質問待ち 検知/改善", Body: "概要本文"})
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	if task.Name != "2026-07-20_質問待ち_検知_改善_plan.md" {
+		t.Errorf("Name = %q", task.Name)
+	}
+	if task.Lane != LaneWaiting || task.Path != "開発/実装/実装待ち/"+task.Name {
+		t.Errorf("Lane/Path = %s / %s", task.Lane, task.Path)
+	}
+	if task.Title != "質問待ち 検知/改善 実装計画" {
+		t.Errorf("Title = %q", task.Title)
+	}
+	if task.ETag == "" || task.Content == "" {
+		t.Errorf("ETag/Content should be set: %+v", task)
+	}
+
+	// 同名は連番を付けて上書きしない
+	again, err := svc.Create(CreateRequest{ProjectPath: project, Title: "質問待ち 検知/改善"})
+	if err != nil {
+		t.Fatalf("Create(2) error: %v", err)
+	}
+	if again.Name != "2026-07-20_質問待ち_検知_改善_plan_2.md" {
+		t.Errorf("second Name = %q", again.Name)
+	}
+
+	// プロジェクト独自テンプレートが使える
+	tmplDir := filepath.Join(project, projectTemplateDir)
+	if err := os.MkdirAll(tmplDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmplDir, "bug.md"), []byte("# 不具合: {{title}}\n{{body}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bug, err := svc.Create(CreateRequest{ProjectPath: project, Lane: LaneReviewing, Title: "落ちる", Template: "bug"})
+	if err != nil {
+		t.Fatalf("Create(bug) error: %v", err)
+	}
+	if bug.Content != "# 不具合: 落ちる\n\n" || bug.Name != "2026-07-20_落ちる.md" {
+		t.Errorf("bug task = %+v", bug)
+	}
+}
+
+func TestCreate_Validation(t *testing.T) {
+	svc, project := newTestService(t)
+
+	tests := []struct {
+		name string
+		req  CreateRequest
+	}{
+		{"未登録プロジェクト", CreateRequest{ProjectPath: "/not/registered", Title: "a"}},
+		{"タイトル空", CreateRequest{ProjectPath: project, Title: "  "}},
+		{"不正なレーン", CreateRequest{ProjectPath: project, Title: "a", Lane: "todo"}},
+		{"存在しないテンプレート", CreateRequest{ProjectPath: project, Title: "a", Template: "nope"}},
+		{"テンプレート名にパス", CreateRequest{ProjectPath: project, Title: "a", Template: "../x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.Create(tt.req); !errors.Is(err, ErrValidation) {
+				t.Errorf("err = %v, want ErrValidation", err)
+			}
+		})
+	}
+}
+
+func TestUpdate_ETag(t *testing.T) {
+	svc, project := newTestService(t)
+	writeTask(t, project, LaneWaiting, "a.md", "# A\n")
+
+	task, err := svc.Get(project, LaneWaiting, "a.md")
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+
+	updated, err := svc.Update(UpdateRequest{ProjectPath: project, Lane: LaneWaiting, Name: "a.md", Content: "# A2\n", ETag: task.ETag})
+	if err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+	if updated.Title != "A2" || updated.ETag == task.ETag {
+		t.Errorf("updated = %+v", updated)
+	}
+
+	// 古い etag での更新は競合
+	_, err = svc.Update(UpdateRequest{ProjectPath: project, Lane: LaneWaiting, Name: "a.md", Content: "# A3\n", ETag: task.ETag})
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("stale etag: err = %v, want ErrConflict", err)
+	}
+
+	tests := []struct {
+		name string
+		req  UpdateRequest
+		want error
+	}{
+		{"etag必須", UpdateRequest{ProjectPath: project, Lane: LaneWaiting, Name: "a.md"}, ErrValidation},
+		{"パストラバーサル", UpdateRequest{ProjectPath: project, Lane: LaneWaiting, Name: "../a.md", ETag: "x"}, ErrValidation},
+		{"md以外", UpdateRequest{ProjectPath: project, Lane: LaneWaiting, Name: "a.txt", ETag: "x"}, ErrValidation},
+		{"存在しない", UpdateRequest{ProjectPath: project, Lane: LaneWaiting, Name: "b.md", ETag: "x"}, ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.Update(tt.req); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoveAndReorder(t *testing.T) {
+	svc, project := newTestService(t)
+	writeTask(t, project, LaneWaiting, "a.md", "# A\n")
+	writeTask(t, project, LaneWaiting, "b.md", "# B\n")
+	writeTask(t, project, LaneWaiting, "c.md", "# C\n")
+	writeTask(t, project, LaneRunning, "x.md", "# X\n")
+
+	// 並び替え（指定外の c は後ろ）
+	if _, err := svc.Reorder(ReorderRequest{ProjectPath: project, Lane: LaneWaiting, Names: []string{"b.md", "a.md"}}); err != nil {
+		t.Fatalf("Reorder error: %v", err)
+	}
+	board, err := svc.List(project)
+	if err != nil {
+		t.Fatalf("List error: %v", err)
+	}
+	if got := laneNames(board, LaneWaiting); len(got) != 3 || got[0] != "b.md" || got[1] != "a.md" || got[2] != "c.md" {
+		t.Errorf("waiting order = %v", got)
+	}
+
+	// 位置指定で実行中の先頭へ移動
+	pos := 0
+	moved, err := svc.Move(MoveRequest{ProjectPath: project, Lane: LaneWaiting, Name: "b.md", To: LaneRunning, Position: &pos})
+	if err != nil {
+		t.Fatalf("Move error: %v", err)
+	}
+	if moved.Lane != LaneRunning || moved.Path != "開発/実装/実行中/b.md" {
+		t.Errorf("moved = %+v", moved)
+	}
+	board, _ = svc.List(project)
+	if got := laneNames(board, LaneRunning); len(got) != 2 || got[0] != "b.md" {
+		t.Errorf("running order = %v", got)
+	}
+	if got := laneNames(board, LaneWaiting); len(got) != 2 || got[0] != "a.md" {
+		t.Errorf("waiting after move = %v", got)
+	}
+
+	// アーカイブは 完了/アーカイブ 配下
+	if _, err := svc.Move(MoveRequest{ProjectPath: project, Lane: LaneRunning, Name: "x.md", To: LaneArchived}); err != nil {
+		t.Fatalf("Move(archive) error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(project, "開発", "実装", "完了", "アーカイブ", "x.md")); err != nil {
+		t.Errorf("archived file missing: %v", err)
+	}
+	board, _ = svc.List(project)
+	if got := laneNames(board, LaneDone); len(got) != 0 {
+		t.Errorf("done lane should not list archive dir: %v", got)
+	}
+
+	// 移動先に同名があれば競合
+	writeTask(t, project, LaneDone, "a.md", "# other\n")
+	if _, err := svc.Move(MoveRequest{ProjectPath: project, Lane: LaneWaiting, Name: "a.md", To: LaneDone}); !errors.Is(err, ErrConflict) {
+		t.Errorf("move onto existing: err = %v, want ErrConflict", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(project, LaneDone.Dir(), "a.md")); err != nil || string(data) != "# other\n" {
+		t.Errorf("existing destination overwritten: %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(project, LaneWaiting.Dir(), "a.md")); err != nil {
+		t.Errorf("source removed on conflict: %v", err)
+	}
+	// etag 不一致は競合
+	if _, err := svc.Move(MoveRequest{ProjectPath: project, Lane: LaneWaiting, Name: "a.md", To: LaneReviewing, ETag: "stale"}); !errors.Is(err, ErrConflict) {
+		t.Errorf("move with stale etag: err = %v, want ErrConflict", err)
+	}
+	// 並び替えに存在しないファイル
+	if _, err := svc.Reorder(ReorderRequest{ProjectPath: project, Lane: LaneWaiting, Names: []string{"zzz.md"}}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("reorder unknown: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	svc, project := newTestService(t)
+	writeTask(t, project, LaneDone, "a.md", "# A\n")
+	task, err := svc.Get(project, LaneDone, "a.md")
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+
+	if err := svc.Delete(DeleteRequest{ProjectPath: project, Lane: LaneDone, Name: "a.md", ETag: "stale"}); !errors.Is(err, ErrConflict) {
+		t.Errorf("stale etag: err = %v, want ErrConflict", err)
+	}
+	if err := svc.Delete(DeleteRequest{ProjectPath: project, Lane: LaneDone, Name: "a.md", ETag: task.ETag}); err != nil {
+		t.Fatalf("Delete error: %v", err)
+	}
+	if _, err := svc.Get(project, LaneDone, "a.md"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("after delete: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	svc, project := newTestService(t)
+	dir := filepath.Join(project, projectTemplateDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "plan.md"), []byte("# {{title}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := svc.Templates(project)
+	if err != nil {
+		t.Fatalf("Templates error: %v", err)
+	}
+	want := []Template{{Name: "blank", Source: "builtin"}, {Name: "plan", Source: "project"}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Templates = %+v, want %+v", got, want)
+	}
+}
+
+// recordingPublisher は発行されたイベントを記録するテスト用スタブです
+type recordingPublisher struct {
+	topics []string
+	events []Event
+}
+
+func (p *recordingPublisher) Publish(topic string, data any) {
+	p.topics = append(p.topics, topic)
+	p.events = append(p.events, data.(Event))
+}
+
+func TestPublishesEvents(t *testing.T) {
+	project := t.TempDir()
+	provider := func() ([]projects.Project, error) {
+		return []projects.Project{{Name: "proj", Path: project}}, nil
+	}
+	pub := &recordingPublisher{}
+	svc := NewService(provider, func() time.Time { return fixedNow }, WithPublisher(pub))
+
+	task, err := svc.Create(CreateRequest{ProjectPath: project, Title: "通知"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	task, err = svc.Update(UpdateRequest{ProjectPath: project, Lane: LaneWaiting, Name: task.Name, Content: "# 通知\n", ETag: task.ETag})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	moved, err := svc.Move(MoveRequest{ProjectPath: project, Lane: LaneWaiting, Name: task.Name, To: LaneRunning})
+	if err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	if _, err := svc.Reorder(ReorderRequest{ProjectPath: project, Lane: LaneRunning, Names: []string{task.Name}}); err != nil {
+		t.Fatalf("Reorder: %v", err)
+	}
+	if err := svc.Delete(DeleteRequest{ProjectPath: project, Lane: LaneRunning, Name: task.Name, ETag: moved.ETag}); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	// 失敗した操作は発行しない
+	if _, err := svc.Create(CreateRequest{ProjectPath: project, Title: ""}); err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	want := []string{"task.created", "task.updated", "task.moved", "task.reordered", "task.deleted"}
+	if len(pub.topics) != len(want) {
+		t.Fatalf("topics = %v, want %v", pub.topics, want)
+	}
+	for i, topic := range want {
+		if pub.topics[i] != topic {
+			t.Errorf("topic[%d] = %s, want %s", i, pub.topics[i], topic)
+		}
+	}
+	if ev := pub.events[0]; ev.Task == nil || ev.Task.Content != "" || ev.Lane != LaneWaiting {
+		t.Errorf("created event should carry task without content: %+v", ev)
+	}
+	if ev := pub.events[2]; ev.From != LaneWaiting || ev.Lane != LaneRunning {
+		t.Errorf("moved event lanes: from=%s, lane=%s", ev.From, ev.Lane)
+	}
+}
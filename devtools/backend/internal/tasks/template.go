@@ -0,0 +1,80 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultTemplate はテンプレート省略時に使う組み込みテンプレート名です
+const defaultTemplate = "plan"
+
+// projectTemplateDir はプロジェクト独自テンプレートの相対ディレクトリです
+var projectTemplateDir = filepath.Join("開発", "テンプレート")
+
+// taskTemplate はタスク本文の雛形です。{{title}} / {{body}} / {{date}} を置換します。
+type taskTemplate struct {
+	text string
+	// suffix はファイル名のタイトルの後ろに付ける接尾辞（例: _plan）
+	suffix string
+}
+
+// builtinTemplates は組み込みテンプレートです（plan は既存の計画書の命名 *_plan.md に合わせる）
+var builtinTemplates = map[string]taskTemplate{
+	"plan": {
+		text:   "# {{title}} 実装計画\n\n作成日: {{date}}\n\n## 概要\n\n{{body}}\n\n## 確認事項\n\n## 実装ステップ\n\n## 完了条件\n",
+		suffix: "_plan",
+	},
+	"blank": {
+		text: "# {{title}}\n\n{{body}}\n",
+	},
+}
+
+// render はテンプレートにタイトル・本文・日付を埋め込みます
+func (t taskTemplate) render(title, body string, now time.Time) string {
+	r := strings.NewReplacer(
+		"{{title}}", title,
+		"{{body}}", strings.TrimSpace(body),
+		"{{date}}", now.Format("2006-01-02"),
+	)
+	return r.Replace(t.text)
+}
+
+// loadTemplate は名前からテンプレートを返します。プロジェクト独自テンプレート（開発/テンプレート/<name>.md）が
+// 組み込みより優先です。
+func loadTemplate(project, name string) (taskTemplate, error) {
+	if name != filepath.Base(name) || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return taskTemplate{}, fmt.Errorf("%w: invalid template name: %s", ErrValidation, name)
+	}
+	data, err := os.ReadFile(filepath.Join(project, projectTemplateDir, name+".md"))
+	if err == nil {
+		// 組み込みと同名で上書きした場合もファイル名の接尾辞は引き継ぐ
+		return taskTemplate{text: string(data), suffix: builtinTemplates[name].suffix}, nil
+	}
+	if t, ok := builtinTemplates[name]; ok {
+		return t, nil
+	}
+	return taskTemplate{}, fmt.Errorf("%w: template not found: %s", ErrValidation, name)
+}
+
+// listTemplates は組み込みとプロジェクト独自（開発/テンプレート/*.md）のテンプレート名を返します
+func listTemplates(project string) []Template {
+	seen := make(map[string]bool)
+	var out []Template
+	matches, _ := filepath.Glob(filepath.Join(project, projectTemplateDir, "*.md"))
+	for _, m := range matches {
+		name := strings.TrimSuffix(filepath.Base(m), ".md")
+		seen[name] = true
+		out = append(out, Template{Name: name, Source: "project"})
+	}
+	for name := range builtinTemplates {
+		if !seen[name] {
+			out = append(out, Template{Name: name, Source: "builtin"})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
@@ -0,0 +1,143 @@
+package tasks
+
+import (
+	"errors"
+	"path/filepath"
+)
+
+// ErrValidation はリクエストが不正な場合のエラーです（未登録プロジェクト・不正なレーン/ファイル名等）
+var ErrValidation = errors.New("validation error")
+
+// ErrNotFound はタスクファイルが存在しない場合のエラーです
+var ErrNotFound = errors.New("task not found")
+
+// ErrConflict は楽観的排他制御の競合（etag 不一致）または移動先に同名ファイルがある場合のエラーです
+var ErrConflict = errors.New("task conflict")
+
+// Lane はカンバンのレーンです。値は dashboard.KanbanCounts の JSON キーと揃えています。
+type Lane string
+
+const (
+	// LaneReviewing は 開発/実装/レビュー です
+	LaneReviewing Lane = "reviewing"
+	// LaneWaiting は 開発/実装/実装待ち です
+	LaneWaiting Lane = "waiting"
+	// LaneRunning は 開発/実装/実行中 です
+	LaneRunning Lane = "running"
+	// LaneDone は 開発/実装/完了 です
+	LaneDone Lane = "done"
+	// LaneArchived は 開発/実装/完了/アーカイブ です（テンプレートの既存フォルダ構成に合わせ完了の配下）
+	LaneArchived Lane = "archived"
+)
+
+// Lanes はレーンの表示順です
+var Lanes = []Lane{LaneReviewing, LaneWaiting, LaneRunning, LaneDone, LaneArchived}
+
+// laneDirs はレーンごとのプロジェクトからの相対ディレクトリです
+var laneDirs = map[Lane]string{
+	LaneReviewing: filepath.Join("開発", "実装", "レビュー"),
+	LaneWaiting:   filepath.Join("開発", "実装", "実装待ち"),
+	LaneRunning:   filepath.Join("開発", "実装", "実行中"),
+	LaneDone:      filepath.Join("開発", "実装", "完了"),
+	LaneArchived:  filepath.Join("開発", "実装", "完了", "アーカイブ"),
+}
+
+// Dir はレーンのプロジェクトからの相対ディレクトリを返します（不正なレーンは空文字）
+func (l Lane) Dir() string {
+	return laneDirs[l]
+}
+
+// Task は1つのタスクファイル（レーン配下の .md）を表します
+type Task struct {
+	Lane Lane `json:"lane"`
+	// Name はファイル名（例: 2026-07-20_質問待ち検知_plan.md）
+	Name string `json:"name"`
+	// Path はプロジェクトからの相対パス（例: 開発/実装/実装待ち/2026-07-20_質問待ち検知_plan.md）
+	Path string `json:"path"`
+	// Title は本文の最初の「# 見出し」（無ければファイル名から拡張子を除いたもの）
+	Title string `json:"title"`
+	// ETag は内容の SHA-256 先頭16桁です。更新・移動・削除で渡すと競合を検出します
+	ETag    string `json:"etag"`
+	ModTime string `json:"modTime"` // RFC3339
+	Size    int64  `json:"size"`
+	Content string `json:"content,omitempty"` // Get / Create / Update の応答のみ
+}
+
+// LaneTasks は1レーンのタスク一覧です（並び順は .order → ファイル名）
+type LaneTasks struct {
+	Lane  Lane   `json:"lane"`
+	Dir   string `json:"dir"`
+	Tasks []Task `json:"tasks"`
+}
+
+// Board は1プロジェクトのカンバン全体です
+type Board struct {
+	ProjectPath string      `json:"projectPath"`
+	Lanes       []LaneTasks `json:"lanes"`
+}
+
+// Template はタスク作成に使えるテンプレートです
+type Template struct {
+	Name string `json:"name"`
+	// Source は builtin（組み込み）または project（開発/テンプレート/ 配下）です
+	Source string `json:"source"`
+}
+
+// CreateRequest はタスク作成リクエストです
+type CreateRequest struct {
+	ProjectPath string `json:"projectPath"`
+	// Lane は作成先レーン（省略時は waiting）
+	Lane  Lane   `json:"lane"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	// Template はテンプレート名（省略時は plan）
+	Template string `json:"template"`
+}
+
+// UpdateRequest はタスク内容の更新リクエストです。ETag は必須です
+type UpdateRequest struct {
+	ProjectPath string `json:"projectPath"`
+	Lane        Lane   `json:"lane"`
+	Name        string `json:"name"`
+	Content     string `json:"content"`
+	ETag        string `json:"etag"`
+}
+
+// MoveRequest はタスクのレーン移動リクエストです
+type MoveRequest struct {
+	ProjectPath string `json:"projectPath"`
+	Lane        Lane   `json:"lane"`
+	Name        string `json:"name"`
+	To          Lane   `json:"to"`
+	// ETag は任意。指定時は移動前の内容と一致しなければ ErrConflict
+	ETag string `json:"etag,omitempty"`
+	// Position は移動先レーンでの並び位置（0始まり）。省略時（nil）は末尾
+	Position *int `json:"position,omitempty"`
+}
+
+// ReorderRequest はレーン内の並び替えリクエストです。Names に含めないタスクは後ろにファイル名順で並びます
+type ReorderRequest struct {
+	ProjectPath string   `json:"projectPath"`
+	Lane        Lane     `json:"lane"`
+	Names       []string `json:"names"`
+}
+
+// DeleteRequest はタスク削除リクエストです。ETag は必須です
+type DeleteRequest struct {
+	ProjectPath string `json:"projectPath"`
+	Lane        Lane   `json:"lane"`
+	Name        string `json:"name"`
+	ETag        string `json:"etag"`
+}
+
+// Event はタスク操作のイベント（task.* トピックのデータ）です。Task は本文を含みません
+type Event struct {
+	ProjectPath string `json:"projectPath"`
+	Lane        Lane   `json:"lane"`
+	Name        string `json:"name,omitempty"`
+	// From は task.moved の移動元レーン
+	From Lane  `json:"from,omitempty"`
+	Task *Task `json:"task,omitempty"`
+	// Names は task.reordered の並び順
+	Names []string `json:"names,omitempty"`
+}
@@ -0,0 +1,148 @@
+package transcript
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+)
+
+const (
+	// maxDeltaBytes は ReadDelta が1回に読む最大バイト数です。
+	// 前回位置からの差分がこれを超える（長時間ぶりの読み取り・初回の巨大ログ）場合は末尾のみ読みます。
+	maxDeltaBytes = 1024 * 1024
+	// maxDeltaToolCalls は Delta.ToolCalls に残す直近のツール呼び出し数です
+	maxDeltaToolCalls = 30
+)
+
+// ToolCall は差分内の1回のツール呼び出しです。
+type ToolCall struct {
+	Name string `json:"name"`
+	// Target は代表的な対象（コマンド・ファイルパス・URL・パターン等。無ければ空）です
+	Target string `json:"target,omitempty"`
+	// IsError は結果がエラーだったかを表します（結果が差分内に無ければ false）
+	IsError bool `json:"isError,omitempty"`
+}
+
+// Delta は会話ログの前回位置（offset）以降に追記された部分の要点です。
+// 実行中セッションの進捗要約の材料として、直近のツール呼び出し・編集したファイル・最後の発言を持ちます。
+type Delta struct {
+	// From / Offset は読んだ範囲 [From, Offset) です。次回は Offset を渡すと続きから読めます
+	From   int64 `json:"from"`
+	Offset int64 `json:"offset"`
+	// Reset は会話ログが前回位置より短くなった（書き直された）ため先頭から読み直したかを表します
+	Reset bool `json:"reset,omitempty"`
+	// Skipped は差分が maxDeltaBytes を超えたため読み飛ばした先頭側のバイト数です
+	Skipped int64 `json:"skipped,omitempty"`
+	// ToolCalls は時系列のツール呼び出し（直近 maxDeltaToolCalls 件）です
+	ToolCalls []ToolCall `json:"toolCalls,omitempty"`
+	// EditedFiles は編集系ツール（editTools）で編集・作成したファイル（初出順、重複なし）です
+	EditedFiles []string `json:"editedFiles,omitempty"`
+	// LastAssistant はアシスタントの最後の発言テキストです
+	LastAssistant string `json:"lastAssistant,omitempty"`
+}
+
+// Empty は差分に要約すべき活動（ツール呼び出し・発言）が無いかを返します。
+func (d Delta) Empty() bool {
+	return len(d.ToolCalls) == 0 && d.LastAssistant == ""
+}
+
+// ReadDelta は会話ログ path の offset 以降に追記された完結行を読み、要点を Delta で返します。
+// 末尾の書きかけの行は読まずに残し（Offset はその手前）、次回の読み取りに回します。
+// offset がファイルサイズを超える場合は書き直されたとみなして先頭から読みます。
+func ReadDelta(path string, offset int64) (Delta, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Delta{}, fmt.Errorf("failed to open transcript %s: %w", path, err)
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil {
+			log.Printf("[transcript] failed to close transcript file: path=%s, error=%v", path, cerr)
+		}
+	}()
+	info, err := f.Stat()
+	if err != nil {
+		return Delta{}, fmt.Errorf("failed to stat transcript %s: %w", path, err)
+	}
+
+	d := Delta{From: offset, Offset: offset}
+	size := info.Size()
+	if offset < 0 || offset > size {
+		d.From, d.Offset, d.Reset = 0, 0, true
+	}
+	start := d.From
+	if size-start > maxDeltaBytes {
+		d.Skipped = size - maxDeltaBytes - start
+		start = size - maxDeltaBytes
+	}
+	if start == size {
+		return d, nil
+	}
+
+	buf := make([]byte, size-start)
+	if _, err := f.ReadAt(buf, start); err != nil && err != io.EOF {
+		return Delta{}, fmt.Errorf("failed to read transcript delta %s: %w", path, err)
+	}
+	end := bytes.LastIndexByte(buf, '\n')
+	if end < 0 {
+		// 完結した行が無い（書きかけの1行のみ）。読み飛ばした分だけ進める
+		d.Offset = start
+		return d, nil
+	}
+	data := buf[:end+1]
+	if d.Skipped > 0 {
+		// 読み始めが行の途中のため、最初の改行までを捨てる
+		data = data[bytes.IndexByte(data, '\n')+1:]
+	}
+	d.Offset = start + int64(end+1)
+
+	// メモリ上のバイト列の読み取りは失敗しない（壊れ行は DecodeEntries が skip する）
+	entries, _ := DecodeEntries(bytes.NewReader(data))
+	summarizeDelta(&d, entries)
+	return d, nil
+}
+
+// summarizeDelta はエントリ群からツール呼び出し・編集ファイル・最後の発言を d へ集めます。
+func summarizeDelta(d *Delta, entries []Entry) {
+	calls := make(map[string]int) // tool_use id → d.ToolCalls の添字
+	for _, e := range entries {
+		if e.Message == nil {
+			continue
+		}
+		for _, b := range e.Message.Content {
+			switch b.Type {
+			case "text":
+				if e.Type == "assistant" && b.Text != "" {
+					d.LastAssistant = b.Text
+				}
+			case "tool_use":
+				call := ToolCall{Name: b.Name, Target: truncateTarget(toolTarget(b.Input))}
+				if b.ID != "" {
+					calls[b.ID] = len(d.ToolCalls)
+				}
+				d.ToolCalls = append(d.ToolCalls, call)
+				if _, ok := editTools[b.Name]; ok && call.Target != "" && !slices.Contains(d.EditedFiles, call.Target) {
+					d.EditedFiles = append(d.EditedFiles, call.Target)
+				}
+			case "tool_result":
+				if i, ok := calls[b.ToolUseID]; ok && b.IsError {
+					d.ToolCalls[i].IsError = true
+				}
+			}
+		}
+	}
+	if n := len(d.ToolCalls); n > maxDeltaToolCalls {
+		d.ToolCalls = d.ToolCalls[n-maxDeltaToolCalls:]
+	}
+}
+
+// truncateTarget は ToolCall.Target を表示・プロンプト用に maxFailingInputRunes 文字へ切り詰めます。
+func truncateTarget(s string) string {
+	r := []rune(s)
+	if len(r) <= maxFailingInputRunes {
+		return s
+	}
+	return string(r[:maxFailingInputRunes]) + "…"
+}
@@ -0,0 +1,99 @@
+package transcript
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+// TestReadDelta は前回位置以降の完結行だけを読み、ツール呼び出し・編集ファイル・最後の発言を集めることを検証します。
+func TestReadDelta(t *testing.T) {
+	ts := "2026-07-20T10:00:00Z"
+	path := writeLines(t,
+		asstText(ts, cwd, "前回までの発言"),
+	)
+	first, err := ReadDelta(path, 0)
+	if err != nil {
+		t.Fatalf("ReadDelta: %v", err)
+	}
+	if first.LastAssistant != "前回までの発言" || len(first.ToolCalls) != 0 {
+		t.Fatalf("first delta = %+v", first)
+	}
+
+	// 続きを追記（最後の行は書きかけ）
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	for _, line := range []string{
+		asstTool(ts, cwd, "Edit", map[string]any{"file_path": "/Users/x/app/a.go"}),
+		toolUse("t1", "Bash", "go test ./..."),
+		toolResult("t1", true),
+		asstTool(ts, cwd, "Write", map[string]any{"file_path": "/Users/x/app/b.go"}),
+		asstTool(ts, cwd, "Edit", map[string]any{"file_path": "/Users/x/app/a.go"}),
+		asstText(ts, cwd, "テストを直しています"),
+	} {
+		if _, err := f.WriteString(line + "\n"); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	if _, err := f.WriteString(`{"type":"assistant","mess`); err != nil {
+		t.Fatalf("append partial: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	d, err := ReadDelta(path, first.Offset)
+	if err != nil {
+		t.Fatalf("ReadDelta: %v", err)
+	}
+	if d.From != first.Offset || d.Reset || d.Skipped != 0 {
+		t.Errorf("range = from %d reset %t skipped %d, want from %d", d.From, d.Reset, d.Skipped, first.Offset)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if want := info.Size() - int64(len(`{"type":"assistant","mess`)); d.Offset != want {
+		t.Errorf("Offset = %d, want %d (before the partial line)", d.Offset, want)
+	}
+	gotNames := make([]string, 0, len(d.ToolCalls))
+	for _, c := range d.ToolCalls {
+		gotNames = append(gotNames, c.Name)
+	}
+	if want := []string{"Edit", "Bash", "Write", "Edit"}; !slices.Equal(gotNames, want) {
+		t.Errorf("tool calls = %v, want %v", gotNames, want)
+	}
+	if !d.ToolCalls[1].IsError || d.ToolCalls[1].Target != "go test ./..." {
+		t.Errorf("bash call = %+v, want failed go test", d.ToolCalls[1])
+	}
+	if want := []string{"/Users/x/app/a.go", "/Users/x/app/b.go"}; !slices.Equal(d.EditedFiles, want) {
+		t.Errorf("EditedFiles = %v, want %v", d.EditedFiles, want)
+	}
+	if d.LastAssistant != "テストを直しています" {
+		t.Errorf("LastAssistant = %q", d.LastAssistant)
+	}
+
+	// 位置が末尾（書きかけの行の手前）なら活動なし
+	again, err := ReadDelta(path, d.Offset)
+	if err != nil {
+		t.Fatalf("ReadDelta: %v", err)
+	}
+	if !again.Empty() || again.Offset != d.Offset {
+		t.Errorf("re-read = %+v, want empty at %d", again, d.Offset)
+	}
+}
+
+// TestReadDelta_Reset は会話ログが前回位置より短くなった場合に先頭から読み直すことを検証します。
+func TestReadDelta_Reset(t *testing.T) {
+	path := writeLines(t, asstText("2026-07-20T10:00:00Z", cwd, "書き直し後"))
+
+	d, err := ReadDelta(path, 1<<20)
+	if err != nil {
+		t.Fatalf("ReadDelta: %v", err)
+	}
+	if !d.Reset || d.From != 0 || d.LastAssistant != "書き直し後" {
+		t.Errorf("delta = %+v, want reset from 0", d)
+	}
+}
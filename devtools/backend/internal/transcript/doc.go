@@ -18,8 +18,13 @@
 //   - NewReader: homeDir / projectsProvider / now を注入して Reader を生成する
 //   - parseTail: 末尾 tailReadBytes だけを読み最終実質エントリの種別（tailKind）を判定する
 //   - classifyRepresentative: 種別（内容）と mtime 鮮度を合成して最終 status を確定する純粋関数
+//   - classifySession: classifyRepresentative に実行許可待ちの推定を重ね、status と待機理由を確定する
 //   - deriveProjectID / discoverSessions: 走査ディレクトリ絞り込み用の project-id と候補列挙
 //   - parseCache: mtime 不変時の再パース抑制と entry-time 欠落版の署名→初回検出時刻の保持
+//   - ReadEntries / Entry: 分析・検索・エクスポート用の全読み（usage / tool_use id / tool_result / is_error を保持）
+//   - ListSessions / FindSession: 会話ログの列挙（reader と同じ discoverSessions）と session-id 検索
+//   - ReadDelta / Delta: 前回位置（offset）以降に追記された完結行からツール呼び出し・編集ファイル・最後の発言を集める
+//     （動作中セッションの進捗要約の材料。書きかけの末尾行は次回に回す）
 //
 // # 設計方針
 //
@@ -37,6 +42,10 @@
 //     Marker 化する（none は skip）。Marker.SessionCount は代表と同一 status のセッション数
 //   - Marker.Timestamp の意味は Status で分岐: waiting は最後の assistant エントリの entry-time
 //     （要約 key の安定同一性・C1。mtime は使わない）、running は代表セッションの mtime
+//   - 待機理由: parse が末尾の内容から question（AskUserQuestion）/ plan_approval（ExitPlanMode）/
+//     end_of_turn（text）を判定する。許可プロンプトは会話ログに残らないため、許可の要る未応答 tool_use
+//     （bypassPermissions・読み取り専用ツール・acceptEdits の編集系は除く）を permission 候補とし、
+//     reader が idle.PermissionWaitAge 以上 idle.RunningMaxAge 未満の滞留で waiting（permission）へ昇格する
 //   - C2: セッション帰属は実 cwd + idle.MatchProject。lossy な project-id glob は走査絞り込み専用
 //   - C3: 要約マージ（MergeSummaries）は List 内で行い Summary 込みの完成 Marker を返す契約。
 //     要約は waiting のみが対象で、孤児キャッシュ掃除の aliveKeys も waiting marker のみで構築する（W-2）
@@ -0,0 +1,243 @@
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// maxEntryLineBytes は全読み時に1行として受け付ける上限バイト数です。
+// 巨大な tool_result（ファイル全文の Read 結果等）を含む行でも読み切れるよう十分大きくし、
+// これを超える行は解釈せず読み飛ばします。
+const maxEntryLineBytes = 16 * 1024 * 1024
+
+// Entry は会話ログ JSONL 1行を全フィールド解釈した結果です。
+// 待機判定用の logEntry（末尾判定に必要な範囲のみ）とは別に、分析・検索・エクスポート等の
+// 全読み用途のために usage / tool_use id / tool_result / is_error を保持します。
+type Entry struct {
+	Type        string        `json:"type"`
+	UUID        string        `json:"uuid,omitempty"`
+	SessionID   string        `json:"sessionId,omitempty"`
+	Cwd         string        `json:"cwd,omitempty"`
+	Timestamp   time.Time     `json:"timestamp"`
+	IsSidechain bool          `json:"isSidechain,omitempty"`
+	IsMeta      bool          `json:"isMeta,omitempty"`
+	Message     *EntryMessage `json:"message,omitempty"`
+}
+
+// EntryMessage は assistant/user エントリの message 部です。
+// content は文字列（ユーザー発言）と要素配列の両形式があるため、
+// 文字列の場合は text 要素1件の配列に正規化します。
+type EntryMessage struct {
+	ID      string         `json:"id,omitempty"`
+	Role    string         `json:"role"`
+	Model   string         `json:"model,omitempty"`
+	Content []ContentBlock `json:"content"`
+	Usage   *Usage         `json:"usage,omitempty"`
+}
+
+// ContentBlock は message.content の1要素です。
+type ContentBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	Thinking  string          `json:"thinking,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	IsError   bool            `json:"is_error,omitempty"`
+	Content   json.RawMessage `json:"content,omitempty"`
+}
+
+// Usage は assistant メッセージのトークン使用量です。
+type Usage struct {
+	InputTokens              int64 `json:"input_tokens"`
+	OutputTokens             int64 `json:"output_tokens"`
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+}
+
+// rawEntry は Entry のデコード用中間表現です（timestamp 文字列と content の両形式を吸収）。
+type rawEntry struct {
+	Type        string `json:"type"`
+	UUID        string `json:"uuid"`
+	SessionID   string `json:"sessionId"`
+	Cwd         string `json:"cwd"`
+	Timestamp   string `json:"timestamp"`
+	IsSidechain bool   `json:"isSidechain"`
+	IsMeta      bool   `json:"isMeta"`
+	Message     *struct {
+		ID      string          `json:"id"`
+		Role    string          `json:"role"`
+		Model   string          `json:"model"`
+		Content json.RawMessage `json:"content"`
+		Usage   *Usage          `json:"usage"`
+	} `json:"message"`
+}
+
+// ReadEntries は会話ログ JSONL を先頭から全読みし、解釈できた行を Entry として返します。
+// 会話ログは公式非サポート形式のため、解釈できない行・上限超過の行は読み飛ばします（best-effort）。
+func ReadEntries(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript %s: %w", path, err)
+	}
+	defer f.Close()
+
+	entries, err := DecodeEntries(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transcript %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// DecodeEntries は r から JSONL を読み、解釈できた行を Entry として返します。
+func DecodeEntries(r io.Reader) ([]Entry, error) {
+	br := bufio.NewReaderSize(r, 64*1024)
+	out := make([]Entry, 0)
+	for {
+		line, err := readLine(br)
+		if len(line) > 0 {
+			if e, ok := decodeEntry(line); ok {
+				out = append(out, e)
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return out, nil
+			}
+			return nil, err
+		}
+	}
+}
+
+// readLine は改行までの1行を返します。maxEntryLineBytes を超える行は末尾まで読み捨てて空を返します。
+func readLine(br *bufio.Reader) ([]byte, error) {
+	var buf []byte
+	oversized := false
+	for {
+		chunk, err := br.ReadSlice('\n')
+		if !oversized {
+			if len(buf)+len(chunk) > maxEntryLineBytes {
+				oversized = true
+				buf = nil
+			} else {
+				buf = append(buf, chunk...)
+			}
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if oversized {
+			return nil, err
+		}
+		return bytes.TrimSpace(buf), err
+	}
+}
+
+// decodeEntry は JSONL 1行を Entry に変換します。解釈できない行は ok=false を返します。
+func decodeEntry(line []byte) (Entry, bool) {
+	var raw rawEntry
+	if err := json.Unmarshal(line, &raw); err != nil || raw.Type == "" {
+		return Entry{}, false
+	}
+
+	e := Entry{
+		Type:        raw.Type,
+		UUID:        raw.UUID,
+		SessionID:   raw.SessionID,
+		Cwd:         raw.Cwd,
+		IsSidechain: raw.IsSidechain,
+		IsMeta:      raw.IsMeta,
+	}
+	if raw.Timestamp != "" {
+		if t, err := time.Parse(time.RFC3339Nano, raw.Timestamp); err == nil {
+			e.Timestamp = t
+		}
+	}
+	if raw.Message != nil {
+		e.Message = &EntryMessage{
+			ID:      raw.Message.ID,
+			Role:    raw.Message.Role,
+			Model:   raw.Message.Model,
+			Content: decodeContent(raw.Message.Content),
+			Usage:   raw.Message.Usage,
+		}
+	}
+	return e, true
+}
+
+// decodeContent は content の文字列形式/配列形式を ContentBlock 配列に正規化します。
+func decodeContent(raw json.RawMessage) []ContentBlock {
+	if len(raw) == 0 {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if s == "" {
+			return nil
+		}
+		return []ContentBlock{{Type: "text", Text: s}}
+	}
+	var blocks []ContentBlock
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return nil
+	}
+	return blocks
+}
+
+// IsPrompt はエントリが人間のプロンプト（tool_result の返送ではない user 発言）かを返します。
+// isMeta（コマンド展開等の自動挿入）は除外します。
+func (e Entry) IsPrompt() bool {
+	if e.Type != "user" || e.IsMeta || e.Message == nil {
+		return false
+	}
+	for _, b := range e.Message.Content {
+		if b.Type == "tool_result" {
+			return false
+		}
+	}
+	return len(e.Message.Content) > 0
+}
+
+// Text はエントリの text 要素を改行で連結して返します。
+func (e Entry) Text() string {
+	if e.Message == nil {
+		return ""
+	}
+	parts := make([]string, 0, len(e.Message.Content))
+	for _, b := range e.Message.Content {
+		if b.Type == "text" && strings.TrimSpace(b.Text) != "" {
+			parts = append(parts, b.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// ResultText は tool_result 要素の content（文字列または text 要素配列）をテキストで返します。
+func (b ContentBlock) ResultText() string {
+	if len(b.Content) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(b.Content, &s); err == nil {
+		return s
+	}
+	var items []ContentBlock
+	if err := json.Unmarshal(b.Content, &items); err != nil {
+		return ""
+	}
+	parts := make([]string, 0, len(items))
+	for _, it := range items {
+		if it.Type == "text" {
+			parts = append(parts, it.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
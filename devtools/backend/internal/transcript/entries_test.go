@@ -0,0 +1,64 @@
+package transcript
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeEntries(t *testing.T) {
+	input := strings.Join([]string{
+		`{"type":"user","cwd":"/p","timestamp":"2026-07-01T00:00:00Z","message":{"role":"user","content":"こんにちは"}}`,
+		`not json`,
+		`{"type":"assistant","timestamp":"2026-07-01T00:00:02.5Z","message":{"id":"m1","role":"assistant","model":"claude-x","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"ls"}}],"usage":{"input_tokens":10,"output_tokens":5,"cache_creation_input_tokens":3,"cache_read_input_tokens":7}}}`,
+		`{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","is_error":true,"content":[{"type":"text","text":"boom"}]}]}}`,
+		``,
+	}, "\n")
+
+	entries, err := DecodeEntries(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("DecodeEntries error: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("entries = %d, want 3（解釈不能行は skip）", len(entries))
+	}
+
+	if !entries[0].IsPrompt() || entries[0].Text() != "こんにちは" || entries[0].Cwd != "/p" {
+		t.Errorf("文字列 content がプロンプトとして正規化されていない: %+v", entries[0])
+	}
+	if !entries[1].Timestamp.Equal(time.Date(2026, 7, 1, 0, 0, 2, 500000000, time.UTC)) {
+		t.Errorf("timestamp = %v", entries[1].Timestamp)
+	}
+	u := entries[1].Message.Usage
+	if u == nil || u.InputTokens != 10 || u.OutputTokens != 5 || u.CacheCreationInputTokens != 3 || u.CacheReadInputTokens != 7 {
+		t.Errorf("usage = %+v", u)
+	}
+	if b := entries[1].Message.Content[0]; b.ID != "t1" || b.Name != "Bash" {
+		t.Errorf("tool_use = %+v", b)
+	}
+	if entries[2].IsPrompt() {
+		t.Error("tool_result 返送はプロンプトではない")
+	}
+	if b := entries[2].Message.Content[0]; !b.IsError || b.ToolUseID != "t1" || b.ResultText() != "boom" {
+		t.Errorf("tool_result = %+v, text=%q", b, b.ResultText())
+	}
+}
+
+func TestFindSession(t *testing.T) {
+	home := t.TempDir()
+	writeSession(t, home, "-p", "sess-1", asstText("2026-07-01T00:00:00Z", "/p", "hi"))
+
+	s, err := FindSession(home, "sess-1")
+	if err != nil {
+		t.Fatalf("FindSession error: %v", err)
+	}
+	if s.ID != "sess-1" || !strings.HasSuffix(s.Path, "sess-1.jsonl") || s.Size == 0 {
+		t.Errorf("session = %+v", s)
+	}
+
+	for _, id := range []string{"missing", "../etc", ""} {
+		if _, err := FindSession(home, id); err == nil {
+			t.Errorf("FindSession(%q) should fail", id)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package transcript
+
+import "ghostrunner/backend/internal/metrics"
+
+// parseCacheLookupsTotal は会話ログのパース結果キャッシュの参照数です（result=hit / miss）。
+// ヒット率 = hit / (hit + miss)
+var parseCacheLookupsTotal = metrics.NewCounterVec("ghostrunner_transcript_parse_cache_lookups_total",
+	"Transcript parse cache lookups by result (hit, miss).", "result")
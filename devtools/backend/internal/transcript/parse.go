@@ -10,6 +10,8 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"ghostrunner/backend/internal/idle"
 )
 
 // tailReadBytes は会話ログ JSONL の末尾から読み取るバイト数です。
@@ -31,6 +33,31 @@ var substantiveEntryTypes = map[string]struct{}{
 	"assistant": {},
 }
 
+// bypassPermissionMode は許可プロンプトを一切出さない permissionMode です。
+// このモードでは未応答 tool_use を実行許可待ちと推定しません。
+const bypassPermissionMode = "bypassPermissions"
+
+// noPermissionTools は既定モードでも許可プロンプトを出さない（読み取り専用・内部管理の）ツールです。
+// これらの tool_use が未応答でも実行許可待ちとは推定しません。
+var noPermissionTools = map[string]struct{}{
+	"Read":       {},
+	"Glob":       {},
+	"Grep":       {},
+	"LS":         {},
+	"TodoWrite":  {},
+	"Task":       {},
+	"Agent":      {},
+	"BashOutput": {},
+}
+
+// editTools は acceptEdits モードで自動許可されるファイル編集系ツールです。
+var editTools = map[string]struct{}{
+	"Edit":         {},
+	"MultiEdit":    {},
+	"Write":        {},
+	"NotebookEdit": {},
+}
+
 // tailKind は最終実質エントリの種別です。mtime 鮮度の合成前の「内容だけ」の分類で、
 // reader が classifyRepresentative で mtime を合わせて最終 status（waiting/running/none）を確定します。
 type tailKind int
@@ -38,7 +65,7 @@ type tailKind int
 const (
 	// kindNone は応答済/解釈不能/実質エントリ皆無（ParseOK=false 含む）を表します。
 	kindNone tailKind = iota
-	// kindWaiting は末尾が未応答 assistant（text / AskUserQuestion）で質問待ちを表します。
+	// kindWaiting は末尾が未応答 assistant（text / AskUserQuestion / ExitPlanMode）で質問待ちを表します。
 	kindWaiting
 	// kindMidTurn は生成途中（未応答通常tool_use / thinking / user末尾で assistant 未応答）を表します。
 	kindMidTurn
@@ -55,7 +82,7 @@ type transcriptTail struct {
 	LastPrompt string
 	// LastAssistantAt は最後の assistant エントリ自身の timestamp（epoch秒・C1）です。
 	// mtime ではなく待機 episode の安定同一性を担保します。取得できなければ 0 です。
-	// kindWaiting でのみ設定します（running の Marker.Timestamp は reader が mtime を使う）。
+	// kindWaiting と permission 候補でのみ設定します（running の Marker.Timestamp は reader が mtime を使う）。
 	LastAssistantAt int64
 	// ContentHash は LastAssistantAt が取得できない版での安定キー用の本文署名です。
 	// 呼び出し側が「同一署名なら初回検出時刻を保持」してキーを安定化します（raw mtime fallback 禁止）。
@@ -64,17 +91,28 @@ type transcriptTail struct {
 	Cwd string
 	// Kind は最終実質エントリの種別（waiting/midTurn/none）です。
 	Kind tailKind
+	// Reason は待機理由です。kindWaiting では question / plan_approval / end_of_turn のいずれか、
+	// kindMidTurn では末尾が許可の要る未応答 tool_use の場合のみ permission（実行許可待ち候補）です。
+	// permission 候補を waiting に昇格するかは reader が mtime 鮮度で決めます（classifySession）。
+	Reason idle.WaitReason
+	// PermissionPreview は permission 候補の tool_use の表示用要約（"Bash: go test ./..." 等）です。
+	PermissionPreview string
+	// QuestionHeader / QuestionOptions は question 待機の AskUserQuestion の見出し（改行連結）と
+	// 選択肢ラベルです（自動回答ルールの照合に使う）。それ以外の待機では空です。
+	QuestionHeader  string
+	QuestionOptions []string
 	// ParseOK は種別を判定できたかを表します。false のとき Kind は kindNone です。
 	ParseOK bool
 }
 
 // logEntry は JSONL 1行の共通フィールドを表します（必要な範囲のみ）。
 type logEntry struct {
-	Type       string      `json:"type"`
-	Cwd        string      `json:"cwd"`
-	Timestamp  string      `json:"timestamp"`
-	LastPrompt string      `json:"lastPrompt"`
-	Message    *logMessage `json:"message"`
+	Type           string      `json:"type"`
+	Cwd            string      `json:"cwd"`
+	Timestamp      string      `json:"timestamp"`
+	LastPrompt     string      `json:"lastPrompt"`
+	PermissionMode string      `json:"permissionMode"`
+	Message        *logMessage `json:"message"`
 }
 
 // logMessage は assistant/user エントリの message 部を表します。
@@ -154,6 +192,7 @@ func analyzeEntries(data []byte) (tail transcriptTail, found bool) {
 		lastPrompt   string
 		cwd          string
 		lastAsstText string // tail 内で最後に見た assistant テキスト（running preview 材料・W-5改）
+		permMode     string // tail 内で最後に見た permissionMode（permission-mode 帳簿 / user エントリが持つ）
 	)
 
 	for _, raw := range lines {
@@ -171,6 +210,9 @@ func analyzeEntries(data []byte) (tail transcriptTail, found bool) {
 		if e.Cwd != "" {
 			cwd = e.Cwd
 		}
+		if e.PermissionMode != "" {
+			permMode = e.PermissionMode
+		}
 
 		// LastPrompt は last-prompt 帳簿エントリから抽出します（要約の材料）。
 		// これは「最終実質エントリ判定」とは独立で、last-prompt 自体は実質エントリではありません。
@@ -226,7 +268,7 @@ func analyzeEntries(data []byte) (tail transcriptTail, found bool) {
 		return tail, true
 	}
 
-	kind, text, parsed := classifyAssistant(lastSub.Message.Content)
+	kind, text, reason, parsed := classifyAssistant(lastSub.Message.Content)
 	if !parsed {
 		// content 解釈不能 → 保守的に none
 		tail.ParseOK = false
@@ -236,58 +278,143 @@ func analyzeEntries(data []byte) (tail transcriptTail, found bool) {
 
 	tail.ParseOK = true
 	tail.Kind = kind
+	tail.Reason = reason
 	if kind == kindWaiting {
 		// waiting は分類済みテキスト（末尾 text または質問文）をそのまま preview に使う。
 		tail.LastAssistant = text
 		// entry-time は待機 episode の安定同一性（要約 key）に使う（C1）。running は reader が mtime を使う。
 		tail.LastAssistantAt, tail.ContentHash = entryTimeOrHash(lastSub.Timestamp, text)
+		if reason == idle.WaitQuestion {
+			if tool, ok := pendingToolUse(lastSub.Message.Content); ok {
+				tail.QuestionHeader, tail.QuestionOptions = extractQuestionChoices(tool.Input)
+			}
+		}
 	} else {
 		// midTurn（末尾 tool_use / thinking）: running preview は tail 全体で最後に見た
 		// assistant テキストを使う（同一 assistant 内に text が無くても直近の発言を出せる）。
 		tail.LastAssistant = lastAsstText
+
+		// 末尾が許可の要る未応答 tool_use なら実行許可待ち候補にする。許可プロンプト自体は
+		// 会話ログに残らないため、ここでは候補に留め、滞留時間による昇格は reader が行う。
+		if tool, ok := pendingToolUse(lastSub.Message.Content); ok && needsPermission(tool.Name, permMode) {
+			tail.Reason = idle.WaitPermission
+			tail.PermissionPreview = toolPreview(tool)
+			tail.LastAssistantAt, tail.ContentHash = entryTimeOrHash(lastSub.Timestamp, tail.PermissionPreview)
+		}
 	}
 	return tail, true
 }
 
-// classifyAssistant は assistant の message.content から種別を判定します。
-//   - 末尾が text → kindWaiting（回答提示後のユーザー返信待ち。text を preview に）
-//   - 末尾が AskUserQuestion tool_use → kindWaiting（質問文を preview に）
+// classifyAssistant は assistant の message.content から種別と待機理由を判定します。
+//   - 末尾が text → kindWaiting / end_of_turn（回答提示後のユーザー返信待ち。text を preview に）
+//   - 末尾が AskUserQuestion tool_use → kindWaiting / question（質問文を preview に）
+//   - 末尾が ExitPlanMode tool_use → kindWaiting / plan_approval（計画本文を preview に）
 //   - 末尾が通常 tool_use（Bash/Edit 等）で結果未着 → kindMidTurn（生成途中・running 候補）
 //   - 末尾が thinking 等 → kindMidTurn（生成途中・running 候補）
 //
-// text は kindWaiting でのみ意味を持ちます（末尾 text または質問文）。midTurn の running preview は
+// text は kindWaiting でのみ意味を持ちます（末尾 text・質問文・計画本文）。midTurn の running preview は
 // 呼び出し側が tail 全体で最後に見た assistant テキストを使うため、ここでは "" を返します（W-5改）。
 // parsed=false は content が解釈不能（配列でない・空）で ParseOK=false / kindNone に倒すべき場合です。
-func classifyAssistant(content json.RawMessage) (kind tailKind, text string, parsed bool) {
+func classifyAssistant(content json.RawMessage) (kind tailKind, text string, reason idle.WaitReason, parsed bool) {
 	var items []contentItem
 	if err := json.Unmarshal(content, &items); err != nil {
-		return kindNone, "", false
+		return kindNone, "", "", false
 	}
 	if len(items) == 0 {
-		return kindNone, "", false
+		return kindNone, "", "", false
 	}
 
 	last := items[len(items)-1]
 	switch last.Type {
 	case "text":
-		return kindWaiting, last.Text, true
+		return kindWaiting, last.Text, idle.WaitEndOfTurn, true
 	case "tool_use":
-		if last.Name == "AskUserQuestion" {
+		switch last.Name {
+		case "AskUserQuestion":
 			q := extractQuestions(last.Input)
 			if q == "" {
 				// 質問文が取れなければ直前の text を preview に流用
 				q = lastTextBefore(items)
 			}
-			return kindWaiting, q, true
+			return kindWaiting, q, idle.WaitQuestion, true
+		case "ExitPlanMode":
+			plan := extractPlan(last.Input)
+			if plan == "" {
+				plan = lastTextBefore(items)
+			}
+			return kindWaiting, plan, idle.WaitPlanApproval, true
 		}
 		// 通常 tool_use は結果未着 = 生成途中 → midTurn（preview は呼び出し側が lastAsstText を使う）
-		return kindMidTurn, "", true
+		return kindMidTurn, "", "", true
 	default:
 		// thinking 等の生成途中 → midTurn（preview は呼び出し側が lastAsstText を使う）
-		return kindMidTurn, "", true
+		return kindMidTurn, "", "", true
 	}
 }
 
+// pendingToolUse は content の末尾が tool_use ならその要素を返します。
+func pendingToolUse(content json.RawMessage) (contentItem, bool) {
+	var items []contentItem
+	if err := json.Unmarshal(content, &items); err != nil || len(items) == 0 {
+		return contentItem{}, false
+	}
+	last := items[len(items)-1]
+	if last.Type != "tool_use" {
+		return contentItem{}, false
+	}
+	return last, true
+}
+
+// needsPermission は permissionMode 下でツール name の実行に許可プロンプトが出うるかを判定します。
+// bypassPermissions は常に不要、acceptEdits は編集系ツールが不要、読み取り専用ツールはモードに関わらず不要です。
+func needsPermission(name, permissionMode string) bool {
+	if permissionMode == bypassPermissionMode {
+		return false
+	}
+	if _, ok := noPermissionTools[name]; ok {
+		return false
+	}
+	if _, ok := editTools[name]; ok && permissionMode == "acceptEdits" {
+		return false
+	}
+	return true
+}
+
+// toolPreview は許可待ち tool_use の表示用要約を返します（"Bash: go test ./..." 等）。
+// input の代表的なキー（command / file_path / url / path / pattern）を先頭から1つ採用します。
+func toolPreview(item contentItem) string {
+	if target := toolTarget(item.Input); target != "" {
+		return item.Name + ": " + target
+	}
+	return item.Name
+}
+
+// toolTarget は tool_use の input から代表的な対象（command / file_path / notebook_path / url / path / pattern の
+// 先頭から最初に見つかった値）を返します。見つからなければ空を返します。
+func toolTarget(input json.RawMessage) string {
+	var in map[string]any
+	if err := json.Unmarshal(input, &in); err != nil {
+		return ""
+	}
+	for _, key := range []string{"command", "file_path", "notebook_path", "url", "path", "pattern"} {
+		if v, ok := in[key].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// extractPlan は ExitPlanMode の input.plan（承認を求める計画本文）を返します。
+func extractPlan(input json.RawMessage) string {
+	var in struct {
+		Plan string `json:"plan"`
+	}
+	if err := json.Unmarshal(input, &in); err != nil {
+		return ""
+	}
+	return in.Plan
+}
+
 // lastAssistantText は assistant の message.content から最後の text ブロックを返します。
 // running preview 材料として、tool_use / thinking で終わる assistant メッセージでも
 // その中に含まれる直近の発言テキストを取り出します。text が無ければ空を返します。
@@ -318,6 +445,34 @@ func extractQuestions(input json.RawMessage) string {
 	return strings.Join(questions, "\n")
 }
 
+// extractQuestionChoices は AskUserQuestion の input.questions[].header を改行連結した見出しと、
+// options[].label を質問順に並べた選択肢を返します。
+func extractQuestionChoices(input json.RawMessage) (header string, options []string) {
+	var in struct {
+		Questions []struct {
+			Header  string `json:"header"`
+			Options []struct {
+				Label string `json:"label"`
+			} `json:"options"`
+		} `json:"questions"`
+	}
+	if err := json.Unmarshal(input, &in); err != nil {
+		return "", nil
+	}
+	headers := make([]string, 0, len(in.Questions))
+	for _, q := range in.Questions {
+		if q.Header != "" {
+			headers = append(headers, q.Header)
+		}
+		for _, o := range q.Options {
+			if o.Label != "" {
+				options = append(options, o.Label)
+			}
+		}
+	}
+	return strings.Join(headers, "\n"), options
+}
+
 // lastTextBefore は content 内の最後の text 要素を返します（AskUserQuestion の preview fallback）。
 func lastTextBefore(items []contentItem) string {
 	for i := len(items) - 1; i >= 0; i-- {
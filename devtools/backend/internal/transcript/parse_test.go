@@ -7,6 +7,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"ghostrunner/backend/internal/idle"
 )
 
 // j は map をコンパクト JSON 文字列にします（テスト用ビルダー）。map の Marshal は失敗しないため error は無視します。
@@ -281,6 +283,149 @@ func TestParseTail_Classification(t *testing.T) {
 	}
 }
 
+func asstTool(ts, cwd, name string, input map[string]any) string {
+	return j(map[string]any{
+		"type": "assistant", "cwd": cwd, "timestamp": ts,
+		"message": map[string]any{"role": "assistant", "content": []any{
+			map[string]any{"type": "tool_use", "name": name, "input": input},
+		}},
+	})
+}
+
+func permissionModeEntry(cwd, mode string) string {
+	return j(map[string]any{"type": "permission-mode", "cwd": cwd, "permissionMode": mode})
+}
+
+// TestParseTail_WaitReason は末尾の内容から待機理由（質問・計画承認・許可待ち候補・返信待ち）を
+// 判定し、許可待ち候補は permissionMode と読み取り専用ツールを考慮することを検証します。
+func TestParseTail_WaitReason(t *testing.T) {
+	tests := []struct {
+		name              string
+		lines             []string
+		wantKind          tailKind
+		wantReason        idle.WaitReason
+		wantLastAssistant string
+		wantPermPreview   string
+	}{
+		{
+			name:              "末尾textは返信待ち",
+			lines:             []string{asstText("2026-07-20T10:00:00Z", cwd, "完了しました")},
+			wantKind:          kindWaiting,
+			wantReason:        idle.WaitEndOfTurn,
+			wantLastAssistant: "完了しました",
+		},
+		{
+			name:              "AskUserQuestionは質問待ち",
+			lines:             []string{asstAsk("2026-07-20T10:00:00Z", cwd, "案Aと案Bどちら?")},
+			wantKind:          kindWaiting,
+			wantReason:        idle.WaitQuestion,
+			wantLastAssistant: "案Aと案Bどちら?",
+		},
+		{
+			name:              "ExitPlanModeは計画承認待ち・計画本文preview",
+			lines:             []string{asstTool("2026-07-20T10:00:00Z", cwd, "ExitPlanMode", map[string]any{"plan": "1. APIを追加\n2. テスト"})},
+			wantKind:          kindWaiting,
+			wantReason:        idle.WaitPlanApproval,
+			wantLastAssistant: "1. APIを追加\n2. テスト",
+		},
+		{
+			name:            "既定モードの未応答Bashは許可待ち候補",
+			lines:           []string{asstBash("2026-07-20T10:00:00Z", cwd)},
+			wantKind:        kindMidTurn,
+			wantReason:      idle.WaitPermission,
+			wantPermPreview: "Bash: ls",
+		},
+		{
+			name:     "bypassPermissionsでは許可待ち候補にしない",
+			lines:    []string{permissionModeEntry(cwd, "bypassPermissions"), asstBash("2026-07-20T10:00:00Z", cwd)},
+			wantKind: kindMidTurn,
+		},
+		{
+			name:     "読み取り専用ツールは許可待ち候補にしない",
+			lines:    []string{asstTool("2026-07-20T10:00:00Z", cwd, "Read", map[string]any{"file_path": "/tmp/a.go"})},
+			wantKind: kindMidTurn,
+		},
+		{
+			name:     "acceptEditsの編集ツールは許可待ち候補にしない",
+			lines:    []string{permissionModeEntry(cwd, "acceptEdits"), asstTool("2026-07-20T10:00:00Z", cwd, "Edit", map[string]any{"file_path": "/tmp/a.go"})},
+			wantKind: kindMidTurn,
+		},
+		{
+			name:            "acceptEditsでも編集以外は許可待ち候補",
+			lines:           []string{permissionModeEntry(cwd, "acceptEdits"), asstTool("2026-07-20T10:00:00Z", cwd, "WebFetch", map[string]any{"url": "https://example.com"})},
+			wantKind:        kindMidTurn,
+			wantReason:      idle.WaitPermission,
+			wantPermPreview: "WebFetch: https://example.com",
+		},
+		{
+			name:     "thinking末尾は理由なし",
+			lines:    []string{asstThinking("2026-07-20T10:00:00Z", cwd)},
+			wantKind: kindMidTurn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tail, err := parseTail(writeLines(t, tt.lines...))
+			if err != nil {
+				t.Fatalf("parseTail error: %v", err)
+			}
+			if tail.Kind != tt.wantKind {
+				t.Errorf("Kind = %v, want %v", tail.Kind, tt.wantKind)
+			}
+			if tail.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", tail.Reason, tt.wantReason)
+			}
+			if tt.wantKind == kindWaiting && tail.LastAssistant != tt.wantLastAssistant {
+				t.Errorf("LastAssistant = %q, want %q", tail.LastAssistant, tt.wantLastAssistant)
+			}
+			if tail.PermissionPreview != tt.wantPermPreview {
+				t.Errorf("PermissionPreview = %q, want %q", tail.PermissionPreview, tt.wantPermPreview)
+			}
+			if tt.wantReason == idle.WaitPermission && tail.LastAssistantAt != epoch(t, "2026-07-20T10:00:00Z") {
+				t.Errorf("LastAssistantAt = %d, want entry-time", tail.LastAssistantAt)
+			}
+		})
+	}
+}
+
+// TestParseTail_QuestionChoices は質問待ちで AskUserQuestion の見出しと選択肢ラベルを取り出すことを検証します。
+func TestParseTail_QuestionChoices(t *testing.T) {
+	ask := j(map[string]any{
+		"type": "assistant", "cwd": cwd, "timestamp": "2026-07-20T10:00:00Z",
+		"message": map[string]any{"role": "assistant", "content": []any{
+			map[string]any{"type": "tool_use", "name": "AskUserQuestion", "input": map[string]any{"questions": []any{
+				map[string]any{"question": "どちらで進めますか?", "header": "方針", "options": []any{
+					map[string]any{"label": "案A", "description": "既存APIを拡張"},
+					map[string]any{"label": "案B"},
+				}},
+				map[string]any{"question": "テストも書きますか?", "header": "テスト", "options": []any{
+					map[string]any{"label": "はい"},
+				}},
+			}}},
+		}},
+	})
+	tail, err := parseTail(writeLines(t, ask))
+	if err != nil {
+		t.Fatalf("parseTail error: %v", err)
+	}
+	if tail.Reason != idle.WaitQuestion || tail.QuestionHeader != "方針\nテスト" {
+		t.Errorf("Reason = %q, QuestionHeader = %q", tail.Reason, tail.QuestionHeader)
+	}
+	if got := strings.Join(tail.QuestionOptions, ","); got != "案A,案B,はい" {
+		t.Errorf("QuestionOptions = %q, want 案A,案B,はい", got)
+	}
+
+	// 質問以外の待機では空
+	tail, err = parseTail(writeLines(t, asstText("2026-07-20T10:00:00Z", cwd, "完了しました")))
+	if err != nil {
+		t.Fatalf("parseTail error: %v", err)
+	}
+	if tail.QuestionHeader != "" || tail.QuestionOptions != nil {
+		t.Errorf("end_of_turn: QuestionHeader = %q, QuestionOptions = %v", tail.QuestionHeader, tail.QuestionOptions)
+	}
+}
+
 // TestParseTail_EmptyFile は空ファイルが ParseOK=false になることを検証します（section1 case10）。
 func TestParseTail_EmptyFile(t *testing.T) {
 	dir := t.TempDir()
@@ -0,0 +1,98 @@
+package transcript
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Progress は実行中セッションの進捗監視（停滞検出）用に会話ログ末尾を要約した結果です。
+// 会話ログの伸び（Size / ModTime）と、末尾で同じツール呼び出しが失敗し続けているかを表します。
+type Progress struct {
+	Size    int64
+	ModTime time.Time
+	// MidTurn は最終実質エントリが生成途中（未応答 tool_use / tool_result 後の応答待ち / thinking）かを表します。
+	MidTurn bool
+	// RepeatedFailures は末尾で連続する「同一ツール・同一入力」の失敗回数です（失敗でなければ 0）。
+	RepeatedFailures int
+	// FailingTool / FailingInput は連続失敗しているツール名と入力（表示用に切り詰め済み）です。
+	FailingTool  string
+	FailingInput string
+}
+
+// maxFailingInputRunes は Progress.FailingInput に載せる最大文字数です。
+const maxFailingInputRunes = 200
+
+// InspectProgress は会話ログ path の末尾 tailReadBytes を読み、進捗監視用の Progress を返します。
+// 全読みはせず、連続失敗の検出も末尾窓内のツール呼び出しのみで行います。
+func InspectProgress(path string) (Progress, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Progress{}, fmt.Errorf("failed to stat transcript %s: %w", path, err)
+	}
+	data, err := readTailBytes(path, tailReadBytes)
+	if err != nil {
+		return Progress{}, fmt.Errorf("failed to read transcript tail %s: %w", path, err)
+	}
+
+	p := Progress{Size: info.Size(), ModTime: info.ModTime()}
+	tail, _ := analyzeEntries(data)
+	p.MidTurn = tail.Kind == kindMidTurn
+
+	// メモリ上のバイト列の読み取りは失敗しない（壊れ行は DecodeEntries が skip する）
+	entries, _ := DecodeEntries(bytes.NewReader(data))
+	p.RepeatedFailures, p.FailingTool, p.FailingInput = trailingFailures(entries)
+	return p, nil
+}
+
+// toolAttempt は tool_use とその結果の組です。
+type toolAttempt struct {
+	name    string
+	input   string
+	isError bool
+}
+
+// trailingFailures は結果の届いたツール呼び出しを時系列に並べ、末尾から「同一ツール・同一入力の失敗」が
+// 何回連続しているかを返します。末尾の呼び出しが成功していれば 0 です。
+func trailingFailures(entries []Entry) (count int, name, input string) {
+	calls := make(map[string]toolAttempt)
+	var attempts []toolAttempt
+	for _, e := range entries {
+		if e.Message == nil {
+			continue
+		}
+		for _, b := range e.Message.Content {
+			switch b.Type {
+			case "tool_use":
+				if b.ID != "" {
+					calls[b.ID] = toolAttempt{name: b.Name, input: string(b.Input)}
+				}
+			case "tool_result":
+				call, ok := calls[b.ToolUseID]
+				if !ok {
+					continue
+				}
+				call.isError = b.IsError
+				attempts = append(attempts, call)
+			}
+		}
+	}
+
+	if len(attempts) == 0 || !attempts[len(attempts)-1].isError {
+		return 0, "", ""
+	}
+	last := attempts[len(attempts)-1]
+	for i := len(attempts) - 1; i >= 0; i-- {
+		a := attempts[i]
+		if !a.isError || a.name != last.name || a.input != last.input {
+			break
+		}
+		count++
+	}
+	in := []rune(last.input)
+	if len(in) > maxFailingInputRunes {
+		in = append(in[:maxFailingInputRunes], '…')
+	}
+	return count, last.name, string(in)
+}
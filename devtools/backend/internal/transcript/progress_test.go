@@ -0,0 +1,86 @@
+package transcript
+
+import (
+	"testing"
+)
+
+func toolUse(id, name, command string) string {
+	return j(map[string]any{
+		"type": "assistant", "cwd": cwd, "timestamp": "2026-07-20T10:00:00Z",
+		"message": map[string]any{"role": "assistant", "content": []any{
+			map[string]any{"type": "tool_use", "id": id, "name": name, "input": map[string]any{"command": command}},
+		}},
+	})
+}
+
+func toolResult(id string, isError bool) string {
+	return j(map[string]any{
+		"type": "user", "cwd": cwd,
+		"message": map[string]any{"role": "user", "content": []any{
+			map[string]any{"type": "tool_result", "tool_use_id": id, "content": "out", "is_error": isError},
+		}},
+	})
+}
+
+// TestInspectProgress は末尾の連続失敗回数と生成途中判定を検証します。
+func TestInspectProgress(t *testing.T) {
+	tests := []struct {
+		name        string
+		lines       []string
+		wantFails   int
+		wantTool    string
+		wantMidTurn bool
+	}{
+		{
+			name: "同一コマンドの3連続失敗",
+			lines: []string{
+				toolUse("t1", "Bash", "go test"), toolResult("t1", true),
+				toolUse("t2", "Bash", "go test"), toolResult("t2", true),
+				toolUse("t3", "Bash", "go test"), toolResult("t3", true),
+			},
+			wantFails:   3,
+			wantTool:    "Bash",
+			wantMidTurn: true,
+		},
+		{
+			name: "入力が変われば連続に数えない",
+			lines: []string{
+				toolUse("t1", "Bash", "go build"), toolResult("t1", true),
+				toolUse("t2", "Bash", "go test"), toolResult("t2", true),
+			},
+			wantFails:   1,
+			wantTool:    "Bash",
+			wantMidTurn: true,
+		},
+		{
+			name: "末尾が成功なら0",
+			lines: []string{
+				toolUse("t1", "Bash", "go test"), toolResult("t1", true),
+				toolUse("t2", "Bash", "go test"), toolResult("t2", false),
+			},
+			wantMidTurn: true,
+		},
+		{
+			name:  "ターン終了は生成途中でない",
+			lines: []string{toolUse("t1", "Bash", "go test"), toolResult("t1", false), asstText("2026-07-20T10:01:00Z", cwd, "完了")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := InspectProgress(writeLines(t, tt.lines...))
+			if err != nil {
+				t.Fatalf("InspectProgress: %v", err)
+			}
+			if p.RepeatedFailures != tt.wantFails || p.FailingTool != tt.wantTool {
+				t.Errorf("failures = %d/%q, want %d/%q", p.RepeatedFailures, p.FailingTool, tt.wantFails, tt.wantTool)
+			}
+			if p.MidTurn != tt.wantMidTurn {
+				t.Errorf("MidTurn = %v, want %v", p.MidTurn, tt.wantMidTurn)
+			}
+			if p.Size == 0 || p.ModTime.IsZero() {
+				t.Errorf("Size/ModTime not set: %+v", p)
+			}
+		})
+	}
+}
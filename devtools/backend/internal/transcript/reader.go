@@ -43,6 +43,7 @@ type classifiedSession struct {
 	sf     sessionFile
 	tail   transcriptTail
 	status idle.Status // "" は none（マーカー化しない）
+	reason idle.WaitReason
 }
 
 // classifyRepresentative は種別（内容）と mtime 鮮度を合成して最終 status を確定する純粋関数です（C-2）。
@@ -70,6 +71,22 @@ func classifyRepresentative(kind tailKind, mtimeAge time.Duration) idle.Status {
 	}
 }
 
+// classifySession は classifyRepresentative に実行許可待ちの推定を重ね、最終 status と待機理由を確定します。
+// 末尾が許可の要る未応答 tool_use（Reason=permission 候補）で、mtimeAge が PermissionWaitAge 以上
+// RunningMaxAge 未満なら waiting（permission）とします。RunningMaxAge 以上は従来どおり固まった
+// tool_use とみなし none に倒します。waiting 以外の status では待機理由は空です。
+func classifySession(tail transcriptTail, mtimeAge time.Duration) (idle.Status, idle.WaitReason) {
+	if tail.Kind == kindMidTurn && tail.Reason == idle.WaitPermission &&
+		mtimeAge >= idle.PermissionWaitAge && mtimeAge < idle.RunningMaxAge {
+		return idle.StatusWaiting, idle.WaitPermission
+	}
+	status := classifyRepresentative(tail.Kind, mtimeAge)
+	if status != idle.StatusWaiting {
+		return status, ""
+	}
+	return status, tail.Reason
+}
+
 // List は登録プロジェクトの会話ログを走査し、プロジェクト毎に最新 mtime の代表セッション1件を
 // 分類（running/waiting/none）して idle.Marker を返します。none 代表はマーカー化しません。
 // 帰属は各セッションの実 cwd + idle.MatchProject で判定します（C2）。Marker.Timestamp は
@@ -117,8 +134,8 @@ func (r *transcriptReader) List(ctx context.Context) ([]idle.Marker, error) {
 			continue
 		}
 
-		status := classifyRepresentative(tail.Kind, now.Sub(sf.modTime))
-		byProject[matched] = append(byProject[matched], classifiedSession{sf: sf, tail: tail, status: status})
+		status, reason := classifySession(tail, now.Sub(sf.modTime))
+		byProject[matched] = append(byProject[matched], classifiedSession{sf: sf, tail: tail, status: status, reason: reason})
 	}
 
 	r.cache.prune(alive)
@@ -178,8 +195,13 @@ func countByStatus(sessions []classifiedSession, status idle.Status) int {
 
 // buildMarker は代表セッションから idle.Marker を組み立てます。
 // Timestamp は waiting なら entry-time（C1・要約 key の同一性）、running なら mtime です。
+// 実行許可待ちの preview は直近の発言ではなく許可を求めているツール呼び出しの要約です。
 func (r *transcriptReader) buildMarker(rep classifiedSession, count int, now time.Time) idle.Marker {
 	var ts int64
+	lastAssistant := rep.tail.LastAssistant
+	if rep.reason == idle.WaitPermission {
+		lastAssistant = rep.tail.PermissionPreview
+	}
 	if rep.status == idle.StatusWaiting {
 		ts = rep.tail.LastAssistantAt
 		if ts == 0 {
@@ -191,12 +213,19 @@ func (r *transcriptReader) buildMarker(rep classifiedSession, count int, now tim
 	}
 
 	return idle.Marker{
-		Cwd:          rep.tail.Cwd,
-		SessionID:    rep.sf.sessionID,
-		Timestamp:    ts,
-		Status:       rep.status,
-		SessionCount: count,
-		RawTail:      idle.RawTail{LastAssistant: rep.tail.LastAssistant, LastPrompt: rep.tail.LastPrompt},
+		Cwd:            rep.tail.Cwd,
+		SessionID:      rep.sf.sessionID,
+		Timestamp:      ts,
+		Status:         rep.status,
+		WaitReason:     rep.reason,
+		TranscriptPath: rep.sf.path,
+		SessionCount:   count,
+		RawTail: idle.RawTail{
+			LastAssistant:   lastAssistant,
+			LastPrompt:      rep.tail.LastPrompt,
+			QuestionHeader:  rep.tail.QuestionHeader,
+			QuestionOptions: rep.tail.QuestionOptions,
+		},
 		Summary:      "",
 		SummarizedAt: "",
 	}
@@ -207,8 +236,10 @@ func (r *transcriptReader) buildMarker(rep classifiedSession, count int, now tim
 // check(lock)→miss判定→unlock→parseTail(IO)→lock→store。
 func (r *transcriptReader) tailFor(sf sessionFile) (transcriptTail, bool) {
 	if tail, ok := r.cache.get(sf.path, sf.modTime); ok {
+		parseCacheLookupsTotal.Inc("hit")
 		return tail, true
 	}
+	parseCacheLookupsTotal.Inc("miss")
 	tail, err := parseTail(sf.path)
 	if err != nil {
 		log.Printf("[transcript] skip session (parse failed): path=%s, error=%v", sf.path, err)
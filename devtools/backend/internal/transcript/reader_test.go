@@ -113,6 +113,71 @@ func TestClassifyRepresentative_ThresholdSSOT(t *testing.T) {
 	}
 }
 
+// TestClassifySession_PermissionWait は許可待ち候補の未応答 tool_use が PermissionWaitAge 以上
+// RunningMaxAge 未満でのみ waiting（permission）へ昇格し、それ以外は classifyRepresentative に従うことを検証します。
+func TestClassifySession_PermissionWait(t *testing.T) {
+	perm := transcriptTail{Kind: kindMidTurn, Reason: idle.WaitPermission}
+	plain := transcriptTail{Kind: kindMidTurn}
+	plan := transcriptTail{Kind: kindWaiting, Reason: idle.WaitPlanApproval}
+
+	tests := []struct {
+		name       string
+		tail       transcriptTail
+		mtimeAge   time.Duration
+		wantStatus idle.Status
+		wantReason idle.WaitReason
+	}{
+		{"許可待ち候補/1m→running", perm, 1 * time.Minute, idle.StatusRunning, ""},
+		{"許可待ち候補/PermissionWaitAge直前→running", perm, idle.PermissionWaitAge - time.Nanosecond, idle.StatusRunning, ""},
+		{"許可待ち候補/PermissionWaitAge→permission", perm, idle.PermissionWaitAge, idle.StatusWaiting, idle.WaitPermission},
+		{"許可待ち候補/RunningMaxAge→none", perm, idle.RunningMaxAge, "", ""},
+		{"候補でないmidTurn/5m→running", plain, 5 * time.Minute, idle.StatusRunning, ""},
+		{"計画承認/30s→running(理由なし)", plan, 30 * time.Second, idle.StatusRunning, ""},
+		{"計画承認/2m→plan_approval", plan, 2 * time.Minute, idle.StatusWaiting, idle.WaitPlanApproval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, reason := classifySession(tt.tail, tt.mtimeAge)
+			if status != tt.wantStatus || reason != tt.wantReason {
+				t.Errorf("classifySession = (%q, %q), want (%q, %q)", status, reason, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
+
+// TestTranscriptReaderList_PermissionWaitMarker は許可の要る tool_use が滞留したセッションが
+// WaitReason=permission の waiting Marker になり、preview がツール呼び出しの要約になることを検証します。
+func TestTranscriptReaderList_PermissionWaitMarker(t *testing.T) {
+	nowStr := "2026-07-20T10:30:00Z"
+	now, _ := time.Parse(time.RFC3339, nowStr)
+	appPath := "/Users/x/app"
+	home := t.TempDir()
+
+	ts := "2026-07-20T10:25:00Z"
+	path := writeSession(t, home, "-Users-x-app", "perm", asstBash(ts, appPath))
+	chtimesAge(t, path, now, 5*time.Minute)
+
+	r := NewReader(home, provider(projects.Project{Path: appPath, Name: "app"}), fixedNow(nowStr), filepath.Join(home, "summaries"))
+	markers, err := r.List(context.Background())
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(markers) != 1 {
+		t.Fatalf("markers = %d, want 1", len(markers))
+	}
+	m := markers[0]
+	if m.Status != idle.StatusWaiting || m.WaitReason != idle.WaitPermission {
+		t.Errorf("Status/WaitReason = %q/%q, want waiting/permission", m.Status, m.WaitReason)
+	}
+	if m.RawTail.LastAssistant != "Bash: ls" {
+		t.Errorf("LastAssistant = %q, want tool preview", m.RawTail.LastAssistant)
+	}
+	if m.Timestamp != epoch(t, ts) {
+		t.Errorf("Timestamp = %d, want entry-time %d", m.Timestamp, epoch(t, ts))
+	}
+}
+
 // chtimesAge は path の mtime を now-age に設定します（mtime 鮮度注入ヘルパー）。
 func chtimesAge(t *testing.T, path string, now time.Time, age time.Duration) {
 	t.Helper()
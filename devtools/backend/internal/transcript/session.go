@@ -0,0 +1,77 @@
+package transcript
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"ghostrunner/backend/internal/projects"
+)
+
+// Session は ~/.claude/projects/ 配下の会話ログ1件の所在情報です。
+// 帰属プロジェクトは含みません（lossy な project-id では決められないため、
+// 呼び出し側が会話ログ内の実 cwd + idle.MatchProject で判定します・C2）。
+type Session struct {
+	ID      string    `json:"id"`
+	Path    string    `json:"path"`
+	ModTime time.Time `json:"modTime"`
+	Size    int64     `json:"size"`
+}
+
+// ListSessions は登録プロジェクトに対応しうる会話ログを mtime 降順で列挙します。
+// 列挙規則は reader と同じ discoverSessions（project-id 前方一致 glob + 全走査 fallback）です。
+func ListSessions(homeDir string, projs []projects.Project) ([]Session, error) {
+	files, err := discoverSessions(homeDir, projs)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Session, 0, len(files))
+	for _, f := range files {
+		info, err := os.Stat(f.path)
+		if err != nil {
+			continue
+		}
+		out = append(out, Session{
+			ID:      f.sessionID,
+			Path:    f.path,
+			ModTime: info.ModTime(),
+			Size:    info.Size(),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ModTime.After(out[j].ModTime) })
+	return out, nil
+}
+
+// FindSession は session-id に一致する会話ログを ~/.claude/projects/*/ から探します。
+// 見つからない場合は os.ErrNotExist をラップしたエラーを返します。
+func FindSession(homeDir, sessionID string) (Session, error) {
+	if sessionID == "" || sessionID != filepath.Base(sessionID) || sessionID == "." || sessionID == ".." ||
+		strings.ContainsAny(sessionID, `*?[\`) {
+		return Session{}, fmt.Errorf("invalid session id %q: %w", sessionID, os.ErrNotExist)
+	}
+
+	pattern := filepath.Join(homeDir, ".claude", "projects", "*", sessionID+".jsonl")
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return Session{}, fmt.Errorf("failed to glob session %s: %w", sessionID, err)
+	}
+
+	var best Session
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			continue
+		}
+		if best.Path == "" || info.ModTime().After(best.ModTime) {
+			best = Session{ID: sessionID, Path: p, ModTime: info.ModTime(), Size: info.Size()}
+		}
+	}
+	if best.Path == "" {
+		return Session{}, fmt.Errorf("session %s not found: %w", sessionID, os.ErrNotExist)
+	}
+	return best, nil
+}
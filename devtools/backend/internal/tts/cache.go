@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	lru "github.com/hashicorp/golang-lru/v2"
@@ -137,16 +138,97 @@ func (c *lruCache) Bytes() int {
 	return c.currBytes
 }
 
+// tieredCache はインメモリ LRU の下にディスクキャッシュを重ねた 2 段キャッシュです。
+// Get はメモリ → ディスクの順に引き、ディスクでヒットした音声はメモリへ昇格します。
+// Set は両方へ書きます。Len / Bytes はメモリ段の値を返します(ディスク段は Stats で返す)。
+type tieredCache struct {
+	memory Cache
+	disk   Cache // nil の場合はメモリのみ
+
+	memoryHits atomic.Int64
+	diskHits   atomic.Int64
+	misses     atomic.Int64
+}
+
+// newTieredCache は memory の下に disk を重ねた tieredCache を生成します(disk は nil 可)。
+func newTieredCache(memory, disk Cache) *tieredCache {
+	return &tieredCache{memory: memory, disk: disk}
+}
+
+// Get はメモリ → ディスクの順にデータを返し、どの段でヒットしたかを数えます。
+func (c *tieredCache) Get(key string) ([]byte, bool) {
+	if data, ok := c.memory.Get(key); ok {
+		c.memoryHits.Add(1)
+		cacheTierHitsTotal.Inc("memory")
+		return data, true
+	}
+	if c.disk != nil {
+		if data, ok := c.disk.Get(key); ok {
+			c.diskHits.Add(1)
+			cacheTierHitsTotal.Inc("disk")
+			c.memory.Set(key, data)
+			return data, true
+		}
+	}
+	c.misses.Add(1)
+	return nil, false
+}
+
+// Set はメモリとディスクの両方へデータを保存します。
+func (c *tieredCache) Set(key string, value []byte) {
+	c.memory.Set(key, value)
+	if c.disk != nil {
+		c.disk.Set(key, value)
+	}
+}
+
+// Len はメモリ段のエントリ数を返します。
+func (c *tieredCache) Len() int {
+	return c.memory.Len()
+}
+
+// Bytes はメモリ段のバイト数を返します。
+func (c *tieredCache) Bytes() int {
+	return c.memory.Bytes()
+}
+
+// Stats は起動以降の段ごとのヒット数・ミス数と、現在の件数・バイト数を返します。
+func (c *tieredCache) Stats() CacheStats {
+	stats := CacheStats{
+		Memory: CacheTierStats{Hits: c.memoryHits.Load(), Entries: c.memory.Len(), Bytes: c.memory.Bytes()},
+		Misses: c.misses.Load(),
+	}
+	if c.disk != nil {
+		stats.Disk = &CacheTierStats{Hits: c.diskHits.Load(), Entries: c.disk.Len(), Bytes: c.disk.Bytes()}
+	}
+	return stats
+}
+
 // cacheKey は (text, speakerID, outputFormat) を SHA256 して hex 化したキーです。
 // 区切り文字 \x00 を挟むことでフィールド境界の衝突を防ぎます。
 // outputFormat は MVP++ では常に "wav" ですが、MVP+++ での MP3 対応に備えてキーに含めます。
 func cacheKey(text string, speakerID int, outputFormat string) string {
+	return hashKey(text, speakerIDStr(speakerID), outputFormat)
+}
+
+// paramsCacheKey は SynthesizeParams のキャッシュキーです。声質パラメータの上書きが無い場合は
+// cacheKey と同じ値(既存のディスクキャッシュをそのまま使える)、ある場合は正規化したパラメータも含めます。
+func paramsCacheKey(p SynthesizeParams) string {
+	if p.Voice.IsZero() {
+		return cacheKey(p.Text, p.SpeakerID, p.OutputFormat)
+	}
+	return hashKey(p.Text, speakerIDStr(p.SpeakerID), p.OutputFormat, p.Voice.canonical())
+}
+
+// hashKey は fields を \x00 区切りで連結して SHA256 し hex 化します。
+func hashKey(fields ...string) string {
 	h := sha256.New()
-	h.Write([]byte(text))
-	h.Write([]byte{0x00})
-	h.Write([]byte(speakerIDStr(speakerID)))
-	h.Write([]byte{0x00})
-	h.Write([]byte(outputFormat))
+	for i, f := range fields {
+		if i > 0 {
+			h.Write([]byte{0x00})
+		}
+		h.Write([]byte(f))
+	}
 	return hex.EncodeToString(h.Sum(nil))
 }
 
@@ -3,6 +3,7 @@ package tts
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -77,6 +78,12 @@ func (c *Client) Synthesize(ctx context.Context, params SynthesizeParams) ([]byt
 		return nil, err
 	}
 
+	// 声質パラメータ(speedScale 等)を AudioQuery へ上書き
+	query, err = params.Voice.apply(query)
+	if err != nil {
+		return nil, err
+	}
+
 	// Stage 2: synthesis
 	audio, err := c.synthesis(ctx, speakerID, query)
 	if err != nil {
@@ -90,6 +97,49 @@ func (c *Client) Synthesize(ctx context.Context, params SynthesizeParams) ([]byt
 	return audio, nil
 }
 
+// Speakers は VOICEVOX /speakers エンドポイントの話者一覧(JSON 配列)をそのまま返します。
+//
+// 戻り値:
+//   - 成功: (json.RawMessage, nil)
+//   - 非 200 応答: *UpstreamStatusError
+//   - JSON 配列以外: ErrInvalidSpeakers
+//   - タイムアウト/接続拒否: ErrUpstreamTimeout
+func (c *Client) Speakers(ctx context.Context) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, HTTPTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.host+"/speakers", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create speakers request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, mapClientError(err, "speakers")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodySnippet := readBodySnippet(resp.Body, upstreamBodySnippetMax)
+		log.Printf("[TTSService] VOICEVOX speakers failed: status=%d, bodySnippet=%q",
+			resp.StatusCode, bodySnippet)
+		return nil, &UpstreamStatusError{Status: resp.StatusCode, Body: bodySnippet}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read speakers response: %w", err)
+	}
+	trimmed := bytes.TrimSpace(body)
+	if !json.Valid(trimmed) || len(trimmed) == 0 || trimmed[0] != '[' {
+		log.Printf("[TTSService] VOICEVOX speakers returned non-array body: bytes=%d", len(body))
+		return nil, ErrInvalidSpeakers
+	}
+
+	return json.RawMessage(trimmed), nil
+}
+
 // audioQuery は VOICEVOX /audio_query エンドポイントを呼び出します。
 // テキストからアクセント・イントネーション等の中間表現 (AudioQuery JSON) を取得します。
 func (c *Client) audioQuery(ctx context.Context, text string, speakerID int) (AudioQuery, error) {
@@ -318,3 +318,79 @@ func TestClient_AudioQuery_BodySnippetInError(t *testing.T) {
 	// Verify it's valid JSON for error reporting
 	_, _ = json.Marshal(ue)
 }
+
+// ---------------------------------------------------------------------------
+// Voice params and speakers
+// ---------------------------------------------------------------------------
+
+func TestClient_Synthesize_AppliesVoiceParams(t *testing.T) {
+	var received map[string]any
+	srv := newTestVOICEVOXServer(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"accent_phrases":[],"speedScale":1.0,"intonationScale":1.0}`))
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&received)
+			w.Header().Set("Content-Type", "audio/wav")
+			_, _ = w.Write([]byte("wav"))
+		},
+	)
+	defer srv.Close()
+
+	c := newTestClient(srv.URL, 0)
+	_, err := c.Synthesize(context.Background(), SynthesizeParams{
+		Text:  "test",
+		Voice: VoiceParams{SpeedScale: f64(1.4), IntonationScale: f64(0.5)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1.4, received["speedScale"])
+	assert.Equal(t, 0.5, received["intonationScale"])
+}
+
+func TestClient_Speakers(t *testing.T) {
+	speakersJSON := `[{"name":"春日部つむぎ","speaker_uuid":"u1","styles":[{"name":"ノーマル","id":8}]}]`
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr error
+	}{
+		{name: "ok", status: http.StatusOK, body: speakersJSON},
+		{name: "non-array body", status: http.StatusOK, body: `{"detail":"oops"}`, wantErr: ErrInvalidSpeakers},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /speakers", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			got, err := newTestClient(srv.URL, 0).Speakers(context.Background())
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, speakersJSON, string(got))
+		})
+	}
+}
+
+func TestClient_Speakers_ErrorStatus(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /speakers", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	_, err := newTestClient(srv.URL, 0).Speakers(context.Background())
+	var ue *UpstreamStatusError
+	require.True(t, errors.As(err, &ue))
+	assert.Equal(t, http.StatusInternalServerError, ue.Status)
+}
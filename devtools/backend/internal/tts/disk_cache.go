@@ -0,0 +1,283 @@
+package tts
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ディスクキャッシュのファイル形式: magic(8) + 作成時刻 UnixNano(8, big endian) + 音声の SHA256(32) + 音声。
+// 作成時刻は TTL の起点、ファイルの mtime は最終アクセス時刻（LRU の順序）として使います。
+const (
+	diskCacheMagic      = "GRTTS1\x00\x00"
+	diskCacheHeaderSize = len(diskCacheMagic) + 8 + sha256.Size
+	diskCacheExt        = ".bin"
+)
+
+// diskEntry はディスクキャッシュ 1 エントリの索引です。
+type diskEntry struct {
+	size       int64 // ファイルサイズ(ヘッダー込み)
+	createdAt  time.Time
+	accessedAt time.Time
+}
+
+// diskCache は <dir>/<cacheKey>.bin に音声を保存する永続キャッシュです。
+// 起動時にディレクトリを走査して索引を作り、バイト数上限を超えた分は最終アクセス時刻の古い順に削除します。
+// 壊れたファイル(ヘッダー不正・チェックサム不一致・読み取り失敗)は miss 扱いで削除し、エラーにしません。
+type diskCache struct {
+	mu        sync.Mutex
+	dir       string
+	entries   map[string]*diskEntry
+	maxBytes  int64
+	currBytes int64
+	ttl       time.Duration
+	clock     func() time.Time // テスト用注入
+}
+
+// NewDiskCache は dir を作成・走査して diskCache を生成します。
+// maxBytes はファイルサイズ合計の上限、ttl は書き込みからの有効期限です。
+// 走査時に TTL 切れ・壊れたファイル・書き込み途中の一時ファイルは削除します。
+func NewDiskCache(dir string, maxBytes int64, ttl time.Duration) (*diskCache, error) {
+	return newDiskCache(dir, maxBytes, ttl, time.Now)
+}
+
+func newDiskCache(dir string, maxBytes int64, ttl time.Duration, clock func() time.Time) (*diskCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create tts cache dir %s: %w", dir, err)
+	}
+	c := &diskCache{
+		dir:      dir,
+		entries:  make(map[string]*diskEntry),
+		maxBytes: maxBytes,
+		ttl:      ttl,
+		clock:    clock,
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.evictLocked("")
+	c.mu.Unlock()
+	return c, nil
+}
+
+// load はディレクトリを走査して索引を作ります。
+func (c *diskCache) load() error {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read tts cache dir %s: %w", c.dir, err)
+	}
+	now := c.clock()
+	for _, de := range dirEntries {
+		name := de.Name()
+		if de.IsDir() {
+			continue
+		}
+		path := filepath.Join(c.dir, name)
+		if !strings.HasSuffix(name, diskCacheExt) {
+			if strings.HasSuffix(name, ".tmp") {
+				c.remove(path)
+			}
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		createdAt, ok := readDiskCacheCreatedAt(path)
+		if !ok || now.After(createdAt.Add(c.ttl)) {
+			c.remove(path)
+			continue
+		}
+		key := strings.TrimSuffix(name, diskCacheExt)
+		c.entries[key] = &diskEntry{size: info.Size(), createdAt: createdAt, accessedAt: info.ModTime()}
+		c.currBytes += info.Size()
+	}
+	log.Printf("[TTSService] disk cache loaded: dir=%s, entries=%d, bytes=%d", c.dir, len(c.entries), c.currBytes)
+	return nil
+}
+
+// Get はキーに対応する音声を返します。TTL 切れ・壊れたファイルは削除して miss 扱いにします。
+// ヒット時はファイルの mtime を現在時刻に更新し、LRU の順序を再起動後も保ちます。
+func (c *diskCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	path := c.path(key)
+	now := c.clock()
+	if now.After(entry.createdAt.Add(c.ttl)) {
+		c.dropLocked(key)
+		return nil, false
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("[TTSService] disk cache read failed, dropped: keyPrefix=%s, error=%v", keyPrefix(key), err)
+		c.dropLocked(key)
+		return nil, false
+	}
+	data, ok := decodeDiskCacheFile(raw)
+	if !ok {
+		log.Printf("[TTSService] disk cache corrupted, dropped: keyPrefix=%s, size=%d", keyPrefix(key), len(raw))
+		c.dropLocked(key)
+		return nil, false
+	}
+
+	entry.accessedAt = now
+	if err := os.Chtimes(path, now, now); err != nil {
+		log.Printf("[TTSService] disk cache touch failed: keyPrefix=%s, error=%v", keyPrefix(key), err)
+	}
+	return data, true
+}
+
+// Set はキーに音声を保存します(temp + rename で原子的に書き込む)。
+// バイト数上限を超えた分は最終アクセス時刻の古い順に削除し、単一エントリが上限を超える場合は保存しません。
+func (c *diskCache) Set(key string, value []byte) {
+	if len(value) == 0 {
+		return
+	}
+	size := int64(diskCacheHeaderSize + len(value))
+	if size > c.maxBytes {
+		log.Printf("[TTSService] disk cache: entry too large, skipped: keyPrefix=%s, size=%d, maxBytes=%d",
+			keyPrefix(key), size, c.maxBytes)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock()
+	path := c.path(key)
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, encodeDiskCacheFile(value, now), 0o600); err != nil {
+		log.Printf("[TTSService] disk cache write failed: keyPrefix=%s, error=%v", keyPrefix(key), err)
+		c.remove(tmpFile)
+		return
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		log.Printf("[TTSService] disk cache rename failed: keyPrefix=%s, error=%v", keyPrefix(key), err)
+		c.remove(tmpFile)
+		return
+	}
+
+	if existing, ok := c.entries[key]; ok {
+		c.currBytes -= existing.size
+	}
+	c.entries[key] = &diskEntry{size: size, createdAt: now, accessedAt: now}
+	c.currBytes += size
+	c.evictLocked(key)
+}
+
+// Len は現在のエントリ数を返します。
+func (c *diskCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Bytes は現在保持しているファイルサイズの合計を返します。
+func (c *diskCache) Bytes() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return int(c.currBytes)
+}
+
+// evictLocked はバイト数上限を超えた分を最終アクセス時刻の古い順に削除します(keep は削除しない)。
+func (c *diskCache) evictLocked(keep string) {
+	if c.currBytes <= c.maxBytes {
+		return
+	}
+	keys := make([]string, 0, len(c.entries))
+	for k := range c.entries {
+		if k != keep {
+			keys = append(keys, k)
+		}
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		return c.entries[a].accessedAt.Compare(c.entries[b].accessedAt)
+	})
+	for _, k := range keys {
+		if c.currBytes <= c.maxBytes {
+			break
+		}
+		size := c.entries[k].size
+		c.dropLocked(k)
+		log.Printf("[TTSService] disk cache evicted: keyPrefix=%s, bytes=%d, currBytes=%d", keyPrefix(k), size, c.currBytes)
+	}
+}
+
+// dropLocked はエントリを索引とディスクから削除します。
+func (c *diskCache) dropLocked(key string) {
+	if entry, ok := c.entries[key]; ok {
+		c.currBytes -= entry.size
+		delete(c.entries, key)
+	}
+	c.remove(c.path(key))
+}
+
+// remove はファイルを削除します(不在は無視し、その他の失敗はログのみ)。
+func (c *diskCache) remove(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.Printf("[TTSService] disk cache remove failed: path=%s, error=%v", path, err)
+	}
+}
+
+// path はキーのファイルパスを返します。
+func (c *diskCache) path(key string) string {
+	return filepath.Join(c.dir, key+diskCacheExt)
+}
+
+// encodeDiskCacheFile はヘッダーを付けたファイル内容を返します。
+func encodeDiskCacheFile(data []byte, createdAt time.Time) []byte {
+	sum := sha256.Sum256(data)
+	buf := make([]byte, 0, diskCacheHeaderSize+len(data))
+	buf = append(buf, diskCacheMagic...)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(createdAt.UnixNano()))
+	buf = append(buf, sum[:]...)
+	return append(buf, data...)
+}
+
+// decodeDiskCacheFile はファイル内容を検証して音声を返します。
+// magic・長さ・チェックサムのいずれかが不正なら ok=false です。
+func decodeDiskCacheFile(raw []byte) ([]byte, bool) {
+	if len(raw) <= diskCacheHeaderSize || string(raw[:len(diskCacheMagic)]) != diskCacheMagic {
+		return nil, false
+	}
+	want := raw[len(diskCacheMagic)+8 : diskCacheHeaderSize]
+	data := raw[diskCacheHeaderSize:]
+	if sum := sha256.Sum256(data); !bytes.Equal(sum[:], want) {
+		return nil, false
+	}
+	return data, true
+}
+
+// readDiskCacheCreatedAt はヘッダーだけを読み作成時刻を返します(走査時に音声本体は読まない)。
+func readDiskCacheCreatedAt(path string) (time.Time, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil {
+			log.Printf("[TTSService] disk cache close failed: path=%s, error=%v", path, cerr)
+		}
+	}()
+	header := make([]byte, len(diskCacheMagic)+8)
+	if _, err := io.ReadFull(f, header); err != nil || string(header[:len(diskCacheMagic)]) != diskCacheMagic {
+		return time.Time{}, false
+	}
+	return time.Unix(0, int64(binary.BigEndian.Uint64(header[len(diskCacheMagic):]))), true
+}
@@ -0,0 +1,171 @@
+package tts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a mutable clock for disk cache tests.
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestDiskCache(t *testing.T, dir string, maxBytes int64, ttl time.Duration, clock *fakeClock) *diskCache {
+	t.Helper()
+	c, err := newDiskCache(dir, maxBytes, ttl, clock.Now)
+	require.NoError(t, err)
+	return c
+}
+
+// ---------------------------------------------------------------------------
+// diskCache
+// ---------------------------------------------------------------------------
+
+func TestDiskCache_PersistsAcrossRestart(t *testing.T) {
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	c := newTestDiskCache(t, dir, 1<<20, time.Hour, clock)
+	c.Set("k1", []byte("wav-1"))
+
+	reopened := newTestDiskCache(t, dir, 1<<20, time.Hour, clock)
+	data, ok := reopened.Get("k1")
+	require.True(t, ok)
+	assert.Equal(t, []byte("wav-1"), data)
+	assert.Equal(t, 1, reopened.Len())
+	assert.Equal(t, diskCacheHeaderSize+len("wav-1"), reopened.Bytes())
+}
+
+func TestDiskCache_TTLExpiry(t *testing.T) {
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	c := newTestDiskCache(t, dir, 1<<20, time.Hour, clock)
+	c.Set("k1", []byte("wav-1"))
+
+	clock.now = clock.now.Add(2 * time.Hour)
+	_, ok := c.Get("k1")
+	assert.False(t, ok, "expired entry must miss")
+	assert.Equal(t, 0, c.Len())
+	assert.NoFileExists(t, filepath.Join(dir, "k1"+diskCacheExt))
+
+	// 再起動時の走査でも TTL 切れは読み込まない
+	c.Set("k2", []byte("wav-2"))
+	clock.now = clock.now.Add(2 * time.Hour)
+	reopened := newTestDiskCache(t, dir, 1<<20, time.Hour, clock)
+	assert.Equal(t, 0, reopened.Len())
+}
+
+func TestDiskCache_EvictsLeastRecentlyAccessed(t *testing.T) {
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	entrySize := int64(diskCacheHeaderSize + 10)
+	c := newTestDiskCache(t, dir, 2*entrySize, time.Hour, clock)
+
+	c.Set("a", []byte("aaaaaaaaaa"))
+	clock.now = clock.now.Add(time.Second)
+	c.Set("b", []byte("bbbbbbbbbb"))
+	clock.now = clock.now.Add(time.Second)
+	_, ok := c.Get("a") // a を最近アクセスに
+	require.True(t, ok)
+	clock.now = clock.now.Add(time.Second)
+	c.Set("c", []byte("cccccccccc"))
+
+	_, okA := c.Get("a")
+	_, okB := c.Get("b")
+	clock.now = clock.now.Add(time.Second)
+	_, okC := c.Get("c")
+	assert.True(t, okA, "recently accessed entry should survive")
+	assert.False(t, okB, "least recently accessed entry should be evicted")
+	assert.True(t, okC)
+	assert.Equal(t, int(2*entrySize), c.Bytes())
+
+	// アクセス順は mtime に残るため、再起動後の上限縮小でも古い順に消える
+	reopened := newTestDiskCache(t, dir, entrySize, time.Hour, clock)
+	_, okC = reopened.Get("c")
+	assert.Equal(t, 1, reopened.Len())
+	assert.True(t, okC)
+}
+
+func TestDiskCache_CorruptedFileIsMiss(t *testing.T) {
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	c := newTestDiskCache(t, dir, 1<<20, time.Hour, clock)
+	c.Set("k1", []byte("wav-1"))
+	c.Set("k2", []byte("wav-2"))
+
+	// k1: 本体を書き換え(チェックサム不一致)、k2: ヘッダー途中で切り詰め
+	path1 := filepath.Join(dir, "k1"+diskCacheExt)
+	raw, err := os.ReadFile(path1)
+	require.NoError(t, err)
+	raw[len(raw)-1] ^= 0xff
+	require.NoError(t, os.WriteFile(path1, raw, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "k2"+diskCacheExt), []byte("GRT"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "k3"+diskCacheExt+".tmp"), []byte("partial"), 0o600))
+
+	_, ok := c.Get("k1")
+	assert.False(t, ok, "checksum mismatch must miss")
+	assert.NoFileExists(t, path1)
+
+	reopened := newTestDiskCache(t, dir, 1<<20, time.Hour, clock)
+	assert.Equal(t, 0, reopened.Len(), "truncated file must be dropped on load")
+	assert.NoFileExists(t, filepath.Join(dir, "k3"+diskCacheExt+".tmp"))
+}
+
+func TestDiskCache_EntryTooLarge_Skipped(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	c := newTestDiskCache(t, t.TempDir(), 10, time.Hour, clock)
+	c.Set("big", []byte("too large for the cap"))
+	assert.Equal(t, 0, c.Len())
+}
+
+// ---------------------------------------------------------------------------
+// tieredCache
+// ---------------------------------------------------------------------------
+
+func TestTieredCache_PromotesDiskHitsAndCountsTiers(t *testing.T) {
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	disk := newTestDiskCache(t, dir, 1<<20, time.Hour, clock)
+	disk.Set("k1", []byte("wav-1")) // 前回起動時に書かれた想定
+
+	c := newTieredCache(NewLRUCache(CacheMaxBytes, CacheTTL), disk)
+	data, ok := c.Get("k1")
+	require.True(t, ok)
+	assert.Equal(t, []byte("wav-1"), data)
+	_, ok = c.Get("k1")
+	require.True(t, ok)
+	_, ok = c.Get("missing")
+	require.False(t, ok)
+
+	stats := c.Stats()
+	assert.Equal(t, int64(1), stats.Memory.Hits, "second lookup should hit memory after promotion")
+	require.NotNil(t, stats.Disk)
+	assert.Equal(t, int64(1), stats.Disk.Hits)
+	assert.Equal(t, 1, stats.Disk.Entries)
+	assert.Equal(t, int64(1), stats.Misses)
+}
+
+func TestService_Synthesize_DiskCacheSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("VOICEVOX_SPEAKER_ID", "")
+	mc := &mockClient{audio: []byte("wav-data")}
+
+	first := NewService(WithDiskCache(dir)).(*serviceImpl)
+	first.client = mc
+	_, err := first.Synthesize(t.Context(), SynthesizeParams{Text: "hello"})
+	require.NoError(t, err)
+
+	restarted := NewService(WithDiskCache(dir)).(*serviceImpl)
+	restarted.client = mc
+	result, err := restarted.Synthesize(t.Context(), SynthesizeParams{Text: "hello"})
+	require.NoError(t, err)
+	assert.True(t, result.FromCache)
+	assert.Equal(t, int32(1), mc.callCount.Load(), "restart should be served from the disk cache")
+	stats := restarted.CacheStats()
+	require.NotNil(t, stats.Disk)
+	assert.Equal(t, int64(1), stats.Disk.Hits)
+}
@@ -6,8 +6,9 @@
 // フロントエンドに audio/wav バイナリを返す HTTP プロキシを提供する。
 // 目的は以下の二点である:
 //
-//  1. インメモリ LRU キャッシュ(SHA256 キー / TTL 24h / 50MB 上限)で
-//     同一テキストの重複読み上げを抑制し、VOICEVOX への負荷を軽減する
+//  1. インメモリ LRU キャッシュ(SHA256 キー / TTL 24h / 50MB 上限)と、その下に重ねる
+//     ディスクキャッシュ(TTL 30 日 / 500MB 上限)で同一テキストの重複読み上げを抑制し、
+//     再起動後も含めて VOICEVOX への負荷を軽減する
 //  2. 重複リクエストを singleflight で統合し、上流呼び出しの増幅を防ぐ
 //
 // VOICEVOX Engine はローカルで動作し、API キーは不要である。
@@ -37,15 +38,33 @@
 //     を呼び出す HTTP クライアント。
 //   - [Cache]: LRU + TTL + バイト数上限のインメモリキャッシュ。
 //     [NewLRUCache] で生成する。
+//   - [NewDiskCache] / [WithDiskCache]: <dir>/<key>.bin に音声を保存するディスクキャッシュ。
+//     tieredCache がメモリ LRU の下に重ね、ディスクでのヒットはメモリへ昇格する。
+//   - [Handler.HandleCacheStats] / [CacheStats]: GET /api/tts/cache。段ごとのヒット数と件数・バイト数。
+//   - [Handler.HandleSpeakers]: GET /api/tts/speakers。VOICEVOX の /speakers を
+//     [SpeakersCacheTTL] の間キャッシュして中継し、再取得に失敗したら前回の一覧を返す。
+//   - [VoiceParams]: 話速・音高などの声質パラメータ。audio_query の結果の同名キーだけを上書きして
+//     /synthesis に送り、指定がある場合はキャッシュキーにも含める。
+//   - [PresetStore] / [WithPresets]: 端末ごとの [VoicePreset] を JSON ファイルに保存し、
+//     POST /api/tts で省略された話者ID・声質パラメータを補う(リクエストの指定が優先)。
+//   - [WithPublisher] / [Event]: 合成の完了(キャッシュヒットを含む)と失敗を
+//     イベントバスへ tts.synthesized / tts.failed として発行する。本文は載せず文字数のみ。
 //   - [UpstreamStatusError]: VOICEVOX 非 200 応答を表す型エラー。
 //     Status / Body フィールドを持ち、errors.As で取り出して
 //     [mapErrorToStatus] が HTTP ステータスへマッピングする。
 //
 // # Design Decisions
 //
+//   - 声質パラメータの指定が無い場合のキャッシュキーは従来と同じにし、既存のディスクキャッシュを無効にしない。
+//   - ユーザーの特定は handler パッケージの認証結果(端末ID)を [WithPresets] の関数で受け取り、
+//     tts から auth への依存を持たない。端末IDが空(認証無効時)の要求ではプリセットを使わず、
+//     /api/tts/preset は 404 を返す(全員で1つのプリセットを上書きし合わないため)。
 //   - キャッシュは LRU + TTL + バイト数のハイブリッド: エントリ数固定では
 //     1 リクエストあたりのサイズ変動(数十 KB 〜 数 MB)に追従できない。
 //     バイト数厳密管理 + LRU + TTL の 3 軸を持つ。
+//   - ディスクキャッシュのファイルは magic + 作成時刻 + SHA256 のヘッダー付き。作成時刻を TTL の起点、
+//     mtime を最終アクセス時刻(LRU の順序)とし、起動時はヘッダーのみ読んで索引を作る。
+//     ヘッダー不正・チェックサム不一致は miss 扱いで削除し、エラーにしない。
 //   - singleflight 中の上流呼び出しは個別の context cancel に従わず、
 //     最初に開始したリクエストの ctx が支配する。結果はキャッシュへ
 //     書き込まれるため、後続の同一キーリクエストは即 hit に倒れる。
@@ -9,14 +9,33 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// Handler は POST /api/tts を処理する Gin ハンドラです。
+// Handler は /api/tts 配下(合成・話者一覧・プリセット・キャッシュ統計)を処理する Gin ハンドラです。
 type Handler struct {
-	svc Service
+	svc     Service
+	presets *PresetStore                // nil の場合はプリセットを使わない
+	userOf  func(c *gin.Context) string // リクエストのユーザー(端末ID)
+}
+
+// HandlerOption は Handler の任意の依存を設定します。
+type HandlerOption func(*Handler)
+
+// WithPresets はユーザーごとの声質プリセットを設定します。userOf はリクエストからユーザー(端末ID)を返します。
+// 設定時は POST /api/tts で話者・声質の省略分をプリセットで補い、/api/tts/preset で取得・保存できます。
+// userOf が空文字を返す要求(認証無効時など端末を特定できない要求)にはプリセットを使いません。
+func WithPresets(store *PresetStore, userOf func(c *gin.Context) string) HandlerOption {
+	return func(h *Handler) {
+		h.presets = store
+		h.userOf = userOf
+	}
 }
 
 // NewHandler は新しい Handler を生成します。
-func NewHandler(svc Service) *Handler {
-	return &Handler{svc: svc}
+func NewHandler(svc Service, opts ...HandlerOption) *Handler {
+	h := &Handler{svc: svc}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // HandleSynthesize は POST /api/tts を処理します。
@@ -53,9 +72,20 @@ func (h *Handler) HandleSynthesize(c *gin.Context) {
 		return
 	}
 
-	log.Printf("[TTSHandler] HandleSynthesize started: textLen=%d", textLen)
+	if err := validateVoice(req.SpeakerID, req.Voice); err != nil {
+		log.Printf("[TTSHandler] HandleSynthesize failed: %v", err)
+		c.JSON(http.StatusBadRequest, TTSErrorResponse{
+			Success: false,
+			Error:   "声質パラメータが不正です",
+		})
+		return
+	}
+
+	params := h.applyPreset(c, SynthesizeParams{Text: req.Text, SpeakerID: req.SpeakerID, Voice: req.Voice})
+	log.Printf("[TTSHandler] HandleSynthesize started: textLen=%d, speakerID=%d, voice=%q",
+		textLen, params.SpeakerID, params.Voice.canonical())
 
-	result, err := h.svc.Synthesize(c.Request.Context(), SynthesizeParams{Text: req.Text})
+	result, err := h.svc.Synthesize(c.Request.Context(), params)
 	if err != nil {
 		status, message := mapErrorToStatus(err)
 		log.Printf("[TTSHandler] HandleSynthesize failed: status=%d, error=%v", status, err)
@@ -76,6 +106,104 @@ func (h *Handler) HandleSynthesize(c *gin.Context) {
 	c.Data(http.StatusOK, result.ContentType, result.Audio)
 }
 
+// applyPreset はリクエストで省略された話者・声質をユーザーのプリセットで補います(リクエストの指定が優先)。
+func (h *Handler) applyPreset(c *gin.Context, params SynthesizeParams) SynthesizeParams {
+	user, ok := h.presetUser(c)
+	if !ok {
+		return params
+	}
+	preset, ok := h.presets.Get(user)
+	if !ok {
+		return params
+	}
+	if params.SpeakerID == 0 {
+		params.SpeakerID = preset.SpeakerID
+	}
+	params.Voice = preset.Voice.Merge(params.Voice)
+	return params
+}
+
+// presetUser はプリセットを使う要求のユーザーを返します。
+// プリセットが無効、またはユーザーを特定できない(全員が同じプリセットを共有してしまう)場合は false です。
+func (h *Handler) presetUser(c *gin.Context) (string, bool) {
+	if h.presets == nil {
+		return "", false
+	}
+	user := h.userOf(c)
+	return user, user != ""
+}
+
+// HandleSpeakers は GET /api/tts/speakers を処理します。
+// VOICEVOX の /speakers の JSON 配列(話者ごとの styles[].id が speakerId)をそのまま返します。
+func (h *Handler) HandleSpeakers(c *gin.Context) {
+	speakers, err := h.svc.Speakers(c.Request.Context())
+	if err != nil {
+		status, message := mapErrorToStatus(err)
+		log.Printf("[TTSHandler] HandleSpeakers failed: status=%d, error=%v", status, err)
+		c.JSON(status, TTSErrorResponse{
+			Success: false,
+			Error:   message,
+		})
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", speakers)
+}
+
+// HandleGetPreset は GET /api/tts/preset を処理します。
+// リクエストしたユーザーのプリセットを返します(未保存の場合はゼロ値)。
+func (h *Handler) HandleGetPreset(c *gin.Context) {
+	user, ok := h.presetUser(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, TTSErrorResponse{Success: false, Error: "プリセットは無効です"})
+		return
+	}
+	preset, _ := h.presets.Get(user)
+	c.JSON(http.StatusOK, preset)
+}
+
+// HandleSetPreset は POST /api/tts/preset を処理します。
+// リクエストしたユーザーのプリセットを置き換え、保存した値(updatedAt 付き)を返します。
+func (h *Handler) HandleSetPreset(c *gin.Context) {
+	user, ok := h.presetUser(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, TTSErrorResponse{Success: false, Error: "プリセットは無効です"})
+		return
+	}
+	var req VoicePreset
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("[TTSHandler] HandleSetPreset failed: invalid request, error=%v", err)
+		c.JSON(http.StatusBadRequest, TTSErrorResponse{
+			Success: false,
+			Error:   "リクエストが不正です",
+		})
+		return
+	}
+
+	saved, err := h.presets.Set(user, req)
+	if err != nil {
+		if errors.Is(err, ErrInvalidVoiceParams) {
+			log.Printf("[TTSHandler] HandleSetPreset failed: user=%s, error=%v", user, err)
+			c.JSON(http.StatusBadRequest, TTSErrorResponse{Success: false, Error: "声質パラメータが不正です"})
+			return
+		}
+		log.Printf("[TTSHandler] HandleSetPreset failed: user=%s, error=%v", user, err)
+		c.JSON(http.StatusInternalServerError, TTSErrorResponse{Success: false, Error: "プリセットを保存できませんでした"})
+		return
+	}
+
+	log.Printf("[TTSHandler] HandleSetPreset completed: user=%s, speakerID=%d, voice=%q",
+		user, saved.SpeakerID, saved.Voice.canonical())
+	c.JSON(http.StatusOK, saved)
+}
+
+// HandleCacheStats は GET /api/tts/cache を処理します。
+// メモリ・ディスクの段ごとのヒット数(起動以降)と現在の件数・バイト数を CacheStats で返します。
+func (h *Handler) HandleCacheStats(c *gin.Context) {
+	stats := h.svc.CacheStats()
+	log.Printf("[TTSHandler] HandleCacheStats completed: memoryHits=%d, misses=%d", stats.Memory.Hits, stats.Misses)
+	c.JSON(http.StatusOK, stats)
+}
+
 // mapErrorToStatus はサービス層のエラーを HTTP ステータスとユーザー向けメッセージへ変換します。
 func mapErrorToStatus(err error) (int, string) {
 	if errors.Is(err, ErrTextEmpty) || errors.Is(err, ErrTextTooLong) {
@@ -97,6 +225,10 @@ func mapErrorToStatus(err error) (int, string) {
 		return http.StatusBadGateway, "VOICEVOX から音声を取得できませんでした"
 	}
 
+	if errors.Is(err, ErrInvalidVoiceParams) {
+		return http.StatusBadRequest, "声質パラメータが不正です"
+	}
+
 	// その他は 502 に丸める
 	return http.StatusBadGateway, "VOICEVOX から音声を取得できませんでした"
 }
@@ -24,12 +24,25 @@ func init() {
 type mockService struct {
 	result *SynthesizeResult
 	err    error
+	stats  CacheStats
+	// speakers / params は話者一覧の応答と、Synthesize に渡された最後のパラメータです
+	speakers json.RawMessage
+	params   SynthesizeParams
 }
 
-func (m *mockService) Synthesize(_ context.Context, _ SynthesizeParams) (*SynthesizeResult, error) {
+func (m *mockService) Synthesize(_ context.Context, params SynthesizeParams) (*SynthesizeResult, error) {
+	m.params = params
 	return m.result, m.err
 }
 
+func (m *mockService) CacheStats() CacheStats {
+	return m.stats
+}
+
+func (m *mockService) Speakers(_ context.Context) (json.RawMessage, error) {
+	return m.speakers, m.err
+}
+
 func setupRouter(svc Service) *gin.Engine {
 	r := gin.New()
 	h := NewHandler(svc)
@@ -382,3 +395,137 @@ func TestHandler_ErrorMapping(t *testing.T) {
 		})
 	}
 }
+
+// ---------------------------------------------------------------------------
+// GET /api/tts/cache
+// ---------------------------------------------------------------------------
+
+func TestHandler_CacheStats(t *testing.T) {
+	svc := &mockService{stats: CacheStats{
+		Memory: CacheTierStats{Hits: 3, Entries: 2, Bytes: 100},
+		Disk:   &CacheTierStats{Hits: 1, Entries: 5, Bytes: 400},
+		Misses: 2,
+	}}
+	r := gin.New()
+	r.GET("/api/tts/cache", NewHandler(svc).HandleCacheStats)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tts/cache", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got CacheStats
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, svc.stats.Memory, got.Memory)
+	require.NotNil(t, got.Disk)
+	assert.Equal(t, int64(1), got.Disk.Hits)
+	assert.Equal(t, int64(2), got.Misses)
+}
+
+// ---------------------------------------------------------------------------
+// Speakers and presets
+// ---------------------------------------------------------------------------
+
+func setupPresetRouter(t *testing.T, svc Service) (*gin.Engine, *PresetStore) {
+	t.Helper()
+	store, err := NewPresetStore(t.TempDir() + "/tts_presets.json")
+	require.NoError(t, err)
+	h := NewHandler(svc, WithPresets(store, func(c *gin.Context) string { return c.GetHeader("X-Test-User") }))
+	r := gin.New()
+	r.POST("/api/tts", h.HandleSynthesize)
+	r.GET("/api/tts/speakers", h.HandleSpeakers)
+	r.GET("/api/tts/preset", h.HandleGetPreset)
+	r.POST("/api/tts/preset", h.HandleSetPreset)
+	return r, store
+}
+
+func doUserRequest(router *gin.Engine, method, path, user, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Test-User", user)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestHandler_Speakers(t *testing.T) {
+	svc := &mockService{speakers: json.RawMessage(`[{"name":"春日部つむぎ","styles":[{"id":8}]}]`)}
+	router, _ := setupPresetRouter(t, svc)
+
+	w := doUserRequest(router, http.MethodGet, "/api/tts/speakers", "phone", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	assert.JSONEq(t, string(svc.speakers), w.Body.String())
+
+	svc.err = ErrUpstreamTimeout
+	w = doUserRequest(router, http.MethodGet, "/api/tts/speakers", "phone", "")
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+}
+
+func TestHandler_Synthesize_VoiceParams(t *testing.T) {
+	svc := &mockService{result: &SynthesizeResult{Audio: []byte("wav"), ContentType: "audio/wav"}}
+	router, _ := setupPresetRouter(t, svc)
+
+	w := doUserRequest(router, http.MethodPost, "/api/tts", "phone", `{"text":"hello","speakerId":3,"voice":{"speedScale":1.2}}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 3, svc.params.SpeakerID)
+	require.NotNil(t, svc.params.Voice.SpeedScale)
+	assert.Equal(t, 1.2, *svc.params.Voice.SpeedScale)
+
+	for _, body := range []string{
+		`{"text":"hello","voice":{"speedScale":5}}`,
+		`{"text":"hello","speakerId":-1}`,
+	} {
+		w = doUserRequest(router, http.MethodPost, "/api/tts", "phone", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestHandler_Preset_AppliedPerUser(t *testing.T) {
+	svc := &mockService{result: &SynthesizeResult{Audio: []byte("wav"), ContentType: "audio/wav"}}
+	router, _ := setupPresetRouter(t, svc)
+
+	w := doUserRequest(router, http.MethodPost, "/api/tts/preset", "phone",
+		`{"speakerId":3,"voice":{"speedScale":1.3,"volumeScale":0.8}}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var saved VoicePreset
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &saved))
+	assert.NotEmpty(t, saved.UpdatedAt)
+
+	w = doUserRequest(router, http.MethodGet, "/api/tts/preset", "phone", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var got VoicePreset
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, 3, got.SpeakerID)
+
+	// リクエストの指定がプリセットより優先され、省略分はプリセットで補う
+	w = doUserRequest(router, http.MethodPost, "/api/tts", "phone", `{"text":"hello","voice":{"speedScale":1.5}}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 3, svc.params.SpeakerID)
+	assert.Equal(t, 1.5, *svc.params.Voice.SpeedScale)
+	assert.Equal(t, 0.8, *svc.params.Voice.VolumeScale)
+
+	// 別のユーザーにはプリセットを適用しない
+	w = doUserRequest(router, http.MethodPost, "/api/tts", "host", `{"text":"hello"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 0, svc.params.SpeakerID)
+	assert.True(t, svc.params.Voice.IsZero())
+
+	w = doUserRequest(router, http.MethodPost, "/api/tts/preset", "phone", `{"voice":{"pitchScale":1}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestHandler_Preset_DisabledWithoutUser は端末を特定できない要求(認証無効時)ではプリセットを使わないことを確認します
+func TestHandler_Preset_DisabledWithoutUser(t *testing.T) {
+	svc := &mockService{result: &SynthesizeResult{Audio: []byte("wav"), ContentType: "audio/wav"}}
+	router, store := setupPresetRouter(t, svc)
+	_, err := store.Set("", VoicePreset{SpeakerID: 3})
+	require.NoError(t, err)
+
+	w := doUserRequest(router, http.MethodGet, "/api/tts/preset", "", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = doUserRequest(router, http.MethodPost, "/api/tts/preset", "", `{"speakerId":3}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = doUserRequest(router, http.MethodPost, "/api/tts", "", `{"text":"hello"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 0, svc.params.SpeakerID)
+}
@@ -0,0 +1,36 @@
+package tts
+
+import "ghostrunner/backend/internal/metrics"
+
+var (
+	// cacheLookupsTotal は音声キャッシュの参照数です（result=hit / miss）
+	cacheLookupsTotal = metrics.NewCounterVec("ghostrunner_tts_cache_lookups_total",
+		"TTS audio cache lookups by result (hit, miss).", "result")
+	// cacheEntries / cacheBytes は音声キャッシュの現在の件数・合計バイト数です（Cache.Len / Cache.Bytes）
+	cacheEntries = metrics.NewGaugeVec("ghostrunner_tts_cache_entries",
+		"Number of entries in the TTS audio cache.")
+	cacheBytes = metrics.NewGaugeVec("ghostrunner_tts_cache_bytes",
+		"Total bytes held in the TTS audio cache.")
+	// cacheTierHitsTotal は段ごとのキャッシュヒット数です（tier=memory / disk）
+	cacheTierHitsTotal = metrics.NewCounterVec("ghostrunner_tts_cache_tier_hits_total",
+		"TTS audio cache hits by tier (memory, disk).", "tier")
+	// diskCacheEntries / diskCacheBytes はディスクキャッシュの現在の件数・合計バイト数です
+	diskCacheEntries = metrics.NewGaugeVec("ghostrunner_tts_disk_cache_entries",
+		"Number of entries in the TTS on-disk audio cache.")
+	diskCacheBytes = metrics.NewGaugeVec("ghostrunner_tts_disk_cache_bytes",
+		"Total bytes held in the TTS on-disk audio cache.")
+)
+
+// observeCache はキャッシュ参照の結果と、参照・格納後の件数・バイト数を記録します。
+// Get は TTL 切れのエントリを削除するため、ヒット・ミスのどちらでも件数を取り直します。
+func observeCache(c Cache, result string) {
+	if result != "" {
+		cacheLookupsTotal.Inc(result)
+	}
+	cacheEntries.Set(float64(c.Len()))
+	cacheBytes.Set(float64(c.Bytes()))
+	if t, ok := c.(*tieredCache); ok && t.disk != nil {
+		diskCacheEntries.Set(float64(t.disk.Len()))
+		diskCacheBytes.Set(float64(t.disk.Bytes()))
+	}
+}
@@ -0,0 +1,104 @@
+package tts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// PresetStore はユーザー(端末ID)ごとの VoicePreset を 1 つの JSON ファイルに保存するストアです。
+// 起動時に読み込んでメモリに保持し、保存のたびにファイル全体を temp + rename で書き直します。
+type PresetStore struct {
+	mu      sync.Mutex
+	path    string
+	presets map[string]VoicePreset // key: ユーザー(端末ID)
+	clock   func() time.Time       // テスト用注入
+}
+
+// NewPresetStore は path のプリセットを読み込んで PresetStore を生成します。
+// ファイルが無い場合は空で始めます(最初の保存で作成します)。
+func NewPresetStore(path string) (*PresetStore, error) {
+	s := &PresetStore{
+		path:    path,
+		presets: make(map[string]VoicePreset),
+		clock:   time.Now,
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tts presets %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &s.presets); err != nil {
+		return nil, fmt.Errorf("failed to parse tts presets %s: %w", path, err)
+	}
+	log.Printf("[TTSService] presets loaded: path=%s, users=%d", path, len(s.presets))
+	return s, nil
+}
+
+// Get はユーザーのプリセットを返します。未保存の場合は (ゼロ値, false) を返します。
+func (s *PresetStore) Get(user string) (VoicePreset, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.presets[user]
+	return p, ok
+}
+
+// Set はユーザーのプリセットを検証して保存し、UpdatedAt を設定した値を返します。
+// 話者ID が負、または声質パラメータが範囲外の場合は ErrInvalidVoiceParams を返します。
+func (s *PresetStore) Set(user string, preset VoicePreset) (VoicePreset, error) {
+	if err := validateVoice(preset.SpeakerID, preset.Voice); err != nil {
+		return VoicePreset{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	preset.UpdatedAt = s.clock().UTC().Format(time.RFC3339)
+	next := make(map[string]VoicePreset, len(s.presets)+1)
+	for k, v := range s.presets {
+		next[k] = v
+	}
+	next[user] = preset
+	if err := s.write(next); err != nil {
+		return VoicePreset{}, err
+	}
+	s.presets = next
+	return preset, nil
+}
+
+// write は presets をファイルへ原子的に書き込みます。
+func (s *PresetStore) write(presets map[string]VoicePreset) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create tts presets dir %s: %w", filepath.Dir(s.path), err)
+	}
+	data, err := json.MarshalIndent(presets, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tts presets: %w", err)
+	}
+	tmpFile := s.path + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write tts presets %s: %w", tmpFile, err)
+	}
+	if err := os.Rename(tmpFile, s.path); err != nil {
+		if rmErr := os.Remove(tmpFile); rmErr != nil {
+			log.Printf("[TTSService] failed to remove temp presets: path=%s, error=%v", tmpFile, rmErr)
+		}
+		return fmt.Errorf("failed to rename tts presets %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// validateVoice は話者ID と声質パラメータを検証します。
+func validateVoice(speakerID int, voice VoiceParams) error {
+	if speakerID < 0 {
+		return fmt.Errorf("%w: speakerId must not be negative: %d", ErrInvalidVoiceParams, speakerID)
+	}
+	return voice.Validate()
+}
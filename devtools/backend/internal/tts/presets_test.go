@@ -0,0 +1,45 @@
+package tts
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresetStore_SetAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tts_presets.json")
+	store, err := NewPresetStore(path)
+	require.NoError(t, err)
+	store.clock = func() time.Time { return time.Date(2026, 7, 20, 10, 0, 0, 0, time.UTC) }
+
+	_, ok := store.Get("phone")
+	assert.False(t, ok)
+
+	saved, err := store.Set("phone", VoicePreset{SpeakerID: 3, Voice: VoiceParams{SpeedScale: f64(1.3)}})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-07-20T10:00:00Z", saved.UpdatedAt)
+
+	reloaded, err := NewPresetStore(path)
+	require.NoError(t, err)
+	got, ok := reloaded.Get("phone")
+	require.True(t, ok)
+	assert.Equal(t, 3, got.SpeakerID)
+	assert.Equal(t, 1.3, *got.Voice.SpeedScale)
+	_, ok = reloaded.Get("host")
+	assert.False(t, ok, "presets are per user")
+}
+
+func TestPresetStore_Set_Invalid(t *testing.T) {
+	store, err := NewPresetStore(filepath.Join(t.TempDir(), "tts_presets.json"))
+	require.NoError(t, err)
+
+	_, err = store.Set("phone", VoicePreset{SpeakerID: -1})
+	assert.ErrorIs(t, err, ErrInvalidVoiceParams)
+	_, err = store.Set("phone", VoicePreset{Voice: VoiceParams{VolumeScale: f64(3)}})
+	assert.ErrorIs(t, err, ErrInvalidVoiceParams)
+	_, ok := store.Get("phone")
+	assert.False(t, ok, "invalid preset must not be saved")
+}
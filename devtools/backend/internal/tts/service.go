@@ -2,10 +2,16 @@ package tts
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"log"
 	"os"
 	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"ghostrunner/backend/internal/events"
 
 	"golang.org/x/sync/singleflight"
 )
@@ -14,12 +20,17 @@ import (
 type Service interface {
 	// Synthesize は音声を合成して返します。キャッシュヒット時は即返却します。
 	Synthesize(ctx context.Context, params SynthesizeParams) (*SynthesizeResult, error)
+	// CacheStats はキャッシュの段ごとのヒット数と現在の件数・バイト数を返します。
+	CacheStats() CacheStats
+	// Speakers は VOICEVOX の話者一覧(/speakers の JSON 配列)を SpeakersCacheTTL の間キャッシュして返します。
+	Speakers(ctx context.Context) (json.RawMessage, error)
 }
 
 // clientInterface は service がテストで client を差し替えるための非公開インターフェースです。
 // 本物の *Client は自動的に満たすため production コードの変更は不要です。
 type clientInterface interface {
 	Synthesize(ctx context.Context, params SynthesizeParams) ([]byte, error)
+	Speakers(ctx context.Context) (json.RawMessage, error)
 }
 
 // serviceImpl は Service の実装です。
@@ -28,6 +39,31 @@ type serviceImpl struct {
 	cache            Cache
 	sfGroup          singleflight.Group
 	defaultSpeakerID int
+	publisher        events.Publisher // nil の場合はイベントを発行しない
+	diskCacheDir     string           // 空の場合はディスクキャッシュを使わない
+	clock            func() time.Time // nil の場合は time.Now(テスト用注入)
+
+	speakersMu        sync.Mutex
+	speakers          json.RawMessage
+	speakersFetchedAt time.Time
+}
+
+// Option は Service の任意の依存を設定します。
+type Option func(*serviceImpl)
+
+// WithPublisher は合成結果のイベント(tts.synthesized / tts.failed)の発行先を設定します。
+func WithPublisher(p events.Publisher) Option {
+	return func(s *serviceImpl) {
+		s.publisher = p
+	}
+}
+
+// WithDiskCache はメモリ LRU の下に dir へのディスクキャッシュ(DiskCacheMaxBytes / DiskCacheTTL)を重ねます。
+// 再起動後も同じ読み上げを VOICEVOX で合成し直さずに返せます。dir を作成できない場合はメモリのみで動作します。
+func WithDiskCache(dir string) Option {
+	return func(s *serviceImpl) {
+		s.diskCacheDir = dir
+	}
 }
 
 // NewService は環境変数を読んで Service を生成します。
@@ -36,7 +72,7 @@ type serviceImpl struct {
 // 環境変数:
 //   - VOICEVOX_HOST: VOICEVOX Engine のアドレス(デフォルト: http://localhost:50021)
 //   - VOICEVOX_SPEAKER_ID: 話者ID(デフォルト: 8 = 春日部つむぎ)
-func NewService() Service {
+func NewService(opts ...Option) Service {
 	host := os.Getenv("VOICEVOX_HOST")
 	if host == "" {
 		host = DefaultBaseURL
@@ -60,22 +96,50 @@ func NewService() Service {
 	log.Printf("[TTSService] Initialized: host=%s, speakerID=%d, cacheMaxBytes=%d, cacheTTL=%s",
 		host, speakerID, CacheMaxBytes, CacheTTL)
 
-	return &serviceImpl{
+	s := &serviceImpl{
 		client:           NewClient(cfg, 0),
-		cache:            NewLRUCache(CacheMaxBytes, CacheTTL),
 		defaultSpeakerID: speakerID,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.cache = newTieredCache(NewLRUCache(CacheMaxBytes, CacheTTL), s.openDiskCache())
+	return s
+}
+
+// openDiskCache は diskCacheDir のディスクキャッシュを開きます。未設定・失敗時は nil を返します。
+func (s *serviceImpl) openDiskCache() Cache {
+	if s.diskCacheDir == "" {
+		return nil
+	}
+	disk, err := NewDiskCache(s.diskCacheDir, DiskCacheMaxBytes, DiskCacheTTL)
+	if err != nil {
+		log.Printf("[TTSService] disk cache disabled: error=%v", err)
+		return nil
+	}
+	return disk
+}
+
+// CacheStats はキャッシュの段ごとのヒット数と現在の件数・バイト数を返します。
+// 段を持たないキャッシュ(テスト注入)はメモリ段の件数・バイト数のみ返します。
+func (s *serviceImpl) CacheStats() CacheStats {
+	if t, ok := s.cache.(*tieredCache); ok {
+		return t.Stats()
+	}
+	return CacheStats{Memory: CacheTierStats{Entries: s.cache.Len(), Bytes: s.cache.Bytes()}}
 }
 
 // Synthesize は params を正規化し、キャッシュ -> singleflight -> client の順で音声を取得します。
 func (s *serviceImpl) Synthesize(ctx context.Context, params SynthesizeParams) (*SynthesizeResult, error) {
 	normalized := s.normalize(params)
 
-	key := cacheKey(normalized.Text, normalized.SpeakerID, normalized.OutputFormat)
+	key := paramsCacheKey(normalized)
 
 	// キャッシュ参照
 	if data, ok := s.cache.Get(key); ok {
+		observeCache(s.cache, "hit")
 		log.Printf("[TTSService] cache hit: keyPrefix=%s, bytes=%d", keyPrefix(key), len(data))
+		s.publish(events.TopicTTSSynthesized, normalized, Event{Bytes: len(data), FromCache: true})
 		return &SynthesizeResult{
 			Audio:       data,
 			ContentType: "audio/wav",
@@ -83,6 +147,7 @@ func (s *serviceImpl) Synthesize(ctx context.Context, params SynthesizeParams) (
 		}, nil
 	}
 
+	observeCache(s.cache, "miss")
 	log.Printf("[TTSService] cache miss: keyPrefix=%s, calling VOICEVOX", keyPrefix(key))
 
 	// singleflight で重複呼出を統合
@@ -92,11 +157,13 @@ func (s *serviceImpl) Synthesize(ctx context.Context, params SynthesizeParams) (
 			return nil, callErr
 		}
 		s.cache.Set(key, audio)
+		observeCache(s.cache, "")
 		return audio, nil
 	})
 
 	if err != nil {
 		log.Printf("[TTSService] VOICEVOX failed: error=%v", err)
+		s.publish(events.TopicTTSFailed, normalized, Event{Error: err.Error()})
 		return nil, err
 	}
 
@@ -105,6 +172,7 @@ func (s *serviceImpl) Synthesize(ctx context.Context, params SynthesizeParams) (
 		return nil, errors.New("unexpected singleflight value type")
 	}
 
+	s.publish(events.TopicTTSSynthesized, normalized, Event{Bytes: len(audio)})
 	return &SynthesizeResult{
 		Audio:       audio,
 		ContentType: "audio/wav",
@@ -112,6 +180,60 @@ func (s *serviceImpl) Synthesize(ctx context.Context, params SynthesizeParams) (
 	}, nil
 }
 
+// Speakers は話者一覧を返します。SpeakersCacheTTL 以内に取得済みならキャッシュを返し、
+// 再取得に失敗した場合は古い一覧があればそれを返します(VOICEVOX 停止中も選択肢を表示できるように)。
+func (s *serviceImpl) Speakers(ctx context.Context) (json.RawMessage, error) {
+	now := s.now()
+	s.speakersMu.Lock()
+	cached, fetchedAt := s.speakers, s.speakersFetchedAt
+	s.speakersMu.Unlock()
+	if cached != nil && now.Sub(fetchedAt) < SpeakersCacheTTL {
+		return cached, nil
+	}
+
+	// 話者一覧の key は SHA256 の hex と衝突しない
+	v, err, _ := s.sfGroup.Do("speakers", func() (any, error) {
+		return s.client.Speakers(ctx)
+	})
+	if err != nil {
+		if cached != nil {
+			log.Printf("[TTSService] speakers refresh failed, serving stale list: fetchedAt=%s, error=%v",
+				fetchedAt.Format(time.RFC3339), err)
+			return cached, nil
+		}
+		log.Printf("[TTSService] speakers failed: error=%v", err)
+		return nil, err
+	}
+	speakers, ok := v.(json.RawMessage)
+	if !ok {
+		return nil, errors.New("unexpected singleflight value type")
+	}
+
+	s.speakersMu.Lock()
+	s.speakers, s.speakersFetchedAt = speakers, now
+	s.speakersMu.Unlock()
+	log.Printf("[TTSService] speakers refreshed: bytes=%d", len(speakers))
+	return speakers, nil
+}
+
+// now は現在時刻を返します(clock 未設定時は time.Now)。
+func (s *serviceImpl) now() time.Time {
+	if s.clock != nil {
+		return s.clock()
+	}
+	return time.Now()
+}
+
+// publish は合成結果のイベントを発行します(発行先未設定時は何もしない)。
+func (s *serviceImpl) publish(topic string, params SynthesizeParams, ev Event) {
+	if s.publisher == nil {
+		return
+	}
+	ev.SpeakerID = params.SpeakerID
+	ev.Chars = utf8.RuneCountInString(params.Text)
+	s.publisher.Publish(topic, ev)
+}
+
 // normalize は空フィールドをデフォルト値で埋めます。
 func (s *serviceImpl) normalize(params SynthesizeParams) SynthesizeParams {
 	out := params
@@ -2,6 +2,7 @@ package tts
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"sync"
 	"sync/atomic"
@@ -20,6 +21,7 @@ type mockClient struct {
 	err       error
 	callCount atomic.Int32
 	delay     time.Duration
+	speakers  json.RawMessage
 }
 
 func (m *mockClient) Synthesize(_ context.Context, params SynthesizeParams) ([]byte, error) {
@@ -33,6 +35,11 @@ func (m *mockClient) Synthesize(_ context.Context, params SynthesizeParams) ([]b
 	return m.audio, m.err
 }
 
+func (m *mockClient) Speakers(_ context.Context) (json.RawMessage, error) {
+	m.callCount.Add(1)
+	return m.speakers, m.err
+}
+
 // newTestService builds a serviceImpl with injected mock client and cache.
 func newTestService(client *mockClient, speakerID int) *serviceImpl {
 	if speakerID == 0 {
@@ -229,3 +236,91 @@ func TestService_Synthesize_OutputFormat_DefaultsToWav(t *testing.T) {
 	require.Len(t, mc.calls, 1)
 	assert.Equal(t, "wav", mc.calls[0].OutputFormat, "empty OutputFormat should default to wav")
 }
+
+// ---------------------------------------------------------------------------
+// Synthesize: events
+// ---------------------------------------------------------------------------
+
+// recordingPublisher records published events.
+type recordingPublisher struct {
+	mu     sync.Mutex
+	topics []string
+	events []Event
+}
+
+func (p *recordingPublisher) Publish(topic string, data any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.topics = append(p.topics, topic)
+	p.events = append(p.events, data.(Event))
+}
+
+func TestService_Synthesize_PublishesEvents(t *testing.T) {
+	mc := &mockClient{audio: []byte("wav-data")}
+	svc := newTestService(mc, 0)
+	pub := &recordingPublisher{}
+	WithPublisher(pub)(svc)
+
+	_, err := svc.Synthesize(context.Background(), SynthesizeParams{Text: "こんにちは"})
+	require.NoError(t, err)
+	_, err = svc.Synthesize(context.Background(), SynthesizeParams{Text: "こんにちは"})
+	require.NoError(t, err)
+
+	mc.err = assert.AnError
+	_, err = svc.Synthesize(context.Background(), SynthesizeParams{Text: "別の文"})
+	require.Error(t, err)
+
+	assert.Equal(t, []string{"tts.synthesized", "tts.synthesized", "tts.failed"}, pub.topics)
+	assert.Equal(t, Event{SpeakerID: DefaultSpeakerID, Chars: 5, Bytes: 8}, pub.events[0])
+	assert.True(t, pub.events[1].FromCache)
+	assert.NotEmpty(t, pub.events[2].Error)
+}
+
+// ---------------------------------------------------------------------------
+// Voice params and speakers
+// ---------------------------------------------------------------------------
+
+func TestService_Synthesize_VoiceParamsSeparateCacheEntries(t *testing.T) {
+	mc := &mockClient{audio: []byte("wav-data")}
+	svc := newTestService(mc, 0)
+
+	_, err := svc.Synthesize(context.Background(), SynthesizeParams{Text: "hello"})
+	require.NoError(t, err)
+	result, err := svc.Synthesize(context.Background(), SynthesizeParams{Text: "hello", Voice: VoiceParams{SpeedScale: f64(1.5)}})
+	require.NoError(t, err)
+	assert.False(t, result.FromCache, "different voice params must not share a cache entry")
+	result, err = svc.Synthesize(context.Background(), SynthesizeParams{Text: "hello", Voice: VoiceParams{SpeedScale: f64(1.5)}})
+	require.NoError(t, err)
+	assert.True(t, result.FromCache)
+
+	assert.Equal(t, int32(2), mc.callCount.Load())
+	require.Len(t, mc.calls, 2)
+	assert.Equal(t, 1.5, *mc.calls[1].Voice.SpeedScale, "voice params are passed to the client")
+}
+
+func TestService_Speakers_CachedAndStaleOnError(t *testing.T) {
+	now := time.Unix(1000, 0)
+	mc := &mockClient{speakers: json.RawMessage(`[{"name":"a"}]`)}
+	svc := newTestService(mc, 0)
+	svc.clock = func() time.Time { return now }
+
+	got, err := svc.Speakers(context.Background())
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"name":"a"}]`, string(got))
+	_, err = svc.Speakers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), mc.callCount.Load(), "second call within TTL is served from cache")
+
+	// TTL 経過後の再取得に失敗しても古い一覧を返す
+	now = now.Add(SpeakersCacheTTL + time.Second)
+	mc.err = ErrUpstreamTimeout
+	got, err = svc.Speakers(context.Background())
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"name":"a"}]`, string(got))
+	assert.Equal(t, int32(2), mc.callCount.Load())
+
+	// 一覧を一度も取得できていなければエラー
+	fresh := newTestService(&mockClient{err: ErrUpstreamTimeout}, 0)
+	_, err = fresh.Speakers(context.Background())
+	assert.ErrorIs(t, err, ErrUpstreamTimeout)
+}
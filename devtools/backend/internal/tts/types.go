@@ -27,8 +27,17 @@ const (
 	// CacheTTL はキャッシュエントリの有効期限
 	CacheTTL = 24 * time.Hour
 
+	// DiskCacheMaxBytes はディスクキャッシュ全体のバイト数上限(500MB)
+	DiskCacheMaxBytes = 500 * 1024 * 1024
+
+	// DiskCacheTTL はディスクキャッシュエントリの有効期限(書き込みから)
+	DiskCacheTTL = 30 * 24 * time.Hour
+
 	// HTTPTimeout は VOICEVOX Engine への HTTP リクエストタイムアウト(2-stage 合計)
 	HTTPTimeout = 60 * time.Second
+
+	// SpeakersCacheTTL は VOICEVOX の話者一覧(/speakers)を再取得するまでの間隔
+	SpeakersCacheTTL = 10 * time.Minute
 )
 
 // AudioQuery は VOICEVOX audio_query エンドポイントが返す JSON を透過的に保持する型です。
@@ -37,8 +46,21 @@ const (
 type AudioQuery = json.RawMessage
 
 // TTSRequest は POST /api/tts のリクエストボディです。
+// SpeakerID / Voice は省略時にユーザーのプリセット(無ければ VOICEVOX_SPEAKER_ID / audio_query の値)を使います。
 type TTSRequest struct {
-	Text string `json:"text"`
+	Text      string      `json:"text"`
+	SpeakerID int         `json:"speakerId,omitempty"`
+	Voice     VoiceParams `json:"voice"`
+}
+
+// VoicePreset はユーザー(ペアリング済み端末)ごとの既定の話者と声質です。
+// GET / POST /api/tts/preset のレスポンス・リクエストボディでもあります。
+type VoicePreset struct {
+	// SpeakerID は既定の話者ID です(0 はサーバーの既定)
+	SpeakerID int         `json:"speakerId,omitempty"`
+	Voice     VoiceParams `json:"voice"`
+	// UpdatedAt はサーバーが保存時に設定する RFC3339 の時刻です(未保存のプリセットは空)
+	UpdatedAt string `json:"updatedAt,omitempty"`
 }
 
 // TTSErrorResponse はエラー時のレスポンス JSON 形式です。
@@ -59,6 +81,8 @@ type SynthesizeParams struct {
 	Text         string
 	SpeakerID    int
 	OutputFormat string
+	// Voice は AudioQuery へ上書きする声質パラメータです(ゼロ値は上書きなし)
+	Voice VoiceParams
 }
 
 // SynthesizeResult はサービスの合成結果です。
@@ -68,6 +92,32 @@ type SynthesizeResult struct {
 	FromCache   bool
 }
 
+// CacheStats は GET /api/tts/cache のレスポンスです。
+// ヒット数・ミス数は起動以降の累計、件数・バイト数は現在の値です。
+type CacheStats struct {
+	Memory CacheTierStats `json:"memory"`
+	// Disk はディスクキャッシュ未設定時は省略します
+	Disk   *CacheTierStats `json:"disk,omitempty"`
+	Misses int64           `json:"misses"`
+}
+
+// CacheTierStats はキャッシュ 1 段の統計です。
+type CacheTierStats struct {
+	Hits    int64 `json:"hits"`
+	Entries int   `json:"entries"`
+	Bytes   int   `json:"bytes"`
+}
+
+// Event は音声合成のイベント(tts.synthesized / tts.failed トピックのデータ)です。
+// 読み上げ本文は含めず、文字数のみ載せます。
+type Event struct {
+	SpeakerID int    `json:"speakerId"`
+	Chars     int    `json:"chars"`
+	Bytes     int    `json:"bytes,omitempty"`
+	FromCache bool   `json:"fromCache"`
+	Error     string `json:"error,omitempty"`
+}
+
 // UpstreamStatusError は VOICEVOX が非 200 を返したことを示すエラーです。
 // errors.As で取り出して Status を見ることで、ハンドラ側で適切な HTTP
 // ステータスへマッピングできます。Body には API レスポンスボディの先頭
@@ -96,4 +146,10 @@ var (
 
 	// ErrInvalidContentType は VOICEVOX の synthesis から audio/wav 以外が返ったことを表します。
 	ErrInvalidContentType = errors.New("voicevox returned non audio/wav content type")
+
+	// ErrInvalidVoiceParams は話者ID または声質パラメータが範囲外であることを表します。
+	ErrInvalidVoiceParams = errors.New("invalid voice params")
+
+	// ErrInvalidSpeakers は VOICEVOX の /speakers が JSON 配列以外を返したことを表します。
+	ErrInvalidSpeakers = errors.New("voicevox returned invalid speakers list")
 )
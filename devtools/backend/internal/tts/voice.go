@@ -0,0 +1,109 @@
+package tts
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// voiceParamRange は AudioQuery の 1 パラメータの JSON キーと許容範囲です(VOICEVOX エディタの範囲)。
+type voiceParamRange struct {
+	key      string
+	min, max float64
+}
+
+// voiceParamRanges は VoiceParams の各フィールドに対応する AudioQuery のキーと範囲です。
+// 並び順はキャッシュキー(canonical)の順序でもあるため変更しないこと。
+var voiceParamRanges = [...]voiceParamRange{
+	{key: "speedScale", min: 0.5, max: 2.0},
+	{key: "pitchScale", min: -0.15, max: 0.15},
+	{key: "intonationScale", min: 0, max: 2.0},
+	{key: "volumeScale", min: 0, max: 2.0},
+	{key: "prePhonemeLength", min: 0, max: 1.5},
+	{key: "postPhonemeLength", min: 0, max: 1.5},
+}
+
+// VoiceParams は /synthesis の前に AudioQuery を上書きする声質パラメータです。
+// nil のフィールドは上書きせず、VOICEVOX が audio_query で返した値をそのまま使います。
+type VoiceParams struct {
+	SpeedScale        *float64 `json:"speedScale,omitempty"`
+	PitchScale        *float64 `json:"pitchScale,omitempty"`
+	IntonationScale   *float64 `json:"intonationScale,omitempty"`
+	VolumeScale       *float64 `json:"volumeScale,omitempty"`
+	PrePhonemeLength  *float64 `json:"prePhonemeLength,omitempty"`
+	PostPhonemeLength *float64 `json:"postPhonemeLength,omitempty"`
+}
+
+// fields は voiceParamRanges と同じ順のフィールドを返します。
+func (v *VoiceParams) fields() [len(voiceParamRanges)]**float64 {
+	return [...]**float64{
+		&v.SpeedScale, &v.PitchScale, &v.IntonationScale,
+		&v.VolumeScale, &v.PrePhonemeLength, &v.PostPhonemeLength,
+	}
+}
+
+// IsZero は上書きするパラメータが 1 つも無いかを返します。
+func (v VoiceParams) IsZero() bool {
+	for _, f := range v.fields() {
+		if *f != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge は v を基にして over の指定があるフィールドを上書きした値を返します(プリセット < リクエスト)。
+func (v VoiceParams) Merge(over VoiceParams) VoiceParams {
+	out := v
+	dst, src := out.fields(), over.fields()
+	for i := range dst {
+		if *src[i] != nil {
+			*dst[i] = *src[i]
+		}
+	}
+	return out
+}
+
+// Validate は各パラメータが許容範囲内かを検証します。範囲外は ErrInvalidVoiceParams を返します。
+func (v VoiceParams) Validate() error {
+	for i, f := range v.fields() {
+		r := voiceParamRanges[i]
+		if *f != nil && (**f < r.min || **f > r.max) {
+			return fmt.Errorf("%w: %s must be between %g and %g: %g", ErrInvalidVoiceParams, r.key, r.min, r.max, **f)
+		}
+	}
+	return nil
+}
+
+// canonical は指定のあるパラメータを固定順の "key=value" で連結した文字列です(キャッシュキー用)。
+func (v VoiceParams) canonical() string {
+	var parts []string
+	for i, f := range v.fields() {
+		if *f != nil {
+			parts = append(parts, voiceParamRanges[i].key+"="+strconv.FormatFloat(**f, 'g', -1, 64))
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// apply は AudioQuery の該当キーを上書きした JSON を返します。その他のキーは透過的に保持します。
+func (v VoiceParams) apply(query AudioQuery) (AudioQuery, error) {
+	if v.IsZero() {
+		return query, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(query, &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse audio_query response: %w", err)
+	}
+	for i, f := range v.fields() {
+		if *f != nil {
+			fields[voiceParamRanges[i].key] = json.RawMessage(strconv.FormatFloat(**f, 'g', -1, 64))
+		}
+	}
+	patched, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal patched audio_query: %w", err)
+	}
+	return patched, nil
+}
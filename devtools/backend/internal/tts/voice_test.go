@@ -0,0 +1,94 @@
+package tts
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func f64(v float64) *float64 { return &v }
+
+// ---------------------------------------------------------------------------
+// VoiceParams
+// ---------------------------------------------------------------------------
+
+func TestVoiceParams_Apply_PatchesOnlySetKeys(t *testing.T) {
+	query := AudioQuery(`{"accent_phrases":[{"text":"ア"}],"speedScale":1,"pitchScale":0,"volumeScale":1,"outputSamplingRate":24000}`)
+	voice := VoiceParams{SpeedScale: f64(1.25), PitchScale: f64(-0.05), PostPhonemeLength: f64(0.3)}
+
+	patched, err := voice.apply(query)
+	require.NoError(t, err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(patched, &got))
+	assert.Equal(t, 1.25, got["speedScale"])
+	assert.Equal(t, -0.05, got["pitchScale"])
+	assert.Equal(t, 0.3, got["postPhonemeLength"])
+	assert.Equal(t, 1.0, got["volumeScale"], "unset params keep the audio_query value")
+	assert.Equal(t, 24000.0, got["outputSamplingRate"], "unknown keys are passed through")
+	assert.Len(t, got["accent_phrases"], 1)
+}
+
+func TestVoiceParams_Apply_ZeroIsPassthrough(t *testing.T) {
+	query := AudioQuery(`{"speedScale":1}`)
+	patched, err := VoiceParams{}.apply(query)
+	require.NoError(t, err)
+	assert.Equal(t, query, patched)
+}
+
+func TestVoiceParams_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		voice   VoiceParams
+		wantErr bool
+	}{
+		{name: "empty", voice: VoiceParams{}},
+		{name: "in range", voice: VoiceParams{SpeedScale: f64(2.0), PitchScale: f64(-0.15), VolumeScale: f64(0)}},
+		{name: "speed too slow", voice: VoiceParams{SpeedScale: f64(0.4)}, wantErr: true},
+		{name: "pitch too high", voice: VoiceParams{PitchScale: f64(0.2)}, wantErr: true},
+		{name: "negative pre phoneme", voice: VoiceParams{PrePhonemeLength: f64(-0.1)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.voice.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidVoiceParams)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVoiceParams_Merge_RequestOverridesPreset(t *testing.T) {
+	preset := VoiceParams{SpeedScale: f64(1.2), VolumeScale: f64(0.8)}
+	got := preset.Merge(VoiceParams{SpeedScale: f64(1.5), PitchScale: f64(0.05)})
+
+	assert.Equal(t, 1.5, *got.SpeedScale)
+	assert.Equal(t, 0.05, *got.PitchScale)
+	assert.Equal(t, 0.8, *got.VolumeScale)
+	assert.Equal(t, 1.2, *preset.SpeedScale, "merge must not mutate the preset")
+}
+
+// ---------------------------------------------------------------------------
+// paramsCacheKey
+// ---------------------------------------------------------------------------
+
+func TestParamsCacheKey_IncludesVoice(t *testing.T) {
+	base := SynthesizeParams{Text: "hello", SpeakerID: 8, OutputFormat: "wav"}
+	assert.Equal(t, cacheKey("hello", 8, "wav"), paramsCacheKey(base),
+		"no voice override keeps the original key so existing cache entries stay valid")
+
+	faster := base
+	faster.Voice = VoiceParams{SpeedScale: f64(1.5)}
+	slower := base
+	slower.Voice = VoiceParams{SpeedScale: f64(0.8)}
+	assert.NotEqual(t, paramsCacheKey(base), paramsCacheKey(faster))
+	assert.NotEqual(t, paramsCacheKey(faster), paramsCacheKey(slower))
+
+	same := base
+	same.Voice = VoiceParams{SpeedScale: f64(1.5)}
+	assert.Equal(t, paramsCacheKey(faster), paramsCacheKey(same))
+}
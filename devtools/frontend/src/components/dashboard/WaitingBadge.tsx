@@ -23,6 +23,7 @@ function waitingMinutes(timestamp: string, nowMs: number): number {
 }
 
 // 質問待ちバッジ。`[質問待ち N分]` + 「何を待っているか」の1行を表示する（描画のみ）。
+// 待機理由（reasonLabel）があれば「質問待ち」の代わりに「計画の承認待ち」等を表示する。
 // summary があれば summary、無ければ preview を暫定表示。summary 未生成なら「(要約中…)」、
 // preview も summary も無ければ「(プレビューなし)」。
 export default function WaitingBadge({ idle, now }: WaitingBadgeProps) {
@@ -49,7 +50,7 @@ export default function WaitingBadge({ idle, now }: WaitingBadgeProps) {
   return (
     <div className="mt-2 flex flex-col gap-0.5">
       <span className="inline-flex w-fit items-center rounded bg-red-100 px-1.5 py-0.5 text-xs font-semibold text-red-700">
-        [質問待ち {minutes}分]
+        [{idle.reasonLabel || "質問待ち"} {minutes}分]
       </span>
       <div className="text-xs text-gray-700">
         {summarizing && <span className="mr-1 text-gray-400">(要約中…)</span>}
@@ -0,0 +1,33 @@
+// /api/auth（端末のペアリングとスコープ付きトークン）の型。
+// トークンは Authorization: Bearer、EventSource / WebSocket では ?access_token= で送る。
+
+/** read < execute < destroy の順に上位が下位を含む */
+export type AuthScope = "read" | "execute" | "destroy";
+
+/** POST /api/auth/pairing のレスポンス（ホストに表示するコード） */
+export interface Pairing {
+  code: string; // "K7QM-3XPA"
+  scope: AuthScope;
+  expiresAt: string; // RFC3339（発行から5分）
+}
+
+/** ペアリング済みの端末（ホスト本人は id="host"） */
+export interface Device {
+  id: string;
+  name: string;
+  scope: AuthScope;
+  createdAt: string;
+  lastUsedAt: string;
+}
+
+/** POST /api/auth/pair のレスポンス。token はこの応答でのみ返る */
+export interface PairResult {
+  token: string;
+  device: Device;
+}
+
+/** GET /api/auth/devices のレスポンス */
+export interface DeviceList {
+  devices: Device[];
+  total: number;
+}
@@ -0,0 +1,50 @@
+// GET /api/config（起動時に読み込んだサーバー設定）の型。
+
+/** 設定値の出どころ（既定値 < 設定ファイル < 環境変数 < 引数） */
+export type ConfigSource = "default" | "detected" | "file" | "env" | "flag";
+
+export interface ServerFeatures {
+  auth: boolean;
+  trustLoopback: boolean;
+  autoResume: boolean;
+  pprof: boolean;
+}
+
+/** 活動ダイジェストの定期配信（空なら送らない） */
+export interface DigestConfig {
+  daily?: string; // "HH:MM"
+  weekly?: string; // "mon 09:00"
+  writeDocs: boolean;
+}
+
+/** 要約プロバイダー（試す順にエラーなら次へ） */
+export type SummarizerProvider = "claude" | "openai" | "gemini" | "local" | "extractive";
+
+export interface SummarizerEndpoint {
+  baseURL?: string;
+  model?: string;
+}
+
+export interface SummarizerConfig {
+  providers: SummarizerProvider[];
+  openai: SummarizerEndpoint;
+  gemini: SummarizerEndpoint;
+  local: SummarizerEndpoint; // OpenAI 互換のローカルエンドポイント
+  progressInterval: string; // 動作中セッションの進捗を再要約する最小間隔（"5m" 等、"0" で無効）
+}
+
+/** GET /api/config のレスポンス */
+export interface ServerConfig {
+  root: string;
+  listen: string; // "host:port"
+  projectBaseDir: string;
+  stateDir: string;
+  patrolProjects: string;
+  allowedOrigins: string[];
+  publicURL?: string; // 通知の回答ボタンの宛先（未設定なら省略）
+  digest: DigestConfig;
+  summarizer: SummarizerConfig;
+  features: ServerFeatures;
+  file?: string; // 読み込んだ設定ファイル（読んでいない場合は省略）
+  sources: Record<string, ConfigSource>; // キーは "listen"、"features.auth" 等
+}
@@ -9,6 +9,8 @@ export interface KanbanCounts {
 
 export interface UnansweredItem {
   planPath: string;
+  /** 確認事項の ID（例: Q1）。見出しの無いステータス行では省略 */
+  questionId?: string;
   lineStart: number;
   lineEnd: number;
   questionText: string;
@@ -35,11 +37,23 @@ export interface OpsStats {
 // 質問待ち状態（バックエンド `idle` オブジェクトと同一フィールド）。
 // キーの存在自体が「質問待ち」を意味する（undefined/null = 質問待ちでない）。
 export interface IdleState {
+  sessionId?: string; // 代表セッションのID
   timestamp: string; // RFC3339。バッジの「N分」はフロントが now - timestamp で算出
   preview: string; // rawTail.lastAssistant 先頭80字 / summary 未生成時の暫定
   sessionCount: number; // 同プロジェクトの質問待ちセッション数（代表1件＝最長待機）
   summary: string; // 「何を待っているか」の日本語1行要約（生成前は ""）
   summarizedAt: string; // 要約生成時刻（RFC3339・未生成は ""）
+  reason?: "question" | "plan_approval" | "permission" | "end_of_turn"; // 待機理由（判定不能時は欠落）
+  reasonLabel?: string; // 待機理由の表示名（「計画の承認待ち」等）
+  autoAnswer?: AutoAnswerState; // 質問文に一致した自動回答ルール（一致時のみ）
+}
+
+// AutoAnswerState は質問待ちに一致した自動回答ルール（answer / timeout は回答の提案、escalate は通知済み）
+export interface AutoAnswerState {
+  rule: string;
+  action: "answer" | "timeout" | "escalate";
+  answer?: string; // 提案する回答（escalate では欠落）
+  dueAt?: string; // timeout の回答予定時刻（RFC3339）
 }
 
 export interface OpsEntry {
@@ -55,6 +69,46 @@ export interface OpsEntry {
   staleHours: number;
   sourceFile: string;
   rawExtra?: Record<string, unknown>;
+  errorThreshold: number; // consecutiveErrors を要対応とみなす回数（運用/ops.yaml。既定3）
+  missingFields?: string[]; // ops.yaml の fields に宣言されたのに状態ファイルに無いフィールド
+  alerts?: OpsAlert[]; // ops.yaml のアラート条件の評価結果（宣言順）
+}
+
+// 運用/ops.yaml のアラート条件1つの評価結果（バックエンド `OpsAlert` と同一フィールド）。
+export interface OpsAlert {
+  rule: string;
+  severity: "critical" | "warning"; // critical の発火中は attention=required
+  when: string; // 条件式（例: "today.count < today.target"）
+  by?: string; // "HH:MM"。この時刻より前は評価しない
+  firing: boolean;
+  skipped?: boolean; // by の時刻より前で評価していない
+  message?: string; // 発火時の説明
+  error?: string; // 評価できなかった理由
+}
+
+// git リポジトリ状態（バックエンド `git` オブジェクトと同一フィールド）。
+export interface GitInfo {
+  branch: string;
+  detached?: boolean;
+  upstream?: string;
+  ahead: number;
+  behind: number;
+  dirty: number; // 未コミット変更のファイル数
+  lastCommitAt?: string; // RFC3339
+  lastCommitAuthor?: string;
+  lastCommitSubject?: string;
+  taskStartedAt?: string; // 実行中タスクの開始時刻（RFC3339）
+  changedSinceTaskStart?: number; // タスク開始以降に更新された未コミットファイル数
+}
+
+// 停滞セッション状態（バックエンド `stuck` オブジェクトと同一フィールド）。
+// キーの存在自体が「停滞（要対応）」を意味する。
+export interface StuckState {
+  sessionId: string;
+  reason: "no_progress" | "repeated_failure";
+  since: string; // RFC3339。no_progress は会話ログの最終更新時刻、repeated_failure は検出時刻
+  detail: string; // 日本語の説明
+  toolName?: string; // repeated_failure で失敗し続けているツール名
 }
 
 export interface ProjectCardData {
@@ -68,6 +122,8 @@ export interface ProjectCardData {
   opsOptedIn: boolean;
   warnings: string[];
   idle?: IdleState | null; // キー欠落 or null = 質問待ちでない（FC3）
+  stuck?: StuckState | null; // キー欠落 or null = 停滞なし
+  git?: GitInfo | null; // キー欠落 or null = git リポジトリでない
 }
 
 export interface DashboardState {
@@ -75,6 +131,35 @@ export interface DashboardState {
   generatedAt: string;
 }
 
+// GET /api/dashboard/history のサンプル（false / 0 / 空は省略される）
+export interface HistorySample {
+  t: string;
+  project: string;
+  kanban: KanbanCounts;
+  idle?: boolean;
+  running?: boolean;
+  stuck?: boolean;
+  unanswered?: number;
+  ops?: { account: string; kind: string; status: string; stale?: boolean }[];
+}
+
+export interface HistorySummary {
+  project: string;
+  samples: number;
+  idleSeconds: number;
+  runningSeconds: number;
+  stuckSeconds: number;
+  unansweredSeconds: number;
+  completed: number;
+}
+
+export interface DashboardHistory {
+  from: string;
+  to: string;
+  samples: HistorySample[];
+  summaries: HistorySummary[];
+}
+
 // 型ガード関数
 
 // SSE 生 payload の DashboardState を軽く検証する（fe-W5）
@@ -143,3 +228,41 @@ export function isStatsShape(v: unknown): v is OpsStats {
     typeof (v as OpsStats).error === "number"
   );
 }
+
+/** 回答元（計画書の「**回答元**:」に記録される） */
+export type AnswerSource = "dashboard" | "voice" | "ntfy";
+
+/** POST /api/dashboard/answers の1件（questionId または lineStart で確認事項を指定） */
+export interface AnswerItem {
+  questionId?: string;
+  lineStart?: number;
+  answer: string;
+}
+
+/** POST /api/dashboard/answers のリクエスト（全件適用か無変更） */
+export interface BatchAnswerRequest {
+  projectPath: string;
+  planPath: string;
+  source?: AnswerSource;
+  answers: AnswerItem[];
+}
+
+/** GET /api/dashboard/answers/history の1件 */
+export interface AnsweredQuestion {
+  projectName: string;
+  projectPath: string;
+  planPath: string;
+  questionId?: string;
+  heading: string;
+  questionText: string;
+  answer: string;
+  answeredAt: string;
+  source?: AnswerSource;
+  line: number;
+}
+
+/** GET /api/dashboard/answers/history のレスポンス（回答日時の新しい順） */
+export interface AnswerHistory {
+  answers: AnsweredQuestion[];
+  total: number;
+}
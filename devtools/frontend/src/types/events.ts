@@ -0,0 +1,43 @@
+// GET /api/bus/events（SSE）・/api/bus/events/ws（WebSocket）のイベント型。
+// トピックは run.* / patrol.* / dashboard.state / task.* / tts.* の5系統。
+import type { StreamEvent } from "@/types";
+import type { Task, TaskLane } from "@/types/tasks";
+
+/** 背圧ポリシー（購読バッファが満杯のときの扱い） */
+export type EventPolicy = "drop_newest" | "drop_oldest" | "block" | "disconnect";
+
+/** バスを流れる1件のイベント（バックエンド `events.Event` と同一フィールド） */
+export interface BusEvent<T = unknown> {
+  id: number;
+  topic: string;
+  time: string; // RFC3339
+  data: T;
+}
+
+/** run.* のデータ。run.finished では event が欠落する */
+export interface RunEvent {
+  runId: string;
+  kind: "command" | "command_continue" | "plan" | "plan_continue";
+  project: string;
+  event?: StreamEvent;
+}
+
+/** task.* のデータ（task は本文 content を含まない） */
+export interface TaskEvent {
+  projectPath: string;
+  lane: TaskLane;
+  name?: string;
+  from?: TaskLane; // task.moved の移動元
+  task?: Task;
+  names?: string[]; // task.reordered の並び順
+}
+
+/** tts.* のデータ */
+export interface TTSEvent {
+  speakerId: number;
+  chars: number;
+  bytes?: number;
+  fromCache: boolean;
+  error?: string; // tts.failed の理由
+}
+
@@ -0,0 +1,71 @@
+// GET /api/reports/digest（日次・週次の活動ダイジェスト、format=json）の型。
+
+export type DigestPeriod = "day" | "week";
+
+export interface DigestTokens {
+  input: number;
+  output: number;
+  cacheCreation: number;
+  cacheRead: number;
+}
+
+export interface DigestTotals {
+  completed: number;
+  problems: number;
+  questionsAsked: number;
+  questionsAnswered: number;
+  runs: number;
+  staleOps: number;
+  cost: DigestCost;
+}
+
+/** 実行コスト（金額は記録されていないためセッション数とトークン数で表す） */
+export interface DigestCost {
+  sessions: number;
+  tokens: DigestTokens;
+}
+
+/** 期間内に 開発/実装/完了 へ移ったタスク */
+export interface DigestCompletedTask {
+  name: string;
+  commit: string;
+  at: string;
+}
+
+/** 異常終了・要確認で終わった gr-run の実行 */
+export interface DigestRunProblem {
+  taskFile: string;
+  outcome: string; // "abnormal" | "needs_check"
+  exitCode: number;
+  at: string;
+}
+
+export interface DigestAnswer {
+  planPath: string;
+  heading: string;
+  answer: string;
+  source?: string;
+  answeredAt: string;
+}
+
+export interface DigestProject {
+  name: string;
+  path: string;
+  completed: DigestCompletedTask[];
+  problems: DigestRunProblem[];
+  questionsAsked: number; // 時系列の未回答数の増加分
+  answered: DigestAnswer[];
+  runs: { runs: number; resumed: number; durationSec: number; outcomes: Record<string, number> };
+  cost: DigestCost;
+  staleOps: { account: string; kind: string; since: string }[];
+}
+
+export interface Digest {
+  period: DigestPeriod;
+  from: string;
+  to: string;
+  totals: DigestTotals;
+  projects: DigestProject[]; // 内容の無いプロジェクトは含まない
+  generatedAt: string;
+  warnings?: string[];
+}
@@ -0,0 +1,30 @@
+// /api/tasks（カンバンのタスク管理）のレスポンス型
+
+export type TaskLane = "reviewing" | "waiting" | "running" | "done" | "archived";
+
+export interface Task {
+  lane: TaskLane;
+  name: string;
+  path: string;
+  title: string;
+  etag: string;
+  modTime: string;
+  size: number;
+  content?: string; // 取得・作成・更新の応答のみ
+}
+
+export interface LaneTasks {
+  lane: TaskLane;
+  dir: string;
+  tasks: Task[];
+}
+
+export interface TaskBoard {
+  projectPath: string;
+  lanes: LaneTasks[];
+}
+
+export interface TaskTemplate {
+  name: string;
+  source: "builtin" | "project";
+}